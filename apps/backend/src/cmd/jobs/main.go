@@ -0,0 +1,128 @@
+package main
+
+import (
+	"context"
+	"log"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"cinetag-backend/src/internal/db"
+	"cinetag-backend/src/internal/logger"
+	"cinetag-backend/src/internal/mailer"
+	"cinetag-backend/src/internal/repository"
+	"cinetag-backend/src/internal/service"
+)
+
+// このコマンドは定期実行するバッチジョブのエントリーポイントです。
+// Cron などのスケジューラから実行することを想定しています。
+// send-email-digests 以外のジョブは service.MaintenanceService で実行し、管理者用 API（POST /api/v1/admin/jobs/:job）からも同じ処理を実行できます。
+//
+// 使い方: go run ./src/cmd/jobs [send-email-digests|purge-deactivated-users|purge-outbox-events|purge-notifications|purge-webhook-deliveries|purge-clerk-webhook-events|refresh-movie-cache]
+func main() {
+	jobs := strings.Join(append([]string{"send-email-digests"}, service.MaintenanceJobs...), ", ")
+	if len(os.Args) < 2 {
+		log.Fatalf("job name is required (use: %s)", jobs)
+	}
+	job := strings.ToLower(os.Args[1])
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Minute)
+	defer cancel()
+
+	switch {
+	case job == "send-email-digests":
+		runSendEmailDigests(ctx)
+	case slices.Contains(service.MaintenanceJobs, job):
+		runMaintenanceJob(ctx, job)
+	default:
+		log.Fatalf("unknown job: %s (use: %s)", job, jobs)
+	}
+
+	log.Printf("job '%s' completed successfully", job)
+}
+
+// runMaintenanceJob は保持期間を過ぎたデータの削除や映画キャッシュの更新などのメンテナンスジョブを実行します。
+// purge-notifications の保持ポリシーは NOTIFICATION_READ_RETENTION_DAYS / NOTIFICATION_UNREAD_MAX_PER_USER で指定します。
+// refresh-movie-cache は TMDB_API_KEY が必要です。
+func runMaintenanceJob(ctx context.Context, job string) {
+	appLogger := logger.NewLogger()
+	database := db.NewDB()
+
+	policy, err := service.NotificationRetentionPolicyFromEnv()
+	if err != nil {
+		log.Fatalf("%s: %v", job, err)
+	}
+
+	userRepo := repository.NewUserRepository(appLogger, database)
+	notifRepo := repository.NewNotificationRepository(database)
+	maintenanceService := service.NewMaintenanceService(
+		appLogger,
+		service.NewUserService(appLogger, database, userRepo, nil, nil, nil, nil, nil, nil, nil),
+		service.NewNotificationService(appLogger, notifRepo, nil, nil, nil, nil, nil, nil, nil, nil),
+		service.NewMovieService(appLogger, database),
+		repository.NewOutboxRepository(database),
+		repository.NewWebhookRepository(database),
+		repository.NewClerkWebhookEventRepository(database),
+		repository.NewUserDataExportRepository(database),
+		policy,
+	)
+
+	result, err := maintenanceService.RunJob(ctx, job, time.Now())
+	if err != nil {
+		var affected int64
+		if result != nil {
+			affected = result.Affected
+		}
+		log.Fatalf("%s failed after %d rows: %v", job, affected, err)
+	}
+	if len(result.Details) > 0 {
+		log.Printf("%s affected %d rows %v", job, result.Affected, result.Details)
+		return
+	}
+	log.Printf("%s affected %d rows", job, result.Affected)
+}
+
+// runSendEmailDigests は配信時期を迎えたユーザーにメールダイジェストを送信します。
+// 毎日・毎週の配信時期はユーザーごとの前回の配信日時から判定するため、1時間ごとなど配信間隔より短い周期で実行します。
+func runSendEmailDigests(ctx context.Context) {
+	appLogger := logger.NewLogger()
+	database := db.NewDB()
+
+	port, err := strconv.Atoi(os.Getenv("SMTP_PORT"))
+	if err != nil && os.Getenv("SMTP_PORT") != "" {
+		log.Fatalf("invalid SMTP_PORT: %v", err)
+	}
+	smtpMailer := mailer.NewSMTPMailer(mailer.SMTPConfig{
+		Host:     os.Getenv("SMTP_HOST"),
+		Port:     port,
+		Username: os.Getenv("SMTP_USERNAME"),
+		Password: os.Getenv("SMTP_PASSWORD"),
+		From:     os.Getenv("MAIL_FROM"),
+	})
+
+	digestRepo := repository.NewEmailDigestRepository(database)
+	notifRepo := repository.NewNotificationRepository(database)
+	digestService := service.NewEmailDigestService(appLogger, digestRepo, notifRepo, smtpMailer, service.EmailDigestConfigFromEnv())
+
+	const batchSize = 100
+	now := time.Now()
+	var processed, sent, failed int
+	for {
+		result, err := digestService.SendDueDigests(ctx, now, batchSize)
+		if result != nil {
+			processed += result.Processed
+			sent += result.Sent
+			failed += result.Failed
+		}
+		if err != nil {
+			log.Fatalf("send-email-digests failed after processing %d users: %v", processed, err)
+		}
+		// 処理したユーザーは配信済み・再送待ちになり次のバッチには含まれないため、バッチが埋まらなければ対象は残っていない
+		if result.Processed < batchSize {
+			break
+		}
+	}
+	log.Printf("processed %d users (sent: %d, failed: %d)", processed, sent, failed)
+}
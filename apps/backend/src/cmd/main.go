@@ -1,13 +1,28 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"log"
+	"log/slog"
+	"net/http"
 	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
 	docs "cinetag-backend/src/cmd/docs"
 	appRouter "cinetag-backend/src/router"
 )
 
+// Cloud Run は SIGTERM の送信から10秒後に強制終了するため、合計がそれに収まるようにする。
+const (
+	// 終了シグナル受信後、処理中のリクエストの完了を待つ時間の上限。
+	serverShutdownTimeout = 5 * time.Second
+	// HTTP サーバーの停止後、配信中の通知イベントの処理を待つ時間の上限。
+	outboxDrainTimeout = 4 * time.Second
+)
+
 func main() {
 	// ポート番号の取得
 	port := os.Getenv("PORT")
@@ -19,11 +34,54 @@ func main() {
 	docs.SwaggerInfo.Host = "localhost:" + port
 	docs.SwaggerInfo.BasePath = "/api/v1"
 
-	// ルーターの初期化
-	router := appRouter.NewRouter()
+	// 依存関係とルーターの初期化
+	deps := appRouter.NewDependencies()
+	router := appRouter.NewRouter(deps)
+
+	// 通知イベントの配信を開始
+	deps.OutboxDispatcher.Start()
+
+	// Clerk の JWKS のバックグラウンド更新を開始
+	if deps.JWTValidator != nil {
+		deps.JWTValidator.Start()
+		defer deps.JWTValidator.Stop()
+	}
 
 	// サーバーの起動
-	if err := router.Run(":" + port); err != nil {
-		log.Fatalf("failed to start server: %v", err)
+	srv := &http.Server{
+		Addr:    ":" + port,
+		Handler: router,
 	}
+	// 停止時は通知ストリーム（SSE）をすぐに終了させ、停止を待たずにクライアントを再接続させる
+	srv.RegisterOnShutdown(deps.NotificationHandler.CloseStreams)
+	go func() {
+		if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Fatalf("failed to start server: %v", err)
+		}
+	}()
+
+	// SIGINT / SIGTERM（デプロイ時の停止）を受けたら、新しい接続の受け付けをやめて処理中のリクエストを待つ
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	<-ctx.Done()
+	deps.Logger.Info("shutting down server")
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), serverShutdownTimeout)
+	defer cancel()
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		// 長時間の接続が残っている場合は強制的に閉じる（クライアントは再接続する）
+		deps.Logger.Warn("server shutdown timed out, closing remaining connections", slog.Any("error", err))
+		_ = srv.Close()
+	}
+
+	// 配信中の通知イベントを処理し終えるまで待つ（未配信のイベントはアウトボックスに残り、次回起動時に配信される）
+	drainCtx, cancelDrain := context.WithTimeout(context.Background(), outboxDrainTimeout)
+	defer cancelDrain()
+	if err := deps.OutboxDispatcher.Shutdown(drainCtx); err != nil {
+		deps.Logger.Warn("outbox dispatcher did not drain in time", slog.Any("error", err))
+	}
+
+	// 通知ストリームの LISTEN 接続を閉じる
+	deps.StopNotificationHub()
+	deps.Logger.Info("server stopped")
 }
@@ -0,0 +1,240 @@
+package handler
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"cinetag-backend/src/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// 管理者用 API の HTTP ハンドラー。
+// ルートは admin ロールのユーザーのみが呼び出せるよう middleware.NewAdminMiddleware で保護する
+// （メンテナンスジョブのみ、定期実行用に X-Admin-Token でも呼び出せる middleware.NewAdminJobMiddleware で保護する）。
+type AdminHandler struct {
+	logger              *slog.Logger
+	adminService        service.AdminService
+	notificationService service.NotificationService
+	maintenanceService  service.MaintenanceService
+}
+
+// AdminHandler を初期化して返す。
+func NewAdminHandler(logger *slog.Logger, adminService service.AdminService, notificationService service.NotificationService, maintenanceService service.MaintenanceService) *AdminHandler {
+	return &AdminHandler{
+		logger:              logger,
+		adminService:        adminService,
+		notificationService: notificationService,
+		maintenanceService:  maintenanceService,
+	}
+}
+
+// ロール変更のリクエストボディ。
+type updateUserRoleRequest struct {
+	Role string `json:"role" binding:"required"`
+}
+
+// ListUsers は退会・完全削除済みを含むユーザーを検索する。
+// GET /api/v1/admin/users?q=&status=&role=&page=&page_size=
+func (h *AdminHandler) ListUsers(c *gin.Context) {
+	page := parseIntDefault(c.Query("page"), 1)
+	pageSize := parseIntDefault(c.Query("page_size"), 20)
+
+	users, total, err := h.adminService.ListUsers(c.Request.Context(), service.ListAdminUsersInput{
+		Query:    c.Query("q"),
+		Status:   c.Query("status"),
+		Role:     c.Query("role"),
+		Page:     page,
+		PageSize: pageSize,
+	})
+	if err != nil {
+		h.writeAdminError(c, "handler.ListUsers", err, "failed to list users")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"items":       users,
+		"page":        page,
+		"page_size":   pageSize,
+		"total_count": total,
+	})
+}
+
+// GetUser は退会済みを含むユーザーを取得する。
+// GET /api/v1/admin/users/:userId
+func (h *AdminHandler) GetUser(c *gin.Context) {
+	u, err := h.adminService.GetUser(c.Request.Context(), c.Param("userId"))
+	if err != nil {
+		h.writeAdminError(c, "handler.GetUser", err, "failed to get user")
+		return
+	}
+
+	c.JSON(http.StatusOK, u)
+}
+
+// UpdateUserRole はユーザーのロールを変更する。
+// PATCH /api/v1/admin/users/:userId/role
+func (h *AdminHandler) UpdateUserRole(c *gin.Context) {
+	var req updateUserRoleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body"})
+		return
+	}
+
+	u, err := h.adminService.UpdateUserRole(c.Request.Context(), adminActorID(c), c.Param("userId"), req.Role)
+	if err != nil {
+		h.writeAdminError(c, "handler.UpdateUserRole", err, "failed to update user role")
+		return
+	}
+
+	c.JSON(http.StatusOK, u)
+}
+
+// SuspendUser はユーザーを利用停止にする。
+// POST /api/v1/admin/users/:userId/suspend
+func (h *AdminHandler) SuspendUser(c *gin.Context) {
+	u, err := h.adminService.SuspendUser(c.Request.Context(), adminActorID(c), c.Param("userId"))
+	if err != nil {
+		h.writeAdminError(c, "handler.SuspendUser", err, "failed to suspend user")
+		return
+	}
+
+	c.JSON(http.StatusOK, u)
+}
+
+// RestoreUser は利用停止・退会状態のユーザーを通常状態に戻す。
+// POST /api/v1/admin/users/:userId/restore
+func (h *AdminHandler) RestoreUser(c *gin.Context) {
+	u, err := h.adminService.RestoreUser(c.Request.Context(), adminActorID(c), c.Param("userId"))
+	if err != nil {
+		h.writeAdminError(c, "handler.RestoreUser", err, "failed to restore user")
+		return
+	}
+
+	c.JSON(http.StatusOK, u)
+}
+
+// ListUserNotifications はユーザーが受け取った通知の一覧を取得する（問い合わせの調査用）。
+// GET /api/v1/admin/users/:userId/notifications?page=&page_size=&unread_only=&archived=
+func (h *AdminHandler) ListUserNotifications(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	u, err := h.adminService.GetUser(ctx, c.Param("userId"))
+	if err != nil {
+		h.writeAdminError(c, "handler.ListUserNotifications", err, "failed to list notifications")
+		return
+	}
+
+	page := parseIntDefault(c.Query("page"), 1)
+	pageSize := parseIntDefault(c.Query("page_size"), 20)
+	if pageSize > 50 {
+		pageSize = 50
+	}
+	unreadOnly := c.Query("unread_only") == "true"
+	archived := c.Query("archived") == "true"
+
+	items, total, err := h.notificationService.ListNotifications(ctx, u.ID, page, pageSize, unreadOnly, archived)
+	if err != nil {
+		h.writeAdminError(c, "handler.ListUserNotifications", err, "failed to list notifications")
+		return
+	}
+	unread, err := h.notificationService.GetUnreadCount(ctx, u.ID)
+	if err != nil {
+		h.writeAdminError(c, "handler.ListUserNotifications", err, "failed to list notifications")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"notifications": items,
+		"total":         total,
+		"unread_count":  unread,
+		"page":          page,
+		"page_size":     pageSize,
+	})
+}
+
+// HideTag は公開タグを非表示にする。
+// POST /api/v1/admin/tags/:tagId/hide
+func (h *AdminHandler) HideTag(c *gin.Context) {
+	tag, err := h.adminService.HideTag(c.Request.Context(), adminActorID(c), c.Param("tagId"))
+	if err != nil {
+		h.writeAdminError(c, "handler.HideTag", err, "failed to hide tag")
+		return
+	}
+
+	c.JSON(http.StatusOK, tag)
+}
+
+// UnhideTag は非表示にしたタグを公開に戻す。
+// POST /api/v1/admin/tags/:tagId/unhide
+func (h *AdminHandler) UnhideTag(c *gin.Context) {
+	tag, err := h.adminService.UnhideTag(c.Request.Context(), adminActorID(c), c.Param("tagId"))
+	if err != nil {
+		h.writeAdminError(c, "handler.UnhideTag", err, "failed to unhide tag")
+		return
+	}
+
+	c.JSON(http.StatusOK, tag)
+}
+
+// RunJob はメンテナンスジョブ（データの削除・映画キャッシュの更新）を同期的に実行する。
+// POST /api/v1/admin/jobs/:job
+func (h *AdminHandler) RunJob(c *gin.Context) {
+	job := c.Param("job")
+
+	result, err := h.maintenanceService.RunJob(c.Request.Context(), job, time.Now())
+	if err != nil {
+		if errors.Is(err, service.ErrUnknownMaintenanceJob) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "unknown job", "jobs": service.MaintenanceJobs})
+			return
+		}
+		h.logger.Error("handler.RunJob failed",
+			slog.String("actor_user_id", adminActorID(c)),
+			slog.String("job", job),
+			slog.Any("error", err),
+		)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "job failed", "result": result})
+		return
+	}
+
+	h.logger.Info("handler.RunJob completed",
+		slog.String("actor_user_id", adminActorID(c)),
+		slog.String("job", job),
+	)
+	c.JSON(http.StatusOK, result)
+}
+
+// 操作した管理者のユーザーIDを返す。X-Admin-Token でメンテナンスジョブを実行した場合は空文字を返す。
+func adminActorID(c *gin.Context) string {
+	if user := getUserFromContext(c); user != nil {
+		return user.ID
+	}
+	return ""
+}
+
+// 管理者用サービスのエラーをレスポンスに変換する。想定外のエラーはログに出して 500 を返す。
+func (h *AdminHandler) writeAdminError(c *gin.Context, op string, err error, failedMessage string) {
+	switch {
+	case errors.Is(err, service.ErrInvalidUserRole),
+		errors.Is(err, service.ErrInvalidAdminUserFilter):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrCannotModerateSelf):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrUserNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "user not found"})
+	case errors.Is(err, service.ErrTagNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "tag not found"})
+	case errors.Is(err, service.ErrUserNotSuspended),
+		errors.Is(err, service.ErrTagNotPublic),
+		errors.Is(err, service.ErrTagNotHidden):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		h.logger.Error(op+" failed",
+			slog.String("actor_user_id", adminActorID(c)),
+			slog.Any("error", err),
+		)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": failedMessage})
+	}
+}
@@ -0,0 +1,353 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"cinetag-backend/src/internal/model"
+	"cinetag-backend/src/internal/service"
+	"cinetag-backend/src/internal/testutil"
+
+	"github.com/gin-gonic/gin"
+)
+
+type fakeAdminService struct {
+	ListUsersFn      func(ctx context.Context, in service.ListAdminUsersInput) ([]*model.User, int64, error)
+	GetUserFn        func(ctx context.Context, userID string) (*model.User, error)
+	UpdateUserRoleFn func(ctx context.Context, actorUserID, userID, role string) (*model.User, error)
+	SuspendUserFn    func(ctx context.Context, actorUserID, userID string) (*model.User, error)
+	RestoreUserFn    func(ctx context.Context, actorUserID, userID string) (*model.User, error)
+	HideTagFn        func(ctx context.Context, actorUserID, tagID string) (*model.Tag, error)
+	UnhideTagFn      func(ctx context.Context, actorUserID, tagID string) (*model.Tag, error)
+}
+
+func (f *fakeAdminService) ListUsers(ctx context.Context, in service.ListAdminUsersInput) ([]*model.User, int64, error) {
+	if f.ListUsersFn == nil {
+		return []*model.User{}, 0, nil
+	}
+	return f.ListUsersFn(ctx, in)
+}
+
+func (f *fakeAdminService) GetUser(ctx context.Context, userID string) (*model.User, error) {
+	if f.GetUserFn == nil {
+		return &model.User{ID: userID}, nil
+	}
+	return f.GetUserFn(ctx, userID)
+}
+
+func (f *fakeAdminService) UpdateUserRole(ctx context.Context, actorUserID, userID, role string) (*model.User, error) {
+	if f.UpdateUserRoleFn == nil {
+		return &model.User{ID: userID, Role: role}, nil
+	}
+	return f.UpdateUserRoleFn(ctx, actorUserID, userID, role)
+}
+
+func (f *fakeAdminService) SuspendUser(ctx context.Context, actorUserID, userID string) (*model.User, error) {
+	if f.SuspendUserFn == nil {
+		return &model.User{ID: userID}, nil
+	}
+	return f.SuspendUserFn(ctx, actorUserID, userID)
+}
+
+func (f *fakeAdminService) RestoreUser(ctx context.Context, actorUserID, userID string) (*model.User, error) {
+	if f.RestoreUserFn == nil {
+		return &model.User{ID: userID}, nil
+	}
+	return f.RestoreUserFn(ctx, actorUserID, userID)
+}
+
+func (f *fakeAdminService) HideTag(ctx context.Context, actorUserID, tagID string) (*model.Tag, error) {
+	if f.HideTagFn == nil {
+		return &model.Tag{ID: tagID}, nil
+	}
+	return f.HideTagFn(ctx, actorUserID, tagID)
+}
+
+func (f *fakeAdminService) UnhideTag(ctx context.Context, actorUserID, tagID string) (*model.Tag, error) {
+	if f.UnhideTagFn == nil {
+		return &model.Tag{ID: tagID, IsPublic: true}, nil
+	}
+	return f.UnhideTagFn(ctx, actorUserID, tagID)
+}
+
+type fakeMaintenanceService struct {
+	RunJobFn func(ctx context.Context, job string, now time.Time) (*service.MaintenanceJobResult, error)
+}
+
+func (f *fakeMaintenanceService) RunJob(ctx context.Context, job string, now time.Time) (*service.MaintenanceJobResult, error) {
+	if f.RunJobFn == nil {
+		return &service.MaintenanceJobResult{Job: job}, nil
+	}
+	return f.RunJobFn(ctx, job, now)
+}
+
+func newAdminHandlerRouter(t *testing.T, adminSvc service.AdminService, notifSvc service.NotificationService, maintenanceSvc service.MaintenanceService, admin *model.User) *gin.Engine {
+	t.Helper()
+	r := testutil.NewTestRouter()
+	h := NewAdminHandler(testutil.NewTestLogger(), adminSvc, notifSvc, maintenanceSvc)
+
+	group := r.Group("/api/v1/admin")
+	if admin != nil {
+		group.Use(func(c *gin.Context) {
+			c.Set("user", admin)
+			c.Next()
+		})
+	}
+	group.GET("/users", h.ListUsers)
+	group.GET("/users/:userId", h.GetUser)
+	group.PATCH("/users/:userId/role", h.UpdateUserRole)
+	group.POST("/users/:userId/suspend", h.SuspendUser)
+	group.POST("/users/:userId/restore", h.RestoreUser)
+	group.GET("/users/:userId/notifications", h.ListUserNotifications)
+	group.POST("/tags/:tagId/hide", h.HideTag)
+	group.POST("/tags/:tagId/unhide", h.UnhideTag)
+	group.POST("/jobs/:job", h.RunJob)
+
+	return r
+}
+
+func TestAdminHandler_ListUsers(t *testing.T) {
+	t.Parallel()
+
+	t.Run("検索条件を渡して一覧を返す", func(t *testing.T) {
+		t.Parallel()
+
+		var got service.ListAdminUsersInput
+		svc := &fakeAdminService{
+			ListUsersFn: func(ctx context.Context, in service.ListAdminUsersInput) ([]*model.User, int64, error) {
+				got = in
+				return []*model.User{{ID: "u1", Role: model.UserRoleAdmin}}, 1, nil
+			},
+		}
+		r := newAdminHandlerRouter(t, svc, &fakeNotificationService{}, &fakeMaintenanceService{}, nil)
+		rr := testutil.PerformRequest(r, http.MethodGet, "/api/v1/admin/users?q=alice&status=suspended&role=admin&page=2&page_size=10", nil, nil)
+		if rr.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d body=%s", rr.Code, rr.Body.String())
+		}
+		want := service.ListAdminUsersInput{Query: "alice", Status: "suspended", Role: "admin", Page: 2, PageSize: 10}
+		if got != want {
+			t.Fatalf("expected input %+v, got %+v", want, got)
+		}
+
+		var body struct {
+			Items      []map[string]any `json:"items"`
+			TotalCount int64            `json:"total_count"`
+		}
+		testutil.MustUnmarshalJSON(t, rr.Body.Bytes(), &body)
+		if len(body.Items) != 1 || body.Items[0]["role"] != "admin" || body.TotalCount != 1 {
+			t.Fatalf("unexpected body: %s", rr.Body.String())
+		}
+	})
+
+	t.Run("絞り込み条件が不正な場合は400", func(t *testing.T) {
+		t.Parallel()
+
+		svc := &fakeAdminService{
+			ListUsersFn: func(ctx context.Context, in service.ListAdminUsersInput) ([]*model.User, int64, error) {
+				return nil, 0, service.ErrInvalidAdminUserFilter
+			},
+		}
+		r := newAdminHandlerRouter(t, svc, &fakeNotificationService{}, &fakeMaintenanceService{}, nil)
+		rr := testutil.PerformRequest(r, http.MethodGet, "/api/v1/admin/users?status=banned", nil, nil)
+		if rr.Code != http.StatusBadRequest {
+			t.Fatalf("expected 400, got %d", rr.Code)
+		}
+	})
+}
+
+func TestAdminHandler_ModerateUser(t *testing.T) {
+	t.Parallel()
+
+	admin := &model.User{ID: "admin1", Role: model.UserRoleAdmin}
+	jsonHeader := map[string]string{"Content-Type": "application/json"}
+
+	t.Run("操作した管理者のIDをサービスに渡す", func(t *testing.T) {
+		t.Parallel()
+
+		var gotActor string
+		svc := &fakeAdminService{
+			SuspendUserFn: func(ctx context.Context, actorUserID, userID string) (*model.User, error) {
+				gotActor = actorUserID
+				return &model.User{ID: userID}, nil
+			},
+		}
+		r := newAdminHandlerRouter(t, svc, &fakeNotificationService{}, &fakeMaintenanceService{}, admin)
+		rr := testutil.PerformRequest(r, http.MethodPost, "/api/v1/admin/users/u1/suspend", nil, nil)
+		if rr.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d", rr.Code)
+		}
+		if gotActor != "admin1" {
+			t.Fatalf("expected actor admin1, got %q", gotActor)
+		}
+	})
+
+	t.Run("ロールの変更: ボディが不正な場合は400", func(t *testing.T) {
+		t.Parallel()
+
+		r := newAdminHandlerRouter(t, &fakeAdminService{}, &fakeNotificationService{}, &fakeMaintenanceService{}, admin)
+		rr := testutil.PerformRequest(r, http.MethodPatch, "/api/v1/admin/users/u1/role", []byte(`{}`), jsonHeader)
+		if rr.Code != http.StatusBadRequest {
+			t.Fatalf("expected 400, got %d", rr.Code)
+		}
+	})
+
+	t.Run("サービスのエラーをステータスに変換する", func(t *testing.T) {
+		t.Parallel()
+
+		cases := []struct {
+			err  error
+			want int
+		}{
+			{err: service.ErrInvalidUserRole, want: http.StatusBadRequest},
+			{err: service.ErrCannotModerateSelf, want: http.StatusForbidden},
+			{err: service.ErrUserNotFound, want: http.StatusNotFound},
+			{err: service.ErrUserNotSuspended, want: http.StatusConflict},
+			{err: errors.New("db error"), want: http.StatusInternalServerError},
+		}
+		for _, tc := range cases {
+			svc := &fakeAdminService{
+				UpdateUserRoleFn: func(ctx context.Context, actorUserID, userID, role string) (*model.User, error) {
+					return nil, tc.err
+				},
+			}
+			r := newAdminHandlerRouter(t, svc, &fakeNotificationService{}, &fakeMaintenanceService{}, admin)
+			rr := testutil.PerformRequest(r, http.MethodPatch, "/api/v1/admin/users/u1/role", []byte(`{"role":"admin"}`), jsonHeader)
+			if rr.Code != tc.want {
+				t.Fatalf("%v: expected %d, got %d", tc.err, tc.want, rr.Code)
+			}
+		}
+	})
+}
+
+func TestAdminHandler_ListUserNotifications(t *testing.T) {
+	t.Parallel()
+
+	t.Run("存在しないユーザーは404", func(t *testing.T) {
+		t.Parallel()
+
+		svc := &fakeAdminService{
+			GetUserFn: func(ctx context.Context, userID string) (*model.User, error) {
+				return nil, service.ErrUserNotFound
+			},
+		}
+		r := newAdminHandlerRouter(t, svc, &fakeNotificationService{}, &fakeMaintenanceService{}, nil)
+		rr := testutil.PerformRequest(r, http.MethodGet, "/api/v1/admin/users/u1/notifications", nil, nil)
+		if rr.Code != http.StatusNotFound {
+			t.Fatalf("expected 404, got %d", rr.Code)
+		}
+	})
+
+	t.Run("対象ユーザーの通知と未読数を返す", func(t *testing.T) {
+		t.Parallel()
+
+		var gotUserID string
+		var gotUnreadOnly bool
+		notifSvc := &fakeNotificationService{
+			ListNotificationsFn: func(ctx context.Context, userID string, page, pageSize int, unreadOnly, archived bool) ([]*service.NotificationItem, int64, error) {
+				gotUserID = userID
+				gotUnreadOnly = unreadOnly
+				return []*service.NotificationItem{{ID: "n1"}}, 1, nil
+			},
+			GetUnreadCountFn: func(ctx context.Context, userID string) (int64, error) {
+				return 3, nil
+			},
+		}
+		r := newAdminHandlerRouter(t, &fakeAdminService{}, notifSvc, &fakeMaintenanceService{}, nil)
+		rr := testutil.PerformRequest(r, http.MethodGet, "/api/v1/admin/users/u1/notifications?unread_only=true", nil, nil)
+		if rr.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d body=%s", rr.Code, rr.Body.String())
+		}
+		if gotUserID != "u1" || !gotUnreadOnly {
+			t.Fatalf("unexpected args: userID=%q unreadOnly=%v", gotUserID, gotUnreadOnly)
+		}
+
+		var body struct {
+			Total       int64 `json:"total"`
+			UnreadCount int64 `json:"unread_count"`
+		}
+		testutil.MustUnmarshalJSON(t, rr.Body.Bytes(), &body)
+		if body.Total != 1 || body.UnreadCount != 3 {
+			t.Fatalf("unexpected body: %s", rr.Body.String())
+		}
+	})
+}
+
+func TestAdminHandler_HideTag(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		err  error
+		want int
+	}{
+		{err: nil, want: http.StatusOK},
+		{err: service.ErrTagNotFound, want: http.StatusNotFound},
+		{err: service.ErrTagNotPublic, want: http.StatusConflict},
+	}
+	for _, tc := range cases {
+		svc := &fakeAdminService{
+			HideTagFn: func(ctx context.Context, actorUserID, tagID string) (*model.Tag, error) {
+				if tc.err != nil {
+					return nil, tc.err
+				}
+				return &model.Tag{ID: tagID}, nil
+			},
+		}
+		r := newAdminHandlerRouter(t, svc, &fakeNotificationService{}, &fakeMaintenanceService{}, nil)
+		rr := testutil.PerformRequest(r, http.MethodPost, "/api/v1/admin/tags/t1/hide", nil, nil)
+		if rr.Code != tc.want {
+			t.Fatalf("%v: expected %d, got %d", tc.err, tc.want, rr.Code)
+		}
+	}
+}
+
+func TestAdminHandler_RunJob(t *testing.T) {
+	t.Parallel()
+
+	t.Run("ジョブの結果を返す", func(t *testing.T) {
+		t.Parallel()
+
+		svc := &fakeMaintenanceService{
+			RunJobFn: func(ctx context.Context, job string, now time.Time) (*service.MaintenanceJobResult, error) {
+				return &service.MaintenanceJobResult{Job: job, Affected: 12}, nil
+			},
+		}
+		r := newAdminHandlerRouter(t, &fakeAdminService{}, &fakeNotificationService{}, svc, nil)
+		rr := testutil.PerformRequest(r, http.MethodPost, "/api/v1/admin/jobs/purge-outbox-events", nil, nil)
+		if rr.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d", rr.Code)
+		}
+
+		var body service.MaintenanceJobResult
+		testutil.MustUnmarshalJSON(t, rr.Body.Bytes(), &body)
+		if body.Job != "purge-outbox-events" || body.Affected != 12 {
+			t.Fatalf("unexpected body: %s", rr.Body.String())
+		}
+	})
+
+	t.Run("存在しないジョブは404、失敗は500", func(t *testing.T) {
+		t.Parallel()
+
+		cases := []struct {
+			err  error
+			want int
+		}{
+			{err: service.ErrUnknownMaintenanceJob, want: http.StatusNotFound},
+			{err: errors.New("db error"), want: http.StatusInternalServerError},
+		}
+		for _, tc := range cases {
+			svc := &fakeMaintenanceService{
+				RunJobFn: func(ctx context.Context, job string, now time.Time) (*service.MaintenanceJobResult, error) {
+					return nil, tc.err
+				},
+			}
+			r := newAdminHandlerRouter(t, &fakeAdminService{}, &fakeNotificationService{}, svc, nil)
+			rr := testutil.PerformRequest(r, http.MethodPost, "/api/v1/admin/jobs/unknown", nil, nil)
+			if rr.Code != tc.want {
+				t.Fatalf("%v: expected %d, got %d", tc.err, tc.want, rr.Code)
+			}
+		}
+	})
+}
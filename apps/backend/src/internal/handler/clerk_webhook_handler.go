@@ -2,11 +2,14 @@ package handler
 
 import (
 	"encoding/json"
+	"errors"
 	"log/slog"
 	"net/http"
 
 	"cinetag-backend/src/internal/middleware"
+	"cinetag-backend/src/internal/model"
 	"cinetag-backend/src/internal/service"
+	"cinetag-backend/src/internal/webhook"
 
 	"github.com/gin-gonic/gin"
 )
@@ -19,60 +22,66 @@ type clerkWebhookEvent struct {
 	Data json.RawMessage `json:"data"`
 }
 
-// Clerk の user.created Webhook の data 部分を表します。
-type clerkUserCreatedData struct {
-	ID             string `json:"id"`
-	Username       string `json:"username"`
-	FirstName      string `json:"first_name"`
-	LastName       string `json:"last_name"`
-	ImageURL       string `json:"image_url"`
-	EmailAddresses []struct {
-		EmailAddress string `json:"email_address"`
-	} `json:"email_addresses"`
-}
-
-// Clerk の user.updated Webhook の data 部分を表します。
-// user.created と同じ形式です。
-type clerkUserUpdatedData struct {
-	ID             string `json:"id"`
-	Username       string `json:"username"`
-	FirstName      string `json:"first_name"`
-	LastName       string `json:"last_name"`
-	ImageURL       string `json:"image_url"`
-	EmailAddresses []struct {
-		EmailAddress string `json:"email_address"`
-	} `json:"email_addresses"`
-}
-
 // Clerk の user.deleted Webhook の data 部分を表します。
 type clerkUserDeletedData struct {
 	ID string `json:"id"`
 }
 
+// Clerk の session.* Webhook の data 部分のうち、利用する項目を表します。
+type clerkSessionData struct {
+	ID     string `json:"id"`
+	UserID string `json:"user_id"`
+}
+
 // Clerk Webhook を処理するハンドラーです。
 type ClerkWebhookHandler struct {
-	logger      *slog.Logger
-	userService service.UserService
+	logger              *slog.Logger
+	userService         service.UserService
+	clerkWebhookService service.ClerkWebhookService
 }
 
 // ClerkWebhookHandler を初期化して返します。
-func NewClerkWebhookHandler(logger *slog.Logger, userService service.UserService) *ClerkWebhookHandler {
+func NewClerkWebhookHandler(logger *slog.Logger, userService service.UserService, clerkWebhookService service.ClerkWebhookService) *ClerkWebhookHandler {
 	return &ClerkWebhookHandler{
-		logger:      logger,
-		userService: userService,
+		logger:              logger,
+		userService:         userService,
+		clerkWebhookService: clerkWebhookService,
 	}
 }
 
 // POST /api/v1/clerk/webhook を処理します。
-// 現時点では svix 署名検証ロジックは未実装です。
-// TODO: svix の署名検証を追加し、Clerk からの正当なリクエストのみを受け付ける。
+// svix の署名を検証して Clerk からの正当なリクエストのみを受け付け、
+// 同じメッセージ（svix-id）の再送は処理せずに成功として返します。
+// 他のリクエストが処理中のメッセージは 409 を返し、Svix の再送で処理結果を確認します。
+// https://clerk.com/docs/guides/development/webhooks/overview#verify-the-webhook-signature
 func (h *ClerkWebhookHandler) HandleWebhook(c *gin.Context) {
 
 	requestID := middleware.GetRequestID(c)
 
+	// 署名は受信した本文そのものに対して検証する
+	body, err := c.GetRawData()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "invalid webhook payload",
+		})
+		return
+	}
+
+	svixID := c.GetHeader(webhook.HeaderSvixID)
+	if err := h.clerkWebhookService.VerifySignature(svixID, c.GetHeader(webhook.HeaderSvixTimestamp), c.GetHeader(webhook.HeaderSvixSignature), body); err != nil {
+		h.logger.Warn("handler.HandleWebhook invalid signature",
+			slog.String("request_id", requestID),
+			slog.String("svix_id", svixID),
+		)
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "invalid webhook signature",
+		})
+		return
+	}
+
 	// ペイロードをバインド
 	var event clerkWebhookEvent
-	if err := c.ShouldBindJSON(&event); err != nil {
+	if err := json.Unmarshal(body, &event); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{
 			"error": "invalid webhook payload",
 		})
@@ -82,72 +91,73 @@ func (h *ClerkWebhookHandler) HandleWebhook(c *gin.Context) {
 	// 開始ログ（INFO）
 	h.logger.Info("handler.HandleWebhook started",
 		slog.String("request_id", requestID),
+		slog.String("svix_id", svixID),
 		slog.String("event_type", event.Type),
 	)
 
-	switch event.Type {
-	case "user.created":
-		var data clerkUserCreatedData
-		if err := json.Unmarshal(event.Data, &data); err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{
-				"error": "invalid webhook data",
+	claim, err := h.clerkWebhookService.BeginEvent(c.Request.Context(), svixID, event.Type)
+	if err != nil {
+		if errors.Is(err, service.ErrClerkWebhookEventInProgress) {
+			// 処理中のリクエストが失敗する可能性があるため、成功とはせずに再送させる
+			h.logger.Info("handler.HandleWebhook event in progress",
+				slog.String("request_id", requestID),
+				slog.String("svix_id", svixID),
+			)
+			c.JSON(http.StatusConflict, gin.H{
+				"error": "webhook is being processed",
 			})
 			return
 		}
-
-		// デバッグログ（DEBUG）
-		h.logger.Debug("handler.HandleWebhook user.created",
+		h.logger.Error("handler.HandleWebhook failed to record event",
 			slog.String("request_id", requestID),
-			slog.String("clerk_user_id", data.ID),
-			slog.String("username", data.Username),
-			slog.String("first_name", data.FirstName),
-			slog.String("last_name", data.LastName),
-			slog.String("image_url", data.ImageURL),
+			slog.String("svix_id", svixID),
+			slog.Any("error", err),
+		)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "failed to record webhook",
+		})
+		return
+	}
+	if claim == nil {
+		// 再送されたメッセージは処理済みのため、成功として返す
+		h.logger.Info("handler.HandleWebhook duplicate event ignored",
+			slog.String("request_id", requestID),
+			slog.String("svix_id", svixID),
 		)
+		c.Status(http.StatusOK)
+		return
+	}
 
-		email := ""
-		if len(data.EmailAddresses) > 0 {
-			// TODO: 複数のメールアドレスを無効化できる？
-			email = data.EmailAddresses[0].EmailAddress
-		}
+	h.handleEvent(c, requestID, claim, event)
 
-		// ImageURL をオプショナルな *string 型に変換する。
-		// 空文字列の場合は nil を設定し、値がある場合はローカル変数にコピーしてから
-		// そのアドレスを取得する。これにより、構造体フィールドへの直接ポインタ取得を
-		// 避け、エスケープ解析の最適化と独立性を確保する。
-		var avatarURL *string
-		if data.ImageURL != "" {
-			url := data.ImageURL
-			avatarURL = &url
+	// 処理に失敗した場合は記録を取り消し、Svix の再送で処理をやり直せるようにする
+	if c.Writer.Status() >= http.StatusMultipleChoices {
+		if err := h.clerkWebhookService.AbortEvent(c.Request.Context(), claim); err != nil {
+			h.logger.Warn("handler.HandleWebhook failed to abort event",
+				slog.String("request_id", requestID),
+				slog.String("svix_id", svixID),
+				slog.Any("error", err),
+			)
 		}
+		return
+	}
 
-		// clerkUserInfo を作成
-		clerkUser, err := service.NewClerkUserInfoFromWebhook(
-			data.ID,
-			email,
-			data.FirstName,
-			data.LastName,
-			avatarURL,
+	// ユーザー情報を同期しないイベントを処理済みにする（同期したイベントは同期と同じトランザクションで処理済みになっている）。
+	// 記録に失敗しても、期限切れ後の再送で処理し直されるだけのため成功として返す
+	if err := h.clerkWebhookService.CompleteEvent(c.Request.Context(), claim); err != nil {
+		h.logger.Warn("handler.HandleWebhook failed to complete event",
+			slog.String("request_id", requestID),
+			slog.String("svix_id", svixID),
+			slog.Any("error", err),
 		)
-		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{
-				"error": "failed to sync user",
-			})
-			return
-		}
-
-		if _, err := h.userService.EnsureUser(c.Request.Context(), clerkUser); err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{
-				"error": "failed to sync user",
-			})
-			return
-		}
-
-		c.Status(http.StatusOK)
-		return
+	}
+}
 
-	case "user.updated":
-		var data clerkUserUpdatedData
+// イベントの種類ごとにユーザー情報を同期し、レスポンスを書き込みます。
+func (h *ClerkWebhookHandler) handleEvent(c *gin.Context, requestID string, claim *service.ClerkWebhookClaim, event clerkWebhookEvent) {
+	switch event.Type {
+	case "user.created", "user.updated":
+		var data service.ClerkWebhookUserData
 		if err := json.Unmarshal(event.Data, &data); err != nil {
 			c.JSON(http.StatusBadRequest, gin.H{
 				"error": "invalid webhook data",
@@ -156,44 +166,58 @@ func (h *ClerkWebhookHandler) HandleWebhook(c *gin.Context) {
 		}
 
 		// デバッグログ（DEBUG）
-		h.logger.Debug("handler.HandleWebhook user.updated",
+		h.logger.Debug("handler.HandleWebhook "+event.Type,
 			slog.String("request_id", requestID),
 			slog.String("clerk_user_id", data.ID),
-			slog.String("image_url", data.ImageURL),
+			slog.String("username", data.Username),
+			slog.Int64("updated_at", data.UpdatedAt),
 		)
 
-		// ユーザーが存在しない場合は無視
-		u, err := h.userService.FindUserByClerkUserID(c.Request.Context(), data.ID)
+		// 再同期用にペイロードを保存する。配信順が前後して古いペイロードが届いた場合は反映しない
+		latest, err := h.clerkWebhookService.RecordUserPayload(c.Request.Context(), claim.SvixID, event.Type, data, event.Data)
 		if err != nil {
-			if err == service.ErrUserNotFound {
-				h.logger.Warn("handler.HandleWebhook user.updated: user not found",
-					slog.String("request_id", requestID),
-					slog.String("clerk_user_id", data.ID),
-				)
-				c.Status(http.StatusOK)
-				return
-			}
+			h.logger.Error("handler.HandleWebhook failed to record user payload",
+				slog.String("request_id", requestID),
+				slog.String("clerk_user_id", data.ID),
+				slog.Any("error", err),
+			)
 			c.JSON(http.StatusInternalServerError, gin.H{
-				"error": "failed to resolve user by clerk user id",
+				"error": "failed to sync user",
 			})
 			return
 		}
-
-		// avatar_url を更新
-		var avatarURL *string
-		if data.ImageURL != "" {
-			url := data.ImageURL
-			avatarURL = &url
+		if !latest {
+			h.logger.Info("handler.HandleWebhook stale user payload ignored",
+				slog.String("request_id", requestID),
+				slog.String("clerk_user_id", data.ID),
+				slog.String("event_type", event.Type),
+			)
+			c.Status(http.StatusOK)
+			return
 		}
 
-		if err := h.userService.UpdateUserFromClerk(c.Request.Context(), u.ID, avatarURL); err != nil {
-			h.logger.Error("handler.HandleWebhook user.updated: failed to update user",
+		// user.created の場合のみユーザーを作成し、再受信（Clerk 側でユーザーを作り直した場合など）は退会済みのユーザーを復帰させる
+		if _, err := h.syncUser(c, data, service.ClerkSyncOptions{
+			Created: event.Type == "user.created",
+			Event:   claim,
+		}); err != nil {
+			if errors.Is(err, service.ErrUserNotFound) {
+				// 未作成・完全削除済みのユーザーの user.updated は同期しない
+				h.logger.Info("handler.HandleWebhook user not found; sync skipped",
+					slog.String("request_id", requestID),
+					slog.String("clerk_user_id", data.ID),
+					slog.String("event_type", event.Type),
+				)
+				c.Status(http.StatusOK)
+				return
+			}
+			h.logger.Error("handler.HandleWebhook failed to sync user",
 				slog.String("request_id", requestID),
-				slog.String("user_id", u.ID),
-				slog.String("error", err.Error()),
+				slog.String("clerk_user_id", data.ID),
+				slog.Any("error", err),
 			)
 			c.JSON(http.StatusInternalServerError, gin.H{
-				"error": "failed to update user",
+				"error": "failed to sync user",
 			})
 			return
 		}
@@ -216,33 +240,121 @@ func (h *ClerkWebhookHandler) HandleWebhook(c *gin.Context) {
 			slog.String("clerk_user_id", data.ID),
 		)
 
-		// ユーザーが存在しない場合は成功とする
-		u, err := h.userService.FindUserByClerkUserID(c.Request.Context(), data.ID)
-		if err != nil {
-			if err == service.ErrUserNotFound {
-				c.Status(http.StatusOK)
-				return
-			}
+		// 退会状態にする（ユーザーが存在しない場合は成功とする）
+		if err := h.userService.DeactivateClerkUser(c.Request.Context(), data.ID, claim); err != nil {
+			h.logger.Error("handler.HandleWebhook failed to deactivate user",
+				slog.String("request_id", requestID),
+				slog.String("clerk_user_id", data.ID),
+				slog.Any("error", err),
+			)
 			c.JSON(http.StatusInternalServerError, gin.H{
-				"error": "failed to resolve user by clerk user id",
+				"error": "failed to deactivate user",
 			})
 			return
 		}
 
-		// ユーザー削除
-		if err := h.userService.DeactivateUser(c.Request.Context(), u.ID); err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{
-				"error": "failed to deactivate user",
+		c.Status(http.StatusOK)
+		return
+
+	case "session.created":
+		var data clerkSessionData
+		if err := json.Unmarshal(event.Data, &data); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "invalid webhook data",
 			})
 			return
 		}
 
+		// ユーザーは初回のリクエスト時に AuthMiddleware が作成するため、ここでは同期漏れの検知のみ行う
+		if _, err := h.userService.FindUserByClerkUserID(c.Request.Context(), data.UserID); err == service.ErrUserNotFound {
+			h.logger.Warn("handler.HandleWebhook session.created: user not synced yet",
+				slog.String("request_id", requestID),
+				slog.String("clerk_user_id", data.UserID),
+				slog.String("session_id", data.ID),
+			)
+		}
+
+		c.Status(http.StatusOK)
+		return
+
+	case "session.ended", "session.removed", "session.revoked", "email.created":
+		// セッションはリクエストごとに JWT で検証し、メールアドレスの変更は user.updated で同期するため、
+		// 受信の記録のみ行う
+		h.logger.Debug("handler.HandleWebhook event acknowledged",
+			slog.String("request_id", requestID),
+			slog.String("event_type", event.Type),
+		)
 		c.Status(http.StatusOK)
 		return
 
 	default:
 		// 他のイベントタイプは無視
+		h.logger.Info("handler.HandleWebhook unsupported event ignored",
+			slog.String("request_id", requestID),
+			slog.String("event_type", event.Type),
+		)
 		c.Status(http.StatusOK)
 		return
 	}
 }
+
+// Clerk のユーザー情報を users テーブルに同期します。
+func (h *ClerkWebhookHandler) syncUser(c *gin.Context, data service.ClerkWebhookUserData, opts service.ClerkSyncOptions) (*model.User, error) {
+	clerkUser, err := data.ToClerkUserInfo()
+	if err != nil {
+		return nil, err
+	}
+	return h.userService.SyncUserFromClerk(c.Request.Context(), clerkUser, opts)
+}
+
+// 保存済みの最新の Webhook ペイロードから、ユーザー情報を再同期します（管理者用）。
+// Webhook の取りこぼしや同期処理の不具合の修正後に使います。
+// POST /api/v1/admin/clerk/users/:clerkUserId/resync
+func (h *ClerkWebhookHandler) ResyncUser(c *gin.Context) {
+	requestID := middleware.GetRequestID(c)
+	clerkUserID := c.Param("clerkUserId")
+
+	data, err := h.clerkWebhookService.FindUserPayload(c.Request.Context(), clerkUserID)
+	if err != nil {
+		if errors.Is(err, service.ErrClerkUserPayloadNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "webhook payload not found"})
+			return
+		}
+		h.logger.Error("handler.ResyncUser failed to find payload",
+			slog.String("request_id", requestID),
+			slog.String("clerk_user_id", clerkUserID),
+			slog.Any("error", err),
+		)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to resync user"})
+		return
+	}
+
+	user, err := h.syncUser(c, *data, service.ClerkSyncOptions{})
+	if err != nil {
+		if errors.Is(err, service.ErrUserNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "user not found"})
+			return
+		}
+		h.logger.Error("handler.ResyncUser failed to sync user",
+			slog.String("request_id", requestID),
+			slog.String("clerk_user_id", clerkUserID),
+			slog.Any("error", err),
+		)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to resync user"})
+		return
+	}
+
+	h.logger.Info("handler.ResyncUser completed",
+		slog.String("request_id", requestID),
+		slog.String("clerk_user_id", clerkUserID),
+		slog.String("user_id", user.ID),
+	)
+	c.JSON(http.StatusOK, UserProfileResponse{
+		ID:          user.ID,
+		DisplayID:   user.DisplayID,
+		DisplayName: user.DisplayName,
+		AvatarURL:   user.AvatarURL,
+		Bio:         user.Bio,
+		IsPrivate:   user.IsPrivate,
+	})
+}
@@ -5,11 +5,17 @@ import (
 	"encoding/json"
 	"errors"
 	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
 	"testing"
+	"time"
 
 	"cinetag-backend/src/internal/model"
+	"cinetag-backend/src/internal/repository"
 	"cinetag-backend/src/internal/service"
 	"cinetag-backend/src/internal/testutil"
+	"cinetag-backend/src/internal/webhook"
 
 	"github.com/gin-gonic/gin"
 )
@@ -17,8 +23,8 @@ import (
 type fakeWebhookUserService struct {
 	EnsureUserFn            func(ctx context.Context, clerkUser service.ClerkUserInfo) (*model.User, error)
 	FindUserByClerkUserIDFn func(ctx context.Context, clerkUserID string) (*model.User, error)
-	UpdateUserFromClerkFn   func(ctx context.Context, userID string, avatarURL *string) error
-	DeactivateUserFn        func(ctx context.Context, userID string) error
+	SyncUserFromClerkFn     func(ctx context.Context, clerkUser service.ClerkUserInfo, opts service.ClerkSyncOptions) (*model.User, error)
+	DeactivateClerkUserFn   func(ctx context.Context, clerkUserID string, event *service.ClerkWebhookClaim) error
 }
 
 func (f *fakeWebhookUserService) EnsureUser(ctx context.Context, clerkUser service.ClerkUserInfo) (*model.User, error) {
@@ -39,19 +45,23 @@ func (f *fakeWebhookUserService) GetUserByDisplayID(ctx context.Context, display
 	return nil, service.ErrUserNotFound
 }
 
+func (f *fakeWebhookUserService) ResolveRenamedDisplayID(ctx context.Context, displayID string) (string, error) {
+	return "", service.ErrUserNotFound
+}
+
 func (f *fakeWebhookUserService) UpdateUser(ctx context.Context, userID string, input service.UpdateUserInput) (*model.User, error) {
 	return nil, nil
 }
 
-func (f *fakeWebhookUserService) UpdateUserFromClerk(ctx context.Context, userID string, avatarURL *string) error {
-	if f.UpdateUserFromClerkFn == nil {
-		return nil
+func (f *fakeWebhookUserService) SyncUserFromClerk(ctx context.Context, clerkUser service.ClerkUserInfo, opts service.ClerkSyncOptions) (*model.User, error) {
+	if f.SyncUserFromClerkFn == nil {
+		return &model.User{ID: "u1", ClerkUserID: clerkUser.ID}, nil
 	}
-	return f.UpdateUserFromClerkFn(ctx, userID, avatarURL)
+	return f.SyncUserFromClerkFn(ctx, clerkUser, opts)
 }
 
-func (f *fakeWebhookUserService) FollowUser(ctx context.Context, followerID, followeeID string) error {
-	return nil
+func (f *fakeWebhookUserService) FollowUser(ctx context.Context, followerID, followeeID string) (service.FollowStatus, error) {
+	return service.FollowStatusFollowing, nil
 }
 
 func (f *fakeWebhookUserService) UnfollowUser(ctx context.Context, followerID, followeeID string) error {
@@ -74,25 +84,120 @@ func (f *fakeWebhookUserService) GetFollowStats(ctx context.Context, userID stri
 	return 0, 0, nil
 }
 
-func (f *fakeWebhookUserService) DeactivateUser(ctx context.Context, userID string) error {
-	if f.DeactivateUserFn == nil {
+func (f *fakeWebhookUserService) DeactivateUser(ctx context.Context, userID string, policy service.AccountDeletionPolicy) (*model.User, error) {
+	return &model.User{ID: userID}, nil
+}
+
+func (f *fakeWebhookUserService) DeactivateClerkUser(ctx context.Context, clerkUserID string, event *service.ClerkWebhookClaim) error {
+	if f.DeactivateClerkUserFn == nil {
 		return nil
 	}
-	return f.DeactivateUserFn(ctx, userID)
+	return f.DeactivateClerkUserFn(ctx, clerkUserID, event)
+}
+
+func (f *fakeWebhookUserService) ReactivateUser(ctx context.Context, userID string) (*model.User, error) {
+	return nil, nil
+}
+
+func (f *fakeWebhookUserService) PurgeDeactivatedUsers(ctx context.Context, now time.Time, limit int) (int, error) {
+	return 0, nil
+}
+
+func (f *fakeWebhookUserService) BlockUser(ctx context.Context, blockerID, blockedID string) error {
+	return nil
+}
+
+func (f *fakeWebhookUserService) UnblockUser(ctx context.Context, blockerID, blockedID string) error {
+	return nil
+}
+
+func (f *fakeWebhookUserService) MuteUser(ctx context.Context, muterID, mutedID string) error {
+	return nil
+}
+
+func (f *fakeWebhookUserService) UnmuteUser(ctx context.Context, muterID, mutedID string) error {
+	return nil
+}
+
+func (f *fakeWebhookUserService) ListBlockedUsers(ctx context.Context, userID string, page, pageSize int) ([]*model.User, int64, error) {
+	return []*model.User{}, 0, nil
+}
+
+func (f *fakeWebhookUserService) ListMutedUsers(ctx context.Context, userID string, page, pageSize int) ([]*model.User, int64, error) {
+	return []*model.User{}, 0, nil
+}
+
+func (f *fakeWebhookUserService) SearchUsers(ctx context.Context, viewerID, query string, page, pageSize int) ([]service.UserSearchResult, int64, error) {
+	return []service.UserSearchResult{}, 0, nil
+}
+
+func (f *fakeWebhookUserService) SuggestUsers(ctx context.Context, userID string, limit int) ([]service.UserSuggestion, error) {
+	return []service.UserSuggestion{}, nil
+}
+
+func (f *fakeWebhookUserService) ApproveFollowRequest(ctx context.Context, targetID, requesterID string) error {
+	return nil
+}
+
+func (f *fakeWebhookUserService) DenyFollowRequest(ctx context.Context, targetID, requesterID string) error {
+	return nil
+}
+
+func (f *fakeWebhookUserService) ListFollowRequests(ctx context.Context, userID string, page, pageSize int) ([]*model.User, int64, error) {
+	return []*model.User{}, 0, nil
+}
+
+func (f *fakeWebhookUserService) HasPendingFollowRequest(ctx context.Context, requesterID, targetID string) (bool, error) {
+	return false, nil
+}
+
+func (f *fakeWebhookUserService) CanViewUserContent(ctx context.Context, viewerID string, owner *model.User) (bool, error) {
+	return true, nil
 }
 
+// Clerk Webhook のテストで使う署名鍵。
+const testClerkWebhookSecret = "whsec_dGVzdC1jbGVyay13ZWJob29rLXNlY3JldA=="
+
 func newWebhookHandlerRouter(t *testing.T, userSvc service.UserService) *gin.Engine {
 	t.Helper()
+	return newWebhookHandlerRouterWithEvents(t, userSvc, &testutil.FakeClerkWebhookEventRepository{})
+}
+
+func newWebhookHandlerRouterWithEvents(t *testing.T, userSvc service.UserService, eventRepo repository.ClerkWebhookEventRepository) *gin.Engine {
+	t.Helper()
 
 	r := testutil.NewTestRouter()
 	logger := testutil.NewTestLogger()
-	h := NewClerkWebhookHandler(logger, userSvc)
+	clerkWebhookSvc := service.NewClerkWebhookService(logger, eventRepo, service.ClerkWebhookConfig{Secrets: []string{testClerkWebhookSecret}})
+	h := NewClerkWebhookHandler(logger, userSvc, clerkWebhookSvc)
 
 	r.POST("/api/v1/clerk/webhook", h.HandleWebhook)
 
 	return r
 }
 
+// Clerk（Svix）と同じ形式で署名した Webhook のヘッダーを返す。
+func signedWebhookHeaders(t *testing.T, svixID string, body []byte) map[string]string {
+	t.Helper()
+	now := time.Now()
+	signature, err := webhook.SignSvix(testClerkWebhookSecret, svixID, now, body)
+	if err != nil {
+		t.Fatalf("failed to sign webhook: %v", err)
+	}
+	return map[string]string{
+		"Content-Type":              "application/json",
+		webhook.HeaderSvixID:        svixID,
+		webhook.HeaderSvixTimestamp: strconv.FormatInt(now.Unix(), 10),
+		webhook.HeaderSvixSignature: signature,
+	}
+}
+
+// 署名付きの Webhook を送信する。
+func performSignedWebhook(t *testing.T, r *gin.Engine, body []byte) *httptest.ResponseRecorder {
+	t.Helper()
+	return testutil.PerformRequest(r, http.MethodPost, "/api/v1/clerk/webhook", body, signedWebhookHeaders(t, "msg_test", body))
+}
+
 func TestClerkWebhookHandler_HandleWebhook(t *testing.T) {
 	t.Parallel()
 
@@ -101,7 +206,7 @@ func TestClerkWebhookHandler_HandleWebhook(t *testing.T) {
 
 		r := newWebhookHandlerRouter(t, &fakeWebhookUserService{})
 		body := []byte("{invalid json")
-		rw := testutil.PerformRequest(r, http.MethodPost, "/api/v1/clerk/webhook", body, nil)
+		rw := performSignedWebhook(t, r, body)
 		if rw.Code != http.StatusBadRequest {
 			t.Fatalf("expected 400, got %d", rw.Code)
 		}
@@ -121,13 +226,210 @@ func TestClerkWebhookHandler_HandleWebhook(t *testing.T) {
 			"type": "session.created",
 			"data": map[string]any{},
 		})
-		rw := testutil.PerformRequest(r, http.MethodPost, "/api/v1/clerk/webhook", body, nil)
+		rw := performSignedWebhook(t, r, body)
 		if rw.Code != http.StatusOK {
 			t.Fatalf("expected 200, got %d", rw.Code)
 		}
 	})
 }
 
+func TestClerkWebhookHandler_VerifySignature(t *testing.T) {
+	t.Parallel()
+
+	deletedBody := []byte(`{"type":"user.deleted","data":{"id":"user_123"}}`)
+
+	t.Run("署名が検証できない: 401 (処理しない)", func(t *testing.T) {
+		t.Parallel()
+
+		called := false
+		userSvc := &fakeWebhookUserService{
+			DeactivateClerkUserFn: func(ctx context.Context, clerkUserID string, event *service.ClerkWebhookClaim) error {
+				called = true
+				return nil
+			},
+		}
+		r := newWebhookHandlerRouter(t, userSvc)
+
+		forged := signedWebhookHeaders(t, "msg_test", []byte(`{"type":"session.created","data":{}}`))
+		stale := signedWebhookHeaders(t, "msg_test", deletedBody)
+		stale[webhook.HeaderSvixTimestamp] = strconv.FormatInt(time.Now().Add(-10*time.Minute).Unix(), 10)
+		for name, headers := range map[string]map[string]string{
+			"ヘッダーなし":  {"Content-Type": "application/json"},
+			"本文の改ざん":  forged,
+			"古いリクエスト": stale,
+		} {
+			rw := testutil.PerformRequest(r, http.MethodPost, "/api/v1/clerk/webhook", deletedBody, headers)
+			if rw.Code != http.StatusUnauthorized {
+				t.Fatalf("%s: expected 401, got %d", name, rw.Code)
+			}
+		}
+		if called {
+			t.Fatalf("user service must not be called for unverified requests")
+		}
+	})
+
+	t.Run("署名鍵が未設定: 401", func(t *testing.T) {
+		t.Parallel()
+
+		r := testutil.NewTestRouter()
+		logger := testutil.NewTestLogger()
+		h := NewClerkWebhookHandler(logger, &fakeWebhookUserService{},
+			service.NewClerkWebhookService(logger, &testutil.FakeClerkWebhookEventRepository{}, service.ClerkWebhookConfig{}))
+		r.POST("/api/v1/clerk/webhook", h.HandleWebhook)
+
+		rw := performSignedWebhook(t, r, deletedBody)
+		if rw.Code != http.StatusUnauthorized {
+			t.Fatalf("expected 401, got %d", rw.Code)
+		}
+	})
+
+	t.Run("再送されたメッセージは処理せず200", func(t *testing.T) {
+		t.Parallel()
+
+		var mu sync.Mutex
+		claimed := map[string]bool{}
+		eventRepo := &testutil.FakeClerkWebhookEventRepository{
+			ClaimFn: func(ctx context.Context, svixID, eventType, lockToken string, now, lockedUntil time.Time) (bool, error) {
+				mu.Lock()
+				defer mu.Unlock()
+				if claimed[svixID] {
+					return false, nil
+				}
+				claimed[svixID] = true
+				return true, nil
+			},
+		}
+		deactivated := 0
+		userSvc := &fakeWebhookUserService{
+			DeactivateClerkUserFn: func(ctx context.Context, clerkUserID string, event *service.ClerkWebhookClaim) error {
+				deactivated++
+				return nil
+			},
+		}
+		r := newWebhookHandlerRouterWithEvents(t, userSvc, eventRepo)
+
+		for i := 0; i < 2; i++ {
+			if rw := performSignedWebhook(t, r, deletedBody); rw.Code != http.StatusOK {
+				t.Fatalf("expected 200, got %d", rw.Code)
+			}
+		}
+		if deactivated != 1 {
+			t.Fatalf("expected event to be processed once, got %d", deactivated)
+		}
+	})
+
+	t.Run("処理中のメッセージの再送は処理せず409", func(t *testing.T) {
+		t.Parallel()
+
+		eventRepo := &testutil.FakeClerkWebhookEventRepository{
+			ClaimFn: func(ctx context.Context, svixID, eventType, lockToken string, now, lockedUntil time.Time) (bool, error) {
+				return false, repository.ErrClerkWebhookEventInProgress
+			},
+		}
+		called := false
+		userSvc := &fakeWebhookUserService{
+			DeactivateClerkUserFn: func(ctx context.Context, clerkUserID string, event *service.ClerkWebhookClaim) error {
+				called = true
+				return nil
+			},
+		}
+		r := newWebhookHandlerRouterWithEvents(t, userSvc, eventRepo)
+
+		if rw := performSignedWebhook(t, r, deletedBody); rw.Code != http.StatusConflict {
+			t.Fatalf("expected 409, got %d", rw.Code)
+		}
+		if called {
+			t.Fatalf("event in progress must not be processed")
+		}
+	})
+
+	t.Run("処理に成功した場合は処理済みにする", func(t *testing.T) {
+		t.Parallel()
+
+		var claimedToken, completedToken string
+		eventRepo := &testutil.FakeClerkWebhookEventRepository{
+			ClaimFn: func(ctx context.Context, svixID, eventType, lockToken string, now, lockedUntil time.Time) (bool, error) {
+				if !lockedUntil.After(now) {
+					t.Errorf("lockedUntil = %v, want after %v", lockedUntil, now)
+				}
+				claimedToken = lockToken
+				return true, nil
+			},
+			MarkProcessedFn: func(ctx context.Context, svixID, lockToken string, at time.Time) error {
+				completedToken = lockToken
+				return nil
+			},
+		}
+		var gotEvent *service.ClerkWebhookClaim
+		userSvc := &fakeWebhookUserService{
+			DeactivateClerkUserFn: func(ctx context.Context, clerkUserID string, event *service.ClerkWebhookClaim) error {
+				gotEvent = event
+				return nil
+			},
+		}
+		r := newWebhookHandlerRouterWithEvents(t, userSvc, eventRepo)
+
+		if rw := performSignedWebhook(t, r, deletedBody); rw.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d", rw.Code)
+		}
+		if claimedToken == "" || completedToken != claimedToken {
+			t.Fatalf("expected event to be completed with token %q, got %q", claimedToken, completedToken)
+		}
+		if gotEvent == nil || gotEvent.SvixID != "msg_test" || gotEvent.LockToken != claimedToken {
+			t.Fatalf("expected claim to be passed to the user service, got %+v", gotEvent)
+		}
+	})
+
+	t.Run("処理に失敗した場合は記録を取り消す", func(t *testing.T) {
+		t.Parallel()
+
+		var claimedToken, released, releasedToken string
+		eventRepo := &testutil.FakeClerkWebhookEventRepository{
+			ClaimFn: func(ctx context.Context, svixID, eventType, lockToken string, now, lockedUntil time.Time) (bool, error) {
+				claimedToken = lockToken
+				return true, nil
+			},
+			MarkProcessedFn: func(ctx context.Context, svixID, lockToken string, at time.Time) error {
+				t.Errorf("failed event must not be completed")
+				return nil
+			},
+			ReleaseFn: func(ctx context.Context, svixID, lockToken string) error {
+				released = svixID
+				releasedToken = lockToken
+				return nil
+			},
+		}
+		userSvc := &fakeWebhookUserService{
+			DeactivateClerkUserFn: func(ctx context.Context, clerkUserID string, event *service.ClerkWebhookClaim) error {
+				return errors.New("db error")
+			},
+		}
+		r := newWebhookHandlerRouterWithEvents(t, userSvc, eventRepo)
+
+		if rw := performSignedWebhook(t, r, deletedBody); rw.Code != http.StatusInternalServerError {
+			t.Fatalf("expected 500, got %d", rw.Code)
+		}
+		if released != "msg_test" || releasedToken != claimedToken {
+			t.Fatalf("expected event to be released with token %q, got %q (%q)", claimedToken, released, releasedToken)
+		}
+	})
+
+	t.Run("記録に失敗した場合は500", func(t *testing.T) {
+		t.Parallel()
+
+		eventRepo := &testutil.FakeClerkWebhookEventRepository{
+			ClaimFn: func(ctx context.Context, svixID, eventType, lockToken string, now, lockedUntil time.Time) (bool, error) {
+				return false, errors.New("db error")
+			},
+		}
+		r := newWebhookHandlerRouterWithEvents(t, &fakeWebhookUserService{}, eventRepo)
+
+		if rw := performSignedWebhook(t, r, deletedBody); rw.Code != http.StatusInternalServerError {
+			t.Fatalf("expected 500, got %d", rw.Code)
+		}
+	})
+}
+
 func TestClerkWebhookHandler_UserCreated(t *testing.T) {
 	t.Parallel()
 
@@ -139,7 +441,7 @@ func TestClerkWebhookHandler_UserCreated(t *testing.T) {
 			"type": "user.created",
 			"data": "not an object",
 		})
-		rw := testutil.PerformRequest(r, http.MethodPost, "/api/v1/clerk/webhook", body, nil)
+		rw := performSignedWebhook(t, r, body)
 		if rw.Code != http.StatusBadRequest {
 			t.Fatalf("expected 400, got %d", rw.Code)
 		}
@@ -162,17 +464,17 @@ func TestClerkWebhookHandler_UserCreated(t *testing.T) {
 				"email_addresses": []any{},
 			},
 		})
-		rw := testutil.PerformRequest(r, http.MethodPost, "/api/v1/clerk/webhook", body, nil)
+		rw := performSignedWebhook(t, r, body)
 		if rw.Code != http.StatusInternalServerError {
 			t.Fatalf("expected 500, got %d", rw.Code)
 		}
 	})
 
-	t.Run("EnsureUser失敗: 500", func(t *testing.T) {
+	t.Run("SyncUserFromClerk失敗: 500", func(t *testing.T) {
 		t.Parallel()
 
 		userSvc := &fakeWebhookUserService{
-			EnsureUserFn: func(ctx context.Context, clerkUser service.ClerkUserInfo) (*model.User, error) {
+			SyncUserFromClerkFn: func(ctx context.Context, clerkUser service.ClerkUserInfo, opts service.ClerkSyncOptions) (*model.User, error) {
 				return nil, errors.New("db error")
 			},
 		}
@@ -190,7 +492,7 @@ func TestClerkWebhookHandler_UserCreated(t *testing.T) {
 				},
 			},
 		})
-		rw := testutil.PerformRequest(r, http.MethodPost, "/api/v1/clerk/webhook", body, nil)
+		rw := performSignedWebhook(t, r, body)
 		if rw.Code != http.StatusInternalServerError {
 			t.Fatalf("expected 500, got %d", rw.Code)
 		}
@@ -206,9 +508,11 @@ func TestClerkWebhookHandler_UserCreated(t *testing.T) {
 		t.Parallel()
 
 		var gotClerkUser service.ClerkUserInfo
+		var gotCreated bool
 		userSvc := &fakeWebhookUserService{
-			EnsureUserFn: func(ctx context.Context, clerkUser service.ClerkUserInfo) (*model.User, error) {
+			SyncUserFromClerkFn: func(ctx context.Context, clerkUser service.ClerkUserInfo, opts service.ClerkSyncOptions) (*model.User, error) {
 				gotClerkUser = clerkUser
+				gotCreated = opts.Created
 				return &model.User{ID: "u1"}, nil
 			},
 		}
@@ -226,7 +530,7 @@ func TestClerkWebhookHandler_UserCreated(t *testing.T) {
 				},
 			},
 		})
-		rw := testutil.PerformRequest(r, http.MethodPost, "/api/v1/clerk/webhook", body, nil)
+		rw := performSignedWebhook(t, r, body)
 		if rw.Code != http.StatusOK {
 			t.Fatalf("expected 200, got %d", rw.Code)
 		}
@@ -246,6 +550,9 @@ func TestClerkWebhookHandler_UserCreated(t *testing.T) {
 		if gotClerkUser.AvatarURL == nil || *gotClerkUser.AvatarURL != "https://example.com/avatar.png" {
 			t.Errorf("AvatarURL = %v, want %q", gotClerkUser.AvatarURL, "https://example.com/avatar.png")
 		}
+		if !gotCreated {
+			t.Errorf("created = false, want true")
+		}
 	})
 
 	t.Run("成功(image_url無し): 200", func(t *testing.T) {
@@ -253,7 +560,7 @@ func TestClerkWebhookHandler_UserCreated(t *testing.T) {
 
 		var gotClerkUser service.ClerkUserInfo
 		userSvc := &fakeWebhookUserService{
-			EnsureUserFn: func(ctx context.Context, clerkUser service.ClerkUserInfo) (*model.User, error) {
+			SyncUserFromClerkFn: func(ctx context.Context, clerkUser service.ClerkUserInfo, opts service.ClerkSyncOptions) (*model.User, error) {
 				gotClerkUser = clerkUser
 				return &model.User{ID: "u1"}, nil
 			},
@@ -272,7 +579,7 @@ func TestClerkWebhookHandler_UserCreated(t *testing.T) {
 				},
 			},
 		})
-		rw := testutil.PerformRequest(r, http.MethodPost, "/api/v1/clerk/webhook", body, nil)
+		rw := performSignedWebhook(t, r, body)
 		if rw.Code != http.StatusOK {
 			t.Fatalf("expected 200, got %d", rw.Code)
 		}
@@ -281,11 +588,67 @@ func TestClerkWebhookHandler_UserCreated(t *testing.T) {
 			t.Errorf("AvatarURL = %v, want nil", gotClerkUser.AvatarURL)
 		}
 	})
+
+	t.Run("成功: 200 (プライマリのメールアドレスとユーザー名を使う)", func(t *testing.T) {
+		t.Parallel()
+
+		var gotClerkUser service.ClerkUserInfo
+		userSvc := &fakeWebhookUserService{
+			SyncUserFromClerkFn: func(ctx context.Context, clerkUser service.ClerkUserInfo, opts service.ClerkSyncOptions) (*model.User, error) {
+				gotClerkUser = clerkUser
+				return &model.User{ID: "u1"}, nil
+			},
+		}
+
+		r := newWebhookHandlerRouter(t, userSvc)
+		body := mustMarshalJSON(t, map[string]any{
+			"type": "user.created",
+			"data": map[string]any{
+				"id":                       "user_123",
+				"username":                 "john_doe",
+				"primary_email_address_id": "idn_2",
+				"email_addresses": []any{
+					map[string]any{"id": "idn_1", "email_address": "old@example.com"},
+					map[string]any{"id": "idn_2", "email_address": "primary@example.com"},
+				},
+			},
+		})
+		rw := performSignedWebhook(t, r, body)
+		if rw.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d", rw.Code)
+		}
+
+		if gotClerkUser.Email != "primary@example.com" {
+			t.Errorf("Email = %q, want %q", gotClerkUser.Email, "primary@example.com")
+		}
+		if gotClerkUser.Username != "john_doe" {
+			t.Errorf("Username = %q, want %q", gotClerkUser.Username, "john_doe")
+		}
+	})
 }
 
 func TestClerkWebhookHandler_UserUpdated(t *testing.T) {
 	t.Parallel()
 
+	updatedBody := func(t *testing.T, updatedAt int64) []byte {
+		t.Helper()
+		return mustMarshalJSON(t, map[string]any{
+			"type": "user.updated",
+			"data": map[string]any{
+				"id":                       "user_123",
+				"username":                 "new_name",
+				"first_name":               "John",
+				"last_name":                "Smith",
+				"image_url":                "https://example.com/new-avatar.png",
+				"primary_email_address_id": "idn_1",
+				"email_addresses": []any{
+					map[string]any{"id": "idn_1", "email_address": "john@example.com"},
+				},
+				"updated_at": updatedAt,
+			},
+		})
+	}
+
 	t.Run("無効なdata: 400", func(t *testing.T) {
 		t.Parallel()
 
@@ -294,7 +657,7 @@ func TestClerkWebhookHandler_UserUpdated(t *testing.T) {
 			"type": "user.updated",
 			"data": "not an object",
 		})
-		rw := testutil.PerformRequest(r, http.MethodPost, "/api/v1/clerk/webhook", body, nil)
+		rw := performSignedWebhook(t, r, body)
 		if rw.Code != http.StatusBadRequest {
 			t.Fatalf("expected 400, got %d", rw.Code)
 		}
@@ -306,156 +669,139 @@ func TestClerkWebhookHandler_UserUpdated(t *testing.T) {
 		}
 	})
 
-	t.Run("ユーザーが存在しない(ErrUserNotFound): 200", func(t *testing.T) {
+	t.Run("SyncUserFromClerk失敗: 500", func(t *testing.T) {
 		t.Parallel()
 
 		userSvc := &fakeWebhookUserService{
-			FindUserByClerkUserIDFn: func(ctx context.Context, clerkUserID string) (*model.User, error) {
-				return nil, service.ErrUserNotFound
-			},
-		}
-
-		r := newWebhookHandlerRouter(t, userSvc)
-		body := mustMarshalJSON(t, map[string]any{
-			"type": "user.updated",
-			"data": map[string]any{
-				"id":        "user_nonexistent",
-				"image_url": "https://example.com/new-avatar.png",
-			},
-		})
-		rw := testutil.PerformRequest(r, http.MethodPost, "/api/v1/clerk/webhook", body, nil)
-		if rw.Code != http.StatusOK {
-			t.Fatalf("expected 200, got %d", rw.Code)
-		}
-	})
-
-	t.Run("FindUserByClerkUserID失敗(DBエラー): 500", func(t *testing.T) {
-		t.Parallel()
-
-		userSvc := &fakeWebhookUserService{
-			FindUserByClerkUserIDFn: func(ctx context.Context, clerkUserID string) (*model.User, error) {
-				return nil, errors.New("db error")
+			SyncUserFromClerkFn: func(ctx context.Context, clerkUser service.ClerkUserInfo, opts service.ClerkSyncOptions) (*model.User, error) {
+				return nil, errors.New("update failed")
 			},
 		}
 
 		r := newWebhookHandlerRouter(t, userSvc)
-		body := mustMarshalJSON(t, map[string]any{
-			"type": "user.updated",
-			"data": map[string]any{
-				"id":        "user_123",
-				"image_url": "https://example.com/new-avatar.png",
-			},
-		})
-		rw := testutil.PerformRequest(r, http.MethodPost, "/api/v1/clerk/webhook", body, nil)
+		rw := performSignedWebhook(t, r, updatedBody(t, 1700000000000))
 		if rw.Code != http.StatusInternalServerError {
 			t.Fatalf("expected 500, got %d", rw.Code)
 		}
 
 		resp := map[string]any{}
 		testutil.MustUnmarshalJSON(t, rw.Body.Bytes(), &resp)
-		if resp["error"] != "failed to resolve user by clerk user id" {
+		if resp["error"] != "failed to sync user" {
 			t.Fatalf("unexpected error: %v", resp["error"])
 		}
 	})
 
-	t.Run("UpdateUserFromClerk失敗: 500", func(t *testing.T) {
+	t.Run("成功: 200 (プロフィール全体を同期)", func(t *testing.T) {
 		t.Parallel()
 
+		var gotClerkUser service.ClerkUserInfo
+		var gotEvent *service.ClerkWebhookClaim
+		gotCreated := true
 		userSvc := &fakeWebhookUserService{
-			FindUserByClerkUserIDFn: func(ctx context.Context, clerkUserID string) (*model.User, error) {
-				return &model.User{ID: "u1", ClerkUserID: clerkUserID}, nil
+			SyncUserFromClerkFn: func(ctx context.Context, clerkUser service.ClerkUserInfo, opts service.ClerkSyncOptions) (*model.User, error) {
+				gotClerkUser = clerkUser
+				gotCreated = opts.Created
+				gotEvent = opts.Event
+				return &model.User{ID: "u1", ClerkUserID: clerkUser.ID}, nil
 			},
-			UpdateUserFromClerkFn: func(ctx context.Context, userID string, avatarURL *string) error {
-				return errors.New("update failed")
+		}
+		var saved *model.ClerkUserPayload
+		eventRepo := &testutil.FakeClerkWebhookEventRepository{
+			SaveUserPayloadFn: func(ctx context.Context, p *model.ClerkUserPayload) (bool, error) {
+				saved = p
+				return true, nil
 			},
 		}
 
-		r := newWebhookHandlerRouter(t, userSvc)
-		body := mustMarshalJSON(t, map[string]any{
-			"type": "user.updated",
-			"data": map[string]any{
-				"id":        "user_123",
-				"image_url": "https://example.com/new-avatar.png",
-			},
-		})
-		rw := testutil.PerformRequest(r, http.MethodPost, "/api/v1/clerk/webhook", body, nil)
-		if rw.Code != http.StatusInternalServerError {
-			t.Fatalf("expected 500, got %d", rw.Code)
+		r := newWebhookHandlerRouterWithEvents(t, userSvc, eventRepo)
+		rw := performSignedWebhook(t, r, updatedBody(t, 1700000000000))
+		if rw.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d", rw.Code)
 		}
 
-		resp := map[string]any{}
-		testutil.MustUnmarshalJSON(t, rw.Body.Bytes(), &resp)
-		if resp["error"] != "failed to update user" {
-			t.Fatalf("unexpected error: %v", resp["error"])
+		if gotClerkUser.ID != "user_123" || gotClerkUser.Email != "john@example.com" || gotClerkUser.Username != "new_name" ||
+			gotClerkUser.FirstName != "John" || gotClerkUser.LastName != "Smith" {
+			t.Errorf("unexpected clerk user: %+v", gotClerkUser)
+		}
+		if gotClerkUser.AvatarURL == nil || *gotClerkUser.AvatarURL != "https://example.com/new-avatar.png" {
+			t.Errorf("AvatarURL = %v, want %q", gotClerkUser.AvatarURL, "https://example.com/new-avatar.png")
+		}
+		if gotCreated {
+			t.Errorf("created = true, want false")
+		}
+		if gotEvent == nil || gotEvent.SvixID != "msg_test" {
+			t.Errorf("event = %+v, want claim for %q", gotEvent, "msg_test")
+		}
+		if saved == nil || saved.ClerkUserID != "user_123" || saved.SvixID != "msg_test" || saved.EventType != "user.updated" || saved.ClerkUpdatedAt != 1700000000000 {
+			t.Errorf("unexpected saved payload: %+v", saved)
 		}
 	})
 
-	t.Run("成功: 200 (avatar_url更新)", func(t *testing.T) {
+	t.Run("古いペイロード: 200 (同期しない)", func(t *testing.T) {
 		t.Parallel()
 
-		var gotUserID string
-		var gotAvatarURL *string
+		called := false
 		userSvc := &fakeWebhookUserService{
-			FindUserByClerkUserIDFn: func(ctx context.Context, clerkUserID string) (*model.User, error) {
-				return &model.User{ID: "u1", ClerkUserID: clerkUserID}, nil
+			SyncUserFromClerkFn: func(ctx context.Context, clerkUser service.ClerkUserInfo, opts service.ClerkSyncOptions) (*model.User, error) {
+				called = true
+				return &model.User{ID: "u1"}, nil
 			},
-			UpdateUserFromClerkFn: func(ctx context.Context, userID string, avatarURL *string) error {
-				gotUserID = userID
-				gotAvatarURL = avatarURL
-				return nil
+		}
+		eventRepo := &testutil.FakeClerkWebhookEventRepository{
+			SaveUserPayloadFn: func(ctx context.Context, p *model.ClerkUserPayload) (bool, error) {
+				return false, nil
 			},
 		}
 
-		r := newWebhookHandlerRouter(t, userSvc)
-		body := mustMarshalJSON(t, map[string]any{
-			"type": "user.updated",
-			"data": map[string]any{
-				"id":        "user_123",
-				"image_url": "https://example.com/new-avatar.png",
-			},
-		})
-		rw := testutil.PerformRequest(r, http.MethodPost, "/api/v1/clerk/webhook", body, nil)
+		r := newWebhookHandlerRouterWithEvents(t, userSvc, eventRepo)
+		rw := performSignedWebhook(t, r, updatedBody(t, 1))
 		if rw.Code != http.StatusOK {
 			t.Fatalf("expected 200, got %d", rw.Code)
 		}
-
-		if gotUserID != "u1" {
-			t.Errorf("userID = %q, want %q", gotUserID, "u1")
-		}
-		if gotAvatarURL == nil || *gotAvatarURL != "https://example.com/new-avatar.png" {
-			t.Errorf("avatarURL = %v, want %q", gotAvatarURL, "https://example.com/new-avatar.png")
+		if called {
+			t.Fatalf("stale payload must not be synced")
 		}
 	})
 
-	t.Run("成功: 200 (avatar_url空でnil)", func(t *testing.T) {
+	t.Run("未作成・完全削除済みのユーザー: 200 (同期しない)", func(t *testing.T) {
 		t.Parallel()
 
-		var gotAvatarURL *string
 		userSvc := &fakeWebhookUserService{
-			FindUserByClerkUserIDFn: func(ctx context.Context, clerkUserID string) (*model.User, error) {
-				return &model.User{ID: "u1", ClerkUserID: clerkUserID}, nil
+			SyncUserFromClerkFn: func(ctx context.Context, clerkUser service.ClerkUserInfo, opts service.ClerkSyncOptions) (*model.User, error) {
+				return nil, service.ErrUserNotFound
 			},
-			UpdateUserFromClerkFn: func(ctx context.Context, userID string, avatarURL *string) error {
-				gotAvatarURL = avatarURL
+		}
+		completed := false
+		eventRepo := &testutil.FakeClerkWebhookEventRepository{
+			MarkProcessedFn: func(ctx context.Context, svixID, lockToken string, at time.Time) error {
+				completed = true
 				return nil
 			},
 		}
 
-		r := newWebhookHandlerRouter(t, userSvc)
-		body := mustMarshalJSON(t, map[string]any{
-			"type": "user.updated",
-			"data": map[string]any{
-				"id":        "user_123",
-				"image_url": "",
-			},
-		})
-		rw := testutil.PerformRequest(r, http.MethodPost, "/api/v1/clerk/webhook", body, nil)
+		r := newWebhookHandlerRouterWithEvents(t, userSvc, eventRepo)
+		rw := performSignedWebhook(t, r, updatedBody(t, 1700000000000))
 		if rw.Code != http.StatusOK {
 			t.Fatalf("expected 200, got %d", rw.Code)
 		}
+		if !completed {
+			t.Fatalf("expected skipped event to be completed")
+		}
+	})
 
-		if gotAvatarURL != nil {
-			t.Errorf("avatarURL = %v, want nil", gotAvatarURL)
+	t.Run("ペイロードの保存失敗: 500", func(t *testing.T) {
+		t.Parallel()
+
+		eventRepo := &testutil.FakeClerkWebhookEventRepository{
+			SaveUserPayloadFn: func(ctx context.Context, p *model.ClerkUserPayload) (bool, error) {
+				return false, errors.New("db error")
+			},
+		}
+
+		r := newWebhookHandlerRouterWithEvents(t, &fakeWebhookUserService{}, eventRepo)
+		rw := performSignedWebhook(t, r, updatedBody(t, 1700000000000))
+		if rw.Code != http.StatusInternalServerError {
+			t.Fatalf("expected 500, got %d", rw.Code)
 		}
 	})
 }
@@ -471,7 +817,7 @@ func TestClerkWebhookHandler_UserDeleted(t *testing.T) {
 			"type": "user.deleted",
 			"data": "not an object",
 		})
-		rw := testutil.PerformRequest(r, http.MethodPost, "/api/v1/clerk/webhook", body, nil)
+		rw := performSignedWebhook(t, r, body)
 		if rw.Code != http.StatusBadRequest {
 			t.Fatalf("expected 400, got %d", rw.Code)
 		}
@@ -483,34 +829,12 @@ func TestClerkWebhookHandler_UserDeleted(t *testing.T) {
 		}
 	})
 
-	t.Run("ユーザーが存在しない(ErrUserNotFound): 200", func(t *testing.T) {
+	t.Run("DeactivateClerkUser失敗: 500", func(t *testing.T) {
 		t.Parallel()
 
 		userSvc := &fakeWebhookUserService{
-			FindUserByClerkUserIDFn: func(ctx context.Context, clerkUserID string) (*model.User, error) {
-				return nil, service.ErrUserNotFound
-			},
-		}
-
-		r := newWebhookHandlerRouter(t, userSvc)
-		body := mustMarshalJSON(t, map[string]any{
-			"type": "user.deleted",
-			"data": map[string]any{
-				"id": "user_nonexistent",
-			},
-		})
-		rw := testutil.PerformRequest(r, http.MethodPost, "/api/v1/clerk/webhook", body, nil)
-		if rw.Code != http.StatusOK {
-			t.Fatalf("expected 200, got %d", rw.Code)
-		}
-	})
-
-	t.Run("FindUserByClerkUserID失敗(DBエラー): 500", func(t *testing.T) {
-		t.Parallel()
-
-		userSvc := &fakeWebhookUserService{
-			FindUserByClerkUserIDFn: func(ctx context.Context, clerkUserID string) (*model.User, error) {
-				return nil, errors.New("db error")
+			DeactivateClerkUserFn: func(ctx context.Context, clerkUserID string, event *service.ClerkWebhookClaim) error {
+				return errors.New("deactivation failed")
 			},
 		}
 
@@ -521,27 +845,28 @@ func TestClerkWebhookHandler_UserDeleted(t *testing.T) {
 				"id": "user_123",
 			},
 		})
-		rw := testutil.PerformRequest(r, http.MethodPost, "/api/v1/clerk/webhook", body, nil)
+		rw := performSignedWebhook(t, r, body)
 		if rw.Code != http.StatusInternalServerError {
 			t.Fatalf("expected 500, got %d", rw.Code)
 		}
 
 		resp := map[string]any{}
 		testutil.MustUnmarshalJSON(t, rw.Body.Bytes(), &resp)
-		if resp["error"] != "failed to resolve user by clerk user id" {
+		if resp["error"] != "failed to deactivate user" {
 			t.Fatalf("unexpected error: %v", resp["error"])
 		}
 	})
 
-	t.Run("DeactivateUser失敗: 500", func(t *testing.T) {
+	t.Run("成功: 200", func(t *testing.T) {
 		t.Parallel()
 
+		var gotClerkUserID string
+		var gotEvent *service.ClerkWebhookClaim
 		userSvc := &fakeWebhookUserService{
-			FindUserByClerkUserIDFn: func(ctx context.Context, clerkUserID string) (*model.User, error) {
-				return &model.User{ID: "u1", ClerkUserID: clerkUserID}, nil
-			},
-			DeactivateUserFn: func(ctx context.Context, userID string) error {
-				return errors.New("deactivation failed")
+			DeactivateClerkUserFn: func(ctx context.Context, clerkUserID string, event *service.ClerkWebhookClaim) error {
+				gotClerkUserID = clerkUserID
+				gotEvent = event
+				return nil
 			},
 		}
 
@@ -552,51 +877,75 @@ func TestClerkWebhookHandler_UserDeleted(t *testing.T) {
 				"id": "user_123",
 			},
 		})
-		rw := testutil.PerformRequest(r, http.MethodPost, "/api/v1/clerk/webhook", body, nil)
-		if rw.Code != http.StatusInternalServerError {
-			t.Fatalf("expected 500, got %d", rw.Code)
+		rw := performSignedWebhook(t, r, body)
+		if rw.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d", rw.Code)
 		}
 
-		resp := map[string]any{}
-		testutil.MustUnmarshalJSON(t, rw.Body.Bytes(), &resp)
-		if resp["error"] != "failed to deactivate user" {
-			t.Fatalf("unexpected error: %v", resp["error"])
+		if gotClerkUserID != "user_123" {
+			t.Errorf("clerkUserID = %q, want %q", gotClerkUserID, "user_123")
+		}
+		if gotEvent == nil || gotEvent.SvixID != "msg_test" {
+			t.Errorf("event = %+v, want claim for %q", gotEvent, "msg_test")
 		}
 	})
+}
 
-	t.Run("成功: 200", func(t *testing.T) {
+func TestClerkWebhookHandler_ResyncUser(t *testing.T) {
+	t.Parallel()
+
+	newResyncRouter := func(t *testing.T, userSvc service.UserService, eventRepo repository.ClerkWebhookEventRepository) *gin.Engine {
+		t.Helper()
+		r := testutil.NewTestRouter()
+		logger := testutil.NewTestLogger()
+		h := NewClerkWebhookHandler(logger, userSvc,
+			service.NewClerkWebhookService(logger, eventRepo, service.ClerkWebhookConfig{Secrets: []string{testClerkWebhookSecret}}))
+		r.POST("/api/v1/admin/clerk/users/:clerkUserId/resync", h.ResyncUser)
+		return r
+	}
+
+	t.Run("ペイロードが存在しない: 404", func(t *testing.T) {
 		t.Parallel()
 
-		var gotUserID string
-		var gotClerkUserID string
-		userSvc := &fakeWebhookUserService{
-			FindUserByClerkUserIDFn: func(ctx context.Context, clerkUserID string) (*model.User, error) {
-				gotClerkUserID = clerkUserID
-				return &model.User{ID: "u1", ClerkUserID: clerkUserID}, nil
+		r := newResyncRouter(t, &fakeWebhookUserService{}, &testutil.FakeClerkWebhookEventRepository{})
+		rw := testutil.PerformRequest(r, http.MethodPost, "/api/v1/admin/clerk/users/user_123/resync", nil, nil)
+		if rw.Code != http.StatusNotFound {
+			t.Fatalf("expected 404, got %d", rw.Code)
+		}
+	})
+
+	t.Run("成功: 保存済みのペイロードで同期する", func(t *testing.T) {
+		t.Parallel()
+
+		eventRepo := &testutil.FakeClerkWebhookEventRepository{
+			FindUserPayloadFn: func(ctx context.Context, clerkUserID string) (*model.ClerkUserPayload, error) {
+				return &model.ClerkUserPayload{
+					ClerkUserID: clerkUserID,
+					Payload:     []byte(`{"id":"user_123","username":"john","first_name":"John","email_addresses":[{"id":"idn_1","email_address":"john@example.com"}],"primary_email_address_id":"idn_1"}`),
+				}, nil
 			},
-			DeactivateUserFn: func(ctx context.Context, userID string) error {
-				gotUserID = userID
-				return nil
+		}
+		var gotClerkUser service.ClerkUserInfo
+		userSvc := &fakeWebhookUserService{
+			SyncUserFromClerkFn: func(ctx context.Context, clerkUser service.ClerkUserInfo, opts service.ClerkSyncOptions) (*model.User, error) {
+				gotClerkUser = clerkUser
+				return &model.User{ID: "u1", DisplayID: "john", DisplayName: "John"}, nil
 			},
 		}
 
-		r := newWebhookHandlerRouter(t, userSvc)
-		body := mustMarshalJSON(t, map[string]any{
-			"type": "user.deleted",
-			"data": map[string]any{
-				"id": "user_123",
-			},
-		})
-		rw := testutil.PerformRequest(r, http.MethodPost, "/api/v1/clerk/webhook", body, nil)
+		r := newResyncRouter(t, userSvc, eventRepo)
+		rw := testutil.PerformRequest(r, http.MethodPost, "/api/v1/admin/clerk/users/user_123/resync", nil, nil)
 		if rw.Code != http.StatusOK {
 			t.Fatalf("expected 200, got %d", rw.Code)
 		}
 
-		if gotClerkUserID != "user_123" {
-			t.Errorf("clerkUserID = %q, want %q", gotClerkUserID, "user_123")
+		if gotClerkUser.ID != "user_123" || gotClerkUser.Email != "john@example.com" || gotClerkUser.Username != "john" {
+			t.Errorf("unexpected clerk user: %+v", gotClerkUser)
 		}
-		if gotUserID != "u1" {
-			t.Errorf("userID = %q, want %q", gotUserID, "u1")
+		resp := map[string]any{}
+		testutil.MustUnmarshalJSON(t, rw.Body.Bytes(), &resp)
+		if resp["id"] != "u1" || resp["display_id"] != "john" {
+			t.Errorf("unexpected response: %v", resp)
 		}
 	})
 }
@@ -0,0 +1,145 @@
+package handler
+
+import (
+	"bytes"
+	"errors"
+	"html/template"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"cinetag-backend/src/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// 配信停止リンクを開いたときに表示する確認ページ。
+// リンクのプリフェッチやメールのセキュリティスキャンで配信が停止されないよう、停止はボタンの POST でのみ行う。
+var unsubscribeConfirmPage = template.Must(template.New("unsubscribe").Parse(
+	`<!DOCTYPE html><html lang="ja"><head><meta charset="UTF-8"><title>cinetag</title></head>` +
+		`<body><p>メールダイジェストの配信を停止しますか？</p>` +
+		`<form method="post" action="?token={{.}}"><button type="submit">配信を停止する</button></form></body></html>`))
+
+// 配信停止の結果として表示するページ。
+const (
+	unsubscribeSucceededPage = `<!DOCTYPE html><html lang="ja"><head><meta charset="UTF-8"><title>cinetag</title></head>` +
+		`<body><p>メールダイジェストの配信を停止しました。</p><p>配信の再開は設定画面から行えます。</p></body></html>`
+	unsubscribeInvalidPage = `<!DOCTYPE html><html lang="ja"><head><meta charset="UTF-8"><title>cinetag</title></head>` +
+		`<body><p>配信停止リンクが無効です。</p><p>お手数ですが、設定画面から配信を停止してください。</p></body></html>`
+	unsubscribeFailedPage = `<!DOCTYPE html><html lang="ja"><head><meta charset="UTF-8"><title>cinetag</title></head>` +
+		`<body><p>配信停止に失敗しました。時間をおいて再度お試しください。</p></body></html>`
+)
+
+// メールダイジェスト関連の HTTP ハンドラー。
+type EmailDigestHandler struct {
+	logger             *slog.Logger
+	emailDigestService service.EmailDigestService
+}
+
+// EmailDigestHandler を初期化して返す。
+func NewEmailDigestHandler(logger *slog.Logger, emailDigestService service.EmailDigestService) *EmailDigestHandler {
+	return &EmailDigestHandler{
+		logger:             logger,
+		emailDigestService: emailDigestService,
+	}
+}
+
+// GetEmailDigestSettings はメールダイジェストの配信設定を取得する。
+// GET /api/v1/me/email-digest
+func (h *EmailDigestHandler) GetEmailDigestSettings(c *gin.Context) {
+	user := getUserFromContext(c)
+	if user == nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	settings, err := h.emailDigestService.GetSettings(c.Request.Context(), user.ID)
+	if err != nil {
+		h.logger.Error("handler.GetEmailDigestSettings failed",
+			slog.String("user_id", user.ID),
+			slog.Any("error", err),
+		)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to get email digest settings"})
+		return
+	}
+
+	c.JSON(http.StatusOK, settings)
+}
+
+// メールダイジェスト設定更新のリクエストボディ。
+type updateEmailDigestSettingsRequest struct {
+	Frequency string `json:"frequency" binding:"required"`
+}
+
+// UpdateEmailDigestSettings はメールダイジェストの配信頻度を更新する。
+// PATCH /api/v1/me/email-digest
+func (h *EmailDigestHandler) UpdateEmailDigestSettings(c *gin.Context) {
+	user := getUserFromContext(c)
+	if user == nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	var req updateEmailDigestSettingsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body"})
+		return
+	}
+
+	settings, err := h.emailDigestService.UpdateSettings(c.Request.Context(), user.ID, strings.TrimSpace(req.Frequency))
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidEmailDigestFrequency) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		h.logger.Error("handler.UpdateEmailDigestSettings failed",
+			slog.String("user_id", user.ID),
+			slog.Any("error", err),
+		)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to update email digest settings"})
+		return
+	}
+
+	c.JSON(http.StatusOK, settings)
+}
+
+// ConfirmUnsubscribe はメール内の配信停止リンクを開いたときに確認ページを表示する（認証不要）。
+// 配信設定は変更しない（停止は確認ページのボタンから POST で行う）。
+// GET /api/v1/email/unsubscribe?token={token}
+func (h *EmailDigestHandler) ConfirmUnsubscribe(c *gin.Context) {
+	token := c.Query("token")
+	if err := h.emailDigestService.ValidateUnsubscribeToken(c.Request.Context(), token); err != nil {
+		c.Data(http.StatusBadRequest, "text/html; charset=utf-8", []byte(unsubscribeInvalidPage))
+		return
+	}
+
+	var page bytes.Buffer
+	if err := unsubscribeConfirmPage.Execute(&page, token); err != nil {
+		h.logger.Error("handler.ConfirmUnsubscribe failed",
+			slog.Any("error", err),
+		)
+		c.Data(http.StatusInternalServerError, "text/html; charset=utf-8", []byte(unsubscribeFailedPage))
+		return
+	}
+	c.Data(http.StatusOK, "text/html; charset=utf-8", page.Bytes())
+}
+
+// Unsubscribe はダイジェストの配信を停止する（認証不要）。
+// 確認ページのボタンと、RFC 8058 のワンクリック配信停止（List-Unsubscribe-Post）に対応する。
+// POST /api/v1/email/unsubscribe?token={token}
+func (h *EmailDigestHandler) Unsubscribe(c *gin.Context) {
+	err := h.emailDigestService.Unsubscribe(c.Request.Context(), c.Query("token"))
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidUnsubscribeToken) {
+			c.Data(http.StatusBadRequest, "text/html; charset=utf-8", []byte(unsubscribeInvalidPage))
+			return
+		}
+		h.logger.Error("handler.Unsubscribe failed",
+			slog.Any("error", err),
+		)
+		c.Data(http.StatusInternalServerError, "text/html; charset=utf-8", []byte(unsubscribeFailedPage))
+		return
+	}
+
+	c.Data(http.StatusOK, "text/html; charset=utf-8", []byte(unsubscribeSucceededPage))
+}
@@ -0,0 +1,216 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"cinetag-backend/src/internal/model"
+	"cinetag-backend/src/internal/service"
+	"cinetag-backend/src/internal/testutil"
+
+	"github.com/gin-gonic/gin"
+)
+
+type fakeEmailDigestService struct {
+	GetSettingsFn    func(ctx context.Context, userID string) (*service.EmailDigestSettings, error)
+	UpdateSettingsFn func(ctx context.Context, userID, frequency string) (*service.EmailDigestSettings, error)
+	ValidateTokenFn  func(ctx context.Context, token string) error
+	UnsubscribeFn    func(ctx context.Context, token string) error
+}
+
+func (f *fakeEmailDigestService) GetSettings(ctx context.Context, userID string) (*service.EmailDigestSettings, error) {
+	if f.GetSettingsFn == nil {
+		return &service.EmailDigestSettings{Frequency: model.EmailDigestFrequencyOff}, nil
+	}
+	return f.GetSettingsFn(ctx, userID)
+}
+
+func (f *fakeEmailDigestService) UpdateSettings(ctx context.Context, userID, frequency string) (*service.EmailDigestSettings, error) {
+	if f.UpdateSettingsFn == nil {
+		return &service.EmailDigestSettings{Frequency: frequency}, nil
+	}
+	return f.UpdateSettingsFn(ctx, userID, frequency)
+}
+
+func (f *fakeEmailDigestService) ValidateUnsubscribeToken(ctx context.Context, token string) error {
+	if f.ValidateTokenFn == nil {
+		return nil
+	}
+	return f.ValidateTokenFn(ctx, token)
+}
+
+func (f *fakeEmailDigestService) Unsubscribe(ctx context.Context, token string) error {
+	if f.UnsubscribeFn == nil {
+		return nil
+	}
+	return f.UnsubscribeFn(ctx, token)
+}
+
+func (f *fakeEmailDigestService) SendDueDigests(ctx context.Context, now time.Time, batchSize int) (*service.EmailDigestRunResult, error) {
+	return &service.EmailDigestRunResult{}, nil
+}
+
+func newEmailDigestHandlerRouter(t *testing.T, svc service.EmailDigestService, user *model.User) *gin.Engine {
+	t.Helper()
+	r := testutil.NewTestRouter()
+	h := NewEmailDigestHandler(testutil.NewTestLogger(), svc)
+
+	api := r.Group("/api/v1")
+	api.GET("/email/unsubscribe", h.ConfirmUnsubscribe)
+	api.POST("/email/unsubscribe", h.Unsubscribe)
+
+	auth := api.Group("/")
+	if user != nil {
+		auth.Use(func(c *gin.Context) {
+			c.Set("user", user)
+			c.Next()
+		})
+	}
+	auth.GET("/me/email-digest", h.GetEmailDigestSettings)
+	auth.PATCH("/me/email-digest", h.UpdateEmailDigestSettings)
+
+	return r
+}
+
+func TestEmailDigestHandler_Settings(t *testing.T) {
+	t.Parallel()
+
+	t.Run("未認証は401", func(t *testing.T) {
+		t.Parallel()
+
+		r := newEmailDigestHandlerRouter(t, &fakeEmailDigestService{}, nil)
+		for _, method := range []string{http.MethodGet, http.MethodPatch} {
+			rr := testutil.PerformRequest(r, method, "/api/v1/me/email-digest", []byte(`{"frequency":"daily"}`), nil)
+			if rr.Code != http.StatusUnauthorized {
+				t.Fatalf("%s: expected 401, got %d", method, rr.Code)
+			}
+		}
+	})
+
+	t.Run("設定を返す", func(t *testing.T) {
+		t.Parallel()
+
+		r := newEmailDigestHandlerRouter(t, &fakeEmailDigestService{}, &model.User{ID: "u1"})
+		rr := testutil.PerformRequest(r, http.MethodGet, "/api/v1/me/email-digest", nil, nil)
+		if rr.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d: %s", rr.Code, rr.Body.String())
+		}
+		var body map[string]any
+		testutil.MustUnmarshalJSON(t, rr.Body.Bytes(), &body)
+		if body["frequency"] != "off" {
+			t.Fatalf("unexpected body: %v", body)
+		}
+	})
+
+	t.Run("頻度を更新する", func(t *testing.T) {
+		t.Parallel()
+
+		var got string
+		svc := &fakeEmailDigestService{
+			UpdateSettingsFn: func(ctx context.Context, userID, frequency string) (*service.EmailDigestSettings, error) {
+				got = frequency
+				return &service.EmailDigestSettings{Frequency: frequency}, nil
+			},
+		}
+		r := newEmailDigestHandlerRouter(t, svc, &model.User{ID: "u1"})
+		rr := testutil.PerformRequest(r, http.MethodPatch, "/api/v1/me/email-digest", []byte(`{"frequency":"weekly"}`),
+			map[string]string{"Content-Type": "application/json"})
+		if rr.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d: %s", rr.Code, rr.Body.String())
+		}
+		if got != "weekly" {
+			t.Fatalf("unexpected frequency: %q", got)
+		}
+	})
+
+	t.Run("不正なリクエストは400", func(t *testing.T) {
+		t.Parallel()
+
+		svc := &fakeEmailDigestService{
+			UpdateSettingsFn: func(ctx context.Context, userID, frequency string) (*service.EmailDigestSettings, error) {
+				return nil, service.ErrInvalidEmailDigestFrequency
+			},
+		}
+		r := newEmailDigestHandlerRouter(t, svc, &model.User{ID: "u1"})
+		for _, body := range []string{`{`, `{}`, `{"frequency":"monthly"}`} {
+			rr := testutil.PerformRequest(r, http.MethodPatch, "/api/v1/me/email-digest", []byte(body),
+				map[string]string{"Content-Type": "application/json"})
+			if rr.Code != http.StatusBadRequest {
+				t.Fatalf("%s: expected 400, got %d", body, rr.Code)
+			}
+		}
+	})
+}
+
+func TestEmailDigestHandler_ConfirmUnsubscribe(t *testing.T) {
+	t.Parallel()
+
+	t.Run("確認ページを表示し、配信は停止しない", func(t *testing.T) {
+		t.Parallel()
+
+		svc := &fakeEmailDigestService{
+			UnsubscribeFn: func(ctx context.Context, token string) error {
+				t.Fatal("unexpected Unsubscribe call")
+				return nil
+			},
+		}
+		r := newEmailDigestHandlerRouter(t, svc, nil)
+		rr := testutil.PerformRequest(r, http.MethodGet, "/api/v1/email/unsubscribe?token=u1.123.sig", nil, nil)
+		if rr.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d", rr.Code)
+		}
+		if body := rr.Body.String(); !strings.Contains(body, `method="post"`) || !strings.Contains(body, "token=u1.123.sig") {
+			t.Fatalf("expected a confirmation form, got %s", body)
+		}
+	})
+
+	t.Run("不正なトークンは400", func(t *testing.T) {
+		t.Parallel()
+
+		svc := &fakeEmailDigestService{
+			ValidateTokenFn: func(ctx context.Context, token string) error {
+				return service.ErrInvalidUnsubscribeToken
+			},
+		}
+		r := newEmailDigestHandlerRouter(t, svc, nil)
+		rr := testutil.PerformRequest(r, http.MethodGet, "/api/v1/email/unsubscribe?token=broken", nil, nil)
+		if rr.Code != http.StatusBadRequest {
+			t.Fatalf("expected 400, got %d", rr.Code)
+		}
+	})
+}
+
+func TestEmailDigestHandler_Unsubscribe(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{name: "配信を停止する", err: nil, want: http.StatusOK},
+		{name: "不正なトークンは400", err: service.ErrInvalidUnsubscribeToken, want: http.StatusBadRequest},
+		{name: "その他のエラーは500", err: errors.New("db down"), want: http.StatusInternalServerError},
+	}
+	for _, tc := range cases {
+		var gotToken string
+		svc := &fakeEmailDigestService{
+			UnsubscribeFn: func(ctx context.Context, token string) error {
+				gotToken = token
+				return tc.err
+			},
+		}
+		r := newEmailDigestHandlerRouter(t, svc, nil)
+		rr := testutil.PerformRequest(r, http.MethodPost, "/api/v1/email/unsubscribe?token=u1.123.sig", nil, nil)
+		if rr.Code != tc.want {
+			t.Fatalf("%s: expected %d, got %d", tc.name, tc.want, rr.Code)
+		}
+		if gotToken != "u1.123.sig" {
+			t.Fatalf("%s: unexpected token %q", tc.name, gotToken)
+		}
+	}
+}
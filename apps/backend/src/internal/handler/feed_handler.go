@@ -0,0 +1,61 @@
+package handler
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"cinetag-backend/src/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ホームフィード関連の HTTP ハンドラー。
+type FeedHandler struct {
+	logger      *slog.Logger
+	feedService service.FeedService
+}
+
+// FeedHandler を初期化して返す。
+func NewFeedHandler(logger *slog.Logger, feedService service.FeedService) *FeedHandler {
+	return &FeedHandler{
+		logger:      logger,
+		feedService: feedService,
+	}
+}
+
+// ListMyFeed はログインユーザーのホームフィードを返す。
+// GET /api/v1/feed/me?cursor={cursor}&limit={limit}
+func (h *FeedHandler) ListMyFeed(c *gin.Context) {
+	user := getUserFromContext(c)
+	if user == nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	cursor := strings.TrimSpace(c.Query("cursor"))
+	limit := parseIntDefault(c.Query("limit"), 20)
+	if limit < 1 || limit > 50 {
+		limit = 20
+	}
+
+	items, next, err := h.feedService.ListFeed(c.Request.Context(), user.ID, cursor, limit)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidFeedCursor) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		h.logger.Error("handler.ListMyFeed failed",
+			slog.String("user_id", user.ID),
+			slog.Any("error", err),
+		)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to list feed"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"items":       items,
+		"next_cursor": next,
+	})
+}
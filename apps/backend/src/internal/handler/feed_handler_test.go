@@ -0,0 +1,189 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"cinetag-backend/src/internal/model"
+	"cinetag-backend/src/internal/service"
+	"cinetag-backend/src/internal/testutil"
+
+	"github.com/gin-gonic/gin"
+)
+
+type fakeFeedService struct {
+	ListFeedFn         func(ctx context.Context, userID, cursor string, limit int) ([]service.FeedItem, *string, error)
+	ListUserActivityFn func(ctx context.Context, userID, viewerUserID string, page, pageSize int) ([]service.UserActivityItem, int64, error)
+}
+
+func (f *fakeFeedService) ListFeed(ctx context.Context, userID, cursor string, limit int) ([]service.FeedItem, *string, error) {
+	if f.ListFeedFn == nil {
+		return []service.FeedItem{}, nil, nil
+	}
+	return f.ListFeedFn(ctx, userID, cursor, limit)
+}
+
+func (f *fakeFeedService) ListUserActivity(ctx context.Context, userID, viewerUserID string, page, pageSize int) ([]service.UserActivityItem, int64, error) {
+	if f.ListUserActivityFn == nil {
+		return []service.UserActivityItem{}, 0, nil
+	}
+	return f.ListUserActivityFn(ctx, userID, viewerUserID, page, pageSize)
+}
+
+func newFeedHandlerRouter(t *testing.T, svc service.FeedService, user *model.User) *gin.Engine {
+	t.Helper()
+	r := testutil.NewTestRouter()
+	h := NewFeedHandler(testutil.NewTestLogger(), svc)
+
+	api := r.Group("/api/v1")
+	if user != nil {
+		api.Use(func(c *gin.Context) {
+			c.Set("user", user)
+			c.Next()
+		})
+	}
+	api.GET("/feed/me", h.ListMyFeed)
+
+	return r
+}
+
+func TestFeedHandler_ListMyFeed(t *testing.T) {
+	t.Parallel()
+
+	t.Run("未認証: 401", func(t *testing.T) {
+		t.Parallel()
+
+		r := newFeedHandlerRouter(t, &fakeFeedService{}, nil)
+		rw := testutil.PerformRequest(r, http.MethodGet, "/api/v1/feed/me", nil, nil)
+		if rw.Code != http.StatusUnauthorized {
+			t.Fatalf("expected 401, got %d", rw.Code)
+		}
+	})
+
+	t.Run("不正なカーソル: 400", func(t *testing.T) {
+		t.Parallel()
+
+		svc := &fakeFeedService{
+			ListFeedFn: func(ctx context.Context, userID, cursor string, limit int) ([]service.FeedItem, *string, error) {
+				return nil, nil, service.ErrInvalidFeedCursor
+			},
+		}
+		r := newFeedHandlerRouter(t, svc, &model.User{ID: "u1"})
+		rw := testutil.PerformRequest(r, http.MethodGet, "/api/v1/feed/me?cursor=broken", nil, nil)
+		if rw.Code != http.StatusBadRequest {
+			t.Fatalf("expected 400, got %d", rw.Code)
+		}
+	})
+
+	t.Run("成功: 200（カーソルと件数を渡す）", func(t *testing.T) {
+		t.Parallel()
+
+		var gotUserID, gotCursor string
+		var gotLimit int
+		next := "next-cursor"
+		svc := &fakeFeedService{
+			ListFeedFn: func(ctx context.Context, userID, cursor string, limit int) ([]service.FeedItem, *string, error) {
+				gotUserID, gotCursor, gotLimit = userID, cursor, limit
+				return []service.FeedItem{{ID: "e1", Type: model.FeedEventTypeTagCreated, Count: 1, ActorCount: 1}}, &next, nil
+			},
+		}
+		r := newFeedHandlerRouter(t, svc, &model.User{ID: "u1"})
+		rw := testutil.PerformRequest(r, http.MethodGet, "/api/v1/feed/me?cursor=abc&limit=10", nil, nil)
+		if rw.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d: %s", rw.Code, rw.Body.String())
+		}
+		if gotUserID != "u1" || gotCursor != "abc" || gotLimit != 10 {
+			t.Fatalf("unexpected args: user=%s cursor=%s limit=%d", gotUserID, gotCursor, gotLimit)
+		}
+
+		var data map[string]any
+		testutil.MustUnmarshalJSON(t, rw.Body.Bytes(), &data)
+		testutil.AssertJSON(t, data, map[string]any{"next_cursor": "next-cursor"})
+		items, _ := data["items"].([]any)
+		if len(items) != 1 {
+			t.Fatalf("expected 1 item, got %v", data["items"])
+		}
+	})
+}
+
+func TestUserHandler_ListUserActivity(t *testing.T) {
+	t.Parallel()
+
+	newRouter := func(userSvc service.UserService, feedSvc service.FeedService, viewer *model.User) *gin.Engine {
+		r := testutil.NewTestRouter()
+		h := NewUserHandler(testutil.NewTestLogger(), userSvc, &fakeTagService{}, nil, feedSvc)
+		if viewer != nil {
+			r.Use(func(c *gin.Context) {
+				c.Set("user", viewer)
+				c.Next()
+			})
+		}
+		r.GET("/api/v1/users/:displayId/activity", h.ListUserActivity)
+		return r
+	}
+
+	t.Run("ユーザーが存在しない: 404", func(t *testing.T) {
+		t.Parallel()
+
+		userSvc := &fakeUserService{
+			GetUserByDisplayIDFn: func(ctx context.Context, displayID string) (*model.User, error) {
+				return nil, service.ErrUserNotFound
+			},
+		}
+		rw := testutil.PerformRequest(newRouter(userSvc, &fakeFeedService{}, nil), http.MethodGet, "/api/v1/users/nobody/activity", nil, nil)
+		if rw.Code != http.StatusNotFound {
+			t.Fatalf("expected 404, got %d", rw.Code)
+		}
+	})
+
+	t.Run("非公開アカウント: 403", func(t *testing.T) {
+		t.Parallel()
+
+		userSvc := &fakeUserService{
+			GetUserByDisplayIDFn: func(ctx context.Context, displayID string) (*model.User, error) {
+				return &model.User{ID: "u2", DisplayID: displayID, IsPrivate: true}, nil
+			},
+			CanViewUserContentFn: func(ctx context.Context, viewerID string, owner *model.User) (bool, error) {
+				return false, nil
+			},
+		}
+		feedSvc := &fakeFeedService{
+			ListUserActivityFn: func(ctx context.Context, userID, viewerUserID string, page, pageSize int) ([]service.UserActivityItem, int64, error) {
+				t.Fatalf("ListUserActivity should not be called")
+				return nil, 0, nil
+			},
+		}
+		rw := testutil.PerformRequest(newRouter(userSvc, feedSvc, nil), http.MethodGet, "/api/v1/users/taro/activity", nil, nil)
+		if rw.Code != http.StatusForbidden {
+			t.Fatalf("expected 403, got %d", rw.Code)
+		}
+	})
+
+	t.Run("成功: 200（閲覧者を渡す）", func(t *testing.T) {
+		t.Parallel()
+
+		userSvc := &fakeUserService{
+			GetUserByDisplayIDFn: func(ctx context.Context, displayID string) (*model.User, error) {
+				return &model.User{ID: "u2", DisplayID: displayID}, nil
+			},
+		}
+		var gotUserID, gotViewerID string
+		feedSvc := &fakeFeedService{
+			ListUserActivityFn: func(ctx context.Context, userID, viewerUserID string, page, pageSize int) ([]service.UserActivityItem, int64, error) {
+				gotUserID, gotViewerID = userID, viewerUserID
+				return []service.UserActivityItem{{ID: "e1", Type: model.FeedEventTypeTagCreated}}, 1, nil
+			},
+		}
+		rw := testutil.PerformRequest(newRouter(userSvc, feedSvc, &model.User{ID: "u1"}), http.MethodGet, "/api/v1/users/taro/activity", nil, nil)
+		if rw.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d: %s", rw.Code, rw.Body.String())
+		}
+		if gotUserID != "u2" || gotViewerID != "u1" {
+			t.Fatalf("unexpected args: user=%s viewer=%s", gotUserID, gotViewerID)
+		}
+		var data map[string]any
+		testutil.MustUnmarshalJSON(t, rw.Body.Bytes(), &data)
+		testutil.AssertListResponse(t, data, 1, 1, 20)
+	})
+}
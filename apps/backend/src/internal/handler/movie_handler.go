@@ -13,14 +13,23 @@ import (
 
 // 映画検索等のHTTPハンドラです。
 type MovieHandler struct {
-	logger       *slog.Logger
-	movieService service.MovieService
+	logger             *slog.Logger
+	movieService       service.MovieService
+	movieStatusService service.MovieStatusService
+	movieRatingService service.MovieRatingService
 }
 
-func NewMovieHandler(logger *slog.Logger, movieService service.MovieService) *MovieHandler {
+func NewMovieHandler(
+	logger *slog.Logger,
+	movieService service.MovieService,
+	movieStatusService service.MovieStatusService,
+	movieRatingService service.MovieRatingService,
+) *MovieHandler {
 	return &MovieHandler{
-		logger:       logger,
-		movieService: movieService,
+		logger:             logger,
+		movieService:       movieService,
+		movieStatusService: movieStatusService,
+		movieRatingService: movieRatingService,
 	}
 }
 
@@ -73,6 +82,30 @@ func (h *MovieHandler) GetMovieDetail(c *gin.Context) {
 		return
 	}
 
+	// cinetag 内の評価の集計を埋め込む（取得に失敗しても詳細自体は返す）
+	if detail != nil && h.movieRatingService != nil {
+		summary, err := h.movieRatingService.GetSummary(c.Request.Context(), tmdbMovieID)
+		if err != nil {
+			h.logger.Warn("handler.GetMovieDetail: failed to get rating summary",
+				"tmdb_movie_id", tmdbMovieID,
+				"error", err,
+			)
+		}
+		detail.CinetagRating = summary
+	}
+
+	// ログイン中の場合は視聴ステータスを埋め込む（取得に失敗しても詳細自体は返す）
+	if user := getUserFromContext(c); user != nil && detail != nil && h.movieStatusService != nil {
+		status, err := h.movieStatusService.GetStatus(c.Request.Context(), user.ID, tmdbMovieID)
+		if err != nil {
+			h.logger.Warn("handler.GetMovieDetail: failed to get viewer status",
+				"tmdb_movie_id", tmdbMovieID,
+				"error", err,
+			)
+		}
+		detail.ViewerStatus = status
+	}
+
 	c.JSON(http.StatusOK, detail)
 }
 
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"net/http"
 	"testing"
+	"time"
 
 	"cinetag-backend/src/internal/model"
 	"cinetag-backend/src/internal/service"
@@ -48,12 +49,16 @@ func (f *fakeMovieService) GetMovieRelatedTags(_ context.Context, _ int, _ int)
 	return nil, nil
 }
 
+func (f *fakeMovieService) RefreshExpiredMovieCaches(_ context.Context, _ time.Time, _ int) (int, error) {
+	return 0, nil
+}
+
 func newMovieHandlerRouter(t *testing.T, movieSvc service.MovieService) *gin.Engine {
 	t.Helper()
 
 	r := testutil.NewTestRouter()
 	logger := testutil.NewTestLogger()
-	h := NewMovieHandler(logger, movieSvc)
+	h := NewMovieHandler(logger, movieSvc, nil, nil)
 
 	api := r.Group("/api/v1")
 	api.GET("/movies/search", h.SearchMovies)
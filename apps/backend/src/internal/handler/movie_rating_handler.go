@@ -0,0 +1,138 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"cinetag-backend/src/internal/model"
+	"cinetag-backend/src/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// 評価登録リクエストのボディ。
+type setMovieRatingRequest struct {
+	Rating    *float64 `json:"rating" binding:"required"`
+	Review    *string  `json:"review"`
+	IsSpoiler bool     `json:"is_spoiler"`
+}
+
+// 評価を登録します（既に登録済みの場合は上書き）。
+// PUT /api/v1/me/movies/:tmdbMovieId/rating
+func (h *MovieHandler) SetMovieRating(c *gin.Context) {
+	user := getUserFromContext(c)
+	if user == nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	tmdbMovieID, err := strconv.Atoi(c.Param("tmdbMovieId"))
+	if err != nil || tmdbMovieID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid tmdb_movie_id"})
+		return
+	}
+
+	var req setMovieRatingRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body"})
+		return
+	}
+
+	item, err := h.movieRatingService.SetRating(c.Request.Context(), user.ID, tmdbMovieID, service.SetMovieRatingInput{
+		Rating:    *req.Rating,
+		Review:    req.Review,
+		IsSpoiler: req.IsSpoiler,
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrInvalidRating), errors.Is(err, service.ErrReviewTooLong):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		default:
+			h.logger.Error("handler.SetMovieRating failed",
+				"user_id", user.ID,
+				"tmdb_movie_id", tmdbMovieID,
+				"error", err,
+			)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to set movie rating"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, item)
+}
+
+// 評価を削除します。
+// DELETE /api/v1/me/movies/:tmdbMovieId/rating
+func (h *MovieHandler) DeleteMovieRating(c *gin.Context) {
+	user := getUserFromContext(c)
+	if user == nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	tmdbMovieID, err := strconv.Atoi(c.Param("tmdbMovieId"))
+	if err != nil || tmdbMovieID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid tmdb_movie_id"})
+		return
+	}
+
+	if err := h.movieRatingService.DeleteRating(c.Request.Context(), user.ID, tmdbMovieID); err != nil {
+		if errors.Is(err, service.ErrMovieRatingNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "movie rating not found"})
+			return
+		}
+		h.logger.Error("handler.DeleteMovieRating failed",
+			"user_id", user.ID,
+			"tmdb_movie_id", tmdbMovieID,
+			"error", err,
+		)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to delete movie rating"})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// 指定映画の評価一覧を返します。
+// GET /api/v1/movies/:tmdbMovieId/ratings?page={page}&page_size={page_size}
+func (h *MovieHandler) ListMovieRatings(c *gin.Context) {
+	tmdbMovieID, err := strconv.Atoi(c.Param("tmdbMovieId"))
+	if err != nil || tmdbMovieID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid tmdb_movie_id"})
+		return
+	}
+
+	page := parseIntDefault(c.Query("page"), 1)
+	pageSize := parseIntDefault(c.Query("page_size"), 20)
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 || pageSize > 100 {
+		pageSize = 20
+	}
+
+	var viewerUserID *string
+	if viewerRaw, exists := c.Get("user"); exists {
+		if viewer, ok := viewerRaw.(*model.User); ok && viewer != nil {
+			viewerUserID = &viewer.ID
+		}
+	}
+
+	items, total, err := h.movieRatingService.ListMovieRatings(c.Request.Context(), tmdbMovieID, viewerUserID, page, pageSize)
+	if err != nil {
+		h.logger.Error("handler.ListMovieRatings failed",
+			"tmdb_movie_id", tmdbMovieID,
+			"error", err,
+		)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to list movie ratings"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"items":       items,
+		"page":        page,
+		"page_size":   pageSize,
+		"total_count": total,
+	})
+}
@@ -0,0 +1,246 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"cinetag-backend/src/internal/model"
+	"cinetag-backend/src/internal/service"
+	"cinetag-backend/src/internal/testutil"
+
+	"github.com/gin-gonic/gin"
+)
+
+type fakeMovieRatingService struct {
+	SetRatingFn        func(ctx context.Context, userID string, tmdbMovieID int, in service.SetMovieRatingInput) (*service.MovieRatingItem, error)
+	DeleteRatingFn     func(ctx context.Context, userID string, tmdbMovieID int) error
+	ListUserRatingsFn  func(ctx context.Context, userID string, page, pageSize int) ([]service.MovieRatingItem, int64, error)
+	ListMovieRatingsFn func(ctx context.Context, tmdbMovieID int, viewerUserID *string, page, pageSize int) ([]service.MovieRatingItem, int64, error)
+	GetSummaryFn       func(ctx context.Context, tmdbMovieID int) (*service.MovieRatingSummary, error)
+}
+
+func (f *fakeMovieRatingService) SetRating(ctx context.Context, userID string, tmdbMovieID int, in service.SetMovieRatingInput) (*service.MovieRatingItem, error) {
+	if f.SetRatingFn == nil {
+		return &service.MovieRatingItem{TmdbMovieID: tmdbMovieID, Rating: in.Rating}, nil
+	}
+	return f.SetRatingFn(ctx, userID, tmdbMovieID, in)
+}
+
+func (f *fakeMovieRatingService) DeleteRating(ctx context.Context, userID string, tmdbMovieID int) error {
+	if f.DeleteRatingFn == nil {
+		return nil
+	}
+	return f.DeleteRatingFn(ctx, userID, tmdbMovieID)
+}
+
+func (f *fakeMovieRatingService) ListUserRatings(ctx context.Context, userID string, page, pageSize int) ([]service.MovieRatingItem, int64, error) {
+	if f.ListUserRatingsFn == nil {
+		return []service.MovieRatingItem{}, 0, nil
+	}
+	return f.ListUserRatingsFn(ctx, userID, page, pageSize)
+}
+
+func (f *fakeMovieRatingService) ListMovieRatings(ctx context.Context, tmdbMovieID int, viewerUserID *string, page, pageSize int) ([]service.MovieRatingItem, int64, error) {
+	if f.ListMovieRatingsFn == nil {
+		return []service.MovieRatingItem{}, 0, nil
+	}
+	return f.ListMovieRatingsFn(ctx, tmdbMovieID, viewerUserID, page, pageSize)
+}
+
+func (f *fakeMovieRatingService) GetSummary(ctx context.Context, tmdbMovieID int) (*service.MovieRatingSummary, error) {
+	if f.GetSummaryFn == nil {
+		return &service.MovieRatingSummary{}, nil
+	}
+	return f.GetSummaryFn(ctx, tmdbMovieID)
+}
+
+func newMovieRatingHandlerRouter(t *testing.T, svc service.MovieRatingService, user *model.User) *gin.Engine {
+	t.Helper()
+
+	r := testutil.NewTestRouter()
+	h := NewMovieHandler(testutil.NewTestLogger(), &fakeMovieService{}, nil, svc)
+
+	api := r.Group("/api/v1")
+	if user != nil {
+		api.Use(func(c *gin.Context) {
+			c.Set("user", user)
+			c.Next()
+		})
+	}
+	api.PUT("/me/movies/:tmdbMovieId/rating", h.SetMovieRating)
+	api.DELETE("/me/movies/:tmdbMovieId/rating", h.DeleteMovieRating)
+	api.GET("/movies/:tmdbMovieId/ratings", h.ListMovieRatings)
+
+	return r
+}
+
+func TestMovieHandler_SetMovieRating(t *testing.T) {
+	t.Parallel()
+
+	t.Run("未認証: 401", func(t *testing.T) {
+		t.Parallel()
+
+		r := newMovieRatingHandlerRouter(t, &fakeMovieRatingService{}, nil)
+		rw := testutil.PerformRequest(r, http.MethodPut, "/api/v1/me/movies/100/rating", []byte(`{"rating":4}`), nil)
+		if rw.Code != http.StatusUnauthorized {
+			t.Fatalf("expected 401, got %d", rw.Code)
+		}
+	})
+
+	t.Run("rating が未指定: 400", func(t *testing.T) {
+		t.Parallel()
+
+		r := newMovieRatingHandlerRouter(t, &fakeMovieRatingService{}, &model.User{ID: "u1"})
+		rw := testutil.PerformRequest(r, http.MethodPut, "/api/v1/me/movies/100/rating", []byte(`{"review":"良かった"}`), nil)
+		if rw.Code != http.StatusBadRequest {
+			t.Fatalf("expected 400, got %d", rw.Code)
+		}
+	})
+
+	t.Run("不正な評価: 400", func(t *testing.T) {
+		t.Parallel()
+
+		svc := &fakeMovieRatingService{
+			SetRatingFn: func(ctx context.Context, userID string, tmdbMovieID int, in service.SetMovieRatingInput) (*service.MovieRatingItem, error) {
+				return nil, service.ErrInvalidRating
+			},
+		}
+		r := newMovieRatingHandlerRouter(t, svc, &model.User{ID: "u1"})
+		rw := testutil.PerformRequest(r, http.MethodPut, "/api/v1/me/movies/100/rating", []byte(`{"rating":7}`), nil)
+		if rw.Code != http.StatusBadRequest {
+			t.Fatalf("expected 400, got %d", rw.Code)
+		}
+	})
+
+	t.Run("成功: 200", func(t *testing.T) {
+		t.Parallel()
+
+		var gotIn service.SetMovieRatingInput
+		svc := &fakeMovieRatingService{
+			SetRatingFn: func(ctx context.Context, userID string, tmdbMovieID int, in service.SetMovieRatingInput) (*service.MovieRatingItem, error) {
+				gotIn = in
+				return &service.MovieRatingItem{ID: "r1", TmdbMovieID: tmdbMovieID, Rating: in.Rating, Review: in.Review, IsSpoiler: in.IsSpoiler}, nil
+			},
+		}
+		r := newMovieRatingHandlerRouter(t, svc, &model.User{ID: "u1"})
+		rw := testutil.PerformRequest(r, http.MethodPut, "/api/v1/me/movies/100/rating", []byte(`{"rating":3.5,"review":"結末に驚いた","is_spoiler":true}`), nil)
+		if rw.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d: %s", rw.Code, rw.Body.String())
+		}
+		if gotIn.Rating != 3.5 || gotIn.Review == nil || !gotIn.IsSpoiler {
+			t.Fatalf("unexpected input: %+v", gotIn)
+		}
+		var data map[string]any
+		testutil.MustUnmarshalJSON(t, rw.Body.Bytes(), &data)
+		testutil.AssertJSON(t, data, map[string]any{
+			"tmdb_movie_id": float64(100),
+			"rating":        3.5,
+			"is_spoiler":    true,
+		})
+	})
+}
+
+func TestMovieHandler_DeleteMovieRating(t *testing.T) {
+	t.Parallel()
+
+	t.Run("未登録: 404", func(t *testing.T) {
+		t.Parallel()
+
+		svc := &fakeMovieRatingService{
+			DeleteRatingFn: func(ctx context.Context, userID string, tmdbMovieID int) error {
+				return service.ErrMovieRatingNotFound
+			},
+		}
+		r := newMovieRatingHandlerRouter(t, svc, &model.User{ID: "u1"})
+		rw := testutil.PerformRequest(r, http.MethodDelete, "/api/v1/me/movies/100/rating", nil, nil)
+		if rw.Code != http.StatusNotFound {
+			t.Fatalf("expected 404, got %d", rw.Code)
+		}
+	})
+}
+
+func TestMovieHandler_ListMovieRatings(t *testing.T) {
+	t.Parallel()
+
+	t.Run("ログイン中: ビューアーIDを渡す", func(t *testing.T) {
+		t.Parallel()
+
+		var gotViewer *string
+		svc := &fakeMovieRatingService{
+			ListMovieRatingsFn: func(ctx context.Context, tmdbMovieID int, viewerUserID *string, page, pageSize int) ([]service.MovieRatingItem, int64, error) {
+				gotViewer = viewerUserID
+				return []service.MovieRatingItem{{ID: "r1", TmdbMovieID: tmdbMovieID, Rating: 4}}, 1, nil
+			},
+		}
+		r := newMovieRatingHandlerRouter(t, svc, &model.User{ID: "u1"})
+		rw := testutil.PerformRequest(r, http.MethodGet, "/api/v1/movies/100/ratings", nil, nil)
+		if rw.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d", rw.Code)
+		}
+		if gotViewer == nil || *gotViewer != "u1" {
+			t.Fatalf("unexpected viewer: %v", gotViewer)
+		}
+		var data map[string]any
+		testutil.MustUnmarshalJSON(t, rw.Body.Bytes(), &data)
+		testutil.AssertListResponse(t, data, 1, 1, 20)
+	})
+}
+
+func TestUserHandler_ListUserRatings(t *testing.T) {
+	t.Parallel()
+
+	newRouter := func(userSvc service.UserService, ratingSvc service.MovieRatingService) *gin.Engine {
+		r := testutil.NewTestRouter()
+		h := NewUserHandler(testutil.NewTestLogger(), userSvc, &fakeTagService{}, ratingSvc, nil)
+		r.GET("/api/v1/users/:displayId/ratings", h.ListUserRatings)
+		return r
+	}
+
+	t.Run("非公開アカウント: 403", func(t *testing.T) {
+		t.Parallel()
+
+		userSvc := &fakeUserService{
+			GetUserByDisplayIDFn: func(ctx context.Context, displayID string) (*model.User, error) {
+				return &model.User{ID: "u2", DisplayID: displayID, IsPrivate: true}, nil
+			},
+			CanViewUserContentFn: func(ctx context.Context, viewerID string, owner *model.User) (bool, error) {
+				return false, nil
+			},
+		}
+		ratingSvc := &fakeMovieRatingService{
+			ListUserRatingsFn: func(ctx context.Context, userID string, page, pageSize int) ([]service.MovieRatingItem, int64, error) {
+				t.Fatalf("ListUserRatings should not be called")
+				return nil, 0, nil
+			},
+		}
+		rw := testutil.PerformRequest(newRouter(userSvc, ratingSvc), http.MethodGet, "/api/v1/users/taro/ratings", nil, nil)
+		if rw.Code != http.StatusForbidden {
+			t.Fatalf("expected 403, got %d", rw.Code)
+		}
+	})
+
+	t.Run("成功: 200", func(t *testing.T) {
+		t.Parallel()
+
+		userSvc := &fakeUserService{
+			GetUserByDisplayIDFn: func(ctx context.Context, displayID string) (*model.User, error) {
+				return &model.User{ID: "u2", DisplayID: displayID}, nil
+			},
+		}
+		var gotUserID string
+		ratingSvc := &fakeMovieRatingService{
+			ListUserRatingsFn: func(ctx context.Context, userID string, page, pageSize int) ([]service.MovieRatingItem, int64, error) {
+				gotUserID = userID
+				return []service.MovieRatingItem{{ID: "r1", TmdbMovieID: 100, Rating: 5}}, 1, nil
+			},
+		}
+		rw := testutil.PerformRequest(newRouter(userSvc, ratingSvc), http.MethodGet, "/api/v1/users/taro/ratings", nil, nil)
+		if rw.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d", rw.Code)
+		}
+		if gotUserID != "u2" {
+			t.Fatalf("unexpected user id: %s", gotUserID)
+		}
+	})
+}
@@ -0,0 +1,154 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"cinetag-backend/src/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// 視聴ステータス設定リクエストのボディ。
+type setMovieStatusRequest struct {
+	Status    string  `json:"status" binding:"required"`
+	WatchedAt *string `json:"watched_at"` // YYYY-MM-DD
+}
+
+// 視聴ステータス設定レスポンス。
+type movieStatusResponse struct {
+	TmdbMovieID int `json:"tmdb_movie_id"`
+	service.MovieWatchStatusRef
+}
+
+// 視聴ステータスを設定します（既に設定済みの場合は上書き）。
+// PUT /api/v1/me/movies/:tmdbMovieId/status
+func (h *MovieHandler) SetMovieStatus(c *gin.Context) {
+	user := getUserFromContext(c)
+	if user == nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	tmdbMovieID, err := strconv.Atoi(c.Param("tmdbMovieId"))
+	if err != nil || tmdbMovieID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid tmdb_movie_id"})
+		return
+	}
+
+	var req setMovieStatusRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body"})
+		return
+	}
+
+	var watchedAt *time.Time
+	if req.WatchedAt != nil && strings.TrimSpace(*req.WatchedAt) != "" {
+		t, err := time.Parse("2006-01-02", strings.TrimSpace(*req.WatchedAt))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "watched_at must be in YYYY-MM-DD format"})
+			return
+		}
+		watchedAt = &t
+	}
+
+	status, err := h.movieStatusService.SetStatus(c.Request.Context(), user.ID, tmdbMovieID, service.SetMovieStatusInput{
+		Status:    strings.TrimSpace(req.Status),
+		WatchedAt: watchedAt,
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrInvalidMovieStatus), errors.Is(err, service.ErrInvalidWatchedAt):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		default:
+			h.logger.Error("handler.SetMovieStatus failed",
+				"user_id", user.ID,
+				"tmdb_movie_id", tmdbMovieID,
+				"error", err,
+			)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to set movie status"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, movieStatusResponse{
+		TmdbMovieID:         tmdbMovieID,
+		MovieWatchStatusRef: *status,
+	})
+}
+
+// 視聴ステータスを解除します。
+// DELETE /api/v1/me/movies/:tmdbMovieId/status
+func (h *MovieHandler) ClearMovieStatus(c *gin.Context) {
+	user := getUserFromContext(c)
+	if user == nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	tmdbMovieID, err := strconv.Atoi(c.Param("tmdbMovieId"))
+	if err != nil || tmdbMovieID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid tmdb_movie_id"})
+		return
+	}
+
+	if err := h.movieStatusService.ClearStatus(c.Request.Context(), user.ID, tmdbMovieID); err != nil {
+		if errors.Is(err, service.ErrMovieStatusNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "movie status not found"})
+			return
+		}
+		h.logger.Error("handler.ClearMovieStatus failed",
+			"user_id", user.ID,
+			"tmdb_movie_id", tmdbMovieID,
+			"error", err,
+		)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to clear movie status"})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// ログインユーザーの視聴ステータス一覧を返します。
+// GET /api/v1/me/movie-statuses?status={status}&page={page}&page_size={page_size}
+func (h *MovieHandler) ListMyMovieStatuses(c *gin.Context) {
+	user := getUserFromContext(c)
+	if user == nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	status := strings.TrimSpace(c.Query("status"))
+	page := parseIntDefault(c.Query("page"), 1)
+	pageSize := parseIntDefault(c.Query("page_size"), 20)
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 || pageSize > 100 {
+		pageSize = 20
+	}
+
+	items, total, err := h.movieStatusService.ListStatuses(c.Request.Context(), user.ID, status, page, pageSize)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidMovieStatus) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		h.logger.Error("handler.ListMyMovieStatuses failed",
+			"user_id", user.ID,
+			"error", err,
+		)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to list movie statuses"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"items":       items,
+		"page":        page,
+		"page_size":   pageSize,
+		"total_count": total,
+	})
+}
@@ -0,0 +1,192 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"cinetag-backend/src/internal/model"
+	"cinetag-backend/src/internal/service"
+	"cinetag-backend/src/internal/testutil"
+
+	"github.com/gin-gonic/gin"
+)
+
+type fakeMovieStatusService struct {
+	SetStatusFn    func(ctx context.Context, userID string, tmdbMovieID int, in service.SetMovieStatusInput) (*service.MovieWatchStatusRef, error)
+	ClearStatusFn  func(ctx context.Context, userID string, tmdbMovieID int) error
+	GetStatusFn    func(ctx context.Context, userID string, tmdbMovieID int) (*service.MovieWatchStatusRef, error)
+	ListStatusesFn func(ctx context.Context, userID, status string, page, pageSize int) ([]service.MovieStatusItem, int64, error)
+}
+
+func (f *fakeMovieStatusService) SetStatus(ctx context.Context, userID string, tmdbMovieID int, in service.SetMovieStatusInput) (*service.MovieWatchStatusRef, error) {
+	if f.SetStatusFn == nil {
+		return &service.MovieWatchStatusRef{Status: in.Status}, nil
+	}
+	return f.SetStatusFn(ctx, userID, tmdbMovieID, in)
+}
+
+func (f *fakeMovieStatusService) ClearStatus(ctx context.Context, userID string, tmdbMovieID int) error {
+	if f.ClearStatusFn == nil {
+		return nil
+	}
+	return f.ClearStatusFn(ctx, userID, tmdbMovieID)
+}
+
+func (f *fakeMovieStatusService) GetStatus(ctx context.Context, userID string, tmdbMovieID int) (*service.MovieWatchStatusRef, error) {
+	if f.GetStatusFn == nil {
+		return nil, nil
+	}
+	return f.GetStatusFn(ctx, userID, tmdbMovieID)
+}
+
+func (f *fakeMovieStatusService) ListStatuses(ctx context.Context, userID, status string, page, pageSize int) ([]service.MovieStatusItem, int64, error) {
+	if f.ListStatusesFn == nil {
+		return []service.MovieStatusItem{}, 0, nil
+	}
+	return f.ListStatusesFn(ctx, userID, status, page, pageSize)
+}
+
+func newMovieStatusHandlerRouter(t *testing.T, svc service.MovieStatusService, user *model.User) *gin.Engine {
+	t.Helper()
+
+	r := testutil.NewTestRouter()
+	h := NewMovieHandler(testutil.NewTestLogger(), &fakeMovieService{}, svc, nil)
+
+	auth := r.Group("/api/v1")
+	if user != nil {
+		auth.Use(func(c *gin.Context) {
+			c.Set("user", user)
+			c.Next()
+		})
+	}
+	auth.PUT("/me/movies/:tmdbMovieId/status", h.SetMovieStatus)
+	auth.DELETE("/me/movies/:tmdbMovieId/status", h.ClearMovieStatus)
+	auth.GET("/me/movie-statuses", h.ListMyMovieStatuses)
+
+	return r
+}
+
+func TestMovieHandler_SetMovieStatus(t *testing.T) {
+	t.Parallel()
+
+	t.Run("未認証: 401", func(t *testing.T) {
+		t.Parallel()
+
+		r := newMovieStatusHandlerRouter(t, &fakeMovieStatusService{}, nil)
+		rw := testutil.PerformRequest(r, http.MethodPut, "/api/v1/me/movies/100/status", []byte(`{"status":"watched"}`), nil)
+		if rw.Code != http.StatusUnauthorized {
+			t.Fatalf("expected 401, got %d", rw.Code)
+		}
+	})
+
+	t.Run("視聴日の形式が不正: 400", func(t *testing.T) {
+		t.Parallel()
+
+		r := newMovieStatusHandlerRouter(t, &fakeMovieStatusService{}, &model.User{ID: "u1"})
+		rw := testutil.PerformRequest(r, http.MethodPut, "/api/v1/me/movies/100/status", []byte(`{"status":"watched","watched_at":"2024/01/01"}`), nil)
+		if rw.Code != http.StatusBadRequest {
+			t.Fatalf("expected 400, got %d", rw.Code)
+		}
+	})
+
+	t.Run("不正なステータス: 400", func(t *testing.T) {
+		t.Parallel()
+
+		svc := &fakeMovieStatusService{
+			SetStatusFn: func(ctx context.Context, userID string, tmdbMovieID int, in service.SetMovieStatusInput) (*service.MovieWatchStatusRef, error) {
+				return nil, service.ErrInvalidMovieStatus
+			},
+		}
+		r := newMovieStatusHandlerRouter(t, svc, &model.User{ID: "u1"})
+		rw := testutil.PerformRequest(r, http.MethodPut, "/api/v1/me/movies/100/status", []byte(`{"status":"loved"}`), nil)
+		if rw.Code != http.StatusBadRequest {
+			t.Fatalf("expected 400, got %d", rw.Code)
+		}
+	})
+
+	t.Run("成功: 200", func(t *testing.T) {
+		t.Parallel()
+
+		var gotIn service.SetMovieStatusInput
+		svc := &fakeMovieStatusService{
+			SetStatusFn: func(ctx context.Context, userID string, tmdbMovieID int, in service.SetMovieStatusInput) (*service.MovieWatchStatusRef, error) {
+				gotIn = in
+				d := "2024-01-02"
+				return &service.MovieWatchStatusRef{Status: in.Status, WatchedAt: &d}, nil
+			},
+		}
+		r := newMovieStatusHandlerRouter(t, svc, &model.User{ID: "u1"})
+		rw := testutil.PerformRequest(r, http.MethodPut, "/api/v1/me/movies/100/status", []byte(`{"status":"watched","watched_at":"2024-01-02"}`), nil)
+		if rw.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d: %s", rw.Code, rw.Body.String())
+		}
+		if gotIn.WatchedAt == nil || !gotIn.WatchedAt.Equal(time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)) {
+			t.Fatalf("unexpected watched_at: %v", gotIn.WatchedAt)
+		}
+		var data map[string]any
+		testutil.MustUnmarshalJSON(t, rw.Body.Bytes(), &data)
+		testutil.AssertJSON(t, data, map[string]any{
+			"tmdb_movie_id": float64(100),
+			"status":        "watched",
+			"watched_at":    "2024-01-02",
+		})
+	})
+}
+
+func TestMovieHandler_ClearMovieStatus(t *testing.T) {
+	t.Parallel()
+
+	t.Run("未設定: 404", func(t *testing.T) {
+		t.Parallel()
+
+		svc := &fakeMovieStatusService{
+			ClearStatusFn: func(ctx context.Context, userID string, tmdbMovieID int) error {
+				return service.ErrMovieStatusNotFound
+			},
+		}
+		r := newMovieStatusHandlerRouter(t, svc, &model.User{ID: "u1"})
+		rw := testutil.PerformRequest(r, http.MethodDelete, "/api/v1/me/movies/100/status", nil, nil)
+		if rw.Code != http.StatusNotFound {
+			t.Fatalf("expected 404, got %d", rw.Code)
+		}
+	})
+
+	t.Run("成功: 204", func(t *testing.T) {
+		t.Parallel()
+
+		r := newMovieStatusHandlerRouter(t, &fakeMovieStatusService{}, &model.User{ID: "u1"})
+		rw := testutil.PerformRequest(r, http.MethodDelete, "/api/v1/me/movies/100/status", nil, nil)
+		if rw.Code != http.StatusNoContent {
+			t.Fatalf("expected 204, got %d", rw.Code)
+		}
+	})
+}
+
+func TestMovieHandler_ListMyMovieStatuses(t *testing.T) {
+	t.Parallel()
+
+	t.Run("成功: ステータスで絞り込む", func(t *testing.T) {
+		t.Parallel()
+
+		var gotStatus string
+		svc := &fakeMovieStatusService{
+			ListStatusesFn: func(ctx context.Context, userID, status string, page, pageSize int) ([]service.MovieStatusItem, int64, error) {
+				gotStatus = status
+				return []service.MovieStatusItem{{TmdbMovieID: 100, Status: status}}, 1, nil
+			},
+		}
+		r := newMovieStatusHandlerRouter(t, svc, &model.User{ID: "u1"})
+		rw := testutil.PerformRequest(r, http.MethodGet, "/api/v1/me/movie-statuses?status=want_to_watch", nil, nil)
+		if rw.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d", rw.Code)
+		}
+		if gotStatus != "want_to_watch" {
+			t.Fatalf("unexpected status: %q", gotStatus)
+		}
+		var data map[string]any
+		testutil.MustUnmarshalJSON(t, rw.Body.Bytes(), &data)
+		testutil.AssertListResponse(t, data, 1, 1, 20)
+	})
+}
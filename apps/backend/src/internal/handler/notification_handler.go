@@ -1,10 +1,16 @@
 package handler
 
 import (
+	"context"
+	"encoding/json"
 	"errors"
+	"fmt"
+	"io"
 	"log/slog"
 	"net/http"
 	"strconv"
+	"sync"
+	"time"
 
 	"cinetag-backend/src/internal/model"
 	"cinetag-backend/src/internal/service"
@@ -12,10 +18,21 @@ import (
 	"github.com/gin-gonic/gin"
 )
 
+// 通知ストリームで接続を維持するためにコメント行を送る間隔。
+// Cloud Run やプロキシのアイドルタイムアウトより短くする。
+const notificationStreamHeartbeatInterval = 25 * time.Second
+
+// 通知ストリームが切断された場合に、クライアントが再接続するまでの待ち時間（ミリ秒）。
+const notificationStreamRetryMillis = 3000
+
 // 通知関連の HTTP ハンドラー。
 type NotificationHandler struct {
 	logger              *slog.Logger
 	notificationService service.NotificationService
+
+	// CloseStreams で閉じ、配信中の通知ストリームをすべて終了させる
+	streamsClosed    chan struct{}
+	closeStreamsOnce sync.Once
 }
 
 // NotificationHandler を初期化して返す。
@@ -23,9 +40,19 @@ func NewNotificationHandler(logger *slog.Logger, notificationService service.Not
 	return &NotificationHandler{
 		logger:              logger,
 		notificationService: notificationService,
+		streamsClosed:       make(chan struct{}),
 	}
 }
 
+// CloseStreams は配信中の通知ストリームをすべて終了させ、以降の接続もすぐに終了させる。
+// サーバーの停止時（http.Server.RegisterOnShutdown）に呼び、長時間の接続の終了を待たずに停止できるようにする。
+// 切断されたクライアントは retry の待ち時間の後に再接続する。
+func (h *NotificationHandler) CloseStreams() {
+	h.closeStreamsOnce.Do(func() {
+		close(h.streamsClosed)
+	})
+}
+
 // コンテキストから認証済みユーザーを取得するヘルパー。
 func getUserFromContext(c *gin.Context) *model.User {
 	userRaw, exists := c.Get("user")
@@ -54,8 +81,9 @@ func (h *NotificationHandler) ListNotifications(c *gin.Context) {
 		pageSize = 50
 	}
 	unreadOnly := c.Query("unread_only") == "true"
+	archived := c.Query("archived") == "true"
 
-	items, total, err := h.notificationService.ListNotifications(c.Request.Context(), user.ID, page, pageSize, unreadOnly)
+	items, total, err := h.notificationService.ListNotifications(c.Request.Context(), user.ID, page, pageSize, unreadOnly, archived)
 	if err != nil {
 		h.logger.Error("handler.ListNotifications failed",
 			slog.Any("error", err),
@@ -147,6 +175,295 @@ func (h *NotificationHandler) MarkAllAsRead(c *gin.Context) {
 	c.Status(http.StatusNoContent)
 }
 
+// ArchiveNotification は指定の通知をアーカイブする。
+// PATCH /api/v1/notifications/:notificationId/archive
+func (h *NotificationHandler) ArchiveNotification(c *gin.Context) {
+	h.setArchived(c, true)
+}
+
+// UnarchiveNotification は指定の通知のアーカイブを解除する。
+// PATCH /api/v1/notifications/:notificationId/unarchive
+func (h *NotificationHandler) UnarchiveNotification(c *gin.Context) {
+	h.setArchived(c, false)
+}
+
+// 指定の通知のアーカイブ状態を切り替える。
+func (h *NotificationHandler) setArchived(c *gin.Context, archived bool) {
+	user := getUserFromContext(c)
+	if user == nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	notificationID := c.Param("notificationId")
+	if notificationID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "notification_id is required"})
+		return
+	}
+
+	var err error
+	if archived {
+		err = h.notificationService.ArchiveNotification(c.Request.Context(), notificationID, user.ID)
+	} else {
+		err = h.notificationService.UnarchiveNotification(c.Request.Context(), notificationID, user.ID)
+	}
+	if err != nil {
+		if errors.Is(err, service.ErrNotificationNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "notification not found"})
+			return
+		}
+		h.logger.Error("handler.setArchived failed",
+			slog.Bool("archived", archived),
+			slog.Any("error", err),
+		)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to update notification"})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// DeleteNotification は指定の通知を削除する。
+// DELETE /api/v1/notifications/:notificationId
+func (h *NotificationHandler) DeleteNotification(c *gin.Context) {
+	user := getUserFromContext(c)
+	if user == nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	notificationID := c.Param("notificationId")
+	if notificationID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "notification_id is required"})
+		return
+	}
+
+	err := h.notificationService.DeleteNotification(c.Request.Context(), notificationID, user.ID)
+	if err != nil {
+		if errors.Is(err, service.ErrNotificationNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "notification not found"})
+			return
+		}
+		h.logger.Error("handler.DeleteNotification failed",
+			slog.Any("error", err),
+		)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to delete notification"})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// DeleteNotifications は既読通知をまとめて削除する。
+// 誤って全件を削除しないよう、削除対象の条件として read=true の指定を必須とする。
+// DELETE /api/v1/notifications?read=true
+func (h *NotificationHandler) DeleteNotifications(c *gin.Context) {
+	user := getUserFromContext(c)
+	if user == nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	if c.Query("read") != "true" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "read=true is required"})
+		return
+	}
+
+	deleted, err := h.notificationService.DeleteReadNotifications(c.Request.Context(), user.ID)
+	if err != nil {
+		h.logger.Error("handler.DeleteNotifications failed",
+			slog.Any("error", err),
+		)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to delete notifications"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"deleted_count": deleted,
+	})
+}
+
+// StreamNotifications は新しい通知と未読数の変化を Server-Sent Events で配信する。
+// Last-Event-ID ヘッダー（または last_event_id クエリ）を指定すると、その時点以降の通知から配信する。
+// GET /api/v1/notifications/stream
+func (h *NotificationHandler) StreamNotifications(c *gin.Context) {
+	user := getUserFromContext(c)
+	if user == nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	lastEventID := c.GetHeader("Last-Event-ID")
+	if lastEventID == "" {
+		lastEventID = c.Query("last_event_id")
+	}
+
+	// 取得と購読の間に発生した変化を取りこぼさないよう、先に購読する
+	signals, unsubscribe := h.notificationService.SubscribeStream(user.ID)
+	defer unsubscribe()
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no")
+	c.Status(http.StatusOK)
+
+	ctx := c.Request.Context()
+	w := c.Writer
+	if _, err := fmt.Fprintf(w, "retry: %d\n\n", notificationStreamRetryMillis); err != nil {
+		return
+	}
+
+	cursor, err := h.sendNotificationUpdates(ctx, w, user.ID, lastEventID)
+	if err != nil {
+		h.logStreamError(ctx, err)
+		return
+	}
+	w.Flush()
+
+	heartbeat := time.NewTicker(notificationStreamHeartbeatInterval)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-h.streamsClosed:
+			return
+		case _, ok := <-signals:
+			if !ok {
+				return
+			}
+			cursor, err = h.sendNotificationUpdates(ctx, w, user.ID, cursor)
+			if err != nil {
+				h.logStreamError(ctx, err)
+				return
+			}
+		case <-heartbeat.C:
+			if _, err := io.WriteString(w, ": heartbeat\n\n"); err != nil {
+				return
+			}
+		}
+		w.Flush()
+	}
+}
+
+// cursor 以降の通知と現在の未読数を送り、次の再開位置を返す。
+func (h *NotificationHandler) sendNotificationUpdates(ctx context.Context, w io.Writer, userID, cursor string) (string, error) {
+	for {
+		entries, next, err := h.notificationService.ListNotificationsSince(ctx, userID, cursor)
+		if err != nil {
+			return cursor, err
+		}
+		cursor = next
+		if len(entries) == 0 {
+			break
+		}
+		for _, e := range entries {
+			if err := writeServerSentEvent(w, e.EventID, "notification", e.Notification); err != nil {
+				return cursor, err
+			}
+		}
+	}
+
+	count, err := h.notificationService.GetUnreadCount(ctx, userID)
+	if err != nil {
+		return cursor, err
+	}
+	if err := writeServerSentEvent(w, cursor, "unread_count", gin.H{"unread_count": count}); err != nil {
+		return cursor, err
+	}
+	return cursor, nil
+}
+
+// クライアントの切断による失敗以外をログに記録する。
+func (h *NotificationHandler) logStreamError(ctx context.Context, err error) {
+	if ctx.Err() != nil {
+		return
+	}
+	h.logger.Error("handler.StreamNotifications failed",
+		slog.Any("error", err),
+	)
+}
+
+// Server-Sent Events の1イベントを書き込む。data は JSON にして1行で送る。
+func writeServerSentEvent(w io.Writer, id, event string, data any) error {
+	b, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "id: %s\nevent: %s\ndata: %s\n\n", id, event, b)
+	return err
+}
+
+// GetNotificationSettings は通知設定を取得する。
+// GET /api/v1/me/notification-settings
+func (h *NotificationHandler) GetNotificationSettings(c *gin.Context) {
+	user := getUserFromContext(c)
+	if user == nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	settings, err := h.notificationService.GetSettings(c.Request.Context(), user.ID)
+	if err != nil {
+		h.logger.Error("handler.GetNotificationSettings failed",
+			slog.Any("error", err),
+		)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to get notification settings"})
+		return
+	}
+
+	c.JSON(http.StatusOK, settings)
+}
+
+// 通知設定更新のリクエストボディ。
+type updateNotificationSettingsRequest struct {
+	Types        map[string]bool `json:"types"`
+	MuteTagIDs   []string        `json:"mute_tag_ids"`
+	UnmuteTagIDs []string        `json:"unmute_tag_ids"`
+}
+
+// UpdateNotificationSettings は通知設定を更新する。
+// PATCH /api/v1/me/notification-settings
+func (h *NotificationHandler) UpdateNotificationSettings(c *gin.Context) {
+	user := getUserFromContext(c)
+	if user == nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	var req updateNotificationSettingsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body"})
+		return
+	}
+
+	settings, err := h.notificationService.UpdateSettings(c.Request.Context(), user.ID, service.UpdateNotificationSettingsInput{
+		Types:        req.Types,
+		MuteTagIDs:   req.MuteTagIDs,
+		UnmuteTagIDs: req.UnmuteTagIDs,
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrInvalidNotificationType):
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid notification type"})
+		case errors.Is(err, service.ErrInvalidTagID):
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid tag id"})
+		case errors.Is(err, service.ErrTagNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": "tag not found"})
+		default:
+			h.logger.Error("handler.UpdateNotificationSettings failed",
+				slog.Any("error", err),
+			)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to update notification settings"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, settings)
+}
+
 func parseIntDefaultNotif(s string, def int) int {
 	if s == "" {
 		return def
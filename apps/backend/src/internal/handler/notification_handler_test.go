@@ -0,0 +1,594 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"cinetag-backend/src/internal/model"
+	"cinetag-backend/src/internal/service"
+	"cinetag-backend/src/internal/testutil"
+
+	"github.com/gin-gonic/gin"
+)
+
+type fakeNotificationService struct {
+	ListNotificationsFn func(ctx context.Context, userID string, page, pageSize int, unreadOnly, archived bool) ([]*service.NotificationItem, int64, error)
+	GetUnreadCountFn    func(ctx context.Context, userID string) (int64, error)
+	MarkAsReadFn        func(ctx context.Context, notificationID, userID string) error
+	MarkAllAsReadFn     func(ctx context.Context, userID string) error
+	ArchiveFn           func(ctx context.Context, notificationID, userID string) error
+	UnarchiveFn         func(ctx context.Context, notificationID, userID string) error
+	DeleteFn            func(ctx context.Context, notificationID, userID string) error
+	DeleteReadFn        func(ctx context.Context, userID string) (int64, error)
+	GetSettingsFn       func(ctx context.Context, userID string) (*service.NotificationSettings, error)
+	UpdateSettingsFn    func(ctx context.Context, userID string, in service.UpdateNotificationSettingsInput) (*service.NotificationSettings, error)
+	SubscribeStreamFn   func(userID string) (<-chan struct{}, func())
+	ListSinceFn         func(ctx context.Context, userID, lastEventID string) ([]service.NotificationStreamEntry, string, error)
+}
+
+func (f *fakeNotificationService) ListNotifications(ctx context.Context, userID string, page, pageSize int, unreadOnly, archived bool) ([]*service.NotificationItem, int64, error) {
+	if f.ListNotificationsFn == nil {
+		return []*service.NotificationItem{}, 0, nil
+	}
+	return f.ListNotificationsFn(ctx, userID, page, pageSize, unreadOnly, archived)
+}
+
+func (f *fakeNotificationService) GetUnreadCount(ctx context.Context, userID string) (int64, error) {
+	if f.GetUnreadCountFn == nil {
+		return 0, nil
+	}
+	return f.GetUnreadCountFn(ctx, userID)
+}
+
+func (f *fakeNotificationService) MarkAsRead(ctx context.Context, notificationID, userID string) error {
+	if f.MarkAsReadFn == nil {
+		return nil
+	}
+	return f.MarkAsReadFn(ctx, notificationID, userID)
+}
+
+func (f *fakeNotificationService) MarkAllAsRead(ctx context.Context, userID string) error {
+	if f.MarkAllAsReadFn == nil {
+		return nil
+	}
+	return f.MarkAllAsReadFn(ctx, userID)
+}
+
+func (f *fakeNotificationService) ArchiveNotification(ctx context.Context, notificationID, userID string) error {
+	if f.ArchiveFn == nil {
+		return nil
+	}
+	return f.ArchiveFn(ctx, notificationID, userID)
+}
+
+func (f *fakeNotificationService) UnarchiveNotification(ctx context.Context, notificationID, userID string) error {
+	if f.UnarchiveFn == nil {
+		return nil
+	}
+	return f.UnarchiveFn(ctx, notificationID, userID)
+}
+
+func (f *fakeNotificationService) DeleteNotification(ctx context.Context, notificationID, userID string) error {
+	if f.DeleteFn == nil {
+		return nil
+	}
+	return f.DeleteFn(ctx, notificationID, userID)
+}
+
+func (f *fakeNotificationService) DeleteReadNotifications(ctx context.Context, userID string) (int64, error) {
+	if f.DeleteReadFn == nil {
+		return 0, nil
+	}
+	return f.DeleteReadFn(ctx, userID)
+}
+
+func (f *fakeNotificationService) PurgeNotifications(ctx context.Context, policy service.NotificationRetentionPolicy, now time.Time, batchSize int) (*service.NotificationPurgeResult, error) {
+	return &service.NotificationPurgeResult{}, nil
+}
+
+func (f *fakeNotificationService) GetSettings(ctx context.Context, userID string) (*service.NotificationSettings, error) {
+	if f.GetSettingsFn == nil {
+		return &service.NotificationSettings{Types: map[string]bool{}, MutedTags: []service.NotificationMutedTagItem{}}, nil
+	}
+	return f.GetSettingsFn(ctx, userID)
+}
+
+func (f *fakeNotificationService) UpdateSettings(ctx context.Context, userID string, in service.UpdateNotificationSettingsInput) (*service.NotificationSettings, error) {
+	if f.UpdateSettingsFn == nil {
+		return &service.NotificationSettings{Types: map[string]bool{}, MutedTags: []service.NotificationMutedTagItem{}}, nil
+	}
+	return f.UpdateSettingsFn(ctx, userID, in)
+}
+
+func (f *fakeNotificationService) SubscribeStream(userID string) (<-chan struct{}, func()) {
+	if f.SubscribeStreamFn == nil {
+		ch := make(chan struct{})
+		close(ch)
+		return ch, func() {}
+	}
+	return f.SubscribeStreamFn(userID)
+}
+
+func (f *fakeNotificationService) ListNotificationsSince(ctx context.Context, userID, lastEventID string) ([]service.NotificationStreamEntry, string, error) {
+	if f.ListSinceFn == nil {
+		return []service.NotificationStreamEntry{}, lastEventID, nil
+	}
+	return f.ListSinceFn(ctx, userID, lastEventID)
+}
+
+func (f *fakeNotificationService) NotifyTagMovieAdded(ctx context.Context, sourceEventID, tagID, tagMovieID, actorUserID string) error {
+	return nil
+}
+
+func (f *fakeNotificationService) NotifyTagFollowed(ctx context.Context, sourceEventID, tagID, actorUserID string) error {
+	return nil
+}
+
+func (f *fakeNotificationService) NotifyTagLiked(ctx context.Context, sourceEventID, tagID, actorUserID string) error {
+	return nil
+}
+
+func (f *fakeNotificationService) NotifyTagUpdated(ctx context.Context, sourceEventID, tagID, actorUserID string, details model.NotificationTagUpdateDetails) error {
+	return nil
+}
+
+func (f *fakeNotificationService) NotifyMentionedInNote(ctx context.Context, sourceEventID, tagID, tagMovieID, actorUserID string, displayIDs []string) error {
+	return nil
+}
+
+func (f *fakeNotificationService) NotifyUserFollowed(ctx context.Context, sourceEventID, followeeUserID, actorUserID string) error {
+	return nil
+}
+
+func (f *fakeNotificationService) NotifyFollowingUserCreatedTag(ctx context.Context, sourceEventID, tagID, actorUserID string) error {
+	return nil
+}
+
+func (f *fakeNotificationService) NotifyFollowRequested(ctx context.Context, sourceEventID, targetUserID, requesterUserID string) error {
+	return nil
+}
+
+func (f *fakeNotificationService) NotifyFollowRequestApproved(ctx context.Context, sourceEventID, requesterUserID, targetUserID string) error {
+	return nil
+}
+
+func (f *fakeNotificationService) NotifyFollowRequestDenied(ctx context.Context, sourceEventID, requesterUserID, targetUserID string) error {
+	return nil
+}
+
+func newNotificationHandlerRouter(t *testing.T, svc service.NotificationService, user *model.User) *gin.Engine {
+	t.Helper()
+	r := testutil.NewTestRouter()
+	h := NewNotificationHandler(testutil.NewTestLogger(), svc)
+
+	api := r.Group("/api/v1")
+	if user != nil {
+		api.Use(func(c *gin.Context) {
+			c.Set("user", user)
+			c.Next()
+		})
+	}
+	api.GET("/notifications/stream", h.StreamNotifications)
+	api.PATCH("/notifications/:notificationId/archive", h.ArchiveNotification)
+	api.PATCH("/notifications/:notificationId/unarchive", h.UnarchiveNotification)
+	api.DELETE("/notifications/:notificationId", h.DeleteNotification)
+	api.DELETE("/notifications", h.DeleteNotifications)
+	api.GET("/me/notification-settings", h.GetNotificationSettings)
+	api.PATCH("/me/notification-settings", h.UpdateNotificationSettings)
+
+	return r
+}
+
+func TestNotificationHandler_GetNotificationSettings(t *testing.T) {
+	t.Parallel()
+
+	t.Run("未認証は401", func(t *testing.T) {
+		t.Parallel()
+
+		r := newNotificationHandlerRouter(t, &fakeNotificationService{}, nil)
+		rr := testutil.PerformRequest(r, http.MethodGet, "/api/v1/me/notification-settings", nil, nil)
+		if rr.Code != http.StatusUnauthorized {
+			t.Fatalf("expected 401, got %d", rr.Code)
+		}
+	})
+
+	t.Run("設定を返す", func(t *testing.T) {
+		t.Parallel()
+
+		svc := &fakeNotificationService{
+			GetSettingsFn: func(ctx context.Context, userID string) (*service.NotificationSettings, error) {
+				if userID != "u1" {
+					t.Fatalf("unexpected userID: %s", userID)
+				}
+				return &service.NotificationSettings{
+					Types:     map[string]bool{model.NotificationTypeTagFollowed: false},
+					MutedTags: []service.NotificationMutedTagItem{{ID: "tag1", Title: "Tag 1"}},
+				}, nil
+			},
+		}
+		r := newNotificationHandlerRouter(t, svc, &model.User{ID: "u1"})
+		rr := testutil.PerformRequest(r, http.MethodGet, "/api/v1/me/notification-settings", nil, nil)
+		if rr.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d: %s", rr.Code, rr.Body.String())
+		}
+
+		var body struct {
+			Types     map[string]bool `json:"types"`
+			MutedTags []struct {
+				ID string `json:"id"`
+			} `json:"muted_tags"`
+		}
+		testutil.MustUnmarshalJSON(t, rr.Body.Bytes(), &body)
+		if enabled, ok := body.Types[model.NotificationTypeTagFollowed]; !ok || enabled {
+			t.Fatalf("unexpected types: %+v", body.Types)
+		}
+		if len(body.MutedTags) != 1 || body.MutedTags[0].ID != "tag1" {
+			t.Fatalf("unexpected muted_tags: %+v", body.MutedTags)
+		}
+	})
+}
+
+func TestNotificationHandler_UpdateNotificationSettings(t *testing.T) {
+	t.Parallel()
+
+	t.Run("未認証は401", func(t *testing.T) {
+		t.Parallel()
+
+		r := newNotificationHandlerRouter(t, &fakeNotificationService{}, nil)
+		rr := testutil.PerformRequest(r, http.MethodPatch, "/api/v1/me/notification-settings", []byte(`{}`), nil)
+		if rr.Code != http.StatusUnauthorized {
+			t.Fatalf("expected 401, got %d", rr.Code)
+		}
+	})
+
+	t.Run("リクエストをサービスに渡す", func(t *testing.T) {
+		t.Parallel()
+
+		var got service.UpdateNotificationSettingsInput
+		svc := &fakeNotificationService{
+			UpdateSettingsFn: func(ctx context.Context, userID string, in service.UpdateNotificationSettingsInput) (*service.NotificationSettings, error) {
+				got = in
+				return &service.NotificationSettings{Types: map[string]bool{}, MutedTags: []service.NotificationMutedTagItem{}}, nil
+			},
+		}
+		r := newNotificationHandlerRouter(t, svc, &model.User{ID: "u1"})
+		rr := testutil.PerformRequest(r, http.MethodPatch, "/api/v1/me/notification-settings",
+			[]byte(`{"types":{"user_followed":false},"mute_tag_ids":["tag1"],"unmute_tag_ids":["tag2"]}`),
+			map[string]string{"Content-Type": "application/json"})
+		if rr.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d: %s", rr.Code, rr.Body.String())
+		}
+		if enabled, ok := got.Types["user_followed"]; !ok || enabled {
+			t.Fatalf("unexpected types: %+v", got.Types)
+		}
+		if len(got.MuteTagIDs) != 1 || got.MuteTagIDs[0] != "tag1" || len(got.UnmuteTagIDs) != 1 || got.UnmuteTagIDs[0] != "tag2" {
+			t.Fatalf("unexpected input: %+v", got)
+		}
+	})
+
+	t.Run("エラーをステータスコードに変換する", func(t *testing.T) {
+		t.Parallel()
+
+		cases := []struct {
+			name string
+			err  error
+			want int
+		}{
+			{name: "不明な種類は400", err: service.ErrInvalidNotificationType, want: http.StatusBadRequest},
+			{name: "タグが見つからない場合は404", err: service.ErrTagNotFound, want: http.StatusNotFound},
+		}
+		for _, tc := range cases {
+			svc := &fakeNotificationService{
+				UpdateSettingsFn: func(ctx context.Context, userID string, in service.UpdateNotificationSettingsInput) (*service.NotificationSettings, error) {
+					return nil, tc.err
+				},
+			}
+			r := newNotificationHandlerRouter(t, svc, &model.User{ID: "u1"})
+			rr := testutil.PerformRequest(r, http.MethodPatch, "/api/v1/me/notification-settings", []byte(`{}`),
+				map[string]string{"Content-Type": "application/json"})
+			if rr.Code != tc.want {
+				t.Fatalf("%s: expected %d, got %d", tc.name, tc.want, rr.Code)
+			}
+		}
+	})
+
+	t.Run("不正なJSONは400", func(t *testing.T) {
+		t.Parallel()
+
+		r := newNotificationHandlerRouter(t, &fakeNotificationService{}, &model.User{ID: "u1"})
+		rr := testutil.PerformRequest(r, http.MethodPatch, "/api/v1/me/notification-settings", []byte(`{`),
+			map[string]string{"Content-Type": "application/json"})
+		if rr.Code != http.StatusBadRequest {
+			t.Fatalf("expected 400, got %d", rr.Code)
+		}
+	})
+}
+
+func TestNotificationHandler_StreamNotifications(t *testing.T) {
+	t.Parallel()
+
+	t.Run("未認証は401", func(t *testing.T) {
+		t.Parallel()
+
+		r := newNotificationHandlerRouter(t, &fakeNotificationService{}, nil)
+		rr := testutil.PerformRequest(r, http.MethodGet, "/api/v1/notifications/stream", nil, nil)
+		if rr.Code != http.StatusUnauthorized {
+			t.Fatalf("expected 401, got %d", rr.Code)
+		}
+	})
+
+	t.Run("Last-Event-ID 以降の通知と未読数を配信する", func(t *testing.T) {
+		t.Parallel()
+
+		var cursors []string
+		svc := &fakeNotificationService{
+			ListSinceFn: func(ctx context.Context, userID, lastEventID string) ([]service.NotificationStreamEntry, string, error) {
+				cursors = append(cursors, lastEventID)
+				if lastEventID != "c0" {
+					return []service.NotificationStreamEntry{}, lastEventID, nil
+				}
+				return []service.NotificationStreamEntry{
+					{EventID: "c1", Notification: &service.NotificationItem{ID: "n1", NotificationType: model.NotificationTypeTagFollowed}},
+				}, "c1", nil
+			},
+			GetUnreadCountFn: func(ctx context.Context, userID string) (int64, error) {
+				return 3, nil
+			},
+		}
+		r := newNotificationHandlerRouter(t, svc, &model.User{ID: "u1"})
+		rr := testutil.PerformRequest(r, http.MethodGet, "/api/v1/notifications/stream", nil,
+			map[string]string{"Last-Event-ID": "c0"})
+		if rr.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d: %s", rr.Code, rr.Body.String())
+		}
+		if ct := rr.Header().Get("Content-Type"); ct != "text/event-stream" {
+			t.Fatalf("unexpected content type: %s", ct)
+		}
+
+		body := rr.Body.String()
+		for _, want := range []string{
+			"retry: 3000\n\n",
+			"id: c1\nevent: notification\ndata: {\"id\":\"n1\"",
+			"id: c1\nevent: unread_count\ndata: {\"unread_count\":3}\n\n",
+		} {
+			if !strings.Contains(body, want) {
+				t.Fatalf("expected body to contain %q, got %q", want, body)
+			}
+		}
+		if len(cursors) != 2 || cursors[0] != "c0" || cursors[1] != "c1" {
+			t.Fatalf("unexpected cursors: %v", cursors)
+		}
+	})
+
+	t.Run("変化の合図を受けると再送する", func(t *testing.T) {
+		t.Parallel()
+
+		signals := make(chan struct{}, 1)
+		signals <- struct{}{}
+		close(signals)
+		unsubscribed := false
+		counts := []int64{1, 0}
+		svc := &fakeNotificationService{
+			SubscribeStreamFn: func(userID string) (<-chan struct{}, func()) {
+				return signals, func() { unsubscribed = true }
+			},
+			ListSinceFn: func(ctx context.Context, userID, lastEventID string) ([]service.NotificationStreamEntry, string, error) {
+				return []service.NotificationStreamEntry{}, "now", nil
+			},
+			GetUnreadCountFn: func(ctx context.Context, userID string) (int64, error) {
+				count := counts[0]
+				counts = counts[1:]
+				return count, nil
+			},
+		}
+		r := newNotificationHandlerRouter(t, svc, &model.User{ID: "u1"})
+		rr := testutil.PerformRequest(r, http.MethodGet, "/api/v1/notifications/stream", nil, nil)
+		if rr.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d", rr.Code)
+		}
+
+		body := rr.Body.String()
+		first := strings.Index(body, "data: {\"unread_count\":1}")
+		second := strings.Index(body, "data: {\"unread_count\":0}")
+		if first < 0 || second < first {
+			t.Fatalf("expected unread counts 1 then 0, got %q", body)
+		}
+		if !unsubscribed {
+			t.Fatal("expected stream to be unsubscribed")
+		}
+	})
+
+	t.Run("CloseStreams の後は初回の配信後に終了する", func(t *testing.T) {
+		t.Parallel()
+
+		// 合図の来ない購読でも、サーバーの停止時はストリームを終了する
+		svc := &fakeNotificationService{
+			SubscribeStreamFn: func(userID string) (<-chan struct{}, func()) {
+				return make(chan struct{}), func() {}
+			},
+			ListSinceFn: func(ctx context.Context, userID, lastEventID string) ([]service.NotificationStreamEntry, string, error) {
+				return []service.NotificationStreamEntry{}, "now", nil
+			},
+		}
+		h := NewNotificationHandler(testutil.NewTestLogger(), svc)
+		h.CloseStreams()
+		h.CloseStreams() // 複数回呼んでもよい
+
+		r := testutil.NewTestRouter()
+		r.GET("/api/v1/notifications/stream", func(c *gin.Context) {
+			c.Set("user", &model.User{ID: "u1"})
+			h.StreamNotifications(c)
+		})
+		rr := testutil.PerformRequest(r, http.MethodGet, "/api/v1/notifications/stream", nil, nil)
+		if rr.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d", rr.Code)
+		}
+		if !strings.Contains(rr.Body.String(), "event: unread_count") {
+			t.Fatalf("expected initial unread count, got %q", rr.Body.String())
+		}
+	})
+}
+
+func TestNotificationHandler_ArchiveNotification(t *testing.T) {
+	t.Parallel()
+
+	t.Run("未認証は401", func(t *testing.T) {
+		t.Parallel()
+
+		r := newNotificationHandlerRouter(t, &fakeNotificationService{}, nil)
+		rr := testutil.PerformRequest(r, http.MethodPatch, "/api/v1/notifications/n1/archive", nil, nil)
+		if rr.Code != http.StatusUnauthorized {
+			t.Fatalf("expected 401, got %d", rr.Code)
+		}
+	})
+
+	t.Run("アーカイブ・解除をサービスに渡す", func(t *testing.T) {
+		t.Parallel()
+
+		var calls []string
+		svc := &fakeNotificationService{
+			ArchiveFn: func(ctx context.Context, notificationID, userID string) error {
+				calls = append(calls, "archive:"+notificationID+":"+userID)
+				return nil
+			},
+			UnarchiveFn: func(ctx context.Context, notificationID, userID string) error {
+				calls = append(calls, "unarchive:"+notificationID+":"+userID)
+				return nil
+			},
+		}
+		r := newNotificationHandlerRouter(t, svc, &model.User{ID: "u1"})
+		for _, path := range []string{"/api/v1/notifications/n1/archive", "/api/v1/notifications/n1/unarchive"} {
+			rr := testutil.PerformRequest(r, http.MethodPatch, path, nil, nil)
+			if rr.Code != http.StatusNoContent {
+				t.Fatalf("%s: expected 204, got %d", path, rr.Code)
+			}
+		}
+		if len(calls) != 2 || calls[0] != "archive:n1:u1" || calls[1] != "unarchive:n1:u1" {
+			t.Fatalf("unexpected calls: %v", calls)
+		}
+	})
+
+	t.Run("通知が見つからない場合は404", func(t *testing.T) {
+		t.Parallel()
+
+		svc := &fakeNotificationService{
+			ArchiveFn: func(ctx context.Context, notificationID, userID string) error {
+				return service.ErrNotificationNotFound
+			},
+		}
+		r := newNotificationHandlerRouter(t, svc, &model.User{ID: "u1"})
+		rr := testutil.PerformRequest(r, http.MethodPatch, "/api/v1/notifications/n1/archive", nil, nil)
+		if rr.Code != http.StatusNotFound {
+			t.Fatalf("expected 404, got %d", rr.Code)
+		}
+	})
+}
+
+func TestNotificationHandler_DeleteNotification(t *testing.T) {
+	t.Parallel()
+
+	t.Run("未認証は401", func(t *testing.T) {
+		t.Parallel()
+
+		r := newNotificationHandlerRouter(t, &fakeNotificationService{}, nil)
+		rr := testutil.PerformRequest(r, http.MethodDelete, "/api/v1/notifications/n1", nil, nil)
+		if rr.Code != http.StatusUnauthorized {
+			t.Fatalf("expected 401, got %d", rr.Code)
+		}
+	})
+
+	t.Run("削除すると204", func(t *testing.T) {
+		t.Parallel()
+
+		var gotID, gotUserID string
+		svc := &fakeNotificationService{
+			DeleteFn: func(ctx context.Context, notificationID, userID string) error {
+				gotID, gotUserID = notificationID, userID
+				return nil
+			},
+		}
+		r := newNotificationHandlerRouter(t, svc, &model.User{ID: "u1"})
+		rr := testutil.PerformRequest(r, http.MethodDelete, "/api/v1/notifications/n1", nil, nil)
+		if rr.Code != http.StatusNoContent {
+			t.Fatalf("expected 204, got %d", rr.Code)
+		}
+		if gotID != "n1" || gotUserID != "u1" {
+			t.Fatalf("unexpected args: %s %s", gotID, gotUserID)
+		}
+	})
+
+	t.Run("通知が見つからない場合は404", func(t *testing.T) {
+		t.Parallel()
+
+		svc := &fakeNotificationService{
+			DeleteFn: func(ctx context.Context, notificationID, userID string) error {
+				return service.ErrNotificationNotFound
+			},
+		}
+		r := newNotificationHandlerRouter(t, svc, &model.User{ID: "u1"})
+		rr := testutil.PerformRequest(r, http.MethodDelete, "/api/v1/notifications/n1", nil, nil)
+		if rr.Code != http.StatusNotFound {
+			t.Fatalf("expected 404, got %d", rr.Code)
+		}
+	})
+}
+
+func TestNotificationHandler_DeleteNotifications(t *testing.T) {
+	t.Parallel()
+
+	t.Run("未認証は401", func(t *testing.T) {
+		t.Parallel()
+
+		r := newNotificationHandlerRouter(t, &fakeNotificationService{}, nil)
+		rr := testutil.PerformRequest(r, http.MethodDelete, "/api/v1/notifications?read=true", nil, nil)
+		if rr.Code != http.StatusUnauthorized {
+			t.Fatalf("expected 401, got %d", rr.Code)
+		}
+	})
+
+	t.Run("read=true がない場合は400", func(t *testing.T) {
+		t.Parallel()
+
+		svc := &fakeNotificationService{
+			DeleteReadFn: func(ctx context.Context, userID string) (int64, error) {
+				t.Fatal("unexpected call")
+				return 0, nil
+			},
+		}
+		r := newNotificationHandlerRouter(t, svc, &model.User{ID: "u1"})
+		for _, path := range []string{"/api/v1/notifications", "/api/v1/notifications?read=false"} {
+			rr := testutil.PerformRequest(r, http.MethodDelete, path, nil, nil)
+			if rr.Code != http.StatusBadRequest {
+				t.Fatalf("%s: expected 400, got %d", path, rr.Code)
+			}
+		}
+	})
+
+	t.Run("既読通知を削除して件数を返す", func(t *testing.T) {
+		t.Parallel()
+
+		svc := &fakeNotificationService{
+			DeleteReadFn: func(ctx context.Context, userID string) (int64, error) {
+				if userID != "u1" {
+					t.Fatalf("unexpected userID: %s", userID)
+				}
+				return 4, nil
+			},
+		}
+		r := newNotificationHandlerRouter(t, svc, &model.User{ID: "u1"})
+		rr := testutil.PerformRequest(r, http.MethodDelete, "/api/v1/notifications?read=true", nil, nil)
+		if rr.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d: %s", rr.Code, rr.Body.String())
+		}
+		var body struct {
+			DeletedCount int64 `json:"deleted_count"`
+		}
+		testutil.MustUnmarshalJSON(t, rr.Body.Bytes(), &body)
+		if body.DeletedCount != 4 {
+			t.Fatalf("expected deleted_count 4, got %d", body.DeletedCount)
+		}
+	})
+}
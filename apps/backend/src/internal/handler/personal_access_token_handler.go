@@ -0,0 +1,115 @@
+package handler
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"cinetag-backend/src/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// パーソナルアクセストークン関連の HTTP ハンドラー。
+type PersonalAccessTokenHandler struct {
+	logger       *slog.Logger
+	tokenService service.PersonalAccessTokenService
+}
+
+// PersonalAccessTokenHandler を初期化して返す。
+func NewPersonalAccessTokenHandler(logger *slog.Logger, tokenService service.PersonalAccessTokenService) *PersonalAccessTokenHandler {
+	return &PersonalAccessTokenHandler{
+		logger:       logger,
+		tokenService: tokenService,
+	}
+}
+
+// トークン作成のリクエストボディ。
+type createAccessTokenRequest struct {
+	Name          string   `json:"name" binding:"required"`
+	Scopes        []string `json:"scopes" binding:"required"`
+	ExpiresInDays *int     `json:"expires_in_days"`
+}
+
+// CreateAccessToken はパーソナルアクセストークンを発行する。トークンはこのレスポンスでのみ返す。
+// POST /api/v1/me/access-tokens
+func (h *PersonalAccessTokenHandler) CreateAccessToken(c *gin.Context) {
+	user := getUserFromContext(c)
+	if user == nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	var req createAccessTokenRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body"})
+		return
+	}
+
+	created, err := h.tokenService.CreateAccessToken(c.Request.Context(), user.ID, service.CreateAccessTokenInput{
+		Name:          req.Name,
+		Scopes:        req.Scopes,
+		ExpiresInDays: req.ExpiresInDays,
+	})
+	if err != nil {
+		h.writeAccessTokenError(c, "handler.CreateAccessToken", user.ID, err, "failed to create access token")
+		return
+	}
+
+	c.JSON(http.StatusCreated, created)
+}
+
+// ListAccessTokens は自分のパーソナルアクセストークンの一覧を取得する（トークン本体は含まない）。
+// GET /api/v1/me/access-tokens
+func (h *PersonalAccessTokenHandler) ListAccessTokens(c *gin.Context) {
+	user := getUserFromContext(c)
+	if user == nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	tokens, err := h.tokenService.ListAccessTokens(c.Request.Context(), user.ID)
+	if err != nil {
+		h.writeAccessTokenError(c, "handler.ListAccessTokens", user.ID, err, "failed to list access tokens")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"items": tokens})
+}
+
+// RevokeAccessToken はパーソナルアクセストークンを失効する。
+// DELETE /api/v1/me/access-tokens/:tokenId
+func (h *PersonalAccessTokenHandler) RevokeAccessToken(c *gin.Context) {
+	user := getUserFromContext(c)
+	if user == nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	if err := h.tokenService.RevokeAccessToken(c.Request.Context(), c.Param("tokenId"), user.ID); err != nil {
+		h.writeAccessTokenError(c, "handler.RevokeAccessToken", user.ID, err, "failed to revoke access token")
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// パーソナルアクセストークンのサービスのエラーをレスポンスに変換する。想定外のエラーはログに出して 500 を返す。
+func (h *PersonalAccessTokenHandler) writeAccessTokenError(c *gin.Context, op, userID string, err error, failedMessage string) {
+	switch {
+	case errors.Is(err, service.ErrInvalidAccessTokenName),
+		errors.Is(err, service.ErrInvalidAccessTokenScopes),
+		errors.Is(err, service.ErrInvalidAccessTokenExpiry):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrAccessTokenLimitExceeded):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrAccessTokenNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "access token not found"})
+	default:
+		h.logger.Error(op+" failed",
+			slog.String("user_id", userID),
+			slog.Any("error", err),
+		)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": failedMessage})
+	}
+}
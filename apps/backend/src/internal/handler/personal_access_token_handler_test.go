@@ -0,0 +1,216 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"cinetag-backend/src/internal/model"
+	"cinetag-backend/src/internal/service"
+	"cinetag-backend/src/internal/testutil"
+
+	"github.com/gin-gonic/gin"
+)
+
+type fakePersonalAccessTokenService struct {
+	CreateAccessTokenFn func(ctx context.Context, userID string, in service.CreateAccessTokenInput) (*service.CreatedAccessToken, error)
+	ListAccessTokensFn  func(ctx context.Context, userID string) ([]*model.PersonalAccessToken, error)
+	RevokeAccessTokenFn func(ctx context.Context, tokenID, userID string) error
+}
+
+func (f *fakePersonalAccessTokenService) CreateAccessToken(ctx context.Context, userID string, in service.CreateAccessTokenInput) (*service.CreatedAccessToken, error) {
+	if f.CreateAccessTokenFn == nil {
+		return &service.CreatedAccessToken{PersonalAccessToken: &model.PersonalAccessToken{ID: "pat1", UserID: userID}, Token: "cnt_test"}, nil
+	}
+	return f.CreateAccessTokenFn(ctx, userID, in)
+}
+
+func (f *fakePersonalAccessTokenService) ListAccessTokens(ctx context.Context, userID string) ([]*model.PersonalAccessToken, error) {
+	if f.ListAccessTokensFn == nil {
+		return []*model.PersonalAccessToken{}, nil
+	}
+	return f.ListAccessTokensFn(ctx, userID)
+}
+
+func (f *fakePersonalAccessTokenService) RevokeAccessToken(ctx context.Context, tokenID, userID string) error {
+	if f.RevokeAccessTokenFn == nil {
+		return nil
+	}
+	return f.RevokeAccessTokenFn(ctx, tokenID, userID)
+}
+
+func (f *fakePersonalAccessTokenService) Authenticate(ctx context.Context, rawToken string) (*model.PersonalAccessToken, *model.User, error) {
+	return nil, nil, service.ErrInvalidAccessToken
+}
+
+func newPersonalAccessTokenHandlerRouter(t *testing.T, svc service.PersonalAccessTokenService, user *model.User) *gin.Engine {
+	t.Helper()
+	r := testutil.NewTestRouter()
+	h := NewPersonalAccessTokenHandler(testutil.NewTestLogger(), svc)
+
+	auth := r.Group("/api/v1")
+	if user != nil {
+		auth.Use(func(c *gin.Context) {
+			c.Set("user", user)
+			c.Next()
+		})
+	}
+	auth.GET("/me/access-tokens", h.ListAccessTokens)
+	auth.POST("/me/access-tokens", h.CreateAccessToken)
+	auth.DELETE("/me/access-tokens/:tokenId", h.RevokeAccessToken)
+
+	return r
+}
+
+func TestPersonalAccessTokenHandler_CreateAccessToken(t *testing.T) {
+	t.Parallel()
+
+	jsonHeader := map[string]string{"Content-Type": "application/json"}
+
+	t.Run("未認証は401", func(t *testing.T) {
+		t.Parallel()
+
+		r := newPersonalAccessTokenHandlerRouter(t, &fakePersonalAccessTokenService{}, nil)
+		rr := testutil.PerformRequest(r, http.MethodPost, "/api/v1/me/access-tokens", []byte(`{}`), jsonHeader)
+		if rr.Code != http.StatusUnauthorized {
+			t.Fatalf("expected 401, got %d", rr.Code)
+		}
+	})
+
+	t.Run("発行してトークンを返す", func(t *testing.T) {
+		t.Parallel()
+
+		var got service.CreateAccessTokenInput
+		svc := &fakePersonalAccessTokenService{
+			CreateAccessTokenFn: func(ctx context.Context, userID string, in service.CreateAccessTokenInput) (*service.CreatedAccessToken, error) {
+				got = in
+				return &service.CreatedAccessToken{
+					PersonalAccessToken: &model.PersonalAccessToken{ID: "pat1", UserID: userID, Name: in.Name, TokenHash: "hash", TokenPrefix: "cnt_0123abcd", Scopes: in.Scopes},
+					Token:               "cnt_0123abcdef",
+				}, nil
+			},
+		}
+		r := newPersonalAccessTokenHandlerRouter(t, svc, &model.User{ID: "u1"})
+		rr := testutil.PerformRequest(r, http.MethodPost, "/api/v1/me/access-tokens",
+			[]byte(`{"name":"bot","scopes":["tags:write"],"expires_in_days":7}`), jsonHeader)
+		if rr.Code != http.StatusCreated {
+			t.Fatalf("expected 201, got %d: %s", rr.Code, rr.Body.String())
+		}
+		if got.Name != "bot" || len(got.Scopes) != 1 || got.Scopes[0] != "tags:write" || got.ExpiresInDays == nil || *got.ExpiresInDays != 7 {
+			t.Fatalf("unexpected input: %+v", got)
+		}
+		var body map[string]any
+		testutil.MustUnmarshalJSON(t, rr.Body.Bytes(), &body)
+		if body["id"] != "pat1" || body["token"] != "cnt_0123abcdef" || body["token_prefix"] != "cnt_0123abcd" {
+			t.Fatalf("unexpected body: %v", body)
+		}
+		if _, ok := body["token_hash"]; ok {
+			t.Fatalf("token_hash must not be returned: %v", body)
+		}
+	})
+
+	t.Run("エラーをステータスコードに変換する", func(t *testing.T) {
+		t.Parallel()
+
+		cases := []struct {
+			err  error
+			want int
+		}{
+			{err: service.ErrInvalidAccessTokenName, want: http.StatusBadRequest},
+			{err: service.ErrInvalidAccessTokenScopes, want: http.StatusBadRequest},
+			{err: service.ErrInvalidAccessTokenExpiry, want: http.StatusBadRequest},
+			{err: service.ErrAccessTokenLimitExceeded, want: http.StatusConflict},
+		}
+		for _, tc := range cases {
+			svc := &fakePersonalAccessTokenService{
+				CreateAccessTokenFn: func(ctx context.Context, userID string, in service.CreateAccessTokenInput) (*service.CreatedAccessToken, error) {
+					return nil, tc.err
+				},
+			}
+			r := newPersonalAccessTokenHandlerRouter(t, svc, &model.User{ID: "u1"})
+			rr := testutil.PerformRequest(r, http.MethodPost, "/api/v1/me/access-tokens",
+				[]byte(`{"name":"bot","scopes":["tags:write"]}`), jsonHeader)
+			if rr.Code != tc.want {
+				t.Fatalf("%v: expected %d, got %d", tc.err, tc.want, rr.Code)
+			}
+		}
+	})
+
+	t.Run("名前・スコープの指定がない場合は400", func(t *testing.T) {
+		t.Parallel()
+
+		r := newPersonalAccessTokenHandlerRouter(t, &fakePersonalAccessTokenService{}, &model.User{ID: "u1"})
+		for _, body := range []string{`{`, `{"scopes":["tags:write"]}`, `{"name":"bot"}`} {
+			rr := testutil.PerformRequest(r, http.MethodPost, "/api/v1/me/access-tokens", []byte(body), jsonHeader)
+			if rr.Code != http.StatusBadRequest {
+				t.Fatalf("%s: expected 400, got %d", body, rr.Code)
+			}
+		}
+	})
+}
+
+func TestPersonalAccessTokenHandler_ManageAccessTokens(t *testing.T) {
+	t.Parallel()
+
+	t.Run("一覧ではトークン本体とハッシュを返さない", func(t *testing.T) {
+		t.Parallel()
+
+		svc := &fakePersonalAccessTokenService{
+			ListAccessTokensFn: func(ctx context.Context, userID string) ([]*model.PersonalAccessToken, error) {
+				return []*model.PersonalAccessToken{{ID: "pat1", UserID: userID, Name: "bot", TokenHash: "hash", TokenPrefix: "cnt_0123abcd"}}, nil
+			},
+		}
+		r := newPersonalAccessTokenHandlerRouter(t, svc, &model.User{ID: "u1"})
+		rr := testutil.PerformRequest(r, http.MethodGet, "/api/v1/me/access-tokens", nil, nil)
+		if rr.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d", rr.Code)
+		}
+		var body struct {
+			Items []map[string]any `json:"items"`
+		}
+		testutil.MustUnmarshalJSON(t, rr.Body.Bytes(), &body)
+		if len(body.Items) != 1 || body.Items[0]["id"] != "pat1" || body.Items[0]["token_prefix"] != "cnt_0123abcd" {
+			t.Fatalf("unexpected body: %s", rr.Body.String())
+		}
+		for _, key := range []string{"token", "token_hash"} {
+			if _, ok := body.Items[0][key]; ok {
+				t.Fatalf("%s must not be listed: %s", key, rr.Body.String())
+			}
+		}
+	})
+
+	t.Run("失効すると204", func(t *testing.T) {
+		t.Parallel()
+
+		var gotTokenID, gotUserID string
+		svc := &fakePersonalAccessTokenService{
+			RevokeAccessTokenFn: func(ctx context.Context, tokenID, userID string) error {
+				gotTokenID, gotUserID = tokenID, userID
+				return nil
+			},
+		}
+		r := newPersonalAccessTokenHandlerRouter(t, svc, &model.User{ID: "u1"})
+		rr := testutil.PerformRequest(r, http.MethodDelete, "/api/v1/me/access-tokens/pat1", nil, nil)
+		if rr.Code != http.StatusNoContent {
+			t.Fatalf("expected 204, got %d", rr.Code)
+		}
+		if gotTokenID != "pat1" || gotUserID != "u1" {
+			t.Fatalf("unexpected args: token=%s user=%s", gotTokenID, gotUserID)
+		}
+	})
+
+	t.Run("存在しないトークンは404", func(t *testing.T) {
+		t.Parallel()
+
+		svc := &fakePersonalAccessTokenService{
+			RevokeAccessTokenFn: func(ctx context.Context, tokenID, userID string) error {
+				return service.ErrAccessTokenNotFound
+			},
+		}
+		r := newPersonalAccessTokenHandlerRouter(t, svc, &model.User{ID: "u1"})
+		rr := testutil.PerformRequest(r, http.MethodDelete, "/api/v1/me/access-tokens/missing", nil, nil)
+		if rr.Code != http.StatusNotFound {
+			t.Fatalf("expected 404, got %d", rr.Code)
+		}
+	})
+}
@@ -163,6 +163,8 @@ func (h *TagHandler) UpdateTag(c *gin.Context) {
 			c.JSON(http.StatusNotFound, gin.H{"error": "tag not found"})
 		case errors.Is(err, service.ErrTagPermissionDenied):
 			c.JSON(http.StatusForbidden, gin.H{"error": "forbidden"})
+		case errors.Is(err, service.ErrTagHidden):
+			c.JSON(http.StatusForbidden, gin.H{"error": "tag is hidden by an administrator"})
 		default:
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to update tag"})
 		}
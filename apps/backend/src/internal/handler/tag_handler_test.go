@@ -32,6 +32,7 @@ type fakeTagService struct {
 	LikeTagFn            func(ctx context.Context, tagID, userID string) error
 	UnlikeTagFn          func(ctx context.Context, tagID, userID string) error
 	IsLikingTagFn        func(ctx context.Context, tagID, userID string) (bool, error)
+	GetUserTagStatsFn    func(ctx context.Context, userID string, publicOnly bool) (*service.UserTagStats, error)
 }
 
 func (f *fakeTagService) ListPublicTags(ctx context.Context, q, sort string, page, pageSize int) ([]service.TagListItem, int64, error) {
@@ -153,6 +154,13 @@ func (f *fakeTagService) IsLikingTag(ctx context.Context, tagID, userID string)
 	return f.IsLikingTagFn(ctx, tagID, userID)
 }
 
+func (f *fakeTagService) GetUserTagStats(ctx context.Context, userID string, publicOnly bool) (*service.UserTagStats, error) {
+	if f.GetUserTagStatsFn == nil {
+		return &service.UserTagStats{TopGenres: []service.GenreStat{}, TopDecades: []service.DecadeStat{}}, nil
+	}
+	return f.GetUserTagStatsFn(ctx, userID, publicOnly)
+}
+
 // newTagHandlerRouter は TagHandler のテスト用ルーターを生成します。
 func newTagHandlerRouter(t *testing.T, tagSvc service.TagService, user *model.User) *gin.Engine {
 	t.Helper()
@@ -0,0 +1,130 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"cinetag-backend/src/internal/model"
+	"cinetag-backend/src/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// 個人データエクスポート関連の HTTP ハンドラー。
+type UserDataExportHandler struct {
+	logger        *slog.Logger
+	exportService service.UserDataExportService
+}
+
+// UserDataExportHandler を初期化して返す。
+func NewUserDataExportHandler(logger *slog.Logger, exportService service.UserDataExportService) *UserDataExportHandler {
+	return &UserDataExportHandler{
+		logger:        logger,
+		exportService: exportService,
+	}
+}
+
+// エクスポート要求リクエストの形式。
+type RequestExportRequest struct {
+	Format string `json:"format"`
+}
+
+// エクスポートの状態を表すレスポンス形式。
+type UserDataExportResponse struct {
+	ID        string    `json:"id"`
+	Format    string    `json:"format"`
+	Status    string    `json:"status"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// RequestExport は個人データのエクスポートを要求する。
+// POST /api/v1/me/export
+func (h *UserDataExportHandler) RequestExport(c *gin.Context) {
+	user := getUserFromContext(c)
+	if user == nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	var req RequestExportRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body"})
+			return
+		}
+	}
+
+	export, err := h.exportService.RequestExport(c.Request.Context(), user.ID, req.Format)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidExportFormat) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		h.logger.Error("handler.RequestExport failed",
+			slog.String("user_id", user.ID),
+			slog.Any("error", err),
+		)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to request export"})
+		return
+	}
+
+	c.JSON(http.StatusAccepted, toUserDataExportResponse(export))
+}
+
+// DownloadExport は生成済みのエクスポートファイルをダウンロードする。
+// 生成中の場合は 202 で状態を返す。
+// GET /api/v1/me/export/:exportId
+func (h *UserDataExportHandler) DownloadExport(c *gin.Context) {
+	user := getUserFromContext(c)
+	if user == nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	export, err := h.exportService.GetExport(c.Request.Context(), user.ID, c.Param("exportId"))
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrExportNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": "export not found"})
+		case errors.Is(err, service.ErrExportExpired):
+			c.JSON(http.StatusGone, gin.H{"error": "export has expired"})
+		default:
+			h.logger.Error("handler.DownloadExport failed",
+				slog.String("user_id", user.ID),
+				slog.Any("error", err),
+			)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to get export"})
+		}
+		return
+	}
+
+	switch export.Status {
+	case model.UserDataExportStatusCompleted:
+		contentType := "application/zip"
+		if export.Format == model.UserDataExportFormatJSON {
+			contentType = "application/json"
+		}
+		fileName := "cinetag-export." + export.Format
+		if export.FileName != nil {
+			fileName = *export.FileName
+		}
+		c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, fileName))
+		c.Data(http.StatusOK, contentType, export.Content)
+	case model.UserDataExportStatusFailed:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "export failed"})
+	default:
+		c.JSON(http.StatusAccepted, toUserDataExportResponse(export))
+	}
+}
+
+func toUserDataExportResponse(export *model.UserDataExport) UserDataExportResponse {
+	return UserDataExportResponse{
+		ID:        export.ID,
+		Format:    export.Format,
+		Status:    export.Status,
+		CreatedAt: export.CreatedAt,
+	}
+}
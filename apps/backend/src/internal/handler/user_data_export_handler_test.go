@@ -0,0 +1,170 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"cinetag-backend/src/internal/model"
+	"cinetag-backend/src/internal/service"
+	"cinetag-backend/src/internal/testutil"
+
+	"github.com/gin-gonic/gin"
+)
+
+type fakeUserDataExportService struct {
+	RequestExportFn func(ctx context.Context, userID, format string) (*model.UserDataExport, error)
+	GetExportFn     func(ctx context.Context, userID, exportID string) (*model.UserDataExport, error)
+}
+
+func (f *fakeUserDataExportService) RequestExport(ctx context.Context, userID, format string) (*model.UserDataExport, error) {
+	if f.RequestExportFn == nil {
+		return &model.UserDataExport{ID: "e1", UserID: userID, Format: format, Status: model.UserDataExportStatusPending}, nil
+	}
+	return f.RequestExportFn(ctx, userID, format)
+}
+
+func (f *fakeUserDataExportService) GenerateExport(ctx context.Context, exportID string) error {
+	return nil
+}
+
+func (f *fakeUserDataExportService) GetExport(ctx context.Context, userID, exportID string) (*model.UserDataExport, error) {
+	if f.GetExportFn == nil {
+		return nil, service.ErrExportNotFound
+	}
+	return f.GetExportFn(ctx, userID, exportID)
+}
+
+func newUserDataExportHandlerRouter(t *testing.T, svc service.UserDataExportService, user *model.User) *gin.Engine {
+	t.Helper()
+
+	r := testutil.NewTestRouter()
+	h := NewUserDataExportHandler(testutil.NewTestLogger(), svc)
+
+	auth := r.Group("/api/v1")
+	if user != nil {
+		auth.Use(func(c *gin.Context) {
+			c.Set("user", user)
+			c.Next()
+		})
+	}
+	auth.POST("/me/export", h.RequestExport)
+	auth.GET("/me/export/:exportId", h.DownloadExport)
+
+	return r
+}
+
+func TestUserDataExportHandler_RequestExport(t *testing.T) {
+	t.Parallel()
+
+	t.Run("未認証: 401", func(t *testing.T) {
+		t.Parallel()
+
+		r := newUserDataExportHandlerRouter(t, &fakeUserDataExportService{}, nil)
+		rw := testutil.PerformRequest(r, http.MethodPost, "/api/v1/me/export", nil, nil)
+		if rw.Code != http.StatusUnauthorized {
+			t.Fatalf("expected 401, got %d", rw.Code)
+		}
+	})
+
+	t.Run("不正な形式: 400", func(t *testing.T) {
+		t.Parallel()
+
+		svc := &fakeUserDataExportService{
+			RequestExportFn: func(ctx context.Context, userID, format string) (*model.UserDataExport, error) {
+				return nil, service.ErrInvalidExportFormat
+			},
+		}
+		r := newUserDataExportHandlerRouter(t, svc, &model.User{ID: "u1"})
+		rw := testutil.PerformRequest(r, http.MethodPost, "/api/v1/me/export", []byte(`{"format":"csv"}`), nil)
+		if rw.Code != http.StatusBadRequest {
+			t.Fatalf("expected 400, got %d", rw.Code)
+		}
+	})
+
+	t.Run("成功: 202 かつ形式が渡される", func(t *testing.T) {
+		t.Parallel()
+
+		var gotFormat string
+		svc := &fakeUserDataExportService{
+			RequestExportFn: func(ctx context.Context, userID, format string) (*model.UserDataExport, error) {
+				gotFormat = format
+				return &model.UserDataExport{ID: "e1", UserID: userID, Format: format, Status: model.UserDataExportStatusPending}, nil
+			},
+		}
+		r := newUserDataExportHandlerRouter(t, svc, &model.User{ID: "u1"})
+		rw := testutil.PerformRequest(r, http.MethodPost, "/api/v1/me/export", []byte(`{"format":"json"}`), nil)
+		if rw.Code != http.StatusAccepted {
+			t.Fatalf("expected 202, got %d", rw.Code)
+		}
+		if gotFormat != "json" {
+			t.Fatalf("expected format json, got %q", gotFormat)
+		}
+	})
+}
+
+func TestUserDataExportHandler_DownloadExport(t *testing.T) {
+	t.Parallel()
+
+	t.Run("生成中: 202", func(t *testing.T) {
+		t.Parallel()
+
+		svc := &fakeUserDataExportService{
+			GetExportFn: func(ctx context.Context, userID, exportID string) (*model.UserDataExport, error) {
+				return &model.UserDataExport{ID: exportID, UserID: userID, Format: "zip", Status: model.UserDataExportStatusProcessing}, nil
+			},
+		}
+		r := newUserDataExportHandlerRouter(t, svc, &model.User{ID: "u1"})
+		rw := testutil.PerformRequest(r, http.MethodGet, "/api/v1/me/export/e1", nil, nil)
+		if rw.Code != http.StatusAccepted {
+			t.Fatalf("expected 202, got %d", rw.Code)
+		}
+	})
+
+	t.Run("期限切れ: 410", func(t *testing.T) {
+		t.Parallel()
+
+		svc := &fakeUserDataExportService{
+			GetExportFn: func(ctx context.Context, userID, exportID string) (*model.UserDataExport, error) {
+				return nil, service.ErrExportExpired
+			},
+		}
+		r := newUserDataExportHandlerRouter(t, svc, &model.User{ID: "u1"})
+		rw := testutil.PerformRequest(r, http.MethodGet, "/api/v1/me/export/e1", nil, nil)
+		if rw.Code != http.StatusGone {
+			t.Fatalf("expected 410, got %d", rw.Code)
+		}
+	})
+
+	t.Run("成功: 200 かつファイルが返る", func(t *testing.T) {
+		t.Parallel()
+
+		fileName := "cinetag-export-user1-20260101.zip"
+		svc := &fakeUserDataExportService{
+			GetExportFn: func(ctx context.Context, userID, exportID string) (*model.UserDataExport, error) {
+				return &model.UserDataExport{
+					ID:       exportID,
+					UserID:   userID,
+					Format:   model.UserDataExportFormatZIP,
+					Status:   model.UserDataExportStatusCompleted,
+					FileName: &fileName,
+					Content:  []byte("PK"),
+				}, nil
+			},
+		}
+		r := newUserDataExportHandlerRouter(t, svc, &model.User{ID: "u1"})
+		rw := testutil.PerformRequest(r, http.MethodGet, "/api/v1/me/export/e1", nil, nil)
+		if rw.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d", rw.Code)
+		}
+		if got := rw.Header().Get("Content-Type"); got != "application/zip" {
+			t.Fatalf("expected application/zip, got %q", got)
+		}
+		if got := rw.Header().Get("Content-Disposition"); got != `attachment; filename="`+fileName+`"` {
+			t.Fatalf("unexpected Content-Disposition: %q", got)
+		}
+		if rw.Body.String() != "PK" {
+			t.Fatalf("unexpected body: %q", rw.Body.String())
+		}
+	})
+}
@@ -1,10 +1,13 @@
 package handler
 
 import (
+	"context"
 	"errors"
 	"log/slog"
 	"net/http"
+	"path"
 	"strconv"
+	"time"
 
 	"cinetag-backend/src/internal/middleware"
 	"cinetag-backend/src/internal/model"
@@ -15,17 +18,21 @@ import (
 
 // ユーザー関連のHTTPハンドラー。
 type UserHandler struct {
-	logger      *slog.Logger
-	userService service.UserService
-	tagService  service.TagService
+	logger             *slog.Logger
+	userService        service.UserService
+	tagService         service.TagService
+	movieRatingService service.MovieRatingService
+	feedService        service.FeedService
 }
 
 // UserHandler を生成する。
-func NewUserHandler(logger *slog.Logger, userService service.UserService, tagService service.TagService) *UserHandler {
+func NewUserHandler(logger *slog.Logger, userService service.UserService, tagService service.TagService, movieRatingService service.MovieRatingService, feedService service.FeedService) *UserHandler {
 	return &UserHandler{
-		logger:      logger,
-		userService: userService,
-		tagService:  tagService,
+		logger:             logger,
+		userService:        userService,
+		tagService:         tagService,
+		movieRatingService: movieRatingService,
+		feedService:        feedService,
 	}
 }
 
@@ -36,11 +43,55 @@ type UserProfileResponse struct {
 	DisplayName string  `json:"display_name"`
 	AvatarURL   *string `json:"avatar_url,omitempty"`
 	Bio         *string `json:"bio,omitempty"`
+	IsPrivate   bool    `json:"is_private"`
+	Role        string  `json:"role,omitempty"` // 自分自身の情報（GET /users/me）でのみ返す
+}
+
+// ユーザー検索結果の1件分のレスポンス形式。
+type UserSearchItemResponse struct {
+	UserProfileResponse
+	FollowersCount int64 `json:"followers_count"`
+	IsFollowing    bool  `json:"is_following"`
+}
+
+// おすすめユーザーの1件分のレスポンス形式。
+type UserSuggestionResponse struct {
+	UserProfileResponse
+	Reason            string `json:"reason"` // followed_by_following / shared_tags / shared_movies / popular
+	MutualFollowCount int64  `json:"mutual_follow_count"`
+	SharedTagCount    int64  `json:"shared_tag_count"`
+	SharedMovieCount  int64  `json:"shared_movie_count"`
+	FollowersCount    int64  `json:"followers_count"`
+}
+
+// プロフィール統計のレスポンス形式。
+type UserStatsResponse struct {
+	service.UserTagStats
+	FollowingCount int64 `json:"following_count"`
+	FollowersCount int64 `json:"followers_count"`
 }
 
 // ユーザー更新リクエストの形式。
 type UpdateMeRequest struct {
 	DisplayName *string `json:"display_name"`
+	DisplayID   *string `json:"display_id"`
+	Bio         *string `json:"bio"`
+	AvatarURL   *string `json:"avatar_url"`
+	IsPrivate   *bool   `json:"is_private"`
+}
+
+// 退会リクエストの形式。
+// 省略した項目は既定ポリシー（所有タグは削除、他者タグへの追加映画は匿名化して残す）になる。
+type DeleteMeRequest struct {
+	OwnedTags           string `json:"owned_tags"`             // delete / transfer
+	TransferToDisplayID string `json:"transfer_to_display_id"` // owned_tags が transfer の場合の譲渡先
+	Contributions       string `json:"contributions"`          // anonymize / remove
+}
+
+// 退会状態のレスポンス形式。
+type AccountDeletionResponse struct {
+	DeletionStatus   string     `json:"deletion_status"`
+	PurgeScheduledAt *time.Time `json:"purge_scheduled_at,omitempty"`
 }
 
 // 認証済みユーザー自身の情報を返す。
@@ -64,6 +115,8 @@ func (h *UserHandler) GetMe(c *gin.Context) {
 		DisplayName: user.DisplayName,
 		AvatarURL:   user.AvatarURL,
 		Bio:         user.Bio,
+		IsPrivate:   user.IsPrivate,
+		Role:        user.Role,
 	})
 }
 
@@ -91,6 +144,10 @@ func (h *UserHandler) UpdateMe(c *gin.Context) {
 	// 更新入力を構築
 	input := service.UpdateUserInput{
 		DisplayName: req.DisplayName,
+		DisplayID:   req.DisplayID,
+		Bio:         req.Bio,
+		AvatarURL:   req.AvatarURL,
+		IsPrivate:   req.IsPrivate,
 	}
 
 	updatedUser, err := h.userService.UpdateUser(c.Request.Context(), user.ID, input)
@@ -99,6 +156,10 @@ func (h *UserHandler) UpdateMe(c *gin.Context) {
 			slog.String("user_id", user.ID),
 			slog.String("error", err.Error()),
 		)
+		if errors.Is(err, service.ErrDisplayIDTaken) {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
@@ -109,6 +170,98 @@ func (h *UserHandler) UpdateMe(c *gin.Context) {
 		DisplayName: updatedUser.DisplayName,
 		AvatarURL:   updatedUser.AvatarURL,
 		Bio:         updatedUser.Bio,
+		IsPrivate:   updatedUser.IsPrivate,
+	})
+}
+
+// 認証済みユーザー自身を退会状態にする。猶予期間中は再開でき、期限後に完全削除される。
+// DELETE /api/v1/users/me
+func (h *UserHandler) DeleteMe(c *gin.Context) {
+	user := getUserFromContext(c)
+	if user == nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	var req DeleteMeRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body"})
+			return
+		}
+	}
+
+	policy := service.DefaultAccountDeletionPolicy()
+	if req.OwnedTags != "" {
+		policy.OwnedTags = req.OwnedTags
+	}
+	if req.Contributions != "" {
+		policy.Contributions = req.Contributions
+	}
+	if req.TransferToDisplayID != "" {
+		target, err := h.userService.GetUserByDisplayID(c.Request.Context(), req.TransferToDisplayID)
+		if err != nil {
+			if errors.Is(err, service.ErrUserNotFound) {
+				c.JSON(http.StatusBadRequest, gin.H{"error": service.ErrInvalidTagTransferTarget.Error()})
+				return
+			}
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to get user"})
+			return
+		}
+		policy.TransferToUserID = target.ID
+	}
+
+	deactivated, err := h.userService.DeactivateUser(c.Request.Context(), user.ID, policy)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidDeletionPolicy) || errors.Is(err, service.ErrInvalidTagTransferTarget) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		h.logger.Error("failed to deactivate user",
+			slog.String("user_id", user.ID),
+			slog.String("error", err.Error()),
+		)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to deactivate user"})
+		return
+	}
+
+	c.JSON(http.StatusOK, AccountDeletionResponse{
+		DeletionStatus:   deactivated.DeletionStatus,
+		PurgeScheduledAt: deactivated.PurgeScheduledAt,
+	})
+}
+
+// 猶予期間中の退会を取り消す。
+// POST /api/v1/users/me/reactivate
+func (h *UserHandler) ReactivateMe(c *gin.Context) {
+	user := getUserFromContext(c)
+	if user == nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	reactivated, err := h.userService.ReactivateUser(c.Request.Context(), user.ID)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrUserNotDeactivated):
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		case errors.Is(err, service.ErrReactivationPeriodExpired):
+			c.JSON(http.StatusGone, gin.H{"error": err.Error()})
+		case errors.Is(err, service.ErrUserSuspended):
+			c.JSON(http.StatusForbidden, gin.H{"error": "account suspended"})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to reactivate user"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, UserProfileResponse{
+		ID:          reactivated.ID,
+		DisplayID:   reactivated.DisplayID,
+		DisplayName: reactivated.DisplayName,
+		AvatarURL:   reactivated.AvatarURL,
+		Bio:         reactivated.Bio,
+		IsPrivate:   reactivated.IsPrivate,
 	})
 }
 
@@ -124,6 +277,15 @@ func (h *UserHandler) GetUserByDisplayID(c *gin.Context) {
 	user, err := h.userService.GetUserByDisplayID(c.Request.Context(), displayID)
 	if err != nil {
 		if errors.Is(err, service.ErrUserNotFound) {
+			// display_id が変更されている場合は、現在の display_id へリダイレクトする
+			if currentID, resolveErr := h.userService.ResolveRenamedDisplayID(c.Request.Context(), displayID); resolveErr == nil {
+				location := path.Join(path.Dir(c.Request.URL.Path), currentID)
+				if c.Request.URL.RawQuery != "" {
+					location += "?" + c.Request.URL.RawQuery
+				}
+				c.Redirect(http.StatusFound, location)
+				return
+			}
 			c.JSON(http.StatusNotFound, gin.H{"error": "user not found"})
 			return
 		}
@@ -137,6 +299,7 @@ func (h *UserHandler) GetUserByDisplayID(c *gin.Context) {
 		DisplayName: user.DisplayName,
 		AvatarURL:   user.AvatarURL,
 		Bio:         user.Bio,
+		IsPrivate:   user.IsPrivate,
 	})
 }
 
@@ -160,6 +323,10 @@ func (h *UserHandler) ListUserTags(c *gin.Context) {
 		return
 	}
 
+	if !h.ensureCanViewUserContent(c, user) {
+		return
+	}
+
 	page := parseIntDefaultUser(c.Query("page"), 1)
 	pageSize := parseIntDefaultUser(c.Query("page_size"), 20)
 
@@ -185,6 +352,161 @@ func (h *UserHandler) ListUserTags(c *gin.Context) {
 	})
 }
 
+// ユーザーのプロフィール統計を取得する。
+// GET /api/v1/users/:displayId/stats
+func (h *UserHandler) GetUserStats(c *gin.Context) {
+	displayID := c.Param("displayId")
+	if displayID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "display_id is required"})
+		return
+	}
+
+	user, err := h.userService.GetUserByDisplayID(c.Request.Context(), displayID)
+	if err != nil {
+		if errors.Is(err, service.ErrUserNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "user not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to get user"})
+		return
+	}
+
+	if !h.ensureCanViewUserContent(c, user) {
+		return
+	}
+
+	// 閲覧者が本人かどうかを判定
+	publicOnly := true
+	if viewerRaw, exists := c.Get("user"); exists {
+		if viewer, ok := viewerRaw.(*model.User); ok && viewer != nil && viewer.ID == user.ID {
+			publicOnly = false // 本人なら非公開タグも集計
+		}
+	}
+
+	tagStats, err := h.tagService.GetUserTagStats(c.Request.Context(), user.ID, publicOnly)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to get user stats"})
+		return
+	}
+
+	following, followers, err := h.userService.GetFollowStats(c.Request.Context(), user.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to get user stats"})
+		return
+	}
+
+	c.JSON(http.StatusOK, UserStatsResponse{
+		UserTagStats:   *tagStats,
+		FollowingCount: following,
+		FollowersCount: followers,
+	})
+}
+
+// ユーザーの映画の評価一覧を取得する。
+// GET /api/v1/users/:displayId/ratings
+func (h *UserHandler) ListUserRatings(c *gin.Context) {
+	displayID := c.Param("displayId")
+	if displayID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "display_id is required"})
+		return
+	}
+
+	user, err := h.userService.GetUserByDisplayID(c.Request.Context(), displayID)
+	if err != nil {
+		if errors.Is(err, service.ErrUserNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "user not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to get user"})
+		return
+	}
+
+	if !h.ensureCanViewUserContent(c, user) {
+		return
+	}
+
+	page := parseIntDefaultUser(c.Query("page"), 1)
+	pageSize := parseIntDefaultUser(c.Query("page_size"), 20)
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 || pageSize > 100 {
+		pageSize = 20
+	}
+
+	items, total, err := h.movieRatingService.ListUserRatings(c.Request.Context(), user.ID, page, pageSize)
+	if err != nil {
+		h.logger.Error("handler.ListUserRatings failed",
+			slog.String("user_id", user.ID),
+			slog.Any("error", err),
+		)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to list user ratings"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"items":       items,
+		"page":        page,
+		"page_size":   pageSize,
+		"total_count": total,
+	})
+}
+
+// ListUserActivity は指定ユーザーのアクティビティ（タグ作成・映画追加・いいね・ユーザーフォロー）一覧を返します。
+// GET /api/v1/users/:displayId/activity?page={page}&page_size={page_size}
+func (h *UserHandler) ListUserActivity(c *gin.Context) {
+	displayID := c.Param("displayId")
+	if displayID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "display_id is required"})
+		return
+	}
+
+	user, err := h.userService.GetUserByDisplayID(c.Request.Context(), displayID)
+	if err != nil {
+		if errors.Is(err, service.ErrUserNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "user not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to get user"})
+		return
+	}
+
+	if !h.ensureCanViewUserContent(c, user) {
+		return
+	}
+
+	viewerID := ""
+	if viewer := getUserFromContext(c); viewer != nil {
+		viewerID = viewer.ID
+	}
+
+	page := parseIntDefaultUser(c.Query("page"), 1)
+	pageSize := parseIntDefaultUser(c.Query("page_size"), 20)
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 || pageSize > 100 {
+		pageSize = 20
+	}
+
+	items, total, err := h.feedService.ListUserActivity(c.Request.Context(), user.ID, viewerID, page, pageSize)
+	if err != nil {
+		h.logger.Error("handler.ListUserActivity failed",
+			slog.String("user_id", user.ID),
+			slog.Any("error", err),
+		)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to list user activity"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"items":       items,
+		"page":        page,
+		"page_size":   pageSize,
+		"total_count": total,
+	})
+}
+
 // FollowUser は指定ユーザーをフォローします。
 // POST /api/v1/users/:displayId/follow
 func (h *UserHandler) FollowUser(c *gin.Context) {
@@ -234,8 +556,8 @@ func (h *UserHandler) FollowUser(c *gin.Context) {
 		return
 	}
 
-	// フォローを実行
-	err = h.userService.FollowUser(c.Request.Context(), currentUser.ID, targetUser.ID)
+	// フォローを実行（非公開アカウントの場合はフォローリクエストになる）
+	status, err := h.userService.FollowUser(c.Request.Context(), currentUser.ID, targetUser.ID)
 	if err != nil {
 		if errors.Is(err, service.ErrUserNotFound) {
 			c.JSON(http.StatusNotFound, gin.H{"error": "user not found"})
@@ -249,11 +571,24 @@ func (h *UserHandler) FollowUser(c *gin.Context) {
 			c.JSON(http.StatusConflict, gin.H{"error": "already following"})
 			return
 		}
+		if errors.Is(err, service.ErrFollowRequestAlreadySent) {
+			c.JSON(http.StatusConflict, gin.H{"error": "follow request already sent"})
+			return
+		}
+		if errors.Is(err, service.ErrUserBlocked) {
+			c.JSON(http.StatusForbidden, gin.H{"error": "user is blocked"})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to follow user"})
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"message": "successfully followed"})
+	if status == service.FollowStatusRequested {
+		c.JSON(http.StatusOK, gin.H{"message": "follow request sent", "status": status})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "successfully followed", "status": status})
 }
 
 // 指定ユーザーをアンフォローする。
@@ -303,6 +638,95 @@ func (h *UserHandler) UnfollowUser(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"message": "successfully unfollowed"})
 }
 
+// display_name / display_id でユーザーを検索する。
+// GET /api/v1/users/search?q=
+func (h *UserHandler) SearchUsers(c *gin.Context) {
+	viewerID := ""
+	if viewerRaw, exists := c.Get("user"); exists {
+		if viewer, ok := viewerRaw.(*model.User); ok && viewer != nil {
+			viewerID = viewer.ID
+		}
+	}
+
+	page := parseIntDefaultUser(c.Query("page"), 1)
+	pageSize := parseIntDefaultUser(c.Query("page_size"), 20)
+
+	results, total, err := h.userService.SearchUsers(c.Request.Context(), viewerID, c.Query("q"), page, pageSize)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrEmptySearchQuery):
+			c.JSON(http.StatusBadRequest, gin.H{"error": "q is required"})
+		case errors.Is(err, service.ErrSearchQueryTooLong):
+			c.JSON(http.StatusBadRequest, gin.H{"error": "q is too long"})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to search users"})
+		}
+		return
+	}
+
+	items := make([]UserSearchItemResponse, len(results))
+	for i, r := range results {
+		items[i] = UserSearchItemResponse{
+			UserProfileResponse: UserProfileResponse{
+				ID:          r.User.ID,
+				DisplayID:   r.User.DisplayID,
+				DisplayName: r.User.DisplayName,
+				AvatarURL:   r.User.AvatarURL,
+				Bio:         r.User.Bio,
+				IsPrivate:   r.User.IsPrivate,
+			},
+			FollowersCount: r.FollowerCount,
+			IsFollowing:    r.IsFollowing,
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"items":       items,
+		"page":        page,
+		"page_size":   pageSize,
+		"total_count": total,
+	})
+}
+
+// 認証ユーザーへのおすすめユーザー（フォロー候補）を取得する。
+// GET /api/v1/me/suggestions/users
+func (h *UserHandler) ListUserSuggestions(c *gin.Context) {
+	currentUser := getUserFromContext(c)
+	if currentUser == nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	limit := parseIntDefaultUser(c.Query("limit"), 20)
+
+	suggestions, err := h.userService.SuggestUsers(c.Request.Context(), currentUser.ID, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to list user suggestions"})
+		return
+	}
+
+	items := make([]UserSuggestionResponse, len(suggestions))
+	for i, s := range suggestions {
+		items[i] = UserSuggestionResponse{
+			UserProfileResponse: UserProfileResponse{
+				ID:          s.User.ID,
+				DisplayID:   s.User.DisplayID,
+				DisplayName: s.User.DisplayName,
+				AvatarURL:   s.User.AvatarURL,
+				Bio:         s.User.Bio,
+				IsPrivate:   s.User.IsPrivate,
+			},
+			Reason:            string(s.Reason),
+			MutualFollowCount: s.MutualFollowCount,
+			SharedTagCount:    s.SharedTagCount,
+			SharedMovieCount:  s.SharedMovieCount,
+			FollowersCount:    s.FollowerCount,
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{"items": items})
+}
+
 // 指定ユーザーがフォローしているユーザー一覧を取得する。
 // GET /api/v1/users/:displayId/following
 func (h *UserHandler) ListFollowing(c *gin.Context) {
@@ -323,6 +747,10 @@ func (h *UserHandler) ListFollowing(c *gin.Context) {
 		return
 	}
 
+	if !h.ensureCanViewUserContent(c, user) {
+		return
+	}
+
 	page := parseIntDefaultUser(c.Query("page"), 1)
 	pageSize := parseIntDefaultUser(c.Query("page_size"), 20)
 
@@ -340,6 +768,7 @@ func (h *UserHandler) ListFollowing(c *gin.Context) {
 			DisplayName: u.DisplayName,
 			AvatarURL:   u.AvatarURL,
 			Bio:         u.Bio,
+			IsPrivate:   u.IsPrivate,
 		}
 	}
 
@@ -371,6 +800,10 @@ func (h *UserHandler) ListFollowers(c *gin.Context) {
 		return
 	}
 
+	if !h.ensureCanViewUserContent(c, user) {
+		return
+	}
+
 	page := parseIntDefaultUser(c.Query("page"), 1)
 	pageSize := parseIntDefaultUser(c.Query("page_size"), 20)
 
@@ -388,6 +821,7 @@ func (h *UserHandler) ListFollowers(c *gin.Context) {
 			DisplayName: u.DisplayName,
 			AvatarURL:   u.AvatarURL,
 			Bio:         u.Bio,
+			IsPrivate:   u.IsPrivate,
 		}
 	}
 
@@ -425,18 +859,268 @@ func (h *UserHandler) GetUserFollowStats(c *gin.Context) {
 		return
 	}
 
-	// 認証ユーザーがこのユーザーをフォローしているか確認
+	// 認証ユーザーがこのユーザーをフォロー（またはリクエスト）しているか確認
 	isFollowing := false
+	isFollowRequested := false
 	if viewerRaw, exists := c.Get("user"); exists {
 		if viewer, ok := viewerRaw.(*model.User); ok && viewer != nil {
 			isFollowing, _ = h.userService.IsFollowing(c.Request.Context(), viewer.ID, user.ID)
+			if !isFollowing {
+				isFollowRequested, _ = h.userService.HasPendingFollowRequest(c.Request.Context(), viewer.ID, user.ID)
+			}
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"following_count":     following,
+		"followers_count":     followers,
+		"is_following":        isFollowing,
+		"is_follow_requested": isFollowRequested,
+	})
+}
+
+// 自分宛ての承認待ちフォローリクエスト一覧を取得する。
+// GET /api/v1/me/follow-requests
+func (h *UserHandler) ListFollowRequests(c *gin.Context) {
+	h.listRelatedUsers(c, h.userService.ListFollowRequests, "failed to list follow requests")
+}
+
+// 指定ユーザーからのフォローリクエストを承認する。
+// POST /api/v1/me/follow-requests/:displayId/approve
+func (h *UserHandler) ApproveFollowRequest(c *gin.Context) {
+	currentUser, requester, ok := h.resolveRelationTarget(c)
+	if !ok {
+		return
+	}
+
+	if err := h.userService.ApproveFollowRequest(c.Request.Context(), currentUser.ID, requester.ID); err != nil {
+		if errors.Is(err, service.ErrFollowRequestNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "follow request not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to approve follow request"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "follow request approved"})
+}
+
+// 指定ユーザーからのフォローリクエストを拒否する。
+// POST /api/v1/me/follow-requests/:displayId/deny
+func (h *UserHandler) DenyFollowRequest(c *gin.Context) {
+	currentUser, requester, ok := h.resolveRelationTarget(c)
+	if !ok {
+		return
+	}
+
+	if err := h.userService.DenyFollowRequest(c.Request.Context(), currentUser.ID, requester.ID); err != nil {
+		if errors.Is(err, service.ErrFollowRequestNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "follow request not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to deny follow request"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "follow request denied"})
+}
+
+// 指定ユーザーをブロックする。
+// POST /api/v1/users/:displayId/block
+func (h *UserHandler) BlockUser(c *gin.Context) {
+	currentUser, targetUser, ok := h.resolveRelationTarget(c)
+	if !ok {
+		return
+	}
+
+	if err := h.userService.BlockUser(c.Request.Context(), currentUser.ID, targetUser.ID); err != nil {
+		switch {
+		case errors.Is(err, service.ErrUserNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": "user not found"})
+		case errors.Is(err, service.ErrCannotBlockSelf):
+			c.JSON(http.StatusBadRequest, gin.H{"error": "cannot block yourself"})
+		case errors.Is(err, service.ErrAlreadyBlocked):
+			c.JSON(http.StatusConflict, gin.H{"error": "already blocked"})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to block user"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "successfully blocked"})
+}
+
+// 指定ユーザーのブロックを解除する。
+// DELETE /api/v1/users/:displayId/block
+func (h *UserHandler) UnblockUser(c *gin.Context) {
+	currentUser, targetUser, ok := h.resolveRelationTarget(c)
+	if !ok {
+		return
+	}
+
+	if err := h.userService.UnblockUser(c.Request.Context(), currentUser.ID, targetUser.ID); err != nil {
+		if errors.Is(err, service.ErrNotBlocked) {
+			c.JSON(http.StatusConflict, gin.H{"error": "not blocked"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to unblock user"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "successfully unblocked"})
+}
+
+// 指定ユーザーをミュートする。
+// POST /api/v1/users/:displayId/mute
+func (h *UserHandler) MuteUser(c *gin.Context) {
+	currentUser, targetUser, ok := h.resolveRelationTarget(c)
+	if !ok {
+		return
+	}
+
+	if err := h.userService.MuteUser(c.Request.Context(), currentUser.ID, targetUser.ID); err != nil {
+		switch {
+		case errors.Is(err, service.ErrUserNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": "user not found"})
+		case errors.Is(err, service.ErrCannotBlockSelf):
+			c.JSON(http.StatusBadRequest, gin.H{"error": "cannot mute yourself"})
+		case errors.Is(err, service.ErrAlreadyMuted):
+			c.JSON(http.StatusConflict, gin.H{"error": "already muted"})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to mute user"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "successfully muted"})
+}
+
+// 指定ユーザーのミュートを解除する。
+// DELETE /api/v1/users/:displayId/mute
+func (h *UserHandler) UnmuteUser(c *gin.Context) {
+	currentUser, targetUser, ok := h.resolveRelationTarget(c)
+	if !ok {
+		return
+	}
+
+	if err := h.userService.UnmuteUser(c.Request.Context(), currentUser.ID, targetUser.ID); err != nil {
+		if errors.Is(err, service.ErrNotMuted) {
+			c.JSON(http.StatusConflict, gin.H{"error": "not muted"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to unmute user"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "successfully unmuted"})
+}
+
+// 自分がブロックしているユーザー一覧を取得する。
+// GET /api/v1/me/blocks
+func (h *UserHandler) ListBlockedUsers(c *gin.Context) {
+	h.listRelatedUsers(c, h.userService.ListBlockedUsers, "failed to list blocked users")
+}
+
+// 自分がミュートしているユーザー一覧を取得する。
+// GET /api/v1/me/mutes
+func (h *UserHandler) ListMutedUsers(c *gin.Context) {
+	h.listRelatedUsers(c, h.userService.ListMutedUsers, "failed to list muted users")
+}
+
+// 閲覧者が owner のタグ一覧・フォロー一覧を閲覧できるか確認する。
+// 非公開アカウントで閲覧できない場合は 403 を書き込み、false を返す。
+func (h *UserHandler) ensureCanViewUserContent(c *gin.Context, owner *model.User) bool {
+	viewerID := ""
+	if viewerRaw, exists := c.Get("user"); exists {
+		if viewer, ok := viewerRaw.(*model.User); ok && viewer != nil {
+			viewerID = viewer.ID
+		}
+	}
+
+	canView, err := h.userService.CanViewUserContent(c.Request.Context(), viewerID, owner)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to check visibility"})
+		return false
+	}
+	if !canView {
+		c.JSON(http.StatusForbidden, gin.H{"error": "this account is private"})
+		return false
+	}
+	return true
+}
+
+// 認証ユーザーと、パスパラメータ displayId の対象ユーザーを解決する。
+// 解決できない場合はレスポンスを書き込み、ok=false を返す。
+func (h *UserHandler) resolveRelationTarget(c *gin.Context) (currentUser *model.User, targetUser *model.User, ok bool) {
+	displayID := c.Param("displayId")
+	if displayID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "display_id is required"})
+		return nil, nil, false
+	}
+
+	userRaw, exists := c.Get("user")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return nil, nil, false
+	}
+	currentUser, isUser := userRaw.(*model.User)
+	if !isUser || currentUser == nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "invalid user context"})
+		return nil, nil, false
+	}
+
+	targetUser, err := h.userService.GetUserByDisplayID(c.Request.Context(), displayID)
+	if err != nil {
+		if errors.Is(err, service.ErrUserNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "user not found"})
+			return nil, nil, false
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to get user"})
+		return nil, nil, false
+	}
+
+	return currentUser, targetUser, true
+}
+
+// 認証ユーザーに紐づくユーザー一覧（ブロック・ミュート・フォローリクエスト）を返す。
+func (h *UserHandler) listRelatedUsers(c *gin.Context, list func(ctx context.Context, userID string, page, pageSize int) ([]*model.User, int64, error), errMessage string) {
+	userRaw, exists := c.Get("user")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	currentUser, ok := userRaw.(*model.User)
+	if !ok || currentUser == nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "invalid user context"})
+		return
+	}
+
+	page := parseIntDefaultUser(c.Query("page"), 1)
+	pageSize := parseIntDefaultUser(c.Query("page_size"), 20)
+
+	users, total, err := list(c.Request.Context(), currentUser.ID, page, pageSize)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": errMessage})
+		return
+	}
+
+	items := make([]UserProfileResponse, len(users))
+	for i, u := range users {
+		items[i] = UserProfileResponse{
+			ID:          u.ID,
+			DisplayID:   u.DisplayID,
+			DisplayName: u.DisplayName,
+			AvatarURL:   u.AvatarURL,
+			Bio:         u.Bio,
+			IsPrivate:   u.IsPrivate,
 		}
 	}
 
 	c.JSON(http.StatusOK, gin.H{
-		"following_count": following,
-		"followers_count": followers,
-		"is_following":    isFollowing,
+		"items":       items,
+		"page":        page,
+		"page_size":   pageSize,
+		"total_count": total,
 	})
 }
 
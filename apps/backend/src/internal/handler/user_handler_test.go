@@ -5,6 +5,7 @@ import (
 	"errors"
 	"net/http"
 	"testing"
+	"time"
 
 	"cinetag-backend/src/internal/model"
 	"cinetag-backend/src/internal/service"
@@ -14,14 +15,29 @@ import (
 )
 
 type fakeUserService struct {
-	GetUserByDisplayIDFn func(ctx context.Context, displayID string) (*model.User, error)
-	UpdateUserFn         func(ctx context.Context, userID string, input service.UpdateUserInput) (*model.User, error)
-	FollowUserFn         func(ctx context.Context, followerID, followeeID string) error
-	UnfollowUserFn       func(ctx context.Context, followerID, followeeID string) error
-	IsFollowingFn        func(ctx context.Context, followerID, followeeID string) (bool, error)
-	ListFollowingFn      func(ctx context.Context, userID string, page, pageSize int) ([]*model.User, int64, error)
-	ListFollowersFn      func(ctx context.Context, userID string, page, pageSize int) ([]*model.User, int64, error)
-	GetFollowStatsFn     func(ctx context.Context, userID string) (following int64, followers int64, err error)
+	GetUserByDisplayIDFn      func(ctx context.Context, displayID string) (*model.User, error)
+	ResolveRenamedDisplayIDFn func(ctx context.Context, displayID string) (string, error)
+	UpdateUserFn              func(ctx context.Context, userID string, input service.UpdateUserInput) (*model.User, error)
+	FollowUserFn              func(ctx context.Context, followerID, followeeID string) (service.FollowStatus, error)
+	UnfollowUserFn            func(ctx context.Context, followerID, followeeID string) error
+	IsFollowingFn             func(ctx context.Context, followerID, followeeID string) (bool, error)
+	ListFollowingFn           func(ctx context.Context, userID string, page, pageSize int) ([]*model.User, int64, error)
+	ListFollowersFn           func(ctx context.Context, userID string, page, pageSize int) ([]*model.User, int64, error)
+	GetFollowStatsFn          func(ctx context.Context, userID string) (following int64, followers int64, err error)
+	BlockUserFn               func(ctx context.Context, blockerID, blockedID string) error
+	UnblockUserFn             func(ctx context.Context, blockerID, blockedID string) error
+	MuteUserFn                func(ctx context.Context, muterID, mutedID string) error
+	UnmuteUserFn              func(ctx context.Context, muterID, mutedID string) error
+	ListBlockedUsersFn        func(ctx context.Context, userID string, page, pageSize int) ([]*model.User, int64, error)
+	ListFollowRequestsFn      func(ctx context.Context, userID string, page, pageSize int) ([]*model.User, int64, error)
+	ApproveFollowRequestFn    func(ctx context.Context, targetID, requesterID string) error
+	DenyFollowRequestFn       func(ctx context.Context, targetID, requesterID string) error
+	HasPendingFollowRequestFn func(ctx context.Context, requesterID, targetID string) (bool, error)
+	CanViewUserContentFn      func(ctx context.Context, viewerID string, owner *model.User) (bool, error)
+	DeactivateUserFn          func(ctx context.Context, userID string, policy service.AccountDeletionPolicy) (*model.User, error)
+	ReactivateUserFn          func(ctx context.Context, userID string) (*model.User, error)
+	SearchUsersFn             func(ctx context.Context, viewerID, query string, page, pageSize int) ([]service.UserSearchResult, int64, error)
+	SuggestUsersFn            func(ctx context.Context, userID string, limit int) ([]service.UserSuggestion, error)
 }
 
 func (f *fakeUserService) EnsureUser(ctx context.Context, clerkUser service.ClerkUserInfo) (*model.User, error) {
@@ -39,6 +55,13 @@ func (f *fakeUserService) GetUserByDisplayID(ctx context.Context, displayID stri
 	return f.GetUserByDisplayIDFn(ctx, displayID)
 }
 
+func (f *fakeUserService) ResolveRenamedDisplayID(ctx context.Context, displayID string) (string, error) {
+	if f.ResolveRenamedDisplayIDFn == nil {
+		return "", service.ErrUserNotFound
+	}
+	return f.ResolveRenamedDisplayIDFn(ctx, displayID)
+}
+
 func (f *fakeUserService) UpdateUser(ctx context.Context, userID string, input service.UpdateUserInput) (*model.User, error) {
 	if f.UpdateUserFn == nil {
 		return nil, nil
@@ -46,13 +69,13 @@ func (f *fakeUserService) UpdateUser(ctx context.Context, userID string, input s
 	return f.UpdateUserFn(ctx, userID, input)
 }
 
-func (f *fakeUserService) UpdateUserFromClerk(ctx context.Context, userID string, avatarURL *string) error {
-	return nil
+func (f *fakeUserService) SyncUserFromClerk(ctx context.Context, clerkUser service.ClerkUserInfo, opts service.ClerkSyncOptions) (*model.User, error) {
+	return nil, nil
 }
 
-func (f *fakeUserService) FollowUser(ctx context.Context, followerID, followeeID string) error {
+func (f *fakeUserService) FollowUser(ctx context.Context, followerID, followeeID string) (service.FollowStatus, error) {
 	if f.FollowUserFn == nil {
-		return nil
+		return service.FollowStatusFollowing, nil
 	}
 	return f.FollowUserFn(ctx, followerID, followeeID)
 }
@@ -92,16 +115,122 @@ func (f *fakeUserService) GetFollowStats(ctx context.Context, userID string) (fo
 	return f.GetFollowStatsFn(ctx, userID)
 }
 
-func (f *fakeUserService) DeactivateUser(ctx context.Context, userID string) error {
+func (f *fakeUserService) DeactivateUser(ctx context.Context, userID string, policy service.AccountDeletionPolicy) (*model.User, error) {
+	if f.DeactivateUserFn == nil {
+		return &model.User{ID: userID}, nil
+	}
+	return f.DeactivateUserFn(ctx, userID, policy)
+}
+
+func (f *fakeUserService) DeactivateClerkUser(ctx context.Context, clerkUserID string, event *service.ClerkWebhookClaim) error {
 	return nil
 }
 
+func (f *fakeUserService) ReactivateUser(ctx context.Context, userID string) (*model.User, error) {
+	if f.ReactivateUserFn == nil {
+		return &model.User{ID: userID}, nil
+	}
+	return f.ReactivateUserFn(ctx, userID)
+}
+
+func (f *fakeUserService) PurgeDeactivatedUsers(ctx context.Context, now time.Time, limit int) (int, error) {
+	return 0, nil
+}
+
+func (f *fakeUserService) BlockUser(ctx context.Context, blockerID, blockedID string) error {
+	if f.BlockUserFn == nil {
+		return nil
+	}
+	return f.BlockUserFn(ctx, blockerID, blockedID)
+}
+
+func (f *fakeUserService) UnblockUser(ctx context.Context, blockerID, blockedID string) error {
+	if f.UnblockUserFn == nil {
+		return nil
+	}
+	return f.UnblockUserFn(ctx, blockerID, blockedID)
+}
+
+func (f *fakeUserService) MuteUser(ctx context.Context, muterID, mutedID string) error {
+	if f.MuteUserFn == nil {
+		return nil
+	}
+	return f.MuteUserFn(ctx, muterID, mutedID)
+}
+
+func (f *fakeUserService) UnmuteUser(ctx context.Context, muterID, mutedID string) error {
+	if f.UnmuteUserFn == nil {
+		return nil
+	}
+	return f.UnmuteUserFn(ctx, muterID, mutedID)
+}
+
+func (f *fakeUserService) ListBlockedUsers(ctx context.Context, userID string, page, pageSize int) ([]*model.User, int64, error) {
+	if f.ListBlockedUsersFn == nil {
+		return []*model.User{}, 0, nil
+	}
+	return f.ListBlockedUsersFn(ctx, userID, page, pageSize)
+}
+
+func (f *fakeUserService) ListMutedUsers(ctx context.Context, userID string, page, pageSize int) ([]*model.User, int64, error) {
+	return []*model.User{}, 0, nil
+}
+
+func (f *fakeUserService) SearchUsers(ctx context.Context, viewerID, query string, page, pageSize int) ([]service.UserSearchResult, int64, error) {
+	if f.SearchUsersFn == nil {
+		return []service.UserSearchResult{}, 0, nil
+	}
+	return f.SearchUsersFn(ctx, viewerID, query, page, pageSize)
+}
+
+func (f *fakeUserService) SuggestUsers(ctx context.Context, userID string, limit int) ([]service.UserSuggestion, error) {
+	if f.SuggestUsersFn == nil {
+		return []service.UserSuggestion{}, nil
+	}
+	return f.SuggestUsersFn(ctx, userID, limit)
+}
+
+func (f *fakeUserService) ApproveFollowRequest(ctx context.Context, targetID, requesterID string) error {
+	if f.ApproveFollowRequestFn == nil {
+		return nil
+	}
+	return f.ApproveFollowRequestFn(ctx, targetID, requesterID)
+}
+
+func (f *fakeUserService) DenyFollowRequest(ctx context.Context, targetID, requesterID string) error {
+	if f.DenyFollowRequestFn == nil {
+		return nil
+	}
+	return f.DenyFollowRequestFn(ctx, targetID, requesterID)
+}
+
+func (f *fakeUserService) ListFollowRequests(ctx context.Context, userID string, page, pageSize int) ([]*model.User, int64, error) {
+	if f.ListFollowRequestsFn == nil {
+		return []*model.User{}, 0, nil
+	}
+	return f.ListFollowRequestsFn(ctx, userID, page, pageSize)
+}
+
+func (f *fakeUserService) HasPendingFollowRequest(ctx context.Context, requesterID, targetID string) (bool, error) {
+	if f.HasPendingFollowRequestFn == nil {
+		return false, nil
+	}
+	return f.HasPendingFollowRequestFn(ctx, requesterID, targetID)
+}
+
+func (f *fakeUserService) CanViewUserContent(ctx context.Context, viewerID string, owner *model.User) (bool, error) {
+	if f.CanViewUserContentFn == nil {
+		return true, nil
+	}
+	return f.CanViewUserContentFn(ctx, viewerID, owner)
+}
+
 func newUserHandlerRouter(t *testing.T, userSvc service.UserService, tagSvc service.TagService, user *model.User) *gin.Engine {
 	t.Helper()
 
 	r := testutil.NewTestRouter()
 	logger := testutil.NewTestLogger()
-	h := NewUserHandler(logger, userSvc, tagSvc)
+	h := NewUserHandler(logger, userSvc, tagSvc, nil, nil)
 
 	api := r.Group("/api/v1")
 
@@ -115,8 +244,18 @@ func newUserHandlerRouter(t *testing.T, userSvc service.UserService, tagSvc serv
 	}
 	auth.GET("/users/me", h.GetMe)
 	auth.PATCH("/users/me", h.UpdateMe)
+	auth.DELETE("/users/me", h.DeleteMe)
+	auth.POST("/users/me/reactivate", h.ReactivateMe)
 	auth.POST("/users/:displayId/follow", h.FollowUser)
 	auth.DELETE("/users/:displayId/follow", h.UnfollowUser)
+	auth.POST("/users/:displayId/block", h.BlockUser)
+	auth.DELETE("/users/:displayId/block", h.UnblockUser)
+	auth.POST("/users/:displayId/mute", h.MuteUser)
+	auth.GET("/me/blocks", h.ListBlockedUsers)
+	auth.GET("/me/follow-requests", h.ListFollowRequests)
+	auth.GET("/me/suggestions/users", h.ListUserSuggestions)
+	auth.POST("/me/follow-requests/:displayId/approve", h.ApproveFollowRequest)
+	auth.POST("/me/follow-requests/:displayId/deny", h.DenyFollowRequest)
 
 	// Optional Auth (認証なしでもアクセス可能)
 	optionalAuth := api.Group("/")
@@ -126,11 +265,13 @@ func newUserHandlerRouter(t *testing.T, userSvc service.UserService, tagSvc serv
 			c.Next()
 		})
 	}
+	optionalAuth.GET("/users/search", h.SearchUsers)
 	optionalAuth.GET("/users/:displayId", h.GetUserByDisplayID)
 	optionalAuth.GET("/users/:displayId/tags", h.ListUserTags)
 	optionalAuth.GET("/users/:displayId/following", h.ListFollowing)
 	optionalAuth.GET("/users/:displayId/followers", h.ListFollowers)
 	optionalAuth.GET("/users/:displayId/follow-stats", h.GetUserFollowStats)
+	optionalAuth.GET("/users/:displayId/stats", h.GetUserStats)
 
 	return r
 }
@@ -153,7 +294,7 @@ func TestUserHandler_GetMe(t *testing.T) {
 
 		r := testutil.NewTestRouter()
 		logger := testutil.NewTestLogger()
-		h := NewUserHandler(logger, &fakeUserService{}, &fakeTagService{})
+		h := NewUserHandler(logger, &fakeUserService{}, &fakeTagService{}, nil, nil)
 
 		r.Use(func(c *gin.Context) {
 			c.Set("user", "invalid-user-type")
@@ -178,6 +319,7 @@ func TestUserHandler_GetMe(t *testing.T) {
 			DisplayName: "User One",
 			AvatarURL:   &avatarURL,
 			Bio:         &bio,
+			Role:        model.UserRoleAdmin,
 		}
 
 		r := newUserHandlerRouter(t, &fakeUserService{}, &fakeTagService{}, u)
@@ -197,6 +339,9 @@ func TestUserHandler_GetMe(t *testing.T) {
 		if resp["bio"] != bio {
 			t.Fatalf("expected bio=%s, got %v", bio, resp["bio"])
 		}
+		if resp["role"] != model.UserRoleAdmin {
+			t.Fatalf("expected role=admin, got %v", resp["role"])
+		}
 	})
 }
 
@@ -219,7 +364,7 @@ func TestUserHandler_UpdateMe(t *testing.T) {
 
 		r := testutil.NewTestRouter()
 		logger := testutil.NewTestLogger()
-		h := NewUserHandler(logger, &fakeUserService{}, &fakeTagService{})
+		h := NewUserHandler(logger, &fakeUserService{}, &fakeTagService{}, nil, nil)
 
 		r.Use(func(c *gin.Context) {
 			c.Set("user", "invalid-user-type")
@@ -313,6 +458,199 @@ func TestUserHandler_UpdateMe(t *testing.T) {
 			t.Fatalf("expected avatar_url=%s, got %v", avatarURL, resp["avatar_url"])
 		}
 	})
+	t.Run("display_id が使用済み: 409", func(t *testing.T) {
+		t.Parallel()
+
+		userSvc := &fakeUserService{
+			UpdateUserFn: func(ctx context.Context, userID string, input service.UpdateUserInput) (*model.User, error) {
+				return nil, service.ErrDisplayIDTaken
+			},
+		}
+
+		u := &model.User{ID: "u1", DisplayID: "user1", DisplayName: "OldName"}
+		r := newUserHandlerRouter(t, userSvc, &fakeTagService{}, u)
+
+		body := testutil.MustMarshalJSON(t, map[string]any{"display_id": "alice"})
+		rw := testutil.PerformRequest(r, http.MethodPatch, "/api/v1/users/me", body, nil)
+		if rw.Code != http.StatusConflict {
+			t.Fatalf("expected 409, got %d", rw.Code)
+		}
+	})
+
+	t.Run("成功: display_id と bio が入力に渡される", func(t *testing.T) {
+		t.Parallel()
+
+		var gotInput service.UpdateUserInput
+		userSvc := &fakeUserService{
+			UpdateUserFn: func(ctx context.Context, userID string, input service.UpdateUserInput) (*model.User, error) {
+				gotInput = input
+				return &model.User{ID: userID, DisplayID: *input.DisplayID, DisplayName: "OldName", Bio: input.Bio}, nil
+			},
+		}
+
+		u := &model.User{ID: "u1", DisplayID: "user1", DisplayName: "OldName"}
+		r := newUserHandlerRouter(t, userSvc, &fakeTagService{}, u)
+
+		body := testutil.MustMarshalJSON(t, map[string]any{"display_id": "alice", "bio": "映画が好きです"})
+		rw := testutil.PerformRequest(r, http.MethodPatch, "/api/v1/users/me", body, nil)
+		if rw.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d", rw.Code)
+		}
+		if gotInput.DisplayID == nil || *gotInput.DisplayID != "alice" {
+			t.Fatalf("expected DisplayID=alice, got %v", gotInput.DisplayID)
+		}
+		if gotInput.Bio == nil || *gotInput.Bio != "映画が好きです" {
+			t.Fatalf("expected Bio to be set, got %v", gotInput.Bio)
+		}
+
+		resp := map[string]any{}
+		testutil.MustUnmarshalJSON(t, rw.Body.Bytes(), &resp)
+		if resp["display_id"] != "alice" {
+			t.Fatalf("expected display_id=alice, got %v", resp["display_id"])
+		}
+	})
+}
+
+func TestUserHandler_DeleteMe(t *testing.T) {
+	t.Parallel()
+
+	t.Run("未認証(user無し): 401", func(t *testing.T) {
+		t.Parallel()
+
+		r := newUserHandlerRouter(t, &fakeUserService{}, &fakeTagService{}, nil)
+		rw := testutil.PerformRequest(r, http.MethodDelete, "/api/v1/users/me", nil, nil)
+		if rw.Code != http.StatusUnauthorized {
+			t.Fatalf("expected 401, got %d", rw.Code)
+		}
+	})
+
+	t.Run("ボディ無しは既定ポリシー: 200", func(t *testing.T) {
+		t.Parallel()
+
+		purgeAt := time.Date(2026, 1, 31, 0, 0, 0, 0, time.UTC)
+		var gotPolicy service.AccountDeletionPolicy
+		userSvc := &fakeUserService{
+			DeactivateUserFn: func(ctx context.Context, userID string, policy service.AccountDeletionPolicy) (*model.User, error) {
+				gotPolicy = policy
+				return &model.User{ID: userID, DeletionStatus: model.UserDeletionStatusDeactivated, PurgeScheduledAt: &purgeAt}, nil
+			},
+		}
+
+		u := &model.User{ID: "u1"}
+		r := newUserHandlerRouter(t, userSvc, &fakeTagService{}, u)
+		rw := testutil.PerformRequest(r, http.MethodDelete, "/api/v1/users/me", nil, nil)
+		if rw.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d", rw.Code)
+		}
+		if gotPolicy != service.DefaultAccountDeletionPolicy() {
+			t.Fatalf("policy = %+v, want default policy", gotPolicy)
+		}
+
+		resp := map[string]any{}
+		testutil.MustUnmarshalJSON(t, rw.Body.Bytes(), &resp)
+		testutil.AssertJSON(t, resp, map[string]any{
+			"deletion_status":    "deactivated",
+			"purge_scheduled_at": "2026-01-31T00:00:00Z",
+		})
+	})
+
+	t.Run("譲渡先を display_id で指定: 200", func(t *testing.T) {
+		t.Parallel()
+
+		var gotPolicy service.AccountDeletionPolicy
+		userSvc := &fakeUserService{
+			GetUserByDisplayIDFn: func(ctx context.Context, displayID string) (*model.User, error) {
+				return &model.User{ID: "u2", DisplayID: displayID}, nil
+			},
+			DeactivateUserFn: func(ctx context.Context, userID string, policy service.AccountDeletionPolicy) (*model.User, error) {
+				gotPolicy = policy
+				return &model.User{ID: userID, DeletionStatus: model.UserDeletionStatusDeactivated}, nil
+			},
+		}
+
+		u := &model.User{ID: "u1"}
+		r := newUserHandlerRouter(t, userSvc, &fakeTagService{}, u)
+		body := []byte(`{"owned_tags":"transfer","transfer_to_display_id":"user2","contributions":"remove"}`)
+		rw := testutil.PerformRequest(r, http.MethodDelete, "/api/v1/users/me", body, nil)
+		if rw.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d", rw.Code)
+		}
+		want := service.AccountDeletionPolicy{
+			OwnedTags:        model.OwnedTagsPolicyTransfer,
+			TransferToUserID: "u2",
+			Contributions:    model.ContributionsPolicyRemove,
+		}
+		if gotPolicy != want {
+			t.Fatalf("policy = %+v, want %+v", gotPolicy, want)
+		}
+	})
+
+	t.Run("譲渡先が存在しない: 400", func(t *testing.T) {
+		t.Parallel()
+
+		u := &model.User{ID: "u1"}
+		r := newUserHandlerRouter(t, &fakeUserService{}, &fakeTagService{}, u)
+		body := []byte(`{"owned_tags":"transfer","transfer_to_display_id":"missing"}`)
+		rw := testutil.PerformRequest(r, http.MethodDelete, "/api/v1/users/me", body, nil)
+		if rw.Code != http.StatusBadRequest {
+			t.Fatalf("expected 400, got %d", rw.Code)
+		}
+	})
+
+	t.Run("不正なポリシー: 400", func(t *testing.T) {
+		t.Parallel()
+
+		userSvc := &fakeUserService{
+			DeactivateUserFn: func(ctx context.Context, userID string, policy service.AccountDeletionPolicy) (*model.User, error) {
+				return nil, service.ErrInvalidDeletionPolicy
+			},
+		}
+
+		u := &model.User{ID: "u1"}
+		r := newUserHandlerRouter(t, userSvc, &fakeTagService{}, u)
+		body := []byte(`{"contributions":"keep"}`)
+		rw := testutil.PerformRequest(r, http.MethodDelete, "/api/v1/users/me", body, nil)
+		if rw.Code != http.StatusBadRequest {
+			t.Fatalf("expected 400, got %d", rw.Code)
+		}
+	})
+}
+
+func TestUserHandler_ReactivateMe(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		err        error
+		wantStatus int
+	}{
+		{name: "成功: 200", err: nil, wantStatus: http.StatusOK},
+		{name: "退会状態でない: 409", err: service.ErrUserNotDeactivated, wantStatus: http.StatusConflict},
+		{name: "猶予期間切れ: 410", err: service.ErrReactivationPeriodExpired, wantStatus: http.StatusGone},
+		{name: "その他のエラー: 500", err: errors.New("db error"), wantStatus: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			userSvc := &fakeUserService{
+				ReactivateUserFn: func(ctx context.Context, userID string) (*model.User, error) {
+					if tt.err != nil {
+						return nil, tt.err
+					}
+					return &model.User{ID: userID, DisplayID: "user1"}, nil
+				},
+			}
+
+			u := &model.User{ID: "u1"}
+			r := newUserHandlerRouter(t, userSvc, &fakeTagService{}, u)
+			rw := testutil.PerformRequest(r, http.MethodPost, "/api/v1/users/me/reactivate", nil, nil)
+			if rw.Code != tt.wantStatus {
+				t.Fatalf("expected %d, got %d", tt.wantStatus, rw.Code)
+			}
+		})
+	}
 }
 
 func TestUserHandler_GetUserByDisplayID(t *testing.T) {
@@ -345,6 +683,30 @@ func TestUserHandler_GetUserByDisplayID(t *testing.T) {
 		}
 	})
 
+	t.Run("変更前の display_id: 現在の display_id へ 302 リダイレクト", func(t *testing.T) {
+		t.Parallel()
+
+		userSvc := &fakeUserService{
+			GetUserByDisplayIDFn: func(ctx context.Context, displayID string) (*model.User, error) {
+				return nil, service.ErrUserNotFound
+			},
+			ResolveRenamedDisplayIDFn: func(ctx context.Context, displayID string) (string, error) {
+				if displayID != "user-abc123" {
+					t.Fatalf("unexpected displayID: %s", displayID)
+				}
+				return "alice", nil
+			},
+		}
+
+		r := newUserHandlerRouter(t, userSvc, &fakeTagService{}, nil)
+		rw := testutil.PerformRequest(r, http.MethodGet, "/api/v1/users/user-abc123", nil, nil)
+		if rw.Code != http.StatusFound {
+			t.Fatalf("expected 302, got %d", rw.Code)
+		}
+		if loc := rw.Header().Get("Location"); loc != "/api/v1/users/alice" {
+			t.Fatalf("expected Location=/api/v1/users/alice, got %s", loc)
+		}
+	})
 	t.Run("サービスが失敗: 500", func(t *testing.T) {
 		t.Parallel()
 
@@ -427,8 +789,8 @@ func TestUserHandler_FollowUser(t *testing.T) {
 			GetUserByDisplayIDFn: func(ctx context.Context, displayID string) (*model.User, error) {
 				return &model.User{ID: "u1", DisplayID: displayID}, nil
 			},
-			FollowUserFn: func(ctx context.Context, followerID, followeeID string) error {
-				return service.ErrCannotFollowSelf
+			FollowUserFn: func(ctx context.Context, followerID, followeeID string) (service.FollowStatus, error) {
+				return "", service.ErrCannotFollowSelf
 			},
 		}
 
@@ -447,8 +809,8 @@ func TestUserHandler_FollowUser(t *testing.T) {
 			GetUserByDisplayIDFn: func(ctx context.Context, displayID string) (*model.User, error) {
 				return &model.User{ID: "u2", DisplayID: displayID}, nil
 			},
-			FollowUserFn: func(ctx context.Context, followerID, followeeID string) error {
-				return service.ErrAlreadyFollowing
+			FollowUserFn: func(ctx context.Context, followerID, followeeID string) (service.FollowStatus, error) {
+				return "", service.ErrAlreadyFollowing
 			},
 		}
 
@@ -468,10 +830,10 @@ func TestUserHandler_FollowUser(t *testing.T) {
 			GetUserByDisplayIDFn: func(ctx context.Context, displayID string) (*model.User, error) {
 				return &model.User{ID: "u2", DisplayID: displayID}, nil
 			},
-			FollowUserFn: func(ctx context.Context, followerID, followeeID string) error {
+			FollowUserFn: func(ctx context.Context, followerID, followeeID string) (service.FollowStatus, error) {
 				gotFollowerID = followerID
 				gotFolloweeID = followeeID
-				return nil
+				return service.FollowStatusFollowing, nil
 			},
 		}
 
@@ -487,6 +849,106 @@ func TestUserHandler_FollowUser(t *testing.T) {
 	})
 }
 
+func TestUserHandler_FollowUser_Private(t *testing.T) {
+	t.Parallel()
+
+	getTarget := func(ctx context.Context, displayID string) (*model.User, error) {
+		return &model.User{ID: "u2", DisplayID: displayID, IsPrivate: true}, nil
+	}
+
+	t.Run("非公開アカウント: 200 かつ status=requested", func(t *testing.T) {
+		t.Parallel()
+
+		userSvc := &fakeUserService{
+			GetUserByDisplayIDFn: getTarget,
+			FollowUserFn: func(ctx context.Context, followerID, followeeID string) (service.FollowStatus, error) {
+				return service.FollowStatusRequested, nil
+			},
+		}
+
+		u := &model.User{ID: "u1"}
+		r := newUserHandlerRouter(t, userSvc, &fakeTagService{}, u)
+		rw := testutil.PerformRequest(r, http.MethodPost, "/api/v1/users/user2/follow", nil, nil)
+		if rw.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d", rw.Code)
+		}
+
+		resp := map[string]any{}
+		testutil.MustUnmarshalJSON(t, rw.Body.Bytes(), &resp)
+		if resp["status"] != "requested" {
+			t.Fatalf("expected status=requested, got %v", resp["status"])
+		}
+	})
+
+	t.Run("リクエスト送信済み: 409", func(t *testing.T) {
+		t.Parallel()
+
+		userSvc := &fakeUserService{
+			GetUserByDisplayIDFn: getTarget,
+			FollowUserFn: func(ctx context.Context, followerID, followeeID string) (service.FollowStatus, error) {
+				return "", service.ErrFollowRequestAlreadySent
+			},
+		}
+
+		u := &model.User{ID: "u1"}
+		r := newUserHandlerRouter(t, userSvc, &fakeTagService{}, u)
+		rw := testutil.PerformRequest(r, http.MethodPost, "/api/v1/users/user2/follow", nil, nil)
+		if rw.Code != http.StatusConflict {
+			t.Fatalf("expected 409, got %d", rw.Code)
+		}
+	})
+}
+
+func TestUserHandler_ApproveFollowRequest(t *testing.T) {
+	t.Parallel()
+
+	getRequester := func(ctx context.Context, displayID string) (*model.User, error) {
+		return &model.User{ID: "u2", DisplayID: displayID}, nil
+	}
+
+	t.Run("リクエストが存在しない: 404", func(t *testing.T) {
+		t.Parallel()
+
+		userSvc := &fakeUserService{
+			GetUserByDisplayIDFn: getRequester,
+			ApproveFollowRequestFn: func(ctx context.Context, targetID, requesterID string) error {
+				return service.ErrFollowRequestNotFound
+			},
+		}
+
+		u := &model.User{ID: "u1"}
+		r := newUserHandlerRouter(t, userSvc, &fakeTagService{}, u)
+		rw := testutil.PerformRequest(r, http.MethodPost, "/api/v1/me/follow-requests/user2/approve", nil, nil)
+		if rw.Code != http.StatusNotFound {
+			t.Fatalf("expected 404, got %d", rw.Code)
+		}
+	})
+
+	t.Run("成功: 200", func(t *testing.T) {
+		t.Parallel()
+
+		var gotTargetID, gotRequesterID string
+		userSvc := &fakeUserService{
+			GetUserByDisplayIDFn: getRequester,
+			ApproveFollowRequestFn: func(ctx context.Context, targetID, requesterID string) error {
+				gotTargetID = targetID
+				gotRequesterID = requesterID
+				return nil
+			},
+		}
+
+		u := &model.User{ID: "u1"}
+		r := newUserHandlerRouter(t, userSvc, &fakeTagService{}, u)
+		rw := testutil.PerformRequest(r, http.MethodPost, "/api/v1/me/follow-requests/user2/approve", nil, nil)
+		if rw.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d", rw.Code)
+		}
+		if gotTargetID != "u1" || gotRequesterID != "u2" {
+			t.Fatalf("unexpected args: targetID=%s requesterID=%s", gotTargetID, gotRequesterID)
+		}
+	})
+}
+
 func TestUserHandler_UnfollowUser(t *testing.T) {
 	t.Parallel()
 
@@ -682,6 +1144,78 @@ func TestUserHandler_ListUserTags(t *testing.T) {
 	})
 }
 
+func TestUserHandler_SearchUsers(t *testing.T) {
+	t.Parallel()
+
+	t.Run("q が空: 400", func(t *testing.T) {
+		t.Parallel()
+
+		userSvc := &fakeUserService{
+			SearchUsersFn: func(ctx context.Context, viewerID, query string, page, pageSize int) ([]service.UserSearchResult, int64, error) {
+				return nil, 0, service.ErrEmptySearchQuery
+			},
+		}
+
+		r := newUserHandlerRouter(t, userSvc, &fakeTagService{}, nil)
+		rw := testutil.PerformRequest(r, http.MethodGet, "/api/v1/users/search", nil, nil)
+		if rw.Code != http.StatusBadRequest {
+			t.Fatalf("expected 400, got %d", rw.Code)
+		}
+	})
+
+	t.Run("サービスが失敗: 500", func(t *testing.T) {
+		t.Parallel()
+
+		userSvc := &fakeUserService{
+			SearchUsersFn: func(ctx context.Context, viewerID, query string, page, pageSize int) ([]service.UserSearchResult, int64, error) {
+				return nil, 0, errors.New("db error")
+			},
+		}
+
+		r := newUserHandlerRouter(t, userSvc, &fakeTagService{}, nil)
+		rw := testutil.PerformRequest(r, http.MethodGet, "/api/v1/users/search?q=taro", nil, nil)
+		if rw.Code != http.StatusInternalServerError {
+			t.Fatalf("expected 500, got %d", rw.Code)
+		}
+	})
+
+	t.Run("成功: 200（閲覧者IDとフォロー状態を返す）", func(t *testing.T) {
+		t.Parallel()
+
+		var gotViewerID, gotQuery string
+		var gotPage, gotPageSize int
+		userSvc := &fakeUserService{
+			SearchUsersFn: func(ctx context.Context, viewerID, query string, page, pageSize int) ([]service.UserSearchResult, int64, error) {
+				gotViewerID, gotQuery, gotPage, gotPageSize = viewerID, query, page, pageSize
+				return []service.UserSearchResult{
+					{User: &model.User{ID: "u2", DisplayID: "taro", DisplayName: "Taro"}, FollowerCount: 5, IsFollowing: true},
+				}, 1, nil
+			},
+		}
+
+		viewer := &model.User{ID: "u1", DisplayID: "viewer"}
+		r := newUserHandlerRouter(t, userSvc, &fakeTagService{}, viewer)
+		rw := testutil.PerformRequest(r, http.MethodGet, "/api/v1/users/search?q=taro&page=2&page_size=10", nil, nil)
+		if rw.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d", rw.Code)
+		}
+		if gotViewerID != "u1" || gotQuery != "taro" || gotPage != 2 || gotPageSize != 10 {
+			t.Fatalf("unexpected args: viewer=%q query=%q page=%d page_size=%d", gotViewerID, gotQuery, gotPage, gotPageSize)
+		}
+
+		resp := map[string]any{}
+		testutil.MustUnmarshalJSON(t, rw.Body.Bytes(), &resp)
+		if resp["total_count"] != float64(1) {
+			t.Fatalf("expected total_count 1, got %v", resp["total_count"])
+		}
+		items := resp["items"].([]any)
+		item := items[0].(map[string]any)
+		if item["display_id"] != "taro" || item["followers_count"] != float64(5) || item["is_following"] != true {
+			t.Fatalf("unexpected item: %v", item)
+		}
+	})
+}
+
 func TestUserHandler_ListFollowing(t *testing.T) {
 	t.Parallel()
 
@@ -808,6 +1342,34 @@ func TestUserHandler_ListFollowers(t *testing.T) {
 		}
 	})
 
+	t.Run("非公開アカウントで閲覧権限なし: 403", func(t *testing.T) {
+		t.Parallel()
+
+		listCalled := false
+		userSvc := &fakeUserService{
+			GetUserByDisplayIDFn: func(ctx context.Context, displayID string) (*model.User, error) {
+				return &model.User{ID: "u1", DisplayID: displayID, IsPrivate: true}, nil
+			},
+			CanViewUserContentFn: func(ctx context.Context, viewerID string, owner *model.User) (bool, error) {
+				return false, nil
+			},
+			ListFollowersFn: func(ctx context.Context, userID string, page, pageSize int) ([]*model.User, int64, error) {
+				listCalled = true
+				return []*model.User{}, 0, nil
+			},
+		}
+
+		u := &model.User{ID: "u2", DisplayID: "user2"}
+		r := newUserHandlerRouter(t, userSvc, &fakeTagService{}, u)
+		rw := testutil.PerformRequest(r, http.MethodGet, "/api/v1/users/user1/followers", nil, nil)
+		if rw.Code != http.StatusForbidden {
+			t.Fatalf("expected 403, got %d", rw.Code)
+		}
+		if listCalled {
+			t.Fatalf("expected ListFollowers not to be called")
+		}
+	})
+
 	t.Run("成功: 200", func(t *testing.T) {
 		t.Parallel()
 
@@ -945,3 +1507,258 @@ func TestUserHandler_GetUserFollowStats(t *testing.T) {
 		}
 	})
 }
+
+func TestUserHandler_BlockUser(t *testing.T) {
+	t.Parallel()
+
+	target := &model.User{ID: "u2", DisplayID: "user2", DisplayName: "User2"}
+	getTarget := func(ctx context.Context, displayID string) (*model.User, error) {
+		return target, nil
+	}
+
+	t.Run("未認証(user無し): 401", func(t *testing.T) {
+		t.Parallel()
+
+		r := newUserHandlerRouter(t, &fakeUserService{GetUserByDisplayIDFn: getTarget}, &fakeTagService{}, nil)
+		rw := testutil.PerformRequest(r, http.MethodPost, "/api/v1/users/user2/block", nil, nil)
+		if rw.Code != http.StatusUnauthorized {
+			t.Fatalf("expected 401, got %d", rw.Code)
+		}
+	})
+
+	t.Run("既にブロック済み: 409", func(t *testing.T) {
+		t.Parallel()
+
+		userSvc := &fakeUserService{
+			GetUserByDisplayIDFn: getTarget,
+			BlockUserFn: func(ctx context.Context, blockerID, blockedID string) error {
+				return service.ErrAlreadyBlocked
+			},
+		}
+		u := &model.User{ID: "u1", DisplayID: "user1"}
+		r := newUserHandlerRouter(t, userSvc, &fakeTagService{}, u)
+		rw := testutil.PerformRequest(r, http.MethodPost, "/api/v1/users/user2/block", nil, nil)
+		if rw.Code != http.StatusConflict {
+			t.Fatalf("expected 409, got %d", rw.Code)
+		}
+	})
+
+	t.Run("成功: 200", func(t *testing.T) {
+		t.Parallel()
+
+		var gotBlocker, gotBlocked string
+		userSvc := &fakeUserService{
+			GetUserByDisplayIDFn: getTarget,
+			BlockUserFn: func(ctx context.Context, blockerID, blockedID string) error {
+				gotBlocker, gotBlocked = blockerID, blockedID
+				return nil
+			},
+		}
+		u := &model.User{ID: "u1", DisplayID: "user1"}
+		r := newUserHandlerRouter(t, userSvc, &fakeTagService{}, u)
+		rw := testutil.PerformRequest(r, http.MethodPost, "/api/v1/users/user2/block", nil, nil)
+		if rw.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d", rw.Code)
+		}
+		if gotBlocker != "u1" || gotBlocked != "u2" {
+			t.Fatalf("unexpected args: %s, %s", gotBlocker, gotBlocked)
+		}
+	})
+
+	t.Run("ブロック中のユーザーをフォロー: 403", func(t *testing.T) {
+		t.Parallel()
+
+		userSvc := &fakeUserService{
+			GetUserByDisplayIDFn: getTarget,
+			FollowUserFn: func(ctx context.Context, followerID, followeeID string) (service.FollowStatus, error) {
+				return "", service.ErrUserBlocked
+			},
+		}
+		u := &model.User{ID: "u1", DisplayID: "user1"}
+		r := newUserHandlerRouter(t, userSvc, &fakeTagService{}, u)
+		rw := testutil.PerformRequest(r, http.MethodPost, "/api/v1/users/user2/follow", nil, nil)
+		if rw.Code != http.StatusForbidden {
+			t.Fatalf("expected 403, got %d", rw.Code)
+		}
+	})
+}
+
+func TestUserHandler_ListBlockedUsers(t *testing.T) {
+	t.Parallel()
+
+	t.Run("成功: 200 かつブロック中のユーザー一覧が返る", func(t *testing.T) {
+		t.Parallel()
+
+		userSvc := &fakeUserService{
+			ListBlockedUsersFn: func(ctx context.Context, userID string, page, pageSize int) ([]*model.User, int64, error) {
+				return []*model.User{{ID: "u2", DisplayID: "user2", DisplayName: "User2"}}, 1, nil
+			},
+		}
+		u := &model.User{ID: "u1", DisplayID: "user1"}
+		r := newUserHandlerRouter(t, userSvc, &fakeTagService{}, u)
+		rw := testutil.PerformRequest(r, http.MethodGet, "/api/v1/me/blocks", nil, nil)
+		if rw.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d", rw.Code)
+		}
+
+		resp := map[string]any{}
+		testutil.MustUnmarshalJSON(t, rw.Body.Bytes(), &resp)
+		items, ok := resp["items"].([]any)
+		if !ok || len(items) != 1 {
+			t.Fatalf("expected 1 item, got %v", resp["items"])
+		}
+		if resp["total_count"] != float64(1) {
+			t.Fatalf("expected total_count=1, got %v", resp["total_count"])
+		}
+	})
+}
+
+func TestUserHandler_ListUserSuggestions(t *testing.T) {
+	t.Parallel()
+
+	t.Run("未認証(user無し): 401", func(t *testing.T) {
+		t.Parallel()
+
+		r := newUserHandlerRouter(t, &fakeUserService{}, &fakeTagService{}, nil)
+		rw := testutil.PerformRequest(r, http.MethodGet, "/api/v1/me/suggestions/users", nil, nil)
+		if rw.Code != http.StatusUnauthorized {
+			t.Fatalf("expected 401, got %d", rw.Code)
+		}
+	})
+
+	t.Run("成功: 200 かつ理由付きの候補が返る", func(t *testing.T) {
+		t.Parallel()
+
+		var gotUserID string
+		var gotLimit int
+		userSvc := &fakeUserService{
+			SuggestUsersFn: func(ctx context.Context, userID string, limit int) ([]service.UserSuggestion, error) {
+				gotUserID, gotLimit = userID, limit
+				return []service.UserSuggestion{
+					{User: &model.User{ID: "u2", DisplayID: "user2"}, Reason: service.SuggestionReasonSharedTags, SharedTagCount: 3},
+				}, nil
+			},
+		}
+		u := &model.User{ID: "u1", DisplayID: "user1"}
+		r := newUserHandlerRouter(t, userSvc, &fakeTagService{}, u)
+		rw := testutil.PerformRequest(r, http.MethodGet, "/api/v1/me/suggestions/users?limit=5", nil, nil)
+		if rw.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d", rw.Code)
+		}
+		if gotUserID != "u1" || gotLimit != 5 {
+			t.Fatalf("unexpected args: user_id=%q limit=%d", gotUserID, gotLimit)
+		}
+
+		resp := map[string]any{}
+		testutil.MustUnmarshalJSON(t, rw.Body.Bytes(), &resp)
+		items, ok := resp["items"].([]any)
+		if !ok || len(items) != 1 {
+			t.Fatalf("expected 1 item, got %v", resp["items"])
+		}
+		item := items[0].(map[string]any)
+		if item["display_id"] != "user2" || item["reason"] != "shared_tags" || item["shared_tag_count"] != float64(3) {
+			t.Fatalf("unexpected item: %v", item)
+		}
+	})
+
+	t.Run("サービスが失敗: 500", func(t *testing.T) {
+		t.Parallel()
+
+		userSvc := &fakeUserService{
+			SuggestUsersFn: func(ctx context.Context, userID string, limit int) ([]service.UserSuggestion, error) {
+				return nil, errors.New("db error")
+			},
+		}
+		u := &model.User{ID: "u1", DisplayID: "user1"}
+		r := newUserHandlerRouter(t, userSvc, &fakeTagService{}, u)
+		rw := testutil.PerformRequest(r, http.MethodGet, "/api/v1/me/suggestions/users", nil, nil)
+		if rw.Code != http.StatusInternalServerError {
+			t.Fatalf("expected 500, got %d", rw.Code)
+		}
+	})
+}
+
+func TestUserHandler_GetUserStats(t *testing.T) {
+	t.Parallel()
+
+	t.Run("ユーザーが見つからない: 404", func(t *testing.T) {
+		t.Parallel()
+
+		r := newUserHandlerRouter(t, &fakeUserService{}, &fakeTagService{}, nil)
+		rw := testutil.PerformRequest(r, http.MethodGet, "/api/v1/users/unknown/stats", nil, nil)
+		if rw.Code != http.StatusNotFound {
+			t.Fatalf("expected 404, got %d", rw.Code)
+		}
+	})
+
+	t.Run("非公開アカウント: 403", func(t *testing.T) {
+		t.Parallel()
+
+		userSvc := &fakeUserService{
+			GetUserByDisplayIDFn: func(ctx context.Context, displayID string) (*model.User, error) {
+				return &model.User{ID: "u2", DisplayID: displayID, IsPrivate: true}, nil
+			},
+			CanViewUserContentFn: func(ctx context.Context, viewerID string, owner *model.User) (bool, error) {
+				return false, nil
+			},
+		}
+		r := newUserHandlerRouter(t, userSvc, &fakeTagService{}, nil)
+		rw := testutil.PerformRequest(r, http.MethodGet, "/api/v1/users/user2/stats", nil, nil)
+		if rw.Code != http.StatusForbidden {
+			t.Fatalf("expected 403, got %d", rw.Code)
+		}
+	})
+
+	tests := []struct {
+		name           string
+		viewer         *model.User
+		wantPublicOnly bool
+	}{
+		{name: "他ユーザー: 公開タグのみ集計", viewer: &model.User{ID: "u1"}, wantPublicOnly: true},
+		{name: "未ログイン: 公開タグのみ集計", viewer: nil, wantPublicOnly: true},
+		{name: "本人: 非公開タグも集計", viewer: &model.User{ID: "u2"}, wantPublicOnly: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			userSvc := &fakeUserService{
+				GetUserByDisplayIDFn: func(ctx context.Context, displayID string) (*model.User, error) {
+					return &model.User{ID: "u2", DisplayID: displayID}, nil
+				},
+				GetFollowStatsFn: func(ctx context.Context, userID string) (int64, int64, error) {
+					return 7, 8, nil
+				},
+			}
+			var gotPublicOnly bool
+			tagSvc := &fakeTagService{
+				GetUserTagStatsFn: func(ctx context.Context, userID string, publicOnly bool) (*service.UserTagStats, error) {
+					gotPublicOnly = publicOnly
+					return &service.UserTagStats{
+						TagCount:   3,
+						TopGenres:  []service.GenreStat{{Name: "SF", MovieCount: 2}},
+						TopDecades: []service.DecadeStat{},
+					}, nil
+				},
+			}
+			r := newUserHandlerRouter(t, userSvc, tagSvc, tt.viewer)
+			rw := testutil.PerformRequest(r, http.MethodGet, "/api/v1/users/user2/stats", nil, nil)
+			if rw.Code != http.StatusOK {
+				t.Fatalf("expected 200, got %d", rw.Code)
+			}
+			if gotPublicOnly != tt.wantPublicOnly {
+				t.Fatalf("expected publicOnly=%v, got %v", tt.wantPublicOnly, gotPublicOnly)
+			}
+
+			resp := map[string]any{}
+			testutil.MustUnmarshalJSON(t, rw.Body.Bytes(), &resp)
+			if resp["tag_count"] != float64(3) || resp["following_count"] != float64(7) || resp["followers_count"] != float64(8) {
+				t.Fatalf("unexpected response: %v", resp)
+			}
+			genres, ok := resp["top_genres"].([]any)
+			if !ok || len(genres) != 1 {
+				t.Fatalf("expected 1 genre, got %v", resp["top_genres"])
+			}
+		})
+	}
+}
@@ -0,0 +1,188 @@
+package handler
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"cinetag-backend/src/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Webhook 関連の HTTP ハンドラー。
+type WebhookHandler struct {
+	logger         *slog.Logger
+	webhookService service.WebhookService
+}
+
+// WebhookHandler を初期化して返す。
+func NewWebhookHandler(logger *slog.Logger, webhookService service.WebhookService) *WebhookHandler {
+	return &WebhookHandler{
+		logger:         logger,
+		webhookService: webhookService,
+	}
+}
+
+// Webhook 作成のリクエストボディ。
+type createWebhookRequest struct {
+	TagID  *string  `json:"tag_id"`
+	URL    string   `json:"url" binding:"required"`
+	Events []string `json:"events" binding:"required"`
+	Secret *string  `json:"secret"`
+}
+
+// Webhook 更新のリクエストボディ。
+type updateWebhookRequest struct {
+	URL      *string   `json:"url"`
+	Events   *[]string `json:"events"`
+	IsActive *bool     `json:"is_active"`
+}
+
+// CreateWebhook は Webhook を作成する。署名鍵はこのレスポンスでのみ返す。
+// POST /api/v1/me/webhooks
+func (h *WebhookHandler) CreateWebhook(c *gin.Context) {
+	user := getUserFromContext(c)
+	if user == nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	var req createWebhookRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body"})
+		return
+	}
+
+	created, err := h.webhookService.CreateWebhook(c.Request.Context(), user.ID, service.CreateWebhookInput{
+		TagID:  req.TagID,
+		URL:    req.URL,
+		Events: req.Events,
+		Secret: req.Secret,
+	})
+	if err != nil {
+		h.writeWebhookError(c, "handler.CreateWebhook", user.ID, err, "failed to create webhook")
+		return
+	}
+
+	c.JSON(http.StatusCreated, created)
+}
+
+// ListWebhooks は自分が登録した Webhook の一覧を取得する。
+// GET /api/v1/me/webhooks
+func (h *WebhookHandler) ListWebhooks(c *gin.Context) {
+	user := getUserFromContext(c)
+	if user == nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	webhooks, err := h.webhookService.ListWebhooks(c.Request.Context(), user.ID)
+	if err != nil {
+		h.writeWebhookError(c, "handler.ListWebhooks", user.ID, err, "failed to list webhooks")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"items": webhooks})
+}
+
+// UpdateWebhook は Webhook の URL・イベント・有効/停止を更新する。
+// PATCH /api/v1/me/webhooks/:webhookId
+func (h *WebhookHandler) UpdateWebhook(c *gin.Context) {
+	user := getUserFromContext(c)
+	if user == nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	var req updateWebhookRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body"})
+		return
+	}
+
+	updated, err := h.webhookService.UpdateWebhook(c.Request.Context(), c.Param("webhookId"), user.ID, service.UpdateWebhookPatch{
+		URL:      req.URL,
+		Events:   req.Events,
+		IsActive: req.IsActive,
+	})
+	if err != nil {
+		h.writeWebhookError(c, "handler.UpdateWebhook", user.ID, err, "failed to update webhook")
+		return
+	}
+
+	c.JSON(http.StatusOK, updated)
+}
+
+// DeleteWebhook は Webhook を削除する。
+// DELETE /api/v1/me/webhooks/:webhookId
+func (h *WebhookHandler) DeleteWebhook(c *gin.Context) {
+	user := getUserFromContext(c)
+	if user == nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	if err := h.webhookService.DeleteWebhook(c.Request.Context(), c.Param("webhookId"), user.ID); err != nil {
+		h.writeWebhookError(c, "handler.DeleteWebhook", user.ID, err, "failed to delete webhook")
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// ListWebhookDeliveries は Webhook の配信ログを新しい順で取得する。
+// GET /api/v1/me/webhooks/:webhookId/deliveries?page={page}&page_size={page_size}
+func (h *WebhookHandler) ListWebhookDeliveries(c *gin.Context) {
+	user := getUserFromContext(c)
+	if user == nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	page := parseIntDefault(c.Query("page"), 1)
+	pageSize := parseIntDefault(c.Query("page_size"), 20)
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 || pageSize > 100 {
+		pageSize = 20
+	}
+
+	items, total, err := h.webhookService.ListDeliveries(c.Request.Context(), c.Param("webhookId"), user.ID, page, pageSize)
+	if err != nil {
+		h.writeWebhookError(c, "handler.ListWebhookDeliveries", user.ID, err, "failed to list webhook deliveries")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"items":       items,
+		"page":        page,
+		"page_size":   pageSize,
+		"total_count": total,
+	})
+}
+
+// Webhook のサービスのエラーをレスポンスに変換する。想定外のエラーはログに出して 500 を返す。
+func (h *WebhookHandler) writeWebhookError(c *gin.Context, op, userID string, err error, failedMessage string) {
+	switch {
+	case errors.Is(err, service.ErrInvalidWebhookURL),
+		errors.Is(err, service.ErrInvalidWebhookEvents),
+		errors.Is(err, service.ErrInvalidWebhookSecret):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrWebhookLimitExceeded):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrWebhookNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "webhook not found"})
+	case errors.Is(err, service.ErrTagNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "tag not found"})
+	case errors.Is(err, service.ErrTagPermissionDenied):
+		c.JSON(http.StatusForbidden, gin.H{"error": "forbidden"})
+	default:
+		h.logger.Error(op+" failed",
+			slog.String("user_id", userID),
+			slog.Any("error", err),
+		)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": failedMessage})
+	}
+}
@@ -0,0 +1,262 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"cinetag-backend/src/internal/model"
+	"cinetag-backend/src/internal/service"
+	"cinetag-backend/src/internal/testutil"
+
+	"github.com/gin-gonic/gin"
+)
+
+type fakeWebhookService struct {
+	CreateWebhookFn  func(ctx context.Context, userID string, in service.CreateWebhookInput) (*service.CreatedWebhook, error)
+	ListWebhooksFn   func(ctx context.Context, userID string) ([]*model.Webhook, error)
+	UpdateWebhookFn  func(ctx context.Context, webhookID, userID string, patch service.UpdateWebhookPatch) (*model.Webhook, error)
+	DeleteWebhookFn  func(ctx context.Context, webhookID, userID string) error
+	ListDeliveriesFn func(ctx context.Context, webhookID, userID string, page, pageSize int) ([]*model.WebhookDelivery, int64, error)
+}
+
+func (f *fakeWebhookService) CreateWebhook(ctx context.Context, userID string, in service.CreateWebhookInput) (*service.CreatedWebhook, error) {
+	if f.CreateWebhookFn == nil {
+		return &service.CreatedWebhook{Webhook: &model.Webhook{ID: "w1", UserID: userID}, Secret: "whsec_test"}, nil
+	}
+	return f.CreateWebhookFn(ctx, userID, in)
+}
+
+func (f *fakeWebhookService) ListWebhooks(ctx context.Context, userID string) ([]*model.Webhook, error) {
+	if f.ListWebhooksFn == nil {
+		return []*model.Webhook{}, nil
+	}
+	return f.ListWebhooksFn(ctx, userID)
+}
+
+func (f *fakeWebhookService) UpdateWebhook(ctx context.Context, webhookID, userID string, patch service.UpdateWebhookPatch) (*model.Webhook, error) {
+	if f.UpdateWebhookFn == nil {
+		return &model.Webhook{ID: webhookID, UserID: userID}, nil
+	}
+	return f.UpdateWebhookFn(ctx, webhookID, userID, patch)
+}
+
+func (f *fakeWebhookService) DeleteWebhook(ctx context.Context, webhookID, userID string) error {
+	if f.DeleteWebhookFn == nil {
+		return nil
+	}
+	return f.DeleteWebhookFn(ctx, webhookID, userID)
+}
+
+func (f *fakeWebhookService) ListDeliveries(ctx context.Context, webhookID, userID string, page, pageSize int) ([]*model.WebhookDelivery, int64, error) {
+	if f.ListDeliveriesFn == nil {
+		return []*model.WebhookDelivery{}, 0, nil
+	}
+	return f.ListDeliveriesFn(ctx, webhookID, userID, page, pageSize)
+}
+
+func (f *fakeWebhookService) FanOut(ctx context.Context, event *model.OutboxEvent) error {
+	return nil
+}
+
+func (f *fakeWebhookService) Deliver(ctx context.Context, event *model.OutboxEvent) error {
+	return nil
+}
+
+func newUserWebhookHandlerRouter(t *testing.T, svc service.WebhookService, user *model.User) *gin.Engine {
+	t.Helper()
+	r := testutil.NewTestRouter()
+	h := NewWebhookHandler(testutil.NewTestLogger(), svc)
+
+	auth := r.Group("/api/v1")
+	if user != nil {
+		auth.Use(func(c *gin.Context) {
+			c.Set("user", user)
+			c.Next()
+		})
+	}
+	auth.GET("/me/webhooks", h.ListWebhooks)
+	auth.POST("/me/webhooks", h.CreateWebhook)
+	auth.PATCH("/me/webhooks/:webhookId", h.UpdateWebhook)
+	auth.DELETE("/me/webhooks/:webhookId", h.DeleteWebhook)
+	auth.GET("/me/webhooks/:webhookId/deliveries", h.ListWebhookDeliveries)
+
+	return r
+}
+
+func TestWebhookHandler_CreateWebhook(t *testing.T) {
+	t.Parallel()
+
+	jsonHeader := map[string]string{"Content-Type": "application/json"}
+
+	t.Run("未認証は401", func(t *testing.T) {
+		t.Parallel()
+
+		r := newUserWebhookHandlerRouter(t, &fakeWebhookService{}, nil)
+		rr := testutil.PerformRequest(r, http.MethodPost, "/api/v1/me/webhooks", []byte(`{}`), jsonHeader)
+		if rr.Code != http.StatusUnauthorized {
+			t.Fatalf("expected 401, got %d", rr.Code)
+		}
+	})
+
+	t.Run("作成して署名鍵を返す", func(t *testing.T) {
+		t.Parallel()
+
+		var got service.CreateWebhookInput
+		svc := &fakeWebhookService{
+			CreateWebhookFn: func(ctx context.Context, userID string, in service.CreateWebhookInput) (*service.CreatedWebhook, error) {
+				got = in
+				return &service.CreatedWebhook{Webhook: &model.Webhook{ID: "w1", UserID: userID, TagID: in.TagID, URL: in.URL, Secret: "whsec_test"}, Secret: "whsec_test"}, nil
+			},
+		}
+		r := newUserWebhookHandlerRouter(t, svc, &model.User{ID: "u1"})
+		rr := testutil.PerformRequest(r, http.MethodPost, "/api/v1/me/webhooks",
+			[]byte(`{"tag_id":"t1","url":"https://example.com/hook","events":["movie_added"]}`), jsonHeader)
+		if rr.Code != http.StatusCreated {
+			t.Fatalf("expected 201, got %d: %s", rr.Code, rr.Body.String())
+		}
+		if got.TagID == nil || *got.TagID != "t1" || got.URL != "https://example.com/hook" || len(got.Events) != 1 {
+			t.Fatalf("unexpected input: %+v", got)
+		}
+		var body map[string]any
+		testutil.MustUnmarshalJSON(t, rr.Body.Bytes(), &body)
+		if body["id"] != "w1" || body["secret"] != "whsec_test" || body["tag_id"] != "t1" {
+			t.Fatalf("unexpected body: %v", body)
+		}
+	})
+
+	t.Run("エラーをステータスコードに変換する", func(t *testing.T) {
+		t.Parallel()
+
+		cases := []struct {
+			err  error
+			want int
+		}{
+			{err: service.ErrInvalidWebhookURL, want: http.StatusBadRequest},
+			{err: service.ErrInvalidWebhookEvents, want: http.StatusBadRequest},
+			{err: service.ErrTagNotFound, want: http.StatusNotFound},
+			{err: service.ErrTagPermissionDenied, want: http.StatusForbidden},
+			{err: service.ErrWebhookLimitExceeded, want: http.StatusConflict},
+		}
+		for _, tc := range cases {
+			svc := &fakeWebhookService{
+				CreateWebhookFn: func(ctx context.Context, userID string, in service.CreateWebhookInput) (*service.CreatedWebhook, error) {
+					return nil, tc.err
+				},
+			}
+			r := newUserWebhookHandlerRouter(t, svc, &model.User{ID: "u1"})
+			rr := testutil.PerformRequest(r, http.MethodPost, "/api/v1/me/webhooks",
+				[]byte(`{"url":"https://example.com/hook","events":["followed"]}`), jsonHeader)
+			if rr.Code != tc.want {
+				t.Fatalf("%v: expected %d, got %d", tc.err, tc.want, rr.Code)
+			}
+		}
+	})
+
+	t.Run("URL・イベントの指定がない場合は400", func(t *testing.T) {
+		t.Parallel()
+
+		r := newUserWebhookHandlerRouter(t, &fakeWebhookService{}, &model.User{ID: "u1"})
+		for _, body := range []string{`{`, `{"events":["followed"]}`, `{"url":"https://example.com/hook"}`} {
+			rr := testutil.PerformRequest(r, http.MethodPost, "/api/v1/me/webhooks", []byte(body), jsonHeader)
+			if rr.Code != http.StatusBadRequest {
+				t.Fatalf("%s: expected 400, got %d", body, rr.Code)
+			}
+		}
+	})
+}
+
+func TestWebhookHandler_ManageWebhook(t *testing.T) {
+	t.Parallel()
+
+	t.Run("一覧では署名鍵を返さない", func(t *testing.T) {
+		t.Parallel()
+
+		svc := &fakeWebhookService{
+			ListWebhooksFn: func(ctx context.Context, userID string) ([]*model.Webhook, error) {
+				return []*model.Webhook{{ID: "w1", UserID: userID, Secret: "whsec_test"}}, nil
+			},
+		}
+		r := newUserWebhookHandlerRouter(t, svc, &model.User{ID: "u1"})
+		rr := testutil.PerformRequest(r, http.MethodGet, "/api/v1/me/webhooks", nil, nil)
+		if rr.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d", rr.Code)
+		}
+		var body struct {
+			Items []map[string]any `json:"items"`
+		}
+		testutil.MustUnmarshalJSON(t, rr.Body.Bytes(), &body)
+		if len(body.Items) != 1 || body.Items[0]["id"] != "w1" {
+			t.Fatalf("unexpected body: %s", rr.Body.String())
+		}
+		if _, ok := body.Items[0]["secret"]; ok {
+			t.Fatalf("secret must not be listed: %s", rr.Body.String())
+		}
+	})
+
+	t.Run("停止して更新後の Webhook を返す", func(t *testing.T) {
+		t.Parallel()
+
+		var got service.UpdateWebhookPatch
+		svc := &fakeWebhookService{
+			UpdateWebhookFn: func(ctx context.Context, webhookID, userID string, patch service.UpdateWebhookPatch) (*model.Webhook, error) {
+				got = patch
+				return &model.Webhook{ID: webhookID, UserID: userID, IsActive: *patch.IsActive}, nil
+			},
+		}
+		r := newUserWebhookHandlerRouter(t, svc, &model.User{ID: "u1"})
+		rr := testutil.PerformRequest(r, http.MethodPatch, "/api/v1/me/webhooks/w1", []byte(`{"is_active":false}`),
+			map[string]string{"Content-Type": "application/json"})
+		if rr.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d: %s", rr.Code, rr.Body.String())
+		}
+		if got.IsActive == nil || *got.IsActive || got.URL != nil || got.Events != nil {
+			t.Fatalf("unexpected patch: %+v", got)
+		}
+	})
+
+	t.Run("他のユーザーの Webhook は404", func(t *testing.T) {
+		t.Parallel()
+
+		svc := &fakeWebhookService{
+			DeleteWebhookFn: func(ctx context.Context, webhookID, userID string) error {
+				return service.ErrWebhookNotFound
+			},
+			ListDeliveriesFn: func(ctx context.Context, webhookID, userID string, page, pageSize int) ([]*model.WebhookDelivery, int64, error) {
+				return nil, 0, service.ErrWebhookNotFound
+			},
+		}
+		r := newUserWebhookHandlerRouter(t, svc, &model.User{ID: "u1"})
+		if rr := testutil.PerformRequest(r, http.MethodDelete, "/api/v1/me/webhooks/w1", nil, nil); rr.Code != http.StatusNotFound {
+			t.Fatalf("expected 404, got %d", rr.Code)
+		}
+		if rr := testutil.PerformRequest(r, http.MethodGet, "/api/v1/me/webhooks/w1/deliveries", nil, nil); rr.Code != http.StatusNotFound {
+			t.Fatalf("expected 404, got %d", rr.Code)
+		}
+	})
+
+	t.Run("配信ログをページングして返す", func(t *testing.T) {
+		t.Parallel()
+
+		var gotPage, gotPageSize int
+		svc := &fakeWebhookService{
+			ListDeliveriesFn: func(ctx context.Context, webhookID, userID string, page, pageSize int) ([]*model.WebhookDelivery, int64, error) {
+				gotPage, gotPageSize = page, pageSize
+				return []*model.WebhookDelivery{{ID: "l1", WebhookID: webhookID}}, 21, nil
+			},
+		}
+		r := newUserWebhookHandlerRouter(t, svc, &model.User{ID: "u1"})
+		rr := testutil.PerformRequest(r, http.MethodGet, "/api/v1/me/webhooks/w1/deliveries?page=2&page_size=500", nil, nil)
+		if rr.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d", rr.Code)
+		}
+		if gotPage != 2 || gotPageSize != 20 {
+			t.Fatalf("unexpected paging: page=%d page_size=%d", gotPage, gotPageSize)
+		}
+		var body map[string]any
+		testutil.MustUnmarshalJSON(t, rr.Body.Bytes(), &body)
+		if body["total_count"] != float64(21) {
+			t.Fatalf("unexpected body: %v", body)
+		}
+	})
+}
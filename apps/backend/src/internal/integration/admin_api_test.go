@@ -0,0 +1,175 @@
+//go:build integration
+
+package integration
+
+import (
+	"encoding/json"
+	"testing"
+
+	"cinetag-backend/src/internal/model"
+	"cinetag-backend/src/internal/testutil"
+)
+
+// 管理者用 API（/api/v1/admin）は admin ロールのユーザーのみが呼び出せ、X-Admin-Token では呼び出せないことを確認する。
+func TestAdmin_RoleGuard(t *testing.T) {
+	t.Setenv("ADMIN_API_TOKEN", "test-admin-token")
+	env := setupTestEnv(t)
+	user := env.createUser(t, "clerk_adm_guard1", "adm-guard-user", "AdmGuardUser")
+	target := env.createUser(t, "clerk_adm_guard2", "adm-guard-target", "AdmGuardTarget")
+	admin := env.createUser(t, "clerk_adm_guard3", "adm-guard-admin", "AdmGuardAdmin")
+	admin.Role = model.UserRoleAdmin
+
+	env.request("GET", "/api/v1/admin/users", nil, nil).AssertStatus(t, 401)
+	env.request("GET", "/api/v1/admin/users", nil, authHeaders(user.ID)).AssertStatus(t, 403)
+	env.request("GET", "/api/v1/admin/users", nil, map[string]string{"X-Admin-Token": "test-admin-token"}).AssertStatus(t, 401)
+
+	body, _ := json.Marshal(map[string]any{"role": "admin"})
+	resp := env.request("PATCH", "/api/v1/admin/users/"+user.ID+"/role", body, authHeaders(admin.ID))
+	resp.AssertStatus(t, 200)
+	testutil.AssertJSON(t, resp.JSON(t), map[string]any{"id": user.ID, "role": "admin"})
+	user.Role = model.UserRoleAdmin
+
+	resp = env.request("GET", "/api/v1/admin/users?q=adm-guard-target", nil, authHeaders(user.ID))
+	resp.AssertStatus(t, 200)
+	items, _ := resp.JSON(t)["items"].([]any)
+	if len(items) != 1 {
+		t.Fatalf("expected 1 user, got %v", items)
+	}
+	testutil.AssertJSON(t, items[0].(map[string]any), map[string]any{"id": target.ID, "role": "user"})
+
+	env.request("GET", "/api/v1/admin/users?role=admin", nil, authHeaders(user.ID)).AssertStatus(t, 200)
+	env.request("GET", "/api/v1/admin/users?status=banned", nil, authHeaders(user.ID)).AssertStatus(t, 400)
+
+	// 自分自身のロールは変更できない
+	body, _ = json.Marshal(map[string]any{"role": "user"})
+	env.request("PATCH", "/api/v1/admin/users/"+user.ID+"/role", body, authHeaders(user.ID)).AssertStatus(t, 403)
+}
+
+// POST /api/v1/admin/users/:userId/suspend, /restore
+// 利用停止中はプロフィールとタグが非表示になり、本人は退会を取り消せないことを確認する。
+func TestAdmin_SuspendAndRestoreUser(t *testing.T) {
+	env := setupTestEnv(t)
+	admin := env.createUser(t, "clerk_adm_sus1", "adm-sus-admin", "AdmSusAdmin")
+	target := env.createUser(t, "clerk_adm_sus2", "adm-sus-target", "AdmSusTarget")
+	admin.Role = model.UserRoleAdmin
+
+	tagBody, _ := json.Marshal(map[string]any{"title": "利用停止ユーザーのタグ", "is_public": true})
+	resp := env.request("POST", "/api/v1/tags", tagBody, authHeaders(target.ID))
+	resp.AssertStatus(t, 201)
+	tagID, _ := resp.JSON(t)["id"].(string)
+
+	env.request("POST", "/api/v1/admin/users/"+admin.ID+"/suspend", nil, authHeaders(admin.ID)).AssertStatus(t, 403)
+	env.request("POST", "/api/v1/admin/users/"+target.ID+"/restore", nil, authHeaders(admin.ID)).AssertStatus(t, 409)
+
+	resp = env.request("POST", "/api/v1/admin/users/"+target.ID+"/suspend", nil, authHeaders(admin.ID))
+	resp.AssertStatus(t, 200)
+	testutil.AssertJSON(t, resp.JSON(t), map[string]any{"deletion_status": "deactivated"})
+	testutil.AssertHasKeys(t, resp.JSON(t), "suspended_at")
+
+	env.request("GET", "/api/v1/users/adm-sus-target", nil, nil).AssertStatus(t, 404)
+	env.request("GET", "/api/v1/tags/"+tagID, nil, nil).AssertStatus(t, 404)
+	env.request("POST", "/api/v1/users/me/reactivate", nil, authHeaders(target.ID)).AssertStatus(t, 403)
+
+	resp = env.request("GET", "/api/v1/admin/users?status=suspended", nil, authHeaders(admin.ID))
+	resp.AssertStatus(t, 200)
+	testutil.AssertJSON(t, resp.JSON(t), map[string]any{"total_count": float64(1)})
+
+	resp = env.request("POST", "/api/v1/admin/users/"+target.ID+"/restore", nil, authHeaders(admin.ID))
+	resp.AssertStatus(t, 200)
+	testutil.AssertJSON(t, resp.JSON(t), map[string]any{"deletion_status": "active"})
+
+	env.request("GET", "/api/v1/users/adm-sus-target", nil, nil).AssertStatus(t, 200)
+	env.request("GET", "/api/v1/tags/"+tagID, nil, nil).AssertStatus(t, 200)
+
+	// 本人が退会した後に利用停止した場合、利用停止の解除では退会を取り消さない
+	env.request("DELETE", "/api/v1/users/me", nil, authHeaders(target.ID)).AssertStatus(t, 200)
+	env.request("POST", "/api/v1/admin/users/"+target.ID+"/restore", nil, authHeaders(admin.ID)).AssertStatus(t, 409)
+	env.request("POST", "/api/v1/admin/users/"+target.ID+"/suspend", nil, authHeaders(admin.ID)).AssertStatus(t, 200)
+	resp = env.request("POST", "/api/v1/admin/users/"+target.ID+"/restore", nil, authHeaders(admin.ID))
+	resp.AssertStatus(t, 200)
+	testutil.AssertJSON(t, resp.JSON(t), map[string]any{"deletion_status": "deactivated"})
+	testutil.AssertHasKeys(t, resp.JSON(t), "purge_scheduled_at")
+	env.request("POST", "/api/v1/users/me/reactivate", nil, authHeaders(target.ID)).AssertStatus(t, 200)
+
+	// 利用停止と解除は操作した管理者とともに監査ログに残る
+	var actions []string
+	if err := env.db.Model(&model.AdminAuditLog{}).
+		Where("actor_user_id = ? AND target_id = ?", admin.ID, target.ID).
+		Order("created_at, action").
+		Pluck("action", &actions).Error; err != nil {
+		t.Fatalf("failed to load audit logs: %v", err)
+	}
+	if len(actions) != 4 {
+		t.Fatalf("expected 4 audit logs, got %v", actions)
+	}
+
+	// 管理者は対象ユーザーの通知を確認できる
+	resp = env.request("GET", "/api/v1/admin/users/"+target.ID+"/notifications", nil, authHeaders(admin.ID))
+	resp.AssertStatus(t, 200)
+	testutil.AssertHasKeys(t, resp.JSON(t), "notifications", "total", "unread_count")
+	env.request("GET", "/api/v1/admin/users/00000000-0000-0000-0000-000000000000/notifications", nil, authHeaders(admin.ID)).AssertStatus(t, 404)
+}
+
+// POST /api/v1/admin/tags/:tagId/hide, /unhide
+// 非表示にしたタグは一覧に出ず、持ち主は公開に戻せないことを確認する。
+func TestAdmin_HideAndUnhideTag(t *testing.T) {
+	env := setupTestEnv(t)
+	admin := env.createUser(t, "clerk_adm_tag1", "adm-tag-admin", "AdmTagAdmin")
+	owner := env.createUser(t, "clerk_adm_tag2", "adm-tag-owner", "AdmTagOwner")
+	admin.Role = model.UserRoleAdmin
+
+	tagBody, _ := json.Marshal(map[string]any{"title": "スパムのタグ", "is_public": true})
+	resp := env.request("POST", "/api/v1/tags", tagBody, authHeaders(owner.ID))
+	resp.AssertStatus(t, 201)
+	tagID, _ := resp.JSON(t)["id"].(string)
+
+	resp = env.request("POST", "/api/v1/admin/tags/"+tagID+"/hide", nil, authHeaders(admin.ID))
+	resp.AssertStatus(t, 200)
+	testutil.AssertJSON(t, resp.JSON(t), map[string]any{"id": tagID, "is_public": false})
+	testutil.AssertHasKeys(t, resp.JSON(t), "hidden_at")
+
+	env.request("GET", "/api/v1/tags/"+tagID, nil, nil).AssertStatus(t, 403)
+	resp = env.request("GET", "/api/v1/tags", nil, nil)
+	resp.AssertStatus(t, 200)
+	testutil.AssertJSON(t, resp.JSON(t), map[string]any{"total_count": float64(0)})
+
+	publish, _ := json.Marshal(map[string]any{"is_public": true})
+	env.request("PATCH", "/api/v1/tags/"+tagID, publish, authHeaders(owner.ID)).AssertStatus(t, 403)
+
+	resp = env.request("POST", "/api/v1/admin/tags/"+tagID+"/unhide", nil, authHeaders(admin.ID))
+	resp.AssertStatus(t, 200)
+	testutil.AssertJSON(t, resp.JSON(t), map[string]any{"is_public": true})
+	env.request("POST", "/api/v1/admin/tags/"+tagID+"/unhide", nil, authHeaders(admin.ID)).AssertStatus(t, 409)
+	env.request("GET", "/api/v1/tags/"+tagID, nil, nil).AssertStatus(t, 200)
+
+	// 非表示の間に持ち主が非公開にした場合は、再表示しても非公開のまま
+	env.request("POST", "/api/v1/admin/tags/"+tagID+"/hide", nil, authHeaders(admin.ID)).AssertStatus(t, 200)
+	unpublish, _ := json.Marshal(map[string]any{"is_public": false})
+	env.request("PATCH", "/api/v1/tags/"+tagID, unpublish, authHeaders(owner.ID)).AssertStatus(t, 200)
+	resp = env.request("POST", "/api/v1/admin/tags/"+tagID+"/unhide", nil, authHeaders(admin.ID))
+	resp.AssertStatus(t, 200)
+	testutil.AssertJSON(t, resp.JSON(t), map[string]any{"is_public": false})
+	env.request("GET", "/api/v1/tags/"+tagID, nil, nil).AssertStatus(t, 403)
+
+	var count int64
+	if err := env.db.Model(&model.AdminAuditLog{}).Where("target_type = ? AND target_id = ?", model.AdminAuditTargetTag, tagID).Count(&count).Error; err != nil {
+		t.Fatalf("failed to count audit logs: %v", err)
+	}
+	if count != 4 {
+		t.Fatalf("expected 4 audit logs, got %d", count)
+	}
+}
+
+// POST /api/v1/admin/jobs/:job
+func TestAdmin_RunJob(t *testing.T) {
+	t.Setenv("ADMIN_API_TOKEN", "test-admin-token")
+	env := setupTestEnv(t)
+	headers := map[string]string{"X-Admin-Token": "test-admin-token"}
+
+	resp := env.request("POST", "/api/v1/admin/jobs/purge-outbox-events", nil, headers)
+	resp.AssertStatus(t, 200)
+	testutil.AssertJSON(t, resp.JSON(t), map[string]any{"job": "purge-outbox-events", "affected": float64(0)})
+
+	env.request("POST", "/api/v1/admin/jobs/send-email-digests", nil, headers).AssertStatus(t, 404)
+	env.request("POST", "/api/v1/admin/jobs/purge-outbox-events", nil, map[string]string{"X-Admin-Token": "wrong"}).AssertStatus(t, 403)
+}
@@ -0,0 +1,187 @@
+//go:build integration
+
+package integration
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"cinetag-backend/src/internal/model"
+
+	"github.com/google/uuid"
+)
+
+// POST /api/v1/clerk/webhook
+// 署名のないリクエストは拒否し、同じメッセージ（svix-id）の再送は1回だけ処理することを確認する。
+func TestClerkWebhook_VerifiesSignatureAndIgnoresRedelivery(t *testing.T) {
+	env := setupTestEnv(t)
+
+	body, _ := json.Marshal(map[string]any{
+		"type": "user.created",
+		"data": map[string]any{
+			"id":              "clerk_cw1",
+			"first_name":      "Clerk",
+			"last_name":       "Webhook",
+			"email_addresses": []any{map[string]any{"email_address": "cw1@example.com"}},
+		},
+	})
+
+	// 署名なし・別のメッセージの署名は拒否する
+	env.request("POST", "/api/v1/clerk/webhook", body, jsonHeaders()).AssertStatus(t, 401)
+	forged := clerkWebhookHeaders(t, "msg_cw0", []byte(`{"type":"session.created","data":{}}`))
+	env.request("POST", "/api/v1/clerk/webhook", body, forged).AssertStatus(t, 401)
+
+	var count int64
+	env.db.Model(&model.User{}).Where("clerk_user_id = ?", "clerk_cw1").Count(&count)
+	if count != 0 {
+		t.Fatalf("expected no user to be created, got %d", count)
+	}
+
+	headers := clerkWebhookHeaders(t, "msg_cw1", body)
+	env.request("POST", "/api/v1/clerk/webhook", body, headers).AssertStatus(t, 200)
+	env.db.Model(&model.User{}).Where("clerk_user_id = ?", "clerk_cw1").Count(&count)
+	if count != 1 {
+		t.Fatalf("expected user to be created, got %d", count)
+	}
+
+	// 再送は処理せずに成功として返す
+	deleted, _ := json.Marshal(map[string]any{"type": "user.deleted", "data": map[string]any{"id": "clerk_cw1"}})
+	deleteHeaders := clerkWebhookHeaders(t, "msg_cw2", deleted)
+	env.request("POST", "/api/v1/clerk/webhook", deleted, deleteHeaders).AssertStatus(t, 200)
+	env.request("POST", "/api/v1/clerk/webhook", deleted, deleteHeaders).AssertStatus(t, 200)
+	env.request("POST", "/api/v1/clerk/webhook", body, headers).AssertStatus(t, 200)
+
+	var user model.User
+	if err := env.db.Where("clerk_user_id = ?", "clerk_cw1").First(&user).Error; err != nil {
+		t.Fatalf("failed to find user: %v", err)
+	}
+	if user.DeletionStatus != model.UserDeletionStatusDeactivated {
+		t.Fatalf("expected user to be deactivated, got %s", user.DeletionStatus)
+	}
+	env.db.Model(&model.ClerkWebhookEvent{}).Where("status = ?", model.ClerkWebhookEventStatusProcessed).Count(&count)
+	if count != 2 {
+		t.Fatalf("expected 2 processed events, got %d", count)
+	}
+}
+
+// POST /api/v1/clerk/webhook
+// 処理中のメッセージの再送は成功とせず、期限を過ぎた処理中のメッセージは再送で処理し直すことを確認する。
+func TestClerkWebhook_RetriesEventInProgressAfterLease(t *testing.T) {
+	env := setupTestEnv(t)
+
+	body, _ := json.Marshal(map[string]any{
+		"type": "user.created",
+		"data": map[string]any{
+			"id":              "clerk_cw6",
+			"first_name":      "Lease",
+			"email_addresses": []any{map[string]any{"email_address": "cw6@example.com"}},
+		},
+	})
+	headers := clerkWebhookHeaders(t, "msg_cw6", body)
+
+	// 他のリクエストが処理中（期限内）
+	lockToken := uuid.NewString()
+	lockedUntil := time.Now().Add(time.Minute)
+	if err := env.db.Create(&model.ClerkWebhookEvent{
+		SvixID:      "msg_cw6",
+		EventType:   "user.created",
+		Status:      model.ClerkWebhookEventStatusProcessing,
+		LockToken:   &lockToken,
+		LockedUntil: &lockedUntil,
+	}).Error; err != nil {
+		t.Fatalf("failed to create event: %v", err)
+	}
+	env.request("POST", "/api/v1/clerk/webhook", body, headers).AssertStatus(t, 409)
+
+	var count int64
+	env.db.Model(&model.User{}).Where("clerk_user_id = ?", "clerk_cw6").Count(&count)
+	if count != 0 {
+		t.Fatalf("event in progress must not be processed, got %d users", count)
+	}
+
+	// 処理中のまま期限を過ぎた場合は引き継いで処理し、同期と同時に処理済みにする
+	env.db.Model(&model.ClerkWebhookEvent{}).Where("svix_id = ?", "msg_cw6").Update("locked_until", time.Now().Add(-time.Second))
+	env.request("POST", "/api/v1/clerk/webhook", body, headers).AssertStatus(t, 200)
+
+	env.db.Model(&model.User{}).Where("clerk_user_id = ?", "clerk_cw6").Count(&count)
+	if count != 1 {
+		t.Fatalf("expected user to be created, got %d", count)
+	}
+	var event model.ClerkWebhookEvent
+	if err := env.db.Where("svix_id = ?", "msg_cw6").First(&event).Error; err != nil {
+		t.Fatalf("failed to find event: %v", err)
+	}
+	if event.Status != model.ClerkWebhookEventStatusProcessed || event.ProcessedAt == nil {
+		t.Fatalf("expected event to be processed, got %+v", event)
+	}
+	if event.LockToken == nil || *event.LockToken == lockToken {
+		t.Fatalf("expected event to be taken over with a new lock token, got %v", event.LockToken)
+	}
+
+	// 処理済みの再送は処理せずに成功として返す
+	env.request("POST", "/api/v1/clerk/webhook", body, headers).AssertStatus(t, 200)
+}
+
+// POST /api/v1/clerk/webhook, POST /api/v1/admin/clerk/users/:clerkUserId/resync
+// user.updated でプロフィール全体を同期し、古いペイロードは反映せず、保存済みのペイロードから再同期できることを確認する。
+func TestClerkWebhook_SyncsUserProfile(t *testing.T) {
+	env := setupTestEnv(t)
+	admin := env.createUser(t, "clerk_cw_admin", "cw-admin", "CwAdmin")
+	admin.Role = model.UserRoleAdmin
+
+	userData := func(username, firstName, email string, updatedAt int64) map[string]any {
+		return map[string]any{
+			"id":                       "clerk_cw3",
+			"username":                 username,
+			"first_name":               firstName,
+			"primary_email_address_id": "idn_primary",
+			"email_addresses": []any{
+				map[string]any{"id": "idn_other", "email_address": "other@example.com"},
+				map[string]any{"id": "idn_primary", "email_address": email},
+			},
+			"updated_at": updatedAt,
+		}
+	}
+	send := func(svixID, eventType string, data map[string]any) {
+		t.Helper()
+		body, _ := json.Marshal(map[string]any{"type": eventType, "data": data})
+		env.request("POST", "/api/v1/clerk/webhook", body, clerkWebhookHeaders(t, svixID, body)).AssertStatus(t, 200)
+	}
+	findUser := func() model.User {
+		t.Helper()
+		var user model.User
+		if err := env.db.Where("clerk_user_id = ?", "clerk_cw3").First(&user).Error; err != nil {
+			t.Fatalf("failed to find user: %v", err)
+		}
+		return user
+	}
+
+	now := time.Now().UnixMilli()
+	send("msg_cw3", "user.created", userData("alice", "Alice", "alice@example.com", now))
+	user := findUser()
+	if user.DisplayID != "alice" || user.DisplayName != "Alice" || user.Email != "alice@example.com" {
+		t.Fatalf("unexpected created user: %+v", user)
+	}
+
+	send("msg_cw4", "user.updated", userData("alice2", "Alicia", "alicia@example.com", now+2))
+	user = findUser()
+	if user.DisplayID != "alice2" || user.DisplayName != "Alicia" || user.Email != "alicia@example.com" {
+		t.Fatalf("unexpected updated user: %+v", user)
+	}
+
+	// 配信順が前後した古いペイロードは反映しない
+	send("msg_cw5", "user.updated", userData("alice", "Alice", "alice@example.com", now+1))
+	if user = findUser(); user.DisplayID != "alice2" {
+		t.Fatalf("stale payload must not be applied: %+v", user)
+	}
+
+	// 同期後にアプリ側の値が壊れても、保存済みのペイロードから再同期できる
+	env.db.Model(&model.User{}).Where("id = ?", user.ID).Update("email", "broken@example.com")
+	env.request("POST", "/api/v1/admin/clerk/users/clerk_cw3/resync", nil, jsonHeaders()).AssertStatus(t, 401)
+	env.request("POST", "/api/v1/admin/clerk/users/clerk_cw3/resync", nil, authHeaders(admin.ID)).AssertStatus(t, 200)
+	if user = findUser(); user.Email != "alicia@example.com" {
+		t.Fatalf("expected email to be resynced, got %s", user.Email)
+	}
+	env.request("POST", "/api/v1/admin/clerk/users/clerk_unknown/resync", nil, authHeaders(admin.ID)).AssertStatus(t, 404)
+}
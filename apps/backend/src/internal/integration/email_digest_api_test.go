@@ -0,0 +1,97 @@
+//go:build integration
+
+package integration
+
+import (
+	"context"
+	"encoding/json"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"cinetag-backend/src/internal/model"
+	"cinetag-backend/src/internal/repository"
+	"cinetag-backend/src/internal/service"
+	"cinetag-backend/src/internal/testutil"
+)
+
+// GET/PATCH /api/v1/me/email-digest, POST /api/v1/email/unsubscribe
+// 配信を有効にしたユーザーにダイジェストが送信され、メール内のリンクから配信を停止できることを確認する。
+func TestEmailDigest_SendAndUnsubscribe(t *testing.T) {
+	env := setupTestEnv(t)
+	alice := env.createUser(t, "clerk_ed1", "ed-alice", "EDAlice")
+	bob := env.createUser(t, "clerk_ed2", "ed-bob", "EDBob")
+	carol := env.createUser(t, "clerk_ed3", "ed-carol", "EDCarol")
+
+	env.request("GET", "/api/v1/me/email-digest", nil, jsonHeaders()).AssertStatus(t, 401)
+
+	// 初期状態は配信しない
+	resp := env.request("GET", "/api/v1/me/email-digest", nil, authHeaders(alice.ID))
+	resp.AssertStatus(t, 200)
+	testutil.AssertJSON(t, resp.JSON(t), map[string]any{"frequency": "off"})
+
+	invalid, _ := json.Marshal(map[string]any{"frequency": "monthly"})
+	env.request("PATCH", "/api/v1/me/email-digest", invalid, authHeaders(alice.ID)).AssertStatus(t, 400)
+
+	daily, _ := json.Marshal(map[string]any{"frequency": "daily"})
+	resp = env.request("PATCH", "/api/v1/me/email-digest", daily, authHeaders(alice.ID))
+	resp.AssertStatus(t, 200)
+	testutil.AssertJSON(t, resp.JSON(t), map[string]any{"frequency": "daily"})
+	// Carol は配信を有効にしているが、載せる内容がない
+	env.request("PATCH", "/api/v1/me/email-digest", daily, authHeaders(carol.ID)).AssertStatus(t, 200)
+
+	tag := &model.Tag{UserID: alice.ID, Title: "SF", IsPublic: true}
+	if err := env.db.Create(tag).Error; err != nil {
+		t.Fatalf("failed to create tag: %v", err)
+	}
+	notification := &model.Notification{RecipientUserID: alice.ID, ActorUserID: &bob.ID, NotificationType: model.NotificationTypeTagFollowed, TagID: &tag.ID}
+	if err := env.db.Create(notification).Error; err != nil {
+		t.Fatalf("failed to create notification: %v", err)
+	}
+
+	m := &testutil.FakeMailer{}
+	digestService := service.NewEmailDigestService(testutil.NewTestLogger(), repository.NewEmailDigestRepository(env.db), repository.NewNotificationRepository(env.db), m, testEmailDigestConfig)
+
+	result, err := digestService.SendDueDigests(context.Background(), time.Now(), 100)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Processed != 2 || result.Sent != 1 {
+		t.Fatalf("unexpected result: %+v", result)
+	}
+	if len(m.Sent) != 1 || m.Sent[0].To != alice.Email {
+		t.Fatalf("expected 1 mail to alice, got %+v", m.Sent)
+	}
+	if !strings.Contains(m.Sent[0].TextBody, "EDBob さんがタグ「SF」をフォローしました") {
+		t.Fatalf("unexpected body:\n%s", m.Sent[0].TextBody)
+	}
+
+	// 配信済みのため、次の配信時期までは送らない
+	result, err = digestService.SendDueDigests(context.Background(), time.Now(), 100)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Processed != 0 {
+		t.Fatalf("expected no due users, got %+v", result)
+	}
+
+	// 配信停止リンク（ワンクリック）
+	link, err := url.Parse(strings.Trim(m.Sent[0].Headers["List-Unsubscribe"], "<>"))
+	if err != nil {
+		t.Fatalf("invalid unsubscribe link: %v", err)
+	}
+	env.request("POST", "/api/v1/email/unsubscribe?token=invalid", nil, nil).AssertStatus(t, 400)
+
+	// リンクを開いただけ（GET）では配信を停止しない
+	env.request("GET", link.RequestURI(), nil, nil).AssertStatus(t, 200)
+	resp = env.request("GET", "/api/v1/me/email-digest", nil, authHeaders(alice.ID))
+	resp.AssertStatus(t, 200)
+	testutil.AssertJSON(t, resp.JSON(t), map[string]any{"frequency": "daily"})
+
+	env.request("POST", link.RequestURI(), nil, nil).AssertStatus(t, 200)
+
+	resp = env.request("GET", "/api/v1/me/email-digest", nil, authHeaders(alice.ID))
+	resp.AssertStatus(t, 200)
+	testutil.AssertJSON(t, resp.JSON(t), map[string]any{"frequency": "off"})
+}
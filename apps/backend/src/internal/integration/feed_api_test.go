@@ -0,0 +1,252 @@
+//go:build integration
+
+package integration
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"cinetag-backend/src/internal/model"
+	"cinetag-backend/src/internal/testutil"
+)
+
+// フィードの項目数が want になるまで待ち、最後に取得したレスポンスを返す。
+// フィードイベントは通知と同じく非同期で記録されるため、ポーリングで確認する。
+func waitForFeedItems(t *testing.T, env *testEnv, userID string, want int) map[string]any {
+	t.Helper()
+
+	var data map[string]any
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		resp := env.request("GET", "/api/v1/feed/me", nil, authHeaders(userID))
+		resp.AssertStatus(t, 200)
+		data = resp.JSON(t)
+		if len(testutil.GetItems(t, data)) == want || time.Now().After(deadline) {
+			break
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+	if got := len(testutil.GetItems(t, data)); got != want {
+		t.Fatalf("expected %d feed items, got %d: %v", want, got, data)
+	}
+	return data
+}
+
+// GET /api/v1/feed/me
+// フォロー中ユーザーの公開タグ作成・ユーザーフォローがフィードに表示され、非公開タグや取り消したフォローは表示されないことを確認する。
+func TestFeed_FollowedUsersActivity(t *testing.T) {
+	env := setupTestEnv(t)
+	alice := env.createUser(t, "clerk_fd1", "fd-alice", "FDAlice")
+	env.createUser(t, "clerk_fd2", "fd-dave", "FDDave")
+	carol := env.createUser(t, "clerk_fd3", "fd-carol", "FDCarol")
+
+	env.request("GET", "/api/v1/feed/me", nil, jsonHeaders()).AssertStatus(t, 401)
+
+	env.request("POST", "/api/v1/users/fd-alice/follow", nil, authHeaders(carol.ID)).AssertStatus(t, 200)
+
+	publicTag, _ := json.Marshal(map[string]any{"title": "公開タグ", "is_public": true})
+	env.request("POST", "/api/v1/tags", publicTag, authHeaders(alice.ID)).AssertStatus(t, 201)
+	privateTag, _ := json.Marshal(map[string]any{"title": "非公開タグ", "is_public": false})
+	env.request("POST", "/api/v1/tags", privateTag, authHeaders(alice.ID)).AssertStatus(t, 201)
+	env.request("POST", "/api/v1/users/fd-dave/follow", nil, authHeaders(alice.ID)).AssertStatus(t, 200)
+
+	data := waitForFeedItems(t, env, carol.ID, 2)
+	if data["next_cursor"] != nil {
+		t.Fatalf("expected next_cursor=nil, got %v", data["next_cursor"])
+	}
+	types := map[string]map[string]any{}
+	for _, item := range testutil.GetItems(t, data) {
+		types[item["type"].(string)] = item
+	}
+	created, ok := types["tag_created"]
+	if !ok {
+		t.Fatalf("expected tag_created item, got %v", data)
+	}
+	tag, _ := created["tag"].(map[string]any)
+	testutil.AssertJSON(t, tag, map[string]any{"title": "公開タグ"})
+	followed, ok := types["user_followed"]
+	if !ok {
+		t.Fatalf("expected user_followed item, got %v", data)
+	}
+	target, _ := followed["target_user"].(map[string]any)
+	testutil.AssertJSON(t, target, map[string]any{"display_id": "fd-dave"})
+
+	// フォローを解除すると表示されなくなる
+	env.request("DELETE", "/api/v1/users/fd-dave/follow", nil, authHeaders(alice.ID)).AssertStatus(t, 200)
+	waitForFeedItems(t, env, carol.ID, 1)
+}
+
+// GET /api/v1/feed/me
+// フォロー中タグへの映画追加が時間幅内で1件にまとめられ、カーソルで続きを取得できることを確認する。
+func TestFeed_GroupsMoviesAddedToFollowedTag(t *testing.T) {
+	env := setupTestEnv(t)
+	alice := env.createUser(t, "clerk_fd4", "fd-owner", "FDOwner")
+	bob := env.createUser(t, "clerk_fd5", "fd-bob", "FDBob")
+	carol := env.createUser(t, "clerk_fd6", "fd-viewer", "FDViewer")
+
+	tag := &model.Tag{UserID: alice.ID, Title: "SF", IsPublic: true}
+	if err := env.db.Create(tag).Error; err != nil {
+		t.Fatalf("failed to create tag: %v", err)
+	}
+	if err := env.db.Create(&model.TagFollower{TagID: tag.ID, UserID: carol.ID}).Error; err != nil {
+		t.Fatalf("failed to follow tag: %v", err)
+	}
+
+	base := time.Now().Add(-time.Hour)
+	events := []struct {
+		actor   string
+		movieID int
+		at      time.Time
+	}{
+		{alice.ID, 1, base},
+		{bob.ID, 2, base.Add(-5 * time.Minute)},
+		{alice.ID, 3, base.Add(-10 * time.Minute)},
+		// 時間幅の外
+		{alice.ID, 4, base.Add(-5 * time.Hour)},
+	}
+	for _, e := range events {
+		tm := &model.TagMovie{TagID: tag.ID, TmdbMovieID: e.movieID, AddedByUser: e.actor}
+		if err := env.db.Create(tm).Error; err != nil {
+			t.Fatalf("failed to create tag movie: %v", err)
+		}
+		tagID, tagMovieID := tag.ID, tm.ID
+		ev := &model.FeedEvent{
+			ActorUserID: e.actor,
+			EventType:   model.FeedEventTypeTagMovieAdded,
+			TagID:       &tagID,
+			TagMovieID:  &tagMovieID,
+			CreatedAt:   e.at,
+		}
+		if err := env.db.Create(ev).Error; err != nil {
+			t.Fatalf("failed to create feed event: %v", err)
+		}
+	}
+
+	resp := env.request("GET", "/api/v1/feed/me?limit=1", nil, authHeaders(carol.ID))
+	resp.AssertStatus(t, 200)
+	data := resp.JSON(t)
+	items := testutil.GetItems(t, data)
+	if len(items) != 1 {
+		t.Fatalf("expected 1 item, got %v", data)
+	}
+	testutil.AssertJSON(t, items[0], map[string]any{
+		"type":        "tag_movie_added",
+		"count":       float64(3),
+		"actor_count": float64(2),
+	})
+	cursor, ok := data["next_cursor"].(string)
+	if !ok || cursor == "" {
+		t.Fatalf("expected next_cursor, got %v", data["next_cursor"])
+	}
+
+	resp = env.request("GET", "/api/v1/feed/me?limit=1&cursor="+cursor, nil, authHeaders(carol.ID))
+	resp.AssertStatus(t, 200)
+	data = resp.JSON(t)
+	items = testutil.GetItems(t, data)
+	if len(items) != 1 {
+		t.Fatalf("expected 1 item, got %v", data)
+	}
+	testutil.AssertJSON(t, items[0], map[string]any{"count": float64(1)})
+	if data["next_cursor"] != nil {
+		t.Fatalf("expected next_cursor=nil, got %v", data["next_cursor"])
+	}
+
+	// 不正なカーソル
+	env.request("GET", "/api/v1/feed/me?cursor=broken", nil, authHeaders(carol.ID)).AssertStatus(t, 400)
+
+	// 閲覧者をブロックしているユーザーのイベントは表示しない
+	env.request("POST", "/api/v1/users/fd-viewer/block", nil, authHeaders(bob.ID)).AssertStatus(t, 200)
+	resp = env.request("GET", "/api/v1/feed/me?limit=1", nil, authHeaders(carol.ID))
+	resp.AssertStatus(t, 200)
+	items = testutil.GetItems(t, resp.JSON(t))
+	if len(items) != 1 {
+		t.Fatalf("expected 1 item, got %v", items)
+	}
+	testutil.AssertJSON(t, items[0], map[string]any{
+		"count":       float64(2),
+		"actor_count": float64(1),
+	})
+}
+
+// GET /api/v1/users/:displayId/activity
+// 公開タグに関するアクティビティのみが返り、非公開タグ・取り消したいいね・非公開アカウントへのフォローは表示されないことを確認する。
+func TestUserActivity_RespectsVisibility(t *testing.T) {
+	env := setupTestEnv(t)
+	alice := env.createUser(t, "clerk_ua1", "ua-alice", "UAAlice")
+	bob := env.createUser(t, "clerk_ua2", "ua-bob", "UABob")
+	secret := env.createUser(t, "clerk_ua3", "ua-secret", "UASecret")
+	if err := env.db.Model(&model.User{}).Where("id = ?", secret.ID).Update("is_private", true).Error; err != nil {
+		t.Fatalf("failed to make user private: %v", err)
+	}
+
+	publicTag := &model.Tag{UserID: alice.ID, Title: "公開", IsPublic: true}
+	privateTag := &model.Tag{UserID: alice.ID, Title: "非公開", IsPublic: false}
+	for _, tag := range []*model.Tag{publicTag, privateTag} {
+		if err := env.db.Create(tag).Error; err != nil {
+			t.Fatalf("failed to create tag: %v", err)
+		}
+	}
+	bobTag := &model.Tag{UserID: bob.ID, Title: "ボブのタグ", IsPublic: true}
+	if err := env.db.Create(bobTag).Error; err != nil {
+		t.Fatalf("failed to create tag: %v", err)
+	}
+
+	publicMovie := &model.TagMovie{TagID: publicTag.ID, TmdbMovieID: 1, AddedByUser: alice.ID}
+	privateMovie := &model.TagMovie{TagID: privateTag.ID, TmdbMovieID: 2, AddedByUser: alice.ID}
+	for _, tm := range []*model.TagMovie{publicMovie, privateMovie} {
+		if err := env.db.Create(tm).Error; err != nil {
+			t.Fatalf("failed to create tag movie: %v", err)
+		}
+	}
+	if err := env.db.Create(&model.TagLike{TagID: bobTag.ID, UserID: alice.ID}).Error; err != nil {
+		t.Fatalf("failed to like tag: %v", err)
+	}
+	for _, followee := range []string{bob.ID, secret.ID} {
+		if err := env.db.Create(&model.UserFollower{FollowerID: alice.ID, FolloweeID: followee}).Error; err != nil {
+			t.Fatalf("failed to follow user: %v", err)
+		}
+	}
+
+	base := time.Now().Add(-time.Hour)
+	strPtr := func(s string) *string { return &s }
+	events := []*model.FeedEvent{
+		{EventType: model.FeedEventTypeTagCreated, TagID: strPtr(publicTag.ID), CreatedAt: base},
+		{EventType: model.FeedEventTypeTagMovieAdded, TagID: strPtr(publicTag.ID), TagMovieID: strPtr(publicMovie.ID), CreatedAt: base.Add(time.Minute)},
+		{EventType: model.FeedEventTypeTagMovieAdded, TagID: strPtr(privateTag.ID), TagMovieID: strPtr(privateMovie.ID), CreatedAt: base.Add(2 * time.Minute)},
+		{EventType: model.FeedEventTypeTagLiked, TagID: strPtr(bobTag.ID), CreatedAt: base.Add(3 * time.Minute)},
+		{EventType: model.FeedEventTypeUserFollowed, TargetUserID: strPtr(bob.ID), CreatedAt: base.Add(4 * time.Minute)},
+		{EventType: model.FeedEventTypeUserFollowed, TargetUserID: strPtr(secret.ID), CreatedAt: base.Add(5 * time.Minute)},
+	}
+	for _, ev := range events {
+		ev.ActorUserID = alice.ID
+		if err := env.db.Create(ev).Error; err != nil {
+			t.Fatalf("failed to create feed event: %v", err)
+		}
+	}
+
+	resp := env.request("GET", "/api/v1/users/ua-alice/activity", nil, nil)
+	resp.AssertStatus(t, 200)
+	data := resp.JSON(t)
+	testutil.AssertListResponse(t, data, 4, 1, 20)
+	items := testutil.GetItems(t, data)
+	testutil.AssertJSON(t, items[0], map[string]any{"type": "user_followed"})
+	testutil.AssertJSON(t, items[2], map[string]any{"type": "tag_movie_added"})
+	movie, _ := items[2]["movie"].(map[string]any)
+	testutil.AssertJSON(t, movie, map[string]any{"tmdb_movie_id": float64(1)})
+
+	// 非公開アカウント本人には、そのアカウントへのフォローも表示される
+	resp = env.request("GET", "/api/v1/users/ua-alice/activity", nil, authHeaders(secret.ID))
+	resp.AssertStatus(t, 200)
+	testutil.AssertListResponse(t, resp.JSON(t), 5, 1, 20)
+
+	// いいねを取り消すと表示されなくなる
+	if err := env.db.Where("tag_id = ? AND user_id = ?", bobTag.ID, alice.ID).Delete(&model.TagLike{}).Error; err != nil {
+		t.Fatalf("failed to unlike tag: %v", err)
+	}
+	resp = env.request("GET", "/api/v1/users/ua-alice/activity", nil, nil)
+	resp.AssertStatus(t, 200)
+	testutil.AssertListResponse(t, resp.JSON(t), 3, 1, 20)
+
+	env.request("GET", "/api/v1/users/ua-nobody/activity", nil, nil).AssertStatus(t, 404)
+}
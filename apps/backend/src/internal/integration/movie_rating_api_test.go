@@ -0,0 +1,107 @@
+//go:build integration
+
+package integration
+
+import (
+	"testing"
+	"time"
+
+	"cinetag-backend/src/internal/model"
+	"cinetag-backend/src/internal/testutil"
+)
+
+// PUT/DELETE /api/v1/me/movies/:tmdbMovieId/rating, GET /api/v1/movies/:tmdbMovieId/ratings, GET /api/v1/users/:displayId/ratings
+// 評価の登録・上書き・削除ができ、非公開アカウントの評価はフォロワー以外に表示されないことを確認する。
+func TestMovieRating_SetListAndDelete(t *testing.T) {
+	env := setupTestEnv(t)
+	alice := env.createUser(t, "clerk_mr1", "mr-alice", "MRAlice")
+	bob := env.createUser(t, "clerk_mr2", "mr-bob", "MRBob")
+	carol := env.createUser(t, "clerk_mr3", "mr-carol", "MRCarol")
+	if err := env.db.Model(&model.User{}).Where("id = ?", carol.ID).Update("is_private", true).Error; err != nil {
+		t.Fatalf("failed to make user private: %v", err)
+	}
+
+	cache := &model.MovieCache{TmdbMovieID: 1, Title: "movie", ExpiresAt: time.Now().Add(time.Hour)}
+	if err := env.db.Create(cache).Error; err != nil {
+		t.Fatalf("failed to create movie cache: %v", err)
+	}
+
+	// 不正な評価
+	env.request("PUT", "/api/v1/me/movies/1/rating", []byte(`{"rating":4.2}`), authHeaders(alice.ID)).AssertStatus(t, 400)
+
+	first := env.request("PUT", "/api/v1/me/movies/1/rating", []byte(`{"rating":3}`), authHeaders(alice.ID))
+	first.AssertStatus(t, 200)
+	// 上書き（作成日時は最初の評価のものを返す）
+	resp := env.request("PUT", "/api/v1/me/movies/1/rating", []byte(`{"rating":4.5,"review":"ラストが良い","is_spoiler":true}`), authHeaders(alice.ID))
+	resp.AssertStatus(t, 200)
+	testutil.AssertJSON(t, resp.JSON(t), map[string]any{
+		"rating":     4.5,
+		"review":     "ラストが良い",
+		"is_spoiler": true,
+		"created_at": first.JSON(t)["created_at"],
+	})
+	env.request("PUT", "/api/v1/me/movies/1/rating", []byte(`{"rating":2}`), authHeaders(carol.ID)).AssertStatus(t, 200)
+
+	// 非公開アカウント（carol）の評価はフォロワー以外には表示されない
+	resp = env.request("GET", "/api/v1/movies/1/ratings", nil, authHeaders(bob.ID))
+	resp.AssertStatus(t, 200)
+	data := resp.JSON(t)
+	testutil.AssertListResponse(t, data, 1, 1, 20)
+	items := testutil.GetItems(t, data)
+	user, _ := items[0]["user"].(map[string]any)
+	testutil.AssertJSON(t, user, map[string]any{"display_id": "mr-alice"})
+
+	// 本人には自分の評価も表示される
+	resp = env.request("GET", "/api/v1/movies/1/ratings", nil, authHeaders(carol.ID))
+	resp.AssertStatus(t, 200)
+	testutil.AssertListResponse(t, resp.JSON(t), 2, 1, 20)
+
+	resp = env.request("GET", "/api/v1/users/mr-alice/ratings", nil, nil)
+	resp.AssertStatus(t, 200)
+	data = resp.JSON(t)
+	testutil.AssertListResponse(t, data, 1, 1, 20)
+	movie, _ := testutil.GetItems(t, data)[0]["movie"].(map[string]any)
+	testutil.AssertJSON(t, movie, map[string]any{"title": "movie"})
+
+	env.request("GET", "/api/v1/users/mr-carol/ratings", nil, authHeaders(bob.ID)).AssertStatus(t, 403)
+
+	env.request("DELETE", "/api/v1/me/movies/1/rating", nil, authHeaders(alice.ID)).AssertStatus(t, 204)
+	env.request("DELETE", "/api/v1/me/movies/1/rating", nil, authHeaders(alice.ID)).AssertStatus(t, 404)
+}
+
+// GET /api/v1/tags/:tagId/movies
+// タグの映画一覧に閲覧者の評価が埋め込まれることを確認する。
+func TestListTagMovies_IncludesViewerRating(t *testing.T) {
+	env := setupTestEnv(t)
+	owner := env.createUser(t, "clerk_mr4", "mr-owner", "MROwner")
+	viewer := env.createUser(t, "clerk_mr5", "mr-viewer", "MRViewer")
+
+	tag := &model.Tag{UserID: owner.ID, Title: "評価テスト", IsPublic: true}
+	if err := env.db.Create(tag).Error; err != nil {
+		t.Fatalf("failed to create tag: %v", err)
+	}
+	for i, id := range []int{1, 2} {
+		if err := env.db.Create(&model.MovieCache{TmdbMovieID: id, Title: "movie", ExpiresAt: time.Now().Add(time.Hour)}).Error; err != nil {
+			t.Fatalf("failed to create movie cache: %v", err)
+		}
+		if err := env.db.Create(&model.TagMovie{TagID: tag.ID, TmdbMovieID: id, AddedByUser: owner.ID, Position: i}).Error; err != nil {
+			t.Fatalf("failed to add movie: %v", err)
+		}
+	}
+	env.request("PUT", "/api/v1/me/movies/2/rating", []byte(`{"rating":5}`), authHeaders(viewer.ID)).AssertStatus(t, 200)
+
+	resp := env.request("GET", "/api/v1/tags/"+tag.ID+"/movies", nil, authHeaders(viewer.ID))
+	resp.AssertStatus(t, 200)
+	for _, item := range testutil.GetItems(t, resp.JSON(t)) {
+		switch item["tmdb_movie_id"] {
+		case float64(2):
+			if item["viewer_rating"] != float64(5) {
+				t.Fatalf("expected viewer_rating 5, got %v", item["viewer_rating"])
+			}
+		default:
+			if _, ok := item["viewer_rating"]; ok {
+				t.Fatalf("expected no viewer_rating, got %v", item["viewer_rating"])
+			}
+		}
+	}
+}
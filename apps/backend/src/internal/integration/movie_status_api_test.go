@@ -0,0 +1,92 @@
+//go:build integration
+
+package integration
+
+import (
+	"testing"
+	"time"
+
+	"cinetag-backend/src/internal/model"
+	"cinetag-backend/src/internal/testutil"
+)
+
+// PUT/DELETE /api/v1/me/movies/:tmdbMovieId/status, GET /api/v1/me/movie-statuses
+// 視聴ステータスの設定・上書き・一覧・解除ができ、タグの映画一覧と詳細に閲覧者の視聴状況が埋め込まれることを確認する。
+func TestMovieStatus_SetListAndEmbed(t *testing.T) {
+	env := setupTestEnv(t)
+	viewer := env.createUser(t, "clerk_ms1", "ms-viewer", "MSViewer")
+	owner := env.createUser(t, "clerk_ms2", "ms-owner", "MSOwner")
+
+	for _, id := range []int{1, 2, 3} {
+		cache := &model.MovieCache{TmdbMovieID: id, Title: "movie", ExpiresAt: time.Now().Add(time.Hour)}
+		if err := env.db.Create(cache).Error; err != nil {
+			t.Fatalf("failed to create movie cache: %v", err)
+		}
+	}
+	tag := &model.Tag{UserID: owner.ID, Title: "観たい映画", IsPublic: true}
+	if err := env.db.Create(tag).Error; err != nil {
+		t.Fatalf("failed to create tag: %v", err)
+	}
+	for i, id := range []int{1, 2, 3} {
+		if err := env.db.Create(&model.TagMovie{TagID: tag.ID, TmdbMovieID: id, AddedByUser: owner.ID, Position: i}).Error; err != nil {
+			t.Fatalf("failed to add movie: %v", err)
+		}
+	}
+
+	// 未来の視聴日は指定できない
+	future := time.Now().AddDate(0, 0, 7).Format("2006-01-02")
+	env.request("PUT", "/api/v1/me/movies/1/status", []byte(`{"status":"watched","watched_at":"`+future+`"}`), authHeaders(viewer.ID)).AssertStatus(t, 400)
+
+	env.request("PUT", "/api/v1/me/movies/1/status", []byte(`{"status":"want_to_watch"}`), authHeaders(viewer.ID)).AssertStatus(t, 200)
+	// 上書き
+	resp := env.request("PUT", "/api/v1/me/movies/1/status", []byte(`{"status":"watched","watched_at":"2024-02-03"}`), authHeaders(viewer.ID))
+	resp.AssertStatus(t, 200)
+	testutil.AssertJSON(t, resp.JSON(t), map[string]any{
+		"tmdb_movie_id": float64(1),
+		"status":        "watched",
+		"watched_at":    "2024-02-03",
+	})
+	env.request("PUT", "/api/v1/me/movies/2/status", []byte(`{"status":"dropped"}`), authHeaders(viewer.ID)).AssertStatus(t, 200)
+
+	resp = env.request("GET", "/api/v1/me/movie-statuses?status=watched", nil, authHeaders(viewer.ID))
+	resp.AssertStatus(t, 200)
+	data := resp.JSON(t)
+	testutil.AssertListResponse(t, data, 1, 1, 20)
+
+	// タグの映画一覧に閲覧者の視聴ステータスが埋め込まれる
+	resp = env.request("GET", "/api/v1/tags/"+tag.ID+"/movies", nil, authHeaders(viewer.ID))
+	resp.AssertStatus(t, 200)
+	items := testutil.GetItems(t, resp.JSON(t))
+	if len(items) != 3 {
+		t.Fatalf("expected 3 items, got %d", len(items))
+	}
+	for _, item := range items {
+		status, _ := item["viewer_status"].(map[string]any)
+		switch item["tmdb_movie_id"] {
+		case float64(1):
+			testutil.AssertJSON(t, status, map[string]any{"status": "watched", "watched_at": "2024-02-03"})
+		case float64(2):
+			testutil.AssertJSON(t, status, map[string]any{"status": "dropped"})
+		default:
+			if status != nil {
+				t.Fatalf("expected no viewer_status, got %v", status)
+			}
+		}
+	}
+
+	// タグ詳細に視聴進捗が含まれる
+	resp = env.request("GET", "/api/v1/tags/"+tag.ID, nil, authHeaders(viewer.ID))
+	resp.AssertStatus(t, 200)
+	progress, _ := resp.JSON(t)["viewer_progress"].(map[string]any)
+	testutil.AssertJSON(t, progress, map[string]any{
+		"watched_count": float64(1),
+		"movie_count":   float64(3),
+	})
+
+	// 解除
+	env.request("DELETE", "/api/v1/me/movies/1/status", nil, authHeaders(viewer.ID)).AssertStatus(t, 204)
+	env.request("DELETE", "/api/v1/me/movies/1/status", nil, authHeaders(viewer.ID)).AssertStatus(t, 404)
+	resp = env.request("GET", "/api/v1/me/movie-statuses", nil, authHeaders(viewer.ID))
+	resp.AssertStatus(t, 200)
+	testutil.AssertListResponse(t, resp.JSON(t), 1, 1, 20)
+}
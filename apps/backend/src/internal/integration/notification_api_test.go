@@ -0,0 +1,449 @@
+//go:build integration
+
+package integration
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"cinetag-backend/src/internal/model"
+	"cinetag-backend/src/internal/repository"
+	"cinetag-backend/src/internal/testutil"
+)
+
+// GET/PATCH /api/v1/me/notification-settings
+// 通知の種類ごとの設定とタグのミュートを更新・取得できることを確認する。
+func TestNotificationSettings_UpdateAndGet(t *testing.T) {
+	env := setupTestEnv(t)
+	alice := env.createUser(t, "clerk_ns1", "ns-alice", "NSAlice")
+	bob := env.createUser(t, "clerk_ns2", "ns-bob", "NSBob")
+
+	env.request("GET", "/api/v1/me/notification-settings", nil, jsonHeaders()).AssertStatus(t, 401)
+
+	// 初期状態は全種類が有効
+	resp := env.request("GET", "/api/v1/me/notification-settings", nil, authHeaders(alice.ID))
+	resp.AssertStatus(t, 200)
+	data := resp.JSON(t)
+	types, _ := data["types"].(map[string]any)
+	testutil.AssertJSON(t, types, map[string]any{
+		"tag_movie_added": true,
+		"user_followed":   true,
+	})
+
+	tag := &model.Tag{UserID: bob.ID, Title: "ボブのタグ", IsPublic: true}
+	privateTag := &model.Tag{UserID: bob.ID, Title: "非公開", IsPublic: false}
+	for _, tg := range []*model.Tag{tag, privateTag} {
+		if err := env.db.Create(tg).Error; err != nil {
+			t.Fatalf("failed to create tag: %v", err)
+		}
+	}
+
+	body, _ := json.Marshal(map[string]any{
+		"types":        map[string]bool{"user_followed": false},
+		"mute_tag_ids": []string{tag.ID},
+	})
+	resp = env.request("PATCH", "/api/v1/me/notification-settings", body, authHeaders(alice.ID))
+	resp.AssertStatus(t, 200)
+	data = resp.JSON(t)
+	types, _ = data["types"].(map[string]any)
+	testutil.AssertJSON(t, types, map[string]any{
+		"tag_movie_added": true,
+		"user_followed":   false,
+	})
+	muted, _ := data["muted_tags"].([]any)
+	if len(muted) != 1 {
+		t.Fatalf("expected 1 muted tag, got %v", data["muted_tags"])
+	}
+	testutil.AssertJSON(t, muted[0].(map[string]any), map[string]any{"id": tag.ID, "title": "ボブのタグ"})
+
+	// 不明な種類・UUID 形式でないタグID・他人の非公開タグ
+	invalid, _ := json.Marshal(map[string]any{"types": map[string]bool{"unknown": false}})
+	env.request("PATCH", "/api/v1/me/notification-settings", invalid, authHeaders(alice.ID)).AssertStatus(t, 400)
+	malformed, _ := json.Marshal(map[string]any{"mute_tag_ids": []string{"not-a-uuid"}})
+	env.request("PATCH", "/api/v1/me/notification-settings", malformed, authHeaders(alice.ID)).AssertStatus(t, 400)
+	private, _ := json.Marshal(map[string]any{"mute_tag_ids": []string{privateTag.ID}})
+	env.request("PATCH", "/api/v1/me/notification-settings", private, authHeaders(alice.ID)).AssertStatus(t, 404)
+
+	// ミュート解除
+	unmute, _ := json.Marshal(map[string]any{"unmute_tag_ids": []string{tag.ID}})
+	resp = env.request("PATCH", "/api/v1/me/notification-settings", unmute, authHeaders(alice.ID))
+	resp.AssertStatus(t, 200)
+	data = resp.JSON(t)
+	if muted, _ := data["muted_tags"].([]any); len(muted) != 0 {
+		t.Fatalf("expected no muted tags, got %v", data["muted_tags"])
+	}
+}
+
+// 受け取らない設定にした種類の通知が作成されないことを確認する。
+func TestNotificationSettings_FiltersRecipients(t *testing.T) {
+	env := setupTestEnv(t)
+	alice := env.createUser(t, "clerk_ns3", "ns-owner", "NSOwner")
+	bob := env.createUser(t, "clerk_ns4", "ns-follower", "NSFollower")
+
+	tag := &model.Tag{UserID: alice.ID, Title: "SF", IsPublic: true}
+	if err := env.db.Create(tag).Error; err != nil {
+		t.Fatalf("failed to create tag: %v", err)
+	}
+
+	body, _ := json.Marshal(map[string]any{"types": map[string]bool{"user_followed": false}})
+	env.request("PATCH", "/api/v1/me/notification-settings", body, authHeaders(alice.ID)).AssertStatus(t, 200)
+
+	env.request("POST", "/api/v1/users/ns-owner/follow", nil, authHeaders(bob.ID)).AssertStatus(t, 200)
+	env.request("POST", "/api/v1/tags/"+tag.ID+"/follow", nil, authHeaders(bob.ID)).AssertStatus(t, 200)
+
+	// 通知は非同期で作成されるため、有効な種類の通知が届くまで待つ
+	var data map[string]any
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		resp := env.request("GET", "/api/v1/notifications", nil, authHeaders(alice.ID))
+		resp.AssertStatus(t, 200)
+		data = resp.JSON(t)
+		if total, _ := data["total"].(float64); total >= 1 || time.Now().After(deadline) {
+			break
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+
+	notifications, _ := data["notifications"].([]any)
+	if len(notifications) != 1 {
+		t.Fatalf("expected 1 notification, got %v", data)
+	}
+	testutil.AssertJSON(t, notifications[0].(map[string]any), map[string]any{"notification_type": "tag_followed"})
+}
+
+// GET /api/v1/notifications
+// 同じタグへのフォローが1件の通知にまとめられ、未読数もまとめた単位で数えられることを確認する。
+func TestNotifications_GroupsTagFollows(t *testing.T) {
+	env := setupTestEnv(t)
+	owner := env.createUser(t, "clerk_ng1", "ng-owner", "NGOwner")
+	followers := []*model.User{
+		env.createUser(t, "clerk_ng2", "ng-a", "NGA"),
+		env.createUser(t, "clerk_ng3", "ng-b", "NGB"),
+		env.createUser(t, "clerk_ng4", "ng-c", "NGC"),
+	}
+
+	tag := &model.Tag{UserID: owner.ID, Title: "SF", IsPublic: true}
+	if err := env.db.Create(tag).Error; err != nil {
+		t.Fatalf("failed to create tag: %v", err)
+	}
+
+	// 通知は非同期で作成されるため、まとめたイベント数が want になるまで待つ
+	waitForGroupedNotification := func(want float64) map[string]any {
+		t.Helper()
+		var data map[string]any
+		deadline := time.Now().Add(5 * time.Second)
+		for {
+			resp := env.request("GET", "/api/v1/notifications", nil, authHeaders(owner.ID))
+			resp.AssertStatus(t, 200)
+			data = resp.JSON(t)
+			notifications, _ := data["notifications"].([]any)
+			if len(notifications) == 1 {
+				if count, _ := notifications[0].(map[string]any)["count"].(float64); count == want {
+					return data
+				}
+			}
+			if time.Now().After(deadline) {
+				t.Fatalf("expected 1 notification with count=%v, got %v", want, data)
+			}
+			time.Sleep(100 * time.Millisecond)
+		}
+	}
+
+	for _, f := range followers {
+		env.request("POST", "/api/v1/tags/"+tag.ID+"/follow", nil, authHeaders(f.ID)).AssertStatus(t, 200)
+	}
+
+	data := waitForGroupedNotification(3)
+	testutil.AssertJSON(t, data, map[string]any{"total": float64(1)})
+	item := data["notifications"].([]any)[0].(map[string]any)
+	testutil.AssertJSON(t, item, map[string]any{
+		"notification_type": "tag_followed",
+		"is_read":           false,
+		"actor_count":       float64(3),
+	})
+	if actors, _ := item["actors"].([]any); len(actors) != 2 {
+		t.Fatalf("expected 2 sample actors, got %v", item["actors"])
+	}
+
+	resp := env.request("GET", "/api/v1/notifications/unread-count", nil, authHeaders(owner.ID))
+	resp.AssertStatus(t, 200)
+	testutil.AssertJSON(t, resp.JSON(t), map[string]any{"unread_count": float64(1)})
+
+	// 既読にした後に新しいイベントがまとめられると、未読に戻る
+	env.request("PATCH", "/api/v1/notifications/read-all", nil, authHeaders(owner.ID)).AssertStatus(t, 204)
+	env.request("DELETE", "/api/v1/tags/"+tag.ID+"/follow", nil, authHeaders(followers[0].ID)).AssertStatus(t, 200)
+	env.request("POST", "/api/v1/tags/"+tag.ID+"/follow", nil, authHeaders(followers[0].ID)).AssertStatus(t, 200)
+
+	data = waitForGroupedNotification(4)
+	item = data["notifications"].([]any)[0].(map[string]any)
+	testutil.AssertJSON(t, item, map[string]any{"is_read": false, "actor_count": float64(3)})
+
+	// 最後のアクターをミュートしても通知は残り、ミュートしたユーザーのイベントは数えない
+	env.request("POST", "/api/v1/users/ng-a/mute", nil, authHeaders(owner.ID)).AssertStatus(t, 200)
+	data = waitForGroupedNotification(2)
+	item = data["notifications"].([]any)[0].(map[string]any)
+	testutil.AssertJSON(t, item, map[string]any{"actor_count": float64(2)})
+	testutil.AssertJSON(t, item["actor"].(map[string]any), map[string]any{"id": followers[2].ID})
+	resp = env.request("GET", "/api/v1/notifications/unread-count", nil, authHeaders(owner.ID))
+	resp.AssertStatus(t, 200)
+	testutil.AssertJSON(t, resp.JSON(t), map[string]any{"unread_count": float64(1)})
+	env.request("DELETE", "/api/v1/users/ng-a/mute", nil, authHeaders(owner.ID)).AssertStatus(t, 200)
+
+	// 最初のイベントから時間幅を過ぎていても、最後のイベントから時間幅内であればまとめる
+	env.db.Exec("UPDATE notifications SET created_at = NOW() - INTERVAL '3 hours', updated_at = NOW() - INTERVAL '50 minutes' WHERE recipient_user_id = ?", owner.ID)
+	env.request("DELETE", "/api/v1/tags/"+tag.ID+"/follow", nil, authHeaders(followers[1].ID)).AssertStatus(t, 200)
+	env.request("POST", "/api/v1/tags/"+tag.ID+"/follow", nil, authHeaders(followers[1].ID)).AssertStatus(t, 200)
+	waitForGroupedNotification(5)
+}
+
+// PATCH /api/v1/notifications/:notificationId/archive, DELETE /api/v1/notifications/:notificationId, DELETE /api/v1/notifications?read=true
+// アーカイブした通知が一覧・未読数から外れ、削除した通知が一覧から消えることを確認する。
+func TestNotifications_ArchiveAndDelete(t *testing.T) {
+	env := setupTestEnv(t)
+	alice := env.createUser(t, "clerk_nd1", "nd-alice", "NDAlice")
+	bob := env.createUser(t, "clerk_nd2", "nd-bob", "NDBob")
+
+	now := time.Now()
+	newNotification := func(isRead bool) *model.Notification {
+		actor := bob.ID
+		n := &model.Notification{
+			RecipientUserID:  alice.ID,
+			ActorUserID:      &actor,
+			NotificationType: model.NotificationTypeUserFollowed,
+			IsRead:           isRead,
+			CreatedAt:        now,
+			UpdatedAt:        now,
+		}
+		if err := env.db.Create(n).Error; err != nil {
+			t.Fatalf("failed to create notification: %v", err)
+		}
+		return n
+	}
+	unread := newNotification(false)
+	read1 := newNotification(true)
+	newNotification(true)
+
+	// アーカイブした通知は一覧・未読数から外れ、archived=true で取得できる
+	env.request("PATCH", "/api/v1/notifications/"+unread.ID+"/archive", nil, authHeaders(alice.ID)).AssertStatus(t, 204)
+	env.request("PATCH", "/api/v1/notifications/"+unread.ID+"/archive", nil, authHeaders(bob.ID)).AssertStatus(t, 404)
+
+	resp := env.request("GET", "/api/v1/notifications", nil, authHeaders(alice.ID))
+	resp.AssertStatus(t, 200)
+	testutil.AssertJSON(t, resp.JSON(t), map[string]any{"total": float64(2)})
+	resp = env.request("GET", "/api/v1/notifications?archived=true", nil, authHeaders(alice.ID))
+	resp.AssertStatus(t, 200)
+	data := resp.JSON(t)
+	testutil.AssertJSON(t, data, map[string]any{"total": float64(1)})
+	testutil.AssertJSON(t, data["notifications"].([]any)[0].(map[string]any), map[string]any{"id": unread.ID, "is_archived": true})
+	resp = env.request("GET", "/api/v1/notifications/unread-count", nil, authHeaders(alice.ID))
+	resp.AssertStatus(t, 200)
+	testutil.AssertJSON(t, resp.JSON(t), map[string]any{"unread_count": float64(0)})
+
+	env.request("PATCH", "/api/v1/notifications/"+unread.ID+"/unarchive", nil, authHeaders(alice.ID)).AssertStatus(t, 204)
+	resp = env.request("GET", "/api/v1/notifications/unread-count", nil, authHeaders(alice.ID))
+	resp.AssertStatus(t, 200)
+	testutil.AssertJSON(t, resp.JSON(t), map[string]any{"unread_count": float64(1)})
+
+	// 他人の通知は削除できない
+	env.request("DELETE", "/api/v1/notifications/"+read1.ID, nil, authHeaders(bob.ID)).AssertStatus(t, 404)
+	env.request("DELETE", "/api/v1/notifications/"+read1.ID, nil, authHeaders(alice.ID)).AssertStatus(t, 204)
+	env.request("DELETE", "/api/v1/notifications/"+read1.ID, nil, authHeaders(alice.ID)).AssertStatus(t, 404)
+
+	// 既読通知のみをまとめて削除する
+	env.request("DELETE", "/api/v1/notifications", nil, authHeaders(alice.ID)).AssertStatus(t, 400)
+	resp = env.request("DELETE", "/api/v1/notifications?read=true", nil, authHeaders(alice.ID))
+	resp.AssertStatus(t, 200)
+	testutil.AssertJSON(t, resp.JSON(t), map[string]any{"deleted_count": float64(1)})
+
+	var remaining []model.Notification
+	env.db.Where("recipient_user_id = ?", alice.ID).Find(&remaining)
+	if len(remaining) != 1 || remaining[0].ID != unread.ID {
+		t.Fatalf("expected only the unread notification to remain, got %+v", remaining)
+	}
+}
+
+// 保持期間を過ぎた既読通知と、ユーザーごとの上限を超えた古い未読通知が削除されることを確認する。
+func TestNotificationRepository_Retention(t *testing.T) {
+	env := setupTestEnv(t)
+	alice := env.createUser(t, "clerk_nr1", "nr-alice", "NRAlice")
+	bob := env.createUser(t, "clerk_nr2", "nr-bob", "NRBob")
+	repo := repository.NewNotificationRepository(env.db)
+	ctx := context.Background()
+
+	now := time.Now()
+	create := func(recipientID string, isRead bool, at time.Time) *model.Notification {
+		n := &model.Notification{
+			RecipientUserID:  recipientID,
+			NotificationType: model.NotificationTypeUserFollowed,
+			IsRead:           isRead,
+			CreatedAt:        at,
+			UpdatedAt:        at,
+		}
+		if err := env.db.Create(n).Error; err != nil {
+			t.Fatalf("failed to create notification: %v", err)
+		}
+		return n
+	}
+	oldRead := create(alice.ID, true, now.Add(-100*24*time.Hour))
+	create(alice.ID, true, now.Add(-time.Hour))
+	oldUnread := create(alice.ID, false, now.Add(-3*time.Hour))
+	create(alice.ID, false, now.Add(-2*time.Hour))
+	create(alice.ID, false, now.Add(-time.Hour))
+	create(bob.ID, false, now.Add(-3*time.Hour))
+
+	deleted, err := repo.DeleteReadBefore(ctx, now.Add(-90*24*time.Hour), 100)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if deleted != 1 {
+		t.Fatalf("expected 1 read notification to be deleted, got %d", deleted)
+	}
+
+	deleted, err = repo.DeleteUnreadOverLimit(ctx, 2, 100)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if deleted != 1 {
+		t.Fatalf("expected 1 unread notification to be deleted, got %d", deleted)
+	}
+
+	for _, id := range []string{oldRead.ID, oldUnread.ID} {
+		var count int64
+		env.db.Model(&model.Notification{}).Where("id = ?", id).Count(&count)
+		if count != 0 {
+			t.Fatalf("expected notification %s to be deleted", id)
+		}
+	}
+	var count int64
+	env.db.Model(&model.Notification{}).Count(&count)
+	if count != 4 {
+		t.Fatalf("expected 4 notifications to remain, got %d", count)
+	}
+}
+
+// 通知ストリームのカーソル（stream_seq）が、作成時とまとめた通知へのイベント追加時に進むことを確認する。
+func TestNotificationRepository_StreamSeq(t *testing.T) {
+	env := setupTestEnv(t)
+	alice := env.createUser(t, "clerk_ns1", "ns-alice", "NSAlice")
+	bob := env.createUser(t, "clerk_ns2", "ns-bob", "NSBob")
+	repo := repository.NewNotificationRepository(env.db)
+	ctx := context.Background()
+
+	start, err := repo.LatestStreamSeq(ctx, alice.ID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if start != 0 {
+		t.Fatalf("expected 0 without notifications, got %d", start)
+	}
+
+	groupKey := model.NotificationTypeUserFollowed
+	grouped := func() {
+		t.Helper()
+		now := time.Now()
+		actor := bob.ID
+		key := groupKey
+		err := repo.CreateGroupedBatch(ctx, []*model.Notification{{
+			RecipientUserID:  alice.ID,
+			ActorUserID:      &actor,
+			NotificationType: model.NotificationTypeUserFollowed,
+			GroupKey:         &key,
+			EventCount:       1,
+			CreatedAt:        now,
+			UpdatedAt:        now,
+		}}, model.NotificationEvent{ActorUserID: bob.ID, CreatedAt: now}, now.Add(-time.Hour))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	grouped()
+	first, err := repo.LatestStreamSeq(ctx, alice.ID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	rows, err := repo.ListUpdatedSince(ctx, alice.ID, 0, 10)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(rows) != 1 || rows[0].StreamSeq != first {
+		t.Fatalf("expected 1 notification at seq %d, got %+v", first, rows)
+	}
+
+	// まとめた通知にイベントが加わると、同じ通知が新しい stream_seq で返る
+	grouped()
+	rows, err = repo.ListUpdatedSince(ctx, alice.ID, first, 10)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(rows) != 1 || rows[0].StreamSeq <= first || rows[0].EventCount != 2 {
+		t.Fatalf("expected the grouped notification to be returned again, got %+v", rows)
+	}
+}
+
+// 同じアウトボックスイベントの再配信では、通知・まとめた通知のイベント・フィードイベントを重複して作成しないことを確認する。
+func TestNotificationRepository_SourceEventIdempotency(t *testing.T) {
+	env := setupTestEnv(t)
+	alice := env.createUser(t, "clerk_se1", "se-alice", "SEAlice")
+	bob := env.createUser(t, "clerk_se2", "se-bob", "SEBob")
+	repo := repository.NewNotificationRepository(env.db)
+	feedRepo := repository.NewFeedEventRepository(env.db)
+	ctx := context.Background()
+
+	sourceEventID := "33333333-3333-3333-3333-333333333333"
+	for range 2 {
+		actor := bob.ID
+		err := repo.CreateBatch(ctx, []*model.Notification{{
+			RecipientUserID:  alice.ID,
+			ActorUserID:      &actor,
+			NotificationType: model.NotificationTypeFollowRequested,
+			SourceEventID:    &sourceEventID,
+		}})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if err := feedRepo.Create(ctx, &model.FeedEvent{
+			ActorUserID:   bob.ID,
+			EventType:     model.FeedEventTypeUserFollowed,
+			TargetUserID:  &alice.ID,
+			SourceEventID: &sourceEventID,
+		}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		now := time.Now()
+		key := model.NotificationTypeUserFollowed
+		err = repo.CreateGroupedBatch(ctx, []*model.Notification{{
+			RecipientUserID:  alice.ID,
+			ActorUserID:      &actor,
+			NotificationType: model.NotificationTypeUserFollowed,
+			GroupKey:         &key,
+			EventCount:       1,
+			SourceEventID:    &sourceEventID,
+			CreatedAt:        now,
+			UpdatedAt:        now,
+		}}, model.NotificationEvent{ActorUserID: bob.ID, SourceEventID: &sourceEventID, CreatedAt: now}, now.Add(-time.Hour))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	var count int64
+	env.db.Model(&model.Notification{}).Where("recipient_user_id = ? AND group_key IS NULL", alice.ID).Count(&count)
+	if count != 1 {
+		t.Fatalf("expected 1 notification, got %d", count)
+	}
+	env.db.Model(&model.FeedEvent{}).Where("source_event_id = ?", sourceEventID).Count(&count)
+	if count != 1 {
+		t.Fatalf("expected 1 feed event, got %d", count)
+	}
+	var grouped model.Notification
+	env.db.First(&grouped, "recipient_user_id = ? AND group_key IS NOT NULL", alice.ID)
+	env.db.Model(&model.NotificationEvent{}).Where("notification_id = ?", grouped.ID).Count(&count)
+	if count != 1 || grouped.EventCount != 1 {
+		t.Fatalf("expected the grouped notification to have 1 event, got events=%d event_count=%d", count, grouped.EventCount)
+	}
+}
@@ -0,0 +1,163 @@
+//go:build integration
+
+package integration
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"cinetag-backend/src/internal/model"
+	"cinetag-backend/src/internal/repository"
+)
+
+// POST /api/v1/tags/:tagId/follow
+// フォローと同じトランザクションで通知イベントがアウトボックスに書き込まれ、ディスパッチャーが配信することを確認する。
+func TestOutbox_DeliversNotificationEvents(t *testing.T) {
+	env := setupTestEnv(t)
+	owner := env.createUser(t, "clerk_ob1", "ob-owner", "OBOwner")
+	follower := env.createUser(t, "clerk_ob2", "ob-follower", "OBFollower")
+
+	tag := &model.Tag{UserID: owner.ID, Title: "SF", IsPublic: true}
+	if err := env.db.Create(tag).Error; err != nil {
+		t.Fatalf("failed to create tag: %v", err)
+	}
+
+	env.request("POST", "/api/v1/tags/"+tag.ID+"/follow", nil, authHeaders(follower.ID)).AssertStatus(t, 200)
+	// 失敗した状態変更（フォロー済み）ではイベントを書き込まない
+	env.request("POST", "/api/v1/tags/"+tag.ID+"/follow", nil, authHeaders(follower.ID)).AssertStatus(t, 409)
+
+	var event model.OutboxEvent
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		if err := env.db.Where("event_type = ?", model.OutboxEventTypeTagFollowed).First(&event).Error; err != nil {
+			t.Fatalf("expected outbox event to be written: %v", err)
+		}
+		if event.Status == model.OutboxStatusDone || time.Now().After(deadline) {
+			break
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+	if event.Status != model.OutboxStatusDone || event.ProcessedAt == nil || event.Attempts != 1 {
+		t.Fatalf("expected event to be delivered once, got %+v", event)
+	}
+
+	var count int64
+	env.db.Model(&model.OutboxEvent{}).Count(&count)
+	if count != 1 {
+		t.Fatalf("expected 1 outbox event, got %d", count)
+	}
+	env.db.Model(&model.Notification{}).
+		Where("recipient_user_id = ? AND notification_type = ?", owner.ID, model.NotificationTypeTagFollowed).
+		Count(&count)
+	if count != 1 {
+		t.Fatalf("expected 1 notification, got %d", count)
+	}
+}
+
+// 配信待ちの同じキーのイベントは重複させず、確保したイベントは他のワーカーに渡らないことを確認する。
+func TestOutboxRepository_EnqueueAndClaim(t *testing.T) {
+	env := setupTestEnv(t)
+	repo := repository.NewOutboxRepository(env.db)
+	ctx := context.Background()
+
+	// テスト中に動いているディスパッチャーが確保しないよう、配信時期を未来にする
+	now := time.Now().Add(time.Hour)
+	newEvent := func() *model.OutboxEvent {
+		return &model.OutboxEvent{
+			EventType:      "test.event",
+			Payload:        []byte(`{}`),
+			IdempotencyKey: "test.event:1",
+			Status:         model.OutboxStatusPending,
+			NextAttemptAt:  now,
+		}
+	}
+
+	if err := repo.Enqueue(ctx, newEvent()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := repo.Enqueue(ctx, newEvent()); err != nil {
+		t.Fatalf("duplicate key should be ignored: %v", err)
+	}
+	var count int64
+	env.db.Model(&model.OutboxEvent{}).Where("idempotency_key = ?", "test.event:1").Count(&count)
+	if count != 1 {
+		t.Fatalf("expected 1 pending event, got %d", count)
+	}
+
+	claimed, err := repo.ClaimDue(ctx, now, now.Add(time.Minute), "11111111-1111-1111-1111-111111111111", 10)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(claimed) != 1 || claimed[0].Attempts != 1 {
+		t.Fatalf("expected 1 claimed event, got %+v", claimed)
+	}
+	// 確保期限内は他のワーカーが取得できない
+	again, err := repo.ClaimDue(ctx, now, now.Add(time.Minute), "22222222-2222-2222-2222-222222222222", 10)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(again) != 0 {
+		t.Fatalf("expected no events while locked, got %+v", again)
+	}
+
+	// 別のワーカーのトークンでは更新できない
+	if err := repo.MarkDone(ctx, claimed[0].ID, "22222222-2222-2222-2222-222222222222", now); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var got model.OutboxEvent
+	env.db.First(&got, "id = ?", claimed[0].ID)
+	if got.Status != model.OutboxStatusPending {
+		t.Fatalf("expected event to stay pending, got %s", got.Status)
+	}
+
+	// リトライせずに破棄すると、同じキーのイベントを再び積める
+	if err := repo.MarkFailed(ctx, claimed[0].ID, "11111111-1111-1111-1111-111111111111", "boom", nil, nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	env.db.First(&got, "id = ?", claimed[0].ID)
+	if got.Status != model.OutboxStatusDead || got.LastError == nil || *got.LastError != "boom" {
+		t.Fatalf("expected event to be dead, got %+v", got)
+	}
+	if err := repo.Enqueue(ctx, newEvent()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	env.db.Model(&model.OutboxEvent{}).Where("idempotency_key = ?", "test.event:1").Count(&count)
+	if count != 2 {
+		t.Fatalf("expected a new event after the previous one was dead, got %d", count)
+	}
+}
+
+// 振り分け元のイベントが同じ配信イベントは、配信済みになった後も重複して積まないことを確認する。
+func TestOutboxRepository_EnqueueIgnoresRedeliveredSourceEvent(t *testing.T) {
+	env := setupTestEnv(t)
+	repo := repository.NewOutboxRepository(env.db)
+	ctx := context.Background()
+
+	now := time.Now().Add(time.Hour)
+	sourceEventID := "44444444-4444-4444-4444-444444444444"
+	newDelivery := func() *model.OutboxEvent {
+		return &model.OutboxEvent{
+			EventType:      model.OutboxEventTypeWebhookDelivery,
+			Payload:        []byte(`{}`),
+			IdempotencyKey: "webhook.deliver:w1:" + sourceEventID,
+			SourceEventID:  &sourceEventID,
+			Status:         model.OutboxStatusPending,
+			NextAttemptAt:  now,
+		}
+	}
+
+	if err := repo.Enqueue(ctx, newDelivery()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	env.db.Model(&model.OutboxEvent{}).Where("source_event_id = ?", sourceEventID).Update("status", model.OutboxStatusDone)
+	if err := repo.Enqueue(ctx, newDelivery()); err != nil {
+		t.Fatalf("redelivered source event should be ignored: %v", err)
+	}
+
+	var count int64
+	env.db.Model(&model.OutboxEvent{}).Where("source_event_id = ?", sourceEventID).Count(&count)
+	if count != 1 {
+		t.Fatalf("expected 1 delivery, got %d", count)
+	}
+}
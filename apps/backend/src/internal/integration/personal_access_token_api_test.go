@@ -0,0 +1,78 @@
+//go:build integration
+
+package integration
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"cinetag-backend/src/internal/model"
+	"cinetag-backend/src/internal/repository"
+	"cinetag-backend/src/internal/service"
+	"cinetag-backend/src/internal/testutil"
+)
+
+// POST/GET/DELETE /api/v1/me/access-tokens
+// トークンを発行するとハッシュのみが保存され、照合・失効できることを確認する。
+func TestPersonalAccessToken_CreateAuthenticateRevoke(t *testing.T) {
+	env := setupTestEnv(t)
+	owner := env.createUser(t, "clerk_pat1", "pat-owner", "PATOwner")
+	other := env.createUser(t, "clerk_pat2", "pat-other", "PATOther")
+
+	// 不明なスコープは指定できない
+	invalid, _ := json.Marshal(map[string]any{"name": "bot", "scopes": []string{"admin"}})
+	env.request("POST", "/api/v1/me/access-tokens", invalid, authHeaders(owner.ID)).AssertStatus(t, 400)
+
+	body, _ := json.Marshal(map[string]any{"name": "bot", "scopes": []string{"tags:write", "tags:read"}, "expires_in_days": 7})
+	resp := env.request("POST", "/api/v1/me/access-tokens", body, authHeaders(owner.ID))
+	resp.AssertStatus(t, 201)
+	created := resp.JSON(t)
+	tokenID, _ := created["id"].(string)
+	rawToken, _ := created["token"].(string)
+	if tokenID == "" || !strings.HasPrefix(rawToken, service.AccessTokenPrefix) {
+		t.Fatalf("unexpected response: %v", created)
+	}
+
+	var stored model.PersonalAccessToken
+	if err := env.db.First(&stored, "id = ?", tokenID).Error; err != nil {
+		t.Fatalf("failed to load token: %v", err)
+	}
+	if stored.TokenHash == rawToken || !strings.HasPrefix(rawToken, stored.TokenPrefix) {
+		t.Fatalf("token must be stored as a hash: %+v", stored)
+	}
+
+	resp = env.request("GET", "/api/v1/me/access-tokens", nil, authHeaders(owner.ID))
+	resp.AssertStatus(t, 200)
+	items, _ := resp.JSON(t)["items"].([]any)
+	if len(items) != 1 {
+		t.Fatalf("expected 1 token, got %v", items)
+	}
+	item, _ := items[0].(map[string]any)
+	testutil.AssertJSON(t, item, map[string]any{"id": tokenID, "name": "bot"})
+	if _, ok := item["token"]; ok {
+		t.Fatalf("token must not be listed: %v", item)
+	}
+
+	tokenService := service.NewPersonalAccessTokenService(testutil.NewTestLogger(), repository.NewPersonalAccessTokenRepository(env.db), repository.NewUserRepository(testutil.NewTestLogger(), env.db))
+	token, user, err := tokenService.Authenticate(context.Background(), rawToken)
+	if err != nil {
+		t.Fatalf("failed to authenticate: %v", err)
+	}
+	if user.ID != owner.ID || !token.HasScope(model.AccessTokenScopeTagsWrite) || token.HasScope(model.AccessTokenScopeFeedRead) {
+		t.Fatalf("unexpected result: token=%+v user=%+v", token, user)
+	}
+	if err := env.db.First(&stored, "id = ?", tokenID).Error; err != nil || stored.LastUsedAt == nil {
+		t.Fatalf("expected last_used_at to be updated: %+v (%v)", stored, err)
+	}
+
+	// 他のユーザーのトークンは失効できない
+	env.request("DELETE", "/api/v1/me/access-tokens/"+tokenID, nil, authHeaders(other.ID)).AssertStatus(t, 404)
+	env.request("DELETE", "/api/v1/me/access-tokens/"+tokenID, nil, authHeaders(owner.ID)).AssertStatus(t, 204)
+
+	if _, _, err := tokenService.Authenticate(context.Background(), rawToken); !errors.Is(err, service.ErrInvalidAccessToken) {
+		t.Fatalf("expected ErrInvalidAccessToken after revoke, got %v", err)
+	}
+}
@@ -5,18 +5,25 @@ package integration
 import (
 	"context"
 	"database/sql"
+	"encoding/base64"
 	"io/fs"
 	"log/slog"
 	"net/http"
 	"os"
+	"strconv"
 	"testing"
+	"time"
 
 	"cinetag-backend/src/internal/handler"
+	"cinetag-backend/src/internal/middleware"
 	"cinetag-backend/src/internal/migration"
 	"cinetag-backend/src/internal/model"
+	"cinetag-backend/src/internal/outbox"
+	"cinetag-backend/src/internal/pubsub"
 	"cinetag-backend/src/internal/repository"
 	"cinetag-backend/src/internal/service"
 	"cinetag-backend/src/internal/testutil"
+	"cinetag-backend/src/internal/webhook"
 
 	"github.com/gin-gonic/gin"
 	_ "github.com/jackc/pgx/v5/stdlib"
@@ -93,11 +100,41 @@ func applyMigrations(t *testing.T, dsn string) {
 	}
 }
 
+// testEmailDigestConfig は結合テストで使うメールダイジェストの設定です。
+// 配信停止リンクの検証のため、テスト側で生成するサービスとも同じ値を使います。
+var testEmailDigestConfig = service.EmailDigestConfig{
+	UnsubscribeSecret: "test-unsubscribe-secret",
+	APIBaseURL:        "http://api.test",
+	AppBaseURL:        "http://app.test",
+}
+
+// testClerkWebhookSecret は結合テストで Clerk Webhook の署名に使う署名鍵です。
+var testClerkWebhookSecret = "whsec_" + base64.StdEncoding.EncodeToString([]byte("test-clerk-webhook-secret"))
+
 // truncateAll は全テーブルを TRUNCATE して各テストの独立性を確保します。
 // 外部キーの依存関係を考慮した順序になっています。
 func truncateAll(t *testing.T, db *gorm.DB) {
 	t.Helper()
 	tables := []string{
+		"outbox_events",
+		"admin_audit_logs",
+		"clerk_webhook_events",
+		"clerk_user_payloads",
+		"personal_access_tokens",
+		"webhook_deliveries",
+		"webhooks",
+		"email_digest_settings",
+		"notification_muted_tags",
+		"notification_preferences",
+		"feed_events",
+		"movie_ratings",
+		"user_movie_statuses",
+		"user_data_exports",
+		"user_follow_requests",
+		"user_mutes",
+		"user_blocks",
+		"user_display_id_histories",
+		"notification_events",
 		"notifications",
 		"tag_likes",
 		"tag_followers",
@@ -129,20 +166,66 @@ func buildRouter(t *testing.T, db *gorm.DB, testUsers map[string]*model.User) *g
 	tagLikeRepo := repository.NewTagLikeRepository(db)
 	userRepo := repository.NewUserRepository(log, db)
 	userFollowerRepo := repository.NewUserFollowerRepository(db)
+	displayIDHistoryRepo := repository.NewUserDisplayIDHistoryRepository(db)
+	userBlockRepo := repository.NewUserBlockRepository(db)
+	userMuteRepo := repository.NewUserMuteRepository(db)
+	followRequestRepo := repository.NewUserFollowRequestRepository(db)
+	exportRepo := repository.NewUserDataExportRepository(db)
+	movieStatusRepo := repository.NewUserMovieStatusRepository(db)
+	movieRatingRepo := repository.NewMovieRatingRepository(db)
 	notifRepo := repository.NewNotificationRepository(db)
+	notifPrefRepo := repository.NewNotificationPreferenceRepository(db)
+	feedEventRepo := repository.NewFeedEventRepository(db)
+	emailDigestRepo := repository.NewEmailDigestRepository(db)
+	outboxRepo := repository.NewOutboxRepository(db)
+	webhookRepo := repository.NewWebhookRepository(db)
+	clerkWebhookEventRepo := repository.NewClerkWebhookEventRepository(db)
+	accessTokenRepo := repository.NewPersonalAccessTokenRepository(db)
+	moderationRepo := repository.NewModerationRepository(db)
 
 	// Services
 	movieService := service.NewMovieService(log, db)
-	notificationService := service.NewNotificationService(log, notifRepo, tagRepo, tagFollowerRepo, userFollowerRepo)
-	tagService := service.NewTagService(log, tagRepo, tagMovieRepo, tagFollowerRepo, tagLikeRepo, movieService, notificationService, "")
-	userService := service.NewUserService(log, db, userRepo, userFollowerRepo, tagFollowerRepo, notificationService)
+	notificationService := service.NewNotificationService(log, notifRepo, notifPrefRepo, tagRepo, tagFollowerRepo, userFollowerRepo, userRepo, userBlockRepo, feedEventRepo, pubsub.NewMemoryHub())
+	tagService := service.NewTagService(log, db, tagRepo, tagMovieRepo, tagFollowerRepo, tagLikeRepo, userBlockRepo, movieStatusRepo, movieRatingRepo, movieService, outboxRepo, "")
+	userService := service.NewUserService(log, db, userRepo, userFollowerRepo, tagFollowerRepo, outboxRepo, displayIDHistoryRepo, userBlockRepo, userMuteRepo, followRequestRepo)
+	exportService := service.NewUserDataExportService(log, db, exportRepo, outboxRepo)
+	movieStatusService := service.NewMovieStatusService(log, movieStatusRepo, movieService)
+	movieRatingService := service.NewMovieRatingService(log, movieRatingRepo, userBlockRepo, movieService)
+	feedService := service.NewFeedService(log, feedEventRepo)
+	emailDigestService := service.NewEmailDigestService(log, emailDigestRepo, notifRepo, nil, testEmailDigestConfig)
+	webhookService := service.NewWebhookService(log, webhookRepo, tagRepo, userRepo, outboxRepo, &testutil.FakeWebhookSender{}, service.WebhookConfig{})
+	clerkWebhookService := service.NewClerkWebhookService(log, clerkWebhookEventRepo, service.ClerkWebhookConfig{Secrets: []string{testClerkWebhookSecret}})
+	accessTokenService := service.NewPersonalAccessTokenService(log, accessTokenRepo, userRepo)
+	adminService := service.NewAdminService(log, db, moderationRepo, userRepo, tagRepo)
+	maintenanceService := service.NewMaintenanceService(log, userService, notificationService, movieService, outboxRepo, webhookRepo, clerkWebhookEventRepo, exportRepo, service.NotificationRetentionPolicy{})
+
+	// Workers（通知・Webhook・エクスポート生成イベントの配信。テストではポーリング間隔を短くする）
+	outboxHandlers := outbox.MergeHandlers(outbox.WebhookHandlers(webhookService), outbox.NotificationHandlers(notificationService), outbox.UserDataExportHandlers(exportService))
+	outboxDispatcher := outbox.NewDispatcher(log, outboxRepo, outboxHandlers, outbox.Config{
+		PollInterval:    50 * time.Millisecond,
+		HandlerTimeouts: map[string]time.Duration{model.OutboxEventTypeUserDataExportRequested: service.UserDataExportTimeout},
+	})
+	outboxDispatcher.Start()
+	t.Cleanup(func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := outboxDispatcher.Shutdown(ctx); err != nil {
+			t.Errorf("outbox dispatcher の停止に失敗: %v", err)
+		}
+	})
 
 	// Handlers
 	tagHandler := handler.NewTagHandler(log, tagService)
-	movieHandler := handler.NewMovieHandler(log, movieService)
-	userHandler := handler.NewUserHandler(log, userService, tagService)
+	movieHandler := handler.NewMovieHandler(log, movieService, movieStatusService, movieRatingService)
+	userHandler := handler.NewUserHandler(log, userService, tagService, movieRatingService, feedService)
 	notificationHandler := handler.NewNotificationHandler(log, notificationService)
-	clerkWebhookHandler := handler.NewClerkWebhookHandler(log, userService)
+	feedHandler := handler.NewFeedHandler(log, feedService)
+	emailDigestHandler := handler.NewEmailDigestHandler(log, emailDigestService)
+	exportHandler := handler.NewUserDataExportHandler(log, exportService)
+	clerkWebhookHandler := handler.NewClerkWebhookHandler(log, userService, clerkWebhookService)
+	webhookHandler := handler.NewWebhookHandler(log, webhookService)
+	accessTokenHandler := handler.NewPersonalAccessTokenHandler(log, accessTokenService)
+	adminHandler := handler.NewAdminHandler(log, adminService, notificationService, maintenanceService)
 
 	// Auth bypass middlewares
 	authMW := testutil.TestAuthMiddleware(testUsers)
@@ -158,6 +241,8 @@ func buildRouter(t *testing.T, db *gorm.DB, testUsers map[string]*model.User) *g
 	api := r.Group("/api/v1")
 	{
 		api.POST("/clerk/webhook", clerkWebhookHandler.HandleWebhook)
+		api.GET("/email/unsubscribe", emailDigestHandler.ConfirmUnsubscribe)
+		api.POST("/email/unsubscribe", emailDigestHandler.Unsubscribe)
 
 		// 公開ルート（認証不要）
 		api.GET("/tags", tagHandler.ListPublicTags)
@@ -165,15 +250,38 @@ func buildRouter(t *testing.T, db *gorm.DB, testUsers map[string]*model.User) *g
 		api.GET("/tags/:tagId/movies", optionalAuthMW, tagHandler.ListTagMovies)
 		api.GET("/tags/:tagId/followers", tagHandler.ListTagFollowers)
 
+		api.GET("/users/search", optionalAuthMW, userHandler.SearchUsers)
 		api.GET("/users/:displayId", userHandler.GetUserByDisplayID)
 		api.GET("/users/:displayId/tags", optionalAuthMW, userHandler.ListUserTags)
-		api.GET("/users/:displayId/following", userHandler.ListFollowing)
-		api.GET("/users/:displayId/followers", userHandler.ListFollowers)
+		api.GET("/users/:displayId/following", optionalAuthMW, userHandler.ListFollowing)
+		api.GET("/users/:displayId/followers", optionalAuthMW, userHandler.ListFollowers)
 		api.GET("/users/:displayId/follow-stats", optionalAuthMW, userHandler.GetUserFollowStats)
+		api.GET("/users/:displayId/stats", optionalAuthMW, userHandler.GetUserStats)
+		api.GET("/users/:displayId/ratings", optionalAuthMW, userHandler.ListUserRatings)
+		api.GET("/users/:displayId/activity", optionalAuthMW, userHandler.ListUserActivity)
 
 		api.GET("/movies/search", movieHandler.SearchMovies)
-		api.GET("/movies/:tmdbMovieId", movieHandler.GetMovieDetail)
+		api.GET("/movies/:tmdbMovieId", optionalAuthMW, movieHandler.GetMovieDetail)
 		api.GET("/movies/:tmdbMovieId/tags", movieHandler.GetMovieTags)
+		api.GET("/movies/:tmdbMovieId/ratings", optionalAuthMW, movieHandler.ListMovieRatings)
+
+		api.POST("/users/me/reactivate", authMW, userHandler.ReactivateMe)
+
+		// 管理者用ルート（メンテナンスジョブのみ X-Admin-Token でも呼び出せる）
+		api.POST("/admin/jobs/:job", testAdminMiddleware(testUsers, true), adminHandler.RunJob)
+		admin := api.Group("/admin")
+		admin.Use(testAdminMiddleware(testUsers, false))
+		{
+			admin.GET("/users", adminHandler.ListUsers)
+			admin.GET("/users/:userId", adminHandler.GetUser)
+			admin.PATCH("/users/:userId/role", adminHandler.UpdateUserRole)
+			admin.POST("/users/:userId/suspend", adminHandler.SuspendUser)
+			admin.POST("/users/:userId/restore", adminHandler.RestoreUser)
+			admin.GET("/users/:userId/notifications", adminHandler.ListUserNotifications)
+			admin.POST("/tags/:tagId/hide", adminHandler.HideTag)
+			admin.POST("/tags/:tagId/unhide", adminHandler.UnhideTag)
+			admin.POST("/clerk/users/:clerkUserId/resync", clerkWebhookHandler.ResyncUser)
+		}
 
 		// 認証必須ルート
 		auth := api.Group("/")
@@ -181,8 +289,27 @@ func buildRouter(t *testing.T, db *gorm.DB, testUsers map[string]*model.User) *g
 		{
 			auth.GET("/users/me", userHandler.GetMe)
 			auth.PATCH("/users/me", userHandler.UpdateMe)
+			auth.DELETE("/users/me", userHandler.DeleteMe)
 			auth.POST("/users/:displayId/follow", userHandler.FollowUser)
 			auth.DELETE("/users/:displayId/follow", userHandler.UnfollowUser)
+			auth.POST("/users/:displayId/block", userHandler.BlockUser)
+			auth.DELETE("/users/:displayId/block", userHandler.UnblockUser)
+			auth.POST("/users/:displayId/mute", userHandler.MuteUser)
+			auth.DELETE("/users/:displayId/mute", userHandler.UnmuteUser)
+			auth.GET("/me/blocks", userHandler.ListBlockedUsers)
+			auth.GET("/me/mutes", userHandler.ListMutedUsers)
+			auth.GET("/me/follow-requests", userHandler.ListFollowRequests)
+			auth.GET("/me/suggestions/users", userHandler.ListUserSuggestions)
+			auth.POST("/me/follow-requests/:displayId/approve", userHandler.ApproveFollowRequest)
+			auth.POST("/me/follow-requests/:displayId/deny", userHandler.DenyFollowRequest)
+			auth.POST("/me/export", exportHandler.RequestExport)
+			auth.GET("/me/export/:exportId", exportHandler.DownloadExport)
+
+			auth.PUT("/me/movies/:tmdbMovieId/status", movieHandler.SetMovieStatus)
+			auth.DELETE("/me/movies/:tmdbMovieId/status", movieHandler.ClearMovieStatus)
+			auth.GET("/me/movie-statuses", movieHandler.ListMyMovieStatuses)
+			auth.PUT("/me/movies/:tmdbMovieId/rating", movieHandler.SetMovieRating)
+			auth.DELETE("/me/movies/:tmdbMovieId/rating", movieHandler.DeleteMovieRating)
 
 			auth.POST("/tags", tagHandler.CreateTag)
 			auth.PATCH("/tags/:tagId", tagHandler.UpdateTag)
@@ -199,8 +326,29 @@ func buildRouter(t *testing.T, db *gorm.DB, testUsers map[string]*model.User) *g
 
 			auth.GET("/notifications", notificationHandler.ListNotifications)
 			auth.GET("/notifications/unread-count", notificationHandler.GetUnreadCount)
+			auth.GET("/notifications/stream", notificationHandler.StreamNotifications)
 			auth.PATCH("/notifications/:notificationId/read", notificationHandler.MarkAsRead)
 			auth.PATCH("/notifications/read-all", notificationHandler.MarkAllAsRead)
+			auth.PATCH("/notifications/:notificationId/archive", notificationHandler.ArchiveNotification)
+			auth.PATCH("/notifications/:notificationId/unarchive", notificationHandler.UnarchiveNotification)
+			auth.DELETE("/notifications/:notificationId", notificationHandler.DeleteNotification)
+			auth.DELETE("/notifications", notificationHandler.DeleteNotifications)
+			auth.GET("/me/notification-settings", notificationHandler.GetNotificationSettings)
+			auth.PATCH("/me/notification-settings", notificationHandler.UpdateNotificationSettings)
+			auth.GET("/me/email-digest", emailDigestHandler.GetEmailDigestSettings)
+			auth.PATCH("/me/email-digest", emailDigestHandler.UpdateEmailDigestSettings)
+
+			auth.GET("/me/webhooks", webhookHandler.ListWebhooks)
+			auth.POST("/me/webhooks", webhookHandler.CreateWebhook)
+			auth.PATCH("/me/webhooks/:webhookId", webhookHandler.UpdateWebhook)
+			auth.DELETE("/me/webhooks/:webhookId", webhookHandler.DeleteWebhook)
+			auth.GET("/me/webhooks/:webhookId/deliveries", webhookHandler.ListWebhookDeliveries)
+
+			auth.GET("/me/access-tokens", accessTokenHandler.ListAccessTokens)
+			auth.POST("/me/access-tokens", accessTokenHandler.CreateAccessToken)
+			auth.DELETE("/me/access-tokens/:tokenId", accessTokenHandler.RevokeAccessToken)
+
+			auth.GET("/feed/me", feedHandler.ListMyFeed)
 
 			auth.GET("/me/following-tags", tagHandler.ListFollowingTags)
 			auth.GET("/me/liked-tags", tagHandler.ListLikedTags)
@@ -210,6 +358,35 @@ func buildRouter(t *testing.T, db *gorm.DB, testUsers map[string]*model.User) *g
 	return r
 }
 
+// testAdminMiddleware は middleware.NewAdminMiddleware / NewAdminJobMiddleware と同じ判定を認証バイパスで行うミドルウェアです。
+// allowToken が true で X-Admin-Token ヘッダーがあればトークンで、それ以外は X-Test-User-ID のユーザーのロールで判定します。
+func testAdminMiddleware(testUsers map[string]*model.User, allowToken bool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if token := c.GetHeader(middleware.HeaderAdminToken); allowToken && token != "" {
+			if expected := os.Getenv("ADMIN_API_TOKEN"); expected == "" || token != expected {
+				c.JSON(http.StatusForbidden, gin.H{"error": "forbidden"})
+				c.Abort()
+				return
+			}
+			c.Next()
+			return
+		}
+		user, ok := testUsers[c.GetHeader("X-Test-User-ID")]
+		if !ok {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+			c.Abort()
+			return
+		}
+		if !user.IsAdmin() {
+			c.JSON(http.StatusForbidden, gin.H{"error": "forbidden"})
+			c.Abort()
+			return
+		}
+		c.Set("user", user)
+		c.Next()
+	}
+}
+
 // createUser はテスト用ユーザーを DB に直接作成し、testUsers マップにも登録します。
 func (e *testEnv) createUser(t *testing.T, clerkID, displayID, displayName string) *model.User {
 	t.Helper()
@@ -246,3 +423,19 @@ func jsonHeaders() map[string]string {
 		"Content-Type": "application/json",
 	}
 }
+
+// clerkWebhookHeaders は Clerk（Svix）と同じ形式で署名した Webhook のヘッダーを返します。
+func clerkWebhookHeaders(t *testing.T, svixID string, body []byte) map[string]string {
+	t.Helper()
+	now := time.Now()
+	signature, err := webhook.SignSvix(testClerkWebhookSecret, svixID, now, body)
+	if err != nil {
+		t.Fatalf("failed to sign webhook: %v", err)
+	}
+	return map[string]string{
+		"Content-Type":              "application/json",
+		webhook.HeaderSvixID:        svixID,
+		webhook.HeaderSvixTimestamp: strconv.FormatInt(now.Unix(), 10),
+		webhook.HeaderSvixSignature: signature,
+	}
+}
@@ -3,9 +3,14 @@
 package integration
 
 import (
+	"context"
 	"encoding/json"
 	"testing"
+	"time"
 
+	"cinetag-backend/src/internal/model"
+	"cinetag-backend/src/internal/repository"
+	"cinetag-backend/src/internal/service"
 	"cinetag-backend/src/internal/testutil"
 )
 
@@ -61,6 +66,47 @@ func TestUpdateMe_Success(t *testing.T) {
 	})
 }
 
+// PATCH /api/v1/users/me → GET /api/v1/users/:displayId
+// display_id を変更すると、変更前の display_id へのアクセスが新しい display_id へリダイレクトされることを確認する。
+func TestUpdateMe_ChangeDisplayID_RedirectsOldID(t *testing.T) {
+	env := setupTestEnv(t)
+	user := env.createUser(t, "clerk_rename1", "rename-user1", "RenameUser1")
+
+	body, _ := json.Marshal(map[string]any{
+		"display_id": "Renamed-User1",
+		"bio":        "映画が好きです",
+	})
+	resp := env.request("PATCH", "/api/v1/users/me", body, authHeaders(user.ID))
+	resp.AssertStatus(t, 200)
+
+	data := resp.JSON(t)
+	testutil.AssertJSON(t, data, map[string]any{
+		"id":         user.ID,
+		"display_id": "renamed-user1",
+		"bio":        "映画が好きです",
+	})
+
+	resp = env.request("GET", "/api/v1/users/rename-user1", nil, nil)
+	resp.AssertStatus(t, 302)
+	if loc := resp.Recorder.Header().Get("Location"); loc != "/api/v1/users/renamed-user1" {
+		t.Fatalf("expected Location=/api/v1/users/renamed-user1, got %s", loc)
+	}
+}
+
+// PATCH /api/v1/users/me
+// 他ユーザーが使用中の display_id へ変更しようとすると 409 が返ることを確認する。
+func TestUpdateMe_DisplayIDTaken(t *testing.T) {
+	env := setupTestEnv(t)
+	env.createUser(t, "clerk_taken1", "taken-user1", "TakenUser1")
+	user := env.createUser(t, "clerk_taken2", "taken-user2", "TakenUser2")
+
+	body, _ := json.Marshal(map[string]any{
+		"display_id": "taken-user1",
+	})
+	resp := env.request("PATCH", "/api/v1/users/me", body, authHeaders(user.ID))
+	resp.AssertStatus(t, 409)
+}
+
 // GET /api/v1/users/:displayId
 // display_id でユーザーを取得し、全フィールドが正しく返ることを確認する。
 func TestGetUserByDisplayID_Success(t *testing.T) {
@@ -317,3 +363,466 @@ func TestGetUserFollowStats_Success(t *testing.T) {
 		"is_following":    true,
 	})
 }
+
+// GET /api/v1/users/search
+// 完全一致・前方一致の順に並び、退会済みユーザーを除外し、is_following が返ることを確認する。
+func TestSearchUsers_RanksAndExcludesDeactivated(t *testing.T) {
+	env := setupTestEnv(t)
+	viewer := env.createUser(t, "clerk_sr0", "sr-viewer", "SRViewer")
+	exact := env.createUser(t, "clerk_sr1", "sr-taro", "Taro")
+	prefix := env.createUser(t, "clerk_sr2", "sr-taro-fan", "TaroFan")
+	partial := env.createUser(t, "clerk_sr3", "my-sr-taro", "MyTaro")
+	gone := env.createUser(t, "clerk_sr4", "sr-taro-gone", "TaroGone")
+
+	env.request("POST", "/api/v1/users/sr-taro-fan/follow", nil, authHeaders(viewer.ID)).AssertStatus(t, 200)
+	if err := env.db.Model(&model.User{}).Where("id = ?", gone.ID).Update("deleted_at", time.Now()).Error; err != nil {
+		t.Fatalf("failed to deactivate user: %v", err)
+	}
+
+	resp := env.request("GET", "/api/v1/users/search?q=sr-taro", nil, authHeaders(viewer.ID))
+	resp.AssertStatus(t, 200)
+
+	data := resp.JSON(t)
+	testutil.AssertListResponse(t, data, 3, 1, 20)
+
+	items := testutil.GetItems(t, data)
+	wantIDs := []string{exact.ID, prefix.ID, partial.ID}
+	for i, id := range wantIDs {
+		if items[i]["id"] != id {
+			t.Fatalf("items[%d]: expected id %s, got %v", i, id, items[i]["id"])
+		}
+	}
+	testutil.AssertJSON(t, items[1], map[string]any{
+		"display_id":      "sr-taro-fan",
+		"followers_count": float64(1),
+		"is_following":    true,
+	})
+	testutil.AssertJSON(t, items[0], map[string]any{
+		"is_following": false,
+	})
+
+	// % や _ はワイルドカードではなく文字として検索する
+	resp = env.request("GET", "/api/v1/users/search?q=sr%25", nil, authHeaders(viewer.ID))
+	resp.AssertStatus(t, 200)
+	testutil.AssertListResponse(t, resp.JSON(t), 0, 1, 20)
+	resp = env.request("GET", "/api/v1/users/search?q=sr_taro", nil, authHeaders(viewer.ID))
+	resp.AssertStatus(t, 200)
+	testutil.AssertListResponse(t, resp.JSON(t), 0, 1, 20)
+
+	resp = env.request("GET", "/api/v1/users/search", nil, nil)
+	resp.AssertStatus(t, 400)
+}
+
+// GET /api/v1/me/suggestions/users
+// 友達の友達・共通のフォロータグの順に並び、フォロー中・ブロック中・退会済みのユーザーが除外されることを確認する。
+func TestListUserSuggestions_RanksAndExcludes(t *testing.T) {
+	env := setupTestEnv(t)
+	viewer := env.createUser(t, "clerk_sg0", "sg-viewer", "SGViewer")
+	friend := env.createUser(t, "clerk_sg1", "sg-friend", "SGFriend")
+	fof := env.createUser(t, "clerk_sg2", "sg-fof", "SGFof")
+	tagFan := env.createUser(t, "clerk_sg3", "sg-tagfan", "SGTagFan")
+	blocked := env.createUser(t, "clerk_sg4", "sg-blocked", "SGBlocked")
+	gone := env.createUser(t, "clerk_sg5", "sg-gone", "SGGone")
+	owner := env.createUser(t, "clerk_sg6", "sg-owner", "SGOwner")
+
+	env.request("POST", "/api/v1/users/sg-friend/follow", nil, authHeaders(viewer.ID)).AssertStatus(t, 200)
+	for _, displayID := range []string{"sg-fof", "sg-blocked", "sg-gone"} {
+		env.request("POST", "/api/v1/users/"+displayID+"/follow", nil, authHeaders(friend.ID)).AssertStatus(t, 200)
+	}
+	env.request("POST", "/api/v1/users/sg-blocked/block", nil, authHeaders(viewer.ID)).AssertStatus(t, 200)
+	if err := env.db.Model(&model.User{}).Where("id = ?", gone.ID).Update("deleted_at", time.Now()).Error; err != nil {
+		t.Fatalf("failed to deactivate user: %v", err)
+	}
+
+	createBody, _ := json.Marshal(map[string]any{"title": "おすすめテスト", "is_public": true})
+	createResp := env.request("POST", "/api/v1/tags", createBody, authHeaders(owner.ID))
+	createResp.AssertStatus(t, 201)
+	tagID := createResp.JSON(t)["id"].(string)
+	env.request("POST", "/api/v1/tags/"+tagID+"/follow", nil, authHeaders(viewer.ID)).AssertStatus(t, 200)
+	env.request("POST", "/api/v1/tags/"+tagID+"/follow", nil, authHeaders(tagFan.ID)).AssertStatus(t, 200)
+
+	resp := env.request("GET", "/api/v1/me/suggestions/users", nil, authHeaders(viewer.ID))
+	resp.AssertStatus(t, 200)
+
+	items := testutil.GetItems(t, resp.JSON(t))
+	if len(items) < 2 {
+		t.Fatalf("expected at least 2 items, got %d", len(items))
+	}
+	testutil.AssertJSON(t, items[0], map[string]any{
+		"id":                  fof.ID,
+		"reason":              "followed_by_following",
+		"mutual_follow_count": float64(1),
+	})
+	testutil.AssertJSON(t, items[1], map[string]any{
+		"id":               tagFan.ID,
+		"reason":           "shared_tags",
+		"shared_tag_count": float64(1),
+	})
+	for _, item := range items {
+		switch item["id"] {
+		case viewer.ID, friend.ID, blocked.ID, gone.ID:
+			t.Fatalf("unexpected suggestion: %v", item["display_id"])
+		}
+	}
+}
+
+// GET /api/v1/users/:displayId/stats
+// 他ユーザーには公開タグのみ、本人には非公開タグも含めた統計が返ることを確認する。
+func TestGetUserStats_Success(t *testing.T) {
+	env := setupTestEnv(t)
+	owner := env.createUser(t, "clerk_st1", "st-owner", "STOwner")
+	other := env.createUser(t, "clerk_st2", "st-other", "STOther")
+
+	newTag := func(userID, title string, isPublic bool) *model.Tag {
+		tag := &model.Tag{UserID: userID, Title: title, IsPublic: isPublic}
+		if err := env.db.Create(tag).Error; err != nil {
+			t.Fatalf("failed to create tag: %v", err)
+		}
+		return tag
+	}
+	addMovie := func(tagID string, tmdbMovieID int, addedBy string) {
+		if err := env.db.Create(&model.TagMovie{TagID: tagID, TmdbMovieID: tmdbMovieID, AddedByUser: addedBy}).Error; err != nil {
+			t.Fatalf("failed to add movie: %v", err)
+		}
+	}
+	cacheMovie := func(tmdbMovieID int, releaseDate string, genres string) {
+		d, _ := time.Parse("2006-01-02", releaseDate)
+		cache := &model.MovieCache{
+			TmdbMovieID: tmdbMovieID,
+			Title:       "movie",
+			ReleaseDate: &d,
+			Genres:      []byte(genres),
+			ExpiresAt:   time.Now().Add(time.Hour),
+		}
+		if err := env.db.Create(cache).Error; err != nil {
+			t.Fatalf("failed to create movie cache: %v", err)
+		}
+	}
+
+	cacheMovie(1, "1994-09-23", `[{"id":18,"name":"ドラマ"}]`)
+	cacheMovie(2, "1999-03-31", `[{"id":878,"name":"SF"},{"id":28,"name":"アクション"}]`)
+	cacheMovie(3, "2010-07-16", `[{"id":878,"name":"SF"}]`)
+
+	publicTag := newTag(owner.ID, "公開", true)
+	privateTag := newTag(owner.ID, "非公開", false)
+	otherTag := newTag(other.ID, "他人のタグ", true)
+	addMovie(publicTag.ID, 1, owner.ID)
+	addMovie(publicTag.ID, 2, owner.ID)
+	addMovie(privateTag.ID, 3, owner.ID)
+	addMovie(otherTag.ID, 3, owner.ID)
+
+	env.request("POST", "/api/v1/tags/"+publicTag.ID+"/follow", nil, authHeaders(other.ID)).AssertStatus(t, 200)
+	env.request("POST", "/api/v1/tags/"+publicTag.ID+"/like", nil, authHeaders(other.ID)).AssertStatus(t, 200)
+
+	resp := env.request("GET", "/api/v1/users/st-owner/stats", nil, authHeaders(other.ID))
+	resp.AssertStatus(t, 200)
+	data := resp.JSON(t)
+	testutil.AssertJSON(t, data, map[string]any{
+		"tag_count":               float64(1),
+		"movie_count":             float64(2),
+		"contributed_movie_count": float64(1),
+		"likes_received":          float64(1),
+		"follows_received":        float64(1),
+	})
+	decades, _ := data["top_decades"].([]any)
+	if len(decades) != 1 {
+		t.Fatalf("expected 1 decade, got %v", data["top_decades"])
+	}
+	testutil.AssertJSON(t, decades[0].(map[string]any), map[string]any{
+		"decade":      float64(1990),
+		"movie_count": float64(2),
+	})
+
+	resp = env.request("GET", "/api/v1/users/st-owner/stats", nil, authHeaders(owner.ID))
+	resp.AssertStatus(t, 200)
+	data = resp.JSON(t)
+	testutil.AssertJSON(t, data, map[string]any{
+		"tag_count":   float64(2),
+		"movie_count": float64(3),
+	})
+	genres, _ := data["top_genres"].([]any)
+	if len(genres) != 3 {
+		t.Fatalf("expected 3 genres, got %v", data["top_genres"])
+	}
+	testutil.AssertJSON(t, genres[0].(map[string]any), map[string]any{
+		"name":        "SF",
+		"movie_count": float64(2),
+	})
+}
+
+// POST /api/v1/users/:displayId/block
+// ブロックすると双方向のフォロー関係が削除され、以降はどちらからもフォローできないことを確認する。
+func TestBlockUser_RemovesFollowsAndPreventsFollow(t *testing.T) {
+	env := setupTestEnv(t)
+	blocker := env.createUser(t, "clerk_blk1", "blk-user1", "Blocker1")
+	blocked := env.createUser(t, "clerk_blk2", "blk-user2", "Blocked1")
+
+	env.request("POST", "/api/v1/users/blk-user2/follow", nil, authHeaders(blocker.ID)).AssertStatus(t, 200)
+	env.request("POST", "/api/v1/users/blk-user1/follow", nil, authHeaders(blocked.ID)).AssertStatus(t, 200)
+
+	resp := env.request("POST", "/api/v1/users/blk-user2/block", nil, authHeaders(blocker.ID))
+	resp.AssertStatus(t, 200)
+
+	stats := env.request("GET", "/api/v1/users/blk-user1/follow-stats", nil, nil)
+	stats.AssertStatus(t, 200)
+	testutil.AssertJSON(t, stats.JSON(t), map[string]any{
+		"following_count": float64(0),
+		"followers_count": float64(0),
+	})
+
+	resp = env.request("POST", "/api/v1/users/blk-user1/follow", nil, authHeaders(blocked.ID))
+	resp.AssertStatus(t, 403)
+	testutil.AssertJSON(t, resp.JSON(t), map[string]any{
+		"error": "user is blocked",
+	})
+
+	list := env.request("GET", "/api/v1/me/blocks", nil, authHeaders(blocker.ID))
+	list.AssertStatus(t, 200)
+	testutil.AssertJSON(t, list.JSON(t), map[string]any{
+		"total_count": float64(1),
+	})
+}
+
+// POST /api/v1/users/:displayId/follow, POST /api/v1/me/follow-requests/:displayId/approve
+// 非公開アカウントへのフォローはリクエストとなり、承認されるまでフォロー一覧を閲覧できないことを確認する。
+func TestPrivateAccount_FollowRequestApproveFlow(t *testing.T) {
+	env := setupTestEnv(t)
+	owner := env.createUser(t, "clerk_pa1", "pa-user1", "PAOwner")
+	requester := env.createUser(t, "clerk_pa2", "pa-user2", "PARequester")
+
+	body, _ := json.Marshal(map[string]any{
+		"is_private": true,
+	})
+	resp := env.request("PATCH", "/api/v1/users/me", body, authHeaders(owner.ID))
+	resp.AssertStatus(t, 200)
+	testutil.AssertJSON(t, resp.JSON(t), map[string]any{
+		"is_private": true,
+	})
+
+	resp = env.request("POST", "/api/v1/users/pa-user1/follow", nil, authHeaders(requester.ID))
+	resp.AssertStatus(t, 200)
+	testutil.AssertJSON(t, resp.JSON(t), map[string]any{
+		"message": "follow request sent",
+		"status":  "requested",
+	})
+
+	// 承認前はフォロー一覧・タグ一覧を閲覧できない
+	env.request("GET", "/api/v1/users/pa-user1/followers", nil, authHeaders(requester.ID)).AssertStatus(t, 403)
+	env.request("GET", "/api/v1/users/pa-user1/tags", nil, nil).AssertStatus(t, 403)
+
+	stats := env.request("GET", "/api/v1/users/pa-user1/follow-stats", nil, authHeaders(requester.ID))
+	stats.AssertStatus(t, 200)
+	testutil.AssertJSON(t, stats.JSON(t), map[string]any{
+		"followers_count":     float64(0),
+		"is_following":        false,
+		"is_follow_requested": true,
+	})
+
+	list := env.request("GET", "/api/v1/me/follow-requests", nil, authHeaders(owner.ID))
+	list.AssertStatus(t, 200)
+	testutil.AssertJSON(t, list.JSON(t), map[string]any{
+		"total_count": float64(1),
+	})
+
+	env.request("POST", "/api/v1/me/follow-requests/pa-user2/approve", nil, authHeaders(owner.ID)).AssertStatus(t, 200)
+
+	followers := env.request("GET", "/api/v1/users/pa-user1/followers", nil, authHeaders(requester.ID))
+	followers.AssertStatus(t, 200)
+	testutil.AssertListResponse(t, followers.JSON(t), 1, 1, 20)
+
+	// 承認済みのリクエストは再度承認できない
+	env.request("POST", "/api/v1/me/follow-requests/pa-user2/approve", nil, authHeaders(owner.ID)).AssertStatus(t, 404)
+}
+
+// PATCH /api/v1/users/me
+// 非公開アカウントを公開に切り替えると、承認待ちのフォローリクエストが承認されることを確認する。
+func TestPrivateAccount_SwitchToPublicAcceptsPendingRequests(t *testing.T) {
+	env := setupTestEnv(t)
+	owner := env.createUser(t, "clerk_pp1", "pp-user1", "PPOwner")
+	requester := env.createUser(t, "clerk_pp2", "pp-user2", "PPRequester")
+
+	body, _ := json.Marshal(map[string]any{
+		"is_private": true,
+	})
+	env.request("PATCH", "/api/v1/users/me", body, authHeaders(owner.ID)).AssertStatus(t, 200)
+	env.request("POST", "/api/v1/users/pp-user1/follow", nil, authHeaders(requester.ID)).AssertStatus(t, 200)
+
+	body, _ = json.Marshal(map[string]any{
+		"is_private": false,
+	})
+	env.request("PATCH", "/api/v1/users/me", body, authHeaders(owner.ID)).AssertStatus(t, 200)
+
+	list := env.request("GET", "/api/v1/me/follow-requests", nil, authHeaders(owner.ID))
+	list.AssertStatus(t, 200)
+	testutil.AssertJSON(t, list.JSON(t), map[string]any{
+		"total_count": float64(0),
+	})
+
+	stats := env.request("GET", "/api/v1/users/pp-user1/follow-stats", nil, authHeaders(requester.ID))
+	stats.AssertStatus(t, 200)
+	testutil.AssertJSON(t, stats.JSON(t), map[string]any{
+		"followers_count":     float64(1),
+		"is_following":        true,
+		"is_follow_requested": false,
+	})
+}
+
+// POST /api/v1/me/export, GET /api/v1/me/export/:exportId
+// エクスポートを要求し、生成完了後に JSON ファイルとしてダウンロードできることを確認する。
+func TestUserDataExport_RequestAndDownload(t *testing.T) {
+	env := setupTestEnv(t)
+	user := env.createUser(t, "clerk_ex1", "ex-user1", "ExUser1")
+	other := env.createUser(t, "clerk_ex2", "ex-user2", "ExUser2")
+
+	body, _ := json.Marshal(map[string]any{
+		"format": "json",
+	})
+	resp := env.request("POST", "/api/v1/me/export", body, authHeaders(user.ID))
+	resp.AssertStatus(t, 202)
+	data := resp.JSON(t)
+	testutil.AssertJSON(t, data, map[string]any{
+		"format": "json",
+		"status": "pending",
+	})
+	exportID, _ := data["id"].(string)
+
+	// 他ユーザーのエクスポートは取得できない
+	env.request("GET", "/api/v1/me/export/"+exportID, nil, authHeaders(other.ID)).AssertStatus(t, 404)
+	// UUID 形式でない ID は存在しないものとして扱う
+	env.request("GET", "/api/v1/me/export/not-a-uuid", nil, authHeaders(user.ID)).AssertStatus(t, 404)
+
+	// 生成完了まで待つ
+	var download *testutil.HTTPResponse
+	deadline := time.Now().Add(10 * time.Second)
+	for time.Now().Before(deadline) {
+		download = env.request("GET", "/api/v1/me/export/"+exportID, nil, authHeaders(user.ID))
+		if download.Recorder.Code != 202 {
+			break
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+	download.AssertStatus(t, 200)
+
+	if got := download.Recorder.Header().Get("Content-Disposition"); got == "" {
+		t.Fatalf("expected Content-Disposition header")
+	}
+	bundle := download.JSON(t)
+	profile, ok := bundle["profile"].(map[string]any)
+	if !ok {
+		t.Fatalf("expected profile in bundle, got %v", bundle)
+	}
+	testutil.AssertJSON(t, profile, map[string]any{
+		"id":         user.ID,
+		"display_id": "ex-user1",
+	})
+	testutil.AssertHasKeys(t, bundle, "tags", "contributions", "follows", "liked_tags", "notifications")
+}
+
+// DELETE /api/v1/users/me, POST /api/v1/users/me/reactivate
+// 退会中はタグが非表示になり、再開で元に戻ること、猶予期間後の完全削除でポリシーが適用されることを確認する。
+func TestAccountDeletion_DeactivateReactivateAndPurge(t *testing.T) {
+	env := setupTestEnv(t)
+	user := env.createUser(t, "clerk_del1", "del-user1", "DelUser1")
+	heir := env.createUser(t, "clerk_del2", "del-user2", "DelUser2")
+	other := env.createUser(t, "clerk_del3", "del-user3", "DelUser3")
+
+	createTag := func(owner *model.User, title string) string {
+		body, _ := json.Marshal(map[string]any{"title": title, "is_public": true})
+		resp := env.request("POST", "/api/v1/tags", body, authHeaders(owner.ID))
+		resp.AssertStatus(t, 201)
+		id, _ := resp.JSON(t)["id"].(string)
+		return id
+	}
+	ownTagID := createTag(user, "退会ユーザーのタグ")
+	otherTagID := createTag(other, "他ユーザーのタグ")
+
+	contribution := &model.TagMovie{TagID: otherTagID, TmdbMovieID: 550, AddedByUser: user.ID}
+	if err := env.db.Create(contribution).Error; err != nil {
+		t.Fatalf("failed to create tag movie: %v", err)
+	}
+	webhooks := []*model.Webhook{
+		{UserID: user.ID, TagID: &ownTagID, URL: "https://example.com/tag", Secret: "secret", Events: []string{model.WebhookEventFollowed}},
+		{UserID: user.ID, URL: "https://example.com/account", Secret: "secret", Events: []string{model.WebhookEventFollowed}},
+	}
+	if err := env.db.Create(webhooks).Error; err != nil {
+		t.Fatalf("failed to create webhooks: %v", err)
+	}
+
+	body, _ := json.Marshal(map[string]any{
+		"owned_tags":             "transfer",
+		"transfer_to_display_id": "del-user2",
+		"contributions":          "remove",
+	})
+	resp := env.request("DELETE", "/api/v1/users/me", body, authHeaders(user.ID))
+	resp.AssertStatus(t, 200)
+	testutil.AssertJSON(t, resp.JSON(t), map[string]any{
+		"deletion_status": "deactivated",
+	})
+	testutil.AssertHasKeys(t, resp.JSON(t), "purge_scheduled_at")
+
+	// 退会中はプロフィールと所有タグが非表示になる
+	env.request("GET", "/api/v1/users/del-user1", nil, nil).AssertStatus(t, 404)
+	env.request("GET", "/api/v1/tags/"+ownTagID, nil, nil).AssertStatus(t, 404)
+
+	// タグの ID を指定しても、映画の一覧・追加やフォロー・いいねはできない
+	env.request("GET", "/api/v1/tags/"+ownTagID+"/movies", nil, nil).AssertStatus(t, 404)
+	env.request("GET", "/api/v1/tags/"+ownTagID+"/followers", nil, nil).AssertStatus(t, 404)
+	addMovie, _ := json.Marshal(map[string]any{"movies": []map[string]any{{"tmdb_movie_id": 603}}})
+	env.request("POST", "/api/v1/tags/"+ownTagID+"/movies", addMovie, authHeaders(other.ID)).AssertStatus(t, 404)
+	env.request("POST", "/api/v1/tags/"+ownTagID+"/follow", nil, authHeaders(other.ID)).AssertStatus(t, 404)
+	env.request("POST", "/api/v1/tags/"+ownTagID+"/like", nil, authHeaders(other.ID)).AssertStatus(t, 404)
+
+	// 猶予期間中は再開でき、タグも再表示される
+	resp = env.request("POST", "/api/v1/users/me/reactivate", nil, authHeaders(user.ID))
+	resp.AssertStatus(t, 200)
+	env.request("GET", "/api/v1/tags/"+ownTagID, nil, nil).AssertStatus(t, 200)
+	env.request("POST", "/api/v1/users/me/reactivate", nil, authHeaders(user.ID)).AssertStatus(t, 409)
+
+	// 再度退会し、猶予期間を過ぎた状態で完全削除する
+	env.request("DELETE", "/api/v1/users/me", body, authHeaders(user.ID)).AssertStatus(t, 200)
+	if err := env.db.Model(&model.User{}).Where("id = ?", user.ID).
+		Update("purge_scheduled_at", time.Now().Add(-time.Minute)).Error; err != nil {
+		t.Fatalf("failed to update purge_scheduled_at: %v", err)
+	}
+
+	log := testutil.NewTestLogger()
+	userService := service.NewUserService(log, env.db, repository.NewUserRepository(log, env.db), nil, nil, nil, nil, nil, nil, nil)
+	purged, err := userService.PurgeDeactivatedUsers(context.Background(), time.Now(), 10)
+	if err != nil {
+		t.Fatalf("PurgeDeactivatedUsers failed: %v", err)
+	}
+	if purged != 1 {
+		t.Fatalf("expected 1 purged user, got %d", purged)
+	}
+
+	// 所有タグは譲渡先に移り、他者タグへの追加映画は削除される
+	var tag model.Tag
+	if err := env.db.Where("id = ?", ownTagID).First(&tag).Error; err != nil {
+		t.Fatalf("transferred tag not found: %v", err)
+	}
+	if tag.UserID != heir.ID {
+		t.Fatalf("expected tag owner %s, got %s", heir.ID, tag.UserID)
+	}
+	var contributions int64
+	env.db.Model(&model.TagMovie{}).Where("added_by_user_id = ?", user.ID).Count(&contributions)
+	if contributions != 0 {
+		t.Fatalf("expected contributions to be removed, got %d", contributions)
+	}
+	// 退会したユーザーの Webhook（譲渡したタグのものを含む）は削除され、譲渡先のアクティビティを配信しない
+	var webhookCount int64
+	env.db.Model(&model.Webhook{}).Where("user_id = ? OR tag_id = ?", user.ID, ownTagID).Count(&webhookCount)
+	if webhookCount != 0 {
+		t.Fatalf("expected webhooks to be removed, got %d", webhookCount)
+	}
+
+	var purgedUser model.User
+	if err := env.db.Where("id = ?", user.ID).First(&purgedUser).Error; err != nil {
+		t.Fatalf("purged user not found: %v", err)
+	}
+	if purgedUser.DeletionStatus != model.UserDeletionStatusPurged || purgedUser.DisplayName != "退会済みユーザー" {
+		t.Fatalf("expected anonymized purged user, got status=%s name=%s", purgedUser.DeletionStatus, purgedUser.DisplayName)
+	}
+
+	// 完全削除後は再開できない
+	env.request("POST", "/api/v1/users/me/reactivate", nil, authHeaders(user.ID)).AssertStatus(t, 409)
+}
@@ -0,0 +1,81 @@
+//go:build integration
+
+package integration
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"cinetag-backend/src/internal/model"
+	"cinetag-backend/src/internal/testutil"
+)
+
+// POST/GET /api/v1/me/webhooks, GET /api/v1/me/webhooks/:webhookId/deliveries
+// タグの Webhook を登録すると、購読しているイベントだけが配信され、配信ログに記録されることを確認する。
+func TestWebhook_DeliversSubscribedEvents(t *testing.T) {
+	env := setupTestEnv(t)
+	owner := env.createUser(t, "clerk_wh1", "wh-owner", "WHOwner")
+	follower := env.createUser(t, "clerk_wh2", "wh-follower", "WHFollower")
+
+	tag := &model.Tag{UserID: owner.ID, Title: "SF", IsPublic: true}
+	if err := env.db.Create(tag).Error; err != nil {
+		t.Fatalf("failed to create tag: %v", err)
+	}
+
+	// 他のユーザーのタグには登録できない
+	forbidden, _ := json.Marshal(map[string]any{"tag_id": tag.ID, "url": "https://example.com/hook", "events": []string{"followed"}})
+	env.request("POST", "/api/v1/me/webhooks", forbidden, authHeaders(follower.ID)).AssertStatus(t, 403)
+
+	body, _ := json.Marshal(map[string]any{"tag_id": tag.ID, "url": "https://example.com/hook", "events": []string{"followed"}})
+	resp := env.request("POST", "/api/v1/me/webhooks", body, authHeaders(owner.ID))
+	resp.AssertStatus(t, 201)
+	created := resp.JSON(t)
+	webhookID, _ := created["id"].(string)
+	if secret, _ := created["secret"].(string); webhookID == "" || secret == "" {
+		t.Fatalf("unexpected response: %v", created)
+	}
+
+	resp = env.request("GET", "/api/v1/me/webhooks", nil, authHeaders(owner.ID))
+	resp.AssertStatus(t, 200)
+	items, _ := resp.JSON(t)["items"].([]any)
+	if len(items) != 1 {
+		t.Fatalf("expected 1 webhook, got %v", items)
+	}
+	item, _ := items[0].(map[string]any)
+	testutil.AssertJSON(t, item, map[string]any{"id": webhookID, "is_active": true})
+	if _, ok := item["secret"]; ok {
+		t.Fatalf("secret must not be listed: %v", item)
+	}
+
+	// 購読していないイベント（いいね）は配信しない
+	env.request("POST", "/api/v1/tags/"+tag.ID+"/like", nil, authHeaders(follower.ID)).AssertStatus(t, 200)
+	env.request("POST", "/api/v1/tags/"+tag.ID+"/follow", nil, authHeaders(follower.ID)).AssertStatus(t, 200)
+
+	var deliveries []model.WebhookDelivery
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		if err := env.db.Where("webhook_id = ?", webhookID).Find(&deliveries).Error; err != nil {
+			t.Fatalf("failed to list deliveries: %v", err)
+		}
+		if len(deliveries) > 0 || time.Now().After(deadline) {
+			break
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+	if len(deliveries) != 1 || !deliveries[0].Succeeded || deliveries[0].EventType != model.WebhookEventFollowed || deliveries[0].Attempt != 1 {
+		t.Fatalf("expected 1 successful delivery, got %+v", deliveries)
+	}
+
+	resp = env.request("GET", "/api/v1/me/webhooks/"+webhookID+"/deliveries", nil, authHeaders(owner.ID))
+	resp.AssertStatus(t, 200)
+	testutil.AssertJSON(t, resp.JSON(t), map[string]any{"total_count": float64(1)})
+	env.request("GET", "/api/v1/me/webhooks/"+webhookID+"/deliveries", nil, authHeaders(follower.ID)).AssertStatus(t, 404)
+
+	env.request("DELETE", "/api/v1/me/webhooks/"+webhookID, nil, authHeaders(owner.ID)).AssertStatus(t, 204)
+	var count int64
+	env.db.Model(&model.WebhookDelivery{}).Where("webhook_id = ?", webhookID).Count(&count)
+	if count != 0 {
+		t.Fatalf("expected deliveries to be deleted with webhook, got %d", count)
+	}
+}
@@ -0,0 +1,24 @@
+// Package mailer はメール送信（ダイジェストメールなど）を提供します。
+//
+// 送信処理は Mailer インターフェースで抽象化しており、本番では SMTP、開発環境ではローカルの
+// SMTP サーバー（Mailpit など）を宛先にした SMTPMailer を使います。
+package mailer
+
+import "context"
+
+// Message は送信するメールを表します。
+// TextBody と HTMLBody の両方を指定した場合は multipart/alternative で送信します。
+type Message struct {
+	To       string
+	Subject  string
+	TextBody string
+	HTMLBody string
+	// Headers は追加のヘッダー（List-Unsubscribe など）です。
+	Headers map[string]string
+}
+
+// Mailer はメール送信を表すインターフェース。
+type Mailer interface {
+	// Send はメールを1通送信します。
+	Send(ctx context.Context, msg *Message) error
+}
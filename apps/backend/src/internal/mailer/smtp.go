@@ -0,0 +1,221 @@
+package mailer
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/tls"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"mime"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SMTP サーバーへの接続のタイムアウト。
+const smtpDialTimeout = 10 * time.Second
+
+// 1回の送信（接続から QUIT まで）のタイムアウト。
+const smtpSendTimeout = 30 * time.Second
+
+// base64 エンコードした本文の1行の長さ（RFC 2045 の上限 76 文字）。
+const mimeLineLength = 76
+
+// 宛先・本文が指定されていない場合のエラー。
+var ErrInvalidMessage = errors.New("mailer: message requires a recipient and a body")
+
+// SMTP 送信に必要な設定値。
+type SMTPConfig struct {
+	Host     string
+	Port     int
+	Username string
+	Password string
+	From     string
+}
+
+// SMTP でメールを送信する Mailer の実装。
+// サーバーが STARTTLS に対応している場合は TLS に切り替えてから送信する。
+type smtpMailer struct {
+	cfg SMTPConfig
+}
+
+// SMTP でメールを送信する Mailer を生成する。
+// Username が空の場合は認証せずに送信する（ローカルの SMTP サーバー向け）。
+func NewSMTPMailer(cfg SMTPConfig) Mailer {
+	if cfg.Port == 0 {
+		cfg.Port = 587
+	}
+	return &smtpMailer{cfg: cfg}
+}
+
+// メールを1通送信する。
+func (m *smtpMailer) Send(ctx context.Context, msg *Message) error {
+	if msg == nil || msg.To == "" || (msg.TextBody == "" && msg.HTMLBody == "") {
+		return ErrInvalidMessage
+	}
+	from, err := mail.ParseAddress(m.cfg.From)
+	if err != nil {
+		return fmt.Errorf("mailer: invalid from address: %w", err)
+	}
+	to, err := mail.ParseAddress(msg.To)
+	if err != nil {
+		return fmt.Errorf("mailer: invalid recipient address: %w", err)
+	}
+	body, err := buildMessage(from, to, msg, time.Now())
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, smtpSendTimeout)
+	defer cancel()
+
+	addr := net.JoinHostPort(m.cfg.Host, strconv.Itoa(m.cfg.Port))
+	dialer := &net.Dialer{Timeout: smtpDialTimeout}
+	conn, err := dialer.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return fmt.Errorf("mailer: dial %s: %w", addr, err)
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+	}
+
+	c, err := smtp.NewClient(conn, m.cfg.Host)
+	if err != nil {
+		conn.Close()
+		return fmt.Errorf("mailer: smtp handshake: %w", err)
+	}
+	defer c.Close()
+
+	if ok, _ := c.Extension("STARTTLS"); ok {
+		if err := c.StartTLS(&tls.Config{ServerName: m.cfg.Host}); err != nil {
+			return fmt.Errorf("mailer: starttls: %w", err)
+		}
+	}
+	if m.cfg.Username != "" {
+		auth := smtp.PlainAuth("", m.cfg.Username, m.cfg.Password, m.cfg.Host)
+		if err := c.Auth(auth); err != nil {
+			return fmt.Errorf("mailer: auth: %w", err)
+		}
+	}
+
+	if err := c.Mail(from.Address); err != nil {
+		return fmt.Errorf("mailer: mail from: %w", err)
+	}
+	if err := c.Rcpt(to.Address); err != nil {
+		return fmt.Errorf("mailer: rcpt to: %w", err)
+	}
+	w, err := c.Data()
+	if err != nil {
+		return fmt.Errorf("mailer: data: %w", err)
+	}
+	if _, err := w.Write(body); err != nil {
+		w.Close()
+		return fmt.Errorf("mailer: write body: %w", err)
+	}
+	if err := w.Close(); err != nil {
+		return fmt.Errorf("mailer: close body: %w", err)
+	}
+	return c.Quit()
+}
+
+// ヘッダーと本文（text/plain・text/html）から MIME 形式のメッセージを組み立てる。
+func buildMessage(from, to *mail.Address, msg *Message, now time.Time) ([]byte, error) {
+	var buf bytes.Buffer
+	writeHeader := func(name, value string) {
+		fmt.Fprintf(&buf, "%s: %s\r\n", name, value)
+	}
+
+	messageID, err := newMessageID(from.Address)
+	if err != nil {
+		return nil, err
+	}
+	writeHeader("From", from.String())
+	writeHeader("To", to.String())
+	writeHeader("Subject", mime.QEncoding.Encode("utf-8", msg.Subject))
+	writeHeader("Date", now.Format(time.RFC1123Z))
+	writeHeader("Message-ID", messageID)
+	writeHeader("MIME-Version", "1.0")
+
+	// 追加ヘッダーは順序を固定して書き込む
+	names := make([]string, 0, len(msg.Headers))
+	for name := range msg.Headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		writeHeader(name, msg.Headers[name])
+	}
+
+	switch {
+	case msg.TextBody != "" && msg.HTMLBody != "":
+		boundary, err := randomHex(12)
+		if err != nil {
+			return nil, err
+		}
+		writeHeader("Content-Type", fmt.Sprintf("multipart/alternative; boundary=%q", boundary))
+		buf.WriteString("\r\n")
+		writePart(&buf, boundary, "text/plain; charset=UTF-8", msg.TextBody)
+		writePart(&buf, boundary, "text/html; charset=UTF-8", msg.HTMLBody)
+		fmt.Fprintf(&buf, "--%s--\r\n", boundary)
+	case msg.HTMLBody != "":
+		writeHeader("Content-Type", "text/html; charset=UTF-8")
+		writeHeader("Content-Transfer-Encoding", "base64")
+		buf.WriteString("\r\n")
+		writeBase64(&buf, msg.HTMLBody)
+	default:
+		writeHeader("Content-Type", "text/plain; charset=UTF-8")
+		writeHeader("Content-Transfer-Encoding", "base64")
+		buf.WriteString("\r\n")
+		writeBase64(&buf, msg.TextBody)
+	}
+	return buf.Bytes(), nil
+}
+
+// multipart の1パートを書き込む。
+func writePart(buf *bytes.Buffer, boundary, contentType, body string) {
+	fmt.Fprintf(buf, "--%s\r\n", boundary)
+	fmt.Fprintf(buf, "Content-Type: %s\r\n", contentType)
+	buf.WriteString("Content-Transfer-Encoding: base64\r\n\r\n")
+	writeBase64(buf, body)
+}
+
+// 本文を base64 エンコードし、行の長さを制限して書き込む。
+func writeBase64(buf *bytes.Buffer, body string) {
+	encoded := base64.StdEncoding.EncodeToString([]byte(body))
+	for len(encoded) > mimeLineLength {
+		buf.WriteString(encoded[:mimeLineLength])
+		buf.WriteString("\r\n")
+		encoded = encoded[mimeLineLength:]
+	}
+	buf.WriteString(encoded)
+	buf.WriteString("\r\n")
+}
+
+// 送信元アドレスのドメインを使って Message-ID を生成する。
+func newMessageID(fromAddress string) (string, error) {
+	id, err := randomHex(16)
+	if err != nil {
+		return "", err
+	}
+	domain := "localhost"
+	if i := strings.LastIndex(fromAddress, "@"); i >= 0 && i < len(fromAddress)-1 {
+		domain = fromAddress[i+1:]
+	}
+	return fmt.Sprintf("<%s@%s>", id, domain), nil
+}
+
+// n バイトの乱数を16進文字列で返す。
+func randomHex(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,169 @@
+package mailer
+
+import (
+	"bufio"
+	"context"
+	"encoding/base64"
+	"errors"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net"
+	"net/mail"
+	"strings"
+	"testing"
+)
+
+// 受信したメールを記録するだけのローカル SMTP サーバー。
+type smtpSink struct {
+	listener net.Listener
+	received chan sinkMail
+}
+
+// smtpSink が受信したメール。
+type sinkMail struct {
+	from string
+	to   []string
+	data string
+}
+
+func newSMTPSink(t *testing.T) *smtpSink {
+	t.Helper()
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	s := &smtpSink{listener: l, received: make(chan sinkMail, 1)}
+	t.Cleanup(func() { l.Close() })
+	go s.serve()
+	return s
+}
+
+func (s *smtpSink) port() int {
+	return s.listener.Addr().(*net.TCPAddr).Port
+}
+
+func (s *smtpSink) serve() {
+	conn, err := s.listener.Accept()
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+
+	r := bufio.NewReader(conn)
+	reply := func(line string) { io.WriteString(conn, line+"\r\n") }
+	reply("220 sink ready")
+
+	var m sinkMail
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return
+		}
+		cmd := strings.TrimRight(line, "\r\n")
+		switch upper := strings.ToUpper(cmd); {
+		case strings.HasPrefix(upper, "EHLO"), strings.HasPrefix(upper, "HELO"):
+			reply("250 sink")
+		case strings.HasPrefix(upper, "MAIL FROM:"):
+			m.from = strings.Trim(cmd[len("MAIL FROM:"):], "<> ")
+			reply("250 OK")
+		case strings.HasPrefix(upper, "RCPT TO:"):
+			m.to = append(m.to, strings.Trim(cmd[len("RCPT TO:"):], "<> "))
+			reply("250 OK")
+		case upper == "DATA":
+			reply("354 end with <CRLF>.<CRLF>")
+			var data strings.Builder
+			for {
+				l, err := r.ReadString('\n')
+				if err != nil {
+					return
+				}
+				if l == ".\r\n" {
+					break
+				}
+				data.WriteString(strings.TrimPrefix(l, "."))
+			}
+			m.data = data.String()
+			reply("250 queued")
+			s.received <- m
+		case upper == "QUIT":
+			reply("221 bye")
+			return
+		default:
+			reply("250 OK")
+		}
+	}
+}
+
+func TestSMTPMailer_Send(t *testing.T) {
+	t.Parallel()
+
+	sink := newSMTPSink(t)
+	m := NewSMTPMailer(SMTPConfig{Host: "127.0.0.1", Port: sink.port(), From: "cinetag <noreply@cine-tag.com>"})
+
+	err := m.Send(context.Background(), &Message{
+		To:       "alice@example.com",
+		Subject:  "今週のまとめ",
+		TextBody: "こんにちは",
+		HTMLBody: "<p>こんにちは</p>",
+		Headers:  map[string]string{"List-Unsubscribe": "<https://example.com/unsubscribe>"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	got := <-sink.received
+	if got.from != "noreply@cine-tag.com" || len(got.to) != 1 || got.to[0] != "alice@example.com" {
+		t.Fatalf("unexpected envelope: from=%s to=%v", got.from, got.to)
+	}
+
+	msg, err := mail.ReadMessage(strings.NewReader(got.data))
+	if err != nil {
+		t.Fatalf("failed to parse message: %v", err)
+	}
+	subject, err := new(mime.WordDecoder).DecodeHeader(msg.Header.Get("Subject"))
+	if err != nil || subject != "今週のまとめ" {
+		t.Fatalf("unexpected subject: %q (%v)", subject, err)
+	}
+	if msg.Header.Get("List-Unsubscribe") != "<https://example.com/unsubscribe>" {
+		t.Fatalf("unexpected List-Unsubscribe: %q", msg.Header.Get("List-Unsubscribe"))
+	}
+
+	mediaType, params, err := mime.ParseMediaType(msg.Header.Get("Content-Type"))
+	if err != nil || mediaType != "multipart/alternative" {
+		t.Fatalf("unexpected content type: %q (%v)", mediaType, err)
+	}
+	bodies := map[string]string{}
+	mr := multipart.NewReader(msg.Body, params["boundary"])
+	for {
+		part, err := mr.NextPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("failed to read part: %v", err)
+		}
+		b, _ := io.ReadAll(base64.NewDecoder(base64.StdEncoding, part))
+		partType, _, _ := mime.ParseMediaType(part.Header.Get("Content-Type"))
+		bodies[partType] = string(b)
+	}
+	if bodies["text/plain"] != "こんにちは" || bodies["text/html"] != "<p>こんにちは</p>" {
+		t.Fatalf("unexpected bodies: %v", bodies)
+	}
+}
+
+func TestSMTPMailer_Send_InvalidMessage(t *testing.T) {
+	t.Parallel()
+
+	m := NewSMTPMailer(SMTPConfig{Host: "127.0.0.1", Port: 1, From: "noreply@cine-tag.com"})
+	cases := []*Message{
+		nil,
+		{Subject: "no recipient", TextBody: "body"},
+		{To: "alice@example.com", Subject: "no body"},
+	}
+	for i, msg := range cases {
+		if err := m.Send(context.Background(), msg); !errors.Is(err, ErrInvalidMessage) {
+			t.Fatalf("case %d: expected ErrInvalidMessage, got %v", i, err)
+		}
+	}
+}
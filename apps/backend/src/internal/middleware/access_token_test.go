@@ -0,0 +1,177 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"cinetag-backend/src/internal/model"
+	"cinetag-backend/src/internal/service"
+	"cinetag-backend/src/internal/testutil"
+
+	"github.com/gin-gonic/gin"
+)
+
+type fakePersonalAccessTokenService struct {
+	AuthenticateFn func(ctx context.Context, rawToken string) (*model.PersonalAccessToken, *model.User, error)
+}
+
+func (f *fakePersonalAccessTokenService) CreateAccessToken(ctx context.Context, userID string, in service.CreateAccessTokenInput) (*service.CreatedAccessToken, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (f *fakePersonalAccessTokenService) ListAccessTokens(ctx context.Context, userID string) ([]*model.PersonalAccessToken, error) {
+	return []*model.PersonalAccessToken{}, nil
+}
+
+func (f *fakePersonalAccessTokenService) RevokeAccessToken(ctx context.Context, tokenID, userID string) error {
+	return nil
+}
+
+func (f *fakePersonalAccessTokenService) Authenticate(ctx context.Context, rawToken string) (*model.PersonalAccessToken, *model.User, error) {
+	return f.AuthenticateFn(ctx, rawToken)
+}
+
+func TestAuthMiddleware_AccessToken(t *testing.T) {
+	const rawToken = "cnt_0123456789abcdef"
+	routeScopes := AccessTokenRouteScopes{"GET /ok": model.AccessTokenScopeTagsRead}
+
+	newAuthenticator := func(token *model.PersonalAccessToken, user *model.User, err error) *AccessTokenAuthenticator {
+		tokens := &fakePersonalAccessTokenService{
+			AuthenticateFn: func(ctx context.Context, got string) (*model.PersonalAccessToken, *model.User, error) {
+				if got != rawToken {
+					t.Fatalf("unexpected token: %q", got)
+				}
+				return token, user, err
+			},
+		}
+		return NewAccessTokenAuthenticator(testutil.NewTestLogger(), tokens, routeScopes)
+	}
+	scoped := func(scopes ...string) *model.PersonalAccessToken {
+		return &model.PersonalAccessToken{ID: "pat1", UserID: "u1", Scopes: scopes}
+	}
+	headers := map[string]string{"Authorization": "Bearer " + rawToken}
+
+	cases := []struct {
+		name     string
+		auth     *AccessTokenAuthenticator
+		wantCode int
+	}{
+		{
+			name:     "スコープを持つトークン: 200",
+			auth:     newAuthenticator(scoped(model.AccessTokenScopeTagsRead, model.AccessTokenScopeTagsWrite), &model.User{ID: "u1"}, nil),
+			wantCode: http.StatusOK,
+		},
+		{
+			name:     "スコープが足りない: 403",
+			auth:     newAuthenticator(scoped(model.AccessTokenScopeTagsWrite), &model.User{ID: "u1"}, nil),
+			wantCode: http.StatusForbidden,
+		},
+		{
+			name:     "不明・期限切れのトークン: 401",
+			auth:     newAuthenticator(nil, nil, service.ErrInvalidAccessToken),
+			wantCode: http.StatusUnauthorized,
+		},
+		{
+			name:     "照合に失敗: 500",
+			auth:     newAuthenticator(nil, nil, errors.New("db error")),
+			wantCode: http.StatusInternalServerError,
+		},
+		{
+			name:     "持ち主が退会状態: 403",
+			auth:     newAuthenticator(scoped(model.AccessTokenScopeTagsRead), &model.User{ID: "u1", DeletedAt: func() *time.Time { now := time.Now(); return &now }()}, nil),
+			wantCode: http.StatusForbidden,
+		},
+		{
+			name:     "持ち主が管理者に利用停止された: 403",
+			auth:     newAuthenticator(scoped(model.AccessTokenScopeTagsRead), &model.User{ID: "u1", SuspendedAt: func() *time.Time { now := time.Now(); return &now }()}, nil),
+			wantCode: http.StatusForbidden,
+		},
+		{
+			name:     "トークンを受け付けない設定: 401",
+			auth:     nil,
+			wantCode: http.StatusUnauthorized,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			// JWT の検証器が無くてもトークンは照合できる
+			mw := NewAuthMiddleware(testutil.NewTestLogger(), &fakeUserService{}, nil, tc.auth)
+			rw := testutil.PerformRequest(newAuthTestRouter(t, mw), http.MethodGet, "/ok", nil, headers)
+			if rw.Code != tc.wantCode {
+				t.Fatalf("expected %d, got %d (%s)", tc.wantCode, rw.Code, rw.Body.String())
+			}
+		})
+	}
+
+	t.Run("スコープが定義されていない API は呼び出せない", func(t *testing.T) {
+		mw := NewAuthMiddleware(testutil.NewTestLogger(), &fakeUserService{}, nil, newAuthenticator(scoped(model.AccessTokenScopes...), &model.User{ID: "u1"}, nil))
+		r := newAuthTestRouter(t, mw)
+		r.DELETE("/users/me", func(c *gin.Context) { c.Status(http.StatusNoContent) })
+
+		rw := testutil.PerformRequest(r, http.MethodDelete, "/users/me", nil, headers)
+		if rw.Code != http.StatusForbidden {
+			t.Fatalf("expected 403, got %d", rw.Code)
+		}
+	})
+
+	t.Run("退会の取り消しはトークンでは行えない", func(t *testing.T) {
+		mw := NewReactivationAuthMiddleware(testutil.NewTestLogger(), &fakeUserService{}, nil)
+		rw := testutil.PerformRequest(newAuthTestRouter(t, mw), http.MethodGet, "/ok", nil, headers)
+		if rw.Code != http.StatusUnauthorized {
+			t.Fatalf("expected 401, got %d", rw.Code)
+		}
+	})
+}
+
+func TestOptionalAuthMiddleware_AccessToken(t *testing.T) {
+	const rawToken = "cnt_0123456789abcdef"
+	tokens := &fakePersonalAccessTokenService{
+		AuthenticateFn: func(ctx context.Context, got string) (*model.PersonalAccessToken, *model.User, error) {
+			return &model.PersonalAccessToken{ID: "pat1", UserID: "u1", Scopes: []string{model.AccessTokenScopeTagsRead}}, &model.User{ID: "u1"}, nil
+		},
+	}
+	auth := NewAccessTokenAuthenticator(testutil.NewTestLogger(), tokens, AccessTokenRouteScopes{"GET /tags": model.AccessTokenScopeTagsRead})
+
+	r := testutil.NewTestRouter()
+	r.Use(NewOptionalAuthMiddleware(testutil.NewTestLogger(), &fakeUserService{}, nil, auth))
+	handler := func(c *gin.Context) {
+		userID := ""
+		if u, ok := c.Get("user"); ok {
+			userID = u.(*model.User).ID
+		}
+		c.JSON(http.StatusOK, gin.H{"user_id": userID})
+	}
+	r.GET("/tags", handler)
+	r.GET("/users", handler)
+
+	t.Run("スコープを持つトークン: ユーザーを設定する", func(t *testing.T) {
+		rw := testutil.PerformRequest(r, http.MethodGet, "/tags", nil, map[string]string{"Authorization": "Bearer " + rawToken})
+		if rw.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d", rw.Code)
+		}
+		var body struct {
+			UserID string `json:"user_id"`
+		}
+		testutil.MustUnmarshalJSON(t, rw.Body.Bytes(), &body)
+		if body.UserID != "u1" {
+			t.Fatalf("expected user_id=u1, got %q", body.UserID)
+		}
+	})
+
+	t.Run("スコープが足りない: 403", func(t *testing.T) {
+		rw := testutil.PerformRequest(r, http.MethodGet, "/users", nil, map[string]string{"Authorization": "Bearer " + rawToken})
+		if rw.Code != http.StatusForbidden {
+			t.Fatalf("expected 403, got %d", rw.Code)
+		}
+	})
+
+	t.Run("Authorization が無い: 匿名で通す", func(t *testing.T) {
+		rw := testutil.PerformRequest(r, http.MethodGet, "/tags", nil, nil)
+		if rw.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d", rw.Code)
+		}
+	})
+}
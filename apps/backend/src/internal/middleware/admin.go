@@ -0,0 +1,66 @@
+package middleware
+
+import (
+	"log/slog"
+	"net/http"
+
+	"cinetag-backend/src/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// NewAdminMiddleware は管理者用 API（/api/v1/admin 配下）へのアクセスを制御するミドルウェアを返します。
+//
+// Clerk JWT で認証し、ロールが admin のユーザーのみを通します（退会状態のユーザーは拒否します）。
+// パーソナルアクセストークン・X-Admin-Token では呼び出せません。
+func NewAdminMiddleware(logger *slog.Logger, userService service.UserService, validator *ClerkJWTValidator) gin.HandlerFunc {
+	// 初期化ログ（DEBUG）
+	logger.Debug("middleware.NewAdminMiddleware initialized")
+
+	return newAdminMiddleware(logger, userService, validator, false)
+}
+
+// NewAdminJobMiddleware は管理者用のメンテナンスジョブの API（POST /api/v1/admin/jobs/:job）へのアクセスを制御するミドルウェアを返します。
+//
+//   - X-Admin-Token ヘッダーがある場合は、環境変数 ADMIN_API_TOKEN と照合します。
+//     Cron などの定期実行からの呼び出しに使います（コンテキストに "user" は設定しません）。
+//   - それ以外は NewAdminMiddleware と同じく、ロールが admin のユーザーのみを通します。
+func NewAdminJobMiddleware(logger *slog.Logger, userService service.UserService, validator *ClerkJWTValidator) gin.HandlerFunc {
+	// 初期化ログ（DEBUG）
+	logger.Debug("middleware.NewAdminJobMiddleware initialized")
+
+	return newAdminMiddleware(logger, userService, validator, true)
+}
+
+// 管理者用 API のミドルウェアを返す。allowToken が true の場合は X-Admin-Token でも呼び出せる。
+func newAdminMiddleware(logger *slog.Logger, userService service.UserService, validator *ClerkJWTValidator, allowToken bool) gin.HandlerFunc {
+	authenticate := newRequestAuthenticator(logger, userService, validator, nil)
+
+	return func(c *gin.Context) {
+		if allowToken && c.GetHeader(HeaderAdminToken) != "" {
+			if !validAdminToken(logger, c) {
+				c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "forbidden"})
+				return
+			}
+			c.Next()
+			return
+		}
+
+		user, ok := authenticate(c)
+		if !ok {
+			return
+		}
+		if !user.IsAdmin() || user.DeletedAt != nil {
+			logger.Warn("admin: rejecting non-admin user",
+				slog.String("method", c.Request.Method),
+				slog.String("path", c.Request.URL.Path),
+				slog.String("user_id", user.ID),
+			)
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "forbidden"})
+			return
+		}
+
+		c.Set("user", user)
+		c.Next()
+	}
+}
@@ -0,0 +1,29 @@
+package middleware
+
+import (
+	"crypto/subtle"
+	"log/slog"
+	"os"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// 管理者用トークンを指定するヘッダー（メンテナンスジョブの API のみ。NewAdminJobMiddleware を参照）。
+const HeaderAdminToken = "X-Admin-Token"
+
+// X-Admin-Token ヘッダーの値が環境変数 ADMIN_API_TOKEN と一致するかを返す。一致しない場合はログに出す。
+// ADMIN_API_TOKEN が未設定の場合は常に false を返す。
+func validAdminToken(logger *slog.Logger, c *gin.Context) bool {
+	expected := strings.TrimSpace(os.Getenv("ADMIN_API_TOKEN"))
+	got := c.GetHeader(HeaderAdminToken)
+	if expected == "" || subtle.ConstantTimeCompare([]byte(got), []byte(expected)) != 1 {
+		logger.Warn("admin token: rejecting request",
+			slog.String("method", c.Request.Method),
+			slog.String("path", c.Request.URL.Path),
+			slog.Bool("token_configured", expected != ""),
+		)
+		return false
+	}
+	return true
+}
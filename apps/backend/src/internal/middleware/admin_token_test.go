@@ -0,0 +1,49 @@
+package middleware
+
+import (
+	"net/http"
+	"testing"
+
+	"cinetag-backend/src/internal/testutil"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestValidAdminToken(t *testing.T) {
+	newRouter := func() *gin.Engine {
+		r := testutil.NewTestRouter()
+		logger := testutil.NewTestLogger()
+		r.GET("/admin", func(c *gin.Context) {
+			if !validAdminToken(logger, c) {
+				c.Status(http.StatusForbidden)
+				return
+			}
+			c.Status(http.StatusOK)
+		})
+		return r
+	}
+
+	t.Run("ADMIN_API_TOKEN 未設定: 403", func(t *testing.T) {
+		t.Setenv("ADMIN_API_TOKEN", "")
+		rw := testutil.PerformRequest(newRouter(), http.MethodGet, "/admin", nil, map[string]string{HeaderAdminToken: ""})
+		if rw.Code != http.StatusForbidden {
+			t.Fatalf("expected 403, got %d", rw.Code)
+		}
+	})
+
+	t.Run("トークン不一致: 403", func(t *testing.T) {
+		t.Setenv("ADMIN_API_TOKEN", "secret")
+		rw := testutil.PerformRequest(newRouter(), http.MethodGet, "/admin", nil, map[string]string{HeaderAdminToken: "wrong"})
+		if rw.Code != http.StatusForbidden {
+			t.Fatalf("expected 403, got %d", rw.Code)
+		}
+	})
+
+	t.Run("トークン一致: 通過", func(t *testing.T) {
+		t.Setenv("ADMIN_API_TOKEN", "secret")
+		rw := testutil.PerformRequest(newRouter(), http.MethodGet, "/admin", nil, map[string]string{HeaderAdminToken: "secret"})
+		if rw.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d", rw.Code)
+		}
+	})
+}
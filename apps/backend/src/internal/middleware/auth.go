@@ -3,9 +3,9 @@ package middleware
 import (
 	"log/slog"
 	"net/http"
-	"os"
 	"strings"
 
+	"cinetag-backend/src/internal/model"
 	"cinetag-backend/src/internal/service"
 
 	"github.com/gin-gonic/gin"
@@ -15,36 +15,75 @@ import (
 // users テーブルとの同期を行う認証ミドルウェアを返します。
 //
 // NOTE:
-//   - Clerk の JWKS で RS256 JWT を検証し、sub（Clerk user ID）を信頼できる形で取得します。
-//   - JWKS の取得先は環境変数 `CLERK_JWKS_URL` に設定してください。
-//   - 必要なら `CLERK_ISSUER` / `CLERK_AUDIENCE` も指定し、iss/aud の検証を有効化できます。
-func NewAuthMiddleware(logger *slog.Logger, userService service.UserService) gin.HandlerFunc {
+//   - Clerk の JWKS で JWT（RS256 / ES256 / EdDSA）を検証し、sub（Clerk user ID）を信頼できる形で取得します。
+//   - validator は ClerkJWTConfigFromEnv の設定で生成し、OptionalAuthMiddleware 等と共有します。
+//     nil の場合（CLERK_JWKS_URL 未設定など）は JWT のリクエストに 500 を返します。
+//   - `Bearer cnt_...` はパーソナルアクセストークンとして accessTokens で照合します（nil の場合は 401）。
+//     トークンのスコープで呼び出せない API の場合は 403 を返します。
+//   - 退会状態（猶予期間中を含む）・管理者による利用停止中のユーザーは 403 を返します。
+func NewAuthMiddleware(logger *slog.Logger, userService service.UserService, validator *ClerkJWTValidator, accessTokens *AccessTokenAuthenticator) gin.HandlerFunc {
 	// 初期化ログ（DEBUG）
 	logger.Debug("middleware.NewAuthMiddleware initialized")
 
-	jwksURL := os.Getenv("CLERK_JWKS_URL")
-	issuer := os.Getenv("CLERK_ISSUER")
-	audience := os.Getenv("CLERK_AUDIENCE")
+	return newAuthMiddleware(logger, userService, validator, accessTokens, false)
+}
 
-	// Clerk JWT 検証器を生成する。
-	validator, err := NewClerkJWTValidator(jwksURL, issuer, audience)
-	if err != nil {
-		// ルーティング初期化時に気づけるようログに出し、リクエストは 500 を返す
-		logger.Error("AuthMiddleware misconfigured", slog.Any("error", err))
-		validator = nil
-	}
+// NewReactivationAuthMiddleware は、退会状態のユーザーも通す認証ミドルウェアを返します。
+// 退会の取り消し（POST /users/me/reactivate）にのみ使用します（パーソナルアクセストークンでは行えません）。
+func NewReactivationAuthMiddleware(logger *slog.Logger, userService service.UserService, validator *ClerkJWTValidator) gin.HandlerFunc {
+	// 初期化ログ（DEBUG）
+	logger.Debug("middleware.NewReactivationAuthMiddleware initialized")
+
+	return newAuthMiddleware(logger, userService, validator, nil, true)
+}
+
+// 認証ミドルウェアの本体。allowDeactivated が false の場合、退会状態のユーザーを拒否する。
+func newAuthMiddleware(logger *slog.Logger, userService service.UserService, validator *ClerkJWTValidator, accessTokens *AccessTokenAuthenticator, allowDeactivated bool) gin.HandlerFunc {
+	authenticate := newRequestAuthenticator(logger, userService, validator, accessTokens)
 
 	return func(c *gin.Context) {
+		user, ok := authenticate(c)
+		if !ok {
+			return
+		}
 
-		// Clerk JWT 検証器が生成できなかった場合は 500 を返す。
-		if validator == nil {
-			c.JSON(http.StatusInternalServerError, gin.H{
-				"error": "auth middleware misconfigured",
+		// 管理者に利用停止されたユーザーは、退会の取り消しを含むすべての操作を行えない。
+		if user.SuspendedAt != nil {
+			c.JSON(http.StatusForbidden, gin.H{
+				"error": "account suspended",
 			})
 			c.Abort()
 			return
 		}
 
+		// 退会状態のユーザーは、退会の取り消し以外の操作を行えない。
+		if user.DeletedAt != nil && !allowDeactivated {
+			c.JSON(http.StatusForbidden, gin.H{
+				"error": "account deactivated",
+			})
+			c.Abort()
+			return
+		}
+
+		// 後続のハンドラーから参照できるよう、コンテキストに格納。
+		c.Set("user", user)
+
+		// 次のハンドラーに処理を渡す。
+		c.Next()
+	}
+}
+
+// Authorization ヘッダーのトークンからユーザーを取得する関数を返す。
+// 認証に失敗した場合はレスポンスを書き込んでリクエストを中断し、false を返す。
+// accessTokens が nil の場合はパーソナルアクセストークンを受け付けない。
+func newRequestAuthenticator(logger *slog.Logger, userService service.UserService, validator *ClerkJWTValidator, accessTokens *AccessTokenAuthenticator) func(c *gin.Context) (*model.User, bool) {
+	if validator == nil {
+		// ルーティング初期化時に気づけるようログに出し、リクエストは 500 を返す
+		logger.Error("AuthMiddleware misconfigured: jwt validator is not available")
+	}
+
+	return func(c *gin.Context) (*model.User, bool) {
+
 		// Authorization ヘッダーを取得する。
 		authHeader := c.GetHeader("Authorization")
 		// Authorization ヘッダーが空か Bearer 形式でない場合は 401 を返す。
@@ -53,7 +92,7 @@ func NewAuthMiddleware(logger *slog.Logger, userService service.UserService) gin
 				"error": "unauthorized",
 			})
 			c.Abort()
-			return
+			return nil, false
 		}
 
 		// Bearer トークンを取得する。
@@ -63,7 +102,33 @@ func NewAuthMiddleware(logger *slog.Logger, userService service.UserService) gin
 				"error": "unauthorized",
 			})
 			c.Abort()
-			return
+			return nil, false
+		}
+
+		if isAccessToken(rawToken) {
+			// パーソナルアクセストークンを照合する。
+			if accessTokens == nil {
+				c.JSON(http.StatusUnauthorized, gin.H{
+					"error": "unauthorized",
+				})
+				c.Abort()
+				return nil, false
+			}
+			user, err := accessTokens.authenticate(c, rawToken)
+			if err != nil {
+				accessTokens.abort(c, err)
+				return nil, false
+			}
+			return user, true
+		}
+
+		// Clerk JWT 検証器が生成できなかった場合は 500 を返す。
+		if validator == nil {
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error": "auth middleware misconfigured",
+			})
+			c.Abort()
+			return nil, false
 		}
 
 		// Bearer トークンを検証する。
@@ -73,7 +138,7 @@ func NewAuthMiddleware(logger *slog.Logger, userService service.UserService) gin
 				"error": "unauthorized",
 			})
 			c.Abort()
-			return
+			return nil, false
 		}
 
 		// ClerkUserInfo を作成する。
@@ -83,7 +148,7 @@ func NewAuthMiddleware(logger *slog.Logger, userService service.UserService) gin
 				"error": "unauthorized",
 			})
 			c.Abort()
-			return
+			return nil, false
 		}
 
 		// users テーブルと同期する。
@@ -93,13 +158,8 @@ func NewAuthMiddleware(logger *slog.Logger, userService service.UserService) gin
 				"error": "failed to ensure user",
 			})
 			c.Abort()
-			return
+			return nil, false
 		}
-
-		// 後続のハンドラーから参照できるよう、コンテキストに格納。
-		c.Set("user", user)
-
-		// 次のハンドラーに処理を渡す。
-		c.Next()
+		return user, true
 	}
 }
@@ -33,20 +33,24 @@ func (f *fakeUserService) GetUserByDisplayID(ctx context.Context, displayID stri
 	return f.GetUserByDisplayIDFn(ctx, displayID)
 }
 
+func (f *fakeUserService) ResolveRenamedDisplayID(ctx context.Context, displayID string) (string, error) {
+	return "", service.ErrUserNotFound
+}
+
 func (f *fakeUserService) UpdateUser(ctx context.Context, userID string, input service.UpdateUserInput) (*model.User, error) {
 	return nil, nil
 }
 
-func (f *fakeUserService) UpdateUserFromClerk(ctx context.Context, userID string, avatarURL *string) error {
-	return nil
+func (f *fakeUserService) SyncUserFromClerk(ctx context.Context, clerkUser service.ClerkUserInfo, opts service.ClerkSyncOptions) (*model.User, error) {
+	return nil, nil
 }
 
 func (f *fakeUserService) FindUserByClerkUserID(ctx context.Context, clerkUserID string) (*model.User, error) {
 	return nil, service.ErrUserNotFound
 }
 
-func (f *fakeUserService) FollowUser(ctx context.Context, followerID, followeeID string) error {
-	return nil
+func (f *fakeUserService) FollowUser(ctx context.Context, followerID, followeeID string) (service.FollowStatus, error) {
+	return service.FollowStatusFollowing, nil
 }
 
 func (f *fakeUserService) UnfollowUser(ctx context.Context, followerID, followeeID string) error {
@@ -69,10 +73,88 @@ func (f *fakeUserService) GetFollowStats(ctx context.Context, userID string) (fo
 	return 0, 0, nil
 }
 
-func (f *fakeUserService) DeactivateUser(ctx context.Context, userID string) error {
+func (f *fakeUserService) DeactivateUser(ctx context.Context, userID string, policy service.AccountDeletionPolicy) (*model.User, error) {
+	return nil, nil
+}
+
+func (f *fakeUserService) DeactivateClerkUser(ctx context.Context, clerkUserID string, event *service.ClerkWebhookClaim) error {
+	return nil
+}
+
+func (f *fakeUserService) ReactivateUser(ctx context.Context, userID string) (*model.User, error) {
+	return nil, nil
+}
+
+func (f *fakeUserService) PurgeDeactivatedUsers(ctx context.Context, now time.Time, limit int) (int, error) {
+	return 0, nil
+}
+
+func (f *fakeUserService) BlockUser(ctx context.Context, blockerID, blockedID string) error {
+	return nil
+}
+
+func (f *fakeUserService) UnblockUser(ctx context.Context, blockerID, blockedID string) error {
+	return nil
+}
+
+func (f *fakeUserService) MuteUser(ctx context.Context, muterID, mutedID string) error {
+	return nil
+}
+
+func (f *fakeUserService) UnmuteUser(ctx context.Context, muterID, mutedID string) error {
+	return nil
+}
+
+func (f *fakeUserService) ListBlockedUsers(ctx context.Context, userID string, page, pageSize int) ([]*model.User, int64, error) {
+	return []*model.User{}, 0, nil
+}
+
+func (f *fakeUserService) ListMutedUsers(ctx context.Context, userID string, page, pageSize int) ([]*model.User, int64, error) {
+	return []*model.User{}, 0, nil
+}
+
+func (f *fakeUserService) SearchUsers(ctx context.Context, viewerID, query string, page, pageSize int) ([]service.UserSearchResult, int64, error) {
+	return []service.UserSearchResult{}, 0, nil
+}
+
+func (f *fakeUserService) SuggestUsers(ctx context.Context, userID string, limit int) ([]service.UserSuggestion, error) {
+	return []service.UserSuggestion{}, nil
+}
+
+func (f *fakeUserService) ApproveFollowRequest(ctx context.Context, targetID, requesterID string) error {
+	return nil
+}
+
+func (f *fakeUserService) DenyFollowRequest(ctx context.Context, targetID, requesterID string) error {
 	return nil
 }
 
+func (f *fakeUserService) ListFollowRequests(ctx context.Context, userID string, page, pageSize int) ([]*model.User, int64, error) {
+	return []*model.User{}, 0, nil
+}
+
+func (f *fakeUserService) HasPendingFollowRequest(ctx context.Context, requesterID, targetID string) (bool, error) {
+	return false, nil
+}
+
+func (f *fakeUserService) CanViewUserContent(ctx context.Context, viewerID string, owner *model.User) (bool, error) {
+	return true, nil
+}
+
+// 環境変数（CLERK_JWKS_URL 等）から検証器を生成する。設定不備の場合は nil を返す。
+func newEnvClerkJWTValidator(t *testing.T) *ClerkJWTValidator {
+	t.Helper()
+	cfg, err := ClerkJWTConfigFromEnv()
+	if err != nil {
+		return nil
+	}
+	v, err := NewClerkJWTValidator(testutil.NewTestLogger(), cfg)
+	if err != nil {
+		return nil
+	}
+	return v
+}
+
 func newAuthTestRouter(t *testing.T, mw gin.HandlerFunc) *gin.Engine {
 	t.Helper()
 
@@ -98,7 +180,7 @@ func TestAuthMiddleware(t *testing.T) {
 	t.Run("設定不備: CLERK_JWKS_URL が空なら 500", func(t *testing.T) {
 		t.Setenv("CLERK_JWKS_URL", "")
 		logger := testutil.NewTestLogger()
-		mw := NewAuthMiddleware(logger, &fakeUserService{})
+		mw := NewAuthMiddleware(logger, &fakeUserService{}, newEnvClerkJWTValidator(t), nil)
 		r := newAuthTestRouter(t, mw)
 
 		rw := testutil.PerformRequest(r, http.MethodGet, "/ok", nil, map[string]string{
@@ -113,7 +195,7 @@ func TestAuthMiddleware(t *testing.T) {
 		// Verify に到達しないのでURLはダミーでよい
 		t.Setenv("CLERK_JWKS_URL", "http://example.invalid/jwks")
 		logger := testutil.NewTestLogger()
-		mw := NewAuthMiddleware(logger, &fakeUserService{})
+		mw := NewAuthMiddleware(logger, &fakeUserService{}, newEnvClerkJWTValidator(t), nil)
 		r := newAuthTestRouter(t, mw)
 
 		rw := testutil.PerformRequest(r, http.MethodGet, "/ok", nil, nil)
@@ -125,7 +207,7 @@ func TestAuthMiddleware(t *testing.T) {
 	t.Run("未認証: Bearer 形式でない場合は 401", func(t *testing.T) {
 		t.Setenv("CLERK_JWKS_URL", "http://example.invalid/jwks")
 		logger := testutil.NewTestLogger()
-		mw := NewAuthMiddleware(logger, &fakeUserService{})
+		mw := NewAuthMiddleware(logger, &fakeUserService{}, newEnvClerkJWTValidator(t), nil)
 		r := newAuthTestRouter(t, mw)
 
 		rw := testutil.PerformRequest(r, http.MethodGet, "/ok", nil, map[string]string{
@@ -139,7 +221,7 @@ func TestAuthMiddleware(t *testing.T) {
 	t.Run("未認証: トークン形式が不正なら 401", func(t *testing.T) {
 		t.Setenv("CLERK_JWKS_URL", "http://example.invalid/jwks")
 		logger := testutil.NewTestLogger()
-		mw := NewAuthMiddleware(logger, &fakeUserService{})
+		mw := NewAuthMiddleware(logger, &fakeUserService{}, newEnvClerkJWTValidator(t), nil)
 		r := newAuthTestRouter(t, mw)
 
 		rw := testutil.PerformRequest(r, http.MethodGet, "/ok", nil, map[string]string{
@@ -167,7 +249,7 @@ func TestAuthMiddleware(t *testing.T) {
 		token := mustSignRS256JWT(t, kid, claims, priv)
 
 		logger := testutil.NewTestLogger()
-		mw := NewAuthMiddleware(logger, &fakeUserService{})
+		mw := NewAuthMiddleware(logger, &fakeUserService{}, newEnvClerkJWTValidator(t), nil)
 		r := newAuthTestRouter(t, mw)
 		rw := testutil.PerformRequest(r, http.MethodGet, "/ok", nil, map[string]string{
 			"Authorization": "Bearer " + token,
@@ -194,7 +276,7 @@ func TestAuthMiddleware(t *testing.T) {
 		token := mustSignRS256JWT(t, kid, claims, priv)
 
 		logger := testutil.NewTestLogger()
-		mw := NewAuthMiddleware(logger, &fakeUserService{})
+		mw := NewAuthMiddleware(logger, &fakeUserService{}, newEnvClerkJWTValidator(t), nil)
 		r := newAuthTestRouter(t, mw)
 		rw := testutil.PerformRequest(r, http.MethodGet, "/ok", nil, map[string]string{
 			"Authorization": "Bearer " + token,
@@ -226,7 +308,7 @@ func TestAuthMiddleware(t *testing.T) {
 		}}
 
 		logger := testutil.NewTestLogger()
-		mw := NewAuthMiddleware(logger, us)
+		mw := NewAuthMiddleware(logger, us, newEnvClerkJWTValidator(t), nil)
 		r := newAuthTestRouter(t, mw)
 		rw := testutil.PerformRequest(r, http.MethodGet, "/ok", nil, map[string]string{
 			"Authorization": "Bearer " + token,
@@ -236,6 +318,42 @@ func TestAuthMiddleware(t *testing.T) {
 		}
 	})
 
+	t.Run("退会状態のユーザー: 403", func(t *testing.T) {
+		kid := "kid1"
+		priv := mustNewRSAKey(t)
+		jwks := testJWKS{Keys: []testJWK{jwkFromPublicKey(kid, &priv.PublicKey)}}
+		srv := newJWKSServer(t, jwks)
+		t.Cleanup(srv.Close)
+
+		t.Setenv("CLERK_JWKS_URL", srv.URL)
+
+		claims := map[string]any{
+			"sub":   "user_123",
+			"email": "a@example.com",
+			"exp":   time.Now().Add(10 * time.Minute).Unix(),
+		}
+		token := mustSignRS256JWT(t, kid, claims, priv)
+
+		deletedAt := time.Now()
+		us := &fakeUserService{EnsureUserFn: func(ctx context.Context, clerkUser service.ClerkUserInfo) (*model.User, error) {
+			return &model.User{ID: "u-local", DeletedAt: &deletedAt, DeletionStatus: model.UserDeletionStatusDeactivated}, nil
+		}}
+
+		logger := testutil.NewTestLogger()
+		headers := map[string]string{"Authorization": "Bearer " + token}
+
+		rw := testutil.PerformRequest(newAuthTestRouter(t, NewAuthMiddleware(logger, us, newEnvClerkJWTValidator(t), nil)), http.MethodGet, "/ok", nil, headers)
+		if rw.Code != http.StatusForbidden {
+			t.Fatalf("expected 403, got %d", rw.Code)
+		}
+
+		// 退会の取り消し用ミドルウェアでは通す
+		rw = testutil.PerformRequest(newAuthTestRouter(t, NewReactivationAuthMiddleware(logger, us, newEnvClerkJWTValidator(t))), http.MethodGet, "/ok", nil, headers)
+		if rw.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d", rw.Code)
+		}
+	})
+
 	t.Run("成功: user が context にセットされ後続へ進む", func(t *testing.T) {
 		kid := "kid1"
 		priv := mustNewRSAKey(t)
@@ -263,7 +381,7 @@ func TestAuthMiddleware(t *testing.T) {
 		}}
 
 		logger := testutil.NewTestLogger()
-		mw := NewAuthMiddleware(logger, us)
+		mw := NewAuthMiddleware(logger, us, newEnvClerkJWTValidator(t), nil)
 		r := newAuthTestRouter(t, mw)
 		rw := testutil.PerformRequest(r, http.MethodGet, "/ok", nil, map[string]string{
 			"Authorization": "Bearer " + token,
@@ -3,50 +3,191 @@ package middleware
 import (
 	"context"
 	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
 	"crypto/rsa"
 	"crypto/sha256"
 	"encoding/base64"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"log/slog"
 	"math/big"
 	"net/http"
+	"os"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
 )
 
+// サポートする JWT の署名アルゴリズム。
+const (
+	jwtAlgRS256 = "RS256"
+	jwtAlgES256 = "ES256"
+	jwtAlgEdDSA = "EdDSA"
+)
+
+// Clerk JWT 検証器の設定。ゼロ値の項目は既定値を使う。
+type ClerkJWTConfig struct {
+	JWKSURL  string // JWKS の取得先（必須）
+	Issuer   string // 空の場合は iss を検証しない
+	Audience string // 空の場合は aud を検証しない
+	// AuthorizedParties は azp（トークンを発行したフロントエンドのオリジン）として許可する値。
+	// 空の場合、または azp を含まないトークンの場合は検証しない。
+	AuthorizedParties []string
+	// Leeway は exp / nbf の検証で許容する時刻のずれ（既定: 5秒）。
+	Leeway time.Duration
+	// NoLeeway が true の場合は Leeway を無視し、exp / nbf の検証で時刻のずれを許容しない。
+	NoLeeway bool
+	// RefreshInterval は JWKS をバックグラウンドで再取得する間隔（既定: 10分）。
+	RefreshInterval time.Duration
+	// MinRefetchInterval は未知の kid を受け取ったときに JWKS を再取得する最小間隔（既定: 30秒）。
+	MinRefetchInterval time.Duration
+}
+
+// ゼロ値の項目を既定値で埋めた設定を返す。
+func (c ClerkJWTConfig) withDefaults() ClerkJWTConfig {
+	c.JWKSURL = strings.TrimSpace(c.JWKSURL)
+	c.Issuer = strings.TrimSpace(c.Issuer)
+	c.Audience = strings.TrimSpace(c.Audience)
+	switch {
+	case c.NoLeeway:
+		c.Leeway = 0
+	case c.Leeway <= 0:
+		c.Leeway = 5 * time.Second
+	}
+	if c.RefreshInterval <= 0 {
+		c.RefreshInterval = 10 * time.Minute
+	}
+	if c.MinRefetchInterval <= 0 {
+		c.MinRefetchInterval = 30 * time.Second
+	}
+	return c
+}
+
+// 環境変数から Clerk JWT 検証器の設定を読み込む。
+// - CLERK_JWKS_URL / CLERK_ISSUER / CLERK_AUDIENCE
+// - CLERK_AUTHORIZED_PARTIES: azp として許可するオリジン（カンマ区切り）
+// - CLERK_JWT_LEEWAY_SECONDS: exp / nbf の検証で許容する時刻のずれ（秒）
+// 値が不正な場合はエラーを返す（その項目以外を読み込んだ設定も返す）。
+func ClerkJWTConfigFromEnv() (ClerkJWTConfig, error) {
+	cfg := ClerkJWTConfig{
+		JWKSURL:  os.Getenv("CLERK_JWKS_URL"),
+		Issuer:   os.Getenv("CLERK_ISSUER"),
+		Audience: os.Getenv("CLERK_AUDIENCE"),
+	}
+	for _, p := range strings.Split(os.Getenv("CLERK_AUTHORIZED_PARTIES"), ",") {
+		if p = strings.TrimSpace(p); p != "" {
+			cfg.AuthorizedParties = append(cfg.AuthorizedParties, p)
+		}
+	}
+	if raw := strings.TrimSpace(os.Getenv("CLERK_JWT_LEEWAY_SECONDS")); raw != "" {
+		sec, err := strconv.Atoi(raw)
+		if err != nil || sec < 0 {
+			return cfg, fmt.Errorf("invalid CLERK_JWT_LEEWAY_SECONDS: %q", raw)
+		}
+		cfg.Leeway = time.Duration(sec) * time.Second
+		cfg.NoLeeway = sec == 0 // 明示的に 0 を指定した場合は既定値ではなく、ずれを許容しない
+	}
+	return cfg, nil
+}
+
 // Clerk が発行する JWT を JWKS を使って検証するためのヘルパー。
-// 外部依存（JWTライブラリ）を追加せず、RS256 のみを最小実装でサポートする。
+// 外部依存（JWTライブラリ）を追加せず、RS256 / ES256 / EdDSA を最小実装でサポートする。
+// 1つの検証器を複数の認証ミドルウェアで共有し、Start で JWKS のバックグラウンド更新を開始する。
 type ClerkJWTValidator struct {
-	jwksURL  string
-	issuer   string
-	audience string
+	logger *slog.Logger
+	cfg    ClerkJWTConfig
+	now    func() time.Time
 
 	client *http.Client
 	cache  *jwksCache
+
+	mu      sync.Mutex
+	started bool
+	stop    chan struct{}
+	done    chan struct{}
 }
 
 // Clerk JWT 検証器を生成する。
-// - jwksURL は必須。
-// - issuer/audience は空の場合は検証しない。
-func NewClerkJWTValidator(jwksURL, issuer, audience string) (*ClerkJWTValidator, error) {
-	jwksURL = strings.TrimSpace(jwksURL)
-	if jwksURL == "" {
+// - cfg.JWKSURL は必須。
+// - issuer/audience/authorized parties は空の場合は検証しない。
+func NewClerkJWTValidator(logger *slog.Logger, cfg ClerkJWTConfig) (*ClerkJWTValidator, error) {
+	cfg = cfg.withDefaults()
+	if cfg.JWKSURL == "" {
 		return nil, errors.New("CLERK_JWKS_URL is required")
 	}
 
 	// Clerk JWT 検証器を生成。
 	v := &ClerkJWTValidator{
-		jwksURL:  jwksURL,
-		issuer:   strings.TrimSpace(issuer),
-		audience: strings.TrimSpace(audience),
-		client:   &http.Client{Timeout: 5 * time.Second},
-	}
-	v.cache = newJWKSCache(v.client, jwksURL, 15*time.Minute)
+		logger: logger,
+		cfg:    cfg,
+		now:    time.Now,
+		client: &http.Client{Timeout: 5 * time.Second},
+		stop:   make(chan struct{}),
+		done:   make(chan struct{}),
+	}
+	// バックグラウンド更新が止まっている場合に備え、更新間隔を過ぎたキャッシュはリクエスト時にも再取得する
+	v.cache = newJWKSCache(v.client, cfg.JWKSURL, cfg.RefreshInterval+cfg.RefreshInterval/2, cfg.MinRefetchInterval)
 	return v, nil
 }
 
+// JWKS のバックグラウンド更新を開始する。Stop を呼ぶまで RefreshInterval ごとに再取得する。
+func (v *ClerkJWTValidator) Start() {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if v.started {
+		return
+	}
+	v.started = true
+	go v.run()
+}
+
+// JWKS のバックグラウンド更新を停止する。
+func (v *ClerkJWTValidator) Stop() {
+	v.mu.Lock()
+	if !v.started {
+		v.mu.Unlock()
+		return
+	}
+	select {
+	case <-v.stop:
+	default:
+		close(v.stop)
+	}
+	v.mu.Unlock()
+	<-v.done
+}
+
+// 起動直後に JWKS を取得し、以降は RefreshInterval ごとに再取得する。
+func (v *ClerkJWTValidator) run() {
+	defer close(v.done)
+
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+	for {
+		select {
+		case <-v.stop:
+			return
+		case <-timer.C:
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), v.client.Timeout)
+		err := v.cache.refresh(ctx)
+		cancel()
+
+		next := v.cfg.RefreshInterval
+		if err != nil {
+			// 失敗した場合は取得済みの鍵を使い続け、短い間隔で再試行する
+			v.logger.Warn("middleware.ClerkJWTValidator: failed to refresh jwks", slog.Any("error", err))
+			next = v.cfg.MinRefetchInterval
+		}
+		timer.Reset(next)
+	}
+}
+
 // JWT ヘッダーの構造。
 type jwtHeader struct {
 	Alg string `json:"alg"`
@@ -54,7 +195,7 @@ type jwtHeader struct {
 	Typ string `json:"typ"`
 }
 
-// JWT の署名/期限/（任意でiss/aud）を検証し、payload(claims)を返す。
+// JWT の署名/期限/（任意でiss/aud/azp）を検証し、payload(claims)を返す。
 func (v *ClerkJWTValidator) Verify(ctx context.Context, token string) (map[string]any, error) {
 	token = strings.TrimSpace(token)
 	if token == "" {
@@ -79,8 +220,10 @@ func (v *ClerkJWTValidator) Verify(ctx context.Context, token string) (map[strin
 		return nil, errors.New("invalid token header json")
 	}
 
-	// JWT アルゴリズムが RS256 でない場合はエラー。
-	if h.Alg != "RS256" {
+	// サポートしていないアルゴリズム（none 等）はエラー。
+	switch h.Alg {
+	case jwtAlgRS256, jwtAlgES256, jwtAlgEdDSA:
+	default:
 		return nil, fmt.Errorf("unsupported jwt alg: %s", h.Alg)
 	}
 
@@ -101,61 +244,105 @@ func (v *ClerkJWTValidator) Verify(ctx context.Context, token string) (map[strin
 		return nil, errors.New("invalid token payload json")
 	}
 
-	// exp/nbf の検証（存在する場合）
-	now := time.Now().Unix()
+	// exp/nbf の検証（存在する場合）。サーバー間の時刻のずれを leeway の範囲で許容する。
+	now := v.now()
+	leeway := v.cfg.Leeway
 	if exp, ok := getNumericClaim(claims, "exp"); ok {
-		if now >= exp {
+		if !now.Add(-leeway).Before(time.Unix(exp, 0)) {
 			return nil, errors.New("token expired")
 		}
 	}
 	if nbf, ok := getNumericClaim(claims, "nbf"); ok {
-		if now < nbf {
+		if now.Add(leeway).Before(time.Unix(nbf, 0)) {
 			return nil, errors.New("token not yet valid")
 		}
 	}
 
 	// iss/aud の検証（設定されている場合のみ）
-	if v.issuer != "" {
-		if iss, _ := claims["iss"].(string); iss != v.issuer {
+	if v.cfg.Issuer != "" {
+		if iss, _ := claims["iss"].(string); iss != v.cfg.Issuer {
 			return nil, errors.New("invalid issuer")
 		}
 	}
-	if v.audience != "" {
-		if !audMatches(claims["aud"], v.audience) {
+	if v.cfg.Audience != "" {
+		if !audMatches(claims["aud"], v.cfg.Audience) {
 			return nil, errors.New("invalid audience")
 		}
 	}
 
+	// azp の検証（設定されていて、トークンに含まれる場合のみ）
+	if azp, ok := claims["azp"].(string); ok && azp != "" && len(v.cfg.AuthorizedParties) > 0 {
+		if !containsString(v.cfg.AuthorizedParties, azp) {
+			return nil, errors.New("invalid authorized party")
+		}
+	}
+
 	// JWT 署名をデコード。
 	sig, err := base64.RawURLEncoding.DecodeString(parts[2])
 	if err != nil {
 		return nil, errors.New("invalid token signature encoding")
 	}
 
-	// JWT 署名を検証。
-	signingInput := parts[0] + "." + parts[1]
-	sum := sha256.Sum256([]byte(signingInput))
-
 	// JWK を取得。
-	pubAny, err := v.cache.getKey(ctx, h.Kid)
+	key, err := v.cache.getKey(ctx, h.Kid)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get jwk: %w", err)
 	}
 
-	// JWK をパース。
-	pub, ok := pubAny.(*rsa.PublicKey)
-	if !ok || pub == nil {
-		return nil, errors.New("invalid jwk type")
-	}
-
 	// JWT 署名を検証。
-	if err := rsa.VerifyPKCS1v15(pub, crypto.SHA256, sum[:], sig); err != nil {
-		return nil, errors.New("invalid token signature")
+	if err := verifyJWTSignature(h.Alg, key, []byte(parts[0]+"."+parts[1]), sig); err != nil {
+		return nil, err
 	}
 
 	return claims, nil
 }
 
+// アルゴリズムに応じて署名を検証する。
+// 鍵の種類とアルゴリズムが一致しない場合（アルゴリズムのすり替え）はエラーとする。
+func verifyJWTSignature(alg string, key jwksKey, signingInput, sig []byte) error {
+	if key.alg != "" && key.alg != alg {
+		return errors.New("jwt alg does not match jwk")
+	}
+
+	switch alg {
+	case jwtAlgRS256:
+		pub, ok := key.pub.(*rsa.PublicKey)
+		if !ok {
+			return errors.New("invalid jwk type")
+		}
+		sum := sha256.Sum256(signingInput)
+		if err := rsa.VerifyPKCS1v15(pub, crypto.SHA256, sum[:], sig); err != nil {
+			return errors.New("invalid token signature")
+		}
+	case jwtAlgES256:
+		pub, ok := key.pub.(*ecdsa.PublicKey)
+		if !ok {
+			return errors.New("invalid jwk type")
+		}
+		// JWS の ECDSA 署名は r と s をそれぞれ32バイトで連結した形式
+		if len(sig) != 64 {
+			return errors.New("invalid token signature")
+		}
+		sum := sha256.Sum256(signingInput)
+		r := new(big.Int).SetBytes(sig[:32])
+		s := new(big.Int).SetBytes(sig[32:])
+		if !ecdsa.Verify(pub, sum[:], r, s) {
+			return errors.New("invalid token signature")
+		}
+	case jwtAlgEdDSA:
+		pub, ok := key.pub.(ed25519.PublicKey)
+		if !ok {
+			return errors.New("invalid jwk type")
+		}
+		if !ed25519.Verify(pub, signingInput, sig) {
+			return errors.New("invalid token signature")
+		}
+	default:
+		return fmt.Errorf("unsupported jwt alg: %s", alg)
+	}
+	return nil
+}
+
 // JWT ペイロードの数値型のクレームを取得する。
 func getNumericClaim(claims map[string]any, key string) (int64, bool) {
 	v, ok := claims[key]
@@ -193,35 +380,52 @@ func audMatches(aud any, expected string) bool {
 		}
 		return false
 	case []string:
-		for _, s := range t {
-			if s == expected {
-				return true
-			}
-		}
-		return false
+		return containsString(t, expected)
 	default:
 		return false
 	}
 }
 
+// values に v が含まれるか確認する。
+func containsString(values []string, v string) bool {
+	for _, s := range values {
+		if s == v {
+			return true
+		}
+	}
+	return false
+}
+
+// JWKS から取り出した公開鍵。
+type jwksKey struct {
+	pub any    // *rsa.PublicKey / *ecdsa.PublicKey / ed25519.PublicKey
+	alg string // JWK に alg が指定されている場合のみ
+}
+
 // JWKS キャッシュの構造。
 type jwksCache struct {
-	client *http.Client
-	url    string
-	ttl    time.Duration
+	client        *http.Client
+	url           string
+	ttl           time.Duration // これより古いキャッシュはリクエスト時に再取得する
+	minRefetchGap time.Duration // 未知の kid による再取得の最小間隔
 
 	mu        sync.RWMutex
 	fetchedAt time.Time
-	keys      map[string]*rsa.PublicKey
+	keys      map[string]jwksKey
+
+	// 再取得は同時に1つだけ行い、直近に試みた場合は行わない（未知の kid を大量に送られた場合の保護）
+	refreshMu   sync.Mutex
+	attemptedAt time.Time
 }
 
 // JWKS キャッシュを生成する。
-func newJWKSCache(client *http.Client, url string, ttl time.Duration) *jwksCache {
+func newJWKSCache(client *http.Client, url string, ttl, minRefetchGap time.Duration) *jwksCache {
 	return &jwksCache{
-		client: client,
-		url:    url,
-		ttl:    ttl,
-		keys:   map[string]*rsa.PublicKey{},
+		client:        client,
+		url:           url,
+		ttl:           ttl,
+		minRefetchGap: minRefetchGap,
+		keys:          map[string]jwksKey{},
 	}
 }
 
@@ -236,45 +440,66 @@ type jwk struct {
 	Kid string `json:"kid"`
 	Use string `json:"use"`
 	Alg string `json:"alg"`
-	N   string `json:"n"`
-	E   string `json:"e"`
+	N   string `json:"n"`   // RSA
+	E   string `json:"e"`   // RSA
+	Crv string `json:"crv"` // EC / OKP
+	X   string `json:"x"`   // EC / OKP
+	Y   string `json:"y"`   // EC
 }
 
 // JWK を取得する。
-func (c *jwksCache) getKey(ctx context.Context, kid string) (any, error) {
+func (c *jwksCache) getKey(ctx context.Context, kid string) (jwksKey, error) {
 	// まずキャッシュヒットを狙う
-	c.mu.RLock()
-	key, ok := c.keys[kid]
-	fetchedAt := c.fetchedAt
-	c.mu.RUnlock()
-
-	if ok && key != nil && time.Since(fetchedAt) < c.ttl {
+	key, ok, fresh := c.lookup(kid)
+	if ok && fresh {
 		return key, nil
 	}
 
-	// miss or stale なら refresh
-	if err := c.refresh(ctx); err != nil {
+	// miss or stale なら refresh（直近に試みた場合は行わない）
+	if err := c.refreshIfDue(ctx); err != nil {
 		// refresh が失敗しても、古いキャッシュに目的のkidがあれば使う（ベストエフォート）
-		c.mu.RLock()
-		key2, ok2 := c.keys[kid]
-		c.mu.RUnlock()
-		if ok2 && key2 != nil {
-			return key2, nil
+		if key, ok, _ := c.lookup(kid); ok {
+			return key, nil
 		}
-		return nil, err
+		return jwksKey{}, err
 	}
 
-	c.mu.RLock()
-	key, ok = c.keys[kid]
-	c.mu.RUnlock()
-	if !ok || key == nil {
-		return nil, errors.New("kid not found in jwks")
+	key, ok, _ = c.lookup(kid)
+	if !ok {
+		return jwksKey{}, errors.New("kid not found in jwks")
 	}
 	return key, nil
 }
 
+// キャッシュから鍵を探す。fresh はキャッシュが ttl 以内に取得されたものかどうか。
+func (c *jwksCache) lookup(kid string) (jwksKey, bool, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	key, ok := c.keys[kid]
+	return key, ok, time.Since(c.fetchedAt) < c.ttl
+}
+
+// 前回の試行から minRefetchGap 以上経っている場合のみ JWKS を再取得する。
+func (c *jwksCache) refreshIfDue(ctx context.Context) error {
+	c.refreshMu.Lock()
+	defer c.refreshMu.Unlock()
+	if !c.attemptedAt.IsZero() && time.Since(c.attemptedAt) < c.minRefetchGap {
+		return nil
+	}
+	return c.refreshLocked(ctx)
+}
+
 // JWKS をリフレッシュする。
 func (c *jwksCache) refresh(ctx context.Context) error {
+	c.refreshMu.Lock()
+	defer c.refreshMu.Unlock()
+	return c.refreshLocked(ctx)
+}
+
+// JWKS を取得してキャッシュを置き換える。refreshMu を保持して呼ぶ。
+func (c *jwksCache) refreshLocked(ctx context.Context) error {
+	c.attemptedAt = time.Now()
+
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.url, nil)
 	if err != nil {
 		return err
@@ -294,16 +519,16 @@ func (c *jwksCache) refresh(ctx context.Context) error {
 		return err
 	}
 
-	next := map[string]*rsa.PublicKey{}
+	next := map[string]jwksKey{}
 	for _, k := range jwks.Keys {
-		if k.Kty != "RSA" || strings.TrimSpace(k.Kid) == "" || k.N == "" || k.E == "" {
+		if strings.TrimSpace(k.Kid) == "" || (k.Use != "" && k.Use != "sig") {
 			continue
 		}
-		pub, err := rsaPublicKeyFromJWK(k.N, k.E)
+		pub, err := publicKeyFromJWK(k)
 		if err != nil {
 			continue
 		}
-		next[k.Kid] = pub
+		next[k.Kid] = jwksKey{pub: pub, alg: k.Alg}
 	}
 
 	if len(next) == 0 {
@@ -318,8 +543,25 @@ func (c *jwksCache) refresh(ctx context.Context) error {
 	return nil
 }
 
+// JWK を公開鍵に変換する。
+func publicKeyFromJWK(k jwk) (any, error) {
+	switch k.Kty {
+	case "RSA":
+		return rsaPublicKeyFromJWK(k.N, k.E)
+	case "EC":
+		return ecdsaPublicKeyFromJWK(k.Crv, k.X, k.Y)
+	case "OKP":
+		return ed25519PublicKeyFromJWK(k.Crv, k.X)
+	default:
+		return nil, fmt.Errorf("unsupported jwk kty: %s", k.Kty)
+	}
+}
+
 // JWK を RSA 公開鍵に変換する。
 func rsaPublicKeyFromJWK(nB64, eB64 string) (*rsa.PublicKey, error) {
+	if nB64 == "" || eB64 == "" {
+		return nil, errors.New("missing rsa parameters")
+	}
 	nBytes, err := base64.RawURLEncoding.DecodeString(nB64)
 	if err != nil {
 		return nil, err
@@ -343,3 +585,34 @@ func rsaPublicKeyFromJWK(nB64, eB64 string) (*rsa.PublicKey, error) {
 
 	return &rsa.PublicKey{N: n, E: e}, nil
 }
+
+// JWK を ECDSA（P-256）公開鍵に変換する。
+func ecdsaPublicKeyFromJWK(crv, xB64, yB64 string) (*ecdsa.PublicKey, error) {
+	if crv != "P-256" {
+		return nil, fmt.Errorf("unsupported ec curve: %s", crv)
+	}
+	x, err := base64.RawURLEncoding.DecodeString(xB64)
+	if err != nil || len(x) != 32 {
+		return nil, errors.New("invalid ec x coordinate")
+	}
+	y, err := base64.RawURLEncoding.DecodeString(yB64)
+	if err != nil || len(y) != 32 {
+		return nil, errors.New("invalid ec y coordinate")
+	}
+
+	// 非圧縮形式（0x04 || x || y）にして、曲線上の点であることも検証する
+	point := append(append([]byte{0x04}, x...), y...)
+	return ecdsa.ParseUncompressedPublicKey(elliptic.P256(), point)
+}
+
+// JWK を Ed25519 公開鍵に変換する。
+func ed25519PublicKeyFromJWK(crv, xB64 string) (ed25519.PublicKey, error) {
+	if crv != "Ed25519" {
+		return nil, fmt.Errorf("unsupported okp curve: %s", crv)
+	}
+	x, err := base64.RawURLEncoding.DecodeString(xB64)
+	if err != nil || len(x) != ed25519.PublicKeySize {
+		return nil, errors.New("invalid ed25519 key")
+	}
+	return ed25519.PublicKey(x), nil
+}
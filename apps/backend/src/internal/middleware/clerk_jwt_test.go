@@ -2,8 +2,12 @@ package middleware
 
 import (
 	"context"
+	"crypto/ed25519"
+	"sync/atomic"
 	"testing"
 	"time"
+
+	"cinetag-backend/src/internal/testutil"
 )
 
 func TestClerkJWTValidator_Verify(t *testing.T) {
@@ -18,7 +22,7 @@ func TestClerkJWTValidator_Verify(t *testing.T) {
 		srv := newJWKSServer(t, jwks)
 		t.Cleanup(srv.Close)
 
-		v, err := NewClerkJWTValidator(srv.URL, "", "")
+		v, err := NewClerkJWTValidator(testutil.NewTestLogger(), ClerkJWTConfig{JWKSURL: srv.URL})
 		if err != nil {
 			t.Fatalf("validator生成に失敗: %v", err)
 		}
@@ -49,7 +53,7 @@ func TestClerkJWTValidator_Verify(t *testing.T) {
 		srv := newJWKSServer(t, jwks)
 		t.Cleanup(srv.Close)
 
-		v, _ := NewClerkJWTValidator(srv.URL, "", "")
+		v, _ := NewClerkJWTValidator(testutil.NewTestLogger(), ClerkJWTConfig{JWKSURL: srv.URL})
 
 		claims := map[string]any{
 			"sub": "user_123",
@@ -72,7 +76,7 @@ func TestClerkJWTValidator_Verify(t *testing.T) {
 		srv := newJWKSServer(t, jwks)
 		t.Cleanup(srv.Close)
 
-		v, _ := NewClerkJWTValidator(srv.URL, "", "")
+		v, _ := NewClerkJWTValidator(testutil.NewTestLogger(), ClerkJWTConfig{JWKSURL: srv.URL})
 
 		claims := map[string]any{
 			"sub": "user_123",
@@ -96,7 +100,7 @@ func TestClerkJWTValidator_Verify(t *testing.T) {
 		srv := newJWKSServer(t, jwks)
 		t.Cleanup(srv.Close)
 
-		v, _ := NewClerkJWTValidator(srv.URL, "iss-ok", "")
+		v, _ := NewClerkJWTValidator(testutil.NewTestLogger(), ClerkJWTConfig{JWKSURL: srv.URL, Issuer: "iss-ok"})
 
 		claims := map[string]any{
 			"iss": "iss-ng",
@@ -120,7 +124,7 @@ func TestClerkJWTValidator_Verify(t *testing.T) {
 		srv := newJWKSServer(t, jwks)
 		t.Cleanup(srv.Close)
 
-		v, _ := NewClerkJWTValidator(srv.URL, "", "aud-ok")
+		v, _ := NewClerkJWTValidator(testutil.NewTestLogger(), ClerkJWTConfig{JWKSURL: srv.URL, Audience: "aud-ok"})
 
 		claims := map[string]any{
 			"aud": "aud-ng",
@@ -135,14 +139,14 @@ func TestClerkJWTValidator_Verify(t *testing.T) {
 		}
 	})
 
-	t.Run("失敗: alg が RS256 以外", func(t *testing.T) {
+	t.Run("失敗: alg が未対応（HS256）", func(t *testing.T) {
 		t.Parallel()
 
 		header := map[string]any{"alg": "HS256", "kid": "kid1", "typ": "JWT"}
 		claims := map[string]any{"sub": "user_123"}
 		token := mustBuildJWTWithHeader(t, header, claims, "")
 
-		v, _ := NewClerkJWTValidator("http://example.invalid/jwks", "", "")
+		v, _ := NewClerkJWTValidator(testutil.NewTestLogger(), ClerkJWTConfig{JWKSURL: "http://example.invalid/jwks"})
 		_, err := v.Verify(context.Background(), token)
 		if err == nil {
 			t.Fatalf("expected error")
@@ -156,7 +160,7 @@ func TestClerkJWTValidator_Verify(t *testing.T) {
 		claims := map[string]any{"sub": "user_123"}
 		token := mustBuildJWTWithHeader(t, header, claims, "")
 
-		v, _ := NewClerkJWTValidator("http://example.invalid/jwks", "", "")
+		v, _ := NewClerkJWTValidator(testutil.NewTestLogger(), ClerkJWTConfig{JWKSURL: "http://example.invalid/jwks"})
 		_, err := v.Verify(context.Background(), token)
 		if err == nil {
 			t.Fatalf("expected error")
@@ -174,7 +178,7 @@ func TestClerkJWTValidator_Verify(t *testing.T) {
 		srv := newJWKSServer(t, jwks)
 		t.Cleanup(srv.Close)
 
-		v, _ := NewClerkJWTValidator(srv.URL, "", "")
+		v, _ := NewClerkJWTValidator(testutil.NewTestLogger(), ClerkJWTConfig{JWKSURL: srv.URL})
 
 		claims := map[string]any{
 			"sub": "user_123",
@@ -198,7 +202,7 @@ func TestClerkJWTValidator_Verify(t *testing.T) {
 		srv := newJWKSServer(t, jwks)
 		t.Cleanup(srv.Close)
 
-		v, _ := NewClerkJWTValidator(srv.URL, "", "aud-ok")
+		v, _ := NewClerkJWTValidator(testutil.NewTestLogger(), ClerkJWTConfig{JWKSURL: srv.URL, Audience: "aud-ok"})
 
 		claims := map[string]any{
 			"aud": []any{"aud-other", "aud-ok"},
@@ -225,7 +229,7 @@ func TestClerkJWTValidator_Verify(t *testing.T) {
 		srv := newJWKSServer(t, jwks)
 		t.Cleanup(srv.Close)
 
-		v, _ := NewClerkJWTValidator(srv.URL, "", "aud-ok")
+		v, _ := NewClerkJWTValidator(testutil.NewTestLogger(), ClerkJWTConfig{JWKSURL: srv.URL, Audience: "aud-ok"})
 
 		claims := map[string]any{
 			"aud": []any{"aud-other", "aud-ng"},
@@ -249,7 +253,7 @@ func TestClerkJWTValidator_Verify(t *testing.T) {
 		srv := newJWKSServer(t, jwks)
 		t.Cleanup(srv.Close)
 
-		v, _ := NewClerkJWTValidator(srv.URL, "iss-ok", "")
+		v, _ := NewClerkJWTValidator(testutil.NewTestLogger(), ClerkJWTConfig{JWKSURL: srv.URL, Issuer: "iss-ok"})
 
 		claims := map[string]any{
 			"iss": "iss-ok",
@@ -276,7 +280,7 @@ func TestClerkJWTValidator_Verify(t *testing.T) {
 		srv := newJWKSServer(t, jwks)
 		t.Cleanup(srv.Close)
 
-		v, _ := NewClerkJWTValidator(srv.URL, "", "aud-ok")
+		v, _ := NewClerkJWTValidator(testutil.NewTestLogger(), ClerkJWTConfig{JWKSURL: srv.URL, Audience: "aud-ok"})
 
 		claims := map[string]any{
 			"aud": "aud-ok",
@@ -303,7 +307,7 @@ func TestClerkJWTValidator_Verify(t *testing.T) {
 		srv := newJWKSServer(t, jwks)
 		t.Cleanup(srv.Close)
 
-		v, _ := NewClerkJWTValidator(srv.URL, "", "aud-ok")
+		v, _ := NewClerkJWTValidator(testutil.NewTestLogger(), ClerkJWTConfig{JWKSURL: srv.URL, Audience: "aud-ok"})
 
 		claims := map[string]any{
 			"aud": 12345, // 不正な型
@@ -327,7 +331,7 @@ func TestClerkJWTValidator_Verify(t *testing.T) {
 		srv := newJWKSServer(t, jwks)
 		t.Cleanup(srv.Close)
 
-		v, _ := NewClerkJWTValidator(srv.URL, "", "")
+		v, _ := NewClerkJWTValidator(testutil.NewTestLogger(), ClerkJWTConfig{JWKSURL: srv.URL})
 
 		claims := map[string]any{
 			"sub": "user_123",
@@ -354,7 +358,7 @@ func TestClerkJWTValidator_Verify(t *testing.T) {
 		srv := newJWKSServer(t, jwks)
 		t.Cleanup(srv.Close)
 
-		v, _ := NewClerkJWTValidator(srv.URL, "", "")
+		v, _ := NewClerkJWTValidator(testutil.NewTestLogger(), ClerkJWTConfig{JWKSURL: srv.URL})
 
 		claims := map[string]any{
 			"sub": "user_123",
@@ -380,7 +384,7 @@ func TestClerkJWTValidator_Verify(t *testing.T) {
 		srv := newJWKSServer(t, jwks)
 		t.Cleanup(srv.Close)
 
-		v, _ := NewClerkJWTValidator(srv.URL, "expected-iss", "")
+		v, _ := NewClerkJWTValidator(testutil.NewTestLogger(), ClerkJWTConfig{JWKSURL: srv.URL, Issuer: "expected-iss"})
 
 		claims := map[string]any{
 			"sub": "user_123",
@@ -404,7 +408,7 @@ func TestClerkJWTValidator_Verify(t *testing.T) {
 		srv := newJWKSServer(t, jwks)
 		t.Cleanup(srv.Close)
 
-		v, _ := NewClerkJWTValidator(srv.URL, "", "expected-aud")
+		v, _ := NewClerkJWTValidator(testutil.NewTestLogger(), ClerkJWTConfig{JWKSURL: srv.URL, Audience: "expected-aud"})
 
 		claims := map[string]any{
 			"sub": "user_123",
@@ -419,3 +423,308 @@ func TestClerkJWTValidator_Verify(t *testing.T) {
 		}
 	})
 }
+
+func TestClerkJWTValidator_VerifyAlgorithms(t *testing.T) {
+	t.Parallel()
+
+	claims := func() map[string]any {
+		return map[string]any{"sub": "user_123", "exp": time.Now().Add(10 * time.Minute).Unix()}
+	}
+
+	t.Run("成功: ES256署名が検証できる", func(t *testing.T) {
+		t.Parallel()
+
+		priv := mustNewECDSAKey(t)
+		srv := newJWKSServer(t, testJWKS{Keys: []testJWK{jwkFromECDSAPublicKey(t, "ec1", &priv.PublicKey)}})
+		t.Cleanup(srv.Close)
+
+		v, err := NewClerkJWTValidator(testutil.NewTestLogger(), ClerkJWTConfig{JWKSURL: srv.URL})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		got, err := v.Verify(context.Background(), mustSignES256JWT(t, "ec1", claims(), priv))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got["sub"] != "user_123" {
+			t.Fatalf("expected sub=user_123, got %v", got["sub"])
+		}
+	})
+
+	t.Run("成功: EdDSA署名が検証できる", func(t *testing.T) {
+		t.Parallel()
+
+		priv := mustNewEd25519Key(t)
+		srv := newJWKSServer(t, testJWKS{Keys: []testJWK{jwkFromEd25519PublicKey("ed1", priv.Public().(ed25519.PublicKey))}})
+		t.Cleanup(srv.Close)
+
+		v, _ := NewClerkJWTValidator(testutil.NewTestLogger(), ClerkJWTConfig{JWKSURL: srv.URL})
+		if _, err := v.Verify(context.Background(), mustSignEdDSAJWT(t, "ed1", claims(), priv)); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("失敗: ES256署名が不正", func(t *testing.T) {
+		t.Parallel()
+
+		priv := mustNewECDSAKey(t)
+		other := mustNewECDSAKey(t)
+		srv := newJWKSServer(t, testJWKS{Keys: []testJWK{jwkFromECDSAPublicKey(t, "ec1", &priv.PublicKey)}})
+		t.Cleanup(srv.Close)
+
+		v, _ := NewClerkJWTValidator(testutil.NewTestLogger(), ClerkJWTConfig{JWKSURL: srv.URL})
+		if _, err := v.Verify(context.Background(), mustSignES256JWT(t, "ec1", claims(), other)); err == nil {
+			t.Fatalf("expected error")
+		}
+	})
+
+	t.Run("失敗: ヘッダーの alg と JWK の鍵の種類が一致しない", func(t *testing.T) {
+		t.Parallel()
+
+		// RSA 鍵の kid を指定した ES256 トークン（アルゴリズムのすり替え）
+		rsaKey := mustNewRSAKey(t)
+		ecKey := mustNewECDSAKey(t)
+		srv := newJWKSServer(t, testJWKS{Keys: []testJWK{jwkFromPublicKey("kid1", &rsaKey.PublicKey)}})
+		t.Cleanup(srv.Close)
+
+		v, _ := NewClerkJWTValidator(testutil.NewTestLogger(), ClerkJWTConfig{JWKSURL: srv.URL})
+		if _, err := v.Verify(context.Background(), mustSignES256JWT(t, "kid1", claims(), ecKey)); err == nil {
+			t.Fatalf("expected error")
+		}
+	})
+}
+
+func TestClerkJWTValidator_Leeway(t *testing.T) {
+	t.Parallel()
+
+	priv := mustNewRSAKey(t)
+	srv := newJWKSServer(t, testJWKS{Keys: []testJWK{jwkFromPublicKey("kid1", &priv.PublicKey)}})
+	t.Cleanup(srv.Close)
+
+	// 検証時刻を固定し、テストの実行時間に左右されないようにする
+	now := time.Unix(time.Now().Unix(), 0)
+	expiredJustNow := mustSignRS256JWT(t, "kid1", map[string]any{"sub": "user_123", "exp": now.Add(-2 * time.Second).Unix()}, priv)
+	notYetValid := mustSignRS256JWT(t, "kid1", map[string]any{"sub": "user_123", "nbf": now.Add(2 * time.Second).Unix()}, priv)
+	newValidator := func(leeway time.Duration, noLeeway bool) *ClerkJWTValidator {
+		v, _ := NewClerkJWTValidator(testutil.NewTestLogger(), ClerkJWTConfig{JWKSURL: srv.URL, Leeway: leeway, NoLeeway: noLeeway})
+		v.now = func() time.Time { return now }
+		return v
+	}
+
+	t.Run("既定の leeway では数秒のずれを許容する", func(t *testing.T) {
+		t.Parallel()
+
+		v := newValidator(0, false)
+		if _, err := v.Verify(context.Background(), expiredJustNow); err != nil {
+			t.Fatalf("exp: unexpected error: %v", err)
+		}
+		if _, err := v.Verify(context.Background(), notYetValid); err != nil {
+			t.Fatalf("nbf: unexpected error: %v", err)
+		}
+	})
+
+	t.Run("NoLeeway ならずれを許容しない", func(t *testing.T) {
+		t.Parallel()
+
+		v := newValidator(0, true)
+		if _, err := v.Verify(context.Background(), expiredJustNow); err == nil {
+			t.Fatalf("exp: expected error")
+		}
+		if _, err := v.Verify(context.Background(), notYetValid); err == nil {
+			t.Fatalf("nbf: expected error")
+		}
+	})
+
+	t.Run("leeway を超えて期限切れなら失敗", func(t *testing.T) {
+		t.Parallel()
+
+		v := newValidator(time.Second, false)
+		if _, err := v.Verify(context.Background(), expiredJustNow); err == nil {
+			t.Fatalf("expected error")
+		}
+	})
+}
+
+func TestClerkJWTValidator_AuthorizedParties(t *testing.T) {
+	t.Parallel()
+
+	priv := mustNewRSAKey(t)
+	srv := newJWKSServer(t, testJWKS{Keys: []testJWK{jwkFromPublicKey("kid1", &priv.PublicKey)}})
+	t.Cleanup(srv.Close)
+
+	v, _ := NewClerkJWTValidator(testutil.NewTestLogger(), ClerkJWTConfig{
+		JWKSURL:           srv.URL,
+		AuthorizedParties: []string{"https://cine-tag.com", "http://localhost:3000"},
+	})
+
+	tests := []struct {
+		name    string
+		azp     any
+		wantErr bool
+	}{
+		{name: "許可されたオリジン", azp: "https://cine-tag.com"},
+		{name: "azp が無いトークンは検証しない", azp: nil},
+		{name: "許可されていないオリジン", azp: "https://evil.example.com", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			claims := map[string]any{"sub": "user_123", "exp": time.Now().Add(10 * time.Minute).Unix()}
+			if tt.azp != nil {
+				claims["azp"] = tt.azp
+			}
+			_, err := v.Verify(context.Background(), mustSignRS256JWT(t, "kid1", claims, priv))
+			if tt.wantErr && err == nil {
+				t.Fatalf("expected error")
+			}
+			if !tt.wantErr && err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestClerkJWTValidator_RefetchUnknownKid(t *testing.T) {
+	t.Parallel()
+
+	oldKey := mustNewRSAKey(t)
+	newKey := mustNewRSAKey(t)
+	claims := func() map[string]any {
+		return map[string]any{"sub": "user_123", "exp": time.Now().Add(10 * time.Minute).Unix()}
+	}
+
+	// 1回目の取得では旧鍵、2回目以降は新鍵を返す（鍵のローテーション）
+	newRotatingServer := func(t *testing.T) (string, func() int32) {
+		var served atomic.Int32
+		srv, count := newCountingJWKSServer(t, func() testJWKS {
+			if served.Add(1) == 1 {
+				return testJWKS{Keys: []testJWK{jwkFromPublicKey("old", &oldKey.PublicKey)}}
+			}
+			return testJWKS{Keys: []testJWK{jwkFromPublicKey("new", &newKey.PublicKey)}}
+		})
+		t.Cleanup(srv.Close)
+		return srv.URL, count.Load
+	}
+
+	t.Run("未知の kid を受け取ったら JWKS を再取得する", func(t *testing.T) {
+		t.Parallel()
+
+		url, count := newRotatingServer(t)
+		v, _ := NewClerkJWTValidator(testutil.NewTestLogger(), ClerkJWTConfig{JWKSURL: url, MinRefetchInterval: time.Millisecond})
+
+		if _, err := v.Verify(context.Background(), mustSignRS256JWT(t, "old", claims(), oldKey)); err != nil {
+			t.Fatalf("old: unexpected error: %v", err)
+		}
+		time.Sleep(5 * time.Millisecond)
+		if _, err := v.Verify(context.Background(), mustSignRS256JWT(t, "new", claims(), newKey)); err != nil {
+			t.Fatalf("new: unexpected error: %v", err)
+		}
+		if got := count(); got != 2 {
+			t.Fatalf("expected 2 fetches, got %d", got)
+		}
+	})
+
+	t.Run("再取得は最小間隔で制限される", func(t *testing.T) {
+		t.Parallel()
+
+		url, count := newRotatingServer(t)
+		v, _ := NewClerkJWTValidator(testutil.NewTestLogger(), ClerkJWTConfig{JWKSURL: url, MinRefetchInterval: time.Hour})
+
+		if _, err := v.Verify(context.Background(), mustSignRS256JWT(t, "old", claims(), oldKey)); err != nil {
+			t.Fatalf("old: unexpected error: %v", err)
+		}
+		for i := 0; i < 5; i++ {
+			if _, err := v.Verify(context.Background(), mustSignRS256JWT(t, "unknown", claims(), newKey)); err == nil {
+				t.Fatalf("expected error")
+			}
+		}
+		if got := count(); got != 1 {
+			t.Fatalf("expected 1 fetch, got %d", got)
+		}
+	})
+}
+
+func TestClerkJWTValidator_StartRefreshesInBackground(t *testing.T) {
+	t.Parallel()
+
+	priv := mustNewRSAKey(t)
+	srv, count := newCountingJWKSServer(t, func() testJWKS {
+		return testJWKS{Keys: []testJWK{jwkFromPublicKey("kid1", &priv.PublicKey)}}
+	})
+	t.Cleanup(srv.Close)
+
+	v, _ := NewClerkJWTValidator(testutil.NewTestLogger(), ClerkJWTConfig{JWKSURL: srv.URL, RefreshInterval: 10 * time.Millisecond})
+	v.Start()
+	v.Start() // 二重に呼んでも goroutine は1つだけ
+
+	deadline := time.Now().Add(2 * time.Second)
+	for count.Load() < 3 {
+		if time.Now().After(deadline) {
+			t.Fatalf("expected background refreshes, got %d", count.Load())
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	v.Stop()
+
+	// 停止後は再取得しない
+	stopped := count.Load()
+	time.Sleep(30 * time.Millisecond)
+	if got := count.Load(); got != stopped {
+		t.Fatalf("expected no refresh after Stop, got %d -> %d", stopped, got)
+	}
+
+	// 取得済みの鍵で検証できる（リクエスト時の取得は発生しない）
+	token := mustSignRS256JWT(t, "kid1", map[string]any{"sub": "user_123", "exp": time.Now().Add(10 * time.Minute).Unix()}, priv)
+	if _, err := v.Verify(context.Background(), token); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := count.Load(); got != stopped {
+		t.Fatalf("expected no fetch on verify, got %d -> %d", stopped, got)
+	}
+}
+
+func TestClerkJWTConfigFromEnv(t *testing.T) {
+	t.Run("環境変数を読み込む", func(t *testing.T) {
+		t.Setenv("CLERK_JWKS_URL", "https://example.clerk.accounts.dev/.well-known/jwks.json")
+		t.Setenv("CLERK_ISSUER", "https://example.clerk.accounts.dev")
+		t.Setenv("CLERK_AUDIENCE", "")
+		t.Setenv("CLERK_AUTHORIZED_PARTIES", " https://cine-tag.com, ,http://localhost:3000 ")
+		t.Setenv("CLERK_JWT_LEEWAY_SECONDS", "10")
+
+		cfg, err := ClerkJWTConfigFromEnv()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if cfg.JWKSURL != "https://example.clerk.accounts.dev/.well-known/jwks.json" || cfg.Issuer != "https://example.clerk.accounts.dev" {
+			t.Fatalf("unexpected config: %+v", cfg)
+		}
+		if len(cfg.AuthorizedParties) != 2 || cfg.AuthorizedParties[0] != "https://cine-tag.com" || cfg.AuthorizedParties[1] != "http://localhost:3000" {
+			t.Fatalf("unexpected authorized parties: %v", cfg.AuthorizedParties)
+		}
+		if cfg.Leeway != 10*time.Second {
+			t.Fatalf("expected leeway 10s, got %v", cfg.Leeway)
+		}
+	})
+
+	t.Run("leeway に 0 を指定するとずれを許容しない", func(t *testing.T) {
+		t.Setenv("CLERK_JWT_LEEWAY_SECONDS", "0")
+
+		cfg, err := ClerkJWTConfigFromEnv()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !cfg.NoLeeway {
+			t.Fatalf("expected NoLeeway")
+		}
+		if got := cfg.withDefaults().Leeway; got != 0 {
+			t.Fatalf("expected leeway 0, got %v", got)
+		}
+	})
+
+	t.Run("leeway が不正: エラー", func(t *testing.T) {
+		t.Setenv("CLERK_JWT_LEEWAY_SECONDS", "abc")
+
+		if _, err := ClerkJWTConfigFromEnv(); err == nil {
+			t.Fatalf("expected error")
+		}
+	})
+}
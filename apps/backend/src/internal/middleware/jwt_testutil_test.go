@@ -2,6 +2,9 @@ package middleware
 
 import (
 	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
 	"crypto/rand"
 	"crypto/rsa"
 	"crypto/sha256"
@@ -9,6 +12,7 @@ import (
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"sync/atomic"
 	"testing"
 )
 
@@ -17,8 +21,11 @@ type testJWK struct {
 	Kid string `json:"kid"`
 	Use string `json:"use"`
 	Alg string `json:"alg"`
-	N   string `json:"n"`
-	E   string `json:"e"`
+	N   string `json:"n,omitempty"`
+	E   string `json:"e,omitempty"`
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+	Y   string `json:"y,omitempty"`
 }
 
 type testJWKS struct {
@@ -57,6 +64,53 @@ func jwkFromPublicKey(kid string, pub *rsa.PublicKey) testJWK {
 	}
 }
 
+func mustNewECDSAKey(t *testing.T) *ecdsa.PrivateKey {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("ECDSA鍵の生成に失敗: %v", err)
+	}
+	return key
+}
+
+func mustNewEd25519Key(t *testing.T) ed25519.PrivateKey {
+	t.Helper()
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("Ed25519鍵の生成に失敗: %v", err)
+	}
+	return key
+}
+
+func jwkFromECDSAPublicKey(t *testing.T, kid string, pub *ecdsa.PublicKey) testJWK {
+	t.Helper()
+	// 非圧縮形式（0x04 || x || y）から x / y を取り出す
+	b, err := pub.Bytes()
+	if err != nil {
+		t.Fatalf("ECDSA公開鍵の変換に失敗: %v", err)
+	}
+	return testJWK{
+		Kty: "EC",
+		Kid: kid,
+		Use: "sig",
+		Alg: "ES256",
+		Crv: "P-256",
+		X:   base64.RawURLEncoding.EncodeToString(b[1:33]),
+		Y:   base64.RawURLEncoding.EncodeToString(b[33:]),
+	}
+}
+
+func jwkFromEd25519PublicKey(kid string, pub ed25519.PublicKey) testJWK {
+	return testJWK{
+		Kty: "OKP",
+		Kid: kid,
+		Use: "sig",
+		Alg: "EdDSA",
+		Crv: "Ed25519",
+		X:   base64.RawURLEncoding.EncodeToString(pub),
+	}
+}
+
 func newJWKSServer(t *testing.T, jwks testJWKS) *httptest.Server {
 	t.Helper()
 	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -88,6 +142,46 @@ func mustSignRS256JWT(t *testing.T, kid string, claims map[string]any, priv *rsa
 	return signingInput + "." + encodedSig
 }
 
+// JWKS の取得回数を数えるサーバー。jwks は取得のたびに呼び出され、鍵の入れ替えを再現できる。
+func newCountingJWKSServer(t *testing.T, jwks func() testJWKS) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+	var count atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		count.Add(1)
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(jwks())
+	}))
+	return srv, &count
+}
+
+func mustSignES256JWT(t *testing.T, kid string, claims map[string]any, priv *ecdsa.PrivateKey) string {
+	t.Helper()
+
+	header := map[string]any{"alg": "ES256", "kid": kid, "typ": "JWT"}
+	signingInput := mustBase64URLJSON(t, header) + "." + mustBase64URLJSON(t, claims)
+
+	sum := sha256.Sum256([]byte(signingInput))
+	r, s, err := ecdsa.Sign(rand.Reader, priv, sum[:])
+	if err != nil {
+		t.Fatalf("JWT署名に失敗: %v", err)
+	}
+	// JWS の形式（r と s をそれぞれ32バイトで連結）
+	sig := make([]byte, 64)
+	r.FillBytes(sig[:32])
+	s.FillBytes(sig[32:])
+	return signingInput + "." + base64.RawURLEncoding.EncodeToString(sig)
+}
+
+func mustSignEdDSAJWT(t *testing.T, kid string, claims map[string]any, priv ed25519.PrivateKey) string {
+	t.Helper()
+
+	header := map[string]any{"alg": "EdDSA", "kid": kid, "typ": "JWT"}
+	signingInput := mustBase64URLJSON(t, header) + "." + mustBase64URLJSON(t, claims)
+
+	sig := ed25519.Sign(priv, []byte(signingInput))
+	return signingInput + "." + base64.RawURLEncoding.EncodeToString(sig)
+}
+
 func mustBuildJWTWithHeader(t *testing.T, header map[string]any, claims map[string]any, signatureB64URL string) string {
 	t.Helper()
 	encodedHeader := mustBase64URLJSON(t, header)
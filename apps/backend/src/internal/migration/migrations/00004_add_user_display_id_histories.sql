@@ -0,0 +1,24 @@
+-- +goose Up
+-- ================================================================
+-- display_id 変更履歴テーブル追加
+-- 変更前の display_id から現在のプロフィールへリダイレクトするために利用する
+-- ================================================================
+
+CREATE TABLE user_display_id_histories (
+    id         UUID        NOT NULL DEFAULT gen_random_uuid(),
+    user_id    UUID        NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    display_id TEXT        NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    CONSTRAINT user_display_id_histories_pkey PRIMARY KEY (id)
+);
+
+CREATE UNIQUE INDEX user_display_id_histories_display_id_key
+    ON user_display_id_histories (display_id);
+
+CREATE INDEX idx_user_display_id_histories_user_id
+    ON user_display_id_histories (user_id);
+
+-- +goose Down
+
+DROP TABLE IF EXISTS user_display_id_histories;
@@ -0,0 +1,28 @@
+-- +goose Up
+-- ================================================================
+-- ユーザーのブロック・ミュートテーブル追加
+-- ================================================================
+
+CREATE TABLE user_blocks (
+    blocker_id UUID        NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    blocked_id UUID        NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    CONSTRAINT user_blocks_pkey PRIMARY KEY (blocker_id, blocked_id)
+);
+
+CREATE INDEX idx_user_blocks_blocked_id
+    ON user_blocks (blocked_id);
+
+CREATE TABLE user_mutes (
+    muter_id   UUID        NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    muted_id   UUID        NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    CONSTRAINT user_mutes_pkey PRIMARY KEY (muter_id, muted_id)
+);
+
+-- +goose Down
+
+DROP TABLE IF EXISTS user_mutes;
+DROP TABLE IF EXISTS user_blocks;
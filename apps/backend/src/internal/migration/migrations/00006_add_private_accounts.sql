@@ -0,0 +1,23 @@
+-- +goose Up
+-- ================================================================
+-- 非公開アカウントとフォローリクエスト
+-- ================================================================
+
+ALTER TABLE users ADD COLUMN is_private BOOLEAN NOT NULL DEFAULT false;
+
+CREATE TABLE user_follow_requests (
+    requester_id UUID        NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    target_id    UUID        NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    created_at   TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    CONSTRAINT user_follow_requests_pkey PRIMARY KEY (requester_id, target_id)
+);
+
+CREATE INDEX idx_user_follow_requests_target_created
+    ON user_follow_requests (target_id, created_at DESC);
+
+-- +goose Down
+
+DROP TABLE IF EXISTS user_follow_requests;
+
+ALTER TABLE users DROP COLUMN IF EXISTS is_private;
@@ -0,0 +1,29 @@
+-- +goose Up
+-- ================================================================
+-- 個人データエクスポート
+-- 非同期で生成したエクスポートファイルを保持する
+-- ================================================================
+
+CREATE TABLE user_data_exports (
+    id            UUID        NOT NULL DEFAULT gen_random_uuid(),
+    user_id       UUID        NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    format        TEXT        NOT NULL,
+    status        TEXT        NOT NULL DEFAULT 'pending',
+    file_name     TEXT,
+    content       BYTEA,
+    error_message TEXT,
+    expires_at    TIMESTAMPTZ,
+    completed_at  TIMESTAMPTZ,
+    created_at    TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    CONSTRAINT user_data_exports_pkey PRIMARY KEY (id),
+    CONSTRAINT user_data_exports_format_check CHECK (format IN ('json', 'zip')),
+    CONSTRAINT user_data_exports_status_check CHECK (status IN ('pending', 'processing', 'completed', 'failed'))
+);
+
+CREATE INDEX idx_user_data_exports_user_created
+    ON user_data_exports (user_id, created_at DESC);
+
+-- +goose Down
+
+DROP TABLE IF EXISTS user_data_exports;
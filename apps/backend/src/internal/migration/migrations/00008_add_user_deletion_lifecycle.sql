@@ -0,0 +1,44 @@
+-- +goose Up
+-- ================================================================
+-- 退会の状態遷移（active → deactivated → purged）
+-- 猶予期間中は再開でき、期限を過ぎたユーザーはジョブで完全削除する
+-- 所有タグ・他者タグへの追加映画の扱いは退会時のポリシーに従う
+-- ================================================================
+
+ALTER TABLE users
+    ADD COLUMN deletion_status      TEXT        NOT NULL DEFAULT 'active',
+    ADD COLUMN purge_scheduled_at   TIMESTAMPTZ,
+    ADD COLUMN purged_at            TIMESTAMPTZ,
+    ADD COLUMN owned_tags_policy    TEXT,
+    ADD COLUMN tag_transfer_user_id UUID        REFERENCES users(id) ON DELETE SET NULL,
+    ADD COLUMN contributions_policy TEXT,
+    ADD CONSTRAINT users_deletion_status_check CHECK (deletion_status IN ('active', 'deactivated', 'purged')),
+    ADD CONSTRAINT users_owned_tags_policy_check CHECK (owned_tags_policy IN ('delete', 'transfer')),
+    ADD CONSTRAINT users_contributions_policy_check CHECK (contributions_policy IN ('anonymize', 'remove'));
+
+CREATE INDEX idx_users_purge_scheduled
+    ON users (purge_scheduled_at)
+    WHERE deletion_status = 'deactivated';
+
+-- 既に退会済みのユーザーは、既定ポリシーで完全削除の対象にする
+UPDATE users
+SET deletion_status      = 'deactivated',
+    purge_scheduled_at   = deleted_at + INTERVAL '30 days',
+    owned_tags_policy    = 'delete',
+    contributions_policy = 'anonymize'
+WHERE deleted_at IS NOT NULL;
+
+-- +goose Down
+
+DROP INDEX IF EXISTS idx_users_purge_scheduled;
+
+ALTER TABLE users
+    DROP CONSTRAINT IF EXISTS users_contributions_policy_check,
+    DROP CONSTRAINT IF EXISTS users_owned_tags_policy_check,
+    DROP CONSTRAINT IF EXISTS users_deletion_status_check,
+    DROP COLUMN IF EXISTS contributions_policy,
+    DROP COLUMN IF EXISTS tag_transfer_user_id,
+    DROP COLUMN IF EXISTS owned_tags_policy,
+    DROP COLUMN IF EXISTS purged_at,
+    DROP COLUMN IF EXISTS purge_scheduled_at,
+    DROP COLUMN IF EXISTS deletion_status;
@@ -0,0 +1,24 @@
+-- +goose Up
+-- ================================================================
+-- ユーザーごとの映画の視聴ステータス（観た / 観たい / 途中でやめた）
+-- ================================================================
+
+CREATE TABLE user_movie_statuses (
+    user_id       UUID        NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    tmdb_movie_id INTEGER     NOT NULL,
+    status        TEXT        NOT NULL,
+    watched_at    DATE,
+    created_at    TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at    TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    CONSTRAINT user_movie_statuses_pkey PRIMARY KEY (user_id, tmdb_movie_id),
+    CONSTRAINT user_movie_statuses_status_check CHECK (status IN ('watched', 'want_to_watch', 'dropped')),
+    CONSTRAINT user_movie_statuses_watched_at_check CHECK (watched_at IS NULL OR status = 'watched')
+);
+
+CREATE INDEX idx_user_movie_statuses_user_status
+    ON user_movie_statuses (user_id, status, updated_at DESC);
+
+-- +goose Down
+
+DROP TABLE IF EXISTS user_movie_statuses;
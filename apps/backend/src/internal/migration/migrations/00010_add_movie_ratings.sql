@@ -0,0 +1,31 @@
+-- +goose Up
+-- ================================================================
+-- ユーザーごとの映画の評価（0.5〜5.0 の星評価）と短いレビュー
+-- 1ユーザーにつき1作品1件
+-- ================================================================
+
+CREATE TABLE movie_ratings (
+    id            UUID         NOT NULL DEFAULT gen_random_uuid(),
+    user_id       UUID         NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    tmdb_movie_id INTEGER      NOT NULL,
+    rating        NUMERIC(2,1) NOT NULL,
+    review        TEXT,
+    is_spoiler    BOOLEAN      NOT NULL DEFAULT FALSE,
+    created_at    TIMESTAMPTZ  NOT NULL DEFAULT NOW(),
+    updated_at    TIMESTAMPTZ  NOT NULL DEFAULT NOW(),
+
+    CONSTRAINT movie_ratings_pkey PRIMARY KEY (id),
+    CONSTRAINT movie_ratings_user_movie_unique UNIQUE (user_id, tmdb_movie_id),
+    -- 0.5 刻みのみ許可する
+    CONSTRAINT movie_ratings_rating_check CHECK (rating BETWEEN 0.5 AND 5.0 AND rating * 2 = FLOOR(rating * 2))
+);
+
+CREATE INDEX idx_movie_ratings_movie_updated
+    ON movie_ratings (tmdb_movie_id, updated_at DESC);
+
+CREATE INDEX idx_movie_ratings_user_updated
+    ON movie_ratings (user_id, updated_at DESC);
+
+-- +goose Down
+
+DROP TABLE IF EXISTS movie_ratings;
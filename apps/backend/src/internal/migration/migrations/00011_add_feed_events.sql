@@ -0,0 +1,33 @@
+-- +goose Up
+-- ================================================================
+-- ホームフィード用のアクティビティイベント
+-- 通知と同じイベント（映画追加・タグ作成・いいね・フォロー）を
+-- アクター単位で1件ずつ記録し、閲覧時にフォロー関係で絞り込む
+-- ================================================================
+
+CREATE TABLE feed_events (
+    id             UUID        NOT NULL DEFAULT gen_random_uuid(),
+    actor_user_id  UUID        NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    event_type     TEXT        NOT NULL,
+    tag_id         UUID                 REFERENCES tags(id) ON DELETE CASCADE,
+    tag_movie_id   UUID                 REFERENCES tag_movies(id) ON DELETE CASCADE,
+    target_user_id UUID                 REFERENCES users(id) ON DELETE CASCADE,
+    created_at     TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    CONSTRAINT feed_events_pkey PRIMARY KEY (id),
+    CONSTRAINT feed_events_event_type_check CHECK (event_type IN ('tag_movie_added', 'tag_created', 'tag_liked', 'tag_followed', 'user_followed'))
+);
+
+CREATE INDEX idx_feed_events_created
+    ON feed_events (created_at DESC, id DESC);
+
+CREATE INDEX idx_feed_events_actor_created
+    ON feed_events (actor_user_id, created_at DESC);
+
+CREATE INDEX idx_feed_events_tag_created
+    ON feed_events (tag_id, created_at DESC)
+    WHERE tag_id IS NOT NULL;
+
+-- +goose Down
+
+DROP TABLE IF EXISTS feed_events;
@@ -0,0 +1,31 @@
+-- +goose Up
+-- ================================================================
+-- 通知設定
+-- 通知の種類ごとの受け取り可否と、映画追加通知をミュートするタグ
+-- 行が存在しない種類は受け取る（既定値: 有効）
+-- ================================================================
+
+CREATE TABLE notification_preferences (
+    user_id           UUID        NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    notification_type TEXT        NOT NULL,
+    enabled           BOOLEAN     NOT NULL DEFAULT TRUE,
+    updated_at        TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    CONSTRAINT notification_preferences_pkey PRIMARY KEY (user_id, notification_type)
+);
+
+CREATE TABLE notification_muted_tags (
+    user_id    UUID        NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    tag_id     UUID        NOT NULL REFERENCES tags(id) ON DELETE CASCADE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    CONSTRAINT notification_muted_tags_pkey PRIMARY KEY (user_id, tag_id)
+);
+
+CREATE INDEX idx_notification_muted_tags_tag
+    ON notification_muted_tags (tag_id);
+
+-- +goose Down
+
+DROP TABLE IF EXISTS notification_muted_tags;
+DROP TABLE IF EXISTS notification_preferences;
@@ -0,0 +1,53 @@
+-- +goose Up
+-- ================================================================
+-- 通知のグルーピング
+-- 同じ受信者・種類・タグへの一定時間内のイベントを1件の通知にまとめる
+-- group_key が同じ通知は1件のみ（NULL の通知はまとめない）
+-- まとめたイベントのアクター・追加された映画は notification_events に記録する
+-- ================================================================
+
+ALTER TABLE notifications
+    ADD COLUMN group_key   TEXT,
+    ADD COLUMN event_count INTEGER     NOT NULL DEFAULT 1,
+    ADD COLUMN updated_at  TIMESTAMPTZ,
+    ADD CONSTRAINT notifications_event_count_check CHECK (event_count >= 1),
+    ADD CONSTRAINT uq_notifications_recipient_group UNIQUE (recipient_user_id, group_key);
+
+-- 既存の通知は最後のイベント日時を作成日時とする
+UPDATE notifications SET updated_at = created_at;
+
+ALTER TABLE notifications
+    ALTER COLUMN updated_at SET NOT NULL,
+    ALTER COLUMN updated_at SET DEFAULT NOW();
+
+CREATE INDEX idx_notifications_recipient_updated
+    ON notifications (recipient_user_id, updated_at DESC);
+
+CREATE TABLE notification_events (
+    id              UUID        NOT NULL DEFAULT gen_random_uuid(),
+    notification_id UUID        NOT NULL REFERENCES notifications(id) ON DELETE CASCADE,
+    actor_user_id   UUID        NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    tag_movie_id    UUID                 REFERENCES tag_movies(id) ON DELETE CASCADE,
+    created_at      TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    CONSTRAINT notification_events_pkey PRIMARY KEY (id)
+);
+
+CREATE INDEX idx_notification_events_notification_created
+    ON notification_events (notification_id, created_at DESC);
+
+CREATE INDEX idx_notification_events_actor
+    ON notification_events (actor_user_id);
+
+-- +goose Down
+
+DROP TABLE IF EXISTS notification_events;
+
+DROP INDEX IF EXISTS idx_notifications_recipient_updated;
+
+ALTER TABLE notifications
+    DROP CONSTRAINT IF EXISTS uq_notifications_recipient_group,
+    DROP CONSTRAINT IF EXISTS notifications_event_count_check,
+    DROP COLUMN IF EXISTS updated_at,
+    DROP COLUMN IF EXISTS event_count,
+    DROP COLUMN IF EXISTS group_key;
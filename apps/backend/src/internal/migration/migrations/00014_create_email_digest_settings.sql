@@ -0,0 +1,25 @@
+-- +goose Up
+-- ================================================================
+-- メールダイジェスト設定
+-- 未読通知とフォロー中タグに追加された映画をまとめたメールの配信設定
+-- 行が存在しないユーザーには配信しない（オプトイン）
+-- ================================================================
+
+CREATE TABLE email_digest_settings (
+    user_id      UUID        NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    frequency    TEXT        NOT NULL DEFAULT 'off',
+    last_sent_at TIMESTAMPTZ,
+    created_at   TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at   TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    CONSTRAINT email_digest_settings_pkey PRIMARY KEY (user_id),
+    CONSTRAINT email_digest_settings_frequency_check CHECK (frequency IN ('off', 'daily', 'weekly'))
+);
+
+CREATE INDEX idx_email_digest_settings_due
+    ON email_digest_settings (frequency, last_sent_at)
+    WHERE frequency <> 'off';
+
+-- +goose Down
+
+DROP TABLE IF EXISTS email_digest_settings;
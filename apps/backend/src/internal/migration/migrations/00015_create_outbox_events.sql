@@ -0,0 +1,38 @@
+-- +goose Up
+-- ================================================================
+-- トランザクショナルアウトボックス
+-- 状態変更と同じトランザクションでドメインイベントを書き込み、
+-- ディスパッチャー（ワーカー）がリトライしながら通知などに配信する
+-- ================================================================
+
+CREATE TABLE outbox_events (
+    id              UUID        NOT NULL DEFAULT gen_random_uuid(),
+    event_type      TEXT        NOT NULL,
+    payload         JSONB       NOT NULL DEFAULT '{}'::jsonb,
+    idempotency_key TEXT        NOT NULL,
+    status          TEXT        NOT NULL DEFAULT 'pending',
+    attempts        INT         NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    locked_until    TIMESTAMPTZ,
+    lock_token      UUID,
+    last_error      TEXT,
+    created_at      TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    processed_at    TIMESTAMPTZ,
+
+    CONSTRAINT outbox_events_pkey PRIMARY KEY (id),
+    CONSTRAINT outbox_events_status_check CHECK (status IN ('pending', 'done', 'dead'))
+);
+
+-- 未配信のイベントは同じキーで重複させない（配信済み・破棄済みになれば同じキーで再び積める）
+CREATE UNIQUE INDEX uq_outbox_events_pending_key
+    ON outbox_events (idempotency_key)
+    WHERE status = 'pending';
+
+-- ディスパッチャーが配信対象を取得するためのインデックス
+CREATE INDEX idx_outbox_events_due
+    ON outbox_events (next_attempt_at)
+    WHERE status = 'pending';
+
+-- +goose Down
+
+DROP TABLE IF EXISTS outbox_events;
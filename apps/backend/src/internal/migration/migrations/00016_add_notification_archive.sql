@@ -0,0 +1,23 @@
+-- +goose Up
+-- ================================================================
+-- 通知のアーカイブと保持期間
+-- アーカイブした通知は通知一覧・未読数から除外する（削除はしない）
+-- 保持期間を過ぎた既読通知・上限を超えた未読通知は削除ジョブで削除する
+-- ================================================================
+
+ALTER TABLE notifications
+    ADD COLUMN is_archived BOOLEAN     NOT NULL DEFAULT false,
+    ADD COLUMN archived_at TIMESTAMPTZ;
+
+-- 削除ジョブで保持期間を過ぎた既読通知を探すためのインデックス
+CREATE INDEX idx_notifications_read_updated
+    ON notifications (updated_at)
+    WHERE is_read = true;
+
+-- +goose Down
+
+DROP INDEX IF EXISTS idx_notifications_read_updated;
+
+ALTER TABLE notifications
+    DROP COLUMN IF EXISTS archived_at,
+    DROP COLUMN IF EXISTS is_archived;
@@ -0,0 +1,13 @@
+-- +goose Up
+-- ================================================================
+-- 通知の追加情報
+-- 通知の種類ごとに表示に必要な情報を保持する（tag_updated の変更前のタイトル・非公開化など）
+-- ================================================================
+
+ALTER TABLE notifications
+    ADD COLUMN details JSONB;
+
+-- +goose Down
+
+ALTER TABLE notifications
+    DROP COLUMN IF EXISTS details;
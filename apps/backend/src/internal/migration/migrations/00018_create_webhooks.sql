@@ -0,0 +1,55 @@
+-- +goose Up
+-- ================================================================
+-- Webhook（外部サービスへのイベント配信）
+-- タグオーナーはタグごと、ユーザーは自分のアカウント全体のイベントを
+-- 登録した URL に HMAC 署名付きの JSON で配信する
+-- ================================================================
+
+CREATE TABLE webhooks (
+    id         UUID        NOT NULL DEFAULT gen_random_uuid(),
+    user_id    UUID        NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    tag_id     UUID        REFERENCES tags(id) ON DELETE CASCADE, -- NULL の場合はアカウント全体
+    url        TEXT        NOT NULL,
+    secret     TEXT        NOT NULL,
+    events     JSONB       NOT NULL DEFAULT '[]'::jsonb,
+    is_active  BOOLEAN     NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    CONSTRAINT webhooks_pkey PRIMARY KEY (id)
+);
+
+CREATE INDEX idx_webhooks_user
+    ON webhooks (user_id, created_at DESC);
+
+CREATE INDEX idx_webhooks_tag
+    ON webhooks (tag_id)
+    WHERE tag_id IS NOT NULL;
+
+-- 配信ログ（リトライを含む配信の試行ごとに1行）
+CREATE TABLE webhook_deliveries (
+    id          UUID        NOT NULL DEFAULT gen_random_uuid(),
+    webhook_id  UUID        NOT NULL REFERENCES webhooks(id) ON DELETE CASCADE,
+    delivery_id UUID        NOT NULL, -- 同じ配信のリトライで共通のID（X-Cinetag-Delivery ヘッダー）
+    event_type  TEXT        NOT NULL,
+    attempt     INT         NOT NULL,
+    succeeded   BOOLEAN     NOT NULL,
+    status_code INT,
+    error       TEXT,
+    duration_ms INT         NOT NULL DEFAULT 0,
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    CONSTRAINT webhook_deliveries_pkey PRIMARY KEY (id)
+);
+
+CREATE INDEX idx_webhook_deliveries_webhook
+    ON webhook_deliveries (webhook_id, created_at DESC);
+
+-- 保持期間を過ぎた配信ログの削除ジョブ用
+CREATE INDEX idx_webhook_deliveries_created
+    ON webhook_deliveries (created_at);
+
+-- +goose Down
+
+DROP TABLE IF EXISTS webhook_deliveries;
+DROP TABLE IF EXISTS webhooks;
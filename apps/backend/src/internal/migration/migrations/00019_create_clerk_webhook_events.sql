@@ -0,0 +1,22 @@
+-- +goose Up
+-- ================================================================
+-- 処理済みの Clerk Webhook のメッセージ
+-- Svix は同じメッセージを再送することがあるため、svix-id を記録して
+-- 同じメッセージを2回処理しないようにする
+-- ================================================================
+
+CREATE TABLE clerk_webhook_events (
+    svix_id     TEXT        NOT NULL,
+    event_type  TEXT        NOT NULL,
+    received_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    CONSTRAINT clerk_webhook_events_pkey PRIMARY KEY (svix_id)
+);
+
+-- 保持期間を過ぎた記録の削除ジョブ用
+CREATE INDEX idx_clerk_webhook_events_received
+    ON clerk_webhook_events (received_at);
+
+-- +goose Down
+
+DROP TABLE IF EXISTS clerk_webhook_events;
@@ -0,0 +1,31 @@
+-- +goose Up
+-- ================================================================
+-- Clerk からのユーザー情報の同期
+-- users に最後に Clerk から同期した表示名・ユーザー名を記録し、
+-- アプリ上で変更していない場合のみ Clerk の変更を反映する。
+-- また、ユーザーごとに最新の Webhook のペイロードを保存し、
+-- 順序が入れ替わって届いた古いイベントの無視と再同期に使う
+-- ================================================================
+
+ALTER TABLE users
+    ADD COLUMN clerk_display_name TEXT, -- 最後に Clerk の氏名から決めた表示名
+    ADD COLUMN clerk_username     TEXT; -- 最後に Clerk から同期したユーザー名
+
+CREATE TABLE clerk_user_payloads (
+    clerk_user_id    TEXT        NOT NULL,
+    svix_id          TEXT        NOT NULL,
+    event_type       TEXT        NOT NULL,
+    payload          JSONB       NOT NULL,
+    clerk_updated_at BIGINT      NOT NULL DEFAULT 0, -- ペイロードの updated_at（UNIX ミリ秒）
+    received_at      TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    CONSTRAINT clerk_user_payloads_pkey PRIMARY KEY (clerk_user_id)
+);
+
+-- +goose Down
+
+DROP TABLE IF EXISTS clerk_user_payloads;
+
+ALTER TABLE users
+    DROP COLUMN IF EXISTS clerk_username,
+    DROP COLUMN IF EXISTS clerk_display_name;
@@ -0,0 +1,27 @@
+-- +goose Up
+-- ================================================================
+-- パーソナルアクセストークン（スクリプト・ボットからの API 呼び出し）
+-- トークン本体は保存せず、SHA-256 のハッシュで照合する
+-- ================================================================
+
+CREATE TABLE personal_access_tokens (
+    id           UUID         NOT NULL DEFAULT gen_random_uuid(),
+    user_id      UUID         NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    name         VARCHAR(100) NOT NULL,
+    token_hash   TEXT         NOT NULL,
+    token_prefix TEXT         NOT NULL, -- 一覧で見分けるためのトークンの先頭部分（例: cnt_1a2b3c4d）
+    scopes       JSONB        NOT NULL DEFAULT '[]'::jsonb,
+    expires_at   TIMESTAMPTZ  NOT NULL,
+    last_used_at TIMESTAMPTZ,
+    created_at   TIMESTAMPTZ  NOT NULL DEFAULT NOW(),
+
+    CONSTRAINT personal_access_tokens_pkey PRIMARY KEY (id),
+    CONSTRAINT personal_access_tokens_token_hash_key UNIQUE (token_hash)
+);
+
+CREATE INDEX idx_personal_access_tokens_user
+    ON personal_access_tokens (user_id, created_at DESC);
+
+-- +goose Down
+
+DROP TABLE IF EXISTS personal_access_tokens;
@@ -0,0 +1,32 @@
+-- +goose Up
+-- ================================================================
+-- 管理者ロールとモデレーション
+-- users にロールを追加し、admin のユーザーのみ管理者用 API を呼び出せるようにする。
+-- 管理者による利用停止は退会状態（deleted_at）として扱い、完全削除の予定は設定しない。
+-- 管理者が非表示にしたタグは非公開にし、持ち主が公開に戻せないよう hidden_at を記録する
+-- ================================================================
+
+ALTER TABLE users
+    ADD COLUMN role         TEXT        NOT NULL DEFAULT 'user',
+    ADD COLUMN suspended_at TIMESTAMPTZ, -- 管理者による利用停止日時（利用停止中は本人が退会を取り消せない）
+    ADD CONSTRAINT users_role_check CHECK (role IN ('user', 'admin'));
+
+ALTER TABLE tags
+    ADD COLUMN hidden_at TIMESTAMPTZ; -- 管理者による非表示日時
+
+-- 管理者の一覧で利用停止中のユーザーを探すためのインデックス
+CREATE INDEX idx_users_suspended
+    ON users (suspended_at)
+    WHERE suspended_at IS NOT NULL;
+
+-- +goose Down
+
+DROP INDEX IF EXISTS idx_users_suspended;
+
+ALTER TABLE tags
+    DROP COLUMN IF EXISTS hidden_at;
+
+ALTER TABLE users
+    DROP CONSTRAINT IF EXISTS users_role_check,
+    DROP COLUMN IF EXISTS suspended_at,
+    DROP COLUMN IF EXISTS role;
@@ -0,0 +1,28 @@
+-- +goose Up
+-- ================================================================
+-- ホームフィードのインデックス見直し
+-- フィードはフォロー中ユーザー・フォロー中タグのイベントをそれぞれ取得してまとめるため、
+-- アクター・タグごとのインデックスにカーソル（created_at, id）の並びを含める
+-- ================================================================
+
+DROP INDEX IF EXISTS idx_feed_events_actor_created;
+DROP INDEX IF EXISTS idx_feed_events_tag_created;
+
+CREATE INDEX idx_feed_events_actor_created
+    ON feed_events (actor_user_id, created_at DESC, id DESC);
+
+CREATE INDEX idx_feed_events_tag_created
+    ON feed_events (tag_id, created_at DESC, id DESC)
+    WHERE tag_id IS NOT NULL;
+
+-- +goose Down
+
+DROP INDEX IF EXISTS idx_feed_events_actor_created;
+DROP INDEX IF EXISTS idx_feed_events_tag_created;
+
+CREATE INDEX idx_feed_events_actor_created
+    ON feed_events (actor_user_id, created_at DESC);
+
+CREATE INDEX idx_feed_events_tag_created
+    ON feed_events (tag_id, created_at DESC)
+    WHERE tag_id IS NOT NULL;
@@ -0,0 +1,31 @@
+-- +goose Up
+-- ================================================================
+-- 通知のグルーピングを最後のイベントからの時間幅で行う
+-- 時刻の区切りごとの group_key をやめ、同じ受信者・group_key の通知のうち
+-- 最後のイベントが時間幅内にあるものにまとめる（同じ group_key の通知は複数になる）
+-- ================================================================
+
+ALTER TABLE notifications
+    DROP CONSTRAINT IF EXISTS uq_notifications_recipient_group;
+
+-- 既存の group_key から時刻の区切りを取り除く
+UPDATE notifications
+SET group_key = regexp_replace(group_key, ':[0-9]+$', '')
+WHERE group_key IS NOT NULL;
+
+-- まとめ先の通知を探すためのインデックス
+CREATE INDEX idx_notifications_recipient_group_updated
+    ON notifications (recipient_user_id, group_key, updated_at DESC)
+    WHERE group_key IS NOT NULL;
+
+-- +goose Down
+
+DROP INDEX IF EXISTS idx_notifications_recipient_group_updated;
+
+-- 同じ group_key の通知が複数あると一意制約を戻せないため、通知ごとに異なるキーにする
+UPDATE notifications
+SET group_key = group_key || ':' || id
+WHERE group_key IS NOT NULL;
+
+ALTER TABLE notifications
+    ADD CONSTRAINT uq_notifications_recipient_group UNIQUE (recipient_user_id, group_key);
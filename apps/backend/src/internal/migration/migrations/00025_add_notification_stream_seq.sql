@@ -0,0 +1,20 @@
+-- +goose Up
+-- ================================================================
+-- 通知ストリームのカーソル
+-- 通知の作成・イベントの追加のたびに採番する連番を持たせ、ストリームはこの順に配信する。
+-- アプリで設定する updated_at はコミット順と一致しないため、カーソルには使わない
+-- （同じ受信者への書き込みはアドバイザリロックで直列化し、採番順とコミット順を揃える）
+-- ================================================================
+
+ALTER TABLE notifications
+    ADD COLUMN stream_seq BIGSERIAL;
+
+CREATE INDEX idx_notifications_recipient_stream_seq
+    ON notifications (recipient_user_id, stream_seq);
+
+-- +goose Down
+
+DROP INDEX IF EXISTS idx_notifications_recipient_stream_seq;
+
+ALTER TABLE notifications
+    DROP COLUMN IF EXISTS stream_seq;
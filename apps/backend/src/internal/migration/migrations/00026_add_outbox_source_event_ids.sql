@@ -0,0 +1,71 @@
+-- +goose Up
+-- ================================================================
+-- アウトボックスの再配信の冪等化
+-- 通知・フィードイベント・Webhook の配信イベントに元のアウトボックスイベントの ID を持たせ、
+-- 同じイベントが再配信されても重複して作成しないようにする。
+-- アウトボックスのイベントは配信済みになってから削除されるため、外部キーは張らない
+-- ================================================================
+
+ALTER TABLE notifications
+    ADD COLUMN source_event_id UUID;
+
+CREATE UNIQUE INDEX uq_notifications_recipient_source_event
+    ON notifications (recipient_user_id, source_event_id)
+    WHERE source_event_id IS NOT NULL;
+
+ALTER TABLE notification_events
+    ADD COLUMN source_event_id UUID;
+
+CREATE UNIQUE INDEX uq_notification_events_notification_source_event
+    ON notification_events (notification_id, source_event_id)
+    WHERE source_event_id IS NOT NULL;
+
+CREATE INDEX idx_notification_events_source_event
+    ON notification_events (source_event_id)
+    WHERE source_event_id IS NOT NULL;
+
+ALTER TABLE feed_events
+    ADD COLUMN source_event_id UUID;
+
+CREATE UNIQUE INDEX uq_feed_events_source_event
+    ON feed_events (source_event_id)
+    WHERE source_event_id IS NOT NULL;
+
+-- Webhook の配信イベント（webhook.deliver）は、振り分け元のイベントと配信先ごとに1件とする
+ALTER TABLE outbox_events
+    ADD COLUMN source_event_id UUID;
+
+CREATE UNIQUE INDEX uq_outbox_events_source_event
+    ON outbox_events (source_event_id, idempotency_key)
+    WHERE source_event_id IS NOT NULL;
+
+-- 同じイベントに複数の配信処理がある場合に、成功した配信処理の名前を記録し、リトライでは再実行しない
+ALTER TABLE outbox_events
+    ADD COLUMN completed_handlers JSONB NOT NULL DEFAULT '[]';
+
+-- +goose Down
+
+ALTER TABLE outbox_events
+    DROP COLUMN IF EXISTS completed_handlers;
+
+DROP INDEX IF EXISTS uq_outbox_events_source_event;
+
+ALTER TABLE outbox_events
+    DROP COLUMN IF EXISTS source_event_id;
+
+DROP INDEX IF EXISTS uq_feed_events_source_event;
+
+ALTER TABLE feed_events
+    DROP COLUMN IF EXISTS source_event_id;
+
+DROP INDEX IF EXISTS idx_notification_events_source_event;
+
+DROP INDEX IF EXISTS uq_notification_events_notification_source_event;
+
+ALTER TABLE notification_events
+    DROP COLUMN IF EXISTS source_event_id;
+
+DROP INDEX IF EXISTS uq_notifications_recipient_source_event;
+
+ALTER TABLE notifications
+    DROP COLUMN IF EXISTS source_event_id;
@@ -0,0 +1,29 @@
+-- +goose Up
+-- ================================================================
+-- Clerk Webhook のメッセージの処理状態
+-- 受信時に処理中（processing）として期限付きで確保し、ユーザー情報の同期と同じトランザクションで
+-- 処理済み（processed）にする。処理中に再送されたメッセージは成功とせず、
+-- 期限を過ぎた処理中のメッセージは再送で処理し直せるようにする
+-- ================================================================
+
+-- 既存の記録は処理済みとして扱う
+ALTER TABLE clerk_webhook_events
+    ADD COLUMN status       TEXT        NOT NULL DEFAULT 'processed',
+    ADD COLUMN lock_token   UUID,
+    ADD COLUMN locked_until TIMESTAMPTZ,
+    ADD COLUMN processed_at TIMESTAMPTZ;
+
+ALTER TABLE clerk_webhook_events
+    ADD CONSTRAINT clerk_webhook_events_status_check
+    CHECK (status IN ('processing', 'processed'));
+
+-- +goose Down
+
+ALTER TABLE clerk_webhook_events
+    DROP CONSTRAINT IF EXISTS clerk_webhook_events_status_check;
+
+ALTER TABLE clerk_webhook_events
+    DROP COLUMN IF EXISTS processed_at,
+    DROP COLUMN IF EXISTS locked_until,
+    DROP COLUMN IF EXISTS lock_token,
+    DROP COLUMN IF EXISTS status;
@@ -0,0 +1,41 @@
+-- +goose Up
+-- ================================================================
+-- 管理者による操作の監査ログとタグの非表示前の公開範囲
+-- ロール変更・利用停止・利用停止の解除・タグの非表示/再表示を、操作した管理者とともに記録する。
+-- 管理者が非表示にしたタグは非表示にする前の公開範囲を保持し、再表示のときにその公開範囲へ戻す。
+-- 非表示の間に持ち主が非公開にした場合は、保持している公開範囲を非公開に更新する
+-- ================================================================
+
+ALTER TABLE tags
+    ADD COLUMN public_before_hidden BOOLEAN; -- 非表示にする前の公開範囲（非表示の間のみ設定する）
+
+-- 既存の非表示タグは公開タグのみを非表示にしていたため、公開に戻す
+UPDATE tags
+SET public_before_hidden = TRUE
+WHERE hidden_at IS NOT NULL;
+
+CREATE TABLE admin_audit_logs (
+    id            UUID        PRIMARY KEY DEFAULT gen_random_uuid(),
+    actor_user_id UUID        REFERENCES users (id) ON DELETE SET NULL, -- 操作した管理者
+    action        TEXT        NOT NULL,
+    target_type   TEXT        NOT NULL, -- 操作対象の種類（user / tag）
+    target_id     UUID        NOT NULL,
+    details       JSONB       NOT NULL DEFAULT '{}', -- 変更前後の値など
+    created_at    TIMESTAMPTZ NOT NULL DEFAULT now(),
+    CONSTRAINT admin_audit_logs_target_type_check CHECK (target_type IN ('user', 'tag'))
+);
+
+-- 操作対象ごとに履歴を新しい順に探すためのインデックス
+CREATE INDEX idx_admin_audit_logs_target
+    ON admin_audit_logs (target_type, target_id, created_at DESC);
+
+-- 管理者ごとに操作を新しい順に探すためのインデックス
+CREATE INDEX idx_admin_audit_logs_actor
+    ON admin_audit_logs (actor_user_id, created_at DESC);
+
+-- +goose Down
+
+DROP TABLE IF EXISTS admin_audit_logs;
+
+ALTER TABLE tags
+    DROP COLUMN IF EXISTS public_before_hidden;
@@ -0,0 +1,16 @@
+-- +goose Up
+-- ================================================================
+-- メールダイジェストの送信失敗時の再試行
+-- 送信に失敗したユーザーは連続失敗回数に応じて次の送信を遅らせ、
+-- 失敗し続けるユーザーが毎回の配信ジョブで先頭に残り、他のユーザーの配信を妨げないようにする
+-- ================================================================
+
+ALTER TABLE email_digest_settings
+    ADD COLUMN failed_attempts INTEGER     NOT NULL DEFAULT 0, -- 連続して送信に失敗した回数（送信できたら 0 に戻す）
+    ADD COLUMN next_attempt_at TIMESTAMPTZ;                    -- 送信に失敗した後、次に送信を試みる日時
+
+-- +goose Down
+
+ALTER TABLE email_digest_settings
+    DROP COLUMN IF EXISTS next_attempt_at,
+    DROP COLUMN IF EXISTS failed_attempts;
@@ -0,0 +1,26 @@
+-- +goose Up
+-- ================================================================
+-- 個人データエクスポートの未完了要求を1ユーザー1件に制限する
+-- 同時に要求された場合に未完了のエクスポートが重複して作成されないようにする
+-- ================================================================
+
+-- 既に重複している未完了のエクスポートは、最新の1件を残して失敗にする
+UPDATE user_data_exports AS ude
+SET status = 'failed',
+    error_message = 'superseded by a newer export'
+WHERE ude.status IN ('pending', 'processing')
+  AND EXISTS (
+    SELECT 1
+    FROM user_data_exports AS newer
+    WHERE newer.user_id = ude.user_id
+      AND newer.status IN ('pending', 'processing')
+      AND (newer.created_at, newer.id) > (ude.created_at, ude.id)
+  );
+
+CREATE UNIQUE INDEX idx_user_data_exports_user_in_progress
+    ON user_data_exports (user_id)
+    WHERE status IN ('pending', 'processing');
+
+-- +goose Down
+
+DROP INDEX IF EXISTS idx_user_data_exports_user_in_progress;
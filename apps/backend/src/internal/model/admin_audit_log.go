@@ -0,0 +1,39 @@
+package model
+
+import (
+	"time"
+
+	"gorm.io/datatypes"
+)
+
+// 管理者による操作の種類。
+const (
+	AdminAuditActionUserRoleUpdated = "user.role_updated" // ユーザーのロールを変更した
+	AdminAuditActionUserSuspended   = "user.suspended"    // ユーザーを利用停止にした
+	AdminAuditActionUserRestored    = "user.restored"     // ユーザーの利用停止を解除した
+	AdminAuditActionTagHidden       = "tag.hidden"        // タグを非表示にした
+	AdminAuditActionTagUnhidden     = "tag.unhidden"      // タグを再表示した
+)
+
+// 管理者による操作の対象の種類。
+const (
+	AdminAuditTargetUser = "user"
+	AdminAuditTargetTag  = "tag"
+)
+
+// AdminAuditLog は管理者による操作の監査ログを表します。
+// Details には変更前後の値など、操作の種類に応じた内容を記録します。
+type AdminAuditLog struct {
+	ID          string         `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
+	ActorUserID *string        `gorm:"type:uuid;column:actor_user_id" json:"actor_user_id"` // 操作した管理者（ユーザーが削除された場合は nil）
+	Action      string         `gorm:"type:text;not null;column:action" json:"action"`
+	TargetType  string         `gorm:"type:text;not null;column:target_type" json:"target_type"`
+	TargetID    string         `gorm:"type:uuid;not null;column:target_id" json:"target_id"`
+	Details     datatypes.JSON `gorm:"type:jsonb;not null;default:'{}';column:details" json:"details"`
+	CreatedAt   time.Time      `gorm:"type:timestamptz;not null;default:now();column:created_at" json:"created_at"`
+}
+
+// TableName は対応するテーブル名を返します。
+func (AdminAuditLog) TableName() string {
+	return "admin_audit_logs"
+}
@@ -0,0 +1,47 @@
+package model
+
+import (
+	"time"
+
+	"gorm.io/datatypes"
+)
+
+// Clerk Webhook のメッセージの処理状態。
+const (
+	ClerkWebhookEventStatusProcessing = "processing" // 処理中（LockedUntil まで他のリクエストは処理しない）
+	ClerkWebhookEventStatusProcessed  = "processed"  // 処理済み
+)
+
+// ClerkWebhookEvent は処理済み（または処理中）の Clerk Webhook のメッセージを表します。
+// 同じ svix-id のメッセージが再送された場合に、2回処理しないために使います。
+// 処理中のまま LockedUntil を過ぎたメッセージは、再送されたリクエストが処理し直します。
+type ClerkWebhookEvent struct {
+	SvixID      string     `gorm:"type:text;primaryKey;column:svix_id" json:"svix_id"`
+	EventType   string     `gorm:"type:text;not null;column:event_type" json:"event_type"`
+	Status      string     `gorm:"type:text;not null;default:processed;column:status" json:"status"`
+	LockToken   *string    `gorm:"type:uuid;column:lock_token" json:"-"`
+	LockedUntil *time.Time `gorm:"type:timestamptz;column:locked_until" json:"locked_until"`
+	ReceivedAt  time.Time  `gorm:"type:timestamptz;not null;default:now();column:received_at" json:"received_at"`
+	ProcessedAt *time.Time `gorm:"type:timestamptz;column:processed_at" json:"processed_at"`
+}
+
+// TableName は対応するテーブル名を返します。
+func (ClerkWebhookEvent) TableName() string {
+	return "clerk_webhook_events"
+}
+
+// ClerkUserPayload は Clerk ユーザーごとに最後に受け取った user.created / user.updated の data 部分を表します。
+// 順序が入れ替わって届いた古いイベントの判定と、保存したペイロードからの再同期に使います。
+type ClerkUserPayload struct {
+	ClerkUserID    string         `gorm:"type:text;primaryKey;column:clerk_user_id" json:"clerk_user_id"`
+	SvixID         string         `gorm:"type:text;not null;column:svix_id" json:"svix_id"`
+	EventType      string         `gorm:"type:text;not null;column:event_type" json:"event_type"`
+	Payload        datatypes.JSON `gorm:"type:jsonb;not null;column:payload" json:"payload"`
+	ClerkUpdatedAt int64          `gorm:"not null;default:0;column:clerk_updated_at" json:"clerk_updated_at"` // UNIX ミリ秒
+	ReceivedAt     time.Time      `gorm:"type:timestamptz;not null;default:now();column:received_at" json:"received_at"`
+}
+
+// TableName は対応するテーブル名を返します。
+func (ClerkUserPayload) TableName() string {
+	return "clerk_user_payloads"
+}
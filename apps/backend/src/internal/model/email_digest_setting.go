@@ -0,0 +1,29 @@
+package model
+
+import "time"
+
+// メールダイジェストの配信頻度
+const (
+	EmailDigestFrequencyOff    = "off"
+	EmailDigestFrequencyDaily  = "daily"
+	EmailDigestFrequencyWeekly = "weekly"
+)
+
+// EmailDigestSetting はメールダイジェストの配信設定を表します。
+// 行が存在しないユーザーには配信しません（オプトイン）。
+type EmailDigestSetting struct {
+	UserID     string     `gorm:"type:uuid;primaryKey;column:user_id" json:"user_id"`
+	Frequency  string     `gorm:"type:text;not null;default:'off';column:frequency" json:"frequency"`
+	LastSentAt *time.Time `gorm:"type:timestamptz;column:last_sent_at" json:"last_sent_at,omitempty"`
+	CreatedAt  time.Time  `gorm:"type:timestamptz;not null;default:now();column:created_at" json:"created_at"`
+	UpdatedAt  time.Time  `gorm:"type:timestamptz;not null;default:now();column:updated_at" json:"updated_at"`
+
+	// 送信に失敗した場合の再試行の状態（送信できたらリセットする）
+	FailedAttempts int        `gorm:"not null;default:0;column:failed_attempts" json:"-"`
+	NextAttemptAt  *time.Time `gorm:"type:timestamptz;column:next_attempt_at" json:"-"`
+}
+
+// TableName は対応するテーブル名を返します。
+func (EmailDigestSetting) TableName() string {
+	return "email_digest_settings"
+}
@@ -0,0 +1,30 @@
+package model
+
+import "time"
+
+// フィードイベントの種類。
+const (
+	FeedEventTypeTagMovieAdded = "tag_movie_added" // タグに映画が追加された
+	FeedEventTypeTagCreated    = "tag_created"     // 公開タグが作成された
+	FeedEventTypeTagLiked      = "tag_liked"       // タグにいいねした
+	FeedEventTypeTagFollowed   = "tag_followed"    // タグをフォローした
+	FeedEventTypeUserFollowed  = "user_followed"   // ユーザーをフォローした
+)
+
+// FeedEvent はホームフィードに表示するアクティビティを表します。
+// TagID / TagMovieID / TargetUserID はイベントの種類に応じて設定されます。
+type FeedEvent struct {
+	ID            string    `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
+	ActorUserID   string    `gorm:"type:uuid;not null;column:actor_user_id" json:"actor_user_id"`
+	EventType     string    `gorm:"type:text;not null;column:event_type" json:"event_type"`
+	TagID         *string   `gorm:"type:uuid;column:tag_id" json:"tag_id,omitempty"`
+	TagMovieID    *string   `gorm:"type:uuid;column:tag_movie_id" json:"tag_movie_id,omitempty"`
+	TargetUserID  *string   `gorm:"type:uuid;column:target_user_id" json:"target_user_id,omitempty"`
+	SourceEventID *string   `gorm:"type:uuid;column:source_event_id" json:"-"` // イベントの元になったアウトボックスイベント（再配信で重複させない）
+	CreatedAt     time.Time `gorm:"type:timestamptz;not null;default:now();column:created_at" json:"created_at"`
+}
+
+// TableName は対応するテーブル名を返します。
+func (FeedEvent) TableName() string {
+	return "feed_events"
+}
@@ -0,0 +1,21 @@
+package model
+
+import "time"
+
+// MovieRating はユーザーごとの映画の評価（0.5〜5.0、0.5 刻み）と短いレビューを表します。
+// 1ユーザーにつき1作品1件です。
+type MovieRating struct {
+	ID          string    `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
+	UserID      string    `gorm:"type:uuid;not null;column:user_id" json:"user_id"`
+	TmdbMovieID int       `gorm:"type:integer;not null;column:tmdb_movie_id" json:"tmdb_movie_id"`
+	Rating      float64   `gorm:"type:numeric(2,1);not null" json:"rating"`
+	Review      *string   `gorm:"type:text" json:"review,omitempty"`
+	IsSpoiler   bool      `gorm:"not null;default:false;column:is_spoiler" json:"is_spoiler"`
+	CreatedAt   time.Time `gorm:"type:timestamptz;not null;default:now();column:created_at" json:"created_at"`
+	UpdatedAt   time.Time `gorm:"type:timestamptz;not null;default:now();column:updated_at" json:"updated_at"`
+}
+
+// TableName は対応するテーブル名を返します。
+func (MovieRating) TableName() string {
+	return "movie_ratings"
+}
@@ -1,6 +1,10 @@
 package model
 
-import "time"
+import (
+	"time"
+
+	"gorm.io/datatypes"
+)
 
 // 通知タイプ定数
 const (
@@ -8,22 +12,56 @@ const (
 	NotificationTypeTagFollowed             = "tag_followed"
 	NotificationTypeUserFollowed            = "user_followed"
 	NotificationTypeFollowingUserCreatedTag = "following_user_created_tag"
+	NotificationTypeFollowRequested         = "follow_requested"
+	NotificationTypeFollowRequestApproved   = "follow_request_approved"
+	NotificationTypeFollowRequestDenied     = "follow_request_denied"
+	NotificationTypeTagLiked                = "tag_liked"
+	NotificationTypeTagUpdated              = "tag_updated"
+	NotificationTypeMentionedInNote         = "mentioned_in_note"
 )
 
 // Notification はアプリ内通知を表すドメインモデルです。
 type Notification struct {
-	ID               string     `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
-	RecipientUserID  string     `gorm:"type:uuid;not null;column:recipient_user_id" json:"recipient_user_id"`
-	ActorUserID      *string    `gorm:"type:uuid;column:actor_user_id" json:"actor_user_id"`
-	NotificationType string     `gorm:"type:text;not null;column:notification_type" json:"notification_type"`
-	TagID            *string    `gorm:"type:uuid;column:tag_id" json:"tag_id"`
-	TagMovieID       *string    `gorm:"type:uuid;column:tag_movie_id" json:"tag_movie_id"`
-	IsRead           bool       `gorm:"not null;default:false;column:is_read" json:"is_read"`
-	ReadAt           *time.Time `gorm:"type:timestamptz;column:read_at" json:"read_at"`
-	CreatedAt        time.Time  `gorm:"type:timestamptz;not null;default:now();column:created_at" json:"created_at"`
+	ID               string         `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
+	RecipientUserID  string         `gorm:"type:uuid;not null;column:recipient_user_id" json:"recipient_user_id"`
+	ActorUserID      *string        `gorm:"type:uuid;column:actor_user_id" json:"actor_user_id"`
+	NotificationType string         `gorm:"type:text;not null;column:notification_type" json:"notification_type"`
+	TagID            *string        `gorm:"type:uuid;column:tag_id" json:"tag_id"`
+	TagMovieID       *string        `gorm:"type:uuid;column:tag_movie_id" json:"tag_movie_id"`
+	IsRead           bool           `gorm:"not null;default:false;column:is_read" json:"is_read"`
+	ReadAt           *time.Time     `gorm:"type:timestamptz;column:read_at" json:"read_at"`
+	IsArchived       bool           `gorm:"not null;default:false;column:is_archived" json:"is_archived"` // 通知一覧・未読数から除外する
+	ArchivedAt       *time.Time     `gorm:"type:timestamptz;column:archived_at" json:"archived_at"`
+	GroupKey         *string        `gorm:"type:text;column:group_key" json:"group_key"`              // 同じキーのイベントを1件にまとめる（NULL はまとめない）
+	EventCount       int            `gorm:"not null;default:1;column:event_count" json:"event_count"` // まとめたイベント数
+	Details          datatypes.JSON `gorm:"type:jsonb;column:details" json:"details"`                 // 種類ごとの追加情報（tag_updated の変更内容など）
+	SourceEventID    *string        `gorm:"type:uuid;column:source_event_id" json:"-"`                // 通知の元になったアウトボックスイベント（再配信で重複させない）
+	CreatedAt        time.Time      `gorm:"type:timestamptz;not null;default:now();column:created_at" json:"created_at"`
+	UpdatedAt        time.Time      `gorm:"type:timestamptz;not null;default:now();autoUpdateTime:false;column:updated_at" json:"updated_at"` // 最後のイベント日時
+}
+
+// NotificationTagUpdateDetails は tag_updated 通知の変更内容を表します（notifications.details に保存）。
+type NotificationTagUpdateDetails struct {
+	PreviousTitle *string `json:"previous_title,omitempty"` // 名前が変わった場合の変更前のタイトル
+	MadePrivate   bool    `json:"made_private,omitempty"`   // 非公開になった場合 true
 }
 
 // TableName は対応するテーブル名を返します。
 func (Notification) TableName() string {
 	return "notifications"
 }
+
+// NotificationEvent はまとめた通知に含まれる個々のイベントを表します。
+type NotificationEvent struct {
+	ID             string    `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
+	NotificationID string    `gorm:"type:uuid;not null;column:notification_id" json:"notification_id"`
+	ActorUserID    string    `gorm:"type:uuid;not null;column:actor_user_id" json:"actor_user_id"`
+	TagMovieID     *string   `gorm:"type:uuid;column:tag_movie_id" json:"tag_movie_id"`
+	SourceEventID  *string   `gorm:"type:uuid;column:source_event_id" json:"-"` // イベントの元になったアウトボックスイベント（再配信で重複させない）
+	CreatedAt      time.Time `gorm:"type:timestamptz;not null;default:now();column:created_at" json:"created_at"`
+}
+
+// TableName は対応するテーブル名を返します。
+func (NotificationEvent) TableName() string {
+	return "notification_events"
+}
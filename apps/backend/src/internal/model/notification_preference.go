@@ -0,0 +1,29 @@
+package model
+
+import "time"
+
+// NotificationPreference は通知の種類ごとの受け取り設定を表します。
+// 行が存在しない種類は受け取る（Enabled = true）ものとして扱います。
+type NotificationPreference struct {
+	UserID           string    `gorm:"type:uuid;primaryKey;column:user_id" json:"user_id"`
+	NotificationType string    `gorm:"type:text;primaryKey;column:notification_type" json:"notification_type"`
+	Enabled          bool      `gorm:"not null;default:true;column:enabled" json:"enabled"`
+	UpdatedAt        time.Time `gorm:"type:timestamptz;not null;default:now();column:updated_at" json:"updated_at"`
+}
+
+// TableName は対応するテーブル名を返します。
+func (NotificationPreference) TableName() string {
+	return "notification_preferences"
+}
+
+// NotificationMutedTag は映画追加通知をミュートしたタグを表します。
+type NotificationMutedTag struct {
+	UserID    string    `gorm:"type:uuid;primaryKey;column:user_id" json:"user_id"`
+	TagID     string    `gorm:"type:uuid;primaryKey;column:tag_id" json:"tag_id"`
+	CreatedAt time.Time `gorm:"type:timestamptz;not null;default:now();column:created_at" json:"created_at"`
+}
+
+// TableName は対応するテーブル名を返します。
+func (NotificationMutedTag) TableName() string {
+	return "notification_muted_tags"
+}
@@ -0,0 +1,77 @@
+package model
+
+import (
+	"time"
+
+	"gorm.io/datatypes"
+)
+
+// アウトボックスイベントの種類。
+const (
+	OutboxEventTypeTagCreated              = "tag.created"                  // 公開タグが作成された
+	OutboxEventTypeTagMovieAdded           = "tag.movie_added"              // タグに映画が追加された
+	OutboxEventTypeTagMovieRemoved         = "tag.movie_removed"            // タグから映画が削除された（Webhook のみ）
+	OutboxEventTypeTagFollowed             = "tag.followed"                 // タグがフォローされた
+	OutboxEventTypeTagLiked                = "tag.liked"                    // タグがいいねされた
+	OutboxEventTypeTagUpdated              = "tag.updated"                  // タグの名前が変わった・非公開になった
+	OutboxEventTypeNoteMentioned           = "tag.note_mentioned"           // 映画のメモでユーザーがメンションされた
+	OutboxEventTypeUserFollowed            = "user.followed"                // ユーザーがフォローされた
+	OutboxEventTypeFollowRequested         = "user.follow_requested"        // フォローリクエストが送信された
+	OutboxEventTypeFollowRequestApproved   = "user.follow_request_approved" // フォローリクエストが承認された
+	OutboxEventTypeFollowRequestDenied     = "user.follow_request_denied"   // フォローリクエストが拒否された
+	OutboxEventTypeWebhookDelivery         = "webhook.deliver"              // Webhook の配信先1件への配信
+	OutboxEventTypeUserDataExportRequested = "user.data_export_requested"   // 個人データのエクスポートが要求された
+)
+
+// アウトボックスイベントの処理状態。
+const (
+	OutboxStatusPending = "pending" // 配信待ち（リトライ待ちを含む）
+	OutboxStatusDone    = "done"    // 配信済み
+	OutboxStatusDead    = "dead"    // リトライ上限に達したため破棄した
+)
+
+// OutboxEvent は状態変更と同じトランザクションで書き込むドメインイベントを表します。
+// ディスパッチャーが LockedUntil までの間イベントを確保し、配信に成功すれば done、失敗すれば NextAttemptAt に再試行します。
+type OutboxEvent struct {
+	ID             string         `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
+	EventType      string         `gorm:"type:text;not null;column:event_type" json:"event_type"`
+	Payload        datatypes.JSON `gorm:"type:jsonb;not null;column:payload" json:"payload"`
+	IdempotencyKey string         `gorm:"type:text;not null;column:idempotency_key" json:"idempotency_key"` // 配信待ちの間は同じキーのイベントを重複させない
+	SourceEventID  *string        `gorm:"type:uuid;column:source_event_id" json:"source_event_id"`          // 振り分け元のイベント。同じ振り分け元・キーのイベントは配信後も重複させない
+	Status         string         `gorm:"type:text;not null;default:pending;column:status" json:"status"`
+	Attempts       int            `gorm:"not null;default:0;column:attempts" json:"attempts"`
+	NextAttemptAt  time.Time      `gorm:"type:timestamptz;not null;default:now();column:next_attempt_at" json:"next_attempt_at"`
+	LockedUntil    *time.Time     `gorm:"type:timestamptz;column:locked_until" json:"locked_until"`
+	LockToken      *string        `gorm:"type:uuid;column:lock_token" json:"-"`
+	LastError      *string        `gorm:"type:text;column:last_error" json:"last_error"`
+	// 複数の配信処理があるイベントで、成功した配信処理の名前（リトライでは再実行しない）
+	CompletedHandlers datatypes.JSONSlice[string] `gorm:"type:jsonb;not null;default:'[]';column:completed_handlers" json:"completed_handlers"`
+	CreatedAt         time.Time                   `gorm:"type:timestamptz;not null;default:now();column:created_at" json:"created_at"`
+	ProcessedAt       *time.Time                  `gorm:"type:timestamptz;column:processed_at" json:"processed_at"`
+}
+
+// TableName は対応するテーブル名を返します。
+func (OutboxEvent) TableName() string {
+	return "outbox_events"
+}
+
+// NotificationEventPayload は通知に関するアウトボックスイベントのペイロードです。
+// イベントの種類に応じて必要な項目のみ設定します。
+type NotificationEventPayload struct {
+	ActorUserID     string `json:"actor_user_id"`
+	RecipientUserID string `json:"recipient_user_id,omitempty"`
+	TagID           string `json:"tag_id,omitempty"`
+	TagMovieID      string `json:"tag_movie_id,omitempty"`
+	// tag.movie_added / tag.movie_removed: 対象の映画（削除後も Webhook で映画を示せるよう保持する）
+	TmdbMovieID int `json:"tmdb_movie_id,omitempty"`
+	// tag.updated: 名前が変わった場合の変更前のタイトルと、非公開になったかどうか
+	PreviousTitle *string `json:"previous_title,omitempty"`
+	MadePrivate   bool    `json:"made_private,omitempty"`
+	// tag.note_mentioned: メモでメンションされた display_id
+	MentionedDisplayIDs []string `json:"mentioned_display_ids,omitempty"`
+}
+
+// UserDataExportEventPayload は個人データのエクスポートを生成するアウトボックスイベントのペイロードです。
+type UserDataExportEventPayload struct {
+	ExportID string `json:"export_id"`
+}
@@ -0,0 +1,63 @@
+package model
+
+import (
+	"time"
+
+	"gorm.io/datatypes"
+)
+
+// パーソナルアクセストークンのスコープ。
+// トークンで呼び出せる API はスコープごとに決まっており、どのスコープにも含まれない API（トークンの管理・退会など）は呼び出せません。
+const (
+	AccessTokenScopeProfileRead        = "profile:read"        // 自分のプロフィールの取得
+	AccessTokenScopeUsersRead          = "users:read"          // ユーザーの検索・プロフィールやフォローの取得
+	AccessTokenScopeTagsRead           = "tags:read"           // タグの取得（非公開のタグを含む）
+	AccessTokenScopeTagsWrite          = "tags:write"          // タグの作成・更新、映画の追加・削除、フォロー・いいね
+	AccessTokenScopeMoviesRead         = "movies:read"         // 映画の詳細・評価、自分の視聴ステータスの取得
+	AccessTokenScopeMoviesWrite        = "movies:write"        // 視聴ステータス・評価の登録・削除
+	AccessTokenScopeNotificationsRead  = "notifications:read"  // 通知の取得
+	AccessTokenScopeNotificationsWrite = "notifications:write" // 通知の既読・アーカイブ・削除
+	AccessTokenScopeFeedRead           = "feed:read"           // ホームフィードの取得
+)
+
+// AccessTokenScopes は指定できるスコープの一覧です（作成時はこの順に並べて保存します）。
+var AccessTokenScopes = []string{
+	AccessTokenScopeProfileRead,
+	AccessTokenScopeUsersRead,
+	AccessTokenScopeTagsRead,
+	AccessTokenScopeTagsWrite,
+	AccessTokenScopeMoviesRead,
+	AccessTokenScopeMoviesWrite,
+	AccessTokenScopeNotificationsRead,
+	AccessTokenScopeNotificationsWrite,
+	AccessTokenScopeFeedRead,
+}
+
+// PersonalAccessToken はスクリプトやボットから API を呼び出すためのトークンを表します。
+// トークン本体は保存せず、SHA-256 のハッシュのみを保存します（作成時のみ返す）。
+type PersonalAccessToken struct {
+	ID          string                      `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
+	UserID      string                      `gorm:"type:uuid;not null;column:user_id" json:"-"`
+	Name        string                      `gorm:"type:varchar(100);not null;column:name" json:"name"`
+	TokenHash   string                      `gorm:"type:text;not null;uniqueIndex:personal_access_tokens_token_hash_key;column:token_hash" json:"-"`
+	TokenPrefix string                      `gorm:"type:text;not null;column:token_prefix" json:"token_prefix"` // 一覧で見分けるためのトークンの先頭部分
+	Scopes      datatypes.JSONSlice[string] `gorm:"type:jsonb;not null;column:scopes" json:"scopes"`
+	ExpiresAt   time.Time                   `gorm:"type:timestamptz;not null;column:expires_at" json:"expires_at"`
+	LastUsedAt  *time.Time                  `gorm:"type:timestamptz;column:last_used_at" json:"last_used_at"`
+	CreatedAt   time.Time                   `gorm:"type:timestamptz;not null;default:now();column:created_at" json:"created_at"`
+}
+
+// TableName は対応するテーブル名を返します。
+func (PersonalAccessToken) TableName() string {
+	return "personal_access_tokens"
+}
+
+// HasScope はトークンに scope が含まれるかを返します。
+func (t *PersonalAccessToken) HasScope(scope string) bool {
+	for _, s := range t.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
@@ -13,6 +13,11 @@ type Tag struct {
 	AddMoviePolicy string    `gorm:"type:text;not null;default:'everyone';column:add_movie_policy" json:"add_movie_policy"`
 	CreatedAt      time.Time `gorm:"type:timestamptz;not null;default:CURRENT_TIMESTAMP;column:created_at" json:"created_at"`
 	UpdatedAt      time.Time `gorm:"type:timestamptz;not null;default:CURRENT_TIMESTAMP;column:updated_at" json:"updated_at"`
+
+	// 管理者が非表示にした日時（非表示の間は非公開になり、持ち主は公開に戻せない）
+	HiddenAt *time.Time `gorm:"type:timestamptz;column:hidden_at" json:"hidden_at,omitempty"`
+	// 非表示にする前の公開範囲（非表示の間のみ設定し、再表示のときにこの公開範囲へ戻す）
+	PublicBeforeHidden *bool `gorm:"type:boolean;column:public_before_hidden" json:"-"`
 }
 
 // TableName は対応するテーブル名を返します。
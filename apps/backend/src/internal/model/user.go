@@ -2,6 +2,31 @@ package model
 
 import "time"
 
+// 退会状態定数
+const (
+	UserDeletionStatusActive      = "active"
+	UserDeletionStatusDeactivated = "deactivated"
+	UserDeletionStatusPurged      = "purged"
+)
+
+// ユーザーのロール
+const (
+	UserRoleUser  = "user"
+	UserRoleAdmin = "admin"
+)
+
+// 退会時の所有タグの扱い
+const (
+	OwnedTagsPolicyDelete   = "delete"
+	OwnedTagsPolicyTransfer = "transfer"
+)
+
+// 退会時の他者タグへの追加映画の扱い
+const (
+	ContributionsPolicyAnonymize = "anonymize"
+	ContributionsPolicyRemove    = "remove"
+)
+
 // User はサービスのユーザーを表すドメインモデルです。
 // docs/data/database-schema.md の users テーブル定義に対応します。
 type User struct {
@@ -12,12 +37,34 @@ type User struct {
 	Email       string     `gorm:"type:text;not null" json:"email"`
 	AvatarURL   *string    `gorm:"type:text;column:avatar_url" json:"avatar_url,omitempty"`
 	Bio         *string    `gorm:"type:text" json:"bio,omitempty"`
+	IsPrivate   bool       `gorm:"not null;default:false;column:is_private" json:"is_private"`
 	CreatedAt   time.Time  `gorm:"type:timestamptz;not null;default:CURRENT_TIMESTAMP;column:created_at" json:"created_at"`
 	UpdatedAt   time.Time  `gorm:"type:timestamptz;not null;default:CURRENT_TIMESTAMP;column:updated_at" json:"updated_at"`
 	DeletedAt   *time.Time `gorm:"type:timestamptz;column:deleted_at" json:"deleted_at,omitempty"`
+
+	// 管理者用 API の権限（model.UserRole*）と、管理者による利用停止日時
+	Role        string     `gorm:"type:text;not null;default:'user';column:role" json:"role"`
+	SuspendedAt *time.Time `gorm:"type:timestamptz;column:suspended_at" json:"suspended_at,omitempty"`
+
+	// 最後に Clerk から同期した値（アプリ上で変更されたかどうかの判定に使う）
+	ClerkDisplayName *string `gorm:"type:text;column:clerk_display_name" json:"-"`
+	ClerkUsername    *string `gorm:"type:text;column:clerk_username" json:"-"`
+
+	// 退会ライフサイクル（退会時に設定し、完全削除ジョブが参照する）
+	DeletionStatus      string     `gorm:"type:text;not null;default:'active';column:deletion_status" json:"deletion_status"`
+	PurgeScheduledAt    *time.Time `gorm:"type:timestamptz;column:purge_scheduled_at" json:"purge_scheduled_at,omitempty"`
+	PurgedAt            *time.Time `gorm:"type:timestamptz;column:purged_at" json:"-"`
+	OwnedTagsPolicy     *string    `gorm:"type:text;column:owned_tags_policy" json:"-"`
+	TagTransferUserID   *string    `gorm:"type:uuid;column:tag_transfer_user_id" json:"-"`
+	ContributionsPolicy *string    `gorm:"type:text;column:contributions_policy" json:"-"`
 }
 
 // TableName は対応するテーブル名を返します。
 func (User) TableName() string {
 	return "users"
 }
+
+// IsAdmin は管理者用 API を呼び出せるユーザーかどうかを返します。
+func (u *User) IsAdmin() bool {
+	return u.Role == UserRoleAdmin
+}
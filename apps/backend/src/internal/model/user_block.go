@@ -0,0 +1,16 @@
+package model
+
+import "time"
+
+// UserBlock はユーザーのブロック関係を表します。
+// ブロックしたユーザー（BlockerID）からは、ブロックされたユーザー（BlockedID）の通知や投稿が非表示になります。
+type UserBlock struct {
+	BlockerID string    `gorm:"type:uuid;primaryKey;column:blocker_id" json:"blocker_id"`
+	BlockedID string    `gorm:"type:uuid;primaryKey;column:blocked_id" json:"blocked_id"`
+	CreatedAt time.Time `gorm:"type:timestamptz;not null;default:now();column:created_at" json:"created_at"`
+}
+
+// TableName は対応するテーブル名を返します。
+func (UserBlock) TableName() string {
+	return "user_blocks"
+}
@@ -0,0 +1,36 @@
+package model
+
+import "time"
+
+// エクスポート形式定数
+const (
+	UserDataExportFormatJSON = "json"
+	UserDataExportFormatZIP  = "zip"
+)
+
+// エクスポート状態定数
+const (
+	UserDataExportStatusPending    = "pending"
+	UserDataExportStatusProcessing = "processing"
+	UserDataExportStatusCompleted  = "completed"
+	UserDataExportStatusFailed     = "failed"
+)
+
+// UserDataExport はユーザーの個人データエクスポート要求と生成結果を表します。
+type UserDataExport struct {
+	ID           string     `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
+	UserID       string     `gorm:"type:uuid;not null;column:user_id" json:"user_id"`
+	Format       string     `gorm:"type:text;not null" json:"format"`
+	Status       string     `gorm:"type:text;not null;default:'pending'" json:"status"`
+	FileName     *string    `gorm:"type:text;column:file_name" json:"file_name,omitempty"`
+	Content      []byte     `gorm:"type:bytea" json:"-"`
+	ErrorMessage *string    `gorm:"type:text;column:error_message" json:"-"`
+	ExpiresAt    *time.Time `gorm:"type:timestamptz;column:expires_at" json:"expires_at,omitempty"`
+	CompletedAt  *time.Time `gorm:"type:timestamptz;column:completed_at" json:"completed_at,omitempty"`
+	CreatedAt    time.Time  `gorm:"type:timestamptz;not null;default:now();column:created_at" json:"created_at"`
+}
+
+// TableName は対応するテーブル名を返します。
+func (UserDataExport) TableName() string {
+	return "user_data_exports"
+}
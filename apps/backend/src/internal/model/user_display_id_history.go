@@ -0,0 +1,17 @@
+package model
+
+import "time"
+
+// UserDisplayIDHistory はユーザーが過去に使用していた display_id を表します。
+// 旧 display_id でのアクセスを現在のプロフィールへリダイレクトするために利用します。
+type UserDisplayIDHistory struct {
+	ID        string    `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
+	UserID    string    `gorm:"type:uuid;not null;index:idx_user_display_id_histories_user_id;column:user_id" json:"user_id"`
+	DisplayID string    `gorm:"type:text;not null;uniqueIndex:user_display_id_histories_display_id_key;column:display_id" json:"display_id"`
+	CreatedAt time.Time `gorm:"type:timestamptz;not null;default:now();column:created_at" json:"created_at"`
+}
+
+// TableName は対応するテーブル名を返します。
+func (UserDisplayIDHistory) TableName() string {
+	return "user_display_id_histories"
+}
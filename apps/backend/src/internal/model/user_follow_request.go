@@ -0,0 +1,15 @@
+package model
+
+import "time"
+
+// UserFollowRequest は非公開アカウントへの承認待ちフォローリクエストを表します。
+type UserFollowRequest struct {
+	RequesterID string    `gorm:"type:uuid;primaryKey;column:requester_id" json:"requester_id"`
+	TargetID    string    `gorm:"type:uuid;primaryKey;column:target_id" json:"target_id"`
+	CreatedAt   time.Time `gorm:"type:timestamptz;not null;default:now();column:created_at" json:"created_at"`
+}
+
+// TableName は対応するテーブル名を返します。
+func (UserFollowRequest) TableName() string {
+	return "user_follow_requests"
+}
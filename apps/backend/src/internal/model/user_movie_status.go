@@ -0,0 +1,26 @@
+package model
+
+import "time"
+
+// 映画の視聴ステータス。
+const (
+	MovieWatchStatusWatched     = "watched"       // 観た
+	MovieWatchStatusWantToWatch = "want_to_watch" // 観たい
+	MovieWatchStatusDropped     = "dropped"       // 途中でやめた
+)
+
+// UserMovieStatus はユーザーごとの映画の視聴ステータスを表します。
+// WatchedAt は Status が watched の場合のみ設定できます（任意）。
+type UserMovieStatus struct {
+	UserID      string     `gorm:"type:uuid;primaryKey;column:user_id" json:"user_id"`
+	TmdbMovieID int        `gorm:"type:integer;primaryKey;column:tmdb_movie_id" json:"tmdb_movie_id"`
+	Status      string     `gorm:"type:text;not null" json:"status"`
+	WatchedAt   *time.Time `gorm:"type:date;column:watched_at" json:"watched_at,omitempty"`
+	CreatedAt   time.Time  `gorm:"type:timestamptz;not null;default:now();column:created_at" json:"created_at"`
+	UpdatedAt   time.Time  `gorm:"type:timestamptz;not null;default:now();column:updated_at" json:"updated_at"`
+}
+
+// TableName は対応するテーブル名を返します。
+func (UserMovieStatus) TableName() string {
+	return "user_movie_statuses"
+}
@@ -0,0 +1,16 @@
+package model
+
+import "time"
+
+// UserMute はユーザーのミュート関係を表します。
+// ミュートは通知とフィードの表示のみに影響し、フォロー関係などには影響しません。
+type UserMute struct {
+	MuterID   string    `gorm:"type:uuid;primaryKey;column:muter_id" json:"muter_id"`
+	MutedID   string    `gorm:"type:uuid;primaryKey;column:muted_id" json:"muted_id"`
+	CreatedAt time.Time `gorm:"type:timestamptz;not null;default:now();column:created_at" json:"created_at"`
+}
+
+// TableName は対応するテーブル名を返します。
+func (UserMute) TableName() string {
+	return "user_mutes"
+}
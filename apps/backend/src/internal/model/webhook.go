@@ -0,0 +1,62 @@
+package model
+
+import (
+	"encoding/json"
+	"time"
+
+	"gorm.io/datatypes"
+)
+
+// Webhook で配信するイベントの種類。
+const (
+	WebhookEventMovieAdded   = "movie_added"   // タグに映画が追加された
+	WebhookEventMovieRemoved = "movie_removed" // タグから映画が削除された
+	WebhookEventTagUpdated   = "tag_updated"   // タグの名前が変わった・非公開になった
+	WebhookEventFollowed     = "followed"      // タグ（アカウント全体の場合は自分も）がフォローされた
+)
+
+// Webhook はイベントの配信先として登録された URL を表します。
+// TagID が nil の場合は、ユーザーが作成したすべてのタグと、ユーザー自身へのフォローが対象です。
+type Webhook struct {
+	ID        string                      `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
+	UserID    string                      `gorm:"type:uuid;not null;column:user_id" json:"user_id"`
+	TagID     *string                     `gorm:"type:uuid;column:tag_id" json:"tag_id"`
+	URL       string                      `gorm:"type:text;not null;column:url" json:"url"`
+	Secret    string                      `gorm:"type:text;not null;column:secret" json:"-"` // 署名鍵（作成時のみ返す）
+	Events    datatypes.JSONSlice[string] `gorm:"type:jsonb;not null;column:events" json:"events"`
+	IsActive  bool                        `gorm:"not null;default:true;column:is_active" json:"is_active"`
+	CreatedAt time.Time                   `gorm:"type:timestamptz;not null;default:now();column:created_at" json:"created_at"`
+	UpdatedAt time.Time                   `gorm:"type:timestamptz;not null;default:now();column:updated_at" json:"updated_at"`
+}
+
+// TableName は対応するテーブル名を返します。
+func (Webhook) TableName() string {
+	return "webhooks"
+}
+
+// WebhookDelivery は Webhook の配信ログ（配信の試行1回分）を表します。
+type WebhookDelivery struct {
+	ID         string    `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
+	WebhookID  string    `gorm:"type:uuid;not null;column:webhook_id" json:"webhook_id"`
+	DeliveryID string    `gorm:"type:uuid;not null;column:delivery_id" json:"delivery_id"` // 同じ配信のリトライで共通
+	EventType  string    `gorm:"type:text;not null;column:event_type" json:"event_type"`
+	Attempt    int       `gorm:"not null;column:attempt" json:"attempt"`
+	Succeeded  bool      `gorm:"not null;column:succeeded" json:"succeeded"`
+	StatusCode *int      `gorm:"column:status_code" json:"status_code"`
+	Error      *string   `gorm:"type:text;column:error" json:"error"`
+	DurationMs int       `gorm:"not null;default:0;column:duration_ms" json:"duration_ms"`
+	CreatedAt  time.Time `gorm:"type:timestamptz;not null;default:now();column:created_at" json:"created_at"`
+}
+
+// TableName は対応するテーブル名を返します。
+func (WebhookDelivery) TableName() string {
+	return "webhook_deliveries"
+}
+
+// WebhookDeliveryPayload は Webhook の配信（webhook.deliver）のアウトボックスイベントのペイロードです。
+// 配信する本文は配信先ごとに振り分けた時点で組み立て、リトライしても同じ内容を送ります。
+type WebhookDeliveryPayload struct {
+	WebhookID string          `json:"webhook_id"`
+	EventType string          `json:"event_type"`
+	Body      json.RawMessage `json:"body"`
+}
@@ -0,0 +1,313 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"sync"
+	"time"
+
+	"cinetag-backend/src/internal/model"
+	"cinetag-backend/src/internal/repository"
+
+	"github.com/google/uuid"
+)
+
+// イベントを配信する処理。エラーを返した場合はリトライする。
+type Handler func(ctx context.Context, event *model.OutboxEvent) error
+
+// リトライしても成功しないエラー。Handler がこのエラーを（ラップして）返した場合は、リトライせずに破棄する。
+var ErrPermanent = errors.New("outbox: permanent failure")
+
+// ディスパッチャーの設定。ゼロ値の項目は既定値を使う。
+type Config struct {
+	PollInterval   time.Duration // 配信対象がない場合のポーリング間隔（既定: 1秒）
+	BatchSize      int           // 1回に確保するイベント数（既定: 20）
+	MaxAttempts    int           // 配信を試みる最大回数。超えたイベントは破棄する（既定: 10）
+	LockTimeout    time.Duration // 確保したイベントを他のワーカーに渡さない時間（既定: 1分）
+	HandlerTimeout time.Duration // 1件の配信のタイムアウト（既定: 10秒）
+	BaseBackoff    time.Duration // 1回目のリトライまでの待ち時間。以降は倍々に延ばす（既定: 5秒）
+	MaxBackoff     time.Duration // リトライまでの待ち時間の上限（既定: 1時間）
+
+	// イベントの種類ごとの配信のタイムアウト。指定がない種類は HandlerTimeout を使う
+	HandlerTimeouts map[string]time.Duration
+}
+
+// ゼロ値の項目を既定値で埋めた設定を返す。
+func (c Config) withDefaults() Config {
+	if c.PollInterval <= 0 {
+		c.PollInterval = time.Second
+	}
+	if c.BatchSize <= 0 {
+		c.BatchSize = 20
+	}
+	if c.MaxAttempts <= 0 {
+		c.MaxAttempts = 10
+	}
+	if c.LockTimeout <= 0 {
+		c.LockTimeout = time.Minute
+	}
+	if c.HandlerTimeout <= 0 {
+		c.HandlerTimeout = 10 * time.Second
+	}
+	// 配信中に他のワーカーへ渡らないよう、確保時間は配信のタイムアウトより長くする
+	maxTimeout := c.HandlerTimeout
+	for _, timeout := range c.HandlerTimeouts {
+		maxTimeout = max(maxTimeout, timeout)
+	}
+	if c.LockTimeout <= maxTimeout {
+		c.LockTimeout = 2 * maxTimeout
+	}
+	if c.BaseBackoff <= 0 {
+		c.BaseBackoff = 5 * time.Second
+	}
+	if c.MaxBackoff <= 0 {
+		c.MaxBackoff = time.Hour
+	}
+	return c
+}
+
+// 名前を付けた配信処理（イベントの種類ごとの Handler）の集まり。
+// 名前は成功した配信処理の記録に使うため、集まりごとに一意で、変更しないものにする。
+type HandlerSet struct {
+	Name     string
+	Handlers map[string]Handler
+}
+
+// 名前を付けた Handler。
+type namedHandler struct {
+	name   string
+	handle Handler
+}
+
+// 複数の配信処理の集まりを1つにまとめる。
+// 同じ種類のイベントに複数の Handler がある場合は、1つが失敗しても残りを引数の順にすべて実行し、
+// 成功した Handler の名前を event.CompletedHandlers に記録する（リトライでは記録済みの Handler を再実行しない）。
+func MergeHandlers(sets ...HandlerSet) map[string]Handler {
+	named := make(map[string][]namedHandler)
+	for _, set := range sets {
+		for eventType, h := range set.Handlers {
+			named[eventType] = append(named[eventType], namedHandler{name: set.Name, handle: h})
+		}
+	}
+
+	merged := make(map[string]Handler, len(named))
+	for eventType, handlers := range named {
+		if len(handlers) == 1 {
+			merged[eventType] = handlers[0].handle
+			continue
+		}
+		merged[eventType] = runEach(handlers)
+	}
+	return merged
+}
+
+// 記録済みでない Handler を順に実行する Handler を返す。
+// リトライしても成功しない Handler は、以降のリトライで再実行しないよう成功した場合と同様に記録する。
+// ErrPermanent を返すのは、失敗した Handler がすべてリトライしても成功しない場合のみ。
+func runEach(handlers []namedHandler) Handler {
+	return func(ctx context.Context, event *model.OutboxEvent) error {
+		var retryable, permanent []error
+		for _, h := range handlers {
+			if slices.Contains(event.CompletedHandlers, h.name) {
+				continue
+			}
+			err := h.handle(ctx, event)
+			if err != nil && !errors.Is(err, ErrPermanent) {
+				retryable = append(retryable, fmt.Errorf("%s: %w", h.name, err))
+				continue
+			}
+			if err != nil {
+				permanent = append(permanent, fmt.Errorf("%s: %w", h.name, err))
+			}
+			event.CompletedHandlers = append(event.CompletedHandlers, h.name)
+		}
+
+		if len(retryable) == 0 {
+			return errors.Join(permanent...)
+		}
+		// リトライさせるため、リトライしても成功しないエラーは内容のみを含める
+		for _, err := range permanent {
+			retryable = append(retryable, errors.New(err.Error()))
+		}
+		return errors.Join(retryable...)
+	}
+}
+
+// アウトボックスのイベントをリトライしながら配信するワーカー。
+// 同じイベントは確保したワーカーだけが配信し、配信済みにするまで他のワーカーには渡らない（少なくとも1回の配信）。
+type Dispatcher struct {
+	logger   *slog.Logger
+	repo     repository.OutboxRepository
+	handlers map[string]Handler
+	cfg      Config
+	now      func() time.Time
+
+	mu      sync.Mutex
+	started bool
+	stop    chan struct{}
+	done    chan struct{}
+}
+
+// Dispatcher を生成する。handlers はイベントの種類ごとの配信処理。
+func NewDispatcher(logger *slog.Logger, repo repository.OutboxRepository, handlers map[string]Handler, cfg Config) *Dispatcher {
+	return &Dispatcher{
+		logger:   logger,
+		repo:     repo,
+		handlers: handlers,
+		cfg:      cfg.withDefaults(),
+		now:      time.Now,
+		stop:     make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+}
+
+// バックグラウンドで配信を開始する。Shutdown を呼ぶまで配信を続ける。
+func (d *Dispatcher) Start() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.started {
+		return
+	}
+	d.started = true
+	go d.run()
+}
+
+// 新しいイベントの確保をやめ、配信中のイベントを処理し終えるまで待つ。
+// ctx が先に終了した場合は ctx のエラーを返す（未完了のイベントは確保期限が切れた後に再配信される）。
+func (d *Dispatcher) Shutdown(ctx context.Context) error {
+	d.mu.Lock()
+	if !d.started {
+		d.mu.Unlock()
+		return nil
+	}
+	select {
+	case <-d.stop:
+	default:
+		close(d.stop)
+	}
+	d.mu.Unlock()
+
+	select {
+	case <-d.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// 配信対象がなくなるまでバッチ処理を繰り返し、なければポーリング間隔だけ待つ。
+func (d *Dispatcher) run() {
+	defer close(d.done)
+
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+	for {
+		select {
+		case <-d.stop:
+			return
+		case <-timer.C:
+		}
+
+		n, err := d.RunOnce(context.Background())
+		if err != nil {
+			d.logger.Error("outbox.Dispatcher failed to claim events", slog.Any("error", err))
+		}
+
+		wait := d.cfg.PollInterval
+		if err == nil && n == d.cfg.BatchSize {
+			// まだ配信対象が残っている可能性があるため、すぐに次のバッチを処理する
+			wait = 0
+		}
+		timer.Reset(wait)
+	}
+}
+
+// 配信時期を迎えたイベントを1バッチ分確保して配信し、確保した件数を返す。
+// 確保したイベントは Shutdown が呼ばれても処理し終えてから戻る。
+func (d *Dispatcher) RunOnce(ctx context.Context) (int, error) {
+	now := d.now()
+	lockToken := uuid.NewString()
+	events, err := d.repo.ClaimDue(ctx, now, now.Add(d.cfg.LockTimeout), lockToken, d.cfg.BatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, event := range events {
+		d.dispatch(ctx, event, lockToken)
+	}
+	return len(events), nil
+}
+
+// イベントを1件配信し、結果を記録する。
+func (d *Dispatcher) dispatch(ctx context.Context, event *model.OutboxEvent, lockToken string) {
+	err := d.handle(ctx, event)
+	if err == nil {
+		if err := d.repo.MarkDone(ctx, event.ID, lockToken, d.now()); err != nil {
+			d.logger.Error("outbox.Dispatcher failed to mark event as done",
+				slog.String("event_id", event.ID),
+				slog.String("event_type", event.EventType),
+				slog.Any("error", err),
+			)
+		}
+		return
+	}
+
+	var nextAttemptAt *time.Time
+	if !errors.Is(err, ErrPermanent) && event.Attempts < d.cfg.MaxAttempts {
+		next := d.now().Add(d.backoff(event.Attempts))
+		nextAttemptAt = &next
+	}
+
+	logAttrs := []any{
+		slog.String("event_id", event.ID),
+		slog.String("event_type", event.EventType),
+		slog.Int("attempts", event.Attempts),
+		slog.Any("error", err),
+	}
+	if nextAttemptAt != nil {
+		d.logger.Warn("outbox.Dispatcher failed to deliver event, will retry", append(logAttrs, slog.Time("next_attempt_at", *nextAttemptAt))...)
+	} else {
+		d.logger.Error("outbox.Dispatcher gave up delivering event", logAttrs...)
+	}
+
+	if err := d.repo.MarkFailed(ctx, event.ID, lockToken, err.Error(), event.CompletedHandlers, nextAttemptAt); err != nil {
+		d.logger.Error("outbox.Dispatcher failed to record delivery failure",
+			slog.String("event_id", event.ID),
+			slog.Any("error", err),
+		)
+	}
+}
+
+// イベントの種類に対応する Handler を実行する。panic はエラーとして扱う。
+func (d *Dispatcher) handle(ctx context.Context, event *model.OutboxEvent) (err error) {
+	h, ok := d.handlers[event.EventType]
+	if !ok {
+		return fmt.Errorf("%w: unknown event type %q", ErrPermanent, event.EventType)
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("handler panicked: %v", r)
+		}
+	}()
+
+	timeout := d.cfg.HandlerTimeout
+	if t, ok := d.cfg.HandlerTimeouts[event.EventType]; ok && t > 0 {
+		timeout = t
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	return h(ctx, event)
+}
+
+// attempts 回目の失敗後、次の配信までの待ち時間を返す。
+func (d *Dispatcher) backoff(attempts int) time.Duration {
+	wait := d.cfg.BaseBackoff
+	for i := 1; i < attempts && wait < d.cfg.MaxBackoff; i++ {
+		wait *= 2
+	}
+	return min(wait, d.cfg.MaxBackoff)
+}
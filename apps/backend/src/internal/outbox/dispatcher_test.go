@@ -0,0 +1,430 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"cinetag-backend/src/internal/model"
+	"cinetag-backend/src/internal/testutil"
+)
+
+func newTestDispatcher(repo *testutil.FakeOutboxRepository, handlers map[string]Handler, now time.Time) *Dispatcher {
+	d := NewDispatcher(testutil.NewTestLogger(), repo, handlers, Config{
+		PollInterval: 10 * time.Millisecond,
+		MaxAttempts:  3,
+		BaseBackoff:  time.Second,
+		MaxBackoff:   3 * time.Second,
+	})
+	d.now = func() time.Time { return now }
+	return d
+}
+
+func claimOnce(events ...*model.OutboxEvent) func(ctx context.Context, now, lockedUntil time.Time, lockToken string, limit int) ([]*model.OutboxEvent, error) {
+	var mu sync.Mutex
+	claimed := false
+	return func(ctx context.Context, now, lockedUntil time.Time, lockToken string, limit int) ([]*model.OutboxEvent, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		if claimed {
+			return []*model.OutboxEvent{}, nil
+		}
+		claimed = true
+		return events, nil
+	}
+}
+
+func TestDispatcher_RunOnce(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	t.Run("配信に成功したイベントは配信済みにする", func(t *testing.T) {
+		t.Parallel()
+
+		var claimToken, doneToken, doneID string
+		repo := &testutil.FakeOutboxRepository{
+			ClaimDueFn: func(ctx context.Context, gotNow, lockedUntil time.Time, lockToken string, limit int) ([]*model.OutboxEvent, error) {
+				if !gotNow.Equal(now) || !lockedUntil.After(now) {
+					t.Fatalf("unexpected claim window: %v - %v", gotNow, lockedUntil)
+				}
+				claimToken = lockToken
+				return []*model.OutboxEvent{{ID: "e1", EventType: "test", Attempts: 1}}, nil
+			},
+			MarkDoneFn: func(ctx context.Context, id, lockToken string, at time.Time) error {
+				doneID, doneToken = id, lockToken
+				return nil
+			},
+			MarkFailedFn: func(ctx context.Context, id, lockToken, lastError string, completedHandlers []string, nextAttemptAt *time.Time) error {
+				t.Fatalf("unexpected failure: %s", lastError)
+				return nil
+			},
+		}
+		var handled []string
+		d := newTestDispatcher(repo, map[string]Handler{
+			"test": func(ctx context.Context, event *model.OutboxEvent) error {
+				handled = append(handled, event.ID)
+				return nil
+			},
+		}, now)
+
+		n, err := d.RunOnce(context.Background())
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if n != 1 || len(handled) != 1 {
+			t.Fatalf("expected 1 event to be handled, got n=%d handled=%v", n, handled)
+		}
+		if doneID != "e1" || doneToken == "" || doneToken != claimToken {
+			t.Fatalf("unexpected MarkDone: id=%s token=%s claim=%s", doneID, doneToken, claimToken)
+		}
+	})
+
+	t.Run("失敗したイベントは待ち時間を延ばしながらリトライし、上限に達したら破棄する", func(t *testing.T) {
+		t.Parallel()
+
+		cases := []struct {
+			attempts int
+			want     *time.Duration
+		}{
+			{attempts: 1, want: durationPtr(time.Second)},
+			{attempts: 2, want: durationPtr(2 * time.Second)},
+			{attempts: 3, want: nil},
+		}
+		for _, tc := range cases {
+			var gotNext *time.Time
+			var gotError string
+			repo := &testutil.FakeOutboxRepository{
+				ClaimDueFn: claimOnce(&model.OutboxEvent{ID: "e1", EventType: "test", Attempts: tc.attempts}),
+				MarkFailedFn: func(ctx context.Context, id, lockToken, lastError string, completedHandlers []string, nextAttemptAt *time.Time) error {
+					gotNext, gotError = nextAttemptAt, lastError
+					return nil
+				},
+			}
+			d := newTestDispatcher(repo, map[string]Handler{
+				"test": func(ctx context.Context, event *model.OutboxEvent) error {
+					return errors.New("db down")
+				},
+			}, now)
+
+			if _, err := d.RunOnce(context.Background()); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if gotError != "db down" {
+				t.Fatalf("attempts=%d: unexpected last error %q", tc.attempts, gotError)
+			}
+			switch {
+			case tc.want == nil && gotNext != nil:
+				t.Fatalf("attempts=%d: expected event to be dead, got retry at %v", tc.attempts, *gotNext)
+			case tc.want != nil && (gotNext == nil || !gotNext.Equal(now.Add(*tc.want))):
+				t.Fatalf("attempts=%d: expected retry after %v, got %v", tc.attempts, *tc.want, gotNext)
+			}
+		}
+	})
+
+	t.Run("未知のイベントや恒久的なエラーはリトライしない", func(t *testing.T) {
+		t.Parallel()
+
+		var dead []string
+		repo := &testutil.FakeOutboxRepository{
+			ClaimDueFn: claimOnce(
+				&model.OutboxEvent{ID: "e1", EventType: "unknown", Attempts: 1},
+				&model.OutboxEvent{ID: "e2", EventType: "test", Attempts: 1},
+			),
+			MarkFailedFn: func(ctx context.Context, id, lockToken, lastError string, completedHandlers []string, nextAttemptAt *time.Time) error {
+				if nextAttemptAt == nil {
+					dead = append(dead, id)
+				}
+				return nil
+			},
+		}
+		d := newTestDispatcher(repo, map[string]Handler{
+			"test": func(ctx context.Context, event *model.OutboxEvent) error {
+				return ErrPermanent
+			},
+		}, now)
+
+		if _, err := d.RunOnce(context.Background()); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(dead) != 2 {
+			t.Fatalf("expected both events to be dead, got %v", dead)
+		}
+	})
+
+	t.Run("失敗を記録する際に成功した配信処理を記録する", func(t *testing.T) {
+		t.Parallel()
+
+		var gotCompleted []string
+		repo := &testutil.FakeOutboxRepository{
+			ClaimDueFn: claimOnce(&model.OutboxEvent{ID: "e1", EventType: "test", Attempts: 1}),
+			MarkFailedFn: func(ctx context.Context, id, lockToken, lastError string, completedHandlers []string, nextAttemptAt *time.Time) error {
+				gotCompleted = completedHandlers
+				return nil
+			},
+		}
+		d := newTestDispatcher(repo, MergeHandlers(
+			HandlerSet{Name: "first", Handlers: map[string]Handler{"test": func(ctx context.Context, event *model.OutboxEvent) error {
+				return nil
+			}}},
+			HandlerSet{Name: "second", Handlers: map[string]Handler{"test": func(ctx context.Context, event *model.OutboxEvent) error {
+				return errors.New("db down")
+			}}},
+		), now)
+
+		if _, err := d.RunOnce(context.Background()); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if fmt.Sprint(gotCompleted) != "[first]" {
+			t.Fatalf("expected the first handler to be recorded, got %v", gotCompleted)
+		}
+	})
+
+	t.Run("Handler の panic は失敗として扱う", func(t *testing.T) {
+		t.Parallel()
+
+		var failed bool
+		repo := &testutil.FakeOutboxRepository{
+			ClaimDueFn: claimOnce(&model.OutboxEvent{ID: "e1", EventType: "test", Attempts: 1}),
+			MarkFailedFn: func(ctx context.Context, id, lockToken, lastError string, completedHandlers []string, nextAttemptAt *time.Time) error {
+				failed = nextAttemptAt != nil
+				return nil
+			},
+		}
+		d := newTestDispatcher(repo, map[string]Handler{
+			"test": func(ctx context.Context, event *model.OutboxEvent) error {
+				panic("boom")
+			},
+		}, now)
+
+		if _, err := d.RunOnce(context.Background()); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !failed {
+			t.Fatalf("expected event to be retried")
+		}
+	})
+
+	t.Run("種類ごとのタイムアウトを指定したイベントは、その時間だけ配信を待ち、確保時間も延ばす", func(t *testing.T) {
+		t.Parallel()
+
+		var lockWindow time.Duration
+		repo := &testutil.FakeOutboxRepository{
+			ClaimDueFn: func(ctx context.Context, gotNow, lockedUntil time.Time, lockToken string, limit int) ([]*model.OutboxEvent, error) {
+				lockWindow = lockedUntil.Sub(gotNow)
+				return []*model.OutboxEvent{{ID: "e1", EventType: "slow", Attempts: 1}, {ID: "e2", EventType: "test", Attempts: 1}}, nil
+			},
+		}
+		deadlines := map[string]time.Duration{}
+		handler := func(ctx context.Context, event *model.OutboxEvent) error {
+			deadline, _ := ctx.Deadline()
+			deadlines[event.EventType] = time.Until(deadline)
+			return nil
+		}
+		d := NewDispatcher(testutil.NewTestLogger(), repo, map[string]Handler{"slow": handler, "test": handler}, Config{
+			HandlerTimeout:  time.Second,
+			HandlerTimeouts: map[string]time.Duration{"slow": time.Minute},
+		})
+		d.now = func() time.Time { return now }
+
+		if _, err := d.RunOnce(context.Background()); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if deadlines["slow"] <= time.Second || deadlines["test"] > time.Second {
+			t.Fatalf("unexpected handler deadlines: %v", deadlines)
+		}
+		if lockWindow <= time.Minute {
+			t.Fatalf("expected lock timeout longer than the slowest handler, got %v", lockWindow)
+		}
+	})
+}
+
+func TestDispatcher_Shutdown(t *testing.T) {
+	t.Parallel()
+
+	t.Run("配信中のイベントを処理し終えてから停止する", func(t *testing.T) {
+		t.Parallel()
+
+		started := make(chan struct{})
+		release := make(chan struct{})
+		var mu sync.Mutex
+		var done []string
+		repo := &testutil.FakeOutboxRepository{
+			ClaimDueFn: claimOnce(&model.OutboxEvent{ID: "e1", EventType: "test", Attempts: 1}),
+			MarkDoneFn: func(ctx context.Context, id, lockToken string, at time.Time) error {
+				mu.Lock()
+				defer mu.Unlock()
+				done = append(done, id)
+				return nil
+			},
+		}
+		d := newTestDispatcher(repo, map[string]Handler{
+			"test": func(ctx context.Context, event *model.OutboxEvent) error {
+				close(started)
+				<-release
+				return nil
+			},
+		}, time.Now())
+
+		d.Start()
+		<-started
+
+		stopped := make(chan error, 1)
+		go func() { stopped <- d.Shutdown(context.Background()) }()
+
+		select {
+		case <-stopped:
+			t.Fatalf("Shutdown returned before the in-flight event finished")
+		case <-time.After(50 * time.Millisecond):
+		}
+
+		close(release)
+		if err := <-stopped; err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		if len(done) != 1 {
+			t.Fatalf("expected in-flight event to be marked as done, got %v", done)
+		}
+	})
+
+	t.Run("待ち時間を過ぎた場合は ctx のエラーを返す", func(t *testing.T) {
+		t.Parallel()
+
+		started := make(chan struct{})
+		release := make(chan struct{})
+		defer close(release)
+		repo := &testutil.FakeOutboxRepository{
+			ClaimDueFn: claimOnce(&model.OutboxEvent{ID: "e1", EventType: "test", Attempts: 1}),
+		}
+		d := newTestDispatcher(repo, map[string]Handler{
+			"test": func(ctx context.Context, event *model.OutboxEvent) error {
+				close(started)
+				<-release
+				return nil
+			},
+		}, time.Now())
+
+		d.Start()
+		<-started
+
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+		if err := d.Shutdown(ctx); !errors.Is(err, context.DeadlineExceeded) {
+			t.Fatalf("expected context.DeadlineExceeded, got %v", err)
+		}
+	})
+
+	t.Run("開始していない場合は何もしない", func(t *testing.T) {
+		t.Parallel()
+
+		d := newTestDispatcher(&testutil.FakeOutboxRepository{}, nil, time.Now())
+		if err := d.Shutdown(context.Background()); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+}
+
+func TestMergeHandlers(t *testing.T) {
+	t.Parallel()
+
+	failed := errors.New("failed")
+
+	t.Run("失敗した Handler があっても残りを実行し、成功した Handler を記録する", func(t *testing.T) {
+		t.Parallel()
+
+		var calls []string
+		record := func(name string, err error) Handler {
+			return func(ctx context.Context, event *model.OutboxEvent) error {
+				calls = append(calls, name)
+				return err
+			}
+		}
+		handlers := MergeHandlers(
+			HandlerSet{Name: "first", Handlers: map[string]Handler{"a": record("first-a", nil), "b": record("first-b", failed)}},
+			HandlerSet{Name: "second", Handlers: map[string]Handler{"a": record("second-a", nil), "b": record("second-b", nil), "c": record("second-c", nil)}},
+		)
+
+		completed := make(map[string][]string)
+		for _, eventType := range []string{"a", "b", "c"} {
+			event := &model.OutboxEvent{EventType: eventType}
+			err := handlers[eventType](context.Background(), event)
+			if (eventType == "b") != errors.Is(err, failed) {
+				t.Fatalf("%s: unexpected error: %v", eventType, err)
+			}
+			completed[eventType] = event.CompletedHandlers
+		}
+		want := []string{"first-a", "second-a", "first-b", "second-b", "second-c"}
+		if fmt.Sprint(calls) != fmt.Sprint(want) {
+			t.Fatalf("expected calls %v, got %v", want, calls)
+		}
+		if fmt.Sprint(completed["a"]) != "[first second]" || fmt.Sprint(completed["b"]) != "[second]" {
+			t.Fatalf("unexpected completed handlers: %v", completed)
+		}
+	})
+
+	t.Run("リトライでは記録済みの Handler を再実行しない", func(t *testing.T) {
+		t.Parallel()
+
+		var calls []string
+		handlers := MergeHandlers(
+			HandlerSet{Name: "first", Handlers: map[string]Handler{"a": func(ctx context.Context, event *model.OutboxEvent) error {
+				calls = append(calls, "first")
+				return nil
+			}}},
+			HandlerSet{Name: "second", Handlers: map[string]Handler{"a": func(ctx context.Context, event *model.OutboxEvent) error {
+				calls = append(calls, "second")
+				return nil
+			}}},
+		)
+
+		event := &model.OutboxEvent{EventType: "a", CompletedHandlers: []string{"first"}}
+		if err := handlers["a"](context.Background(), event); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if fmt.Sprint(calls) != "[second]" {
+			t.Fatalf("expected only the second handler to run, got %v", calls)
+		}
+	})
+
+	t.Run("リトライしても成功しないのは、失敗した Handler がすべてそうである場合のみ", func(t *testing.T) {
+		t.Parallel()
+
+		permanent := func(ctx context.Context, event *model.OutboxEvent) error {
+			return fmt.Errorf("%w: rejected", ErrPermanent)
+		}
+		retryable := func(ctx context.Context, event *model.OutboxEvent) error {
+			return failed
+		}
+
+		mixed := MergeHandlers(
+			HandlerSet{Name: "first", Handlers: map[string]Handler{"a": permanent}},
+			HandlerSet{Name: "second", Handlers: map[string]Handler{"a": retryable}},
+		)
+		event := &model.OutboxEvent{EventType: "a"}
+		err := mixed["a"](context.Background(), event)
+		if errors.Is(err, ErrPermanent) || !errors.Is(err, failed) {
+			t.Fatalf("expected a retryable error, got %v", err)
+		}
+		// リトライしても成功しない Handler は再実行しない
+		if fmt.Sprint(event.CompletedHandlers) != "[first]" {
+			t.Fatalf("unexpected completed handlers: %v", event.CompletedHandlers)
+		}
+
+		allPermanent := MergeHandlers(
+			HandlerSet{Name: "first", Handlers: map[string]Handler{"a": permanent}},
+			HandlerSet{Name: "second", Handlers: map[string]Handler{"a": permanent}},
+		)
+		if err := allPermanent["a"](context.Background(), &model.OutboxEvent{EventType: "a"}); !errors.Is(err, ErrPermanent) {
+			t.Fatalf("expected ErrPermanent, got %v", err)
+		}
+	})
+}
+
+func durationPtr(d time.Duration) *time.Duration {
+	return &d
+}
@@ -0,0 +1,72 @@
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"cinetag-backend/src/internal/model"
+	"cinetag-backend/src/internal/service"
+
+	"gorm.io/gorm"
+)
+
+// 通知に関するイベントの配信処理を返す。
+// NotificationService の通知処理（通知の作成・フィードへの記録）をイベントの種類ごとに呼び出す。
+// 再配信で通知を重複させないよう、イベントの ID を通知の元のイベントとして渡す。
+func NotificationHandlers(notificationService service.NotificationService) HandlerSet {
+	return HandlerSet{Name: "notification", Handlers: map[string]Handler{
+		model.OutboxEventTypeTagCreated: notificationHandler(func(ctx context.Context, eventID string, p model.NotificationEventPayload) error {
+			return notificationService.NotifyFollowingUserCreatedTag(ctx, eventID, p.TagID, p.ActorUserID)
+		}),
+		model.OutboxEventTypeTagMovieAdded: notificationHandler(func(ctx context.Context, eventID string, p model.NotificationEventPayload) error {
+			return notificationService.NotifyTagMovieAdded(ctx, eventID, p.TagID, p.TagMovieID, p.ActorUserID)
+		}),
+		model.OutboxEventTypeTagFollowed: notificationHandler(func(ctx context.Context, eventID string, p model.NotificationEventPayload) error {
+			return notificationService.NotifyTagFollowed(ctx, eventID, p.TagID, p.ActorUserID)
+		}),
+		model.OutboxEventTypeTagLiked: notificationHandler(func(ctx context.Context, eventID string, p model.NotificationEventPayload) error {
+			return notificationService.NotifyTagLiked(ctx, eventID, p.TagID, p.ActorUserID)
+		}),
+		model.OutboxEventTypeTagUpdated: notificationHandler(func(ctx context.Context, eventID string, p model.NotificationEventPayload) error {
+			return notificationService.NotifyTagUpdated(ctx, eventID, p.TagID, p.ActorUserID, model.NotificationTagUpdateDetails{
+				PreviousTitle: p.PreviousTitle,
+				MadePrivate:   p.MadePrivate,
+			})
+		}),
+		model.OutboxEventTypeNoteMentioned: notificationHandler(func(ctx context.Context, eventID string, p model.NotificationEventPayload) error {
+			return notificationService.NotifyMentionedInNote(ctx, eventID, p.TagID, p.TagMovieID, p.ActorUserID, p.MentionedDisplayIDs)
+		}),
+		model.OutboxEventTypeUserFollowed: notificationHandler(func(ctx context.Context, eventID string, p model.NotificationEventPayload) error {
+			return notificationService.NotifyUserFollowed(ctx, eventID, p.RecipientUserID, p.ActorUserID)
+		}),
+		model.OutboxEventTypeFollowRequested: notificationHandler(func(ctx context.Context, eventID string, p model.NotificationEventPayload) error {
+			return notificationService.NotifyFollowRequested(ctx, eventID, p.RecipientUserID, p.ActorUserID)
+		}),
+		model.OutboxEventTypeFollowRequestApproved: notificationHandler(func(ctx context.Context, eventID string, p model.NotificationEventPayload) error {
+			return notificationService.NotifyFollowRequestApproved(ctx, eventID, p.RecipientUserID, p.ActorUserID)
+		}),
+		model.OutboxEventTypeFollowRequestDenied: notificationHandler(func(ctx context.Context, eventID string, p model.NotificationEventPayload) error {
+			return notificationService.NotifyFollowRequestDenied(ctx, eventID, p.RecipientUserID, p.ActorUserID)
+		}),
+	}}
+}
+
+// ペイロードを model.NotificationEventPayload として読み込んでから send を呼ぶ Handler を返す。
+// ペイロードが壊れている場合や、配信前に対象のタグが削除された場合はリトライしても成功しないため、ErrPermanent を返す。
+func notificationHandler(send func(ctx context.Context, eventID string, p model.NotificationEventPayload) error) Handler {
+	return func(ctx context.Context, event *model.OutboxEvent) error {
+		var p model.NotificationEventPayload
+		if err := json.Unmarshal(event.Payload, &p); err != nil {
+			return fmt.Errorf("%w: invalid payload: %v", ErrPermanent, err)
+		}
+		if err := send(ctx, event.ID, p); err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return fmt.Errorf("%w: %v", ErrPermanent, err)
+			}
+			return err
+		}
+		return nil
+	}
+}
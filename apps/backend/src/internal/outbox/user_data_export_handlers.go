@@ -0,0 +1,25 @@
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"cinetag-backend/src/internal/model"
+	"cinetag-backend/src/internal/service"
+)
+
+// 個人データのエクスポートに関するイベントの配信処理を返す。
+// エクスポートファイルの生成は通常の配信より時間がかかるため、
+// Config.HandlerTimeouts で service.UserDataExportTimeout を設定して使う。
+func UserDataExportHandlers(exportService service.UserDataExportService) HandlerSet {
+	return HandlerSet{Name: "user_data_export", Handlers: map[string]Handler{
+		model.OutboxEventTypeUserDataExportRequested: func(ctx context.Context, event *model.OutboxEvent) error {
+			var p model.UserDataExportEventPayload
+			if err := json.Unmarshal(event.Payload, &p); err != nil {
+				return fmt.Errorf("%w: invalid payload: %v", ErrPermanent, err)
+			}
+			return exportService.GenerateExport(ctx, p.ExportID)
+		},
+	}}
+}
@@ -0,0 +1,37 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"cinetag-backend/src/internal/model"
+	"cinetag-backend/src/internal/service"
+)
+
+// Webhook に関するイベントの配信処理を返す。
+// 通知と同じイベントを購読している Webhook ごとの配信イベント（webhook.deliver）に振り分け、配信イベントを送信する。
+func WebhookHandlers(webhookService service.WebhookService) HandlerSet {
+	fanOut := webhookHandler(webhookService.FanOut)
+	return HandlerSet{Name: "webhook", Handlers: map[string]Handler{
+		model.OutboxEventTypeTagMovieAdded:   fanOut,
+		model.OutboxEventTypeTagMovieRemoved: fanOut,
+		model.OutboxEventTypeTagUpdated:      fanOut,
+		model.OutboxEventTypeTagFollowed:     fanOut,
+		model.OutboxEventTypeUserFollowed:    fanOut,
+		model.OutboxEventTypeWebhookDelivery: webhookHandler(webhookService.Deliver),
+	}}
+}
+
+// 送信先が配信を拒否した場合はリトライしても成功しないため、ErrPermanent を返す Handler に変換する。
+func webhookHandler(h func(ctx context.Context, event *model.OutboxEvent) error) Handler {
+	return func(ctx context.Context, event *model.OutboxEvent) error {
+		if err := h(ctx, event); err != nil {
+			if errors.Is(err, service.ErrWebhookDeliveryRejected) {
+				return fmt.Errorf("%w: %v", ErrPermanent, err)
+			}
+			return err
+		}
+		return nil
+	}
+}
@@ -0,0 +1,18 @@
+// Package pubsub はトピック単位の publish/subscribe（通知のリアルタイム配信などに使う）を提供します。
+//
+// 単一インスタンスではプロセス内の MemoryHub を、複数インスタンス（Cloud Run のスケールアウト等）では
+// Postgres の LISTEN/NOTIFY を使う PostgresHub を使います。どちらも Hub インターフェースを満たすため、
+// 利用側は実装を意識せずに差し替えられます。
+package pubsub
+
+import "context"
+
+// Hub はトピック単位の publish/subscribe を表すインターフェース。
+type Hub interface {
+	// Publish は topic の購読者全員に payload を配信します。
+	// 実装によっては他インスタンスの購読者にも配信されます。
+	Publish(ctx context.Context, topic string, payload []byte) error
+	// Subscribe は topic を購読し、受信用チャネルと購読解除関数を返します。
+	// 購読解除するとチャネルは閉じられます。
+	Subscribe(topic string) (<-chan []byte, func())
+}
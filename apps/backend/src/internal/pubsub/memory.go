@@ -0,0 +1,65 @@
+package pubsub
+
+import (
+	"context"
+	"sync"
+)
+
+// 購読者ごとの受信バッファ。
+const subscriberBufferSize = 16
+
+// プロセス内で完結する Hub の実装。
+type memoryHub struct {
+	mu          sync.RWMutex
+	subscribers map[string]map[chan []byte]struct{}
+}
+
+// プロセス内で完結する Hub を生成する。
+// 単一インスタンス構成やテストで使う（他インスタンスには配信されない）。
+func NewMemoryHub() Hub {
+	return newMemoryHub()
+}
+
+func newMemoryHub() *memoryHub {
+	return &memoryHub{subscribers: make(map[string]map[chan []byte]struct{})}
+}
+
+// topic の購読者全員に payload を配信する。
+// 購読側の受信が追いつかずバッファが埋まっている場合、その購読者への配信は破棄する（配信をブロックしない）。
+func (h *memoryHub) Publish(ctx context.Context, topic string, payload []byte) error {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	for ch := range h.subscribers[topic] {
+		select {
+		case ch <- payload:
+		default:
+		}
+	}
+	return nil
+}
+
+// topic を購読する。
+func (h *memoryHub) Subscribe(topic string) (<-chan []byte, func()) {
+	ch := make(chan []byte, subscriberBufferSize)
+
+	h.mu.Lock()
+	if h.subscribers[topic] == nil {
+		h.subscribers[topic] = make(map[chan []byte]struct{})
+	}
+	h.subscribers[topic][ch] = struct{}{}
+	h.mu.Unlock()
+
+	var once sync.Once
+	unsubscribe := func() {
+		once.Do(func() {
+			h.mu.Lock()
+			defer h.mu.Unlock()
+			delete(h.subscribers[topic], ch)
+			if len(h.subscribers[topic]) == 0 {
+				delete(h.subscribers, topic)
+			}
+			close(ch)
+		})
+	}
+	return ch, unsubscribe
+}
@@ -0,0 +1,87 @@
+package pubsub
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// チャネルからメッセージを1件受け取る（一定時間内に届かない場合は失敗）。
+func receive(t *testing.T, ch <-chan []byte) []byte {
+	t.Helper()
+	select {
+	case msg, ok := <-ch:
+		if !ok {
+			t.Fatalf("channel closed unexpectedly")
+		}
+		return msg
+	case <-time.After(time.Second):
+		t.Fatalf("timed out waiting for message")
+	}
+	return nil
+}
+
+func TestMemoryHub_PublishSubscribe(t *testing.T) {
+	t.Parallel()
+
+	t.Run("同じトピックの購読者全員に配信する", func(t *testing.T) {
+		t.Parallel()
+
+		hub := NewMemoryHub()
+		a, unsubA := hub.Subscribe("user:1")
+		defer unsubA()
+		b, unsubB := hub.Subscribe("user:1")
+		defer unsubB()
+		other, unsubOther := hub.Subscribe("user:2")
+		defer unsubOther()
+
+		if err := hub.Publish(context.Background(), "user:1", []byte("hello")); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got := string(receive(t, a)); got != "hello" {
+			t.Fatalf("unexpected message: %q", got)
+		}
+		if got := string(receive(t, b)); got != "hello" {
+			t.Fatalf("unexpected message: %q", got)
+		}
+		select {
+		case msg := <-other:
+			t.Fatalf("unexpected message for other topic: %q", msg)
+		default:
+		}
+	})
+
+	t.Run("購読解除するとチャネルが閉じられ、以降は配信されない", func(t *testing.T) {
+		t.Parallel()
+
+		hub := NewMemoryHub()
+		ch, unsubscribe := hub.Subscribe("user:1")
+		unsubscribe()
+		// 2回呼んでも問題ない
+		unsubscribe()
+
+		if _, ok := <-ch; ok {
+			t.Fatalf("expected channel to be closed")
+		}
+		if err := hub.Publish(context.Background(), "user:1", []byte("hello")); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("受信が追いつかない購読者への配信はブロックしない", func(t *testing.T) {
+		t.Parallel()
+
+		hub := NewMemoryHub()
+		ch, unsubscribe := hub.Subscribe("user:1")
+		defer unsubscribe()
+
+		for i := 0; i < subscriberBufferSize*2; i++ {
+			if err := hub.Publish(context.Background(), "user:1", nil); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		}
+		if len(ch) != subscriberBufferSize {
+			t.Fatalf("expected %d buffered messages, got %d", subscriberBufferSize, len(ch))
+		}
+	})
+}
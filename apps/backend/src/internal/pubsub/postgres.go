@@ -0,0 +1,122 @@
+package pubsub
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"gorm.io/gorm"
+)
+
+// LISTEN/NOTIFY で使うチャネル名。
+const postgresHubChannel = "cinetag_pubsub"
+
+// NOTIFY の payload の上限（Postgres の既定値 8000 バイト未満）。
+const postgresPayloadLimit = 7900
+
+// LISTEN 接続が切れた場合の再接続の待ち時間の上限。
+const postgresMaxReconnectDelay = 30 * time.Second
+
+// payload が NOTIFY の上限を超える場合のエラー。
+var ErrPayloadTooLarge = errors.New("pubsub: payload too large")
+
+// NOTIFY で送るメッセージ。
+type postgresEnvelope struct {
+	Topic   string `json:"topic"`
+	Payload []byte `json:"payload,omitempty"`
+}
+
+// Postgres の LISTEN/NOTIFY を使う Hub の実装。
+// Publish は NOTIFY するのみで、自インスタンスを含む全インスタンスが LISTEN で受け取ってから購読者に配信する。
+type postgresHub struct {
+	logger *slog.Logger
+	db     *gorm.DB
+	dsn    string
+	local  *memoryHub
+}
+
+// Postgres の LISTEN/NOTIFY を使う Hub を生成する。
+// LISTEN 用に dsn で専用の接続を張り、切断された場合は再接続する（切断中の配信は失われる）。
+// ctx がキャンセルされると LISTEN をやめて接続を閉じる。
+func NewPostgresHub(ctx context.Context, logger *slog.Logger, db *gorm.DB, dsn string) Hub {
+	h := &postgresHub{
+		logger: logger,
+		db:     db,
+		dsn:    dsn,
+		local:  newMemoryHub(),
+	}
+	go h.listen(ctx)
+	return h
+}
+
+// topic の購読者全員（他インスタンスを含む）に payload を配信する。
+func (h *postgresHub) Publish(ctx context.Context, topic string, payload []byte) error {
+	msg, err := json.Marshal(postgresEnvelope{Topic: topic, Payload: payload})
+	if err != nil {
+		return err
+	}
+	if len(msg) > postgresPayloadLimit {
+		return ErrPayloadTooLarge
+	}
+	return h.db.WithContext(ctx).Exec("SELECT pg_notify(?, ?)", postgresHubChannel, string(msg)).Error
+}
+
+// topic を購読する（自インスタンスの購読者として登録する）。
+func (h *postgresHub) Subscribe(topic string) (<-chan []byte, func()) {
+	return h.local.Subscribe(topic)
+}
+
+// LISTEN を続け、切断された場合は待ち時間を延ばしながら再接続する。
+func (h *postgresHub) listen(ctx context.Context) {
+	delay := time.Second
+	for {
+		connected, err := h.listenOnce(ctx)
+		if ctx.Err() != nil {
+			return
+		}
+		if connected {
+			delay = time.Second
+		}
+		h.logger.Warn("pubsub.postgresHub listen failed, reconnecting",
+			slog.Any("error", err),
+			slog.Duration("retry_in", delay),
+		)
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(delay):
+		}
+		delay = min(delay*2, postgresMaxReconnectDelay)
+	}
+}
+
+// 接続して LISTEN し、受け取ったメッセージを自インスタンスの購読者に配信する。
+// 接続できたかどうかと、終了の原因となったエラーを返す。
+func (h *postgresHub) listenOnce(ctx context.Context) (bool, error) {
+	conn, err := pgx.Connect(ctx, h.dsn)
+	if err != nil {
+		return false, err
+	}
+	defer conn.Close(context.Background())
+
+	if _, err := conn.Exec(ctx, "LISTEN "+postgresHubChannel); err != nil {
+		return true, err
+	}
+	h.logger.Debug("pubsub.postgresHub listening", slog.String("channel", postgresHubChannel))
+
+	for {
+		n, err := conn.WaitForNotification(ctx)
+		if err != nil {
+			return true, err
+		}
+		var msg postgresEnvelope
+		if err := json.Unmarshal([]byte(n.Payload), &msg); err != nil {
+			h.logger.Warn("pubsub.postgresHub received invalid message", slog.Any("error", err))
+			continue
+		}
+		_ = h.local.Publish(ctx, msg.Topic, msg.Payload)
+	}
+}
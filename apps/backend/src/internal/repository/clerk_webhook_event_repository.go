@@ -0,0 +1,151 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"cinetag-backend/src/internal/model"
+
+	"gorm.io/gorm"
+)
+
+// 同じ svix-id のメッセージを他のリクエストが処理中の場合のエラー。
+var ErrClerkWebhookEventInProgress = errors.New("clerk webhook event is being processed")
+
+// 確保したメッセージの期限が切れ、他のリクエストに処理を引き継がれた場合のエラー。
+var ErrClerkWebhookClaimLost = errors.New("clerk webhook event claim lost")
+
+// clerk_webhook_events / clerk_user_payloads テーブルの永続化処理を表すインターフェース。
+type ClerkWebhookEventRepository interface {
+	// Claim はメッセージを lockedUntil まで処理中として確保し、確保できた場合に true を返します。
+	// 同じ svix-id が処理済みの場合は何もせず false を返し、他のリクエストが処理中（期限内）の場合は
+	// ErrClerkWebhookEventInProgress を返します。期限を過ぎた処理中のメッセージは lockToken で確保し直します。
+	Claim(ctx context.Context, svixID, eventType, lockToken string, now, lockedUntil time.Time) (bool, error)
+	// MarkProcessed は確保したメッセージを処理済みにします。
+	// lockToken が一致しない（他のリクエストに引き継がれた）場合は ErrClerkWebhookClaimLost を返します。
+	MarkProcessed(ctx context.Context, svixID, lockToken string, at time.Time) error
+	// Release は処理中の記録を削除し、同じメッセージの再送を再び処理できるようにします（処理に失敗した場合に使います）。
+	// lockToken が一致しない場合は何もしません。
+	Release(ctx context.Context, svixID, lockToken string) error
+	// DeleteBefore は before より前に受け取ったメッセージの記録を最大 limit 件削除し、削除件数を返します。
+	DeleteBefore(ctx context.Context, before time.Time, limit int) (int64, error)
+
+	// SaveUserPayload は Clerk ユーザーの最新のペイロードを保存し、保存した場合に true を返します。
+	// 保存済みのペイロードの方が新しい（clerk_updated_at が大きい）場合は保存せず false を返します。
+	SaveUserPayload(ctx context.Context, payload *model.ClerkUserPayload) (bool, error)
+	// FindUserPayload は Clerk ユーザーの保存済みのペイロードを返します。
+	FindUserPayload(ctx context.Context, clerkUserID string) (*model.ClerkUserPayload, error)
+}
+
+type clerkWebhookEventRepository struct {
+	db *gorm.DB
+}
+
+// ClerkWebhookEventRepository を生成する。
+func NewClerkWebhookEventRepository(db *gorm.DB) ClerkWebhookEventRepository {
+	return &clerkWebhookEventRepository{db: db}
+}
+
+// メッセージを処理中として確保する。
+func (r *clerkWebhookEventRepository) Claim(ctx context.Context, svixID, eventType, lockToken string, now, lockedUntil time.Time) (bool, error) {
+	// 同時に再送された場合も、主キー（svix_id）の行ロックにより一方のみが確保できる
+	res := r.db.WithContext(ctx).Exec(`
+INSERT INTO clerk_webhook_events (svix_id, event_type, status, lock_token, locked_until, received_at)
+VALUES (?, ?, ?, ?, ?, ?)
+ON CONFLICT (svix_id) DO UPDATE
+SET lock_token = EXCLUDED.lock_token,
+	locked_until = EXCLUDED.locked_until
+WHERE clerk_webhook_events.status = ?
+	AND clerk_webhook_events.locked_until <= ?`,
+		svixID, eventType, model.ClerkWebhookEventStatusProcessing, lockToken, lockedUntil, now,
+		model.ClerkWebhookEventStatusProcessing, now)
+	if res.Error != nil {
+		return false, res.Error
+	}
+	if res.RowsAffected > 0 {
+		return true, nil
+	}
+
+	var event model.ClerkWebhookEvent
+	if err := r.db.WithContext(ctx).Where("svix_id = ?", svixID).First(&event).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			// 処理中の記録が取り消された直後。再送で処理し直す
+			return false, ErrClerkWebhookEventInProgress
+		}
+		return false, err
+	}
+	if event.Status == model.ClerkWebhookEventStatusProcessed {
+		return false, nil
+	}
+	return false, ErrClerkWebhookEventInProgress
+}
+
+// 確保したメッセージを処理済みにする。
+func (r *clerkWebhookEventRepository) MarkProcessed(ctx context.Context, svixID, lockToken string, at time.Time) error {
+	// 同じリクエスト内で2回呼ばれても、一致した行数が返るため成功となる
+	res := r.db.WithContext(ctx).
+		Model(&model.ClerkWebhookEvent{}).
+		Where("svix_id = ? AND lock_token = ?", svixID, lockToken).
+		Updates(map[string]any{
+			"status":       model.ClerkWebhookEventStatusProcessed,
+			"locked_until": nil,
+			"processed_at": at,
+		})
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return ErrClerkWebhookClaimLost
+	}
+	return nil
+}
+
+// 処理中の記録を削除する。
+func (r *clerkWebhookEventRepository) Release(ctx context.Context, svixID, lockToken string) error {
+	return r.db.WithContext(ctx).
+		Where("svix_id = ? AND lock_token = ? AND status = ?", svixID, lockToken, model.ClerkWebhookEventStatusProcessing).
+		Delete(&model.ClerkWebhookEvent{}).Error
+}
+
+// 古い記録を削除する。
+func (r *clerkWebhookEventRepository) DeleteBefore(ctx context.Context, before time.Time, limit int) (int64, error) {
+	res := r.db.WithContext(ctx).Exec(`
+DELETE FROM clerk_webhook_events
+WHERE svix_id IN (
+	SELECT cwe.svix_id
+	FROM clerk_webhook_events AS cwe
+	WHERE cwe.received_at < ?
+	LIMIT ?
+)`, before, limit)
+	return res.RowsAffected, res.Error
+}
+
+// 最新のペイロードを保存する。
+func (r *clerkWebhookEventRepository) SaveUserPayload(ctx context.Context, payload *model.ClerkUserPayload) (bool, error) {
+	// 同じ時刻のペイロードは上書きする（処理に失敗したメッセージの再送を処理し直すため）
+	res := r.db.WithContext(ctx).Exec(`
+INSERT INTO clerk_user_payloads (clerk_user_id, svix_id, event_type, payload, clerk_updated_at, received_at)
+VALUES (?, ?, ?, ?, ?, NOW())
+ON CONFLICT (clerk_user_id) DO UPDATE
+SET svix_id = EXCLUDED.svix_id,
+	event_type = EXCLUDED.event_type,
+	payload = EXCLUDED.payload,
+	clerk_updated_at = EXCLUDED.clerk_updated_at,
+	received_at = EXCLUDED.received_at
+WHERE clerk_user_payloads.clerk_updated_at <= EXCLUDED.clerk_updated_at`,
+		payload.ClerkUserID, payload.SvixID, payload.EventType, payload.Payload, payload.ClerkUpdatedAt)
+	if res.Error != nil {
+		return false, res.Error
+	}
+	return res.RowsAffected > 0, nil
+}
+
+// 保存済みのペイロードを取得する。
+func (r *clerkWebhookEventRepository) FindUserPayload(ctx context.Context, clerkUserID string) (*model.ClerkUserPayload, error) {
+	var payload model.ClerkUserPayload
+	if err := r.db.WithContext(ctx).Where("clerk_user_id = ?", clerkUserID).First(&payload).Error; err != nil {
+		return nil, err
+	}
+	return &payload, nil
+}
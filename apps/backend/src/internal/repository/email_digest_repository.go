@@ -0,0 +1,158 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"cinetag-backend/src/internal/model"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ダイジェストの配信対象ユーザー（email_digest_settings + users）を表す。
+type EmailDigestRecipientRow struct {
+	UserID      string     `gorm:"column:user_id"`
+	Email       string     `gorm:"column:email"`
+	DisplayName string     `gorm:"column:display_name"`
+	Frequency   string     `gorm:"column:frequency"`
+	LastSentAt  *time.Time `gorm:"column:last_sent_at"`
+	// 連続して送信に失敗した回数
+	FailedAttempts int `gorm:"column:failed_attempts"`
+}
+
+// フォロー中タグに追加された映画（tag_movies + タグ・映画キャッシュ情報）を表す。
+type EmailDigestMovieRow struct {
+	TagID       string    `gorm:"column:tag_id"`
+	TagTitle    string    `gorm:"column:tag_title"`
+	TmdbMovieID int       `gorm:"column:tmdb_movie_id"`
+	MovieTitle  *string   `gorm:"column:movie_title"`
+	CreatedAt   time.Time `gorm:"column:created_at"`
+}
+
+// email_digest_settings テーブルの永続化処理と、ダイジェストに含める映画の取得を表すインターフェース。
+type EmailDigestRepository interface {
+	// FindSetting は指定ユーザーの配信設定を取得します（未設定の場合は gorm.ErrRecordNotFound）。
+	FindSetting(ctx context.Context, userID string) (*model.EmailDigestSetting, error)
+	// UpsertFrequency は配信頻度を設定します（未設定の場合は作成します）。
+	UpsertFrequency(ctx context.Context, userID, frequency string) error
+	// ListDue は配信時期を迎えたユーザーを最大 limit 件返します。
+	// 毎日配信は前回の配信が dailyBefore 以前、毎週配信は weeklyBefore 以前（未配信を含む）のユーザーが対象です。
+	// 退会済みのユーザーと、送信に失敗して次の送信日時が now より後のユーザーは含めません。
+	ListDue(ctx context.Context, dailyBefore, weeklyBefore, now time.Time, limit int) ([]EmailDigestRecipientRow, error)
+	// MarkSent は前回の配信日時を更新し、送信失敗の状態をリセットします。
+	MarkSent(ctx context.Context, userID string, at time.Time) error
+	// MarkFailed は連続した送信失敗の回数を増やし、次に送信を試みる日時を設定します。
+	MarkFailed(ctx context.Context, userID string, nextAttemptAt time.Time) error
+	// ListFollowedTagMovies は指定ユーザーがフォロー中の公開タグに since 以降に追加された映画を新しい順で最大 limit 件返します。
+	// 自分が追加した映画、ブロック・ミュートしたユーザーが追加した映画は含めません。総件数も返します。
+	ListFollowedTagMovies(ctx context.Context, userID string, since time.Time, limit int) ([]EmailDigestMovieRow, int64, error)
+}
+
+type emailDigestRepository struct {
+	db *gorm.DB
+}
+
+// EmailDigestRepository を生成する。
+func NewEmailDigestRepository(db *gorm.DB) EmailDigestRepository {
+	return &emailDigestRepository{db: db}
+}
+
+// 指定ユーザーの配信設定を取得する。
+func (r *emailDigestRepository) FindSetting(ctx context.Context, userID string) (*model.EmailDigestSetting, error) {
+	var setting model.EmailDigestSetting
+	if err := r.db.WithContext(ctx).Where("user_id = ?", userID).First(&setting).Error; err != nil {
+		return nil, err
+	}
+	return &setting, nil
+}
+
+// 配信頻度を設定する。
+func (r *emailDigestRepository) UpsertFrequency(ctx context.Context, userID, frequency string) error {
+	return r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"frequency", "updated_at"}),
+	}).Create(&model.EmailDigestSetting{
+		UserID:    userID,
+		Frequency: frequency,
+		UpdatedAt: time.Now(),
+	}).Error
+}
+
+// 配信時期を迎えたユーザーを返す。
+func (r *emailDigestRepository) ListDue(ctx context.Context, dailyBefore, weeklyBefore, now time.Time, limit int) ([]EmailDigestRecipientRow, error) {
+	var rows []EmailDigestRecipientRow
+	err := r.db.WithContext(ctx).
+		Table("email_digest_settings AS eds").
+		Select("eds.user_id, u.email, u.display_name, eds.frequency, eds.last_sent_at, eds.failed_attempts").
+		Joins("INNER JOIN users AS u ON u.id = eds.user_id").
+		Where("u.deleted_at IS NULL AND u.email <> ''").
+		Where("eds.next_attempt_at IS NULL OR eds.next_attempt_at <= ?", now).
+		Where(`(eds.frequency = ? AND (eds.last_sent_at IS NULL OR eds.last_sent_at <= ?))
+			OR (eds.frequency = ? AND (eds.last_sent_at IS NULL OR eds.last_sent_at <= ?))`,
+			model.EmailDigestFrequencyDaily, dailyBefore,
+			model.EmailDigestFrequencyWeekly, weeklyBefore).
+		Order("eds.last_sent_at ASC NULLS FIRST, eds.user_id ASC").
+		Limit(limit).
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
+
+// 前回の配信日時を更新し、送信失敗の状態をリセットする。
+func (r *emailDigestRepository) MarkSent(ctx context.Context, userID string, at time.Time) error {
+	return r.db.WithContext(ctx).
+		Model(&model.EmailDigestSetting{}).
+		Where("user_id = ?", userID).
+		Updates(map[string]any{
+			"last_sent_at":    at,
+			"failed_attempts": 0,
+			"next_attempt_at": nil,
+		}).Error
+}
+
+// 連続した送信失敗の回数を増やし、次に送信を試みる日時を設定する。
+func (r *emailDigestRepository) MarkFailed(ctx context.Context, userID string, nextAttemptAt time.Time) error {
+	return r.db.WithContext(ctx).
+		Model(&model.EmailDigestSetting{}).
+		Where("user_id = ?", userID).
+		Updates(map[string]any{
+			"failed_attempts": gorm.Expr("failed_attempts + 1"),
+			"next_attempt_at": nextAttemptAt,
+		}).Error
+}
+
+// フォロー中の公開タグに since 以降に追加された映画を返す。
+func (r *emailDigestRepository) ListFollowedTagMovies(ctx context.Context, userID string, since time.Time, limit int) ([]EmailDigestMovieRow, int64, error) {
+	base := func() *gorm.DB {
+		return r.db.WithContext(ctx).
+			Table("tag_movies AS tm").
+			Joins("INNER JOIN tag_followers AS tf ON tf.tag_id = tm.tag_id AND tf.user_id = ?", userID).
+			Joins("INNER JOIN tags AS t ON t.id = tm.tag_id").
+			Where("t.is_public = ? AND tm.created_at >= ? AND tm.added_by_user_id <> ?", true, since, userID).
+			Where(`tm.added_by_user_id NOT IN (SELECT blocked_id FROM user_blocks WHERE blocker_id = ?)
+				AND tm.added_by_user_id NOT IN (SELECT muted_id FROM user_mutes WHERE muter_id = ?)`, userID, userID)
+	}
+
+	var total int64
+	if err := base().Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	if total == 0 {
+		return []EmailDigestMovieRow{}, 0, nil
+	}
+
+	var rows []EmailDigestMovieRow
+	err := base().
+		Select("tm.tag_id, t.title AS tag_title, tm.tmdb_movie_id, mc.title AS movie_title, tm.created_at").
+		Joins("LEFT JOIN movie_cache AS mc ON mc.tmdb_movie_id = tm.tmdb_movie_id").
+		Order("tm.created_at DESC, tm.id DESC").
+		Limit(limit).
+		Scan(&rows).Error
+	if err != nil {
+		return nil, 0, err
+	}
+	return rows, total, nil
+}
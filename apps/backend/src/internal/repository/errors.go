@@ -0,0 +1,17 @@
+package repository
+
+import (
+	"errors"
+
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// PostgreSQL の一意制約違反のエラーコード。
+const pgUniqueViolationCode = "23505"
+
+// 一意制約違反のエラーかどうかを判定する。
+// 事前チェックと挿入の間に競合が起きた場合に、呼び出し側でドメインエラーへ変換するために使う。
+func IsUniqueViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == pgUniqueViolationCode
+}
@@ -0,0 +1,223 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"cinetag-backend/src/internal/model"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// フィード取得時のフィルタ条件を表す。
+// BeforeCreatedAt / BeforeID が指定された場合、その位置より古いイベントのみを返す（カーソルページング）。
+type FeedListFilter struct {
+	ViewerID        string
+	BeforeCreatedAt *time.Time
+	BeforeID        string
+	Limit           int
+}
+
+// ユーザーのアクティビティ一覧取得時のフィルタ条件を表す。
+type FeedActivityFilter struct {
+	ActorUserID string
+	ViewerID    string   // 非公開アカウントへのフォローの表示判定に使う（空文字の場合は未ログイン）
+	EventTypes  []string // 対象とするイベントの種類
+	Offset      int
+	Limit       int
+}
+
+// FeedEventRow はフィード取得時の JOIN 結果を格納するフラット構造体。
+// feed_events JOIN users(actor) LEFT JOIN tags LEFT JOIN tag_movies LEFT JOIN movie_cache LEFT JOIN users(target)
+type FeedEventRow struct {
+	// feed_events
+	ID          string    `gorm:"column:id"`
+	EventType   string    `gorm:"column:event_type"`
+	CreatedAt   time.Time `gorm:"column:created_at"`
+	ActorUserID string    `gorm:"column:actor_user_id"`
+	// actor (users)
+	ActorDisplayID   string  `gorm:"column:actor_display_id"`
+	ActorDisplayName string  `gorm:"column:actor_display_name"`
+	ActorAvatarURL   *string `gorm:"column:actor_avatar_url"`
+	// tag
+	TagID            *string `gorm:"column:tag_id"`
+	TagTitle         *string `gorm:"column:tag_title"`
+	TagCoverImageURL *string `gorm:"column:tag_cover_image_url"`
+	// tag_movies / movie_cache
+	TmdbMovieID     *int    `gorm:"column:tmdb_movie_id"`
+	MovieTitle      *string `gorm:"column:movie_title"`
+	MoviePosterPath *string `gorm:"column:movie_poster_path"`
+	// target (users)
+	TargetUserID      *string `gorm:"column:target_user_id"`
+	TargetDisplayID   *string `gorm:"column:target_display_id"`
+	TargetDisplayName *string `gorm:"column:target_display_name"`
+	TargetAvatarURL   *string `gorm:"column:target_avatar_url"`
+}
+
+// feed_events テーブルの永続化処理を表すインターフェース。
+type FeedEventRepository interface {
+	// Create はフィードイベントを1件作成します。
+	// 同じ source_event_id のイベントが既にある場合は作成しません。
+	Create(ctx context.Context, event *model.FeedEvent) error
+	// ListForViewer は閲覧者のホームフィードに表示するイベントを新しい順で返します。
+	// フォロー中タグへの映画追加と、フォロー中ユーザーによるタグ作成・いいね・フォローが対象です。
+	ListForViewer(ctx context.Context, filter FeedListFilter) ([]FeedEventRow, error)
+	// ListByActor は指定ユーザーのアクティビティを新しい順で返します（公開タグに関するもののみ）。
+	ListByActor(ctx context.Context, filter FeedActivityFilter) ([]FeedEventRow, int64, error)
+}
+
+// フィード・アクティビティの取得で共通の SELECT 句。
+const feedEventRowSelect = `fe.id, fe.event_type, fe.created_at, fe.actor_user_id,
+	actor.display_id AS actor_display_id,
+	actor.display_name AS actor_display_name,
+	actor.avatar_url AS actor_avatar_url,
+	fe.tag_id,
+	t.title AS tag_title,
+	t.cover_image_url AS tag_cover_image_url,
+	tm.tmdb_movie_id,
+	mc.title AS movie_title,
+	mc.poster_path AS movie_poster_path,
+	fe.target_user_id,
+	target.display_id AS target_display_id,
+	target.display_name AS target_display_name,
+	target.avatar_url AS target_avatar_url`
+
+// 取り消されたいいね・フォローを除外する条件。
+// feed_events は "fe"、target users は "target" のエイリアスで参照される前提。
+const feedEventActiveCondition = `(fe.event_type <> 'tag_liked'
+		OR EXISTS (SELECT 1 FROM tag_likes AS tl WHERE tl.tag_id = fe.tag_id AND tl.user_id = fe.actor_user_id))
+	AND (fe.event_type <> 'tag_followed'
+		OR EXISTS (SELECT 1 FROM tag_followers AS tf2 WHERE tf2.tag_id = fe.tag_id AND tf2.user_id = fe.actor_user_id))
+	AND (fe.event_type <> 'user_followed' OR (
+		target.id IS NOT NULL AND target.deleted_at IS NULL
+		AND EXISTS (SELECT 1 FROM user_followers AS uf2 WHERE uf2.follower_id = fe.actor_user_id AND uf2.followee_id = fe.target_user_id)))`
+
+// タグ作成者が退会済みでないことの条件（tags は "t" のエイリアスで参照される前提）。
+const feedTagOwnerActiveCondition = `EXISTS (SELECT 1 FROM users AS owner WHERE owner.id = t.user_id AND owner.deleted_at IS NULL)`
+
+// 閲覧者（1つ目・2つ目のパラメータ）のホームフィードの候補となるイベント。
+// フォロー中ユーザーによるタグ作成・いいね・フォローと、フォロー中タグへの映画追加を、
+// それぞれ (actor_user_id, created_at) / (tag_id, created_at) のインデックスで取得してまとめる（feed_events 全体を走査しない）。
+const feedCandidateEvents = `(
+	SELECT e.* FROM feed_events AS e
+	WHERE e.event_type <> 'tag_movie_added'
+		AND e.actor_user_id IN (SELECT uf.followee_id FROM user_followers AS uf WHERE uf.follower_id = ?)
+	UNION ALL
+	SELECT e.* FROM feed_events AS e
+	WHERE e.event_type = 'tag_movie_added'
+		AND e.tag_id IN (SELECT tf.tag_id FROM tag_followers AS tf WHERE tf.user_id = ?)
+) AS fe`
+
+// 閲覧者（@viewer_id）のフィードに表示するイベントの条件（feedCandidateEvents で絞り込んだ後に適用する）。
+// - 閲覧者がブロック・ミュートしているユーザーと、閲覧者をブロックしているユーザーのイベントは表示しない。
+// feed_events は "fe"、tags は "t"、target users は "target" のエイリアスで参照される前提。
+const feedVisibleCondition = `fe.actor_user_id <> @viewer_id
+	AND fe.actor_user_id NOT IN (SELECT blocked_id FROM user_blocks WHERE blocker_id = @viewer_id)
+	AND fe.actor_user_id NOT IN (SELECT blocker_id FROM user_blocks WHERE blocked_id = @viewer_id)
+	AND fe.actor_user_id NOT IN (SELECT muted_id FROM user_mutes WHERE muter_id = @viewer_id)
+	AND (fe.tag_id IS NULL OR (t.id IS NOT NULL AND (t.is_public = TRUE OR t.user_id = @viewer_id) AND ` + feedTagOwnerActiveCondition + `))
+	AND ` + feedEventActiveCondition + `
+	AND (fe.target_user_id IS NULL OR ` + feedTargetVisibleCondition + `)`
+
+// 非公開アカウントへのフォローを、本人・フォロワー以外に表示しない条件（閲覧者は @viewer_id）。
+const feedTargetVisibleCondition = `(target.is_private = FALSE OR target.id = @viewer_id
+	OR EXISTS (SELECT 1 FROM user_followers AS uf3 WHERE uf3.follower_id = @viewer_id AND uf3.followee_id = target.id))`
+
+type feedEventRepository struct {
+	db *gorm.DB
+}
+
+// FeedEventRepository を生成する。
+func NewFeedEventRepository(db *gorm.DB) FeedEventRepository {
+	return &feedEventRepository{db: db}
+}
+
+// フィードイベントを1件作成する。
+func (r *feedEventRepository) Create(ctx context.Context, event *model.FeedEvent) error {
+	return r.db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(event).Error
+}
+
+// 閲覧者のホームフィードに表示するイベントを新しい順で返す。
+// 取り消されたいいね・フォローや、削除された映画追加は表示しない。
+func (r *feedEventRepository) ListForViewer(ctx context.Context, filter FeedListFilter) ([]FeedEventRow, error) {
+	if filter.Limit <= 0 {
+		return []FeedEventRow{}, nil
+	}
+
+	query := r.joinedQueryFrom(ctx, feedCandidateEvents, filter.ViewerID, filter.ViewerID).
+		Select(feedEventRowSelect).
+		Where(feedVisibleCondition, map[string]any{"viewer_id": filter.ViewerID})
+	if filter.BeforeCreatedAt != nil {
+		query = query.Where("(fe.created_at, fe.id) < (?, ?)", *filter.BeforeCreatedAt, filter.BeforeID)
+	}
+
+	var rows []FeedEventRow
+	err := query.
+		Order("fe.created_at DESC, fe.id DESC").
+		Limit(filter.Limit).
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
+
+// 指定ユーザーのアクティビティを新しい順で返す。
+// 公開タグに関するもののみを対象とし、取り消されたいいね・フォローや、削除された映画追加は含めない。
+func (r *feedEventRepository) ListByActor(ctx context.Context, filter FeedActivityFilter) ([]FeedEventRow, int64, error) {
+	if filter.Limit <= 0 || len(filter.EventTypes) == 0 {
+		return []FeedEventRow{}, 0, nil
+	}
+
+	baseQuery := r.joinedQuery(ctx).
+		Where("fe.actor_user_id = ? AND fe.event_type IN ?", filter.ActorUserID, filter.EventTypes).
+		Where("(fe.tag_id IS NULL OR (t.id IS NOT NULL AND t.is_public = TRUE AND " + feedTagOwnerActiveCondition + "))").
+		Where(feedEventActiveCondition)
+	if filter.ViewerID == "" {
+		baseQuery = baseQuery.Where("(fe.target_user_id IS NULL OR target.is_private = ?)", false)
+	} else {
+		baseQuery = baseQuery.Where(
+			"(fe.target_user_id IS NULL OR "+feedTargetVisibleCondition+")",
+			map[string]any{"viewer_id": filter.ViewerID},
+		)
+	}
+
+	var total int64
+	if err := baseQuery.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	if total == 0 {
+		return []FeedEventRow{}, 0, nil
+	}
+
+	// Count()はSELECTをCOUNT(*)に置き換えるため、Select句を再指定
+	var rows []FeedEventRow
+	err := baseQuery.
+		Select(feedEventRowSelect).
+		Order("fe.created_at DESC, fe.id DESC").
+		Offset(filter.Offset).
+		Limit(filter.Limit).
+		Scan(&rows).Error
+	if err != nil {
+		return nil, 0, err
+	}
+	return rows, total, nil
+}
+
+// フィードイベントに、アクター・タグ・映画・対象ユーザーを結合したクエリを返す。
+// 退会済みユーザーのイベントは含めない。
+func (r *feedEventRepository) joinedQuery(ctx context.Context) *gorm.DB {
+	return r.joinedQueryFrom(ctx, "feed_events AS fe")
+}
+
+// from（"fe" のエイリアスを付けたフィードイベントの集合）に、アクター・タグ・映画・対象ユーザーを結合したクエリを返す。
+func (r *feedEventRepository) joinedQueryFrom(ctx context.Context, from string, args ...any) *gorm.DB {
+	return r.db.WithContext(ctx).
+		Table(from, args...).
+		Joins("INNER JOIN users AS actor ON actor.id = fe.actor_user_id AND actor.deleted_at IS NULL").
+		Joins("LEFT JOIN tags AS t ON t.id = fe.tag_id").
+		Joins("LEFT JOIN tag_movies AS tm ON tm.id = fe.tag_movie_id").
+		Joins("LEFT JOIN movie_cache AS mc ON mc.tmdb_movie_id = tm.tmdb_movie_id").
+		Joins("LEFT JOIN users AS target ON target.id = fe.target_user_id")
+}
@@ -0,0 +1,185 @@
+package repository
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"cinetag-backend/src/internal/model"
+
+	"gorm.io/gorm"
+)
+
+// 管理者によるユーザー一覧の絞り込みに使う状態。
+const (
+	AdminUserStatusActive      = "active"      // 通常状態
+	AdminUserStatusDeactivated = "deactivated" // 本人による退会（猶予期間中）
+	AdminUserStatusSuspended   = "suspended"   // 管理者による利用停止
+	AdminUserStatusPurged      = "purged"      // 完全削除済み
+)
+
+// 管理者によるユーザー一覧の検索条件を表す。
+type AdminUserFilter struct {
+	Query  string // 表示ID・表示名・メールアドレスの部分一致、またはユーザーID・Clerk ユーザーIDの完全一致（空の場合は絞り込まない）
+	Status string // AdminUserStatus*（空の場合は絞り込まない）
+	Role   string // model.UserRole*（空の場合は絞り込まない）
+	Offset int
+	Limit  int
+}
+
+// 管理者によるモデレーション（ユーザーのロール・利用停止、タグの非表示、監査ログ）に関する永続化処理を表すインターフェース。
+// 状態を変更するメソッドは、対象が存在しないか状態が変わっていて更新しなかった場合に gorm.ErrRecordNotFound を返す。
+type ModerationRepository interface {
+	// ListUsers は条件に一致するユーザーを、退会・完全削除済みを含めて登録日時の新しい順に取得します。
+	ListUsers(ctx context.Context, filter AdminUserFilter) ([]*model.User, int64, error)
+	// UpdateUserRole はユーザーのロールを変更します。
+	UpdateUserRole(ctx context.Context, userID, role string, now time.Time) error
+	// MarkUserSuspended はユーザーを利用停止にします（退会状態として扱い、完全削除の予定は変更しません）。
+	MarkUserSuspended(ctx context.Context, userID string, now time.Time) error
+	// MarkUserRestored は利用停止中のユーザーの利用停止を解除します（本人による退会は取り消しません）。
+	MarkUserRestored(ctx context.Context, userID string, now time.Time) error
+	// HideTag は公開タグを非表示（非公開）にします。
+	HideTag(ctx context.Context, tagID string, now time.Time) error
+	// UnhideTag は非表示にしたタグを、非表示にする前の公開範囲に戻します。
+	UnhideTag(ctx context.Context, tagID string, now time.Time) error
+	// CreateAuditLog は管理者による操作の監査ログを記録します。
+	CreateAuditLog(ctx context.Context, entry *model.AdminAuditLog) error
+}
+
+type moderationRepository struct {
+	db *gorm.DB
+}
+
+// ModerationRepository を生成する。
+func NewModerationRepository(db *gorm.DB) ModerationRepository {
+	return &moderationRepository{db: db}
+}
+
+// 条件に一致するユーザーを登録日時の新しい順に取得する。
+func (r *moderationRepository) ListUsers(ctx context.Context, filter AdminUserFilter) ([]*model.User, int64, error) {
+	query := r.db.WithContext(ctx).Model(&model.User{})
+
+	if q := strings.TrimSpace(filter.Query); q != "" {
+		like := "%" + q + "%"
+		query = query.Where("(display_id ILIKE ? OR display_name ILIKE ? OR email ILIKE ? OR clerk_user_id = ? OR id::text = ?)", like, like, like, q, q)
+	}
+	switch filter.Status {
+	case AdminUserStatusActive:
+		query = query.Where("deletion_status = ?", model.UserDeletionStatusActive)
+	case AdminUserStatusDeactivated:
+		query = query.Where("deletion_status = ? AND suspended_at IS NULL", model.UserDeletionStatusDeactivated)
+	case AdminUserStatusSuspended:
+		query = query.Where("suspended_at IS NOT NULL")
+	case AdminUserStatusPurged:
+		query = query.Where("deletion_status = ?", model.UserDeletionStatusPurged)
+	}
+	if filter.Role != "" {
+		query = query.Where("role = ?", filter.Role)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	if total == 0 || filter.Limit <= 0 {
+		return []*model.User{}, total, nil
+	}
+
+	var users []*model.User
+	err := query.
+		Order("created_at DESC, id DESC").
+		Offset(filter.Offset).
+		Limit(filter.Limit).
+		Find(&users).Error
+	if err != nil {
+		return nil, 0, err
+	}
+	return users, total, nil
+}
+
+// ユーザーのロールを変更する。
+func (r *moderationRepository) UpdateUserRole(ctx context.Context, userID, role string, now time.Time) error {
+	return r.db.WithContext(ctx).
+		Model(&model.User{}).
+		Where("id = ?", userID).
+		Updates(map[string]any{
+			"role":       role,
+			"updated_at": now,
+		}).Error
+}
+
+// ユーザーを利用停止にする。
+// deleted_at を設定するため、既存の削除済みチェックによりプロフィールやタグは非表示になる。
+// 本人が退会済みの場合は、退会日時と完全削除の予定をそのまま残す。
+func (r *moderationRepository) MarkUserSuspended(ctx context.Context, userID string, now time.Time) error {
+	res := r.db.WithContext(ctx).
+		Model(&model.User{}).
+		Where("id = ? AND deletion_status <> ?", userID, model.UserDeletionStatusPurged).
+		Updates(map[string]any{
+			"suspended_at":    now,
+			"deleted_at":      gorm.Expr("COALESCE(deleted_at, ?)", now),
+			"deletion_status": model.UserDeletionStatusDeactivated,
+			"updated_at":      now,
+		})
+	return requireRowsAffected(res)
+}
+
+// 利用停止中のユーザーの利用停止を解除する。
+// 本人が退会していた場合（完全削除の予定がある場合）は、退会状態と完全削除の予定をそのまま残す。
+func (r *moderationRepository) MarkUserRestored(ctx context.Context, userID string, now time.Time) error {
+	res := r.db.WithContext(ctx).
+		Model(&model.User{}).
+		Where("id = ? AND deletion_status = ? AND suspended_at IS NOT NULL", userID, model.UserDeletionStatusDeactivated).
+		Updates(map[string]any{
+			"suspended_at":    nil,
+			"deleted_at":      gorm.Expr("CASE WHEN purge_scheduled_at IS NULL THEN NULL ELSE deleted_at END"),
+			"deletion_status": gorm.Expr("CASE WHEN purge_scheduled_at IS NULL THEN ? ELSE deletion_status END", model.UserDeletionStatusActive),
+			"updated_at":      now,
+		})
+	return requireRowsAffected(res)
+}
+
+// 公開タグを非表示にする。非公開にするため、既存の公開範囲のチェックにより一覧や検索に表示されなくなる。
+// 再表示のときに戻せるよう、非表示にする前の公開範囲を残す。
+func (r *moderationRepository) HideTag(ctx context.Context, tagID string, now time.Time) error {
+	res := r.db.WithContext(ctx).
+		Model(&model.Tag{}).
+		Where("id = ? AND is_public = ? AND hidden_at IS NULL", tagID, true).
+		Updates(map[string]any{
+			"is_public":            false,
+			"public_before_hidden": gorm.Expr("is_public"),
+			"hidden_at":            now,
+			"updated_at":           now,
+		})
+	return requireRowsAffected(res)
+}
+
+// 非表示にしたタグを、非表示にする前の公開範囲に戻す（非表示の間に持ち主が非公開にした場合は非公開のまま）。
+func (r *moderationRepository) UnhideTag(ctx context.Context, tagID string, now time.Time) error {
+	res := r.db.WithContext(ctx).
+		Model(&model.Tag{}).
+		Where("id = ? AND hidden_at IS NOT NULL", tagID).
+		Updates(map[string]any{
+			"is_public":            gorm.Expr("COALESCE(public_before_hidden, TRUE)"),
+			"public_before_hidden": nil,
+			"hidden_at":            nil,
+			"updated_at":           now,
+		})
+	return requireRowsAffected(res)
+}
+
+// 管理者による操作の監査ログを記録する。
+func (r *moderationRepository) CreateAuditLog(ctx context.Context, entry *model.AdminAuditLog) error {
+	return r.db.WithContext(ctx).Create(entry).Error
+}
+
+// 更新した行がない場合に gorm.ErrRecordNotFound を返す。
+func requireRowsAffected(res *gorm.DB) error {
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
@@ -0,0 +1,230 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"cinetag-backend/src/internal/model"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ユーザー別の評価一覧取得時のフィルタ条件を表す。
+type MovieRatingListByUserFilter struct {
+	UserID string
+	Offset int
+	Limit  int
+}
+
+// 映画別の評価一覧取得時のフィルタ条件を表す。
+type MovieRatingListByMovieFilter struct {
+	TmdbMovieID    int
+	ViewerID       string   // 非公開アカウントの評価は本人・フォロワーのみに表示する（空文字の場合は未ログイン）
+	ExcludeUserIDs []string // ビューアーがブロックしているユーザーなど、除外するユーザー
+	Offset         int
+	Limit          int
+}
+
+// movie_ratings と movie_cache の結合結果を表す。
+// cache 側は存在しない可能性があるため nullable を許容する。
+type MovieRatingWithCache struct {
+	model.MovieRating
+
+	MovieTitle         *string    `gorm:"column:movie_title"`
+	MovieOriginalTitle *string    `gorm:"column:movie_original_title"`
+	MoviePosterPath    *string    `gorm:"column:movie_poster_path"`
+	MovieReleaseDate   *time.Time `gorm:"column:movie_release_date"`
+	MovieVoteAverage   *float64   `gorm:"column:movie_vote_average"`
+}
+
+// movie_ratings と users の結合結果を表す。
+type MovieRatingWithUser struct {
+	model.MovieRating
+
+	UserDisplayID   string  `gorm:"column:user_display_id"`
+	UserDisplayName string  `gorm:"column:user_display_name"`
+	UserAvatarURL   *string `gorm:"column:user_avatar_url"`
+}
+
+// 映画ごとの評価の集計結果を表す。
+type MovieRatingAggregate struct {
+	Average float64 `gorm:"column:average"`
+	Count   int64   `gorm:"column:rating_count"`
+}
+
+// movie_ratings テーブルの永続化処理を表すインターフェース。
+type MovieRatingRepository interface {
+	// Upsert は評価を作成し、既に存在する場合は上書きします（作成日時は既存のものを rating に設定します）。
+	Upsert(ctx context.Context, rating *model.MovieRating) error
+	// Delete は評価を削除します（該当がない場合は gorm.ErrRecordNotFound）。
+	Delete(ctx context.Context, userID string, tmdbMovieID int) error
+	// FindByUserAndMovie は指定ユーザー・映画の評価を取得します。
+	FindByUserAndMovie(ctx context.Context, userID string, tmdbMovieID int) (*model.MovieRating, error)
+	// ListByUserAndMovieIDs は指定ユーザーの、指定した映画群の評価を取得します。
+	ListByUserAndMovieIDs(ctx context.Context, userID string, tmdbMovieIDs []int) ([]model.MovieRating, error)
+	// ListByUser は指定ユーザーの評価一覧を取得します（更新日時の新しい順）。
+	// movie_cache を LEFT JOIN し、可能なら映画情報も一緒に返します。
+	ListByUser(ctx context.Context, filter MovieRatingListByUserFilter) ([]MovieRatingWithCache, int64, error)
+	// ListByMovie は指定映画の評価一覧を取得します（更新日時の新しい順）。
+	// 退会済みユーザーの評価は含めません。
+	ListByMovie(ctx context.Context, filter MovieRatingListByMovieFilter) ([]MovieRatingWithUser, int64, error)
+	// GetAggregate は指定映画の評価の平均と件数を取得します（退会済みユーザーの評価は除く）。
+	GetAggregate(ctx context.Context, tmdbMovieID int) (*MovieRatingAggregate, error)
+}
+
+type movieRatingRepository struct {
+	db *gorm.DB
+}
+
+// MovieRatingRepository を生成する。
+func NewMovieRatingRepository(db *gorm.DB) MovieRatingRepository {
+	return &movieRatingRepository{db: db}
+}
+
+// 評価を作成・上書きする。
+// 上書きした場合も、RETURNING により既存の評価の ID と作成日時が rating に設定される。
+func (r *movieRatingRepository) Upsert(ctx context.Context, rating *model.MovieRating) error {
+	return r.db.WithContext(ctx).Clauses(
+		clause.OnConflict{
+			Columns:   []clause.Column{{Name: "user_id"}, {Name: "tmdb_movie_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{"rating", "review", "is_spoiler", "updated_at"}),
+		},
+		clause.Returning{Columns: []clause.Column{{Name: "id"}, {Name: "created_at"}}},
+	).Create(rating).Error
+}
+
+// 評価を削除する。
+func (r *movieRatingRepository) Delete(ctx context.Context, userID string, tmdbMovieID int) error {
+	res := r.db.WithContext(ctx).
+		Where("user_id = ? AND tmdb_movie_id = ?", userID, tmdbMovieID).
+		Delete(&model.MovieRating{})
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// 指定ユーザー・映画の評価を取得する。
+func (r *movieRatingRepository) FindByUserAndMovie(ctx context.Context, userID string, tmdbMovieID int) (*model.MovieRating, error) {
+	var rating model.MovieRating
+	err := r.db.WithContext(ctx).
+		Where("user_id = ? AND tmdb_movie_id = ?", userID, tmdbMovieID).
+		First(&rating).Error
+	if err != nil {
+		return nil, err
+	}
+	return &rating, nil
+}
+
+// 指定ユーザーの、指定した映画群の評価を取得する。
+func (r *movieRatingRepository) ListByUserAndMovieIDs(ctx context.Context, userID string, tmdbMovieIDs []int) ([]model.MovieRating, error) {
+	if len(tmdbMovieIDs) == 0 {
+		return []model.MovieRating{}, nil
+	}
+
+	var ratings []model.MovieRating
+	err := r.db.WithContext(ctx).
+		Where("user_id = ? AND tmdb_movie_id IN ?", userID, tmdbMovieIDs).
+		Find(&ratings).Error
+	if err != nil {
+		return nil, err
+	}
+	return ratings, nil
+}
+
+// 指定ユーザーの評価一覧を取得する。
+func (r *movieRatingRepository) ListByUser(ctx context.Context, filter MovieRatingListByUserFilter) ([]MovieRatingWithCache, int64, error) {
+	if filter.Limit <= 0 {
+		return []MovieRatingWithCache{}, 0, nil
+	}
+
+	baseQuery := r.db.WithContext(ctx).
+		Table("movie_ratings AS mr").
+		Where("mr.user_id = ?", filter.UserID)
+
+	var total int64
+	if err := baseQuery.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	if total == 0 {
+		return []MovieRatingWithCache{}, 0, nil
+	}
+
+	// Count()はSELECTをCOUNT(*)に置き換えるため、Select句を再指定
+	var rows []MovieRatingWithCache
+	err := baseQuery.
+		Select(`mr.*,
+		        mc.title AS movie_title, mc.original_title AS movie_original_title, mc.poster_path AS movie_poster_path,
+		        mc.release_date AS movie_release_date, mc.vote_average AS movie_vote_average`).
+		Joins("LEFT JOIN movie_cache AS mc ON mc.tmdb_movie_id = mr.tmdb_movie_id").
+		Order("mr.updated_at DESC, mr.id ASC").
+		Offset(filter.Offset).
+		Limit(filter.Limit).
+		Scan(&rows).Error
+	if err != nil {
+		return nil, 0, err
+	}
+	return rows, total, nil
+}
+
+// 指定映画の評価一覧を取得する。
+func (r *movieRatingRepository) ListByMovie(ctx context.Context, filter MovieRatingListByMovieFilter) ([]MovieRatingWithUser, int64, error) {
+	if filter.Limit <= 0 {
+		return []MovieRatingWithUser{}, 0, nil
+	}
+
+	baseQuery := r.db.WithContext(ctx).
+		Table("movie_ratings AS mr").
+		Joins("INNER JOIN users AS u ON u.id = mr.user_id").
+		Where("mr.tmdb_movie_id = ? AND u.deleted_at IS NULL", filter.TmdbMovieID)
+	if filter.ViewerID == "" {
+		baseQuery = baseQuery.Where("u.is_private = ?", false)
+	} else {
+		baseQuery = baseQuery.Where(
+			"(u.is_private = FALSE OR u.id = @viewer_id OR EXISTS (SELECT 1 FROM user_followers AS uf WHERE uf.follower_id = @viewer_id AND uf.followee_id = u.id))",
+			map[string]any{"viewer_id": filter.ViewerID},
+		)
+	}
+	if len(filter.ExcludeUserIDs) > 0 {
+		baseQuery = baseQuery.Where("mr.user_id NOT IN ?", filter.ExcludeUserIDs)
+	}
+
+	var total int64
+	if err := baseQuery.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	if total == 0 {
+		return []MovieRatingWithUser{}, 0, nil
+	}
+
+	var rows []MovieRatingWithUser
+	err := baseQuery.
+		Select("mr.*, u.display_id AS user_display_id, u.display_name AS user_display_name, u.avatar_url AS user_avatar_url").
+		Order("mr.updated_at DESC, mr.id ASC").
+		Offset(filter.Offset).
+		Limit(filter.Limit).
+		Scan(&rows).Error
+	if err != nil {
+		return nil, 0, err
+	}
+	return rows, total, nil
+}
+
+// 指定映画の評価の平均と件数を取得する。
+func (r *movieRatingRepository) GetAggregate(ctx context.Context, tmdbMovieID int) (*MovieRatingAggregate, error) {
+	var agg MovieRatingAggregate
+	err := r.db.WithContext(ctx).
+		Table("movie_ratings AS mr").
+		Select("COALESCE(AVG(mr.rating), 0) AS average, COUNT(*) AS rating_count").
+		Joins("INNER JOIN users AS u ON u.id = mr.user_id").
+		Where("mr.tmdb_movie_id = ? AND u.deleted_at IS NULL", tmdbMovieID).
+		Scan(&agg).Error
+	if err != nil {
+		return nil, err
+	}
+	return &agg, nil
+}
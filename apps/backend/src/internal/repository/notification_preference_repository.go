@@ -0,0 +1,145 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"cinetag-backend/src/internal/model"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// 映画追加通知をミュートしているタグの情報を表す。
+type NotificationMutedTagRow struct {
+	TagID     string    `gorm:"column:tag_id"`
+	TagTitle  string    `gorm:"column:tag_title"`
+	CreatedAt time.Time `gorm:"column:created_at"`
+}
+
+// notification_preferences / notification_muted_tags テーブルの永続化処理を表すインターフェース。
+type NotificationPreferenceRepository interface {
+	// ListByUser は指定ユーザーの通知の種類ごとの設定を取得します（設定済みの種類のみ）。
+	ListByUser(ctx context.Context, userID string) ([]model.NotificationPreference, error)
+	// ListMutedTags は指定ユーザーが映画追加通知をミュートしているタグ一覧を取得します（ミュート日時の新しい順）。
+	ListMutedTags(ctx context.Context, userID string) ([]NotificationMutedTagRow, error)
+	// UpdateSettings は通知の種類ごとの設定の上書きと、タグの映画追加通知のミュート・解除を1つのトランザクションで行います。
+	// ミュート済みのタグのミュート、ミュートしていないタグの解除は何もしません。
+	UpdateSettings(ctx context.Context, userID string, prefs []model.NotificationPreference, muteTagIDs, unmuteTagIDs []string) error
+	// FilterRecipients は通知先候補のうち、指定の種類の通知を受け取るユーザーIDのみを返します。
+	// tagID が指定された場合、そのタグをミュートしているユーザーも除外します。
+	FilterRecipients(ctx context.Context, userIDs []string, notificationType string, tagID *string) ([]string, error)
+}
+
+type notificationPreferenceRepository struct {
+	db *gorm.DB
+}
+
+// NotificationPreferenceRepository を生成する。
+func NewNotificationPreferenceRepository(db *gorm.DB) NotificationPreferenceRepository {
+	return &notificationPreferenceRepository{db: db}
+}
+
+// 指定ユーザーの通知の種類ごとの設定を取得する。
+func (r *notificationPreferenceRepository) ListByUser(ctx context.Context, userID string) ([]model.NotificationPreference, error) {
+	var prefs []model.NotificationPreference
+	err := r.db.WithContext(ctx).
+		Where("user_id = ?", userID).
+		Order("notification_type ASC").
+		Find(&prefs).Error
+	if err != nil {
+		return nil, err
+	}
+	return prefs, nil
+}
+
+// 指定ユーザーが映画追加通知をミュートしているタグ一覧を取得する。
+func (r *notificationPreferenceRepository) ListMutedTags(ctx context.Context, userID string) ([]NotificationMutedTagRow, error) {
+	var rows []NotificationMutedTagRow
+	err := r.db.WithContext(ctx).
+		Table("notification_muted_tags AS nmt").
+		Select("nmt.tag_id, t.title AS tag_title, nmt.created_at").
+		Joins("INNER JOIN tags AS t ON t.id = nmt.tag_id").
+		Where("nmt.user_id = ?", userID).
+		Order("nmt.created_at DESC").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
+
+// 通知の種類ごとの設定とタグのミュートをまとめて更新する。
+func (r *notificationPreferenceRepository) UpdateSettings(ctx context.Context, userID string, prefs []model.NotificationPreference, muteTagIDs, unmuteTagIDs []string) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if len(prefs) > 0 {
+			err := tx.Clauses(clause.OnConflict{
+				Columns:   []clause.Column{{Name: "user_id"}, {Name: "notification_type"}},
+				DoUpdates: clause.AssignmentColumns([]string{"enabled", "updated_at"}),
+			}).Create(&prefs).Error
+			if err != nil {
+				return err
+			}
+		}
+		for _, tagID := range muteTagIDs {
+			err := tx.Clauses(clause.OnConflict{DoNothing: true}).
+				Create(&model.NotificationMutedTag{UserID: userID, TagID: tagID}).Error
+			if err != nil {
+				return err
+			}
+		}
+		if len(unmuteTagIDs) > 0 {
+			err := tx.Where("user_id = ? AND tag_id IN ?", userID, unmuteTagIDs).
+				Delete(&model.NotificationMutedTag{}).Error
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// 通知先候補のうち、指定の種類の通知を受け取るユーザーIDのみを返す。
+func (r *notificationPreferenceRepository) FilterRecipients(ctx context.Context, userIDs []string, notificationType string, tagID *string) ([]string, error) {
+	if len(userIDs) == 0 {
+		return []string{}, nil
+	}
+
+	// 受け取らない設定のユーザー
+	var excluded []string
+	err := r.db.WithContext(ctx).
+		Model(&model.NotificationPreference{}).
+		Where("user_id IN ? AND notification_type = ? AND enabled = ?", userIDs, notificationType, false).
+		Pluck("user_id", &excluded).Error
+	if err != nil {
+		return nil, err
+	}
+
+	// タグをミュートしているユーザー
+	if tagID != nil {
+		var muted []string
+		err := r.db.WithContext(ctx).
+			Model(&model.NotificationMutedTag{}).
+			Where("user_id IN ? AND tag_id = ?", userIDs, *tagID).
+			Pluck("user_id", &muted).Error
+		if err != nil {
+			return nil, err
+		}
+		excluded = append(excluded, muted...)
+	}
+
+	if len(excluded) == 0 {
+		return userIDs, nil
+	}
+	excludedSet := make(map[string]struct{}, len(excluded))
+	for _, id := range excluded {
+		excludedSet[id] = struct{}{}
+	}
+	recipients := make([]string, 0, len(userIDs))
+	for _, id := range userIDs {
+		if _, ok := excludedSet[id]; !ok {
+			recipients = append(recipients, id)
+		}
+	}
+	return recipients, nil
+}
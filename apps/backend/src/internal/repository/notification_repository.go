@@ -2,11 +2,14 @@ package repository
 
 import (
 	"context"
+	"errors"
+	"slices"
 	"time"
 
 	"cinetag-backend/src/internal/model"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 // NotificationRow は通知一覧取得時の JOIN 結果を格納するフラット構造体。
@@ -17,7 +20,13 @@ type NotificationRow struct {
 	RecipientUserID  string    `gorm:"column:recipient_user_id"`
 	NotificationType string    `gorm:"column:notification_type"`
 	IsRead           bool      `gorm:"column:is_read"`
+	IsArchived       bool      `gorm:"column:is_archived"`
 	CreatedAt        time.Time `gorm:"column:created_at"`
+	UpdatedAt        time.Time `gorm:"column:updated_at"`
+	GroupKey         *string   `gorm:"column:group_key"`
+	StreamSeq        int64     `gorm:"column:stream_seq"`
+	EventCount       int       `gorm:"column:event_count"`
+	Details          []byte    `gorm:"column:details"`
 	// actor (users)
 	ActorUserID      *string `gorm:"column:actor_user_id"`
 	ActorDisplayID   *string `gorm:"column:actor_display_id"`
@@ -30,23 +39,82 @@ type NotificationRow struct {
 	MovieTitle *string `gorm:"column:movie_title"`
 }
 
+// NotificationEventRow はまとめた通知に含まれるイベントの取得結果を格納するフラット構造体。
+// notification_events JOIN users(actor) LEFT JOIN tag_movies LEFT JOIN movie_cache
+type NotificationEventRow struct {
+	NotificationID   string    `gorm:"column:notification_id"`
+	CreatedAt        time.Time `gorm:"column:created_at"`
+	ActorUserID      string    `gorm:"column:actor_user_id"`
+	ActorDisplayID   string    `gorm:"column:actor_display_id"`
+	ActorDisplayName string    `gorm:"column:actor_display_name"`
+	ActorAvatarURL   *string   `gorm:"column:actor_avatar_url"`
+	MovieTitle       *string   `gorm:"column:movie_title"`
+}
+
 // notifications テーブルの永続化処理を表すインターフェース。
 type NotificationRepository interface {
 	// Create は通知を1件作成する。
 	Create(ctx context.Context, notification *model.Notification) error
 	// CreateBatch は通知を一括作成する（フォロワー全員への通知等）。
+	// 受信者・source_event_id が同じ通知が既にある場合、その受信者への通知は作成しない。
 	CreateBatch(ctx context.Context, notifications []*model.Notification) error
+	// CreateGroupedBatch は同じ group_key の通知を一括作成し、受信者・group_key が同じで
+	// 最後のイベント日時が mergeSince 以降の通知が既にある場合はそちらにまとめる。
+	// まとめた場合はイベント数を加算し、アクター・最終イベント日時を更新して未読に戻す。
+	// event.SourceEventID のイベントを既に受け取った受信者は、通知もイベントも変更しない。
+	// 作成・更新した各通知には event をイベントとして記録する。
+	CreateGroupedBatch(ctx context.Context, notifications []*model.Notification, event model.NotificationEvent, mergeSince time.Time) error
+	// ListEvents は指定した通知に含まれるイベントを新しい順で返す。
+	// 受信者がブロック・ミュートしたユーザーによるイベントは含めない。
+	ListEvents(ctx context.Context, notificationIDs []string) ([]NotificationEventRow, error)
 	// ListByRecipient は指定ユーザーの通知一覧を新しい順で返す。
 	// unreadOnly が true の場合、未読通知のみに絞る。
-	ListByRecipient(ctx context.Context, userID string, page, pageSize int, unreadOnly bool) ([]*NotificationRow, int64, error)
-	// CountUnread は未読通知数を返す。
+	// archived が true の場合はアーカイブした通知のみ、false の場合はアーカイブしていない通知のみを返す。
+	ListByRecipient(ctx context.Context, userID string, page, pageSize int, unreadOnly, archived bool) ([]*NotificationRow, int64, error)
+	// ListUpdatedSince は指定ユーザーの通知のうち、stream_seq が afterSeq より後のものを古い順で最大 limit 件返す。
+	// stream_seq は作成・イベントの追加のたびに採番され、受信者ごとにコミット順に増える。
+	// まとめた通知は、イベントが加わるたびに更新されたものとして返る。アーカイブした通知は含めない。
+	ListUpdatedSince(ctx context.Context, userID string, afterSeq int64, limit int) ([]*NotificationRow, error)
+	// LatestStreamSeq は指定ユーザーの通知の stream_seq の最大値を返す（通知がない場合は 0）。
+	LatestStreamSeq(ctx context.Context, userID string) (int64, error)
+	// ListUnreadSince は指定ユーザーの未読通知のうち、since 以降に作成・更新されたものを新しい順で最大 limit 件返す。総件数も返す。
+	// アーカイブした通知は含めない。
+	ListUnreadSince(ctx context.Context, userID string, since time.Time, limit int) ([]*NotificationRow, int64, error)
+	// CountUnread は未読通知数を返す（アーカイブした通知は数えない）。
 	CountUnread(ctx context.Context, userID string) (int64, error)
 	// MarkAsRead は指定の通知を既読にする。recipient_user_id で所有権チェック。
 	MarkAsRead(ctx context.Context, notificationID, userID string) error
 	// MarkAllAsRead は指定ユーザーの全通知を既読にする。
 	MarkAllAsRead(ctx context.Context, userID string) error
+	// SetArchived は指定の通知のアーカイブ状態を切り替える。recipient_user_id で所有権チェック。
+	SetArchived(ctx context.Context, notificationID, userID string, archived bool) error
+	// Delete は指定の通知を削除する。recipient_user_id で所有権チェック。
+	Delete(ctx context.Context, notificationID, userID string) error
+	// DeleteRead は指定ユーザーの既読通知（アーカイブした通知を含む）を削除し、削除件数を返す。
+	DeleteRead(ctx context.Context, userID string) (int64, error)
+	// DeleteReadBefore は最後のイベント日時が before より前の既読通知を最大 limit 件削除し、削除件数を返す。
+	DeleteReadBefore(ctx context.Context, before time.Time, limit int) (int64, error)
+	// DeleteUnreadOverLimit はユーザーごとに新しい順で maxPerUser 件を超える未読通知を最大 limit 件削除し、削除件数を返す。
+	DeleteUnreadOverLimit(ctx context.Context, maxPerUser, limit int) (int64, error)
 }
 
+// 受信者がブロック・ミュートしたユーザーによるイベントを除外する条件。
+// notification_events は "ne"、notifications は "n" のエイリアスで参照される前提。
+const notificationEventVisibleCondition = `ne.actor_user_id NOT IN (SELECT blocked_id FROM user_blocks WHERE blocker_id = n.recipient_user_id)
+	AND ne.actor_user_id NOT IN (SELECT muted_id FROM user_mutes WHERE muter_id = n.recipient_user_id)`
+
+// 受信者がブロック・ミュートしたユーザーによる通知を除外する条件。
+// まとめた通知は、除外されないイベントが1件以上ある場合のみ表示する（最後のアクターだけでは判断しない）。
+// notifications は "n" のエイリアスで参照される前提。
+const notificationActorVisibleCondition = `(CASE WHEN n.group_key IS NULL THEN (n.actor_user_id IS NULL OR (
+	n.actor_user_id NOT IN (SELECT blocked_id FROM user_blocks WHERE blocker_id = n.recipient_user_id)
+	AND n.actor_user_id NOT IN (SELECT muted_id FROM user_mutes WHERE muter_id = n.recipient_user_id)))
+	ELSE EXISTS (SELECT 1 FROM notification_events AS ne WHERE ne.notification_id = n.id AND ` + notificationEventVisibleCondition + `) END)`
+
+// 通知のイベント数。まとめた通知は、除外されないイベントのみを数える。
+const notificationEventCountColumn = `CASE WHEN n.group_key IS NULL THEN n.event_count
+	ELSE (SELECT COUNT(*) FROM notification_events AS ne WHERE ne.notification_id = n.id AND ` + notificationEventVisibleCondition + `) END AS event_count`
+
 type notificationRepository struct {
 	db *gorm.DB
 }
@@ -58,7 +126,7 @@ func NewNotificationRepository(db *gorm.DB) NotificationRepository {
 
 // 通知を1件作成する。
 func (r *notificationRepository) Create(ctx context.Context, notification *model.Notification) error {
-	return r.db.WithContext(ctx).Create(notification).Error
+	return r.CreateBatch(ctx, []*model.Notification{notification})
 }
 
 // 通知を一括作成する。
@@ -66,21 +134,181 @@ func (r *notificationRepository) CreateBatch(ctx context.Context, notifications
 	if len(notifications) == 0 {
 		return nil
 	}
-	return r.db.WithContext(ctx).CreateInBatches(notifications, 100).Error
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := lockNotificationRecipients(tx, notificationRecipientIDs(notifications)); err != nil {
+			return err
+		}
+		return tx.Clauses(clause.OnConflict{DoNothing: true}).CreateInBatches(notifications, 100).Error
+	})
+}
+
+// 受信者ごとに、トランザクションの終了まで通知の書き込みを直列化するアドバイザリロックを取る。
+// stream_seq は採番した順にコミットされるとは限らないため、同じ受信者への書き込みを直列化して
+// 受信者ごとの採番順とコミット順を一致させる（ストリームのカーソルが未コミットの通知を追い越さないようにする）。
+// デッドロックを避けるため、ロックは受信者の順に取る。
+func lockNotificationRecipients(tx *gorm.DB, recipientIDs []string) error {
+	return tx.Exec(`
+SELECT pg_advisory_xact_lock(hashtextextended('notifications:' || recipient_id, 0))
+FROM (SELECT DISTINCT unnest(ARRAY[?]::text[]) AS recipient_id) AS recipients
+ORDER BY recipient_id`, recipientIDs).Error
+}
+
+// 通知の受信者IDを返す。
+func notificationRecipientIDs(notifications []*model.Notification) []string {
+	ids := make([]string, 0, len(notifications))
+	for _, n := range notifications {
+		ids = append(ids, n.RecipientUserID)
+	}
+	return ids
+}
+
+// 同じ group_key の通知を一括作成し、最後のイベント日時が mergeSince 以降の通知が既にある場合はそちらにまとめる。
+// 同じ受信者・group_key への並行した作成が別々の通知にならないよう、受信者ごとのロックを取ってから判定する。
+func (r *notificationRepository) CreateGroupedBatch(ctx context.Context, notifications []*model.Notification, event model.NotificationEvent, mergeSince time.Time) error {
+	if len(notifications) == 0 {
+		return nil
+	}
+	groupKey := notifications[0].GroupKey
+	if groupKey == nil {
+		return errors.New("notification group_key is required")
+	}
+
+	recipientIDs := notificationRecipientIDs(notifications)
+
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := lockNotificationRecipients(tx, recipientIDs); err != nil {
+			return err
+		}
+
+		// 再配信の場合、同じイベントを受け取った受信者はまとめ直さない（時間幅を過ぎた後の再配信も含む）
+		if event.SourceEventID != nil {
+			var delivered []string
+			err := tx.Raw(`
+SELECT n.recipient_user_id
+FROM notification_events AS ne
+INNER JOIN notifications AS n ON n.id = ne.notification_id
+WHERE ne.source_event_id = ? AND n.recipient_user_id IN ?`, *event.SourceEventID, recipientIDs).
+				Scan(&delivered).Error
+			if err != nil {
+				return err
+			}
+			notifications = slices.DeleteFunc(slices.Clone(notifications), func(n *model.Notification) bool {
+				return slices.Contains(delivered, n.RecipientUserID)
+			})
+			if len(notifications) == 0 {
+				return nil
+			}
+			recipientIDs = notificationRecipientIDs(notifications)
+		}
+
+		// 受信者ごとに、時間幅内に最後のイベントがある通知を探す
+		var open []struct {
+			ID              string
+			RecipientUserID string
+		}
+		err := tx.Raw(`
+SELECT DISTINCT ON (n.recipient_user_id) n.id, n.recipient_user_id
+FROM notifications AS n
+WHERE n.recipient_user_id IN ? AND n.group_key = ? AND n.updated_at >= ?
+ORDER BY n.recipient_user_id, n.updated_at DESC, n.id DESC`, recipientIDs, *groupKey, mergeSince).
+			Scan(&open).Error
+		if err != nil {
+			return err
+		}
+		openIDs := make(map[string]string, len(open))
+		for _, o := range open {
+			openIDs[o.RecipientUserID] = o.ID
+		}
+
+		merged := make([]string, 0, len(open))
+		created := make([]*model.Notification, 0, len(notifications)-len(open))
+		for _, n := range notifications {
+			if id, ok := openIDs[n.RecipientUserID]; ok {
+				n.ID = id
+				merged = append(merged, id)
+			} else {
+				created = append(created, n)
+			}
+		}
+
+		if len(merged) > 0 {
+			err := tx.Model(&model.Notification{}).
+				Where("id IN ?", merged).
+				Updates(map[string]any{
+					"event_count":   gorm.Expr("event_count + 1"),
+					"actor_user_id": event.ActorUserID,
+					"updated_at":    event.CreatedAt,
+					"stream_seq":    gorm.Expr("nextval(pg_get_serial_sequence('notifications', 'stream_seq'))"),
+					"is_read":       false,
+					"read_at":       nil,
+					"is_archived":   false,
+					"archived_at":   nil,
+				}).Error
+			if err != nil {
+				return err
+			}
+		}
+		if len(created) > 0 {
+			if err := tx.CreateInBatches(created, 100).Error; err != nil {
+				return err
+			}
+		}
+
+		events := make([]*model.NotificationEvent, 0, len(notifications))
+		for _, n := range notifications {
+			events = append(events, &model.NotificationEvent{
+				NotificationID: n.ID,
+				ActorUserID:    event.ActorUserID,
+				TagMovieID:     event.TagMovieID,
+				SourceEventID:  event.SourceEventID,
+				CreatedAt:      event.CreatedAt,
+			})
+		}
+		return tx.Clauses(clause.OnConflict{DoNothing: true}).CreateInBatches(events, 100).Error
+	})
 }
 
-// 指定ユーザーの通知一覧を新しい順で返す。
-// unreadOnly が true の場合、未読通知のみに絞る。
-func (r *notificationRepository) ListByRecipient(ctx context.Context, userID string, page, pageSize int, unreadOnly bool) ([]*NotificationRow, int64, error) {
+// 指定した通知に含まれるイベントを新しい順で返す。
+func (r *notificationRepository) ListEvents(ctx context.Context, notificationIDs []string) ([]NotificationEventRow, error) {
+	if len(notificationIDs) == 0 {
+		return []NotificationEventRow{}, nil
+	}
+
+	var rows []NotificationEventRow
+	err := r.db.WithContext(ctx).
+		Table("notification_events AS ne").
+		Select(`ne.notification_id, ne.created_at, ne.actor_user_id,
+				actor.display_id AS actor_display_id,
+				actor.display_name AS actor_display_name,
+				actor.avatar_url AS actor_avatar_url,
+				mc.title AS movie_title`).
+		Joins("INNER JOIN notifications AS n ON n.id = ne.notification_id").
+		Joins("INNER JOIN users AS actor ON actor.id = ne.actor_user_id").
+		Joins("LEFT JOIN tag_movies AS tm ON tm.id = ne.tag_movie_id").
+		Joins("LEFT JOIN movie_cache AS mc ON mc.tmdb_movie_id = tm.tmdb_movie_id").
+		Where("ne.notification_id IN ?", notificationIDs).
+		Where(notificationEventVisibleCondition).
+		Order("ne.created_at DESC, ne.id DESC").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
+
+// 指定ユーザーの通知一覧を新しい順（最後のイベント日時順）で返す。
+// unreadOnly が true の場合、未読通知のみに絞る。archived に応じてアーカイブした通知のみ・していない通知のみに絞る。
+func (r *notificationRepository) ListByRecipient(ctx context.Context, userID string, page, pageSize int, unreadOnly, archived bool) ([]*NotificationRow, int64, error) {
 	var total int64
 	offset := (page - 1) * pageSize
 
 	// 総件数
 	countQuery := r.db.WithContext(ctx).
-		Model(&model.Notification{}).
-		Where("recipient_user_id = ?", userID)
+		Table("notifications AS n").
+		Where("n.recipient_user_id = ? AND n.is_archived = ?", userID, archived).
+		Where(notificationActorVisibleCondition)
 	if unreadOnly {
-		countQuery = countQuery.Where("is_read = ?", false)
+		countQuery = countQuery.Where("n.is_read = ?", false)
 	}
 	if err := countQuery.Count(&total).Error; err != nil {
 		return nil, 0, err
@@ -91,26 +319,12 @@ func (r *notificationRepository) ListByRecipient(ctx context.Context, userID str
 
 	// JOINクエリ
 	var rows []*NotificationRow
-	query := r.db.WithContext(ctx).
-		Table("notifications AS n").
-		Select(`n.id, n.recipient_user_id, n.notification_type, n.is_read, n.created_at,
-				n.actor_user_id,
-				actor.display_id AS actor_display_id,
-				actor.display_name AS actor_display_name,
-				actor.avatar_url AS actor_avatar_url,
-				n.tag_id,
-				t.title AS tag_title,
-				mc.title AS movie_title`).
-		Joins("LEFT JOIN users AS actor ON actor.id = n.actor_user_id").
-		Joins("LEFT JOIN tags AS t ON t.id = n.tag_id").
-		Joins("LEFT JOIN tag_movies AS tm ON tm.id = n.tag_movie_id").
-		Joins("LEFT JOIN movie_cache AS mc ON mc.tmdb_movie_id = tm.tmdb_movie_id").
-		Where("n.recipient_user_id = ?", userID)
+	query := r.joinedQuery(ctx, userID).Where("n.is_archived = ?", archived)
 	if unreadOnly {
 		query = query.Where("n.is_read = ?", false)
 	}
 	err := query.
-		Order("n.created_at DESC").
+		Order("n.updated_at DESC, n.id DESC").
 		Limit(pageSize).
 		Offset(offset).
 		Scan(&rows).Error
@@ -122,12 +336,94 @@ func (r *notificationRepository) ListByRecipient(ctx context.Context, userID str
 	return rows, total, nil
 }
 
+// 指定ユーザーの通知のうち、stream_seq が afterSeq より後のものを古い順で返す。
+func (r *notificationRepository) ListUpdatedSince(ctx context.Context, userID string, afterSeq int64, limit int) ([]*NotificationRow, error) {
+	if limit <= 0 {
+		return []*NotificationRow{}, nil
+	}
+
+	var rows []*NotificationRow
+	err := r.joinedQuery(ctx, userID).
+		Where("n.is_archived = ?", false).
+		Where("n.stream_seq > ?", afterSeq).
+		Order("n.stream_seq ASC").
+		Limit(limit).
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
+
+// 指定ユーザーの通知の stream_seq の最大値を返す。
+// 書き込み中の通知は、コミット時にこれより大きい stream_seq を持つ（受信者ごとに書き込みを直列化しているため）。
+func (r *notificationRepository) LatestStreamSeq(ctx context.Context, userID string) (int64, error) {
+	var seq int64
+	err := r.db.WithContext(ctx).
+		Table("notifications").
+		Where("recipient_user_id = ?", userID).
+		Select("COALESCE(MAX(stream_seq), 0)").
+		Scan(&seq).Error
+	return seq, err
+}
+
+// 指定ユーザーの未読通知のうち、since 以降に作成・更新されたものを新しい順で返す。
+func (r *notificationRepository) ListUnreadSince(ctx context.Context, userID string, since time.Time, limit int) ([]*NotificationRow, int64, error) {
+	var total int64
+	err := r.db.WithContext(ctx).
+		Table("notifications AS n").
+		Where("n.recipient_user_id = ? AND n.is_read = ? AND n.is_archived = ? AND n.updated_at >= ?", userID, false, false, since).
+		Where(notificationActorVisibleCondition).
+		Count(&total).Error
+	if err != nil {
+		return nil, 0, err
+	}
+	if total == 0 {
+		return []*NotificationRow{}, 0, nil
+	}
+
+	var rows []*NotificationRow
+	err = r.joinedQuery(ctx, userID).
+		Where("n.is_read = ? AND n.is_archived = ? AND n.updated_at >= ?", false, false, since).
+		Order("n.updated_at DESC, n.id DESC").
+		Limit(limit).
+		Scan(&rows).Error
+	if err != nil {
+		return nil, 0, err
+	}
+	return rows, total, nil
+}
+
+// 指定ユーザー宛ての通知に、アクター・タグ・映画を結合したクエリを返す。
+// 受信者がブロック・ミュートしたユーザーによる通知は含めない。
+func (r *notificationRepository) joinedQuery(ctx context.Context, userID string) *gorm.DB {
+	return r.db.WithContext(ctx).
+		Table("notifications AS n").
+		Select(`n.id, n.recipient_user_id, n.notification_type, n.is_read, n.is_archived, n.created_at,
+				n.updated_at, n.group_key, n.stream_seq, n.details,
+				`+notificationEventCountColumn+`,
+				n.actor_user_id,
+				actor.display_id AS actor_display_id,
+				actor.display_name AS actor_display_name,
+				actor.avatar_url AS actor_avatar_url,
+				n.tag_id,
+				t.title AS tag_title,
+				mc.title AS movie_title`).
+		Joins("LEFT JOIN users AS actor ON actor.id = n.actor_user_id").
+		Joins("LEFT JOIN tags AS t ON t.id = n.tag_id").
+		Joins("LEFT JOIN tag_movies AS tm ON tm.id = n.tag_movie_id").
+		Joins("LEFT JOIN movie_cache AS mc ON mc.tmdb_movie_id = tm.tmdb_movie_id").
+		Where("n.recipient_user_id = ?", userID).
+		Where(notificationActorVisibleCondition)
+}
+
 // 未読通知数を返す（部分インデックスが効く軽量クエリ）。
 func (r *notificationRepository) CountUnread(ctx context.Context, userID string) (int64, error) {
 	var count int64
 	err := r.db.WithContext(ctx).
-		Model(&model.Notification{}).
-		Where("recipient_user_id = ? AND is_read = ?", userID, false).
+		Table("notifications AS n").
+		Where("n.recipient_user_id = ? AND n.is_read = ? AND n.is_archived = ?", userID, false, false).
+		Where(notificationActorVisibleCondition).
 		Count(&count).Error
 	return count, err
 }
@@ -163,3 +459,80 @@ func (r *notificationRepository) MarkAllAsRead(ctx context.Context, userID strin
 			"read_at": now,
 		}).Error
 }
+
+// 指定の通知のアーカイブ状態を切り替える。recipient_user_id で所有権チェック。
+func (r *notificationRepository) SetArchived(ctx context.Context, notificationID, userID string, archived bool) error {
+	var archivedAt *time.Time
+	if archived {
+		now := time.Now()
+		archivedAt = &now
+	}
+	result := r.db.WithContext(ctx).
+		Model(&model.Notification{}).
+		Where("id = ? AND recipient_user_id = ?", notificationID, userID).
+		Updates(map[string]any{
+			"is_archived": archived,
+			"archived_at": archivedAt,
+		})
+
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// 指定の通知を削除する。まとめたイベント（notification_events）は外部キーの CASCADE で削除される。
+func (r *notificationRepository) Delete(ctx context.Context, notificationID, userID string) error {
+	result := r.db.WithContext(ctx).
+		Where("id = ? AND recipient_user_id = ?", notificationID, userID).
+		Delete(&model.Notification{})
+
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// 指定ユーザーの既読通知を削除する。
+func (r *notificationRepository) DeleteRead(ctx context.Context, userID string) (int64, error) {
+	result := r.db.WithContext(ctx).
+		Where("recipient_user_id = ? AND is_read = ?", userID, true).
+		Delete(&model.Notification{})
+	return result.RowsAffected, result.Error
+}
+
+// 保持期間を過ぎた既読通知を削除する。
+func (r *notificationRepository) DeleteReadBefore(ctx context.Context, before time.Time, limit int) (int64, error) {
+	res := r.db.WithContext(ctx).Exec(`
+DELETE FROM notifications
+WHERE id IN (
+	SELECT n.id
+	FROM notifications AS n
+	WHERE n.is_read = true AND n.updated_at < ?
+	LIMIT ?
+)`, before, limit)
+	return res.RowsAffected, res.Error
+}
+
+// ユーザーごとの上限を超えた古い未読通知を削除する。
+func (r *notificationRepository) DeleteUnreadOverLimit(ctx context.Context, maxPerUser, limit int) (int64, error) {
+	res := r.db.WithContext(ctx).Exec(`
+DELETE FROM notifications
+WHERE id IN (
+	SELECT ranked.id
+	FROM (
+		SELECT n.id, ROW_NUMBER() OVER (PARTITION BY n.recipient_user_id ORDER BY n.updated_at DESC, n.id DESC) AS rn
+		FROM notifications AS n
+		WHERE n.is_read = false
+	) AS ranked
+	WHERE ranked.rn > ?
+	LIMIT ?
+)`, maxPerUser, limit)
+	return res.RowsAffected, res.Error
+}
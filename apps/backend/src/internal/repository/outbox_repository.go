@@ -0,0 +1,127 @@
+package repository
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"cinetag-backend/src/internal/model"
+
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// outbox_events テーブルの永続化処理を表すインターフェース。
+type OutboxRepository interface {
+	// Enqueue はイベントをアウトボックスに追加します。
+	// 配信待ちのイベントに同じ idempotency_key のものがある場合や、
+	// 同じ source_event_id・idempotency_key のイベントが既にある場合（配信済みを含む）は追加しません。
+	Enqueue(ctx context.Context, events ...*model.OutboxEvent) error
+	// ClaimDue は配信時期を迎えたイベントを最大 limit 件確保して古い順に返します。
+	// 確保したイベントは lockedUntil まで他のワーカーから取得されず、attempts を1増やし lockToken を設定します。
+	ClaimDue(ctx context.Context, now, lockedUntil time.Time, lockToken string, limit int) ([]*model.OutboxEvent, error)
+	// MarkDone は確保したイベントを配信済みにします（lockToken が一致しない場合は何もしません）。
+	MarkDone(ctx context.Context, id, lockToken string, at time.Time) error
+	// MarkFailed は確保したイベントの配信失敗を記録します（lockToken が一致しない場合は何もしません）。
+	// completedHandlers には成功した配信処理の名前を記録し、nextAttemptAt が nil の場合はリトライせずに破棄済みにします。
+	MarkFailed(ctx context.Context, id, lockToken, lastError string, completedHandlers []string, nextAttemptAt *time.Time) error
+	// DeleteProcessedBefore は before より前に配信済みになったイベントを最大 limit 件削除し、削除件数を返します。
+	// 破棄済みのイベントは調査のため削除しません。
+	DeleteProcessedBefore(ctx context.Context, before time.Time, limit int) (int64, error)
+}
+
+type outboxRepository struct {
+	db *gorm.DB
+}
+
+// OutboxRepository を生成する。
+// 状態変更と同じトランザクションで書き込む場合は、トランザクションの *gorm.DB を渡す。
+func NewOutboxRepository(db *gorm.DB) OutboxRepository {
+	return &outboxRepository{db: db}
+}
+
+// イベントをアウトボックスに追加する。
+func (r *outboxRepository) Enqueue(ctx context.Context, events ...*model.OutboxEvent) error {
+	if len(events) == 0 {
+		return nil
+	}
+	// 配信待ちの idempotency_key と、振り分け元ごとの idempotency_key のどちらの重複も追加しない
+	return r.db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(events).Error
+}
+
+// 配信時期を迎えたイベントを確保して返す。
+// FOR UPDATE SKIP LOCKED により、複数のワーカーが同時に取得しても同じイベントを重複して確保しない。
+func (r *outboxRepository) ClaimDue(ctx context.Context, now, lockedUntil time.Time, lockToken string, limit int) ([]*model.OutboxEvent, error) {
+	events := make([]*model.OutboxEvent, 0)
+	err := r.db.WithContext(ctx).Raw(`
+UPDATE outbox_events
+SET locked_until = ?, lock_token = ?, attempts = attempts + 1
+WHERE id IN (
+	SELECT oe.id
+	FROM outbox_events AS oe
+	WHERE oe.status = ?
+		AND oe.next_attempt_at <= ?
+		AND (oe.locked_until IS NULL OR oe.locked_until <= ?)
+	ORDER BY oe.next_attempt_at ASC, oe.created_at ASC
+	LIMIT ?
+	FOR UPDATE SKIP LOCKED
+)
+RETURNING *`, lockedUntil, lockToken, model.OutboxStatusPending, now, now, limit).
+		Scan(&events).Error
+	if err != nil {
+		return nil, err
+	}
+
+	// RETURNING は順序を保証しないため、作成順に並べ直す
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].CreatedAt.Before(events[j].CreatedAt)
+	})
+	return events, nil
+}
+
+// 確保したイベントを配信済みにする。
+func (r *outboxRepository) MarkDone(ctx context.Context, id, lockToken string, at time.Time) error {
+	return r.db.WithContext(ctx).
+		Model(&model.OutboxEvent{}).
+		Where("id = ? AND lock_token = ?", id, lockToken).
+		Updates(map[string]any{
+			"status":       model.OutboxStatusDone,
+			"processed_at": at,
+			"locked_until": nil,
+			"lock_token":   nil,
+			"last_error":   nil,
+		}).Error
+}
+
+// 確保したイベントの配信失敗を記録する。
+func (r *outboxRepository) MarkFailed(ctx context.Context, id, lockToken, lastError string, completedHandlers []string, nextAttemptAt *time.Time) error {
+	updates := map[string]any{
+		"locked_until":       nil,
+		"lock_token":         nil,
+		"last_error":         lastError,
+		"completed_handlers": datatypes.JSONSlice[string](append([]string{}, completedHandlers...)),
+	}
+	if nextAttemptAt != nil {
+		updates["next_attempt_at"] = *nextAttemptAt
+	} else {
+		updates["status"] = model.OutboxStatusDead
+	}
+	return r.db.WithContext(ctx).
+		Model(&model.OutboxEvent{}).
+		Where("id = ? AND lock_token = ?", id, lockToken).
+		Updates(updates).Error
+}
+
+// 配信済みになってから一定期間が過ぎたイベントを削除する。
+func (r *outboxRepository) DeleteProcessedBefore(ctx context.Context, before time.Time, limit int) (int64, error) {
+	res := r.db.WithContext(ctx).Exec(`
+DELETE FROM outbox_events
+WHERE id IN (
+	SELECT oe.id
+	FROM outbox_events AS oe
+	WHERE oe.status = ? AND oe.processed_at < ?
+	LIMIT ?
+)`, model.OutboxStatusDone, before, limit)
+	return res.RowsAffected, res.Error
+}
@@ -0,0 +1,105 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"cinetag-backend/src/internal/model"
+
+	"gorm.io/gorm"
+)
+
+// ユーザーの期限切れでないトークンが発行数の上限に達している。
+var ErrAccessTokenLimitReached = errors.New("access token limit reached")
+
+// personal_access_tokens テーブルの永続化処理を表すインターフェース。
+type PersonalAccessTokenRepository interface {
+	// Create はトークンを作成します。
+	// ユーザーの期限切れでない（expires_at が now より後の）トークンが既に maxActivePerUser 件ある場合は作成せず、
+	// ErrAccessTokenLimitReached を返します。
+	Create(ctx context.Context, token *model.PersonalAccessToken, maxActivePerUser int64, now time.Time) error
+	// FindByHash はトークンのハッシュからトークンを取得します（存在しない場合は gorm.ErrRecordNotFound）。
+	FindByHash(ctx context.Context, tokenHash string) (*model.PersonalAccessToken, error)
+	// ListByUser は指定ユーザーのトークンを新しい順で返します。
+	ListByUser(ctx context.Context, userID string) ([]*model.PersonalAccessToken, error)
+	// Delete は指定ユーザーのトークンを削除します（該当がない場合は gorm.ErrRecordNotFound）。
+	Delete(ctx context.Context, tokenID, userID string) error
+	// TouchLastUsed は最終利用日時を at に更新します。
+	TouchLastUsed(ctx context.Context, tokenID string, at time.Time) error
+}
+
+type personalAccessTokenRepository struct {
+	db *gorm.DB
+}
+
+// PersonalAccessTokenRepository を生成する。
+func NewPersonalAccessTokenRepository(db *gorm.DB) PersonalAccessTokenRepository {
+	return &personalAccessTokenRepository{db: db}
+}
+
+// 発行数の上限を確認してトークンを作成する。
+// 並行した作成で上限を超えないよう、ユーザーの行をロックしてから件数を数える。
+func (r *personalAccessTokenRepository) Create(ctx context.Context, token *model.PersonalAccessToken, maxActivePerUser int64, now time.Time) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("SELECT 1 FROM users WHERE id = ? FOR UPDATE", token.UserID).Error; err != nil {
+			return err
+		}
+		var count int64
+		err := tx.Model(&model.PersonalAccessToken{}).
+			Where("user_id = ? AND expires_at > ?", token.UserID, now).
+			Count(&count).Error
+		if err != nil {
+			return err
+		}
+		if count >= maxActivePerUser {
+			return ErrAccessTokenLimitReached
+		}
+		return tx.Create(token).Error
+	})
+}
+
+// トークンのハッシュからトークンを取得する。
+func (r *personalAccessTokenRepository) FindByHash(ctx context.Context, tokenHash string) (*model.PersonalAccessToken, error) {
+	var token model.PersonalAccessToken
+	if err := r.db.WithContext(ctx).Where("token_hash = ?", tokenHash).First(&token).Error; err != nil {
+		return nil, err
+	}
+	return &token, nil
+}
+
+// 指定ユーザーのトークンを返す。
+func (r *personalAccessTokenRepository) ListByUser(ctx context.Context, userID string) ([]*model.PersonalAccessToken, error) {
+	tokens := make([]*model.PersonalAccessToken, 0)
+	err := r.db.WithContext(ctx).
+		Where("user_id = ?", userID).
+		Order("created_at DESC").
+		Find(&tokens).Error
+	if err != nil {
+		return nil, err
+	}
+	return tokens, nil
+}
+
+// 指定ユーザーのトークンを削除する。
+func (r *personalAccessTokenRepository) Delete(ctx context.Context, tokenID, userID string) error {
+	res := r.db.WithContext(ctx).
+		Where("id = ? AND user_id = ?", tokenID, userID).
+		Delete(&model.PersonalAccessToken{})
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// 最終利用日時を更新する。
+func (r *personalAccessTokenRepository) TouchLastUsed(ctx context.Context, tokenID string, at time.Time) error {
+	return r.db.WithContext(ctx).
+		Model(&model.PersonalAccessToken{}).
+		Where("id = ?", tokenID).
+		Update("last_used_at", at).
+		Error
+}
@@ -136,7 +136,8 @@ func (r *tagFollowerRepository) ListFollowingTags(ctx context.Context, userID st
 	if err := r.db.WithContext(ctx).
 		Table("tag_followers AS tf").
 		Joins("INNER JOIN tags AS t ON t.id = tf.tag_id").
-		Where("tf.user_id = ? AND t.is_public = ?", userID, true).
+		Joins("JOIN users AS u ON u.id = t.user_id").
+		Where("tf.user_id = ? AND t.is_public = ? AND u.deleted_at IS NULL", userID, true).
 		Count(&total).Error; err != nil {
 		return nil, 0, err
 	}
@@ -156,7 +157,7 @@ func (r *tagFollowerRepository) ListFollowingTags(ctx context.Context, userID st
 				u.display_name AS author, u.display_id AS author_display_id`).
 		Joins("INNER JOIN tag_followers AS tf ON t.id = tf.tag_id").
 		Joins("JOIN users AS u ON u.id = t.user_id").
-		Where("tf.user_id = ? AND t.is_public = ?", userID, true).
+		Where("tf.user_id = ? AND t.is_public = ? AND u.deleted_at IS NULL", userID, true).
 		Order("tf.created_at DESC").
 		Limit(pageSize).
 		Offset(offset).
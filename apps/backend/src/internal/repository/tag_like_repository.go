@@ -87,7 +87,8 @@ func (r *tagLikeRepository) ListLikedTags(ctx context.Context, userID string, pa
 	if err := r.db.WithContext(ctx).
 		Table("tag_likes AS tl").
 		Joins("INNER JOIN tags AS t ON t.id = tl.tag_id").
-		Where("tl.user_id = ?", userID).
+		Joins("JOIN users AS u ON u.id = t.user_id").
+		Where("tl.user_id = ? AND u.deleted_at IS NULL", userID).
 		Count(&total).Error; err != nil {
 		return nil, 0, err
 	}
@@ -106,7 +107,7 @@ func (r *tagLikeRepository) ListLikedTags(ctx context.Context, userID string, pa
 				u.display_name AS author, u.display_id AS author_display_id`).
 		Joins("INNER JOIN tag_likes AS tl ON t.id = tl.tag_id").
 		Joins("JOIN users AS u ON u.id = t.user_id").
-		Where("tl.user_id = ?", userID).
+		Where("tl.user_id = ? AND u.deleted_at IS NULL", userID).
 		Order("tl.created_at DESC").
 		Limit(pageSize).
 		Offset(offset).
@@ -19,7 +19,8 @@ type TagMovieRepository interface {
 	ListRecentByTag(ctx context.Context, tagID string, limit int) ([]model.TagMovie, error)
 	// 指定したタグに紐づく映画を取得する（ページング対応）。
 	// movie_cache を LEFT JOIN し、可能なら映画情報も一緒に返す。
-	ListByTag(ctx context.Context, tagID string, offset, limit int) ([]TagMovieWithCache, int64, error)
+	// excludeUserIDs に含まれるユーザーが追加した映画は除外する。
+	ListByTag(ctx context.Context, tagID string, excludeUserIDs []string, offset, limit int) ([]TagMovieWithCache, int64, error)
 	// タグに映画を追加する。
 	// ユニーク制約違反（tag_movies_unique）の場合は ErrTagMovieAlreadyExists を返す。
 	Create(ctx context.Context, tagMovie *model.TagMovie) error
@@ -28,8 +29,8 @@ type TagMovieRepository interface {
 	// 指定したIDのタグ映画を削除する。
 	Delete(ctx context.Context, tagMovieID string) error
 	// 指定したタグに映画を追加したユーザー（参加者）を取得する。
-	// タグ作成者(ownerID)は除外される。
-	ListContributorsByTag(ctx context.Context, tagID string, ownerID string, limit int) ([]TagContributor, int64, error)
+	// タグ作成者(ownerID)と excludeUserIDs に含まれるユーザーは除外される。
+	ListContributorsByTag(ctx context.Context, tagID string, ownerID string, excludeUserIDs []string, limit int) ([]TagContributor, int64, error)
 }
 
 // タグに映画を追加したユーザー情報です。
@@ -88,7 +89,7 @@ func (r *tagMovieRepository) ListRecentByTag(ctx context.Context, tagID string,
 
 // 指定したタグに紐づく映画を取得する（ページング対応）。
 // movie_cache を LEFT JOIN し、可能なら映画情報も一緒に返す。
-func (r *tagMovieRepository) ListByTag(ctx context.Context, tagID string, offset, limit int) ([]TagMovieWithCache, int64, error) {
+func (r *tagMovieRepository) ListByTag(ctx context.Context, tagID string, excludeUserIDs []string, offset, limit int) ([]TagMovieWithCache, int64, error) {
 	if limit <= 0 {
 		return []TagMovieWithCache{}, 0, nil
 	}
@@ -98,10 +99,13 @@ func (r *tagMovieRepository) ListByTag(ctx context.Context, tagID string, offset
 
 	// total count（tag_movies の件数）
 	var total int64
-	if err := r.db.WithContext(ctx).
+	countQuery := r.db.WithContext(ctx).
 		Model(&model.TagMovie{}).
-		Where("tag_id = ?", tagID).
-		Count(&total).Error; err != nil {
+		Where("tag_id = ?", tagID)
+	if len(excludeUserIDs) > 0 {
+		countQuery = countQuery.Where("added_by_user_id NOT IN ?", excludeUserIDs)
+	}
+	if err := countQuery.Count(&total).Error; err != nil {
 		return nil, 0, err
 	}
 	if total == 0 {
@@ -110,13 +114,17 @@ func (r *tagMovieRepository) ListByTag(ctx context.Context, tagID string, offset
 
 	// list with cache join
 	var rows []TagMovieWithCache
-	err := r.db.WithContext(ctx).
+	query := r.db.WithContext(ctx).
 		Table((model.TagMovie{}).TableName()+" AS tm").
 		Select(`tm.id, tm.tag_id, tm.tmdb_movie_id, tm.added_by_user_id, tm.note, tm.position, tm.created_at,
 		        mc.title AS movie_title, mc.original_title AS movie_original_title, mc.poster_path AS movie_poster_path,
 		        mc.release_date AS movie_release_date, mc.vote_average AS movie_vote_average`).
 		Joins("LEFT JOIN "+(model.MovieCache{}).TableName()+" AS mc ON mc.tmdb_movie_id = tm.tmdb_movie_id").
-		Where("tm.tag_id = ?", tagID).
+		Where("tm.tag_id = ?", tagID)
+	if len(excludeUserIDs) > 0 {
+		query = query.Where("tm.added_by_user_id NOT IN ?", excludeUserIDs)
+	}
+	err := query.
 		Order("tm.position ASC, tm.created_at DESC").
 		Offset(offset).
 		Limit(limit).
@@ -161,7 +169,7 @@ func (r *tagMovieRepository) Delete(ctx context.Context, tagMovieID string) erro
 
 // 指定したタグに映画を追加したユーザー（参加者）を取得する。
 // タグ作成者(ownerID)は除外される。
-func (r *tagMovieRepository) ListContributorsByTag(ctx context.Context, tagID string, ownerID string, limit int) ([]TagContributor, int64, error) {
+func (r *tagMovieRepository) ListContributorsByTag(ctx context.Context, tagID string, ownerID string, excludeUserIDs []string, limit int) ([]TagContributor, int64, error) {
 	if limit <= 0 {
 		limit = 10
 	}
@@ -174,6 +182,9 @@ func (r *tagMovieRepository) ListContributorsByTag(ctx context.Context, tagID st
 		Select("COUNT(DISTINCT added_by_user_id)").
 		Where("tag_id = ?", tagID).
 		Where("added_by_user_id != ?", ownerID)
+	if len(excludeUserIDs) > 0 {
+		countQuery = countQuery.Where("added_by_user_id NOT IN ?", excludeUserIDs)
+	}
 	if err := countQuery.Scan(&total).Error; err != nil {
 		return nil, 0, err
 	}
@@ -183,12 +194,16 @@ func (r *tagMovieRepository) ListContributorsByTag(ctx context.Context, tagID st
 
 	// ユーザー情報を取得（GROUP BYでユニーク化し、最初に追加した日時順でソート）
 	var rows []TagContributor
-	err := r.db.WithContext(ctx).
+	query := r.db.WithContext(ctx).
 		Table((model.TagMovie{}).TableName()+" AS tm").
 		Select("u.id AS user_id, u.display_id, u.display_name, u.avatar_url").
 		Joins("JOIN "+(model.User{}).TableName()+" AS u ON u.id = tm.added_by_user_id").
 		Where("tm.tag_id = ?", tagID).
-		Where("tm.added_by_user_id != ?", ownerID).
+		Where("tm.added_by_user_id != ?", ownerID)
+	if len(excludeUserIDs) > 0 {
+		query = query.Where("tm.added_by_user_id NOT IN ?", excludeUserIDs)
+	}
+	err := query.
 		Group("u.id, u.display_id, u.display_name, u.avatar_url").
 		Order("MIN(tm.created_at) ASC").
 		Limit(limit).
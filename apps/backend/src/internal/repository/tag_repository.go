@@ -62,14 +62,40 @@ type UserTagListFilter struct {
 	Limit         int
 }
 
+// ユーザーのタグに関する集計結果を表す。
+type UserTagStatsRow struct {
+	TagCount              int64 `gorm:"column:tag_count"`
+	MovieCount            int64 `gorm:"column:movie_count"`             // 自分のタグに含まれる映画数（重複を除く）
+	ContributedMovieCount int64 `gorm:"column:contributed_movie_count"` // 他者のタグに追加した映画数
+	LikeCount             int64 `gorm:"column:like_count"`              // 自分のタグが受けたいいね数
+	FollowerCount         int64 `gorm:"column:follower_count"`          // 自分のタグが受けたフォロー数
+}
+
+// ジャンルごとの映画数を表す。
+type GenreCount struct {
+	Name  string `gorm:"column:name"`
+	Count int64  `gorm:"column:movie_count"`
+}
+
+// 公開年代ごとの映画数を表す。
+type DecadeCount struct {
+	Decade int   `gorm:"column:decade"` // 1990 など、年代の最初の年
+	Count  int64 `gorm:"column:movie_count"`
+}
+
 // タグに関する永続化処理を表すインターフェース。
 type TagRepository interface {
 	Create(ctx context.Context, tag *model.Tag) error
 	FindByID(ctx context.Context, id string) (*model.Tag, error)
+	// FindByIDWithActiveOwner は作成者が退会・利用停止中でないタグを取得します（該当しない場合は gorm.ErrRecordNotFound）。
+	FindByIDWithActiveOwner(ctx context.Context, id string) (*model.Tag, error)
 	FindDetailByID(ctx context.Context, id string) (*TagDetailRow, error)
 	UpdateByID(ctx context.Context, id string, patch TagUpdatePatch) error
 	ListPublicTags(ctx context.Context, filter TagListFilter) ([]TagSummary, int64, error)
 	ListTagsByUserID(ctx context.Context, filter UserTagListFilter) ([]TagSummary, int64, error)
+	GetUserTagStats(ctx context.Context, userID string, publicOnly bool) (*UserTagStatsRow, error)
+	ListUserTopGenres(ctx context.Context, userID string, publicOnly bool, limit int) ([]GenreCount, error)
+	ListUserTopDecades(ctx context.Context, userID string, publicOnly bool, limit int) ([]DecadeCount, error)
 }
 
 type tagRepository struct {
@@ -109,6 +135,20 @@ func (r *tagRepository) FindByID(ctx context.Context, id string) (*model.Tag, er
 	return &tag, nil
 }
 
+// 作成者が退会・利用停止中でない指定IDのタグを取得する。
+// FindDetailByID や一覧と同じく、退会の猶予期間中・利用停止中のユーザーのタグは存在しないものとして扱う。
+func (r *tagRepository) FindByIDWithActiveOwner(ctx context.Context, id string) (*model.Tag, error) {
+	var tag model.Tag
+	err := r.db.WithContext(ctx).
+		Joins("JOIN "+(model.User{}).TableName()+" AS u ON u.id = tags.user_id").
+		Where("tags.id = ? AND u.deleted_at IS NULL", id).
+		First(&tag).Error
+	if err != nil {
+		return nil, err
+	}
+	return &tag, nil
+}
+
 // 指定IDのタグの詳細を取得する。
 func (r *tagRepository) FindDetailByID(ctx context.Context, id string) (*TagDetailRow, error) {
 	var row TagDetailRow
@@ -122,7 +162,7 @@ func (r *tagRepository) FindDetailByID(ctx context.Context, id string) (*TagDeta
 				u.id AS owner_id, u.display_id AS owner_display_id,
 				u.display_name AS owner_display_name, u.avatar_url AS owner_avatar_url`).
 		Joins("JOIN "+(model.User{}).TableName()+" AS u ON u.id = t.user_id").
-		Where("t.id = ?", id).
+		Where("t.id = ? AND u.deleted_at IS NULL", id).
 		Scan(&row).
 		Error
 	if err != nil {
@@ -147,7 +187,9 @@ func (r *tagRepository) UpdateByID(ctx context.Context, id string, patch TagUpda
 		updates["cover_image_url"] = *patch.CoverImageURL
 	}
 	if patch.IsPublic != nil {
-		updates["is_public"] = *patch.IsPublic
+		// 管理者が非表示にしている間は非公開のままにし、再表示のときに戻す公開範囲として記録する
+		updates["is_public"] = gorm.Expr("CASE WHEN hidden_at IS NULL THEN ?::boolean ELSE FALSE END", *patch.IsPublic)
+		updates["public_before_hidden"] = gorm.Expr("CASE WHEN hidden_at IS NULL THEN NULL ELSE ?::boolean END", *patch.IsPublic)
 	}
 	if patch.AddMoviePolicy != nil {
 		updates["add_movie_policy"] = *patch.AddMoviePolicy
@@ -173,7 +215,7 @@ func (r *tagRepository) ListPublicTags(ctx context.Context, filter TagListFilter
 	baseQuery := r.db.WithContext(ctx).
 		Table((model.Tag{}).TableName()+" AS t").
 		Joins("JOIN "+(model.User{}).TableName()+" AS u ON u.id = t.user_id").
-		Where("t.is_public = ?", true)
+		Where("t.is_public = ? AND u.deleted_at IS NULL", true)
 
 	if filter.Query != "" {
 		baseQuery = baseQuery.Where("t.title ILIKE ?", "%"+filter.Query+"%")
@@ -252,3 +294,84 @@ func (r *tagRepository) ListTagsByUserID(ctx context.Context, filter UserTagList
 
 	return rows, total, nil
 }
+
+// 指定ユーザーのタグに関する件数を集計する。
+// - publicOnly が true の場合、公開タグのみを対象とする（他者タグへの追加映画も公開タグのみ数える）。
+// - 他者タグへの追加映画は、退会済みユーザーのタグを対象外とする。
+func (r *tagRepository) GetUserTagStats(ctx context.Context, userID string, publicOnly bool) (*UserTagStatsRow, error) {
+	const query = `
+SELECT
+	(SELECT COUNT(*) FROM tags t
+		WHERE t.user_id = @user_id AND (NOT @public_only OR t.is_public)) AS tag_count,
+	(SELECT COUNT(DISTINCT tm.tmdb_movie_id) FROM tag_movies tm
+		JOIN tags t ON t.id = tm.tag_id
+		WHERE t.user_id = @user_id AND (NOT @public_only OR t.is_public)) AS movie_count,
+	(SELECT COUNT(*) FROM tag_movies tm
+		JOIN tags t ON t.id = tm.tag_id
+		JOIN users o ON o.id = t.user_id AND o.deleted_at IS NULL
+		WHERE tm.added_by_user_id = @user_id AND t.user_id <> @user_id AND (NOT @public_only OR t.is_public)) AS contributed_movie_count,
+	(SELECT COUNT(*) FROM tag_likes tl
+		JOIN tags t ON t.id = tl.tag_id
+		WHERE t.user_id = @user_id AND (NOT @public_only OR t.is_public)) AS like_count,
+	(SELECT COUNT(*) FROM tag_followers tf
+		JOIN tags t ON t.id = tf.tag_id
+		WHERE t.user_id = @user_id AND (NOT @public_only OR t.is_public)) AS follower_count`
+
+	var row UserTagStatsRow
+	err := r.db.WithContext(ctx).
+		Raw(query, map[string]any{"user_id": userID, "public_only": publicOnly}).
+		Scan(&row).Error
+	if err != nil {
+		return nil, err
+	}
+	return &row, nil
+}
+
+// 指定ユーザーのタグに含まれる映画を、movie_cache.genres のジャンルごとに集計する（多い順）。
+// movie_cache が未作成の映画は集計対象外となる。
+func (r *tagRepository) ListUserTopGenres(ctx context.Context, userID string, publicOnly bool, limit int) ([]GenreCount, error) {
+	const query = `
+SELECT g.genre->>'name' AS name, COUNT(DISTINCT tm.tmdb_movie_id) AS movie_count
+FROM tag_movies tm
+JOIN tags t ON t.id = tm.tag_id
+JOIN movie_cache mc ON mc.tmdb_movie_id = tm.tmdb_movie_id
+CROSS JOIN LATERAL jsonb_array_elements(mc.genres) AS g(genre)
+WHERE t.user_id = @user_id AND (NOT @public_only OR t.is_public)
+	AND jsonb_typeof(mc.genres) = 'array'
+GROUP BY name
+ORDER BY movie_count DESC, name ASC
+LIMIT @limit`
+
+	var rows []GenreCount
+	err := r.db.WithContext(ctx).
+		Raw(query, map[string]any{"user_id": userID, "public_only": publicOnly, "limit": limit}).
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
+
+// 指定ユーザーのタグに含まれる映画を、movie_cache.release_date の年代ごとに集計する（多い順）。
+// 公開日が不明な映画は集計対象外となる。
+func (r *tagRepository) ListUserTopDecades(ctx context.Context, userID string, publicOnly bool, limit int) ([]DecadeCount, error) {
+	const query = `
+SELECT (EXTRACT(YEAR FROM mc.release_date)::int / 10) * 10 AS decade, COUNT(DISTINCT tm.tmdb_movie_id) AS movie_count
+FROM tag_movies tm
+JOIN tags t ON t.id = tm.tag_id
+JOIN movie_cache mc ON mc.tmdb_movie_id = tm.tmdb_movie_id
+WHERE t.user_id = @user_id AND (NOT @public_only OR t.is_public)
+	AND mc.release_date IS NOT NULL
+GROUP BY decade
+ORDER BY movie_count DESC, decade DESC
+LIMIT @limit`
+
+	var rows []DecadeCount
+	err := r.db.WithContext(ctx).
+		Raw(query, map[string]any{"user_id": userID, "public_only": publicOnly, "limit": limit}).
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
@@ -0,0 +1,116 @@
+package repository
+
+import (
+	"context"
+
+	"cinetag-backend/src/internal/model"
+
+	"gorm.io/gorm"
+)
+
+// user_blocks テーブルの永続化処理を表すインターフェース。
+type UserBlockRepository interface {
+	// Create はブロック関係を作成します。
+	Create(ctx context.Context, blockerID, blockedID string) error
+	// Delete はブロック関係を削除します。
+	Delete(ctx context.Context, blockerID, blockedID string) error
+	// IsBlocking は blockerID が blockedID をブロックしているかチェックします。
+	IsBlocking(ctx context.Context, blockerID, blockedID string) (bool, error)
+	// IsBlockedEither は2ユーザー間にいずれかの方向のブロック関係があるかチェックします。
+	IsBlockedEither(ctx context.Context, userA, userB string) (bool, error)
+	// ListBlocked は指定ユーザーがブロックしているユーザー一覧を取得します（ブロック日時の新しい順）。
+	ListBlocked(ctx context.Context, blockerID string, page, pageSize int) ([]*model.User, int64, error)
+	// ListBlockedIDs は指定ユーザーがブロックしているユーザーIDの一覧を取得します。
+	ListBlockedIDs(ctx context.Context, blockerID string) ([]string, error)
+}
+
+type userBlockRepository struct {
+	db *gorm.DB
+}
+
+// UserBlockRepository を生成する。
+func NewUserBlockRepository(db *gorm.DB) UserBlockRepository {
+	return &userBlockRepository{db: db}
+}
+
+// ブロック関係を作成する。
+func (r *userBlockRepository) Create(ctx context.Context, blockerID, blockedID string) error {
+	block := &model.UserBlock{
+		BlockerID: blockerID,
+		BlockedID: blockedID,
+	}
+	return r.db.WithContext(ctx).Create(block).Error
+}
+
+// ブロック関係を削除する。
+func (r *userBlockRepository) Delete(ctx context.Context, blockerID, blockedID string) error {
+	return r.db.WithContext(ctx).
+		Where("blocker_id = ? AND blocked_id = ?", blockerID, blockedID).
+		Delete(&model.UserBlock{}).Error
+}
+
+// blockerID が blockedID をブロックしているかチェックする。
+func (r *userBlockRepository) IsBlocking(ctx context.Context, blockerID, blockedID string) (bool, error) {
+	var count int64
+	err := r.db.WithContext(ctx).
+		Model(&model.UserBlock{}).
+		Where("blocker_id = ? AND blocked_id = ?", blockerID, blockedID).
+		Count(&count).Error
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// 2ユーザー間にいずれかの方向のブロック関係があるかチェックする。
+func (r *userBlockRepository) IsBlockedEither(ctx context.Context, userA, userB string) (bool, error) {
+	var count int64
+	err := r.db.WithContext(ctx).
+		Model(&model.UserBlock{}).
+		Where("(blocker_id = ? AND blocked_id = ?) OR (blocker_id = ? AND blocked_id = ?)", userA, userB, userB, userA).
+		Count(&count).Error
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// 指定ユーザーがブロックしているユーザー一覧を取得する。
+func (r *userBlockRepository) ListBlocked(ctx context.Context, blockerID string, page, pageSize int) ([]*model.User, int64, error) {
+	var users []*model.User
+	var total int64
+
+	offset := (page - 1) * pageSize
+
+	if err := r.db.WithContext(ctx).
+		Model(&model.UserBlock{}).
+		Where("blocker_id = ?", blockerID).
+		Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	err := r.db.WithContext(ctx).
+		Table("users").
+		Select("users.*").
+		Joins("INNER JOIN user_blocks ON users.id = user_blocks.blocked_id").
+		Where("user_blocks.blocker_id = ?", blockerID).
+		Order("user_blocks.created_at DESC").
+		Limit(pageSize).
+		Offset(offset).
+		Find(&users).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return users, total, nil
+}
+
+// 指定ユーザーがブロックしているユーザーIDの一覧を取得する。
+func (r *userBlockRepository) ListBlockedIDs(ctx context.Context, blockerID string) ([]string, error) {
+	var ids []string
+	err := r.db.WithContext(ctx).
+		Model(&model.UserBlock{}).
+		Where("blocker_id = ?", blockerID).
+		Pluck("blocked_id", &ids).Error
+	return ids, err
+}
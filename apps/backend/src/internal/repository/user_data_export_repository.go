@@ -0,0 +1,300 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"cinetag-backend/src/internal/model"
+
+	"gorm.io/gorm"
+)
+
+// エクスポート対象のタグ内映画（tag_movies + タグ・映画キャッシュ情報）を表す。
+type ExportTagMovieRow struct {
+	ID          string    `gorm:"column:id"`
+	TagID       string    `gorm:"column:tag_id"`
+	TagTitle    string    `gorm:"column:tag_title"`
+	TagOwnerID  string    `gorm:"column:tag_owner_id"`
+	TmdbMovieID int       `gorm:"column:tmdb_movie_id"`
+	MovieTitle  *string   `gorm:"column:movie_title"`
+	AddedByUser string    `gorm:"column:added_by_user_id"`
+	Note        *string   `gorm:"column:note"`
+	Position    int       `gorm:"column:position"`
+	CreatedAt   time.Time `gorm:"column:created_at"`
+}
+
+// エクスポート対象のユーザー間の関係（フォロー・フォロワー）を表す。
+type ExportUserRelationRow struct {
+	UserID      string    `gorm:"column:user_id"`
+	DisplayID   string    `gorm:"column:display_id"`
+	DisplayName string    `gorm:"column:display_name"`
+	CreatedAt   time.Time `gorm:"column:created_at"`
+}
+
+// エクスポート対象のタグとの関係（フォロー・いいね）を表す。
+type ExportTagRelationRow struct {
+	TagID     string    `gorm:"column:tag_id"`
+	TagTitle  string    `gorm:"column:tag_title"`
+	CreatedAt time.Time `gorm:"column:created_at"`
+}
+
+// エクスポート対象の視聴ステータス（user_movie_statuses + 映画キャッシュ情報）を表す。
+type ExportMovieStatusRow struct {
+	TmdbMovieID int        `gorm:"column:tmdb_movie_id"`
+	MovieTitle  *string    `gorm:"column:movie_title"`
+	Status      string     `gorm:"column:status"`
+	WatchedAt   *time.Time `gorm:"column:watched_at"`
+	UpdatedAt   time.Time  `gorm:"column:updated_at"`
+}
+
+// エクスポート対象の映画の評価（movie_ratings + 映画キャッシュ情報）を表す。
+type ExportMovieRatingRow struct {
+	TmdbMovieID int       `gorm:"column:tmdb_movie_id"`
+	MovieTitle  *string   `gorm:"column:movie_title"`
+	Rating      float64   `gorm:"column:rating"`
+	Review      *string   `gorm:"column:review"`
+	IsSpoiler   bool      `gorm:"column:is_spoiler"`
+	CreatedAt   time.Time `gorm:"column:created_at"`
+	UpdatedAt   time.Time `gorm:"column:updated_at"`
+}
+
+// ユーザーに紐づくエクスポート対象データ一式を表す。
+type UserDataSnapshot struct {
+	User          *model.User
+	Tags          []model.Tag
+	TagMovies     []ExportTagMovieRow // 自分のタグ内の映画、および自分が他ユーザーのタグに追加した映画
+	Following     []ExportUserRelationRow
+	Followers     []ExportUserRelationRow
+	FollowingTags []ExportTagRelationRow
+	LikedTags     []ExportTagRelationRow
+	Notifications []model.Notification
+	MovieStatuses []ExportMovieStatusRow
+	MovieRatings  []ExportMovieRatingRow
+}
+
+// user_data_exports テーブルの永続化処理と、エクスポート対象データの取得を表すインターフェース。
+type UserDataExportRepository interface {
+	// Create はエクスポート要求を作成します。
+	Create(ctx context.Context, export *model.UserDataExport) error
+	// FindByID は ID からエクスポートを取得します（生成済みファイルを含む）。
+	FindByID(ctx context.Context, id string) (*model.UserDataExport, error)
+	// FindInProgressByUserID は指定ユーザーの未完了（pending / processing）のエクスポートを取得します。
+	FindInProgressByUserID(ctx context.Context, userID string) (*model.UserDataExport, error)
+	// MarkProcessing は未完了のエクスポートを処理中にします。
+	// 未完了のエクスポートが存在しない場合は gorm.ErrRecordNotFound を返します。
+	MarkProcessing(ctx context.Context, id string) error
+	// MarkCompleted は生成したファイルを保存し、処理中のエクスポートを完了にします。
+	// 処理中のエクスポートが存在しない場合（生成中に失敗にされた場合など）は gorm.ErrRecordNotFound を返します。
+	MarkCompleted(ctx context.Context, id, fileName string, content []byte, completedAt, expiresAt time.Time) error
+	// MarkFailed はエクスポートを失敗にします。
+	MarkFailed(ctx context.Context, id, message string) error
+	// FailStale は createdBefore より前に作成され、未完了のまま残ったエクスポートを失敗にします。
+	FailStale(ctx context.Context, createdBefore time.Time, message string) (int64, error)
+	// DeleteExpiredBefore は保存期限が now を過ぎたエクスポートを最大 limit 件削除し、削除件数を返します。
+	DeleteExpiredBefore(ctx context.Context, now time.Time, limit int) (int64, error)
+	// LoadSnapshot は指定ユーザーに紐づくエクスポート対象データ一式を取得します。
+	LoadSnapshot(ctx context.Context, userID string) (*UserDataSnapshot, error)
+}
+
+type userDataExportRepository struct {
+	db *gorm.DB
+}
+
+// UserDataExportRepository を生成する。
+func NewUserDataExportRepository(db *gorm.DB) UserDataExportRepository {
+	return &userDataExportRepository{db: db}
+}
+
+// エクスポート要求を作成する。
+func (r *userDataExportRepository) Create(ctx context.Context, export *model.UserDataExport) error {
+	return r.db.WithContext(ctx).Create(export).Error
+}
+
+// ID からエクスポートを取得する。
+func (r *userDataExportRepository) FindByID(ctx context.Context, id string) (*model.UserDataExport, error) {
+	var export model.UserDataExport
+	if err := r.db.WithContext(ctx).Where("id = ?", id).First(&export).Error; err != nil {
+		return nil, err
+	}
+	return &export, nil
+}
+
+// 指定ユーザーの未完了のエクスポートを取得する。
+func (r *userDataExportRepository) FindInProgressByUserID(ctx context.Context, userID string) (*model.UserDataExport, error) {
+	var export model.UserDataExport
+	err := r.db.WithContext(ctx).
+		Omit("content").
+		Where("user_id = ? AND status IN ?", userID, []string{model.UserDataExportStatusPending, model.UserDataExportStatusProcessing}).
+		Order("created_at DESC").
+		First(&export).Error
+	if err != nil {
+		return nil, err
+	}
+	return &export, nil
+}
+
+// 未完了のエクスポートを処理中にする。
+func (r *userDataExportRepository) MarkProcessing(ctx context.Context, id string) error {
+	res := r.db.WithContext(ctx).
+		Model(&model.UserDataExport{}).
+		Where("id = ? AND status IN ?", id, []string{model.UserDataExportStatusPending, model.UserDataExportStatusProcessing}).
+		Update("status", model.UserDataExportStatusProcessing)
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// 生成したファイルを保存し、エクスポートを完了にする。
+func (r *userDataExportRepository) MarkCompleted(ctx context.Context, id, fileName string, content []byte, completedAt, expiresAt time.Time) error {
+	res := r.db.WithContext(ctx).
+		Model(&model.UserDataExport{}).
+		Where("id = ? AND status = ?", id, model.UserDataExportStatusProcessing).
+		Updates(map[string]any{
+			"status":       model.UserDataExportStatusCompleted,
+			"file_name":    fileName,
+			"content":      content,
+			"completed_at": completedAt,
+			"expires_at":   expiresAt,
+		})
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// エクスポートを失敗にする。
+func (r *userDataExportRepository) MarkFailed(ctx context.Context, id, message string) error {
+	return r.db.WithContext(ctx).
+		Model(&model.UserDataExport{}).
+		Where("id = ?", id).
+		Updates(map[string]any{
+			"status":        model.UserDataExportStatusFailed,
+			"error_message": message,
+		}).Error
+}
+
+// 未完了のまま残ったエクスポートを失敗にする。
+func (r *userDataExportRepository) FailStale(ctx context.Context, createdBefore time.Time, message string) (int64, error) {
+	res := r.db.WithContext(ctx).
+		Model(&model.UserDataExport{}).
+		Where("status IN ? AND created_at < ?", []string{model.UserDataExportStatusPending, model.UserDataExportStatusProcessing}, createdBefore).
+		Updates(map[string]any{
+			"status":        model.UserDataExportStatusFailed,
+			"error_message": message,
+		})
+	return res.RowsAffected, res.Error
+}
+
+// 保存期限を過ぎたエクスポートを削除する。
+func (r *userDataExportRepository) DeleteExpiredBefore(ctx context.Context, now time.Time, limit int) (int64, error) {
+	res := r.db.WithContext(ctx).Exec(`
+DELETE FROM user_data_exports
+WHERE id IN (
+	SELECT ude.id
+	FROM user_data_exports AS ude
+	WHERE ude.expires_at < ?
+	LIMIT ?
+)`, now, limit)
+	return res.RowsAffected, res.Error
+}
+
+// 指定ユーザーに紐づくエクスポート対象データ一式を取得する。
+func (r *userDataExportRepository) LoadSnapshot(ctx context.Context, userID string) (*UserDataSnapshot, error) {
+	db := r.db.WithContext(ctx)
+	snapshot := &UserDataSnapshot{}
+
+	var user model.User
+	if err := db.Where("id = ?", userID).First(&user).Error; err != nil {
+		return nil, err
+	}
+	snapshot.User = &user
+
+	if err := db.Where("user_id = ?", userID).Order("created_at ASC").Find(&snapshot.Tags).Error; err != nil {
+		return nil, err
+	}
+
+	err := db.Table("tag_movies AS tm").
+		Select(`tm.id, tm.tag_id, t.title AS tag_title, t.user_id AS tag_owner_id, tm.tmdb_movie_id,
+		        mc.title AS movie_title, tm.added_by_user_id, tm.note, tm.position, tm.created_at`).
+		Joins("INNER JOIN tags AS t ON t.id = tm.tag_id").
+		Joins("LEFT JOIN movie_cache AS mc ON mc.tmdb_movie_id = tm.tmdb_movie_id").
+		Where("t.user_id = ? OR tm.added_by_user_id = ?", userID, userID).
+		Order("tm.tag_id ASC, tm.position ASC, tm.created_at ASC").
+		Scan(&snapshot.TagMovies).Error
+	if err != nil {
+		return nil, err
+	}
+
+	err = db.Table("user_followers AS uf").
+		Select("u.id AS user_id, u.display_id, u.display_name, uf.created_at").
+		Joins("INNER JOIN users AS u ON u.id = uf.followee_id").
+		Where("uf.follower_id = ?", userID).
+		Order("uf.created_at ASC").
+		Scan(&snapshot.Following).Error
+	if err != nil {
+		return nil, err
+	}
+
+	err = db.Table("user_followers AS uf").
+		Select("u.id AS user_id, u.display_id, u.display_name, uf.created_at").
+		Joins("INNER JOIN users AS u ON u.id = uf.follower_id").
+		Where("uf.followee_id = ?", userID).
+		Order("uf.created_at ASC").
+		Scan(&snapshot.Followers).Error
+	if err != nil {
+		return nil, err
+	}
+
+	err = db.Table("tag_followers AS tf").
+		Select("t.id AS tag_id, t.title AS tag_title, tf.created_at").
+		Joins("INNER JOIN tags AS t ON t.id = tf.tag_id").
+		Where("tf.user_id = ?", userID).
+		Order("tf.created_at ASC").
+		Scan(&snapshot.FollowingTags).Error
+	if err != nil {
+		return nil, err
+	}
+
+	err = db.Table("tag_likes AS tl").
+		Select("t.id AS tag_id, t.title AS tag_title, tl.created_at").
+		Joins("INNER JOIN tags AS t ON t.id = tl.tag_id").
+		Where("tl.user_id = ?", userID).
+		Order("tl.created_at ASC").
+		Scan(&snapshot.LikedTags).Error
+	if err != nil {
+		return nil, err
+	}
+
+	if err := db.Where("recipient_user_id = ?", userID).Order("created_at ASC").Find(&snapshot.Notifications).Error; err != nil {
+		return nil, err
+	}
+
+	err = db.Table("user_movie_statuses AS ums").
+		Select("ums.tmdb_movie_id, mc.title AS movie_title, ums.status, ums.watched_at, ums.updated_at").
+		Joins("LEFT JOIN movie_cache AS mc ON mc.tmdb_movie_id = ums.tmdb_movie_id").
+		Where("ums.user_id = ?", userID).
+		Order("ums.updated_at ASC").
+		Scan(&snapshot.MovieStatuses).Error
+	if err != nil {
+		return nil, err
+	}
+
+	err = db.Table("movie_ratings AS mr").
+		Select("mr.tmdb_movie_id, mc.title AS movie_title, mr.rating, mr.review, mr.is_spoiler, mr.created_at, mr.updated_at").
+		Joins("LEFT JOIN movie_cache AS mc ON mc.tmdb_movie_id = mr.tmdb_movie_id").
+		Where("mr.user_id = ?", userID).
+		Order("mr.created_at ASC").
+		Scan(&snapshot.MovieRatings).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return snapshot, nil
+}
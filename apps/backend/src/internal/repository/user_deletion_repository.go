@@ -0,0 +1,274 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"cinetag-backend/src/internal/model"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// 退会時に保存する、完全削除時のデータの扱いを表す。
+type UserDeletionPolicy struct {
+	OwnedTags        string  // model.OwnedTagsPolicy*
+	TransferToUserID *string // OwnedTags が transfer の場合の譲渡先ユーザーID
+	Contributions    string  // model.ContributionsPolicy*
+}
+
+// 退会ライフサイクル（退会・再開・完全削除）に関する永続化処理を表すインターフェース。
+// 完全削除は複数テーブルにまたがるため、ユーザー単位の操作をこのリポジトリにまとめる。
+type UserDeletionRepository interface {
+	// MarkDeactivated はユーザーを退会状態にし、完全削除予定日時とポリシーを保存します。
+	MarkDeactivated(ctx context.Context, userID string, now, purgeAt time.Time, policy UserDeletionPolicy) error
+	// FindForUpdate は指定ユーザーを取得し、トランザクションの終了まで行をロックします。
+	// 退会の取り消しと完全削除が同時に実行されないよう、状態を確認する前に呼び出します。
+	FindForUpdate(ctx context.Context, userID string) (*model.User, error)
+	// MarkReactivated は猶予期間中の退会状態のユーザー（利用停止中を除く）を通常状態に戻します。
+	// 該当しない場合は gorm.ErrRecordNotFound を返します。
+	MarkReactivated(ctx context.Context, userID string, now time.Time) error
+	// ListDueForPurge は完全削除予定日時を過ぎた退会済みユーザーを取得します（予定日時の古い順）。
+	ListDueForPurge(ctx context.Context, now time.Time, limit int) ([]*model.User, error)
+	// DeleteContributionsToOthersTags は他者のタグに指定ユーザーが追加した映画を削除します。
+	DeleteContributionsToOthersTags(ctx context.Context, userID string) error
+	// TransferOwnedTags は指定ユーザーが所有するタグを譲渡先ユーザーに移します。
+	TransferOwnedTags(ctx context.Context, fromUserID, toUserID string, now time.Time) error
+	// DeleteOwnedTags は指定ユーザーが所有するタグを、タグ内の映画・フォロー・いいねを含めて削除します。
+	DeleteOwnedTags(ctx context.Context, userID string) error
+	// DeleteUserActivity は指定ユーザーのいいね・フォロー・ブロック・ミュート・通知などを全て削除します。
+	// 他のユーザーとまとめた通知からは指定ユーザーのイベントのみを外します。
+	DeleteUserActivity(ctx context.Context, userID string) error
+	// MarkPurged はユーザーを匿名化し、完全削除済みにします。
+	MarkPurged(ctx context.Context, userID string, now time.Time, anonymizedEmail string) error
+}
+
+type userDeletionRepository struct {
+	db *gorm.DB
+}
+
+// UserDeletionRepository を生成する。
+func NewUserDeletionRepository(db *gorm.DB) UserDeletionRepository {
+	return &userDeletionRepository{db: db}
+}
+
+// ユーザーを退会状態にする。
+// deleted_at を設定するため、既存の削除済みチェックによりプロフィールやタグは非表示になる。
+func (r *userDeletionRepository) MarkDeactivated(ctx context.Context, userID string, now, purgeAt time.Time, policy UserDeletionPolicy) error {
+	updates := map[string]any{
+		"deleted_at":           now,
+		"deletion_status":      model.UserDeletionStatusDeactivated,
+		"purge_scheduled_at":   purgeAt,
+		"owned_tags_policy":    policy.OwnedTags,
+		"tag_transfer_user_id": policy.TransferToUserID,
+		"contributions_policy": policy.Contributions,
+		"updated_at":           now,
+	}
+
+	return r.db.WithContext(ctx).
+		Model(&model.User{}).
+		Where("id = ?", userID).
+		Updates(updates).
+		Error
+}
+
+// 指定ユーザーを取得し、行をロックする。
+func (r *userDeletionRepository) FindForUpdate(ctx context.Context, userID string) (*model.User, error) {
+	var u model.User
+	err := r.db.WithContext(ctx).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id = ?", userID).
+		First(&u).Error
+	if err != nil {
+		return nil, err
+	}
+	return &u, nil
+}
+
+// 猶予期間中の退会状態のユーザーを通常状態に戻す。
+func (r *userDeletionRepository) MarkReactivated(ctx context.Context, userID string, now time.Time) error {
+	updates := map[string]any{
+		"deleted_at":           nil,
+		"deletion_status":      model.UserDeletionStatusActive,
+		"purge_scheduled_at":   nil,
+		"owned_tags_policy":    nil,
+		"tag_transfer_user_id": nil,
+		"contributions_policy": nil,
+		"clerk_display_name":   nil,
+		"clerk_username":       nil,
+		"updated_at":           now,
+	}
+
+	res := r.db.WithContext(ctx).
+		Model(&model.User{}).
+		Where("id = ? AND deletion_status = ? AND suspended_at IS NULL", userID, model.UserDeletionStatusDeactivated).
+		Where("purge_scheduled_at IS NULL OR purge_scheduled_at > ?", now).
+		Updates(updates)
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// 完全削除予定日時を過ぎた退会済みユーザーを取得する。
+func (r *userDeletionRepository) ListDueForPurge(ctx context.Context, now time.Time, limit int) ([]*model.User, error) {
+	var users []*model.User
+	err := r.db.WithContext(ctx).
+		Where("deletion_status = ? AND purge_scheduled_at <= ?", model.UserDeletionStatusDeactivated, now).
+		Order("purge_scheduled_at ASC").
+		Limit(limit).
+		Find(&users).Error
+	if err != nil {
+		return nil, err
+	}
+	return users, nil
+}
+
+// 他者のタグに指定ユーザーが追加した映画を削除する。
+// 関連する通知は notifications.tag_movie_id の外部キーにより削除される。
+func (r *userDeletionRepository) DeleteContributionsToOthersTags(ctx context.Context, userID string) error {
+	ownTags := r.db.Model(&model.Tag{}).Select("id").Where("user_id = ?", userID)
+	return r.db.WithContext(ctx).
+		Where("added_by_user_id = ? AND tag_id NOT IN (?)", userID, ownTags).
+		Delete(&model.TagMovie{}).Error
+}
+
+// 指定ユーザーが所有するタグを譲渡先ユーザーに移す。
+// 譲渡前のオーナーが登録したタグごとの Webhook は、譲渡先のアクティビティを配信しないよう削除する。
+func (r *userDeletionRepository) TransferOwnedTags(ctx context.Context, fromUserID, toUserID string, now time.Time) error {
+	db := r.db.WithContext(ctx)
+	ownTags := r.db.Model(&model.Tag{}).Select("id").Where("user_id = ?", fromUserID)
+	if err := db.Where("tag_id IN (?)", ownTags).Delete(&model.Webhook{}).Error; err != nil {
+		return err
+	}
+	return db.
+		Model(&model.Tag{}).
+		Where("user_id = ?", fromUserID).
+		Updates(map[string]any{
+			"user_id":    toUserID,
+			"updated_at": now,
+		}).Error
+}
+
+// 指定ユーザーが所有するタグを削除する。
+// tag_movies / tag_followers / tag_likes は外部キーを持たないため明示的に削除する。
+func (r *userDeletionRepository) DeleteOwnedTags(ctx context.Context, userID string) error {
+	db := r.db.WithContext(ctx)
+	ownTags := r.db.Model(&model.Tag{}).Select("id").Where("user_id = ?", userID)
+
+	if err := db.Where("tag_id IN (?)", ownTags).Delete(&model.TagMovie{}).Error; err != nil {
+		return err
+	}
+	if err := db.Where("tag_id IN (?)", ownTags).Delete(&model.TagFollower{}).Error; err != nil {
+		return err
+	}
+	if err := db.Where("tag_id IN (?)", ownTags).Delete(&model.TagLike{}).Error; err != nil {
+		return err
+	}
+	return db.Where("user_id = ?", userID).Delete(&model.Tag{}).Error
+}
+
+// 指定ユーザーのいいね・フォロー・ブロック・ミュート・通知などを全て削除する。
+func (r *userDeletionRepository) DeleteUserActivity(ctx context.Context, userID string) error {
+	db := r.db.WithContext(ctx)
+
+	if err := r.detachFromGroupedNotifications(ctx, userID); err != nil {
+		return err
+	}
+
+	deletions := []struct {
+		model any
+		query string
+	}{
+		{&model.TagLike{}, "user_id = @id"},
+		{&model.TagFollower{}, "user_id = @id"},
+		{&model.UserFollower{}, "follower_id = @id OR followee_id = @id"},
+		{&model.UserFollowRequest{}, "requester_id = @id OR target_id = @id"},
+		{&model.UserBlock{}, "blocker_id = @id OR blocked_id = @id"},
+		{&model.UserMute{}, "muter_id = @id OR muted_id = @id"},
+		{&model.NotificationEvent{}, "actor_user_id = @id"},
+		{&model.Notification{}, "recipient_user_id = @id OR actor_user_id = @id"},
+		{&model.FeedEvent{}, "actor_user_id = @id OR target_user_id = @id"},
+		{&model.NotificationPreference{}, "user_id = @id"},
+		{&model.NotificationMutedTag{}, "user_id = @id"},
+		{&model.EmailDigestSetting{}, "user_id = @id"},
+		{&model.UserDisplayIDHistory{}, "user_id = @id"},
+		{&model.UserDataExport{}, "user_id = @id"},
+		{&model.UserMovieStatus{}, "user_id = @id"},
+		{&model.MovieRating{}, "user_id = @id"},
+		{&model.PersonalAccessToken{}, "user_id = @id"},
+		// 退会したユーザーは匿名化して残すため、外部キーによる削除は行われない（配信ログは外部キーにより削除される）
+		{&model.Webhook{}, "user_id = @id"},
+		// 再同期用に保存している Clerk のペイロード（メールアドレス等を含む）
+		{&model.ClerkUserPayload{}, "clerk_user_id = (SELECT clerk_user_id FROM users WHERE id = @id)"},
+	}
+	for _, d := range deletions {
+		if err := db.Where(d.query, map[string]any{"id": userID}).Delete(d.model).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// まとめた通知から指定ユーザーのイベントを外す。
+// 指定ユーザーのイベントのみの通知は削除し、他のユーザーのイベントが残る通知はイベント数を減らして
+// 最後のアクターを残ったイベントのアクターに置き換える（通知ごと削除すると他のユーザーのイベントも消えるため）。
+func (r *userDeletionRepository) detachFromGroupedNotifications(ctx context.Context, userID string) error {
+	db := r.db.WithContext(ctx)
+	args := map[string]any{"id": userID}
+
+	err := db.Exec(`
+DELETE FROM notifications AS n
+WHERE n.group_key IS NOT NULL
+	AND n.id IN (SELECT notification_id FROM notification_events WHERE actor_user_id = @id)
+	AND NOT EXISTS (SELECT 1 FROM notification_events AS ne WHERE ne.notification_id = n.id AND ne.actor_user_id <> @id)`, args).Error
+	if err != nil {
+		return err
+	}
+
+	return db.Exec(`
+UPDATE notifications AS n
+SET event_count = GREATEST(n.event_count - own.event_count, 1),
+	actor_user_id = CASE WHEN n.actor_user_id = @id THEN (
+		SELECT ne.actor_user_id FROM notification_events AS ne
+		WHERE ne.notification_id = n.id AND ne.actor_user_id <> @id
+		ORDER BY ne.created_at DESC, ne.id DESC
+		LIMIT 1
+	) ELSE n.actor_user_id END
+FROM (
+	SELECT notification_id, COUNT(*) AS event_count
+	FROM notification_events
+	WHERE actor_user_id = @id
+	GROUP BY notification_id
+) AS own
+WHERE n.id = own.notification_id AND n.group_key IS NOT NULL`, args).Error
+}
+
+// ユーザーを匿名化し、完全削除済みにする。
+// clerk_user_id も置き換えるため、同じ Clerk アカウントで再登録すると新しいユーザーとして作成される。
+func (r *userDeletionRepository) MarkPurged(ctx context.Context, userID string, now time.Time, anonymizedEmail string) error {
+	updates := map[string]any{
+		"clerk_user_id":        "purged:" + userID,
+		"display_name":         "退会済みユーザー",
+		"avatar_url":           nil,
+		"bio":                  nil,
+		"email":                anonymizedEmail,
+		"is_private":           false,
+		"deletion_status":      model.UserDeletionStatusPurged,
+		"purge_scheduled_at":   nil,
+		"purged_at":            now,
+		"owned_tags_policy":    nil,
+		"tag_transfer_user_id": nil,
+		"contributions_policy": nil,
+		"updated_at":           now,
+	}
+
+	return r.db.WithContext(ctx).
+		Model(&model.User{}).
+		Where("id = ?", userID).
+		Updates(updates).
+		Error
+}
@@ -0,0 +1,53 @@
+package repository
+
+import (
+	"context"
+
+	"cinetag-backend/src/internal/model"
+
+	"gorm.io/gorm"
+)
+
+// user_display_id_histories テーブルの永続化処理を表すインターフェース。
+type UserDisplayIDHistoryRepository interface {
+	// Create は旧 display_id を履歴として登録します。
+	Create(ctx context.Context, userID, displayID string) error
+	// FindByDisplayID は旧 display_id から履歴を取得します。
+	FindByDisplayID(ctx context.Context, displayID string) (*model.UserDisplayIDHistory, error)
+	// DeleteByDisplayID は指定した display_id の履歴を削除します。
+	DeleteByDisplayID(ctx context.Context, displayID string) error
+}
+
+type userDisplayIDHistoryRepository struct {
+	db *gorm.DB
+}
+
+// UserDisplayIDHistoryRepository を生成する。
+func NewUserDisplayIDHistoryRepository(db *gorm.DB) UserDisplayIDHistoryRepository {
+	return &userDisplayIDHistoryRepository{db: db}
+}
+
+// 旧 display_id を履歴として登録する。
+func (r *userDisplayIDHistoryRepository) Create(ctx context.Context, userID, displayID string) error {
+	history := &model.UserDisplayIDHistory{
+		UserID:    userID,
+		DisplayID: displayID,
+	}
+	return r.db.WithContext(ctx).Create(history).Error
+}
+
+// 旧 display_id から履歴を取得する。
+func (r *userDisplayIDHistoryRepository) FindByDisplayID(ctx context.Context, displayID string) (*model.UserDisplayIDHistory, error) {
+	var history model.UserDisplayIDHistory
+	if err := r.db.WithContext(ctx).Where("display_id = ?", displayID).First(&history).Error; err != nil {
+		return nil, err
+	}
+	return &history, nil
+}
+
+// 指定した display_id の履歴を削除する。
+func (r *userDisplayIDHistoryRepository) DeleteByDisplayID(ctx context.Context, displayID string) error {
+	return r.db.WithContext(ctx).
+		Where("display_id = ?", displayID).
+		Delete(&model.UserDisplayIDHistory{}).Error
+}
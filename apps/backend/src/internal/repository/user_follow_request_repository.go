@@ -0,0 +1,122 @@
+package repository
+
+import (
+	"context"
+
+	"cinetag-backend/src/internal/model"
+
+	"gorm.io/gorm"
+)
+
+// user_follow_requests テーブルの永続化処理を表すインターフェース。
+type UserFollowRequestRepository interface {
+	// Create はフォローリクエストを作成します。
+	Create(ctx context.Context, requesterID, targetID string) error
+	// Delete はフォローリクエストを削除します。
+	Delete(ctx context.Context, requesterID, targetID string) error
+	// DeleteAllByUserID は指定ユーザーが関与するフォローリクエストを全て削除します。
+	// （requester / target の両方を対象とします）
+	DeleteAllByUserID(ctx context.Context, userID string) error
+	// Exists は requesterID から targetID へのフォローリクエストが存在するかチェックします。
+	Exists(ctx context.Context, requesterID, targetID string) (bool, error)
+	// ListIncoming は指定ユーザー宛てのフォローリクエストを送ったユーザー一覧を取得します（新しい順）。
+	ListIncoming(ctx context.Context, targetID string, page, pageSize int) ([]*model.User, int64, error)
+	// AcceptAllIncoming は指定ユーザー宛てのフォローリクエストを全て承認し、承認したリクエスト送信者のIDを返します。
+	AcceptAllIncoming(ctx context.Context, targetID string) ([]string, error)
+}
+
+type userFollowRequestRepository struct {
+	db *gorm.DB
+}
+
+// UserFollowRequestRepository を生成する。
+func NewUserFollowRequestRepository(db *gorm.DB) UserFollowRequestRepository {
+	return &userFollowRequestRepository{db: db}
+}
+
+// フォローリクエストを作成する。
+func (r *userFollowRequestRepository) Create(ctx context.Context, requesterID, targetID string) error {
+	req := &model.UserFollowRequest{
+		RequesterID: requesterID,
+		TargetID:    targetID,
+	}
+	return r.db.WithContext(ctx).Create(req).Error
+}
+
+// フォローリクエストを削除する。
+func (r *userFollowRequestRepository) Delete(ctx context.Context, requesterID, targetID string) error {
+	return r.db.WithContext(ctx).
+		Where("requester_id = ? AND target_id = ?", requesterID, targetID).
+		Delete(&model.UserFollowRequest{}).Error
+}
+
+// 指定ユーザーが関与するフォローリクエストを全て削除する。
+func (r *userFollowRequestRepository) DeleteAllByUserID(ctx context.Context, userID string) error {
+	return r.db.WithContext(ctx).
+		Where("requester_id = ? OR target_id = ?", userID, userID).
+		Delete(&model.UserFollowRequest{}).Error
+}
+
+// requesterID から targetID へのフォローリクエストが存在するかチェックする。
+func (r *userFollowRequestRepository) Exists(ctx context.Context, requesterID, targetID string) (bool, error) {
+	var count int64
+	err := r.db.WithContext(ctx).
+		Model(&model.UserFollowRequest{}).
+		Where("requester_id = ? AND target_id = ?", requesterID, targetID).
+		Count(&count).Error
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// 指定ユーザー宛てのフォローリクエストを送ったユーザー一覧を取得する。
+func (r *userFollowRequestRepository) ListIncoming(ctx context.Context, targetID string, page, pageSize int) ([]*model.User, int64, error) {
+	var users []*model.User
+	var total int64
+
+	offset := (page - 1) * pageSize
+
+	if err := r.db.WithContext(ctx).
+		Model(&model.UserFollowRequest{}).
+		Where("target_id = ?", targetID).
+		Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	err := r.db.WithContext(ctx).
+		Table("users").
+		Select("users.*").
+		Joins("INNER JOIN user_follow_requests ON users.id = user_follow_requests.requester_id").
+		Where("user_follow_requests.target_id = ?", targetID).
+		Order("user_follow_requests.created_at DESC").
+		Limit(pageSize).
+		Offset(offset).
+		Find(&users).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return users, total, nil
+}
+
+// 指定ユーザー宛てのフォローリクエストを全て承認する。
+// リクエストの削除とフォロー関係の作成を1文で行い、承認したリクエスト送信者のIDを返す。
+func (r *userFollowRequestRepository) AcceptAllIncoming(ctx context.Context, targetID string) ([]string, error) {
+	var requesterIDs []string
+	err := r.db.WithContext(ctx).Raw(`
+WITH accepted AS (
+    DELETE FROM user_follow_requests
+    WHERE target_id = ?
+    RETURNING requester_id
+), inserted AS (
+    INSERT INTO user_followers (follower_id, followee_id)
+    SELECT requester_id, ? FROM accepted
+    ON CONFLICT DO NOTHING
+)
+SELECT requester_id FROM accepted`, targetID, targetID).Scan(&requesterIDs).Error
+	if err != nil {
+		return nil, err
+	}
+	return requesterIDs, nil
+}
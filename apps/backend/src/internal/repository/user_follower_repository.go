@@ -19,6 +19,8 @@ type UserFollowerRepository interface {
 	DeleteAllByUserID(ctx context.Context, userID string) error
 	// followerID が followeeID をフォローしているかチェックする。
 	IsFollowing(ctx context.Context, followerID, followeeID string) (bool, error)
+	// followeeIDs のうち、followerID がフォローしているユーザーのIDを取得する（一覧の一括確認用）。
+	ListFollowingIDs(ctx context.Context, followerID string, followeeIDs []string) ([]string, error)
 	// 指定ユーザーがフォローしているユーザー一覧を取得する。
 	ListFollowing(ctx context.Context, userID string, page, pageSize int) ([]*model.User, int64, error)
 	// 指定ユーザーをフォローしているユーザー一覧を取得する。
@@ -29,6 +31,17 @@ type UserFollowerRepository interface {
 	CountFollowers(ctx context.Context, userID string) (int64, error)
 	// 指定ユーザーをフォローしているユーザーIDの一覧を取得する（通知用軽量クエリ）。
 	ListFollowerIDs(ctx context.Context, userID string) ([]string, error)
+	// 指定ユーザーへのおすすめユーザーを、共通のフォロー・フォロータグ・映画の多い順に取得する。
+	ListSuggestions(ctx context.Context, userID string, limit int) ([]UserSuggestionRow, error)
+}
+
+// おすすめユーザーの1件分を表す。
+type UserSuggestionRow struct {
+	model.User
+	MutualFollowCount int64 `gorm:"column:mutual_follow_count"` // 自分がフォローしているユーザーのうち、このユーザーをフォローしている人数
+	SharedTagCount    int64 `gorm:"column:shared_tag_count"`    // 自分と共通でフォローしているタグ数
+	SharedMovieCount  int64 `gorm:"column:shared_movie_count"`  // 自分のタグとこのユーザーの公開タグに共通して含まれる映画数
+	FollowerCount     int64 `gorm:"column:follower_count"`
 }
 
 type userFollowerRepository struct {
@@ -76,6 +89,19 @@ func (r *userFollowerRepository) IsFollowing(ctx context.Context, followerID, fo
 	return count > 0, nil
 }
 
+// followeeIDs のうち、followerID がフォローしているユーザーのIDを取得する。
+func (r *userFollowerRepository) ListFollowingIDs(ctx context.Context, followerID string, followeeIDs []string) ([]string, error) {
+	ids := make([]string, 0)
+	if len(followeeIDs) == 0 {
+		return ids, nil
+	}
+	err := r.db.WithContext(ctx).
+		Model(&model.UserFollower{}).
+		Where("follower_id = ? AND followee_id IN ?", followerID, followeeIDs).
+		Pluck("followee_id", &ids).Error
+	return ids, err
+}
+
 // 指定ユーザーがフォローしているユーザー一覧を取得する。
 func (r *userFollowerRepository) ListFollowing(ctx context.Context, userID string, page, pageSize int) ([]*model.User, int64, error) {
 	var users []*model.User
@@ -171,3 +197,87 @@ func (r *userFollowerRepository) CountFollowers(ctx context.Context, userID stri
 		Count(&count).Error
 	return count, err
 }
+
+// 指定ユーザーへのおすすめユーザーを取得する。
+// - 友達の友達（3点）・共通のフォロータグ（2点）・共通の映画（1点）の重み付き合計の高い順に並べる。
+// - 手がかりがない新規ユーザーにも表示できるよう、フォロワー数の多いユーザーを候補に加える。
+// - 本人、フォロー中・フォローリクエスト中、ブロック・ミュート関係、退会済みのユーザーは除外する。
+func (r *userFollowerRepository) ListSuggestions(ctx context.Context, userID string, limit int) ([]UserSuggestionRow, error) {
+	const query = `
+WITH excluded AS (
+	SELECT @user_id::uuid AS id
+	UNION SELECT followee_id FROM user_followers WHERE follower_id = @user_id
+	UNION SELECT target_id FROM user_follow_requests WHERE requester_id = @user_id
+	UNION SELECT blocked_id FROM user_blocks WHERE blocker_id = @user_id
+	UNION SELECT blocker_id FROM user_blocks WHERE blocked_id = @user_id
+	UNION SELECT muted_id FROM user_mutes WHERE muter_id = @user_id
+),
+mutual AS (
+	SELECT uf.followee_id AS user_id, COUNT(*) AS cnt
+	FROM user_followers uf
+	JOIN user_followers mine ON mine.followee_id = uf.follower_id AND mine.follower_id = @user_id
+	GROUP BY uf.followee_id
+),
+shared_tags AS (
+	SELECT tf.user_id, COUNT(*) AS cnt
+	FROM tag_followers tf
+	WHERE tf.tag_id IN (SELECT tag_id FROM tag_followers WHERE user_id = @user_id)
+	GROUP BY tf.user_id
+),
+shared_movies AS (
+	SELECT t.user_id, COUNT(DISTINCT tm.tmdb_movie_id) AS cnt
+	FROM tag_movies tm
+	JOIN tags t ON t.id = tm.tag_id AND t.is_public = true
+	WHERE tm.tmdb_movie_id IN (
+		SELECT my_tm.tmdb_movie_id
+		FROM tag_movies my_tm
+		JOIN tags my_t ON my_t.id = my_tm.tag_id
+		WHERE my_t.user_id = @user_id
+	)
+	GROUP BY t.user_id
+),
+followers AS (
+	SELECT followee_id AS user_id, COUNT(*) AS cnt
+	FROM user_followers
+	GROUP BY followee_id
+),
+popular AS (
+	SELECT u.id AS user_id
+	FROM users u
+	LEFT JOIN followers f ON f.user_id = u.id
+	WHERE u.deleted_at IS NULL AND u.id NOT IN (SELECT id FROM excluded)
+	ORDER BY COALESCE(f.cnt, 0) DESC, u.created_at ASC
+	LIMIT @limit
+),
+candidates AS (
+	SELECT user_id FROM mutual
+	UNION SELECT user_id FROM shared_tags
+	UNION SELECT user_id FROM shared_movies
+	UNION SELECT user_id FROM popular
+)
+SELECT u.*,
+	COALESCE(m.cnt, 0) AS mutual_follow_count,
+	COALESCE(st.cnt, 0) AS shared_tag_count,
+	COALESCE(sm.cnt, 0) AS shared_movie_count,
+	COALESCE(f.cnt, 0) AS follower_count
+FROM candidates c
+JOIN users u ON u.id = c.user_id
+LEFT JOIN mutual m ON m.user_id = u.id
+LEFT JOIN shared_tags st ON st.user_id = u.id
+LEFT JOIN shared_movies sm ON sm.user_id = u.id
+LEFT JOIN followers f ON f.user_id = u.id
+WHERE u.deleted_at IS NULL AND u.id NOT IN (SELECT id FROM excluded)
+ORDER BY 3 * COALESCE(m.cnt, 0) + 2 * COALESCE(st.cnt, 0) + COALESCE(sm.cnt, 0) DESC,
+	follower_count DESC,
+	u.created_at ASC
+LIMIT @limit`
+
+	var rows []UserSuggestionRow
+	err := r.db.WithContext(ctx).
+		Raw(query, map[string]any{"user_id": userID, "limit": limit}).
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
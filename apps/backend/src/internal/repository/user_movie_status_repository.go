@@ -0,0 +1,156 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"cinetag-backend/src/internal/model"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// 視聴ステータス一覧取得時のフィルタ条件を表す。
+type UserMovieStatusListFilter struct {
+	UserID string
+	Status string // 空文字の場合は全てのステータスを対象とする
+	Offset int
+	Limit  int
+}
+
+// user_movie_statuses と movie_cache の結合結果を表す。
+// cache 側は存在しない可能性があるため nullable を許容する。
+type UserMovieStatusWithCache struct {
+	model.UserMovieStatus
+
+	MovieTitle         *string    `gorm:"column:movie_title"`
+	MovieOriginalTitle *string    `gorm:"column:movie_original_title"`
+	MoviePosterPath    *string    `gorm:"column:movie_poster_path"`
+	MovieReleaseDate   *time.Time `gorm:"column:movie_release_date"`
+	MovieVoteAverage   *float64   `gorm:"column:movie_vote_average"`
+}
+
+// user_movie_statuses テーブルの永続化処理を表すインターフェース。
+type UserMovieStatusRepository interface {
+	// Upsert は視聴ステータスを作成し、既に存在する場合は上書きします。
+	Upsert(ctx context.Context, status *model.UserMovieStatus) error
+	// Delete は視聴ステータスを削除します（該当がない場合は gorm.ErrRecordNotFound）。
+	Delete(ctx context.Context, userID string, tmdbMovieID int) error
+	// FindByUserAndMovie は指定ユーザー・映画の視聴ステータスを取得します。
+	FindByUserAndMovie(ctx context.Context, userID string, tmdbMovieID int) (*model.UserMovieStatus, error)
+	// ListByUserAndMovieIDs は指定ユーザーの、指定した映画群の視聴ステータスを取得します。
+	ListByUserAndMovieIDs(ctx context.Context, userID string, tmdbMovieIDs []int) ([]model.UserMovieStatus, error)
+	// ListByUser は指定ユーザーの視聴ステータス一覧を取得します（更新日時の新しい順）。
+	// movie_cache を LEFT JOIN し、可能なら映画情報も一緒に返します。
+	ListByUser(ctx context.Context, filter UserMovieStatusListFilter) ([]UserMovieStatusWithCache, int64, error)
+	// CountWatchedInTag は指定タグに含まれる映画のうち、指定ユーザーが観た映画の数を取得します。
+	CountWatchedInTag(ctx context.Context, userID, tagID string) (int64, error)
+}
+
+type userMovieStatusRepository struct {
+	db *gorm.DB
+}
+
+// UserMovieStatusRepository を生成する。
+func NewUserMovieStatusRepository(db *gorm.DB) UserMovieStatusRepository {
+	return &userMovieStatusRepository{db: db}
+}
+
+// 視聴ステータスを作成・上書きする。
+func (r *userMovieStatusRepository) Upsert(ctx context.Context, status *model.UserMovieStatus) error {
+	return r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}, {Name: "tmdb_movie_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"status", "watched_at", "updated_at"}),
+	}).Create(status).Error
+}
+
+// 視聴ステータスを削除する。
+func (r *userMovieStatusRepository) Delete(ctx context.Context, userID string, tmdbMovieID int) error {
+	res := r.db.WithContext(ctx).
+		Where("user_id = ? AND tmdb_movie_id = ?", userID, tmdbMovieID).
+		Delete(&model.UserMovieStatus{})
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// 指定ユーザー・映画の視聴ステータスを取得する。
+func (r *userMovieStatusRepository) FindByUserAndMovie(ctx context.Context, userID string, tmdbMovieID int) (*model.UserMovieStatus, error) {
+	var status model.UserMovieStatus
+	err := r.db.WithContext(ctx).
+		Where("user_id = ? AND tmdb_movie_id = ?", userID, tmdbMovieID).
+		First(&status).Error
+	if err != nil {
+		return nil, err
+	}
+	return &status, nil
+}
+
+// 指定ユーザーの、指定した映画群の視聴ステータスを取得する。
+func (r *userMovieStatusRepository) ListByUserAndMovieIDs(ctx context.Context, userID string, tmdbMovieIDs []int) ([]model.UserMovieStatus, error) {
+	if len(tmdbMovieIDs) == 0 {
+		return []model.UserMovieStatus{}, nil
+	}
+
+	var statuses []model.UserMovieStatus
+	err := r.db.WithContext(ctx).
+		Where("user_id = ? AND tmdb_movie_id IN ?", userID, tmdbMovieIDs).
+		Find(&statuses).Error
+	if err != nil {
+		return nil, err
+	}
+	return statuses, nil
+}
+
+// 指定ユーザーの視聴ステータス一覧を取得する。
+func (r *userMovieStatusRepository) ListByUser(ctx context.Context, filter UserMovieStatusListFilter) ([]UserMovieStatusWithCache, int64, error) {
+	if filter.Limit <= 0 {
+		return []UserMovieStatusWithCache{}, 0, nil
+	}
+
+	baseQuery := r.db.WithContext(ctx).
+		Table((model.UserMovieStatus{}).TableName()+" AS ums").
+		Where("ums.user_id = ?", filter.UserID)
+	if filter.Status != "" {
+		baseQuery = baseQuery.Where("ums.status = ?", filter.Status)
+	}
+
+	var total int64
+	if err := baseQuery.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	if total == 0 {
+		return []UserMovieStatusWithCache{}, 0, nil
+	}
+
+	// Count()はSELECTをCOUNT(*)に置き換えるため、Select句を再指定
+	var rows []UserMovieStatusWithCache
+	err := baseQuery.
+		Select(`ums.*,
+		        mc.title AS movie_title, mc.original_title AS movie_original_title, mc.poster_path AS movie_poster_path,
+		        mc.release_date AS movie_release_date, mc.vote_average AS movie_vote_average`).
+		Joins("LEFT JOIN " + (model.MovieCache{}).TableName() + " AS mc ON mc.tmdb_movie_id = ums.tmdb_movie_id").
+		Order("ums.updated_at DESC, ums.tmdb_movie_id ASC").
+		Offset(filter.Offset).
+		Limit(filter.Limit).
+		Scan(&rows).Error
+	if err != nil {
+		return nil, 0, err
+	}
+	return rows, total, nil
+}
+
+// 指定タグに含まれる映画のうち、指定ユーザーが観た映画の数を取得する。
+func (r *userMovieStatusRepository) CountWatchedInTag(ctx context.Context, userID, tagID string) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).
+		Table((model.TagMovie{}).TableName()+" AS tm").
+		Joins("JOIN "+(model.UserMovieStatus{}).TableName()+" AS ums ON ums.tmdb_movie_id = tm.tmdb_movie_id").
+		Where("tm.tag_id = ? AND ums.user_id = ? AND ums.status = ?", tagID, userID, model.MovieWatchStatusWatched).
+		Count(&count).Error
+	return count, err
+}
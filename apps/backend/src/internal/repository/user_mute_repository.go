@@ -0,0 +1,101 @@
+package repository
+
+import (
+	"context"
+
+	"cinetag-backend/src/internal/model"
+
+	"gorm.io/gorm"
+)
+
+// user_mutes テーブルの永続化処理を表すインターフェース。
+type UserMuteRepository interface {
+	// Create はミュート関係を作成します。
+	Create(ctx context.Context, muterID, mutedID string) error
+	// Delete はミュート関係を削除します。
+	Delete(ctx context.Context, muterID, mutedID string) error
+	// IsMuting は muterID が mutedID をミュートしているかチェックします。
+	IsMuting(ctx context.Context, muterID, mutedID string) (bool, error)
+	// ListMuted は指定ユーザーがミュートしているユーザー一覧を取得します（ミュート日時の新しい順）。
+	ListMuted(ctx context.Context, muterID string, page, pageSize int) ([]*model.User, int64, error)
+	// ListMutedIDs は指定ユーザーがミュートしているユーザーIDの一覧を取得します。
+	ListMutedIDs(ctx context.Context, muterID string) ([]string, error)
+}
+
+type userMuteRepository struct {
+	db *gorm.DB
+}
+
+// UserMuteRepository を生成する。
+func NewUserMuteRepository(db *gorm.DB) UserMuteRepository {
+	return &userMuteRepository{db: db}
+}
+
+// ミュート関係を作成する。
+func (r *userMuteRepository) Create(ctx context.Context, muterID, mutedID string) error {
+	mute := &model.UserMute{
+		MuterID: muterID,
+		MutedID: mutedID,
+	}
+	return r.db.WithContext(ctx).Create(mute).Error
+}
+
+// ミュート関係を削除する。
+func (r *userMuteRepository) Delete(ctx context.Context, muterID, mutedID string) error {
+	return r.db.WithContext(ctx).
+		Where("muter_id = ? AND muted_id = ?", muterID, mutedID).
+		Delete(&model.UserMute{}).Error
+}
+
+// muterID が mutedID をミュートしているかチェックする。
+func (r *userMuteRepository) IsMuting(ctx context.Context, muterID, mutedID string) (bool, error) {
+	var count int64
+	err := r.db.WithContext(ctx).
+		Model(&model.UserMute{}).
+		Where("muter_id = ? AND muted_id = ?", muterID, mutedID).
+		Count(&count).Error
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// 指定ユーザーがミュートしているユーザー一覧を取得する。
+func (r *userMuteRepository) ListMuted(ctx context.Context, muterID string, page, pageSize int) ([]*model.User, int64, error) {
+	var users []*model.User
+	var total int64
+
+	offset := (page - 1) * pageSize
+
+	if err := r.db.WithContext(ctx).
+		Model(&model.UserMute{}).
+		Where("muter_id = ?", muterID).
+		Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	err := r.db.WithContext(ctx).
+		Table("users").
+		Select("users.*").
+		Joins("INNER JOIN user_mutes ON users.id = user_mutes.muted_id").
+		Where("user_mutes.muter_id = ?", muterID).
+		Order("user_mutes.created_at DESC").
+		Limit(pageSize).
+		Offset(offset).
+		Find(&users).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return users, total, nil
+}
+
+// 指定ユーザーがミュートしているユーザーIDの一覧を取得する。
+func (r *userMuteRepository) ListMutedIDs(ctx context.Context, muterID string) ([]string, error) {
+	var ids []string
+	err := r.db.WithContext(ctx).
+		Model(&model.UserMute{}).
+		Where("muter_id = ?", muterID).
+		Pluck("muted_id", &ids).Error
+	return ids, err
+}
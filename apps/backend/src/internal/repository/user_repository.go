@@ -3,7 +3,7 @@ package repository
 import (
 	"context"
 	"log/slog"
-	"time"
+	"strings"
 
 	"cinetag-backend/src/internal/model"
 
@@ -15,9 +15,11 @@ type UserRepository interface {
 	FindByID(ctx context.Context, userID string) (*model.User, error)
 	FindByClerkUserID(ctx context.Context, clerkUserID string) (*model.User, error)
 	FindByDisplayID(ctx context.Context, displayID string) (*model.User, error)
+	// ListActiveByDisplayIDs は指定した display_id のユーザーのうち、退会していないユーザーを返します。
+	ListActiveByDisplayIDs(ctx context.Context, displayIDs []string) ([]*model.User, error)
 	Create(ctx context.Context, user *model.User) error
 	Update(ctx context.Context, userID string, updates map[string]any) error
-	UpdateForUserDeactivated(ctx context.Context, userID string, now time.Time, anonymizedEmail string) error
+	Search(ctx context.Context, filter UserSearchFilter) ([]UserSearchRow, int64, error)
 }
 
 type userRepository struct {
@@ -64,6 +66,21 @@ func (r *userRepository) FindByDisplayID(ctx context.Context, displayID string)
 	return &user, nil
 }
 
+// 指定した display_id のユーザーのうち、退会していないユーザーを返す。
+func (r *userRepository) ListActiveByDisplayIDs(ctx context.Context, displayIDs []string) ([]*model.User, error) {
+	users := make([]*model.User, 0, len(displayIDs))
+	if len(displayIDs) == 0 {
+		return users, nil
+	}
+	err := r.db.WithContext(ctx).
+		Where("display_id IN ? AND deletion_status = ?", displayIDs, model.UserDeletionStatusActive).
+		Find(&users).Error
+	if err != nil {
+		return nil, err
+	}
+	return users, nil
+}
+
 // ユーザーを作成する。
 func (r *userRepository) Create(ctx context.Context, user *model.User) error {
 	return r.db.WithContext(ctx).Create(user).Error
@@ -78,20 +95,81 @@ func (r *userRepository) Update(ctx context.Context, userID string, updates map[
 		Error
 }
 
-// ユーザーを論理削除＋匿名化する。
-func (r *userRepository) UpdateForUserDeactivated(ctx context.Context, userID string, now time.Time, anonymizedEmail string) error {
-	updates := map[string]any{
-		"deleted_at":   now,
-		"display_name": "退会済みユーザー",
-		"avatar_url":   nil,
-		"bio":          nil,
-		"email":        anonymizedEmail,
-		"updated_at":   now,
+// ユーザー検索時のフィルタ条件を表す。
+type UserSearchFilter struct {
+	Query    string
+	ViewerID string // ログインユーザーのID（ブロック関係にあるユーザーを除外する。未ログイン時は空文字）
+	Offset   int
+	Limit    int
+}
+
+// ユーザー検索結果の1件分を表す。
+type UserSearchRow struct {
+	model.User
+	FollowerCount int64 `gorm:"column:follower_count"`
+	ActivityScore int64 `gorm:"column:activity_score"`
+}
+
+// LIKE / ILIKE のパターンとして扱われる文字（%, _, \）をエスケープする。
+// ESCAPE '\' を指定した LIKE / ILIKE と組み合わせて使う。
+func escapeLikePattern(s string) string {
+	return likePatternEscaper.Replace(s)
+}
+
+var likePatternEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// display_name / display_id でユーザーを検索する。
+// - 前方一致を部分一致より優先し、同順位はフォロワー数・直近の活動量の多い順に並べる。
+// - 空白区切りの各語がいずれかに部分一致するユーザーを対象とする。
+// - 一致の判定は ILIKE による大文字小文字を区別しない部分一致のみとし、類似度（pg_trgm）による一致は意図的に扱わない。
+// - display_id / display_name は短いため、類似度で候補を広げるより入力どおりに絞り込む方が目的のユーザーを探しやすい。
+func (r *userRepository) Search(ctx context.Context, filter UserSearchFilter) ([]UserSearchRow, int64, error) {
+	terms := strings.Fields(filter.Query)
+	if len(terms) == 0 || filter.Limit <= 0 {
+		return []UserSearchRow{}, 0, nil
 	}
 
-	return r.db.WithContext(ctx).
-		Model(&model.User{}).
-		Where("id = ?", userID).
-		Updates(updates).
-		Error
+	baseQuery := r.db.WithContext(ctx).
+		Table("users AS u").
+		Where("u.deleted_at IS NULL")
+	for _, term := range terms {
+		like := "%" + escapeLikePattern(term) + "%"
+		baseQuery = baseQuery.Where(`(u.display_name ILIKE ? ESCAPE '\' OR u.display_id ILIKE ? ESCAPE '\')`, like, like)
+	}
+	if filter.ViewerID != "" {
+		baseQuery = baseQuery.
+			Where("u.id NOT IN (SELECT blocked_id FROM user_blocks WHERE blocker_id = ?)", filter.ViewerID).
+			Where("u.id NOT IN (SELECT blocker_id FROM user_blocks WHERE blocked_id = ?)", filter.ViewerID)
+	}
+
+	var total int64
+	if err := baseQuery.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	if total == 0 {
+		return []UserSearchRow{}, 0, nil
+	}
+
+	// Count()はSELECTをCOUNT(*)に置き換えるため、Select句を再指定
+	query := strings.TrimSpace(filter.Query)
+	var rows []UserSearchRow
+	err := baseQuery.
+		Select(`u.*,
+				(SELECT COUNT(*) FROM user_followers WHERE followee_id = u.id) AS follower_count,
+				(SELECT COUNT(*) FROM tags WHERE user_id = u.id AND is_public = true)
+				+ (SELECT COUNT(*) FROM tag_movies WHERE added_by_user_id = u.id AND created_at > NOW() - INTERVAL '90 days') AS activity_score,
+				CASE
+					WHEN LOWER(u.display_id) = LOWER(@query) THEN 0
+					WHEN u.display_id ILIKE @prefix ESCAPE '\' OR u.display_name ILIKE @prefix ESCAPE '\' THEN 1
+					ELSE 2
+				END AS match_rank`, map[string]any{"query": query, "prefix": escapeLikePattern(query) + "%"}).
+		Order("match_rank ASC, follower_count DESC, activity_score DESC, u.created_at ASC").
+		Limit(filter.Limit).
+		Offset(filter.Offset).
+		Scan(&rows).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return rows, total, nil
 }
@@ -0,0 +1,222 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"cinetag-backend/src/internal/model"
+
+	"gorm.io/gorm"
+)
+
+// ユーザーの Webhook が登録数の上限に達している。
+var ErrWebhookLimitReached = errors.New("webhook limit reached")
+
+// Webhook の更新内容。nil の項目は更新しない。
+type WebhookUpdatePatch struct {
+	URL      *string
+	Events   *[]string
+	IsActive *bool
+}
+
+// webhooks / webhook_deliveries テーブルの永続化処理を表すインターフェース。
+type WebhookRepository interface {
+	// Create は Webhook を作成します。
+	// ユーザーの Webhook が既に maxPerUser 件ある場合は作成せず、ErrWebhookLimitReached を返します。
+	Create(ctx context.Context, webhook *model.Webhook, maxPerUser int64) error
+	// FindByID は Webhook を取得します（存在しない場合は gorm.ErrRecordNotFound）。
+	FindByID(ctx context.Context, webhookID string) (*model.Webhook, error)
+	// ListByUser は指定ユーザーが登録した Webhook を新しい順で返します。
+	ListByUser(ctx context.Context, userID string) ([]*model.Webhook, error)
+	// Update は指定ユーザーの Webhook を更新します（該当がない場合は gorm.ErrRecordNotFound）。
+	Update(ctx context.Context, webhookID, userID string, patch WebhookUpdatePatch) error
+	// Delete は指定ユーザーの Webhook を削除します（該当がない場合は gorm.ErrRecordNotFound）。
+	Delete(ctx context.Context, webhookID, userID string) error
+	// ListSubscribed は event を購読している有効な Webhook を返します。
+	// ownerUserID が登録したもののうち、tagID を購読しているもの（tagID が空の場合は除く）とアカウント全体を購読しているものが対象です。
+	ListSubscribed(ctx context.Context, event, tagID, ownerUserID string) ([]*model.Webhook, error)
+	// FindMovieTitle は映画キャッシュから映画のタイトルを返します（キャッシュがない場合は nil）。
+	FindMovieTitle(ctx context.Context, tmdbMovieID int) (*string, error)
+
+	// CreateDelivery は配信ログを1件記録します。
+	CreateDelivery(ctx context.Context, delivery *model.WebhookDelivery) error
+	// ListDeliveries は指定 Webhook の配信ログを新しい順で返します。総件数も返します。
+	ListDeliveries(ctx context.Context, webhookID string, offset, limit int) ([]*model.WebhookDelivery, int64, error)
+	// DeleteDeliveriesBefore は before より前の配信ログを最大 limit 件削除し、削除件数を返します。
+	DeleteDeliveriesBefore(ctx context.Context, before time.Time, limit int) (int64, error)
+}
+
+type webhookRepository struct {
+	db *gorm.DB
+}
+
+// WebhookRepository を生成する。
+func NewWebhookRepository(db *gorm.DB) WebhookRepository {
+	return &webhookRepository{db: db}
+}
+
+// 登録数の上限を確認して Webhook を作成する。
+// 並行した作成で上限を超えないよう、ユーザーの行をロックしてから件数を数える。
+func (r *webhookRepository) Create(ctx context.Context, webhook *model.Webhook, maxPerUser int64) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("SELECT 1 FROM users WHERE id = ? FOR UPDATE", webhook.UserID).Error; err != nil {
+			return err
+		}
+		var count int64
+		if err := tx.Model(&model.Webhook{}).Where("user_id = ?", webhook.UserID).Count(&count).Error; err != nil {
+			return err
+		}
+		if count >= maxPerUser {
+			return ErrWebhookLimitReached
+		}
+		return tx.Create(webhook).Error
+	})
+}
+
+// Webhook を取得する。
+func (r *webhookRepository) FindByID(ctx context.Context, webhookID string) (*model.Webhook, error) {
+	var webhook model.Webhook
+	if err := r.db.WithContext(ctx).Where("id = ?", webhookID).First(&webhook).Error; err != nil {
+		return nil, err
+	}
+	return &webhook, nil
+}
+
+// 指定ユーザーが登録した Webhook を返す。
+func (r *webhookRepository) ListByUser(ctx context.Context, userID string) ([]*model.Webhook, error) {
+	webhooks := make([]*model.Webhook, 0)
+	err := r.db.WithContext(ctx).
+		Where("user_id = ?", userID).
+		Order("created_at DESC").
+		Find(&webhooks).Error
+	if err != nil {
+		return nil, err
+	}
+	return webhooks, nil
+}
+
+// 指定ユーザーの Webhook を更新する。
+func (r *webhookRepository) Update(ctx context.Context, webhookID, userID string, patch WebhookUpdatePatch) error {
+	updates := map[string]any{"updated_at": time.Now()}
+	if patch.URL != nil {
+		updates["url"] = *patch.URL
+	}
+	if patch.Events != nil {
+		events, err := json.Marshal(*patch.Events)
+		if err != nil {
+			return err
+		}
+		updates["events"] = gorm.Expr("?::jsonb", string(events))
+	}
+	if patch.IsActive != nil {
+		updates["is_active"] = *patch.IsActive
+	}
+
+	res := r.db.WithContext(ctx).
+		Model(&model.Webhook{}).
+		Where("id = ? AND user_id = ?", webhookID, userID).
+		Updates(updates)
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// 指定ユーザーの Webhook を削除する（配信ログも削除される）。
+func (r *webhookRepository) Delete(ctx context.Context, webhookID, userID string) error {
+	res := r.db.WithContext(ctx).
+		Where("id = ? AND user_id = ?", webhookID, userID).
+		Delete(&model.Webhook{})
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// イベントを購読している有効な Webhook を返す。
+func (r *webhookRepository) ListSubscribed(ctx context.Context, event, tagID, ownerUserID string) ([]*model.Webhook, error) {
+	events, err := json.Marshal([]string{event})
+	if err != nil {
+		return nil, err
+	}
+
+	webhooks := make([]*model.Webhook, 0)
+	query := r.db.WithContext(ctx).
+		Where("is_active = TRUE AND events @> ?::jsonb", string(events))
+	if tagID != "" {
+		// タグごとの Webhook も現在のタグオーナーが登録したもののみ（譲渡前のオーナーの Webhook には配信しない）
+		query = query.Where("user_id = ? AND (tag_id = ? OR tag_id IS NULL)", ownerUserID, tagID)
+	} else {
+		query = query.Where("tag_id IS NULL AND user_id = ?", ownerUserID)
+	}
+	if err := query.Order("created_at ASC").Find(&webhooks).Error; err != nil {
+		return nil, err
+	}
+	return webhooks, nil
+}
+
+// 映画キャッシュから映画のタイトルを返す。
+func (r *webhookRepository) FindMovieTitle(ctx context.Context, tmdbMovieID int) (*string, error) {
+	var titles []string
+	err := r.db.WithContext(ctx).
+		Model(&model.MovieCache{}).
+		Where("tmdb_movie_id = ?", tmdbMovieID).
+		Limit(1).
+		Pluck("title", &titles).Error
+	if err != nil || len(titles) == 0 {
+		return nil, err
+	}
+	return &titles[0], nil
+}
+
+// 配信ログを1件記録する。
+func (r *webhookRepository) CreateDelivery(ctx context.Context, delivery *model.WebhookDelivery) error {
+	return r.db.WithContext(ctx).Create(delivery).Error
+}
+
+// 指定 Webhook の配信ログを新しい順で返す。
+func (r *webhookRepository) ListDeliveries(ctx context.Context, webhookID string, offset, limit int) ([]*model.WebhookDelivery, int64, error) {
+	base := r.db.WithContext(ctx).
+		Model(&model.WebhookDelivery{}).
+		Where("webhook_id = ?", webhookID)
+
+	var total int64
+	if err := base.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	deliveries := make([]*model.WebhookDelivery, 0)
+	if total == 0 {
+		return deliveries, 0, nil
+	}
+	err := base.
+		Order("created_at DESC").
+		Offset(offset).
+		Limit(limit).
+		Find(&deliveries).Error
+	if err != nil {
+		return nil, 0, err
+	}
+	return deliveries, total, nil
+}
+
+// 保持期間を過ぎた配信ログを削除する。
+func (r *webhookRepository) DeleteDeliveriesBefore(ctx context.Context, before time.Time, limit int) (int64, error) {
+	res := r.db.WithContext(ctx).Exec(`
+DELETE FROM webhook_deliveries
+WHERE id IN (
+	SELECT wd.id
+	FROM webhook_deliveries AS wd
+	WHERE wd.created_at < ?
+	LIMIT ?
+)`, before, limit)
+	return res.RowsAffected, res.Error
+}
@@ -0,0 +1,386 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"strings"
+	"time"
+
+	"cinetag-backend/src/internal/model"
+	"cinetag-backend/src/internal/repository"
+
+	"github.com/google/uuid"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+)
+
+// ロールの指定が不正な場合のエラー。
+var ErrInvalidUserRole = errors.New("role must be 'user' or 'admin'")
+
+// ユーザー一覧の絞り込み条件が不正な場合のエラー。
+var ErrInvalidAdminUserFilter = errors.New("invalid user filter")
+
+// 管理者が自分自身のロールを変更・利用停止しようとした場合のエラー。
+var ErrCannotModerateSelf = errors.New("cannot change your own role or suspend yourself")
+
+// 利用停止中でないユーザーの利用停止を解除しようとした場合のエラー。
+var ErrUserNotSuspended = errors.New("user is not suspended")
+
+// 公開されていないタグを非表示にしようとした場合のエラー。
+var ErrTagNotPublic = errors.New("tag is not public")
+
+// 非表示にしていないタグを公開に戻そうとした場合のエラー。
+var ErrTagNotHidden = errors.New("tag is not hidden")
+
+// 管理者によるユーザー一覧の検索条件。
+type ListAdminUsersInput struct {
+	Query    string // 表示ID・表示名・メールアドレスの部分一致、またはユーザーID・Clerk ユーザーIDの完全一致
+	Status   string // repository.AdminUserStatus*（空の場合はすべて）
+	Role     string // model.UserRole*（空の場合はすべて）
+	Page     int
+	PageSize int
+}
+
+// 管理者用 API のうち、ユーザー・タグのモデレーションを扱うサービス。
+// actorUserID は操作した管理者のユーザーID。状態を変更した操作は、同じトランザクションで監査ログに記録する。
+type AdminService interface {
+	// ListUsers は退会・完全削除済みを含むユーザーを検索する。
+	ListUsers(ctx context.Context, in ListAdminUsersInput) ([]*model.User, int64, error)
+	// GetUser は退会済みを含むユーザーを取得する（完全削除済みの場合は ErrUserNotFound）。
+	GetUser(ctx context.Context, userID string) (*model.User, error)
+	// UpdateUserRole はユーザーのロールを変更する。
+	UpdateUserRole(ctx context.Context, actorUserID, userID, role string) (*model.User, error)
+	// SuspendUser はユーザーを利用停止にする。既に利用停止中の場合はそのまま返す。
+	SuspendUser(ctx context.Context, actorUserID, userID string) (*model.User, error)
+	// RestoreUser は利用停止中のユーザーの利用停止を解除する（本人による退会は取り消さない）。
+	RestoreUser(ctx context.Context, actorUserID, userID string) (*model.User, error)
+	// HideTag は公開タグを非表示（非公開）にする。既に非表示の場合はそのまま返す。
+	HideTag(ctx context.Context, actorUserID, tagID string) (*model.Tag, error)
+	// UnhideTag は非表示にしたタグを、非表示にする前の公開範囲に戻す。
+	UnhideTag(ctx context.Context, actorUserID, tagID string) (*model.Tag, error)
+}
+
+type adminService struct {
+	logger         *slog.Logger
+	db             *gorm.DB
+	moderationRepo repository.ModerationRepository
+	userRepo       repository.UserRepository
+	tagRepo        repository.TagRepository
+	now            func() time.Time
+}
+
+// AdminService の実装を生成する。
+func NewAdminService(logger *slog.Logger, db *gorm.DB, moderationRepo repository.ModerationRepository, userRepo repository.UserRepository, tagRepo repository.TagRepository) AdminService {
+	return &adminService{
+		logger:         logger,
+		db:             db,
+		moderationRepo: moderationRepo,
+		userRepo:       userRepo,
+		tagRepo:        tagRepo,
+		now:            time.Now,
+	}
+}
+
+// 退会・完全削除済みを含むユーザーを検索する。
+func (s *adminService) ListUsers(ctx context.Context, in ListAdminUsersInput) ([]*model.User, int64, error) {
+	switch in.Status {
+	case "", repository.AdminUserStatusActive, repository.AdminUserStatusDeactivated, repository.AdminUserStatusSuspended, repository.AdminUserStatusPurged:
+	default:
+		return nil, 0, ErrInvalidAdminUserFilter
+	}
+	switch in.Role {
+	case "", model.UserRoleUser, model.UserRoleAdmin:
+	default:
+		return nil, 0, ErrInvalidAdminUserFilter
+	}
+	if in.Page < 1 {
+		in.Page = 1
+	}
+	if in.PageSize < 1 || in.PageSize > 100 {
+		in.PageSize = 20
+	}
+
+	return s.moderationRepo.ListUsers(ctx, repository.AdminUserFilter{
+		Query:  strings.TrimSpace(in.Query),
+		Status: in.Status,
+		Role:   in.Role,
+		Offset: (in.Page - 1) * in.PageSize,
+		Limit:  in.PageSize,
+	})
+}
+
+// 退会済みを含むユーザーを取得する。
+func (s *adminService) GetUser(ctx context.Context, userID string) (*model.User, error) {
+	return s.findUser(ctx, userID)
+}
+
+// ユーザーのロールを変更する。管理者がいなくならないよう、自分自身のロールは変更できない。
+func (s *adminService) UpdateUserRole(ctx context.Context, actorUserID, userID, role string) (*model.User, error) {
+	if role != model.UserRoleUser && role != model.UserRoleAdmin {
+		return nil, ErrInvalidUserRole
+	}
+	if actorUserID != "" && actorUserID == userID {
+		return nil, ErrCannotModerateSelf
+	}
+	u, err := s.findUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if u.Role == role {
+		return u, nil
+	}
+
+	now := s.now()
+	err = s.withTx(ctx, func(repo repository.ModerationRepository) error {
+		if err := repo.UpdateUserRole(ctx, u.ID, role, now); err != nil {
+			return err
+		}
+		return recordAdminAction(ctx, repo, actorUserID, model.AdminAuditActionUserRoleUpdated, model.AdminAuditTargetUser, u.ID, map[string]any{
+			"previous_role": u.Role,
+			"role":          role,
+		}, now)
+	})
+	if err != nil {
+		return nil, err
+	}
+	s.logger.Info("service.UpdateUserRole completed",
+		slog.String("actor_user_id", actorUserID),
+		slog.String("user_id", u.ID),
+		slog.String("previous_role", u.Role),
+		slog.String("role", role),
+	)
+
+	u.Role = role
+	u.UpdatedAt = now
+	return u, nil
+}
+
+// ユーザーを利用停止にする。
+// プロフィール・タグは退会状態と同様に非表示になり、本人は退会を取り消せない（完全削除の予定は設定しない）。
+func (s *adminService) SuspendUser(ctx context.Context, actorUserID, userID string) (*model.User, error) {
+	if actorUserID != "" && actorUserID == userID {
+		return nil, ErrCannotModerateSelf
+	}
+	u, err := s.findUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if u.SuspendedAt != nil {
+		return u, nil
+	}
+
+	now := s.now()
+	err = s.withTx(ctx, func(repo repository.ModerationRepository) error {
+		if err := repo.MarkUserSuspended(ctx, u.ID, now); err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrUserNotFound // 同時に完全削除された
+			}
+			return err
+		}
+		return recordAdminAction(ctx, repo, actorUserID, model.AdminAuditActionUserSuspended, model.AdminAuditTargetUser, u.ID, nil, now)
+	})
+	if err != nil {
+		return nil, err
+	}
+	s.logger.Info("service.SuspendUser completed",
+		slog.String("actor_user_id", actorUserID),
+		slog.String("user_id", u.ID),
+	)
+
+	u.SuspendedAt = &now
+	if u.DeletedAt == nil {
+		u.DeletedAt = &now
+	}
+	u.DeletionStatus = model.UserDeletionStatusDeactivated
+	u.UpdatedAt = now
+	return u, nil
+}
+
+// 利用停止中のユーザーの利用停止を解除する。
+// 本人が退会していた場合（完全削除の予定がある場合）は退会状態のままにし、本人の判断で退会を取り消せるようにする。
+func (s *adminService) RestoreUser(ctx context.Context, actorUserID, userID string) (*model.User, error) {
+	u, err := s.findUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if u.SuspendedAt == nil {
+		return nil, ErrUserNotSuspended
+	}
+	deletionRequested := u.PurgeScheduledAt != nil
+
+	now := s.now()
+	err = s.withTx(ctx, func(repo repository.ModerationRepository) error {
+		if err := repo.MarkUserRestored(ctx, u.ID, now); err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrUserNotSuspended // 同時に解除された、または完全削除された
+			}
+			return err
+		}
+		return recordAdminAction(ctx, repo, actorUserID, model.AdminAuditActionUserRestored, model.AdminAuditTargetUser, u.ID, map[string]any{
+			"deletion_requested": deletionRequested,
+		}, now)
+	})
+	if err != nil {
+		return nil, err
+	}
+	s.logger.Info("service.RestoreUser completed",
+		slog.String("actor_user_id", actorUserID),
+		slog.String("user_id", u.ID),
+		slog.Bool("deletion_requested", deletionRequested),
+	)
+
+	u.SuspendedAt = nil
+	if !deletionRequested {
+		u.DeletedAt = nil
+		u.DeletionStatus = model.UserDeletionStatusActive
+	}
+	u.UpdatedAt = now
+	return u, nil
+}
+
+// 公開タグを非表示にする。非表示の間は非公開になり、持ち主は公開に戻せない。
+// 持ち主による非公開化とは異なり、フォロワーへの通知や Webhook の配信は行わない。
+func (s *adminService) HideTag(ctx context.Context, actorUserID, tagID string) (*model.Tag, error) {
+	tag, err := s.findTag(ctx, tagID)
+	if err != nil {
+		return nil, err
+	}
+	if tag.HiddenAt != nil {
+		return tag, nil
+	}
+	if !tag.IsPublic {
+		return nil, ErrTagNotPublic
+	}
+
+	now := s.now()
+	err = s.withTx(ctx, func(repo repository.ModerationRepository) error {
+		if err := repo.HideTag(ctx, tag.ID, now); err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrTagNotPublic // 同時に非表示・非公開・削除された
+			}
+			return err
+		}
+		return recordAdminAction(ctx, repo, actorUserID, model.AdminAuditActionTagHidden, model.AdminAuditTargetTag, tag.ID, nil, now)
+	})
+	if err != nil {
+		return nil, err
+	}
+	s.logger.Info("service.HideTag completed",
+		slog.String("actor_user_id", actorUserID),
+		slog.String("tag_id", tag.ID),
+	)
+
+	publicBeforeHidden := tag.IsPublic
+	tag.IsPublic = false
+	tag.PublicBeforeHidden = &publicBeforeHidden
+	tag.HiddenAt = &now
+	tag.UpdatedAt = now
+	return tag, nil
+}
+
+// 非表示にしたタグを、非表示にする前の公開範囲に戻す。
+// 非表示の間に持ち主が非公開にした場合は、非公開のままにする。
+func (s *adminService) UnhideTag(ctx context.Context, actorUserID, tagID string) (*model.Tag, error) {
+	tag, err := s.findTag(ctx, tagID)
+	if err != nil {
+		return nil, err
+	}
+	if tag.HiddenAt == nil {
+		return nil, ErrTagNotHidden
+	}
+	isPublic := tag.PublicBeforeHidden == nil || *tag.PublicBeforeHidden
+
+	now := s.now()
+	err = s.withTx(ctx, func(repo repository.ModerationRepository) error {
+		if err := repo.UnhideTag(ctx, tag.ID, now); err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrTagNotHidden // 同時に再表示・削除された
+			}
+			return err
+		}
+		return recordAdminAction(ctx, repo, actorUserID, model.AdminAuditActionTagUnhidden, model.AdminAuditTargetTag, tag.ID, map[string]any{
+			"is_public": isPublic,
+		}, now)
+	})
+	if err != nil {
+		return nil, err
+	}
+	s.logger.Info("service.UnhideTag completed",
+		slog.String("actor_user_id", actorUserID),
+		slog.String("tag_id", tag.ID),
+	)
+
+	tag.IsPublic = isPublic
+	tag.PublicBeforeHidden = nil
+	tag.HiddenAt = nil
+	tag.UpdatedAt = now
+	return tag, nil
+}
+
+// fn をトランザクション内で実行する。
+// db が未設定の場合（ユニットテストなど）はトランザクションを張らず、保持しているリポジトリで実行する。
+func (s *adminService) withTx(ctx context.Context, fn func(repo repository.ModerationRepository) error) error {
+	if s.db == nil {
+		return fn(s.moderationRepo)
+	}
+	return s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return fn(repository.NewModerationRepository(tx))
+	})
+}
+
+// 管理者による操作を監査ログに記録する。details には変更前後の値など、操作の種類に応じた内容を渡す。
+func recordAdminAction(ctx context.Context, repo repository.ModerationRepository, actorUserID, action, targetType, targetID string, details map[string]any, now time.Time) error {
+	if details == nil {
+		details = map[string]any{}
+	}
+	raw, err := json.Marshal(details)
+	if err != nil {
+		return err
+	}
+	entry := &model.AdminAuditLog{
+		Action:     action,
+		TargetType: targetType,
+		TargetID:   targetID,
+		Details:    datatypes.JSON(raw),
+		CreatedAt:  now,
+	}
+	if actorUserID != "" {
+		entry.ActorUserID = &actorUserID
+	}
+	return repo.CreateAuditLog(ctx, entry)
+}
+
+// 操作対象のユーザーを取得する。完全削除済みのユーザーは存在しないものとして扱う。
+func (s *adminService) findUser(ctx context.Context, userID string) (*model.User, error) {
+	if _, err := uuid.Parse(userID); err != nil {
+		return nil, ErrUserNotFound
+	}
+	u, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrUserNotFound
+		}
+		return nil, err
+	}
+	if u.DeletionStatus == model.UserDeletionStatusPurged {
+		return nil, ErrUserNotFound
+	}
+	return u, nil
+}
+
+// 操作対象のタグを取得する。
+func (s *adminService) findTag(ctx context.Context, tagID string) (*model.Tag, error) {
+	if _, err := uuid.Parse(tagID); err != nil {
+		return nil, ErrTagNotFound
+	}
+	tag, err := s.tagRepo.FindByID(ctx, tagID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrTagNotFound
+		}
+		return nil, err
+	}
+	return tag, nil
+}
@@ -0,0 +1,395 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"cinetag-backend/src/internal/model"
+	"cinetag-backend/src/internal/repository"
+	"cinetag-backend/src/internal/testutil"
+
+	"gorm.io/gorm"
+)
+
+const (
+	testAdminUserID  = "11111111-1111-1111-1111-111111111111"
+	testTargetUserID = "22222222-2222-2222-2222-222222222222"
+	testHiddenTagID  = "33333333-3333-3333-3333-333333333333"
+)
+
+func newTestAdminService(moderationRepo *testutil.FakeModerationRepository, userRepo *fakeUserRepo, tagRepo *testutil.FakeTagRepository, now time.Time) AdminService {
+	if moderationRepo == nil {
+		moderationRepo = &testutil.FakeModerationRepository{}
+	}
+	if userRepo == nil {
+		userRepo = &fakeUserRepo{}
+	}
+	if tagRepo == nil {
+		tagRepo = &testutil.FakeTagRepository{}
+	}
+	svc := NewAdminService(testutil.NewTestLogger(), nil, moderationRepo, userRepo, tagRepo).(*adminService)
+	svc.now = func() time.Time { return now }
+	return svc
+}
+
+func userRepoReturning(u *model.User) *fakeUserRepo {
+	return &fakeUserRepo{
+		FindByIDFn: func(ctx context.Context, userID string) (*model.User, error) {
+			if u == nil || userID != u.ID {
+				return nil, gorm.ErrRecordNotFound
+			}
+			copied := *u
+			return &copied, nil
+		},
+	}
+}
+
+func TestAdminService_ListUsers(t *testing.T) {
+	t.Parallel()
+
+	t.Run("絞り込み条件が不正な場合は ErrInvalidAdminUserFilter", func(t *testing.T) {
+		t.Parallel()
+
+		svc := newTestAdminService(nil, nil, nil, time.Now())
+		for _, in := range []ListAdminUsersInput{{Status: "banned"}, {Role: "owner"}} {
+			if _, _, err := svc.ListUsers(context.Background(), in); !errors.Is(err, ErrInvalidAdminUserFilter) {
+				t.Fatalf("%+v: expected ErrInvalidAdminUserFilter, got %v", in, err)
+			}
+		}
+	})
+
+	t.Run("ページングを補正して検索する", func(t *testing.T) {
+		t.Parallel()
+
+		var got repository.AdminUserFilter
+		moderationRepo := &testutil.FakeModerationRepository{
+			ListUsersFn: func(ctx context.Context, filter repository.AdminUserFilter) ([]*model.User, int64, error) {
+				got = filter
+				return []*model.User{{ID: testTargetUserID}}, 41, nil
+			},
+		}
+		svc := newTestAdminService(moderationRepo, nil, nil, time.Now())
+		users, total, err := svc.ListUsers(context.Background(), ListAdminUsersInput{
+			Query:    "  alice ",
+			Status:   repository.AdminUserStatusSuspended,
+			Role:     model.UserRoleUser,
+			Page:     3,
+			PageSize: 500,
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(users) != 1 || total != 41 {
+			t.Fatalf("unexpected result: users=%d total=%d", len(users), total)
+		}
+		want := repository.AdminUserFilter{Query: "alice", Status: repository.AdminUserStatusSuspended, Role: model.UserRoleUser, Offset: 40, Limit: 20}
+		if got != want {
+			t.Fatalf("expected filter %+v, got %+v", want, got)
+		}
+	})
+}
+
+func TestAdminService_UpdateUserRole(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+
+	t.Run("不正なロール・自分自身の変更はエラー", func(t *testing.T) {
+		t.Parallel()
+
+		svc := newTestAdminService(nil, userRepoReturning(&model.User{ID: testTargetUserID}), nil, now)
+		if _, err := svc.UpdateUserRole(context.Background(), testAdminUserID, testTargetUserID, "owner"); !errors.Is(err, ErrInvalidUserRole) {
+			t.Fatalf("expected ErrInvalidUserRole, got %v", err)
+		}
+		if _, err := svc.UpdateUserRole(context.Background(), testAdminUserID, testAdminUserID, model.UserRoleUser); !errors.Is(err, ErrCannotModerateSelf) {
+			t.Fatalf("expected ErrCannotModerateSelf, got %v", err)
+		}
+	})
+
+	t.Run("存在しない・完全削除済みのユーザーは ErrUserNotFound", func(t *testing.T) {
+		t.Parallel()
+
+		purged := &model.User{ID: testTargetUserID, DeletionStatus: model.UserDeletionStatusPurged}
+		svc := newTestAdminService(nil, userRepoReturning(purged), nil, now)
+		for _, userID := range []string{"not-a-uuid", testAdminUserID, testTargetUserID} {
+			if _, err := svc.UpdateUserRole(context.Background(), "", userID, model.UserRoleAdmin); !errors.Is(err, ErrUserNotFound) {
+				t.Fatalf("%s: expected ErrUserNotFound, got %v", userID, err)
+			}
+		}
+	})
+
+	t.Run("ロールを変更し、監査ログに記録する", func(t *testing.T) {
+		t.Parallel()
+
+		var gotRole string
+		var audit *model.AdminAuditLog
+		moderationRepo := &testutil.FakeModerationRepository{
+			UpdateUserRoleFn: func(ctx context.Context, userID, role string, at time.Time) error {
+				gotRole = role
+				return nil
+			},
+			CreateAuditLogFn: func(ctx context.Context, entry *model.AdminAuditLog) error {
+				audit = entry
+				return nil
+			},
+		}
+		target := &model.User{ID: testTargetUserID, Role: model.UserRoleUser, DeletionStatus: model.UserDeletionStatusActive}
+		svc := newTestAdminService(moderationRepo, userRepoReturning(target), nil, now)
+		u, err := svc.UpdateUserRole(context.Background(), testAdminUserID, testTargetUserID, model.UserRoleAdmin)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if gotRole != model.UserRoleAdmin || !u.IsAdmin() {
+			t.Fatalf("expected role to be admin, got repo=%q user=%q", gotRole, u.Role)
+		}
+		if audit == nil || audit.Action != model.AdminAuditActionUserRoleUpdated || audit.ActorUserID == nil || *audit.ActorUserID != testAdminUserID || audit.TargetID != testTargetUserID {
+			t.Fatalf("unexpected audit log: %+v", audit)
+		}
+		if string(audit.Details) != `{"previous_role":"user","role":"admin"}` {
+			t.Fatalf("unexpected audit details: %s", audit.Details)
+		}
+	})
+}
+
+func TestAdminService_SuspendAndRestoreUser(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+
+	t.Run("利用停止: 退会状態として扱う", func(t *testing.T) {
+		t.Parallel()
+
+		called := false
+		moderationRepo := &testutil.FakeModerationRepository{
+			MarkUserSuspendedFn: func(ctx context.Context, userID string, at time.Time) error {
+				called = true
+				return nil
+			},
+		}
+		target := &model.User{ID: testTargetUserID, DeletionStatus: model.UserDeletionStatusActive}
+		svc := newTestAdminService(moderationRepo, userRepoReturning(target), nil, now)
+		u, err := svc.SuspendUser(context.Background(), testAdminUserID, testTargetUserID)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !called {
+			t.Fatalf("expected MarkUserSuspended to be called")
+		}
+		if u.SuspendedAt == nil || u.DeletedAt == nil || u.DeletionStatus != model.UserDeletionStatusDeactivated {
+			t.Fatalf("expected suspended user, got %+v", u)
+		}
+	})
+
+	t.Run("利用停止: 同時に完全削除された場合は ErrUserNotFound", func(t *testing.T) {
+		t.Parallel()
+
+		moderationRepo := &testutil.FakeModerationRepository{
+			MarkUserSuspendedFn: func(ctx context.Context, userID string, at time.Time) error {
+				return gorm.ErrRecordNotFound
+			},
+			CreateAuditLogFn: func(ctx context.Context, entry *model.AdminAuditLog) error {
+				t.Fatalf("unexpected CreateAuditLog call")
+				return nil
+			},
+		}
+		target := &model.User{ID: testTargetUserID, DeletionStatus: model.UserDeletionStatusActive}
+		svc := newTestAdminService(moderationRepo, userRepoReturning(target), nil, now)
+		if _, err := svc.SuspendUser(context.Background(), testAdminUserID, testTargetUserID); !errors.Is(err, ErrUserNotFound) {
+			t.Fatalf("expected ErrUserNotFound, got %v", err)
+		}
+	})
+
+	t.Run("利用停止: 自分自身は ErrCannotModerateSelf、停止中なら何もしない", func(t *testing.T) {
+		t.Parallel()
+
+		moderationRepo := &testutil.FakeModerationRepository{
+			MarkUserSuspendedFn: func(ctx context.Context, userID string, at time.Time) error {
+				t.Fatalf("unexpected MarkUserSuspended call")
+				return nil
+			},
+		}
+		suspendedAt := now.Add(-time.Hour)
+		target := &model.User{ID: testTargetUserID, SuspendedAt: &suspendedAt, DeletedAt: &suspendedAt, DeletionStatus: model.UserDeletionStatusDeactivated}
+		svc := newTestAdminService(moderationRepo, userRepoReturning(target), nil, now)
+		if _, err := svc.SuspendUser(context.Background(), testAdminUserID, testAdminUserID); !errors.Is(err, ErrCannotModerateSelf) {
+			t.Fatalf("expected ErrCannotModerateSelf, got %v", err)
+		}
+		u, err := svc.SuspendUser(context.Background(), testAdminUserID, testTargetUserID)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !u.SuspendedAt.Equal(suspendedAt) {
+			t.Fatalf("expected suspended_at to be unchanged, got %v", u.SuspendedAt)
+		}
+	})
+
+	t.Run("復帰: 利用停止中でない場合は ErrUserNotSuspended（本人による退会は取り消さない）", func(t *testing.T) {
+		t.Parallel()
+
+		moderationRepo := &testutil.FakeModerationRepository{
+			MarkUserRestoredFn: func(ctx context.Context, userID string, at time.Time) error {
+				t.Fatalf("unexpected MarkUserRestored call")
+				return nil
+			},
+		}
+		deletedAt := now.Add(-24 * time.Hour)
+		purgeAt := now.Add(29 * 24 * time.Hour)
+		for _, target := range []*model.User{
+			{ID: testTargetUserID, DeletionStatus: model.UserDeletionStatusActive},
+			{ID: testTargetUserID, DeletedAt: &deletedAt, DeletionStatus: model.UserDeletionStatusDeactivated, PurgeScheduledAt: &purgeAt},
+		} {
+			svc := newTestAdminService(moderationRepo, userRepoReturning(target), nil, now)
+			if _, err := svc.RestoreUser(context.Background(), testAdminUserID, testTargetUserID); !errors.Is(err, ErrUserNotSuspended) {
+				t.Fatalf("%s: expected ErrUserNotSuspended, got %v", target.DeletionStatus, err)
+			}
+		}
+	})
+
+	t.Run("復帰: 同時に解除された場合は ErrUserNotSuspended", func(t *testing.T) {
+		t.Parallel()
+
+		moderationRepo := &testutil.FakeModerationRepository{
+			MarkUserRestoredFn: func(ctx context.Context, userID string, at time.Time) error {
+				return gorm.ErrRecordNotFound
+			},
+		}
+		suspendedAt := now.Add(-time.Hour)
+		target := &model.User{ID: testTargetUserID, SuspendedAt: &suspendedAt, DeletedAt: &suspendedAt, DeletionStatus: model.UserDeletionStatusDeactivated}
+		svc := newTestAdminService(moderationRepo, userRepoReturning(target), nil, now)
+		if _, err := svc.RestoreUser(context.Background(), testAdminUserID, testTargetUserID); !errors.Is(err, ErrUserNotSuspended) {
+			t.Fatalf("expected ErrUserNotSuspended, got %v", err)
+		}
+	})
+
+	t.Run("復帰: 利用停止を解除して通常状態に戻す", func(t *testing.T) {
+		t.Parallel()
+
+		called := false
+		var audit *model.AdminAuditLog
+		moderationRepo := &testutil.FakeModerationRepository{
+			MarkUserRestoredFn: func(ctx context.Context, userID string, at time.Time) error {
+				called = true
+				return nil
+			},
+			CreateAuditLogFn: func(ctx context.Context, entry *model.AdminAuditLog) error {
+				audit = entry
+				return nil
+			},
+		}
+		suspendedAt := now.Add(-time.Hour)
+		target := &model.User{ID: testTargetUserID, SuspendedAt: &suspendedAt, DeletedAt: &suspendedAt, DeletionStatus: model.UserDeletionStatusDeactivated}
+		svc := newTestAdminService(moderationRepo, userRepoReturning(target), nil, now)
+		u, err := svc.RestoreUser(context.Background(), testAdminUserID, testTargetUserID)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !called {
+			t.Fatalf("expected MarkUserRestored to be called")
+		}
+		if u.SuspendedAt != nil || u.DeletedAt != nil || u.DeletionStatus != model.UserDeletionStatusActive {
+			t.Fatalf("expected active user, got %+v", u)
+		}
+		if audit == nil || audit.Action != model.AdminAuditActionUserRestored {
+			t.Fatalf("unexpected audit log: %+v", audit)
+		}
+	})
+
+	t.Run("復帰: 本人が退会していた場合は退会状態と完全削除の予定を残す", func(t *testing.T) {
+		t.Parallel()
+
+		deletedAt := now.Add(-24 * time.Hour)
+		suspendedAt := now.Add(-time.Hour)
+		purgeAt := now.Add(29 * 24 * time.Hour)
+		target := &model.User{
+			ID:               testTargetUserID,
+			SuspendedAt:      &suspendedAt,
+			DeletedAt:        &deletedAt,
+			DeletionStatus:   model.UserDeletionStatusDeactivated,
+			PurgeScheduledAt: &purgeAt,
+		}
+		svc := newTestAdminService(nil, userRepoReturning(target), nil, now)
+		u, err := svc.RestoreUser(context.Background(), testAdminUserID, testTargetUserID)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if u.SuspendedAt != nil || u.DeletedAt == nil || u.PurgeScheduledAt == nil || u.DeletionStatus != model.UserDeletionStatusDeactivated {
+			t.Fatalf("expected deactivated user without suspension, got %+v", u)
+		}
+	})
+}
+
+func TestAdminService_HideAndUnhideTag(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	tagRepoReturning := func(tag *model.Tag) *testutil.FakeTagRepository {
+		return &testutil.FakeTagRepository{
+			FindByIDFn: func(ctx context.Context, id string) (*model.Tag, error) {
+				if id != tag.ID {
+					return nil, gorm.ErrRecordNotFound
+				}
+				copied := *tag
+				return &copied, nil
+			},
+		}
+	}
+
+	t.Run("非表示: 公開タグを非公開にする", func(t *testing.T) {
+		t.Parallel()
+
+		called := false
+		moderationRepo := &testutil.FakeModerationRepository{
+			HideTagFn: func(ctx context.Context, tagID string, at time.Time) error {
+				called = true
+				return nil
+			},
+		}
+		svc := newTestAdminService(moderationRepo, nil, tagRepoReturning(&model.Tag{ID: testHiddenTagID, IsPublic: true}), now)
+		tag, err := svc.HideTag(context.Background(), testAdminUserID, testHiddenTagID)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !called || tag.IsPublic || tag.HiddenAt == nil {
+			t.Fatalf("expected hidden tag, got called=%v tag=%+v", called, tag)
+		}
+	})
+
+	t.Run("非表示: 非公開のタグは ErrTagNotPublic、存在しないタグは ErrTagNotFound", func(t *testing.T) {
+		t.Parallel()
+
+		svc := newTestAdminService(nil, nil, tagRepoReturning(&model.Tag{ID: testHiddenTagID, IsPublic: false}), now)
+		if _, err := svc.HideTag(context.Background(), testAdminUserID, testHiddenTagID); !errors.Is(err, ErrTagNotPublic) {
+			t.Fatalf("expected ErrTagNotPublic, got %v", err)
+		}
+		if _, err := svc.HideTag(context.Background(), testAdminUserID, testTargetUserID); !errors.Is(err, ErrTagNotFound) {
+			t.Fatalf("expected ErrTagNotFound, got %v", err)
+		}
+	})
+
+	t.Run("公開に戻す: 非表示でないタグは ErrTagNotHidden", func(t *testing.T) {
+		t.Parallel()
+
+		svc := newTestAdminService(nil, nil, tagRepoReturning(&model.Tag{ID: testHiddenTagID, IsPublic: true}), now)
+		if _, err := svc.UnhideTag(context.Background(), testAdminUserID, testHiddenTagID); !errors.Is(err, ErrTagNotHidden) {
+			t.Fatalf("expected ErrTagNotHidden, got %v", err)
+		}
+	})
+
+	t.Run("公開に戻す: 非表示にする前の公開範囲に戻す", func(t *testing.T) {
+		t.Parallel()
+
+		hiddenAt := now.Add(-time.Hour)
+		for _, publicBeforeHidden := range []bool{true, false} {
+			svc := newTestAdminService(nil, nil, tagRepoReturning(&model.Tag{ID: testHiddenTagID, HiddenAt: &hiddenAt, PublicBeforeHidden: &publicBeforeHidden}), now)
+			tag, err := svc.UnhideTag(context.Background(), testAdminUserID, testHiddenTagID)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if tag.IsPublic != publicBeforeHidden || tag.HiddenAt != nil {
+				t.Fatalf("expected is_public=%v, got %+v", publicBeforeHidden, tag)
+			}
+		}
+	})
+}
@@ -9,6 +9,54 @@ var (
 	ErrClerkUserInfoInvalid = errors.New("invalid clerk user info")
 )
 
+// Clerk の user.created / user.updated Webhook の data 部分を表します。
+// https://clerk.com/docs/guides/development/webhooks/overview#payload-structure
+type ClerkWebhookUserData struct {
+	ID                    string                     `json:"id"`
+	Username              string                     `json:"username"`
+	FirstName             string                     `json:"first_name"`
+	LastName              string                     `json:"last_name"`
+	ImageURL              string                     `json:"image_url"`
+	PrimaryEmailAddressID string                     `json:"primary_email_address_id"`
+	EmailAddresses        []ClerkWebhookEmailAddress `json:"email_addresses"`
+	UpdatedAt             int64                      `json:"updated_at"` // UNIX ミリ秒
+}
+
+// Clerk ユーザーのメールアドレスを表します。
+type ClerkWebhookEmailAddress struct {
+	ID           string `json:"id"`
+	EmailAddress string `json:"email_address"`
+}
+
+// PrimaryEmail はプライマリのメールアドレス（primary_email_address_id のもの）を返します。
+// プライマリが見つからない場合は先頭のメールアドレスを返します。
+func (d ClerkWebhookUserData) PrimaryEmail() string {
+	for _, e := range d.EmailAddresses {
+		if d.PrimaryEmailAddressID != "" && e.ID == d.PrimaryEmailAddressID {
+			return e.EmailAddress
+		}
+	}
+	if len(d.EmailAddresses) > 0 {
+		return d.EmailAddresses[0].EmailAddress
+	}
+	return ""
+}
+
+// ToClerkUserInfo は Webhook の data から ClerkUserInfo を構築します。
+func (d ClerkWebhookUserData) ToClerkUserInfo() (ClerkUserInfo, error) {
+	var imageURL *string
+	if d.ImageURL != "" {
+		url := d.ImageURL
+		imageURL = &url
+	}
+	info, err := NewClerkUserInfoFromWebhook(d.ID, d.PrimaryEmail(), d.FirstName, d.LastName, imageURL)
+	if err != nil {
+		return ClerkUserInfo{}, err
+	}
+	info.Username = strings.TrimSpace(d.Username)
+	return info, nil
+}
+
 // Webhook の生フィールドから ClerkUserInfo を構築します。
 func NewClerkUserInfoFromWebhook(id, email, firstName, lastName string, imageURL *string) (ClerkUserInfo, error) {
 	id = strings.TrimSpace(id)
@@ -35,6 +83,7 @@ func NewClerkUserInfoFromWebhook(id, email, firstName, lastName string, imageURL
 // - FirstName: claim["first_name"]
 // - LastName: claim["last_name"]
 // - AvatarURL: claim["image_url"]
+// - Username: claim["username"]（セッショントークンに追加している場合のみ）
 //
 // Email は必須です（空の場合は error を返します）。
 func NewClerkUserInfoFromJWTClaims(claims map[string]any) (ClerkUserInfo, error) {
@@ -54,9 +103,20 @@ func NewClerkUserInfoFromJWTClaims(claims map[string]any) (ClerkUserInfo, error)
 		FirstName: firstName,
 		LastName:  lastName,
 		AvatarURL: imageURL,
+		Username:  trimStringClaim(claims, "username"),
 	}, nil
 }
 
+// Clerk のユーザー名を display_id として使える形に正規化して返す。
+// 形式が不正・予約語の場合は空文字を返す（重複チェックは呼び出し側で行う）。
+func clerkUsernameDisplayID(clerkInfo ClerkUserInfo) string {
+	displayID := NormalizeCustomUserDisplayID(clerkInfo.Username)
+	if displayID == "" || ValidateCustomUserDisplayID(displayID) != nil {
+		return ""
+	}
+	return displayID
+}
+
 func trimStringClaim(claims map[string]any, key string) string {
 	v, ok := claims[key].(string)
 	if !ok {
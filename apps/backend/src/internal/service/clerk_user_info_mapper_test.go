@@ -392,6 +392,83 @@ func TestNewClerkUserInfoFromJWTClaims(t *testing.T) {
 	}
 }
 
+func TestClerkWebhookUserData_ToClerkUserInfo(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		data      ClerkWebhookUserData
+		wantEmail string
+		wantErr   bool
+	}{
+		{
+			name: "primary_email_address_id のメールアドレスを使う",
+			data: ClerkWebhookUserData{
+				ID:                    "user_123",
+				PrimaryEmailAddressID: "idn_2",
+				EmailAddresses: []ClerkWebhookEmailAddress{
+					{ID: "idn_1", EmailAddress: "old@example.com"},
+					{ID: "idn_2", EmailAddress: "primary@example.com"},
+				},
+			},
+			wantEmail: "primary@example.com",
+		},
+		{
+			name: "プライマリが見つからない場合は先頭のメールアドレスを使う",
+			data: ClerkWebhookUserData{
+				ID:                    "user_123",
+				PrimaryEmailAddressID: "idn_unknown",
+				EmailAddresses: []ClerkWebhookEmailAddress{
+					{ID: "idn_1", EmailAddress: "first@example.com"},
+				},
+			},
+			wantEmail: "first@example.com",
+		},
+		{
+			name:    "メールアドレスがない場合はエラー",
+			data:    ClerkWebhookUserData{ID: "user_123"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := tt.data.ToClerkUserInfo()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ToClerkUserInfo() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if got.Email != tt.wantEmail {
+				t.Errorf("Email = %v, want %v", got.Email, tt.wantEmail)
+			}
+		})
+	}
+
+	t.Run("ユーザー名と画像URLを変換する", func(t *testing.T) {
+		t.Parallel()
+
+		got, err := ClerkWebhookUserData{
+			ID:             "user_123",
+			Username:       " john ",
+			ImageURL:       "https://example.com/avatar.png",
+			EmailAddresses: []ClerkWebhookEmailAddress{{ID: "idn_1", EmailAddress: "john@example.com"}},
+		}.ToClerkUserInfo()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got.Username != "john" {
+			t.Errorf("Username = %q, want %q", got.Username, "john")
+		}
+		if !strPtrEqual(got.AvatarURL, strPtr("https://example.com/avatar.png")) {
+			t.Errorf("AvatarURL = %v", strPtrVal(got.AvatarURL))
+		}
+	})
+}
+
 // Helper functions for tests
 
 func strPtr(s string) *string {
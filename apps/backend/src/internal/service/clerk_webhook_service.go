@@ -0,0 +1,169 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"os"
+	"strings"
+	"time"
+
+	"cinetag-backend/src/internal/model"
+	"cinetag-backend/src/internal/repository"
+	"cinetag-backend/src/internal/webhook"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Clerk Webhook のメッセージを処理中として確保する期間。
+// 処理中のまま期間を過ぎたメッセージ（処理中にプロセスが停止した場合など）は、再送で処理し直す。
+const clerkWebhookEventLease = time.Minute
+
+// Clerk Webhook の署名を検証できない場合のエラー。
+var ErrInvalidClerkWebhookSignature = errors.New("invalid webhook signature")
+
+// 保存済みの Clerk ユーザーの Webhook ペイロードが存在しない場合のエラー。
+var ErrClerkUserPayloadNotFound = errors.New("clerk user payload not found")
+
+// 同じ svix-id のメッセージを他のリクエストが処理中の場合のエラー。
+var ErrClerkWebhookEventInProgress = errors.New("clerk webhook event is being processed")
+
+// ClerkWebhookClaim は処理中として確保した Clerk Webhook のメッセージを表す。
+// 処理の完了（CompleteEvent、またはユーザー情報の同期と同じトランザクション）・取り消し（AbortEvent）に使う。
+type ClerkWebhookClaim struct {
+	SvixID    string
+	LockToken string
+}
+
+// Clerk Webhook の設定値。
+type ClerkWebhookConfig struct {
+	// Secrets は Clerk のダッシュボードで発行した署名鍵（"whsec_..."）。
+	// 署名鍵のローテーション中は新旧の鍵を指定する。空の場合はすべてのリクエストを拒否する。
+	Secrets []string
+	// Tolerance は送信時刻と受信時刻のずれの許容範囲（0 の場合は5分）。
+	Tolerance time.Duration
+}
+
+// 環境変数から Clerk Webhook の設定値を読み込む。
+// CLERK_WEBHOOK_SECRET には署名鍵をカンマまたは空白区切りで複数指定できる（ローテーション用）。
+func ClerkWebhookConfigFromEnv() ClerkWebhookConfig {
+	secrets := strings.FieldsFunc(os.Getenv("CLERK_WEBHOOK_SECRET"), func(r rune) bool {
+		return r == ',' || r == ' ' || r == '\t' || r == '\n'
+	})
+	return ClerkWebhookConfig{Secrets: secrets}
+}
+
+// Clerk Webhook の受信に関するユースケースを表すインターフェース。
+type ClerkWebhookService interface {
+	// svix-id / svix-timestamp / svix-signature ヘッダーと本文から署名を検証する。
+	// 検証できない場合は ErrInvalidClerkWebhookSignature を返す。
+	VerifySignature(svixID, timestamp, signature string, body []byte) error
+	// メッセージを処理中として確保し、処理を開始する。
+	// - 同じ svix-id のメッセージを処理済みの場合は nil を返す。
+	// - 他のリクエストが処理中の場合は ErrClerkWebhookEventInProgress を返す（期限を過ぎていれば処理を引き継ぐ）。
+	BeginEvent(ctx context.Context, svixID, eventType string) (*ClerkWebhookClaim, error)
+	// 確保したメッセージを処理済みにする。
+	CompleteEvent(ctx context.Context, claim *ClerkWebhookClaim) error
+	// 処理に失敗したメッセージの記録を取り消し、再送されたときに処理できるようにする。
+	AbortEvent(ctx context.Context, claim *ClerkWebhookClaim) error
+	// user.created / user.updated のペイロードをユーザーごとの最新の状態として保存する。
+	// 保存済みのものより古いペイロード（updated_at が小さい）の場合は保存せず false を返す。
+	RecordUserPayload(ctx context.Context, svixID, eventType string, data ClerkWebhookUserData, raw json.RawMessage) (bool, error)
+	// 保存済みの最新のペイロードを取得する。存在しない場合は ErrClerkUserPayloadNotFound を返す。
+	FindUserPayload(ctx context.Context, clerkUserID string) (*ClerkWebhookUserData, error)
+}
+
+type clerkWebhookService struct {
+	logger    *slog.Logger
+	eventRepo repository.ClerkWebhookEventRepository
+	verifier  webhook.SvixVerifier // 署名鍵が不正・未設定の場合は nil
+	now       func() time.Time
+}
+
+// ClerkWebhookService を生成する。
+// 署名鍵が未設定・不正な場合はエラーログを出し、すべてのリクエストを拒否する。
+func NewClerkWebhookService(logger *slog.Logger, eventRepo repository.ClerkWebhookEventRepository, cfg ClerkWebhookConfig) ClerkWebhookService {
+	verifier, err := webhook.NewSvixVerifier(cfg.Secrets, cfg.Tolerance)
+	if err != nil {
+		logger.Error("service.NewClerkWebhookService: CLERK_WEBHOOK_SECRET is missing or invalid; all Clerk webhooks will be rejected",
+			slog.Any("error", err),
+		)
+	}
+	return &clerkWebhookService{
+		logger:    logger,
+		eventRepo: eventRepo,
+		verifier:  verifier,
+		now:       time.Now,
+	}
+}
+
+// 署名を検証する。
+func (s *clerkWebhookService) VerifySignature(svixID, timestamp, signature string, body []byte) error {
+	if s.verifier == nil {
+		return ErrInvalidClerkWebhookSignature
+	}
+	if err := s.verifier.Verify(svixID, timestamp, signature, body, s.now()); err != nil {
+		return ErrInvalidClerkWebhookSignature
+	}
+	return nil
+}
+
+// メッセージの処理を開始する。
+func (s *clerkWebhookService) BeginEvent(ctx context.Context, svixID, eventType string) (*ClerkWebhookClaim, error) {
+	now := s.now()
+	claim := &ClerkWebhookClaim{SvixID: svixID, LockToken: uuid.NewString()}
+	began, err := s.eventRepo.Claim(ctx, svixID, eventType, claim.LockToken, now, now.Add(clerkWebhookEventLease))
+	if err != nil {
+		if errors.Is(err, repository.ErrClerkWebhookEventInProgress) {
+			return nil, ErrClerkWebhookEventInProgress
+		}
+		return nil, err
+	}
+	if !began {
+		return nil, nil
+	}
+	return claim, nil
+}
+
+// メッセージを処理済みにする。
+func (s *clerkWebhookService) CompleteEvent(ctx context.Context, claim *ClerkWebhookClaim) error {
+	return s.eventRepo.MarkProcessed(ctx, claim.SvixID, claim.LockToken, s.now())
+}
+
+// メッセージの記録を取り消す。
+func (s *clerkWebhookService) AbortEvent(ctx context.Context, claim *ClerkWebhookClaim) error {
+	return s.eventRepo.Release(ctx, claim.SvixID, claim.LockToken)
+}
+
+// ユーザーのペイロードを保存する。
+func (s *clerkWebhookService) RecordUserPayload(ctx context.Context, svixID, eventType string, data ClerkWebhookUserData, raw json.RawMessage) (bool, error) {
+	if data.ID == "" {
+		return false, errors.New("clerk user id is required")
+	}
+	return s.eventRepo.SaveUserPayload(ctx, &model.ClerkUserPayload{
+		ClerkUserID:    data.ID,
+		SvixID:         svixID,
+		EventType:      eventType,
+		Payload:        []byte(raw),
+		ClerkUpdatedAt: data.UpdatedAt,
+		ReceivedAt:     s.now(),
+	})
+}
+
+// 保存済みのペイロードを取得する。
+func (s *clerkWebhookService) FindUserPayload(ctx context.Context, clerkUserID string) (*ClerkWebhookUserData, error) {
+	p, err := s.eventRepo.FindUserPayload(ctx, clerkUserID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrClerkUserPayloadNotFound
+		}
+		return nil, err
+	}
+	var data ClerkWebhookUserData
+	if err := json.Unmarshal(p.Payload, &data); err != nil {
+		return nil, err
+	}
+	return &data, nil
+}
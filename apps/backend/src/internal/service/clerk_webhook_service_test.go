@@ -0,0 +1,50 @@
+package service
+
+import (
+	"errors"
+	"reflect"
+	"strconv"
+	"testing"
+	"time"
+
+	"cinetag-backend/src/internal/testutil"
+	"cinetag-backend/src/internal/webhook"
+)
+
+func TestClerkWebhookConfigFromEnv(t *testing.T) {
+	t.Setenv("CLERK_WEBHOOK_SECRET", " whsec_new, whsec_old ")
+
+	got := ClerkWebhookConfigFromEnv()
+	if want := []string{"whsec_new", "whsec_old"}; !reflect.DeepEqual(got.Secrets, want) {
+		t.Fatalf("expected %v, got %v", want, got.Secrets)
+	}
+}
+
+func TestClerkWebhookService_VerifySignature(t *testing.T) {
+	t.Parallel()
+
+	const secret = "whsec_dGVzdC1jbGVyay13ZWJob29rLXNlY3JldA=="
+	body := []byte(`{"type":"user.deleted","data":{"id":"user_123"}}`)
+	now := time.Unix(1700000000, 0)
+	signature, err := webhook.SignSvix(secret, "msg_1", now, body)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	ts := strconv.FormatInt(now.Unix(), 10)
+
+	svc := NewClerkWebhookService(testutil.NewTestLogger(), &testutil.FakeClerkWebhookEventRepository{}, ClerkWebhookConfig{Secrets: []string{secret}}).(*clerkWebhookService)
+	svc.now = func() time.Time { return now }
+
+	if err := svc.VerifySignature("msg_1", ts, signature, body); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := svc.VerifySignature("msg_1", ts, signature, []byte(`{}`)); !errors.Is(err, ErrInvalidClerkWebhookSignature) {
+		t.Fatalf("expected ErrInvalidClerkWebhookSignature, got %v", err)
+	}
+
+	// 署名鍵が不正な場合はすべて拒否する
+	invalid := NewClerkWebhookService(testutil.NewTestLogger(), &testutil.FakeClerkWebhookEventRepository{}, ClerkWebhookConfig{Secrets: []string{"whsec_"}})
+	if err := invalid.VerifySignature("msg_1", ts, signature, body); !errors.Is(err, ErrInvalidClerkWebhookSignature) {
+		t.Fatalf("expected ErrInvalidClerkWebhookSignature, got %v", err)
+	}
+}
@@ -0,0 +1,54 @@
+package service
+
+import (
+	"encoding/base64"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// カーソルの形式が不正な場合のエラー（呼び出し側で用途ごとのエラーに変換する）。
+var errInvalidCursor = errors.New("invalid cursor")
+
+// カーソルを生成する（最後に読み込んだ行の日時とIDを URL セーフな文字列にする）。
+func encodeCursor(at time.Time, id string) string {
+	raw := at.UTC().Format(time.RFC3339Nano) + "|" + id
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+// カーソルを日時とIDに分解する。
+func decodeCursor(cursor string) (time.Time, string, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return time.Time{}, "", errInvalidCursor
+	}
+	ts, id, ok := strings.Cut(string(raw), "|")
+	if !ok {
+		return time.Time{}, "", errInvalidCursor
+	}
+	if _, err := uuid.Parse(id); err != nil {
+		return time.Time{}, "", errInvalidCursor
+	}
+	at, err := time.Parse(time.RFC3339Nano, ts)
+	if err != nil {
+		return time.Time{}, "", errInvalidCursor
+	}
+	return at, id, nil
+}
+
+// 通知ストリームのカーソルを生成する（通知の stream_seq を文字列にする）。
+func encodeStreamCursor(seq int64) string {
+	return strconv.FormatInt(seq, 10)
+}
+
+// 通知ストリームのカーソルを stream_seq に戻す。
+func decodeStreamCursor(cursor string) (int64, error) {
+	seq, err := strconv.ParseInt(cursor, 10, 64)
+	if err != nil || seq < 0 {
+		return 0, errInvalidCursor
+	}
+	return seq, nil
+}
@@ -3,6 +3,8 @@ package service
 import (
 	"context"
 	"math/rand"
+	"regexp"
+	"strings"
 
 	"cinetag-backend/src/internal/repository"
 )
@@ -11,8 +13,54 @@ const (
 	userDisplayIDPrefix    = "user-"
 	userDisplayIDSuffixLen = 6
 	userDisplayIDChars     = "abcdefghijklmnopqrstuvwxyz0123456789"
+
+	// ユーザーが任意に設定する display_id の長さ制限。
+	customDisplayIDMinLen = 3
+	customDisplayIDMaxLen = 30
 )
 
+// 任意設定の display_id として許可する形式。
+// - 英小文字・数字・ハイフン・アンダースコアのみ
+// - 先頭と末尾は英小文字または数字
+var customDisplayIDPattern = regexp.MustCompile(`^[a-z0-9](?:[a-z0-9_-]*[a-z0-9])?$`)
+
+// display_id として使用できない予約語。
+// フロントエンドのトップレベルのパスや、API のパスと衝突するものを含む。
+var reservedDisplayIDs = map[string]struct{}{
+	"about":         {},
+	"admin":         {},
+	"api":           {},
+	"auth":          {},
+	"cinetag":       {},
+	"explore":       {},
+	"feed":          {},
+	"follow":        {},
+	"followers":     {},
+	"following":     {},
+	"help":          {},
+	"login":         {},
+	"logout":        {},
+	"me":            {},
+	"movies":        {},
+	"mypage":        {},
+	"notifications": {},
+	"null":          {},
+	"privacy":       {},
+	"root":          {},
+	"search":        {},
+	"settings":      {},
+	"sign-in":       {},
+	"sign-up":       {},
+	"sso-callback":  {},
+	"support":       {},
+	"system":        {},
+	"tags":          {},
+	"terms":         {},
+	"undefined":     {},
+	"user":          {},
+	"users":         {},
+}
+
 // 指定長のランダム英数字を生成して返す。
 func generateRandomString(n int) string {
 	b := make([]byte, n)
@@ -24,25 +72,53 @@ func generateRandomString(n int) string {
 
 // user display_id を生成する。
 // 重複がないかチェックし、重複していたら再帰的に生成し直します。
-func generateUniqueUserDisplayID(ctx context.Context, userRepo repository.UserRepository) string {
+func generateUniqueUserDisplayID(ctx context.Context, userRepo repository.UserRepository, historyRepo repository.UserDisplayIDHistoryRepository) string {
 	displayID := userDisplayIDPrefix + generateRandomString(userDisplayIDSuffixLen)
 
-	if IsValidUserDisplayID(ctx, userRepo, displayID) {
+	if IsValidUserDisplayID(ctx, userRepo, historyRepo, displayID) {
 		return displayID
 	}
-	return generateUniqueUserDisplayID(ctx, userRepo)
+	return generateUniqueUserDisplayID(ctx, userRepo, historyRepo)
 }
 
 // user display_id を生成する。
-func GenerateUserDisplayID(ctx context.Context, userRepo repository.UserRepository) string {
-	return generateUniqueUserDisplayID(ctx, userRepo)
+// historyRepo が nil の場合は変更履歴による予約をチェックしない。
+func GenerateUserDisplayID(ctx context.Context, userRepo repository.UserRepository, historyRepo repository.UserDisplayIDHistoryRepository) string {
+	return generateUniqueUserDisplayID(ctx, userRepo, historyRepo)
 }
 
 // user display_id が有効かチェックする。
-func IsValidUserDisplayID(ctx context.Context, userRepo repository.UserRepository, displayID string) bool {
+// historyRepo が nil の場合は変更履歴による予約をチェックしない。
+func IsValidUserDisplayID(ctx context.Context, userRepo repository.UserRepository, historyRepo repository.UserDisplayIDHistoryRepository, displayID string) bool {
 	// user display_id がすでに存在する場合は無効。
 	if _, err := userRepo.FindByDisplayID(ctx, displayID); err == nil {
 		return false
 	}
+	// 過去に使用されていた display_id も、リダイレクトを維持するため無効。
+	if historyRepo != nil {
+		if _, err := historyRepo.FindByDisplayID(ctx, displayID); err == nil {
+			return false
+		}
+	}
 	return true
 }
+
+// 任意設定の display_id を正規化する（前後の空白除去・小文字化）。
+func NormalizeCustomUserDisplayID(displayID string) string {
+	return strings.ToLower(strings.TrimSpace(displayID))
+}
+
+// 任意設定の display_id の形式と予約語をチェックする。
+// 重複チェックは行わないため、呼び出し側で別途確認する。
+func ValidateCustomUserDisplayID(displayID string) error {
+	if len(displayID) < customDisplayIDMinLen || len(displayID) > customDisplayIDMaxLen {
+		return ErrInvalidDisplayID
+	}
+	if !customDisplayIDPattern.MatchString(displayID) {
+		return ErrInvalidDisplayID
+	}
+	if _, reserved := reservedDisplayIDs[displayID]; reserved {
+		return ErrReservedDisplayID
+	}
+	return nil
+}
@@ -2,6 +2,8 @@ package service
 
 import (
 	"context"
+	"errors"
+	"strings"
 	"testing"
 
 	"cinetag-backend/src/internal/model"
@@ -24,7 +26,7 @@ func TestGenerateUserDisplayID(t *testing.T) {
 		},
 	}
 
-	got := GenerateUserDisplayID(context.Background(), repo)
+	got := GenerateUserDisplayID(context.Background(), repo, nil)
 
 	if calls < 2 {
 		t.Fatalf("expected retry on collision, calls=%d", calls)
@@ -36,3 +38,53 @@ func TestGenerateUserDisplayID(t *testing.T) {
 		t.Fatalf("expected prefix %q, got %q", userDisplayIDPrefix, got)
 	}
 }
+
+func TestGenerateUserDisplayID_SkipsHistory(t *testing.T) {
+	t.Parallel()
+
+	var calls int
+	historyRepo := &fakeUserDisplayIDHistoryRepo{
+		FindByDisplayIDFn: func(ctx context.Context, displayID string) (*model.UserDisplayIDHistory, error) {
+			calls++
+			// 1回目は「他ユーザーの変更履歴に存在する」扱いにして衝突を起こす
+			if calls == 1 {
+				return &model.UserDisplayIDHistory{UserID: "u_other", DisplayID: displayID}, nil
+			}
+			return nil, gorm.ErrRecordNotFound
+		},
+	}
+
+	got := GenerateUserDisplayID(context.Background(), &fakeUserRepo{}, historyRepo)
+
+	if calls < 2 {
+		t.Fatalf("expected retry on history collision, calls=%d", calls)
+	}
+	if got[:len(userDisplayIDPrefix)] != userDisplayIDPrefix {
+		t.Fatalf("expected prefix %q, got %q", userDisplayIDPrefix, got)
+	}
+}
+
+func TestValidateCustomUserDisplayID(t *testing.T) {
+	t.Parallel()
+
+	valid := []string{"abc", "alice_01", "movie-lover", "a1b", strings.Repeat("a", 30)}
+	for _, id := range valid {
+		if err := ValidateCustomUserDisplayID(id); err != nil {
+			t.Fatalf("expected %q to be valid, got: %v", id, err)
+		}
+	}
+
+	invalid := []string{"", "ab", "Alice", "_alice", "alice-", "a.b.c", strings.Repeat("a", 31)}
+	for _, id := range invalid {
+		if err := ValidateCustomUserDisplayID(id); !errors.Is(err, ErrInvalidDisplayID) {
+			t.Fatalf("expected %q to be invalid, got: %v", id, err)
+		}
+	}
+
+	if err := ValidateCustomUserDisplayID("me"); err == nil {
+		t.Fatalf("expected error for reserved word")
+	}
+	if err := ValidateCustomUserDisplayID("notifications"); !errors.Is(err, ErrReservedDisplayID) {
+		t.Fatalf("expected ErrReservedDisplayID, got: %v", err)
+	}
+}
@@ -0,0 +1,425 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"embed"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	htmltemplate "html/template"
+	"log/slog"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	texttemplate "text/template"
+	"time"
+
+	"cinetag-backend/src/internal/mailer"
+	"cinetag-backend/src/internal/model"
+	"cinetag-backend/src/internal/repository"
+
+	"gorm.io/gorm"
+)
+
+// ダイジェストの配信頻度が不正な場合のエラー。
+var ErrInvalidEmailDigestFrequency = errors.New("frequency must be 'off', 'daily' or 'weekly'")
+
+// 配信停止リンクのトークンが不正な場合のエラー。
+var ErrInvalidUnsubscribeToken = errors.New("invalid unsubscribe token")
+
+// 配信停止用の署名鍵が設定されていない場合のエラー。
+var ErrEmailDigestNotConfigured = errors.New("email digest is not configured")
+
+const (
+	// 毎日・毎週配信の間隔。
+	emailDigestDailyInterval  = 24 * time.Hour
+	emailDigestWeeklyInterval = 7 * 24 * time.Hour
+	// ジョブの実行時刻のずれで配信が1回分飛ばないよう、配信時期の判定に持たせる余裕。
+	emailDigestScheduleTolerance = time.Hour
+	// ダイジェストに載せる通知・映画の最大件数。
+	emailDigestNotificationLimit = 10
+	emailDigestMovieLimit        = 10
+	// 配信停止トークンの署名対象に付ける接頭辞（他用途の署名と区別する）。
+	emailDigestUnsubscribeScope = "email-digest-unsubscribe:"
+	// 配信停止リンクの有効期間（メールの送信時から）。
+	emailDigestUnsubscribeTokenTTL = 60 * 24 * time.Hour
+	// 送信に失敗したユーザーに再送するまでの待ち時間（連続失敗ごとに倍にし、最大値で打ち止めにする）。
+	emailDigestRetryBaseBackoff = 15 * time.Minute
+	emailDigestRetryMaxBackoff  = 24 * time.Hour
+)
+
+//go:embed templates/email_digest.*.tmpl
+var emailDigestTemplateFS embed.FS
+
+var (
+	emailDigestTextTemplate = texttemplate.Must(texttemplate.ParseFS(emailDigestTemplateFS, "templates/email_digest.txt.tmpl"))
+	emailDigestHTMLTemplate = htmltemplate.Must(htmltemplate.ParseFS(emailDigestTemplateFS, "templates/email_digest.html.tmpl"))
+)
+
+// メールダイジェストに必要な設定値。
+type EmailDigestConfig struct {
+	// UnsubscribeSecret は配信停止リンクの署名鍵。
+	UnsubscribeSecret string
+	// APIBaseURL は配信停止リンクの基点となる API の URL（例: https://api.cine-tag.com）。
+	APIBaseURL string
+	// AppBaseURL はメール内のリンク先となるフロントエンドの URL（例: https://cine-tag.com）。
+	AppBaseURL string
+}
+
+// 環境変数からメールダイジェストの設定値を読み込む。
+func EmailDigestConfigFromEnv() EmailDigestConfig {
+	return EmailDigestConfig{
+		UnsubscribeSecret: os.Getenv("EMAIL_UNSUBSCRIBE_SECRET"),
+		APIBaseURL:        os.Getenv("API_BASE_URL"),
+		AppBaseURL:        os.Getenv("APP_BASE_URL"),
+	}
+}
+
+// メールダイジェストの配信設定。
+type EmailDigestSettings struct {
+	Frequency  string     `json:"frequency"`
+	LastSentAt *time.Time `json:"last_sent_at"`
+}
+
+// ダイジェスト配信ジョブの実行結果。
+type EmailDigestRunResult struct {
+	Processed int // 配信時期を迎えて処理したユーザー数（配信済み・再送待ちにしたユーザー）
+	Sent      int // 実際に送信したメール数（載せる内容がないユーザーには送らない）
+	Failed    int // 送信に失敗したユーザー数
+}
+
+// メールダイジェストに関するユースケースを表すインターフェース。
+type EmailDigestService interface {
+	// 配信設定を取得する。未設定のユーザーは配信しない（off）として返す。
+	GetSettings(ctx context.Context, userID string) (*EmailDigestSettings, error)
+	// 配信頻度を更新し、更新後の設定を返す。不正な頻度の場合は ErrInvalidEmailDigestFrequency を返す。
+	UpdateSettings(ctx context.Context, userID, frequency string) (*EmailDigestSettings, error)
+	// 配信停止リンクのトークンを検証する（配信設定は変更しない）。不正・期限切れのトークンの場合は ErrInvalidUnsubscribeToken を返す。
+	ValidateUnsubscribeToken(ctx context.Context, token string) error
+	// 配信停止リンクのトークンを検証し、配信を停止する。不正・期限切れのトークンの場合は ErrInvalidUnsubscribeToken を返す。
+	Unsubscribe(ctx context.Context, token string) error
+	// 配信時期を迎えたユーザーにダイジェストを最大 batchSize 件送信する。
+	SendDueDigests(ctx context.Context, now time.Time, batchSize int) (*EmailDigestRunResult, error)
+}
+
+type emailDigestService struct {
+	logger     *slog.Logger
+	digestRepo repository.EmailDigestRepository
+	notifRepo  repository.NotificationRepository
+	mailer     mailer.Mailer
+	cfg        EmailDigestConfig
+}
+
+// EmailDigestService を生成する。
+// mailer は送信ジョブでのみ使うため、設定の取得・更新のみを行う場合は nil でよい。
+func NewEmailDigestService(
+	logger *slog.Logger,
+	digestRepo repository.EmailDigestRepository,
+	notifRepo repository.NotificationRepository,
+	m mailer.Mailer,
+	cfg EmailDigestConfig,
+) EmailDigestService {
+	cfg.APIBaseURL = strings.TrimRight(cfg.APIBaseURL, "/")
+	cfg.AppBaseURL = strings.TrimRight(cfg.AppBaseURL, "/")
+	return &emailDigestService{
+		logger:     logger,
+		digestRepo: digestRepo,
+		notifRepo:  notifRepo,
+		mailer:     m,
+		cfg:        cfg,
+	}
+}
+
+// 配信設定を取得する。
+func (s *emailDigestService) GetSettings(ctx context.Context, userID string) (*EmailDigestSettings, error) {
+	setting, err := s.digestRepo.FindSetting(ctx, userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return &EmailDigestSettings{Frequency: model.EmailDigestFrequencyOff}, nil
+		}
+		return nil, err
+	}
+	return &EmailDigestSettings{Frequency: setting.Frequency, LastSentAt: setting.LastSentAt}, nil
+}
+
+// 配信頻度を更新する。
+func (s *emailDigestService) UpdateSettings(ctx context.Context, userID, frequency string) (*EmailDigestSettings, error) {
+	switch frequency {
+	case model.EmailDigestFrequencyOff, model.EmailDigestFrequencyDaily, model.EmailDigestFrequencyWeekly:
+	default:
+		return nil, ErrInvalidEmailDigestFrequency
+	}
+	if err := s.digestRepo.UpsertFrequency(ctx, userID, frequency); err != nil {
+		return nil, err
+	}
+	return s.GetSettings(ctx, userID)
+}
+
+// 配信停止リンクのトークンを検証する。
+func (s *emailDigestService) ValidateUnsubscribeToken(ctx context.Context, token string) error {
+	if _, ok := s.verifyUnsubscribeToken(token, time.Now()); !ok {
+		return ErrInvalidUnsubscribeToken
+	}
+	return nil
+}
+
+// 配信停止リンクのトークンを検証し、配信を停止する。
+// 配信設定がないユーザー（退会済みを含む）の場合は何もしない。
+func (s *emailDigestService) Unsubscribe(ctx context.Context, token string) error {
+	userID, ok := s.verifyUnsubscribeToken(token, time.Now())
+	if !ok {
+		return ErrInvalidUnsubscribeToken
+	}
+	if _, err := s.digestRepo.FindSetting(ctx, userID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		return err
+	}
+	return s.digestRepo.UpsertFrequency(ctx, userID, model.EmailDigestFrequencyOff)
+}
+
+// 配信時期を迎えたユーザーにダイジェストを送信する。
+// 送信に失敗したユーザーはログに記録し、配信済みにせず再送待ちにする（連続失敗の回数に応じて間隔を空けて再送する）。
+// 処理したユーザーは配信済み・再送待ちのどちらかになり、同じ now で再び ListDue の対象にならない。
+func (s *emailDigestService) SendDueDigests(ctx context.Context, now time.Time, batchSize int) (*EmailDigestRunResult, error) {
+	if s.cfg.UnsubscribeSecret == "" || s.mailer == nil {
+		return nil, ErrEmailDigestNotConfigured
+	}
+
+	recipients, err := s.digestRepo.ListDue(ctx,
+		now.Add(-emailDigestDailyInterval+emailDigestScheduleTolerance),
+		now.Add(-emailDigestWeeklyInterval+emailDigestScheduleTolerance),
+		now,
+		batchSize,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &EmailDigestRunResult{}
+	for _, r := range recipients {
+		sent, err := s.sendDigest(ctx, r, now)
+		if err != nil {
+			if ctx.Err() != nil {
+				return result, ctx.Err()
+			}
+			nextAttemptAt := now.Add(emailDigestRetryBackoff(r.FailedAttempts + 1))
+			s.logger.Warn("service.SendDueDigests failed to send digest",
+				slog.String("user_id", r.UserID),
+				slog.Int("failed_attempts", r.FailedAttempts+1),
+				slog.Time("next_attempt_at", nextAttemptAt),
+				slog.Any("error", err),
+			)
+			if err := s.digestRepo.MarkFailed(ctx, r.UserID, nextAttemptAt); err != nil {
+				return result, err
+			}
+			result.Failed++
+			result.Processed++
+			continue
+		}
+		if sent {
+			result.Sent++
+		}
+
+		if err := s.digestRepo.MarkSent(ctx, r.UserID, now); err != nil {
+			return result, err
+		}
+		result.Processed++
+	}
+	return result, nil
+}
+
+// attempts 回連続して送信に失敗した後、次に送信を試みるまでの待ち時間を返す。
+func emailDigestRetryBackoff(attempts int) time.Duration {
+	wait := emailDigestRetryBaseBackoff
+	for i := 1; i < attempts && wait < emailDigestRetryMaxBackoff; i++ {
+		wait *= 2
+	}
+	return min(wait, emailDigestRetryMaxBackoff)
+}
+
+// ダイジェストに載せる映画の1件。
+type emailDigestMovieItem struct {
+	Title    string
+	TagTitle string
+	TagURL   string
+}
+
+// ダイジェストのテンプレートに渡す内容。
+type emailDigestContent struct {
+	DisplayName       string
+	PeriodLabel       string
+	Notifications     []string
+	NotificationTotal int64
+	MoreNotifications int64
+	Movies            []emailDigestMovieItem
+	MovieTotal        int64
+	MoreMovies        int64
+	NotificationsURL  string
+	SettingsURL       string
+	UnsubscribeURL    string
+}
+
+// 1人分のダイジェストを組み立てて送信する。載せる内容がない場合は送信しない（false を返す）。
+func (s *emailDigestService) sendDigest(ctx context.Context, r repository.EmailDigestRecipientRow, now time.Time) (bool, error) {
+	interval, periodLabel := emailDigestWeeklyInterval, "今週"
+	if r.Frequency == model.EmailDigestFrequencyDaily {
+		interval, periodLabel = emailDigestDailyInterval, "今日"
+	}
+	since := now.Add(-interval)
+	if r.LastSentAt != nil && r.LastSentAt.After(since) {
+		since = *r.LastSentAt
+	}
+
+	notifications, notificationTotal, err := s.notifRepo.ListUnreadSince(ctx, r.UserID, since, emailDigestNotificationLimit)
+	if err != nil {
+		return false, err
+	}
+	movies, movieTotal, err := s.digestRepo.ListFollowedTagMovies(ctx, r.UserID, since, emailDigestMovieLimit)
+	if err != nil {
+		return false, err
+	}
+	if notificationTotal == 0 && movieTotal == 0 {
+		return false, nil
+	}
+
+	unsubscribeURL := s.unsubscribeURL(r.UserID, now.Add(emailDigestUnsubscribeTokenTTL))
+	content := emailDigestContent{
+		DisplayName:       r.DisplayName,
+		PeriodLabel:       periodLabel,
+		Notifications:     make([]string, 0, len(notifications)),
+		NotificationTotal: notificationTotal,
+		MoreNotifications: notificationTotal - int64(len(notifications)),
+		Movies:            make([]emailDigestMovieItem, 0, len(movies)),
+		MovieTotal:        movieTotal,
+		MoreMovies:        movieTotal - int64(len(movies)),
+		NotificationsURL:  s.cfg.AppBaseURL + "/notifications",
+		SettingsURL:       s.cfg.AppBaseURL + "/settings",
+		UnsubscribeURL:    unsubscribeURL,
+	}
+	for _, n := range notifications {
+		content.Notifications = append(content.Notifications, emailDigestNotificationText(n))
+	}
+	for _, m := range movies {
+		title := fmt.Sprintf("TMDB #%d", m.TmdbMovieID)
+		if m.MovieTitle != nil {
+			title = *m.MovieTitle
+		}
+		content.Movies = append(content.Movies, emailDigestMovieItem{
+			Title:    title,
+			TagTitle: m.TagTitle,
+			TagURL:   s.cfg.AppBaseURL + "/tags/" + m.TagID,
+		})
+	}
+
+	var text, html bytes.Buffer
+	if err := emailDigestTextTemplate.Execute(&text, content); err != nil {
+		return false, err
+	}
+	if err := emailDigestHTMLTemplate.Execute(&html, content); err != nil {
+		return false, err
+	}
+
+	err = s.mailer.Send(ctx, &mailer.Message{
+		To:       r.Email,
+		Subject:  fmt.Sprintf("cinetag %sのまとめ", periodLabel),
+		TextBody: text.String(),
+		HTMLBody: html.String(),
+		Headers: map[string]string{
+			// RFC 8058 のワンクリック配信停止
+			"List-Unsubscribe":      "<" + unsubscribeURL + ">",
+			"List-Unsubscribe-Post": "List-Unsubscribe=One-Click",
+		},
+	})
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// 通知1件をダイジェスト用の文に変換する。
+func emailDigestNotificationText(n *repository.NotificationRow) string {
+	actor := "誰か"
+	if n.ActorDisplayName != nil {
+		actor = *n.ActorDisplayName + " さん"
+	}
+	tag := ""
+	if n.TagTitle != nil {
+		tag = *n.TagTitle
+	}
+
+	var text string
+	switch n.NotificationType {
+	case model.NotificationTypeTagMovieAdded:
+		if n.MovieTitle != nil {
+			text = fmt.Sprintf("%sがタグ「%s」に「%s」を追加しました", actor, tag, *n.MovieTitle)
+		} else {
+			text = fmt.Sprintf("%sがタグ「%s」に映画を追加しました", actor, tag)
+		}
+	case model.NotificationTypeTagFollowed:
+		text = fmt.Sprintf("%sがタグ「%s」をフォローしました", actor, tag)
+	case model.NotificationTypeUserFollowed:
+		text = fmt.Sprintf("%sがあなたをフォローしました", actor)
+	case model.NotificationTypeFollowingUserCreatedTag:
+		text = fmt.Sprintf("%sが新しいタグ「%s」を作成しました", actor, tag)
+	case model.NotificationTypeFollowRequested:
+		text = fmt.Sprintf("%sからフォローリクエストが届きました", actor)
+	case model.NotificationTypeFollowRequestApproved:
+		text = fmt.Sprintf("%sがフォローリクエストを承認しました", actor)
+	case model.NotificationTypeFollowRequestDenied:
+		text = "フォローリクエストが承認されませんでした"
+	default:
+		text = "新しい通知があります"
+	}
+	if n.EventCount > 1 {
+		text += fmt.Sprintf("（ほか %d件）", n.EventCount-1)
+	}
+	return text
+}
+
+// 配信停止リンクの URL を返す。
+func (s *emailDigestService) unsubscribeURL(userID string, expiresAt time.Time) string {
+	return s.cfg.APIBaseURL + "/api/v1/email/unsubscribe?token=" + url.QueryEscape(s.unsubscribeToken(userID, expiresAt))
+}
+
+// 配信停止リンクのトークン（ユーザーID・有効期限の UNIX 秒・HMAC 署名）を生成する。
+func (s *emailDigestService) unsubscribeToken(userID string, expiresAt time.Time) string {
+	exp := strconv.FormatInt(expiresAt.Unix(), 10)
+	return userID + "." + exp + "." + base64.RawURLEncoding.EncodeToString(s.signUnsubscribe(userID, exp))
+}
+
+// 配信停止リンクのトークンを検証し、ユーザーID を返す。有効期限を過ぎたトークンは不正とする。
+func (s *emailDigestService) verifyUnsubscribeToken(token string, now time.Time) (string, bool) {
+	if s.cfg.UnsubscribeSecret == "" {
+		return "", false
+	}
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 || parts[0] == "" {
+		return "", false
+	}
+	userID, exp, sig := parts[0], parts[1], parts[2]
+	got, err := base64.RawURLEncoding.DecodeString(sig)
+	if err != nil {
+		return "", false
+	}
+	if !hmac.Equal(got, s.signUnsubscribe(userID, exp)) {
+		return "", false
+	}
+	expiresAt, err := strconv.ParseInt(exp, 10, 64)
+	if err != nil || !now.Before(time.Unix(expiresAt, 0)) {
+		return "", false
+	}
+	return userID, true
+}
+
+// ユーザーID・有効期限に対する配信停止用の署名を返す。
+func (s *emailDigestService) signUnsubscribe(userID, exp string) []byte {
+	mac := hmac.New(sha256.New, []byte(s.cfg.UnsubscribeSecret))
+	mac.Write([]byte(emailDigestUnsubscribeScope + userID + "." + exp))
+	return mac.Sum(nil)
+}
@@ -0,0 +1,284 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"cinetag-backend/src/internal/mailer"
+	"cinetag-backend/src/internal/model"
+	"cinetag-backend/src/internal/repository"
+	"cinetag-backend/src/internal/testutil"
+)
+
+var testDigestConfig = EmailDigestConfig{
+	UnsubscribeSecret: "secret",
+	APIBaseURL:        "https://api.example.com/",
+	AppBaseURL:        "https://app.example.com",
+}
+
+func TestEmailDigestService_UpdateSettings(t *testing.T) {
+	t.Parallel()
+
+	t.Run("不正な頻度はエラー", func(t *testing.T) {
+		t.Parallel()
+
+		svc := NewEmailDigestService(testutil.NewTestLogger(), &testutil.FakeEmailDigestRepository{}, nil, nil, testDigestConfig)
+		if _, err := svc.UpdateSettings(context.Background(), "u1", "monthly"); !errors.Is(err, ErrInvalidEmailDigestFrequency) {
+			t.Fatalf("expected ErrInvalidEmailDigestFrequency, got %v", err)
+		}
+	})
+
+	t.Run("頻度を保存して返す", func(t *testing.T) {
+		t.Parallel()
+
+		var saved string
+		repo := &testutil.FakeEmailDigestRepository{
+			UpsertFrequencyFn: func(ctx context.Context, userID, frequency string) error {
+				saved = frequency
+				return nil
+			},
+			FindSettingFn: func(ctx context.Context, userID string) (*model.EmailDigestSetting, error) {
+				return &model.EmailDigestSetting{UserID: userID, Frequency: saved}, nil
+			},
+		}
+		svc := NewEmailDigestService(testutil.NewTestLogger(), repo, nil, nil, testDigestConfig)
+		got, err := svc.UpdateSettings(context.Background(), "u1", model.EmailDigestFrequencyWeekly)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got.Frequency != model.EmailDigestFrequencyWeekly {
+			t.Fatalf("unexpected frequency: %s", got.Frequency)
+		}
+	})
+
+	t.Run("未設定は off として返す", func(t *testing.T) {
+		t.Parallel()
+
+		svc := NewEmailDigestService(testutil.NewTestLogger(), &testutil.FakeEmailDigestRepository{}, nil, nil, testDigestConfig)
+		got, err := svc.GetSettings(context.Background(), "u1")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got.Frequency != model.EmailDigestFrequencyOff || got.LastSentAt != nil {
+			t.Fatalf("unexpected settings: %+v", got)
+		}
+	})
+}
+
+func TestEmailDigestService_Unsubscribe(t *testing.T) {
+	t.Parallel()
+
+	newService := func(saved *string) *emailDigestService {
+		repo := &testutil.FakeEmailDigestRepository{
+			FindSettingFn: func(ctx context.Context, userID string) (*model.EmailDigestSetting, error) {
+				return &model.EmailDigestSetting{UserID: userID, Frequency: model.EmailDigestFrequencyDaily}, nil
+			},
+			UpsertFrequencyFn: func(ctx context.Context, userID, frequency string) error {
+				*saved = userID + ":" + frequency
+				return nil
+			},
+		}
+		return NewEmailDigestService(testutil.NewTestLogger(), repo, nil, nil, testDigestConfig).(*emailDigestService)
+	}
+
+	t.Run("署名が正しいトークンで配信を停止する", func(t *testing.T) {
+		t.Parallel()
+
+		var saved string
+		svc := newService(&saved)
+		if err := svc.ValidateUnsubscribeToken(context.Background(), svc.unsubscribeToken("u1", time.Now().Add(time.Hour))); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if saved != "" {
+			t.Fatalf("expected validation not to update settings, got %q", saved)
+		}
+		if err := svc.Unsubscribe(context.Background(), svc.unsubscribeToken("u1", time.Now().Add(time.Hour))); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if saved != "u1:off" {
+			t.Fatalf("unexpected update: %q", saved)
+		}
+	})
+
+	t.Run("改ざんされたトークンはエラー", func(t *testing.T) {
+		t.Parallel()
+
+		var saved string
+		svc := newService(&saved)
+		valid := svc.unsubscribeToken("u1", time.Now().Add(time.Hour))
+		parts := strings.Split(valid, ".")
+		exp, sig := parts[1], parts[2]
+		tokens := []string{
+			"", "u1", "u1." + sig, "u2." + exp + "." + sig, "u1." + exp + ".invalid!", valid + "x",
+			// 有効期限を書き換えたトークン
+			"u1.99999999999." + sig,
+			// 有効期限を過ぎたトークン
+			svc.unsubscribeToken("u1", time.Now().Add(-time.Minute)),
+		}
+		for _, token := range tokens {
+			if err := svc.Unsubscribe(context.Background(), token); !errors.Is(err, ErrInvalidUnsubscribeToken) {
+				t.Fatalf("token %q: expected ErrInvalidUnsubscribeToken, got %v", token, err)
+			}
+		}
+		if saved != "" {
+			t.Fatalf("unexpected update: %q", saved)
+		}
+	})
+}
+
+func TestEmailDigestService_SendDueDigests(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2026, 1, 8, 9, 0, 0, 0, time.UTC)
+	lastWeek := now.Add(-7 * 24 * time.Hour)
+
+	t.Run("内容があるユーザーにのみ送信し、全員を配信済みにする", func(t *testing.T) {
+		t.Parallel()
+
+		var marked []string
+		repo := &testutil.FakeEmailDigestRepository{
+			ListDueFn: func(ctx context.Context, dailyBefore, weeklyBefore, due time.Time, limit int) ([]repository.EmailDigestRecipientRow, error) {
+				if !dailyBefore.Equal(now.Add(-23*time.Hour)) || !weeklyBefore.Equal(now.Add(-7*24*time.Hour+time.Hour)) {
+					t.Fatalf("unexpected thresholds: %v %v", dailyBefore, weeklyBefore)
+				}
+				return []repository.EmailDigestRecipientRow{
+					{UserID: "u1", Email: "u1@example.com", DisplayName: "Alice", Frequency: model.EmailDigestFrequencyWeekly, LastSentAt: &lastWeek},
+					{UserID: "u2", Email: "u2@example.com", DisplayName: "Bob", Frequency: model.EmailDigestFrequencyDaily},
+				}, nil
+			},
+			MarkSentFn: func(ctx context.Context, userID string, at time.Time) error {
+				marked = append(marked, userID)
+				return nil
+			},
+			ListFollowedTagMoviesFn: func(ctx context.Context, userID string, since time.Time, limit int) ([]repository.EmailDigestMovieRow, int64, error) {
+				if userID != "u1" {
+					return []repository.EmailDigestMovieRow{}, 0, nil
+				}
+				return []repository.EmailDigestMovieRow{
+					{TagID: "tag1", TagTitle: "SF", TmdbMovieID: 1, MovieTitle: strPtr("ブレードランナー")},
+				}, 1, nil
+			},
+		}
+		notifRepo := &testutil.FakeNotificationRepository{
+			ListUnreadSinceFn: func(ctx context.Context, userID string, since time.Time, limit int) ([]*repository.NotificationRow, int64, error) {
+				if userID != "u1" {
+					return []*repository.NotificationRow{}, 0, nil
+				}
+				if !since.Equal(lastWeek) {
+					t.Fatalf("unexpected since: %v", since)
+				}
+				return []*repository.NotificationRow{
+					{NotificationType: model.NotificationTypeTagFollowed, ActorDisplayName: strPtr("Carol"), TagTitle: strPtr("SF"), EventCount: 3},
+				}, 12, nil
+			},
+		}
+		m := &testutil.FakeMailer{}
+		svc := NewEmailDigestService(testutil.NewTestLogger(), repo, notifRepo, m, testDigestConfig)
+
+		result, err := svc.SendDueDigests(context.Background(), now, 100)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if result.Processed != 2 || result.Sent != 1 || result.Failed != 0 {
+			t.Fatalf("unexpected result: %+v", result)
+		}
+		if len(marked) != 2 {
+			t.Fatalf("expected both users to be marked, got %v", marked)
+		}
+		if len(m.Sent) != 1 {
+			t.Fatalf("expected 1 mail, got %d", len(m.Sent))
+		}
+
+		msg := m.Sent[0]
+		if msg.To != "u1@example.com" || msg.Subject != "cinetag 今週のまとめ" {
+			t.Fatalf("unexpected message: to=%s subject=%s", msg.To, msg.Subject)
+		}
+		for _, want := range []string{
+			"Alice さん",
+			"Carol さんがタグ「SF」をフォローしました（ほか 2件）",
+			"ほか 11件",
+			"「ブレードランナー」（タグ: SF）",
+			"https://app.example.com/tags/tag1",
+		} {
+			if !strings.Contains(msg.TextBody, want) {
+				t.Fatalf("expected text body to contain %q, got:\n%s", want, msg.TextBody)
+			}
+		}
+		if !strings.Contains(msg.HTMLBody, "<li>Carol さんがタグ「SF」をフォローしました（ほか 2件）</li>") {
+			t.Fatalf("unexpected html body:\n%s", msg.HTMLBody)
+		}
+
+		unsubscribe := strings.Trim(msg.Headers["List-Unsubscribe"], "<>")
+		u, err := url.Parse(unsubscribe)
+		if err != nil || u.Host != "api.example.com" || u.Path != "/api/v1/email/unsubscribe" {
+			t.Fatalf("unexpected unsubscribe url: %s", unsubscribe)
+		}
+		if _, ok := svc.(*emailDigestService).verifyUnsubscribeToken(u.Query().Get("token"), now); !ok {
+			t.Fatalf("unsubscribe token should be valid: %s", unsubscribe)
+		}
+		if msg.Headers["List-Unsubscribe-Post"] != "List-Unsubscribe=One-Click" {
+			t.Fatalf("unexpected List-Unsubscribe-Post: %q", msg.Headers["List-Unsubscribe-Post"])
+		}
+	})
+
+	t.Run("送信に失敗したユーザーは配信済みにせず、失敗回数に応じて再送を遅らせる", func(t *testing.T) {
+		t.Parallel()
+
+		var marked []string
+		retries := map[string]time.Time{}
+		repo := &testutil.FakeEmailDigestRepository{
+			ListDueFn: func(ctx context.Context, dailyBefore, weeklyBefore, due time.Time, limit int) ([]repository.EmailDigestRecipientRow, error) {
+				if !due.Equal(now) {
+					t.Fatalf("unexpected due time: %v", due)
+				}
+				return []repository.EmailDigestRecipientRow{
+					{UserID: "u1", Email: "u1@example.com", Frequency: model.EmailDigestFrequencyDaily},
+					{UserID: "u2", Email: "u2@example.com", Frequency: model.EmailDigestFrequencyDaily, FailedAttempts: 2},
+				}, nil
+			},
+			MarkSentFn: func(ctx context.Context, userID string, at time.Time) error {
+				marked = append(marked, userID)
+				return nil
+			},
+			MarkFailedFn: func(ctx context.Context, userID string, nextAttemptAt time.Time) error {
+				retries[userID] = nextAttemptAt
+				return nil
+			},
+		}
+		notifRepo := &testutil.FakeNotificationRepository{
+			ListUnreadSinceFn: func(ctx context.Context, userID string, since time.Time, limit int) ([]*repository.NotificationRow, int64, error) {
+				return []*repository.NotificationRow{{NotificationType: model.NotificationTypeUserFollowed}}, 1, nil
+			},
+		}
+		m := &testutil.FakeMailer{
+			SendFn: func(ctx context.Context, msg *mailer.Message) error {
+				return errors.New("smtp unavailable")
+			},
+		}
+		svc := NewEmailDigestService(testutil.NewTestLogger(), repo, notifRepo, m, testDigestConfig)
+
+		result, err := svc.SendDueDigests(context.Background(), now, 100)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if result.Processed != 2 || result.Sent != 0 || result.Failed != 2 || len(marked) != 0 {
+			t.Fatalf("unexpected result: %+v marked=%v", result, marked)
+		}
+		if !retries["u1"].Equal(now.Add(15*time.Minute)) || !retries["u2"].Equal(now.Add(time.Hour)) {
+			t.Fatalf("unexpected next attempts: %v", retries)
+		}
+	})
+
+	t.Run("署名鍵が未設定の場合はエラー", func(t *testing.T) {
+		t.Parallel()
+
+		svc := NewEmailDigestService(testutil.NewTestLogger(), &testutil.FakeEmailDigestRepository{}, nil, &testutil.FakeMailer{}, EmailDigestConfig{})
+		if _, err := svc.SendDueDigests(context.Background(), now, 100); !errors.Is(err, ErrEmailDigestNotConfigured) {
+			t.Fatalf("expected ErrEmailDigestNotConfigured, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,314 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"cinetag-backend/src/internal/model"
+	"cinetag-backend/src/internal/repository"
+)
+
+// 同じタグへの同種のイベントを1件にまとめる時間幅。
+const feedGroupWindow = time.Hour
+
+// 1件のフィード項目に含めるアクター・映画のサンプル数。
+const feedGroupSampleSize = 3
+
+// 1回のフィード取得で読み込むイベントのバッチ数の上限。
+const feedMaxBatches = 5
+
+// カーソルの形式が不正な場合のエラー。
+var ErrInvalidFeedCursor = errors.New("invalid cursor")
+
+// フィード項目内のタグ情報。
+type FeedTagRef struct {
+	ID            string  `json:"id"`
+	Title         string  `json:"title"`
+	CoverImageURL *string `json:"cover_image_url,omitempty"`
+}
+
+// フィード項目内の映画情報。
+type FeedMovieRef struct {
+	TmdbMovieID int     `json:"tmdb_movie_id"`
+	Title       *string `json:"title,omitempty"`
+	PosterPath  *string `json:"poster_path,omitempty"`
+}
+
+// ホームフィードの1項目。
+// 同じタグへの同種のイベントが短時間に続いた場合は1件にまとめ、Count に件数を持つ。
+type FeedItem struct {
+	ID         string         `json:"id"` // まとめたイベントのうち最新のもののID
+	Type       string         `json:"type"`
+	CreatedAt  time.Time      `json:"created_at"` // まとめたイベントのうち最新の日時
+	Count      int            `json:"count"`
+	Actors     []ActorSummary `json:"actors"` // 新しい順に最大3人
+	ActorCount int            `json:"actor_count"`
+	Tag        *FeedTagRef    `json:"tag,omitempty"`
+	Movies     []FeedMovieRef `json:"movies,omitempty"` // 新しい順に最大3件
+	TargetUser *ActorSummary  `json:"target_user,omitempty"`
+}
+
+// ユーザーのアクティビティ一覧の1件。
+type UserActivityItem struct {
+	ID         string        `json:"id"`
+	Type       string        `json:"type"`
+	CreatedAt  time.Time     `json:"created_at"`
+	Tag        *FeedTagRef   `json:"tag,omitempty"`
+	Movie      *FeedMovieRef `json:"movie,omitempty"`
+	TargetUser *ActorSummary `json:"target_user,omitempty"`
+}
+
+// ユーザーのアクティビティ一覧に表示するイベントの種類。
+var userActivityEventTypes = []string{
+	model.FeedEventTypeTagCreated,
+	model.FeedEventTypeTagMovieAdded,
+	model.FeedEventTypeTagLiked,
+	model.FeedEventTypeUserFollowed,
+}
+
+// ホームフィード・ユーザーのアクティビティに関するユースケースを表すインターフェース。
+type FeedService interface {
+	// ホームフィードを新しい順に返す。
+	// - cursor が空文字の場合は先頭から返す。
+	// - 続きがある場合は次ページ取得用のカーソルを返す（ない場合は nil）。
+	ListFeed(ctx context.Context, userID, cursor string, limit int) ([]FeedItem, *string, error)
+
+	// 指定ユーザーのアクティビティ（タグ作成・映画追加・いいね・ユーザーフォロー）を新しい順に返す。
+	// - 公開タグに関するもののみを返す。
+	// - viewerUserID は非公開アカウントへのフォローの表示判定に使う（未ログインの場合は空文字）。
+	ListUserActivity(ctx context.Context, userID, viewerUserID string, page, pageSize int) ([]UserActivityItem, int64, error)
+}
+
+type feedService struct {
+	logger        *slog.Logger
+	feedEventRepo repository.FeedEventRepository
+}
+
+// FeedService を生成する。
+func NewFeedService(logger *slog.Logger, feedEventRepo repository.FeedEventRepository) FeedService {
+	return &feedService{
+		logger:        logger,
+		feedEventRepo: feedEventRepo,
+	}
+}
+
+// フィード項目の集約中の状態。
+type feedGroup struct {
+	item     FeedItem
+	actorIDs map[string]struct{}
+	movieIDs map[int]struct{}
+}
+
+// ホームフィードを新しい順に返す。
+// イベントを順に読み込み、limit 件の項目が埋まった後に新しい項目が始まる直前までを1ページとする。
+func (s *feedService) ListFeed(ctx context.Context, userID, cursor string, limit int) ([]FeedItem, *string, error) {
+	if strings.TrimSpace(userID) == "" {
+		return nil, nil, fmt.Errorf("user_id is required")
+	}
+	if limit < 1 || limit > 50 {
+		limit = 20
+	}
+
+	filter := repository.FeedListFilter{
+		ViewerID: userID,
+		Limit:    limit * 5,
+	}
+	if cursor != "" {
+		createdAt, id, err := decodeCursor(cursor)
+		if err != nil {
+			return nil, nil, ErrInvalidFeedCursor
+		}
+		filter.BeforeCreatedAt = &createdAt
+		filter.BeforeID = id
+	}
+
+	groups := make([]*feedGroup, 0, limit)
+	openGroups := make(map[string]*feedGroup)
+	var last *repository.FeedEventRow
+	hasMore := false
+
+	for batch := 0; batch < feedMaxBatches && !hasMore; batch++ {
+		rows, err := s.feedEventRepo.ListForViewer(ctx, filter)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		for i := range rows {
+			row := &rows[i]
+			key := feedGroupKey(row)
+			if g, ok := openGroups[key]; ok && key != "" && !row.CreatedAt.Before(g.item.CreatedAt.Add(-feedGroupWindow)) {
+				g.add(row)
+			} else {
+				if len(groups) == limit {
+					hasMore = true
+					break
+				}
+				g := newFeedGroup(row)
+				groups = append(groups, g)
+				if key != "" {
+					openGroups[key] = g
+				}
+			}
+			last = row
+		}
+
+		if len(rows) < filter.Limit {
+			break
+		}
+		if last != nil {
+			createdAt := last.CreatedAt
+			filter.BeforeCreatedAt = &createdAt
+			filter.BeforeID = last.ID
+		}
+		// 読み込み上限に達した場合は、読み込んだ位置までで打ち切る
+		if batch == feedMaxBatches-1 {
+			hasMore = true
+		}
+	}
+
+	items := make([]FeedItem, 0, len(groups))
+	for _, g := range groups {
+		items = append(items, g.item)
+	}
+
+	var next *string
+	if hasMore && last != nil {
+		c := encodeCursor(last.CreatedAt, last.ID)
+		next = &c
+	}
+	return items, next, nil
+}
+
+// 指定ユーザーのアクティビティを新しい順に返す。
+func (s *feedService) ListUserActivity(ctx context.Context, userID, viewerUserID string, page, pageSize int) ([]UserActivityItem, int64, error) {
+	if strings.TrimSpace(userID) == "" {
+		return nil, 0, fmt.Errorf("user_id is required")
+	}
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 || pageSize > 100 {
+		pageSize = 20
+	}
+
+	rows, total, err := s.feedEventRepo.ListByActor(ctx, repository.FeedActivityFilter{
+		ActorUserID: userID,
+		ViewerID:    strings.TrimSpace(viewerUserID),
+		EventTypes:  userActivityEventTypes,
+		Offset:      (page - 1) * pageSize,
+		Limit:       pageSize,
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+
+	items := make([]UserActivityItem, 0, len(rows))
+	for i := range rows {
+		items = append(items, UserActivityItem{
+			ID:         rows[i].ID,
+			Type:       rows[i].EventType,
+			CreatedAt:  rows[i].CreatedAt,
+			Tag:        toFeedTagRef(&rows[i]),
+			Movie:      toFeedMovieRef(&rows[i]),
+			TargetUser: toFeedTargetUser(&rows[i]),
+		})
+	}
+	return items, total, nil
+}
+
+// イベントをまとめる単位のキーを返す（まとめないイベントは空文字）。
+// タグへの映画追加・いいね・フォローは、同じタグ・同じ種類のイベントをまとめる。
+func feedGroupKey(row *repository.FeedEventRow) string {
+	if row.TagID == nil {
+		return ""
+	}
+	switch row.EventType {
+	case model.FeedEventTypeTagMovieAdded, model.FeedEventTypeTagLiked, model.FeedEventTypeTagFollowed:
+		return row.EventType + ":" + *row.TagID
+	}
+	return ""
+}
+
+// イベント1件から新しいフィード項目を作る。
+func newFeedGroup(row *repository.FeedEventRow) *feedGroup {
+	g := &feedGroup{
+		item: FeedItem{
+			ID:         row.ID,
+			Type:       row.EventType,
+			CreatedAt:  row.CreatedAt,
+			Actors:     []ActorSummary{},
+			Tag:        toFeedTagRef(row),
+			TargetUser: toFeedTargetUser(row),
+		},
+		actorIDs: make(map[string]struct{}),
+		movieIDs: make(map[int]struct{}),
+	}
+	g.add(row)
+	return g
+}
+
+// フィード項目にイベントを1件追加する。
+func (g *feedGroup) add(row *repository.FeedEventRow) {
+	g.item.Count++
+
+	if _, ok := g.actorIDs[row.ActorUserID]; !ok {
+		g.actorIDs[row.ActorUserID] = struct{}{}
+		g.item.ActorCount++
+		if len(g.item.Actors) < feedGroupSampleSize {
+			g.item.Actors = append(g.item.Actors, ActorSummary{
+				ID:          row.ActorUserID,
+				DisplayID:   row.ActorDisplayID,
+				DisplayName: row.ActorDisplayName,
+				AvatarURL:   row.ActorAvatarURL,
+			})
+		}
+	}
+
+	if movie := toFeedMovieRef(row); movie != nil {
+		if _, ok := g.movieIDs[movie.TmdbMovieID]; !ok && len(g.item.Movies) < feedGroupSampleSize {
+			g.movieIDs[movie.TmdbMovieID] = struct{}{}
+			g.item.Movies = append(g.item.Movies, *movie)
+		}
+	}
+}
+
+// イベントのタグ情報を返す（タグに紐づかない場合は nil）。
+func toFeedTagRef(row *repository.FeedEventRow) *FeedTagRef {
+	if row.TagID == nil || row.TagTitle == nil {
+		return nil
+	}
+	return &FeedTagRef{
+		ID:            *row.TagID,
+		Title:         *row.TagTitle,
+		CoverImageURL: row.TagCoverImageURL,
+	}
+}
+
+// イベントの映画情報を返す（映画に紐づかない場合は nil）。
+func toFeedMovieRef(row *repository.FeedEventRow) *FeedMovieRef {
+	if row.TmdbMovieID == nil {
+		return nil
+	}
+	return &FeedMovieRef{
+		TmdbMovieID: *row.TmdbMovieID,
+		Title:       row.MovieTitle,
+		PosterPath:  row.MoviePosterPath,
+	}
+}
+
+// イベントの対象ユーザーを返す（ユーザーフォロー以外は nil）。
+func toFeedTargetUser(row *repository.FeedEventRow) *ActorSummary {
+	if row.TargetUserID == nil || row.TargetDisplayID == nil || row.TargetDisplayName == nil {
+		return nil
+	}
+	return &ActorSummary{
+		ID:          *row.TargetUserID,
+		DisplayID:   *row.TargetDisplayID,
+		DisplayName: *row.TargetDisplayName,
+		AvatarURL:   row.TargetAvatarURL,
+	}
+}
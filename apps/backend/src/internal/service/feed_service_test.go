@@ -0,0 +1,256 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"cinetag-backend/src/internal/model"
+	"cinetag-backend/src/internal/repository"
+	"cinetag-backend/src/internal/testutil"
+)
+
+// テスト用のフィードイベント行を生成する。
+func feedRow(id, eventType, actorID string, tagID *string, createdAt time.Time) repository.FeedEventRow {
+	row := repository.FeedEventRow{
+		ID:               id,
+		EventType:        eventType,
+		CreatedAt:        createdAt,
+		ActorUserID:      actorID,
+		ActorDisplayID:   actorID,
+		ActorDisplayName: actorID,
+		TagID:            tagID,
+	}
+	if tagID != nil {
+		title := "tag-" + *tagID
+		row.TagTitle = &title
+	}
+	return row
+}
+
+// 指定したイベント列を、フィルタ（カーソル・件数）に従って返す fake を生成する。
+func newFeedRepoWithRows(rows []repository.FeedEventRow, calls *[]repository.FeedListFilter) *testutil.FakeFeedEventRepository {
+	return &testutil.FakeFeedEventRepository{
+		ListForViewerFn: func(ctx context.Context, filter repository.FeedListFilter) ([]repository.FeedEventRow, error) {
+			if calls != nil {
+				*calls = append(*calls, filter)
+			}
+			start := 0
+			if filter.BeforeCreatedAt != nil {
+				for i, r := range rows {
+					if r.ID == filter.BeforeID {
+						start = i + 1
+						break
+					}
+				}
+			}
+			end := start + filter.Limit
+			if end > len(rows) {
+				end = len(rows)
+			}
+			return rows[start:end], nil
+		},
+	}
+}
+
+func TestFeedService_ListFeed(t *testing.T) {
+	t.Parallel()
+
+	base := time.Date(2025, 1, 10, 12, 0, 0, 0, time.UTC)
+	tagA := "a"
+	tagB := "b"
+
+	t.Run("同じタグへの映画追加は時間幅内で1件にまとめる", func(t *testing.T) {
+		t.Parallel()
+
+		m1, m2, m3 := 1, 2, 3
+		r1 := feedRow("e1", model.FeedEventTypeTagMovieAdded, "alice", &tagA, base)
+		r1.TmdbMovieID = &m1
+		r2 := feedRow("e2", model.FeedEventTypeTagMovieAdded, "bob", &tagA, base.Add(-10*time.Minute))
+		r2.TmdbMovieID = &m2
+		r3 := feedRow("e3", model.FeedEventTypeTagCreated, "bob", &tagB, base.Add(-20*time.Minute))
+		r4 := feedRow("e4", model.FeedEventTypeTagMovieAdded, "alice", &tagA, base.Add(-30*time.Minute))
+		r4.TmdbMovieID = &m3
+		// 時間幅の外は別項目にする
+		r5 := feedRow("e5", model.FeedEventTypeTagMovieAdded, "alice", &tagA, base.Add(-3*time.Hour))
+
+		svc := NewFeedService(testutil.NewTestLogger(), newFeedRepoWithRows([]repository.FeedEventRow{r1, r2, r3, r4, r5}, nil))
+		items, next, err := svc.ListFeed(context.Background(), "viewer", "", 20)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if next != nil {
+			t.Fatalf("expected no next cursor, got %q", *next)
+		}
+		if len(items) != 3 {
+			t.Fatalf("expected 3 items, got %d", len(items))
+		}
+
+		grouped := items[0]
+		if grouped.ID != "e1" || grouped.Count != 3 || grouped.ActorCount != 2 {
+			t.Fatalf("unexpected grouped item: %+v", grouped)
+		}
+		if len(grouped.Movies) != 3 || grouped.Movies[0].TmdbMovieID != 1 {
+			t.Fatalf("unexpected movies: %+v", grouped.Movies)
+		}
+		if grouped.Tag == nil || grouped.Tag.ID != "a" {
+			t.Fatalf("unexpected tag: %+v", grouped.Tag)
+		}
+		if items[1].Type != model.FeedEventTypeTagCreated {
+			t.Fatalf("expected tag_created at index 1, got %s", items[1].Type)
+		}
+		if items[2].ID != "e5" || items[2].Count != 1 {
+			t.Fatalf("expected separate item for e5, got %+v", items[2])
+		}
+	})
+
+	t.Run("ページ末尾の項目は次のイベントまで読み込んでから区切る", func(t *testing.T) {
+		t.Parallel()
+
+		// カーソルにはイベントIDを含めるため UUID 形式にする
+		id := func(n int) string { return fmt.Sprintf("00000000-0000-0000-0000-%012d", n) }
+		rows := []repository.FeedEventRow{
+			feedRow(id(1), model.FeedEventTypeUserFollowed, "alice", nil, base),
+			feedRow(id(2), model.FeedEventTypeTagLiked, "alice", &tagA, base.Add(-time.Minute)),
+			feedRow(id(3), model.FeedEventTypeTagLiked, "bob", &tagA, base.Add(-2*time.Minute)),
+			feedRow(id(4), model.FeedEventTypeTagCreated, "bob", &tagB, base.Add(-3*time.Minute)),
+		}
+		svc := NewFeedService(testutil.NewTestLogger(), newFeedRepoWithRows(rows, nil))
+
+		items, next, err := svc.ListFeed(context.Background(), "viewer", "", 2)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(items) != 2 || items[1].Count != 2 {
+			t.Fatalf("unexpected items: %+v", items)
+		}
+		if next == nil {
+			t.Fatalf("expected next cursor")
+		}
+
+		items, next, err = svc.ListFeed(context.Background(), "viewer", *next, 2)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(items) != 1 || items[0].ID != id(4) {
+			t.Fatalf("unexpected second page: %+v", items)
+		}
+		if next != nil {
+			t.Fatalf("expected no next cursor, got %q", *next)
+		}
+	})
+
+	t.Run("カーソルの位置をリポジトリに渡す", func(t *testing.T) {
+		t.Parallel()
+
+		var calls []repository.FeedListFilter
+		svc := NewFeedService(testutil.NewTestLogger(), newFeedRepoWithRows(nil, &calls))
+		id := "8d3c7a8e-0f4f-4d55-9a43-3f1e8f3b2c11"
+		cursor := encodeCursor(base, id)
+
+		if _, _, err := svc.ListFeed(context.Background(), "viewer", cursor, 10); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(calls) != 1 {
+			t.Fatalf("expected 1 call, got %d", len(calls))
+		}
+		got := calls[0]
+		if got.ViewerID != "viewer" || got.BeforeID != id || got.BeforeCreatedAt == nil || !got.BeforeCreatedAt.Equal(base) {
+			t.Fatalf("unexpected filter: %+v", got)
+		}
+	})
+
+	t.Run("不正なカーソル: ErrInvalidFeedCursor", func(t *testing.T) {
+		t.Parallel()
+
+		svc := NewFeedService(testutil.NewTestLogger(), &testutil.FakeFeedEventRepository{})
+		for _, cursor := range []string{"!!!", encodeCursor(base, "not-a-uuid")} {
+			_, _, err := svc.ListFeed(context.Background(), "viewer", cursor, 10)
+			if !errors.Is(err, ErrInvalidFeedCursor) {
+				t.Fatalf("cursor %q: expected ErrInvalidFeedCursor, got: %v", cursor, err)
+			}
+		}
+	})
+
+	t.Run("リポジトリエラーを返す", func(t *testing.T) {
+		t.Parallel()
+
+		repo := &testutil.FakeFeedEventRepository{
+			ListForViewerFn: func(ctx context.Context, filter repository.FeedListFilter) ([]repository.FeedEventRow, error) {
+				return nil, fmt.Errorf("db error")
+			},
+		}
+		svc := NewFeedService(testutil.NewTestLogger(), repo)
+		if _, _, err := svc.ListFeed(context.Background(), "viewer", "", 10); err == nil {
+			t.Fatalf("expected error")
+		}
+	})
+}
+
+func TestFeedService_ListUserActivity(t *testing.T) {
+	t.Parallel()
+
+	t.Run("対象のイベント種別とページングをリポジトリに渡す", func(t *testing.T) {
+		t.Parallel()
+
+		var got repository.FeedActivityFilter
+		repo := &testutil.FakeFeedEventRepository{
+			ListByActorFn: func(ctx context.Context, filter repository.FeedActivityFilter) ([]repository.FeedEventRow, int64, error) {
+				got = filter
+				return []repository.FeedEventRow{}, 0, nil
+			},
+		}
+		svc := NewFeedService(testutil.NewTestLogger(), repo)
+		if _, _, err := svc.ListUserActivity(context.Background(), "u1", "viewer", 3, 10); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got.ActorUserID != "u1" || got.ViewerID != "viewer" || got.Offset != 20 || got.Limit != 10 {
+			t.Fatalf("unexpected filter: %+v", got)
+		}
+		// タグのフォローはアクティビティに含めない
+		for _, typ := range got.EventTypes {
+			if typ == model.FeedEventTypeTagFollowed {
+				t.Fatalf("tag_followed should not be included: %v", got.EventTypes)
+			}
+		}
+		if len(got.EventTypes) != 4 {
+			t.Fatalf("unexpected event types: %v", got.EventTypes)
+		}
+	})
+
+	t.Run("映画追加は映画とタグを埋める", func(t *testing.T) {
+		t.Parallel()
+
+		tagID := "t1"
+		movieID := 603
+		title := "The Matrix"
+		row := feedRow("e1", model.FeedEventTypeTagMovieAdded, "u1", &tagID, time.Now())
+		row.TmdbMovieID = &movieID
+		row.MovieTitle = &title
+		repo := &testutil.FakeFeedEventRepository{
+			ListByActorFn: func(ctx context.Context, filter repository.FeedActivityFilter) ([]repository.FeedEventRow, int64, error) {
+				return []repository.FeedEventRow{row}, 1, nil
+			},
+		}
+		svc := NewFeedService(testutil.NewTestLogger(), repo)
+		items, total, err := svc.ListUserActivity(context.Background(), "u1", "", 1, 20)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if total != 1 || len(items) != 1 {
+			t.Fatalf("unexpected result: total=%d items=%+v", total, items)
+		}
+		item := items[0]
+		if item.Tag == nil || item.Tag.ID != "t1" {
+			t.Fatalf("unexpected tag: %+v", item.Tag)
+		}
+		if item.Movie == nil || item.Movie.TmdbMovieID != 603 || item.Movie.Title == nil || *item.Movie.Title != title {
+			t.Fatalf("unexpected movie: %+v", item.Movie)
+		}
+		if item.TargetUser != nil {
+			t.Fatalf("expected no target user, got %+v", item.TargetUser)
+		}
+	})
+}
@@ -0,0 +1,218 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"cinetag-backend/src/internal/repository"
+)
+
+// メンテナンスジョブの名前。定期ジョブ（src/cmd/jobs）と管理者用 API で共通です。
+const (
+	MaintenanceJobPurgeDeactivatedUsers   = "purge-deactivated-users"
+	MaintenanceJobPurgeOutboxEvents       = "purge-outbox-events"
+	MaintenanceJobPurgeNotifications      = "purge-notifications"
+	MaintenanceJobPurgeWebhookDeliveries  = "purge-webhook-deliveries"
+	MaintenanceJobPurgeClerkWebhookEvents = "purge-clerk-webhook-events"
+	MaintenanceJobRefreshMovieCache       = "refresh-movie-cache"
+	MaintenanceJobPurgeUserDataExports    = "purge-user-data-exports"
+)
+
+// MaintenanceJobs は MaintenanceService で実行できるジョブの一覧です。
+var MaintenanceJobs = []string{
+	MaintenanceJobPurgeDeactivatedUsers,
+	MaintenanceJobPurgeOutboxEvents,
+	MaintenanceJobPurgeNotifications,
+	MaintenanceJobPurgeWebhookDeliveries,
+	MaintenanceJobPurgeClerkWebhookEvents,
+	MaintenanceJobRefreshMovieCache,
+	MaintenanceJobPurgeUserDataExports,
+}
+
+const (
+	// 配信済みのアウトボックスイベントを保持する期間。
+	outboxEventRetention = 7 * 24 * time.Hour
+	// Webhook の配信ログを保持する期間。
+	webhookDeliveryRetention = 30 * 24 * time.Hour
+	// 受信した Clerk Webhook の svix-id を保持する期間（Clerk の再送はこれより十分短い期間で終わる）。
+	clerkWebhookEventRetention = 30 * 24 * time.Hour
+
+	// 退会済みユーザーの完全削除で1回に処理する件数。
+	purgeUsersBatchSize = 100
+	// 削除ジョブで1回に削除する件数。
+	purgeRowsBatchSize = 1000
+	// 映画キャッシュの更新で1回の実行で TMDB から取得し直す件数（TMDB のレート制限に配慮して1バッチのみ）。
+	refreshMovieCacheBatchSize = 100
+)
+
+// 存在しないジョブを指定した場合のエラー。
+var ErrUnknownMaintenanceJob = errors.New("unknown maintenance job")
+
+// メンテナンスジョブの実行結果。
+type MaintenanceJobResult struct {
+	Job      string           `json:"job"`
+	Affected int64            `json:"affected"`          // 削除・更新した件数
+	Details  map[string]int64 `json:"details,omitempty"` // 件数の内訳（ジョブによって異なる）
+}
+
+// 保持期間を過ぎたデータの削除やキャッシュの更新などのメンテナンスジョブを実行するサービス。
+type MaintenanceService interface {
+	// RunJob はジョブを実行する。失敗した場合も、それまでに処理した件数を結果として返す。
+	RunJob(ctx context.Context, job string, now time.Time) (*MaintenanceJobResult, error)
+}
+
+type maintenanceService struct {
+	logger                *slog.Logger
+	userService           UserService
+	notificationService   NotificationService
+	movieService          MovieService
+	outboxRepo            repository.OutboxRepository
+	webhookRepo           repository.WebhookRepository
+	clerkWebhookEventRepo repository.ClerkWebhookEventRepository
+	exportRepo            repository.UserDataExportRepository
+	notificationRetention NotificationRetentionPolicy
+}
+
+// MaintenanceService の実装を生成する。
+func NewMaintenanceService(
+	logger *slog.Logger,
+	userService UserService,
+	notificationService NotificationService,
+	movieService MovieService,
+	outboxRepo repository.OutboxRepository,
+	webhookRepo repository.WebhookRepository,
+	clerkWebhookEventRepo repository.ClerkWebhookEventRepository,
+	exportRepo repository.UserDataExportRepository,
+	notificationRetention NotificationRetentionPolicy,
+) MaintenanceService {
+	return &maintenanceService{
+		logger:                logger,
+		userService:           userService,
+		notificationService:   notificationService,
+		movieService:          movieService,
+		outboxRepo:            outboxRepo,
+		webhookRepo:           webhookRepo,
+		clerkWebhookEventRepo: clerkWebhookEventRepo,
+		exportRepo:            exportRepo,
+		notificationRetention: notificationRetention,
+	}
+}
+
+// ジョブを実行する。
+func (s *maintenanceService) RunJob(ctx context.Context, job string, now time.Time) (*MaintenanceJobResult, error) {
+	result := &MaintenanceJobResult{Job: job}
+
+	var err error
+	switch job {
+	case MaintenanceJobPurgeDeactivatedUsers:
+		err = s.purgeDeactivatedUsers(ctx, now, result)
+	case MaintenanceJobPurgeOutboxEvents:
+		err = s.purgeInBatches(ctx, result, func(ctx context.Context) (int64, error) {
+			return s.outboxRepo.DeleteProcessedBefore(ctx, now.Add(-outboxEventRetention), purgeRowsBatchSize)
+		})
+	case MaintenanceJobPurgeNotifications:
+		err = s.purgeNotifications(ctx, now, result)
+	case MaintenanceJobPurgeWebhookDeliveries:
+		err = s.purgeInBatches(ctx, result, func(ctx context.Context) (int64, error) {
+			return s.webhookRepo.DeleteDeliveriesBefore(ctx, now.Add(-webhookDeliveryRetention), purgeRowsBatchSize)
+		})
+	case MaintenanceJobPurgeClerkWebhookEvents:
+		err = s.purgeInBatches(ctx, result, func(ctx context.Context) (int64, error) {
+			return s.clerkWebhookEventRepo.DeleteBefore(ctx, now.Add(-clerkWebhookEventRetention), purgeRowsBatchSize)
+		})
+	case MaintenanceJobPurgeUserDataExports:
+		err = s.purgeUserDataExports(ctx, now, result)
+	case MaintenanceJobRefreshMovieCache:
+		var refreshed int
+		refreshed, err = s.movieService.RefreshExpiredMovieCaches(ctx, now, refreshMovieCacheBatchSize)
+		result.Affected = int64(refreshed)
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnknownMaintenanceJob, job)
+	}
+
+	if err != nil {
+		s.logger.Error("service.RunJob failed",
+			slog.String("job", job),
+			slog.Int64("affected", result.Affected),
+			slog.Any("error", err),
+		)
+		return result, err
+	}
+	s.logger.Info("service.RunJob completed",
+		slog.String("job", job),
+		slog.Int64("affected", result.Affected),
+	)
+	return result, nil
+}
+
+// 猶予期間を過ぎた退会済みユーザーを、対象がなくなるまでバッチを繰り返して完全削除する。
+func (s *maintenanceService) purgeDeactivatedUsers(ctx context.Context, now time.Time, result *MaintenanceJobResult) error {
+	for {
+		purged, err := s.userService.PurgeDeactivatedUsers(ctx, now, purgeUsersBatchSize)
+		result.Affected += int64(purged)
+		if err != nil {
+			return err
+		}
+		if purged < purgeUsersBatchSize {
+			return nil
+		}
+	}
+}
+
+// 保持ポリシーに従い、古い既読通知とユーザーごとの上限を超えた未読通知を削除する。
+func (s *maintenanceService) purgeNotifications(ctx context.Context, now time.Time, result *MaintenanceJobResult) error {
+	var deletedRead, deletedUnread int64
+	defer func() {
+		result.Affected = deletedRead + deletedUnread
+		result.Details = map[string]int64{"read": deletedRead, "unread_over_limit": deletedUnread}
+	}()
+
+	for {
+		purged, err := s.notificationService.PurgeNotifications(ctx, s.notificationRetention, now, purgeRowsBatchSize)
+		if purged != nil {
+			deletedRead += purged.DeletedRead
+			deletedUnread += purged.DeletedUnread
+		}
+		if err != nil {
+			return err
+		}
+		if purged.DeletedRead < purgeRowsBatchSize && purged.DeletedUnread < purgeRowsBatchSize {
+			return nil
+		}
+	}
+}
+
+// 生成処理が中断したまま残ったエクスポートを失敗にし、保存期限を過ぎたエクスポート（生成済みファイルを含む）を削除する。
+func (s *maintenanceService) purgeUserDataExports(ctx context.Context, now time.Time, result *MaintenanceJobResult) error {
+	failed, err := s.exportRepo.FailStale(ctx, now.Add(-userDataExportStaleAfter), userDataExportTimedOutMessage)
+	result.Affected = failed
+	result.Details = map[string]int64{"failed_stale": failed}
+	if err != nil {
+		return err
+	}
+
+	expired := &MaintenanceJobResult{}
+	err = s.purgeInBatches(ctx, expired, func(ctx context.Context) (int64, error) {
+		return s.exportRepo.DeleteExpiredBefore(ctx, now, purgeRowsBatchSize)
+	})
+	result.Affected += expired.Affected
+	result.Details["deleted_expired"] = expired.Affected
+	return err
+}
+
+// 1回の削除件数が purgeRowsBatchSize 未満になるまで削除を繰り返す。
+func (s *maintenanceService) purgeInBatches(ctx context.Context, result *MaintenanceJobResult, deleteBatch func(ctx context.Context) (int64, error)) error {
+	for {
+		deleted, err := deleteBatch(ctx)
+		result.Affected += deleted
+		if err != nil {
+			return err
+		}
+		if deleted < purgeRowsBatchSize {
+			return nil
+		}
+	}
+}
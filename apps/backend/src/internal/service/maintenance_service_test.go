@@ -0,0 +1,101 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"cinetag-backend/src/internal/testutil"
+)
+
+func TestMaintenanceService_RunJob(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+
+	t.Run("存在しないジョブは ErrUnknownMaintenanceJob", func(t *testing.T) {
+		t.Parallel()
+
+		svc := NewMaintenanceService(testutil.NewTestLogger(), nil, nil, nil, nil, nil, nil, nil, NotificationRetentionPolicy{})
+		if _, err := svc.RunJob(context.Background(), "send-email-digests", now); !errors.Is(err, ErrUnknownMaintenanceJob) {
+			t.Fatalf("expected ErrUnknownMaintenanceJob, got %v", err)
+		}
+	})
+
+	t.Run("削除件数がバッチサイズ未満になるまで繰り返す", func(t *testing.T) {
+		t.Parallel()
+
+		batches := []int64{purgeRowsBatchSize, purgeRowsBatchSize, 5}
+		var calls int
+		outboxRepo := &testutil.FakeOutboxRepository{
+			DeleteProcessedBeforeFn: func(ctx context.Context, before time.Time, limit int) (int64, error) {
+				if !before.Equal(now.Add(-outboxEventRetention)) {
+					t.Fatalf("unexpected before: %v", before)
+				}
+				deleted := batches[calls]
+				calls++
+				return deleted, nil
+			},
+		}
+		svc := NewMaintenanceService(testutil.NewTestLogger(), nil, nil, nil, outboxRepo, nil, nil, nil, NotificationRetentionPolicy{})
+		result, err := svc.RunJob(context.Background(), MaintenanceJobPurgeOutboxEvents, now)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if calls != 3 || result.Affected != 2*purgeRowsBatchSize+5 {
+			t.Fatalf("unexpected result: calls=%d affected=%d", calls, result.Affected)
+		}
+	})
+
+	t.Run("失敗した場合もそれまでの削除件数を返す", func(t *testing.T) {
+		t.Parallel()
+
+		errDB := errors.New("db error")
+		var calls int
+		eventRepo := &testutil.FakeClerkWebhookEventRepository{
+			DeleteBeforeFn: func(ctx context.Context, before time.Time, limit int) (int64, error) {
+				calls++
+				if calls == 1 {
+					return purgeRowsBatchSize, nil
+				}
+				return 0, errDB
+			},
+		}
+		svc := NewMaintenanceService(testutil.NewTestLogger(), nil, nil, nil, nil, nil, eventRepo, nil, NotificationRetentionPolicy{})
+		result, err := svc.RunJob(context.Background(), MaintenanceJobPurgeClerkWebhookEvents, now)
+		if !errors.Is(err, errDB) {
+			t.Fatalf("expected db error, got %v", err)
+		}
+		if result == nil || result.Affected != purgeRowsBatchSize {
+			t.Fatalf("expected partial result, got %+v", result)
+		}
+	})
+
+	t.Run("エクスポート: 中断したエクスポートを失敗にし、期限切れのエクスポートを削除する", func(t *testing.T) {
+		t.Parallel()
+
+		exportRepo := &fakeUserDataExportRepo{
+			FailStaleFn: func(ctx context.Context, createdBefore time.Time, message string) (int64, error) {
+				if !createdBefore.Equal(now.Add(-userDataExportStaleAfter)) {
+					t.Fatalf("unexpected createdBefore: %v", createdBefore)
+				}
+				return 2, nil
+			},
+			DeleteExpiredBeforeFn: func(ctx context.Context, before time.Time, limit int) (int64, error) {
+				if !before.Equal(now) {
+					t.Fatalf("unexpected before: %v", before)
+				}
+				return 3, nil
+			},
+		}
+		svc := NewMaintenanceService(testutil.NewTestLogger(), nil, nil, nil, nil, nil, nil, exportRepo, NotificationRetentionPolicy{})
+		result, err := svc.RunJob(context.Background(), MaintenanceJobPurgeUserDataExports, now)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if result.Affected != 5 || result.Details["failed_stale"] != 2 || result.Details["deleted_expired"] != 3 {
+			t.Fatalf("unexpected result: %+v", result)
+		}
+	})
+}
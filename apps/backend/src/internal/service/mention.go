@@ -0,0 +1,44 @@
+package service
+
+import (
+	"regexp"
+	"strings"
+)
+
+// 1つのメモから通知するメンションの最大数（大量のメンションによる通知のばらまきを防ぐ）。
+const maxMentionsPerNote = 10
+
+// メモ中のメンション（@display_id）の形式。
+// メールアドレスなどを誤って拾わないよう、直前が英数字・記号（_ - . @）の場合はメンションとして扱わない。
+var mentionPattern = regexp.MustCompile(`(?:^|[^A-Za-z0-9_.@-])@([A-Za-z0-9](?:[A-Za-z0-9_-]*[A-Za-z0-9])?)`)
+
+// メモからメンションされた display_id を出現順に重複なく取り出す。
+// display_id として有効な形式のもののみ、最大 maxMentionsPerNote 件返す。
+func parseMentions(note string) []string {
+	matches := mentionPattern.FindAllStringSubmatch(note, -1)
+	seen := make(map[string]struct{}, len(matches))
+	displayIDs := make([]string, 0, len(matches))
+	for _, m := range matches {
+		displayID := NormalizeCustomUserDisplayID(m[1])
+		if len(displayID) < customDisplayIDMinLen || len(displayID) > customDisplayIDMaxLen {
+			continue
+		}
+		if _, ok := seen[displayID]; ok {
+			continue
+		}
+		seen[displayID] = struct{}{}
+		displayIDs = append(displayIDs, displayID)
+		if len(displayIDs) == maxMentionsPerNote {
+			break
+		}
+	}
+	return displayIDs
+}
+
+// メモにメンションが含まれる場合、メンションされた display_id を返す。
+func mentionsInNote(note *string) []string {
+	if note == nil || !strings.Contains(*note, "@") {
+		return nil
+	}
+	return parseMentions(*note)
+}
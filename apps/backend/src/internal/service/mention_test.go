@@ -0,0 +1,45 @@
+package service
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseMentions(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name string
+		note string
+		want []string
+	}{
+		{name: "メンションなし", note: "最高の映画", want: []string{}},
+		{name: "先頭・文中のメンション", note: "@alice と @bob_01 におすすめ", want: []string{"alice", "bob_01"}},
+		{name: "句読点・改行の後", note: "見て！@alice\n(@carol)", want: []string{"alice", "carol"}},
+		{name: "大文字は小文字にする", note: "@Alice", want: []string{"alice"}},
+		{name: "重複は1件にまとめる", note: "@alice @alice @ALICE", want: []string{"alice"}},
+		{name: "末尾の記号は含めない", note: "@alice_ さんへ", want: []string{"alice"}},
+		{name: "メールアドレスは対象外", note: "mail@example.com", want: []string{}},
+		{name: "短すぎる・長すぎるものは対象外", note: "@ab @" + strings.Repeat("a", 31), want: []string{}},
+	}
+	for _, tc := range cases {
+		if got := parseMentions(tc.note); !reflect.DeepEqual(got, tc.want) {
+			t.Fatalf("%s: expected %v, got %v", tc.name, tc.want, got)
+		}
+	}
+}
+
+func TestParseMentions_Limit(t *testing.T) {
+	t.Parallel()
+
+	mentions := make([]string, 0, maxMentionsPerNote+5)
+	for i := 0; i < maxMentionsPerNote+5; i++ {
+		mentions = append(mentions, fmt.Sprintf("@user%02d", i))
+	}
+	got := parseMentions(strings.Join(mentions, " "))
+	if len(got) != maxMentionsPerNote || got[0] != "user00" {
+		t.Fatalf("expected first %d mentions, got %v", maxMentionsPerNote, got)
+	}
+}
@@ -0,0 +1,304 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"cinetag-backend/src/internal/model"
+	"cinetag-backend/src/internal/repository"
+
+	"gorm.io/gorm"
+)
+
+// レビューの最大文字数。
+const movieReviewMaxLength = 1000
+
+// 評価の値が不正な場合のエラー（0.5〜5.0 の 0.5 刻み以外）。
+var ErrInvalidRating = errors.New("rating must be between 0.5 and 5.0 in steps of 0.5")
+
+// レビューが長すぎる場合のエラー。
+var ErrReviewTooLong = fmt.Errorf("review must be at most %d characters", movieReviewMaxLength)
+
+// 評価が登録されていない場合のエラー。
+var ErrMovieRatingNotFound = errors.New("movie rating not found")
+
+// 評価登録時の入力値。
+type SetMovieRatingInput struct {
+	Rating    float64
+	Review    *string // 任意（空文字の場合はレビューなし）
+	IsSpoiler bool
+}
+
+// 評価一覧に表示する評価者。
+type MovieRatingUser struct {
+	ID          string  `json:"id"`
+	DisplayID   string  `json:"display_id"`
+	DisplayName string  `json:"display_name"`
+	AvatarURL   *string `json:"avatar_url,omitempty"`
+}
+
+// 評価の1件分。
+// - 映画別の一覧では User を、ユーザー別の一覧では Movie を埋める。
+type MovieRatingItem struct {
+	ID          string           `json:"id"`
+	TmdbMovieID int              `json:"tmdb_movie_id"`
+	Rating      float64          `json:"rating"`
+	Review      *string          `json:"review,omitempty"`
+	IsSpoiler   bool             `json:"is_spoiler"`
+	CreatedAt   time.Time        `json:"created_at"`
+	UpdatedAt   time.Time        `json:"updated_at"`
+	User        *MovieRatingUser `json:"user,omitempty"`
+	Movie       *MovieRef        `json:"movie,omitempty"`
+}
+
+// 映画ごとの cinetag 内の評価の集計。
+type MovieRatingSummary struct {
+	Average *float64 `json:"average"` // 評価がない場合は null
+	Count   int64    `json:"count"`
+}
+
+// 映画の評価・レビューに関するユースケースを表すインターフェース。
+type MovieRatingService interface {
+	// 評価を登録する（既に登録済みの場合は上書きする）。
+	SetRating(ctx context.Context, userID string, tmdbMovieID int, in SetMovieRatingInput) (*MovieRatingItem, error)
+
+	// 評価を削除する。
+	// - 未登録の場合は ErrMovieRatingNotFound を返す。
+	DeleteRating(ctx context.Context, userID string, tmdbMovieID int) error
+
+	// 指定ユーザーの評価一覧を返す（更新日時の新しい順）。
+	ListUserRatings(ctx context.Context, userID string, page, pageSize int) ([]MovieRatingItem, int64, error)
+
+	// 指定映画の評価一覧を返す（更新日時の新しい順）。
+	// - 非公開アカウントの評価は本人・フォロワーのみに表示する。
+	// - ビューアーがブロックしているユーザーの評価は表示しない。
+	ListMovieRatings(ctx context.Context, tmdbMovieID int, viewerUserID *string, page, pageSize int) ([]MovieRatingItem, int64, error)
+
+	// 指定映画の評価の集計を返す。
+	GetSummary(ctx context.Context, tmdbMovieID int) (*MovieRatingSummary, error)
+}
+
+type movieRatingService struct {
+	logger        *slog.Logger
+	ratingRepo    repository.MovieRatingRepository
+	userBlockRepo repository.UserBlockRepository
+	movieService  MovieService
+}
+
+// MovieRatingService を生成する。
+func NewMovieRatingService(
+	logger *slog.Logger,
+	ratingRepo repository.MovieRatingRepository,
+	userBlockRepo repository.UserBlockRepository,
+	movieService MovieService,
+) MovieRatingService {
+	return &movieRatingService{
+		logger:        logger,
+		ratingRepo:    ratingRepo,
+		userBlockRepo: userBlockRepo,
+		movieService:  movieService,
+	}
+}
+
+// 評価として有効な値（0.5〜5.0 の 0.5 刻み）かチェックする。
+func isValidRating(rating float64) bool {
+	if rating < 0.5 || rating > 5 {
+		return false
+	}
+	return rating*2 == math.Trunc(rating*2)
+}
+
+// 評価を登録する。
+func (s *movieRatingService) SetRating(ctx context.Context, userID string, tmdbMovieID int, in SetMovieRatingInput) (*MovieRatingItem, error) {
+	if strings.TrimSpace(userID) == "" {
+		return nil, fmt.Errorf("user_id is required")
+	}
+	if tmdbMovieID <= 0 {
+		return nil, fmt.Errorf("tmdb_movie_id is required")
+	}
+	if !isValidRating(in.Rating) {
+		return nil, ErrInvalidRating
+	}
+
+	var review *string
+	if in.Review != nil {
+		trimmed := strings.TrimSpace(*in.Review)
+		if utf8.RuneCountInString(trimmed) > movieReviewMaxLength {
+			return nil, ErrReviewTooLong
+		}
+		if trimmed != "" {
+			review = &trimmed
+		}
+	}
+	// レビューがない場合、ネタバレフラグは意味を持たないため false に揃える
+	isSpoiler := in.IsSpoiler && review != nil
+
+	now := time.Now()
+	rating := &model.MovieRating{
+		UserID:      userID,
+		TmdbMovieID: tmdbMovieID,
+		Rating:      in.Rating,
+		Review:      review,
+		IsSpoiler:   isSpoiler,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	if err := s.ratingRepo.Upsert(ctx, rating); err != nil {
+		return nil, err
+	}
+
+	item := toMovieRatingItem(rating)
+	return &item, nil
+}
+
+// 評価を削除する。
+func (s *movieRatingService) DeleteRating(ctx context.Context, userID string, tmdbMovieID int) error {
+	// 存在確認と削除の間に別リクエストで削除される場合に備え、削除件数で未評価を判定する
+	if err := s.ratingRepo.Delete(ctx, userID, tmdbMovieID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrMovieRatingNotFound
+		}
+		return err
+	}
+	return nil
+}
+
+// 指定ユーザーの評価一覧を返す。
+func (s *movieRatingService) ListUserRatings(ctx context.Context, userID string, page, pageSize int) ([]MovieRatingItem, int64, error) {
+	if strings.TrimSpace(userID) == "" {
+		return nil, 0, fmt.Errorf("user_id is required")
+	}
+	page, pageSize = normalizeMovieRatingPaging(page, pageSize)
+
+	rows, total, err := s.ratingRepo.ListByUser(ctx, repository.MovieRatingListByUserFilter{
+		UserID: userID,
+		Offset: (page - 1) * pageSize,
+		Limit:  pageSize,
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+
+	items := make([]MovieRatingItem, 0, len(rows))
+	for i := range rows {
+		item := toMovieRatingItem(&rows[i].MovieRating)
+		if rows[i].MovieTitle != nil && strings.TrimSpace(*rows[i].MovieTitle) != "" {
+			item.Movie = &MovieRef{
+				Title:         strings.TrimSpace(*rows[i].MovieTitle),
+				OriginalTitle: rows[i].MovieOriginalTitle,
+				PosterPath:    rows[i].MoviePosterPath,
+				ReleaseDate:   formatDate(rows[i].MovieReleaseDate),
+				VoteAverage:   rows[i].MovieVoteAverage,
+			}
+		} else if s.movieService != nil {
+			// ベストエフォートでキャッシュを取得して埋める
+			cache, err := s.movieService.EnsureMovieCache(ctx, rows[i].TmdbMovieID)
+			if err == nil && cache != nil {
+				item.Movie = &MovieRef{
+					Title:         cache.Title,
+					OriginalTitle: cache.OriginalTitle,
+					PosterPath:    cache.PosterPath,
+					ReleaseDate:   formatDate(cache.ReleaseDate),
+					VoteAverage:   cache.VoteAverage,
+				}
+			}
+		}
+		items = append(items, item)
+	}
+	return items, total, nil
+}
+
+// 指定映画の評価一覧を返す。
+func (s *movieRatingService) ListMovieRatings(ctx context.Context, tmdbMovieID int, viewerUserID *string, page, pageSize int) ([]MovieRatingItem, int64, error) {
+	if tmdbMovieID <= 0 {
+		return nil, 0, fmt.Errorf("tmdb_movie_id is required")
+	}
+	page, pageSize = normalizeMovieRatingPaging(page, pageSize)
+
+	viewerID := ""
+	var excludeIDs []string
+	if viewerUserID != nil && strings.TrimSpace(*viewerUserID) != "" {
+		viewerID = strings.TrimSpace(*viewerUserID)
+		if s.userBlockRepo != nil {
+			blocked, err := s.userBlockRepo.ListBlockedIDs(ctx, viewerID)
+			if err != nil {
+				// ブロック一覧の取得に失敗しても一覧自体は返す
+				s.logger.Warn("service.ListMovieRatings failed to list blocked users",
+					slog.String("viewer_user_id", viewerID),
+					slog.Any("error", err),
+				)
+			} else {
+				excludeIDs = blocked
+			}
+		}
+	}
+
+	rows, total, err := s.ratingRepo.ListByMovie(ctx, repository.MovieRatingListByMovieFilter{
+		TmdbMovieID:    tmdbMovieID,
+		ViewerID:       viewerID,
+		ExcludeUserIDs: excludeIDs,
+		Offset:         (page - 1) * pageSize,
+		Limit:          pageSize,
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+
+	items := make([]MovieRatingItem, 0, len(rows))
+	for i := range rows {
+		item := toMovieRatingItem(&rows[i].MovieRating)
+		item.User = &MovieRatingUser{
+			ID:          rows[i].UserID,
+			DisplayID:   rows[i].UserDisplayID,
+			DisplayName: rows[i].UserDisplayName,
+			AvatarURL:   rows[i].UserAvatarURL,
+		}
+		items = append(items, item)
+	}
+	return items, total, nil
+}
+
+// 指定映画の評価の集計を返す。
+func (s *movieRatingService) GetSummary(ctx context.Context, tmdbMovieID int) (*MovieRatingSummary, error) {
+	agg, err := s.ratingRepo.GetAggregate(ctx, tmdbMovieID)
+	if err != nil {
+		return nil, err
+	}
+	summary := &MovieRatingSummary{Count: agg.Count}
+	if agg.Count > 0 {
+		// 小数第2位で丸める
+		avg := math.Round(agg.Average*100) / 100
+		summary.Average = &avg
+	}
+	return summary, nil
+}
+
+// ページングの値を正規化する。
+func normalizeMovieRatingPaging(page, pageSize int) (int, int) {
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 || pageSize > 100 {
+		pageSize = 20
+	}
+	return page, pageSize
+}
+
+// 評価をレスポンス形式に変換する。
+func toMovieRatingItem(r *model.MovieRating) MovieRatingItem {
+	return MovieRatingItem{
+		ID:          r.ID,
+		TmdbMovieID: r.TmdbMovieID,
+		Rating:      r.Rating,
+		Review:      r.Review,
+		IsSpoiler:   r.IsSpoiler,
+		CreatedAt:   r.CreatedAt,
+		UpdatedAt:   r.UpdatedAt,
+	}
+}
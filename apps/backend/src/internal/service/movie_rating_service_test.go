@@ -0,0 +1,170 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"cinetag-backend/src/internal/model"
+	"cinetag-backend/src/internal/repository"
+	"cinetag-backend/src/internal/testutil"
+
+	"gorm.io/gorm"
+)
+
+func TestIsValidRating(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		rating float64
+		want   bool
+	}{
+		{rating: 0, want: false},
+		{rating: 0.5, want: true},
+		{rating: 3, want: true},
+		{rating: 3.5, want: true},
+		{rating: 3.3, want: false},
+		{rating: 5, want: true},
+		{rating: 5.5, want: false},
+	}
+	for _, tt := range tests {
+		if got := isValidRating(tt.rating); got != tt.want {
+			t.Fatalf("isValidRating(%v): expected %v, got %v", tt.rating, tt.want, got)
+		}
+	}
+}
+
+func TestMovieRatingService_SetRating(t *testing.T) {
+	t.Parallel()
+
+	t.Run("不正な評価: ErrInvalidRating", func(t *testing.T) {
+		t.Parallel()
+		svc := NewMovieRatingService(testutil.NewTestLogger(), &testutil.FakeMovieRatingRepository{}, nil, nil)
+		_, err := svc.SetRating(context.Background(), "u1", 100, SetMovieRatingInput{Rating: 4.2})
+		if !errors.Is(err, ErrInvalidRating) {
+			t.Fatalf("expected ErrInvalidRating, got: %v", err)
+		}
+	})
+
+	t.Run("レビューが長すぎる: ErrReviewTooLong", func(t *testing.T) {
+		t.Parallel()
+		svc := NewMovieRatingService(testutil.NewTestLogger(), &testutil.FakeMovieRatingRepository{}, nil, nil)
+		review := strings.Repeat("あ", movieReviewMaxLength+1)
+		_, err := svc.SetRating(context.Background(), "u1", 100, SetMovieRatingInput{Rating: 4, Review: &review})
+		if !errors.Is(err, ErrReviewTooLong) {
+			t.Fatalf("expected ErrReviewTooLong, got: %v", err)
+		}
+	})
+
+	t.Run("成功: 空のレビューはなしとして扱い、ネタバレフラグも外す", func(t *testing.T) {
+		t.Parallel()
+
+		var saved *model.MovieRating
+		repo := &testutil.FakeMovieRatingRepository{
+			UpsertFn: func(ctx context.Context, rating *model.MovieRating) error {
+				saved = rating
+				return nil
+			},
+		}
+		svc := NewMovieRatingService(testutil.NewTestLogger(), repo, nil, nil)
+		review := "   "
+		got, err := svc.SetRating(context.Background(), "u1", 100, SetMovieRatingInput{Rating: 4.5, Review: &review, IsSpoiler: true})
+		if err != nil {
+			t.Fatalf("expected no error, got: %v", err)
+		}
+		if saved == nil || saved.Rating != 4.5 || saved.Review != nil || saved.IsSpoiler {
+			t.Fatalf("unexpected saved rating: %+v", saved)
+		}
+		if got.Rating != 4.5 || got.TmdbMovieID != 100 {
+			t.Fatalf("unexpected result: %+v", got)
+		}
+	})
+}
+
+func TestMovieRatingService_DeleteRating(t *testing.T) {
+	t.Parallel()
+
+	t.Run("未登録: ErrMovieRatingNotFound", func(t *testing.T) {
+		t.Parallel()
+
+		repo := &testutil.FakeMovieRatingRepository{
+			DeleteFn: func(ctx context.Context, userID string, tmdbMovieID int) error {
+				return gorm.ErrRecordNotFound
+			},
+		}
+		svc := NewMovieRatingService(testutil.NewTestLogger(), repo, nil, nil)
+		if err := svc.DeleteRating(context.Background(), "u1", 100); !errors.Is(err, ErrMovieRatingNotFound) {
+			t.Fatalf("expected ErrMovieRatingNotFound, got: %v", err)
+		}
+	})
+}
+
+func TestMovieRatingService_ListMovieRatings(t *testing.T) {
+	t.Parallel()
+
+	t.Run("ビューアーがブロックしているユーザーを除外し、評価者情報を埋める", func(t *testing.T) {
+		t.Parallel()
+
+		var gotFilter repository.MovieRatingListByMovieFilter
+		repo := &testutil.FakeMovieRatingRepository{
+			ListByMovieFn: func(ctx context.Context, filter repository.MovieRatingListByMovieFilter) ([]repository.MovieRatingWithUser, int64, error) {
+				gotFilter = filter
+				return []repository.MovieRatingWithUser{
+					{MovieRating: model.MovieRating{ID: "r1", UserID: "u2", TmdbMovieID: 100, Rating: 3}, UserDisplayID: "taro", UserDisplayName: "Taro"},
+				}, 1, nil
+			},
+		}
+		blockRepo := &testutil.FakeUserBlockRepository{
+			ListBlockedIDsFn: func(ctx context.Context, blockerID string) ([]string, error) {
+				return []string{"blocked1"}, nil
+			},
+		}
+		svc := NewMovieRatingService(testutil.NewTestLogger(), repo, blockRepo, nil)
+
+		viewer := "u1"
+		items, total, err := svc.ListMovieRatings(context.Background(), 100, &viewer, 2, 10)
+		if err != nil {
+			t.Fatalf("expected no error, got: %v", err)
+		}
+		if gotFilter.ViewerID != "u1" || len(gotFilter.ExcludeUserIDs) != 1 || gotFilter.Offset != 10 || gotFilter.Limit != 10 {
+			t.Fatalf("unexpected filter: %+v", gotFilter)
+		}
+		if total != 1 || len(items) != 1 || items[0].User == nil || items[0].User.DisplayID != "taro" {
+			t.Fatalf("unexpected items: %+v", items)
+		}
+	})
+}
+
+func TestMovieRatingService_GetSummary(t *testing.T) {
+	t.Parallel()
+
+	t.Run("評価なし: 平均は nil", func(t *testing.T) {
+		t.Parallel()
+		svc := NewMovieRatingService(testutil.NewTestLogger(), &testutil.FakeMovieRatingRepository{}, nil, nil)
+		got, err := svc.GetSummary(context.Background(), 100)
+		if err != nil {
+			t.Fatalf("expected no error, got: %v", err)
+		}
+		if got.Count != 0 || got.Average != nil {
+			t.Fatalf("unexpected summary: %+v", got)
+		}
+	})
+
+	t.Run("評価あり: 平均を小数第2位で丸める", func(t *testing.T) {
+		t.Parallel()
+		repo := &testutil.FakeMovieRatingRepository{
+			GetAggregateFn: func(ctx context.Context, tmdbMovieID int) (*repository.MovieRatingAggregate, error) {
+				return &repository.MovieRatingAggregate{Average: 3.83333, Count: 3}, nil
+			},
+		}
+		svc := NewMovieRatingService(testutil.NewTestLogger(), repo, nil, nil)
+		got, err := svc.GetSummary(context.Background(), 100)
+		if err != nil {
+			t.Fatalf("expected no error, got: %v", err)
+		}
+		if got.Count != 3 || got.Average == nil || *got.Average != 3.83 {
+			t.Fatalf("unexpected summary: %+v", got)
+		}
+	})
+}
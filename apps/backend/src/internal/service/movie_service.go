@@ -38,6 +38,10 @@ type MovieService interface {
 
 	// 指定した TMDB 映画 ID が含まれるタグの一覧を取得する。
 	GetMovieRelatedTags(ctx context.Context, tmdbMovieID int, limit int) ([]MovieRelatedTagItem, error)
+
+	// 有効期限を過ぎた movie_cache を期限の古い順に最大 limit 件、TMDB から取得し直す。
+	// 1件の失敗で全体を止めず、更新できた件数と発生したエラーをまとめて返す。
+	RefreshExpiredMovieCaches(ctx context.Context, now time.Time, limit int) (int, error)
 }
 
 // TMDB 連携に必要な設定値。
@@ -568,6 +572,12 @@ type MovieDetailResponse struct {
 	ProductionCountries []ProductionCountry `json:"production_countries"`
 	Directors           []string            `json:"directors"`
 	Cast                []CastMember        `json:"cast"`
+
+	// cinetag ユーザーによる評価の集計。
+	CinetagRating *MovieRatingSummary `json:"cinetag_rating,omitempty"`
+
+	// ログイン中のビューアーの視聴ステータス（未設定・未ログイン時は省略）。
+	ViewerStatus *MovieWatchStatusRef `json:"viewer_status,omitempty"`
 }
 
 type GenreItem struct {
@@ -683,6 +693,7 @@ func (s *movieService) GetMovieRelatedTags(ctx context.Context, tmdbMovieID int,
 			(SELECT COUNT(*) FROM tag_followers WHERE tag_id = t.id) AS follower_count,
 			(SELECT COUNT(*) FROM tag_movies WHERE tag_id = t.id) AS movie_count`).
 		Joins("JOIN tags t ON t.id = tm.tag_id AND t.is_public = true").
+		Joins("JOIN users u ON u.id = t.user_id AND u.deleted_at IS NULL").
 		Where("tm.tmdb_movie_id = ?", tmdbMovieID).
 		Order("follower_count DESC").
 		Limit(limit).
@@ -697,6 +708,42 @@ func (s *movieService) GetMovieRelatedTags(ctx context.Context, tmdbMovieID int,
 	return results, nil
 }
 
+// 有効期限を過ぎた movie_cache を TMDB から取得し直す。
+func (s *movieService) RefreshExpiredMovieCaches(ctx context.Context, now time.Time, limit int) (int, error) {
+	if s.cfg.APIKey == "" {
+		return 0, errors.New("TMDB_API_KEY is not set")
+	}
+	if limit <= 0 {
+		return 0, nil
+	}
+
+	var ids []int
+	err := s.db.WithContext(ctx).
+		Model(&model.MovieCache{}).
+		Where("expires_at <= ?", now).
+		Order("expires_at ASC").
+		Limit(limit).
+		Pluck("tmdb_movie_id", &ids).Error
+	if err != nil {
+		return 0, err
+	}
+
+	refreshed := 0
+	var errs []error
+	for _, id := range ids {
+		if _, err := s.EnsureMovieCache(ctx, id); err != nil {
+			s.logger.Warn("service.RefreshExpiredMovieCaches failed to refresh movie",
+				slog.Int("tmdb_movie_id", id),
+				slog.Any("error", err),
+			)
+			errs = append(errs, fmt.Errorf("refresh movie %d: %w", id, err))
+			continue
+		}
+		refreshed++
+	}
+	return refreshed, errors.Join(errs...)
+}
+
 // movie_cache テーブルに対して UPSERT を行う。
 func (s *movieService) upsertMovieCache(ctx context.Context, cache *model.MovieCache) error {
 	return s.db.WithContext(ctx).Clauses(clause.OnConflict{
@@ -0,0 +1,228 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"cinetag-backend/src/internal/model"
+	"cinetag-backend/src/internal/repository"
+
+	"gorm.io/gorm"
+)
+
+// 視聴ステータスの値が不正な場合のエラー。
+var ErrInvalidMovieStatus = errors.New("status must be 'watched', 'want_to_watch' or 'dropped'")
+
+// 視聴日の指定が不正な場合のエラー（watched 以外での指定、未来日の指定）。
+var ErrInvalidWatchedAt = errors.New("watched_at can only be set to a past date for 'watched'")
+
+// 視聴ステータスが登録されていない場合のエラー。
+var ErrMovieStatusNotFound = errors.New("movie status not found")
+
+// 視聴ステータス設定時の入力値。
+type SetMovieStatusInput struct {
+	Status    string
+	WatchedAt *time.Time // 観た日（任意。Status が watched の場合のみ指定できる）
+}
+
+// 映画情報に埋め込む、閲覧者の視聴ステータス。
+type MovieWatchStatusRef struct {
+	Status    string  `json:"status"`
+	WatchedAt *string `json:"watched_at,omitempty"` // YYYY-MM-DD
+}
+
+// 視聴ステータス一覧の1件分。
+type MovieStatusItem struct {
+	TmdbMovieID int       `json:"tmdb_movie_id"`
+	Status      string    `json:"status"`
+	WatchedAt   *string   `json:"watched_at,omitempty"` // YYYY-MM-DD
+	UpdatedAt   time.Time `json:"updated_at"`
+	Movie       *MovieRef `json:"movie,omitempty"`
+}
+
+// 映画の視聴ステータスに関するユースケースを表すインターフェース。
+type MovieStatusService interface {
+	// 視聴ステータスを設定する（既に設定済みの場合は上書きする）。
+	SetStatus(ctx context.Context, userID string, tmdbMovieID int, in SetMovieStatusInput) (*MovieWatchStatusRef, error)
+
+	// 視聴ステータスを解除する。
+	// - 未設定の場合は ErrMovieStatusNotFound を返す。
+	ClearStatus(ctx context.Context, userID string, tmdbMovieID int) error
+
+	// 指定映画の視聴ステータスを取得する（未設定の場合は nil を返す）。
+	GetStatus(ctx context.Context, userID string, tmdbMovieID int) (*MovieWatchStatusRef, error)
+
+	// 視聴ステータス一覧を返す（更新日時の新しい順）。
+	// - status が空文字の場合は全てのステータスを返す。
+	ListStatuses(ctx context.Context, userID, status string, page, pageSize int) ([]MovieStatusItem, int64, error)
+}
+
+type movieStatusService struct {
+	logger          *slog.Logger
+	movieStatusRepo repository.UserMovieStatusRepository
+	movieService    MovieService
+}
+
+// MovieStatusService を生成する。
+func NewMovieStatusService(logger *slog.Logger, movieStatusRepo repository.UserMovieStatusRepository, movieService MovieService) MovieStatusService {
+	return &movieStatusService{
+		logger:          logger,
+		movieStatusRepo: movieStatusRepo,
+		movieService:    movieService,
+	}
+}
+
+// 視聴ステータスとして有効な値かチェックする。
+func isValidMovieStatus(status string) bool {
+	switch status {
+	case model.MovieWatchStatusWatched, model.MovieWatchStatusWantToWatch, model.MovieWatchStatusDropped:
+		return true
+	}
+	return false
+}
+
+// 視聴ステータスを設定する。
+func (s *movieStatusService) SetStatus(ctx context.Context, userID string, tmdbMovieID int, in SetMovieStatusInput) (*MovieWatchStatusRef, error) {
+	if strings.TrimSpace(userID) == "" {
+		return nil, fmt.Errorf("user_id is required")
+	}
+	if tmdbMovieID <= 0 {
+		return nil, fmt.Errorf("tmdb_movie_id is required")
+	}
+	if !isValidMovieStatus(in.Status) {
+		return nil, ErrInvalidMovieStatus
+	}
+
+	now := time.Now()
+	if in.WatchedAt != nil {
+		// タイムゾーン差を考慮し、1日先までは許容する
+		if in.Status != model.MovieWatchStatusWatched || in.WatchedAt.After(now.Add(24*time.Hour)) {
+			return nil, ErrInvalidWatchedAt
+		}
+	}
+
+	status := &model.UserMovieStatus{
+		UserID:      userID,
+		TmdbMovieID: tmdbMovieID,
+		Status:      in.Status,
+		WatchedAt:   in.WatchedAt,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	if err := s.movieStatusRepo.Upsert(ctx, status); err != nil {
+		return nil, err
+	}
+	return toMovieWatchStatusRef(status), nil
+}
+
+// 視聴ステータスを解除する。
+func (s *movieStatusService) ClearStatus(ctx context.Context, userID string, tmdbMovieID int) error {
+	// 存在確認と削除の間に別リクエストで解除される場合に備え、削除件数で未設定を判定する
+	if err := s.movieStatusRepo.Delete(ctx, userID, tmdbMovieID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrMovieStatusNotFound
+		}
+		return err
+	}
+	return nil
+}
+
+// 指定映画の視聴ステータスを取得する。
+func (s *movieStatusService) GetStatus(ctx context.Context, userID string, tmdbMovieID int) (*MovieWatchStatusRef, error) {
+	if strings.TrimSpace(userID) == "" {
+		return nil, nil
+	}
+	status, err := s.movieStatusRepo.FindByUserAndMovie(ctx, userID, tmdbMovieID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return toMovieWatchStatusRef(status), nil
+}
+
+// 視聴ステータス一覧を返す。
+func (s *movieStatusService) ListStatuses(ctx context.Context, userID, status string, page, pageSize int) ([]MovieStatusItem, int64, error) {
+	if strings.TrimSpace(userID) == "" {
+		return nil, 0, fmt.Errorf("user_id is required")
+	}
+	if status != "" && !isValidMovieStatus(status) {
+		return nil, 0, ErrInvalidMovieStatus
+	}
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 || pageSize > 100 {
+		pageSize = 20
+	}
+
+	rows, total, err := s.movieStatusRepo.ListByUser(ctx, repository.UserMovieStatusListFilter{
+		UserID: userID,
+		Status: status,
+		Offset: (page - 1) * pageSize,
+		Limit:  pageSize,
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+
+	items := make([]MovieStatusItem, 0, len(rows))
+	for _, r := range rows {
+		var movie *MovieRef
+		if r.MovieTitle != nil && strings.TrimSpace(*r.MovieTitle) != "" {
+			movie = &MovieRef{
+				Title:         strings.TrimSpace(*r.MovieTitle),
+				OriginalTitle: r.MovieOriginalTitle,
+				PosterPath:    r.MoviePosterPath,
+				ReleaseDate:   formatDate(r.MovieReleaseDate),
+				VoteAverage:   r.MovieVoteAverage,
+			}
+		} else if s.movieService != nil {
+			// ベストエフォートでキャッシュを取得して埋める
+			cache, err := s.movieService.EnsureMovieCache(ctx, r.TmdbMovieID)
+			if err == nil && cache != nil {
+				movie = &MovieRef{
+					Title:         cache.Title,
+					OriginalTitle: cache.OriginalTitle,
+					PosterPath:    cache.PosterPath,
+					ReleaseDate:   formatDate(cache.ReleaseDate),
+					VoteAverage:   cache.VoteAverage,
+				}
+			}
+		}
+
+		items = append(items, MovieStatusItem{
+			TmdbMovieID: r.TmdbMovieID,
+			Status:      r.Status,
+			WatchedAt:   formatDate(r.WatchedAt),
+			UpdatedAt:   r.UpdatedAt,
+			Movie:       movie,
+		})
+	}
+	return items, total, nil
+}
+
+// 視聴ステータスを映画情報に埋め込む形式に変換する（nil の場合は nil を返す）。
+func toMovieWatchStatusRef(status *model.UserMovieStatus) *MovieWatchStatusRef {
+	if status == nil {
+		return nil
+	}
+	return &MovieWatchStatusRef{
+		Status:    status.Status,
+		WatchedAt: formatDate(status.WatchedAt),
+	}
+}
+
+// 日付を YYYY-MM-DD 形式の文字列に変換する（nil の場合は nil を返す）。
+func formatDate(t *time.Time) *string {
+	if t == nil {
+		return nil
+	}
+	s := t.Format("2006-01-02")
+	return &s
+}
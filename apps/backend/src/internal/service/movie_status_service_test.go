@@ -0,0 +1,128 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"cinetag-backend/src/internal/model"
+	"cinetag-backend/src/internal/repository"
+	"cinetag-backend/src/internal/testutil"
+
+	"gorm.io/gorm"
+)
+
+func TestMovieStatusService_SetStatus(t *testing.T) {
+	t.Parallel()
+
+	t.Run("不正なステータス: ErrInvalidMovieStatus", func(t *testing.T) {
+		t.Parallel()
+		svc := NewMovieStatusService(testutil.NewTestLogger(), &testutil.FakeUserMovieStatusRepository{}, nil)
+		_, err := svc.SetStatus(context.Background(), "u1", 100, SetMovieStatusInput{Status: "loved"})
+		if !errors.Is(err, ErrInvalidMovieStatus) {
+			t.Fatalf("expected ErrInvalidMovieStatus, got: %v", err)
+		}
+	})
+
+	t.Run("watched 以外で視聴日を指定: ErrInvalidWatchedAt", func(t *testing.T) {
+		t.Parallel()
+		svc := NewMovieStatusService(testutil.NewTestLogger(), &testutil.FakeUserMovieStatusRepository{}, nil)
+		d := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+		_, err := svc.SetStatus(context.Background(), "u1", 100, SetMovieStatusInput{Status: model.MovieWatchStatusWantToWatch, WatchedAt: &d})
+		if !errors.Is(err, ErrInvalidWatchedAt) {
+			t.Fatalf("expected ErrInvalidWatchedAt, got: %v", err)
+		}
+	})
+
+	t.Run("未来の視聴日: ErrInvalidWatchedAt", func(t *testing.T) {
+		t.Parallel()
+		svc := NewMovieStatusService(testutil.NewTestLogger(), &testutil.FakeUserMovieStatusRepository{}, nil)
+		d := time.Now().AddDate(0, 0, 7)
+		_, err := svc.SetStatus(context.Background(), "u1", 100, SetMovieStatusInput{Status: model.MovieWatchStatusWatched, WatchedAt: &d})
+		if !errors.Is(err, ErrInvalidWatchedAt) {
+			t.Fatalf("expected ErrInvalidWatchedAt, got: %v", err)
+		}
+	})
+
+	t.Run("成功: 視聴日付きで保存する", func(t *testing.T) {
+		t.Parallel()
+
+		var saved *model.UserMovieStatus
+		repo := &testutil.FakeUserMovieStatusRepository{
+			UpsertFn: func(ctx context.Context, status *model.UserMovieStatus) error {
+				saved = status
+				return nil
+			},
+		}
+		svc := NewMovieStatusService(testutil.NewTestLogger(), repo, nil)
+		d := time.Date(2024, 3, 15, 0, 0, 0, 0, time.UTC)
+		got, err := svc.SetStatus(context.Background(), "u1", 100, SetMovieStatusInput{Status: model.MovieWatchStatusWatched, WatchedAt: &d})
+		if err != nil {
+			t.Fatalf("expected no error, got: %v", err)
+		}
+		if saved == nil || saved.UserID != "u1" || saved.TmdbMovieID != 100 || saved.Status != model.MovieWatchStatusWatched {
+			t.Fatalf("unexpected saved status: %+v", saved)
+		}
+		if got.Status != model.MovieWatchStatusWatched || got.WatchedAt == nil || *got.WatchedAt != "2024-03-15" {
+			t.Fatalf("unexpected result: %+v", got)
+		}
+	})
+}
+
+func TestMovieStatusService_ClearStatus(t *testing.T) {
+	t.Parallel()
+
+	t.Run("未設定: ErrMovieStatusNotFound", func(t *testing.T) {
+		t.Parallel()
+
+		repo := &testutil.FakeUserMovieStatusRepository{
+			DeleteFn: func(ctx context.Context, userID string, tmdbMovieID int) error {
+				return gorm.ErrRecordNotFound
+			},
+		}
+		svc := NewMovieStatusService(testutil.NewTestLogger(), repo, nil)
+		if err := svc.ClearStatus(context.Background(), "u1", 100); !errors.Is(err, ErrMovieStatusNotFound) {
+			t.Fatalf("expected ErrMovieStatusNotFound, got: %v", err)
+		}
+	})
+}
+
+func TestMovieStatusService_ListStatuses(t *testing.T) {
+	t.Parallel()
+
+	t.Run("不正なステータス: ErrInvalidMovieStatus", func(t *testing.T) {
+		t.Parallel()
+		svc := NewMovieStatusService(testutil.NewTestLogger(), &testutil.FakeUserMovieStatusRepository{}, nil)
+		if _, _, err := svc.ListStatuses(context.Background(), "u1", "loved", 1, 20); !errors.Is(err, ErrInvalidMovieStatus) {
+			t.Fatalf("expected ErrInvalidMovieStatus, got: %v", err)
+		}
+	})
+
+	t.Run("成功: ページングを正規化し、映画情報を埋める", func(t *testing.T) {
+		t.Parallel()
+
+		var gotFilter repository.UserMovieStatusListFilter
+		title := "Movie A"
+		repo := &testutil.FakeUserMovieStatusRepository{
+			ListByUserFn: func(ctx context.Context, filter repository.UserMovieStatusListFilter) ([]repository.UserMovieStatusWithCache, int64, error) {
+				gotFilter = filter
+				return []repository.UserMovieStatusWithCache{
+					{UserMovieStatus: model.UserMovieStatus{TmdbMovieID: 100, Status: model.MovieWatchStatusWantToWatch}, MovieTitle: &title},
+				}, 1, nil
+			},
+		}
+		svc := NewMovieStatusService(testutil.NewTestLogger(), repo, nil)
+
+		items, total, err := svc.ListStatuses(context.Background(), "u1", model.MovieWatchStatusWantToWatch, 0, 500)
+		if err != nil {
+			t.Fatalf("expected no error, got: %v", err)
+		}
+		if gotFilter.UserID != "u1" || gotFilter.Status != model.MovieWatchStatusWantToWatch || gotFilter.Offset != 0 || gotFilter.Limit != 20 {
+			t.Fatalf("unexpected filter: %+v", gotFilter)
+		}
+		if total != 1 || len(items) != 1 || items[0].Movie == nil || items[0].Movie.Title != "Movie A" {
+			t.Fatalf("unexpected items: %+v", items)
+		}
+	})
+}
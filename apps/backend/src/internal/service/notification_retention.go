@@ -0,0 +1,81 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+)
+
+const (
+	// 既読通知を保持する日数の既定値。
+	defaultNotificationReadRetentionDays = 90
+	// ユーザーごとに保持する未読通知の件数の既定値。
+	defaultNotificationUnreadMaxPerUser = 1000
+)
+
+// 通知の保持ポリシー。
+type NotificationRetentionPolicy struct {
+	// ReadRetention は既読通知を保持する期間（最後のイベント日時から数える）。0 の場合は削除しない。
+	ReadRetention time.Duration
+	// UnreadMaxPerUser はユーザーごとに保持する未読通知の件数。超えた分は古いものから削除する。0 の場合は削除しない。
+	UnreadMaxPerUser int
+}
+
+// 通知の削除ジョブの実行結果。
+type NotificationPurgeResult struct {
+	DeletedRead   int64 // 保持期間を過ぎて削除した既読通知の件数
+	DeletedUnread int64 // 上限を超えて削除した未読通知の件数
+}
+
+// 環境変数から通知の保持ポリシーを読み込む。
+// NOTIFICATION_READ_RETENTION_DAYS（既定: 90）、NOTIFICATION_UNREAD_MAX_PER_USER（既定: 1000）を使い、0 を指定するとその削除を行わない。
+func NotificationRetentionPolicyFromEnv() (NotificationRetentionPolicy, error) {
+	days, err := envNonNegativeInt("NOTIFICATION_READ_RETENTION_DAYS", defaultNotificationReadRetentionDays)
+	if err != nil {
+		return NotificationRetentionPolicy{}, err
+	}
+	maxUnread, err := envNonNegativeInt("NOTIFICATION_UNREAD_MAX_PER_USER", defaultNotificationUnreadMaxPerUser)
+	if err != nil {
+		return NotificationRetentionPolicy{}, err
+	}
+	return NotificationRetentionPolicy{
+		ReadRetention:    time.Duration(days) * 24 * time.Hour,
+		UnreadMaxPerUser: maxUnread,
+	}, nil
+}
+
+// 環境変数を0以上の整数として読み込む。未設定の場合は def を返す。
+func envNonNegativeInt(key string, def int) (int, error) {
+	v := os.Getenv(key)
+	if v == "" {
+		return def, nil
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("invalid %s: %q", key, v)
+	}
+	return n, nil
+}
+
+// 保持ポリシーに従い、保持期間を過ぎた既読通知・上限を超えた未読通知をそれぞれ最大 batchSize 件削除する。
+// 削除した通知の受信者にはストリームで配信しない（古い通知のため、次回の一覧取得で反映されれば十分）。
+func (s *notificationService) PurgeNotifications(ctx context.Context, policy NotificationRetentionPolicy, now time.Time, batchSize int) (*NotificationPurgeResult, error) {
+	result := &NotificationPurgeResult{}
+	if policy.ReadRetention > 0 {
+		deleted, err := s.notifRepo.DeleteReadBefore(ctx, now.Add(-policy.ReadRetention), batchSize)
+		if err != nil {
+			return result, err
+		}
+		result.DeletedRead = deleted
+	}
+	if policy.UnreadMaxPerUser > 0 {
+		deleted, err := s.notifRepo.DeleteUnreadOverLimit(ctx, policy.UnreadMaxPerUser, batchSize)
+		if err != nil {
+			return result, err
+		}
+		result.DeletedUnread = deleted
+	}
+	return result, nil
+}
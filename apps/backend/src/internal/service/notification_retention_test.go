@@ -0,0 +1,161 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"cinetag-backend/src/internal/testutil"
+
+	"gorm.io/gorm"
+)
+
+func TestNotificationService_DeleteNotification(t *testing.T) {
+	t.Parallel()
+
+	t.Run("見つからない場合は ErrNotificationNotFound", func(t *testing.T) {
+		t.Parallel()
+
+		notifRepo := &testutil.FakeNotificationRepository{
+			DeleteFn: func(ctx context.Context, notificationID, userID string) error {
+				return gorm.ErrRecordNotFound
+			},
+			SetArchivedFn: func(ctx context.Context, notificationID, userID string, archived bool) error {
+				return gorm.ErrRecordNotFound
+			},
+		}
+		svc := NewNotificationService(testutil.NewTestLogger(), notifRepo, nil, nil, nil, nil, nil, nil, nil, nil)
+
+		if err := svc.DeleteNotification(context.Background(), "n1", "me"); !errors.Is(err, ErrNotificationNotFound) {
+			t.Fatalf("expected ErrNotificationNotFound, got %v", err)
+		}
+		if err := svc.ArchiveNotification(context.Background(), "n1", "me"); !errors.Is(err, ErrNotificationNotFound) {
+			t.Fatalf("expected ErrNotificationNotFound, got %v", err)
+		}
+	})
+
+	t.Run("アーカイブ・解除をリポジトリに渡す", func(t *testing.T) {
+		t.Parallel()
+
+		var got []bool
+		notifRepo := &testutil.FakeNotificationRepository{
+			SetArchivedFn: func(ctx context.Context, notificationID, userID string, archived bool) error {
+				if notificationID != "n1" || userID != "me" {
+					t.Fatalf("unexpected args: %s %s", notificationID, userID)
+				}
+				got = append(got, archived)
+				return nil
+			},
+		}
+		svc := NewNotificationService(testutil.NewTestLogger(), notifRepo, nil, nil, nil, nil, nil, nil, nil, nil)
+
+		if err := svc.ArchiveNotification(context.Background(), "n1", "me"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if err := svc.UnarchiveNotification(context.Background(), "n1", "me"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(got) != 2 || !got[0] || got[1] {
+			t.Fatalf("unexpected archived flags: %v", got)
+		}
+	})
+}
+
+func TestNotificationService_PurgeNotifications(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	t.Run("保持期間を過ぎた既読通知と上限を超えた未読通知を削除する", func(t *testing.T) {
+		t.Parallel()
+
+		notifRepo := &testutil.FakeNotificationRepository{
+			DeleteReadBeforeFn: func(ctx context.Context, before time.Time, limit int) (int64, error) {
+				if !before.Equal(now.Add(-30*24*time.Hour)) || limit != 100 {
+					t.Fatalf("unexpected args: %v %d", before, limit)
+				}
+				return 7, nil
+			},
+			DeleteUnreadOverLimitFn: func(ctx context.Context, maxPerUser, limit int) (int64, error) {
+				if maxPerUser != 500 || limit != 100 {
+					t.Fatalf("unexpected args: %d %d", maxPerUser, limit)
+				}
+				return 3, nil
+			},
+		}
+		svc := NewNotificationService(testutil.NewTestLogger(), notifRepo, nil, nil, nil, nil, nil, nil, nil, nil)
+
+		result, err := svc.PurgeNotifications(context.Background(), NotificationRetentionPolicy{
+			ReadRetention:    30 * 24 * time.Hour,
+			UnreadMaxPerUser: 500,
+		}, now, 100)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if result.DeletedRead != 7 || result.DeletedUnread != 3 {
+			t.Fatalf("unexpected result: %+v", result)
+		}
+	})
+
+	t.Run("0 を指定した削除は行わない", func(t *testing.T) {
+		t.Parallel()
+
+		notifRepo := &testutil.FakeNotificationRepository{
+			DeleteReadBeforeFn: func(ctx context.Context, before time.Time, limit int) (int64, error) {
+				t.Fatal("unexpected DeleteReadBefore call")
+				return 0, nil
+			},
+			DeleteUnreadOverLimitFn: func(ctx context.Context, maxPerUser, limit int) (int64, error) {
+				t.Fatal("unexpected DeleteUnreadOverLimit call")
+				return 0, nil
+			},
+		}
+		svc := NewNotificationService(testutil.NewTestLogger(), notifRepo, nil, nil, nil, nil, nil, nil, nil, nil)
+
+		result, err := svc.PurgeNotifications(context.Background(), NotificationRetentionPolicy{}, now, 100)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if result.DeletedRead != 0 || result.DeletedUnread != 0 {
+			t.Fatalf("unexpected result: %+v", result)
+		}
+	})
+}
+
+func TestNotificationRetentionPolicyFromEnv(t *testing.T) {
+	t.Run("未設定の場合は既定値", func(t *testing.T) {
+		t.Setenv("NOTIFICATION_READ_RETENTION_DAYS", "")
+		t.Setenv("NOTIFICATION_UNREAD_MAX_PER_USER", "")
+
+		policy, err := NotificationRetentionPolicyFromEnv()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if policy.ReadRetention != 90*24*time.Hour || policy.UnreadMaxPerUser != 1000 {
+			t.Fatalf("unexpected policy: %+v", policy)
+		}
+	})
+
+	t.Run("指定した値を使う", func(t *testing.T) {
+		t.Setenv("NOTIFICATION_READ_RETENTION_DAYS", "0")
+		t.Setenv("NOTIFICATION_UNREAD_MAX_PER_USER", "200")
+
+		policy, err := NotificationRetentionPolicyFromEnv()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if policy.ReadRetention != 0 || policy.UnreadMaxPerUser != 200 {
+			t.Fatalf("unexpected policy: %+v", policy)
+		}
+	})
+
+	t.Run("不正な値はエラー", func(t *testing.T) {
+		for _, v := range []string{"abc", "-1"} {
+			t.Setenv("NOTIFICATION_READ_RETENTION_DAYS", v)
+			if _, err := NotificationRetentionPolicyFromEnv(); err == nil {
+				t.Fatalf("expected error for %q", v)
+			}
+		}
+	})
+}
@@ -2,28 +2,74 @@ package service
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"log/slog"
+	"slices"
+	"sort"
 	"time"
 
 	"cinetag-backend/src/internal/model"
+	"cinetag-backend/src/internal/pubsub"
 	"cinetag-backend/src/internal/repository"
 
+	"github.com/google/uuid"
 	"gorm.io/gorm"
 )
 
 // 通知が見つからなかった場合のエラー。
 var ErrNotificationNotFound = errors.New("notification not found")
 
+// 同じタグ・同じ種類の通知を1件にまとめる時間幅。
+// 最後のイベントからこの時間内に起きたイベントは同じ通知にまとめる。
+const notificationGroupWindow = time.Hour
+
+// まとめた通知に含めるアクターのサンプル数（「A、B 他3人」の表示用）。
+const notificationGroupActorSampleSize = 2
+
+// まとめた通知に含める映画タイトルのサンプル数。
+const notificationGroupMovieSampleSize = 3
+
+// 通知ストリームの再開時に一度に取得する通知の最大件数。
+const notificationStreamPageSize = 50
+
+// 通知設定で指定された通知の種類が不正な場合のエラー。
+var ErrInvalidNotificationType = errors.New("invalid notification type")
+
+// 通知設定で指定されたタグIDが UUID 形式でない場合のエラー。
+var ErrInvalidTagID = errors.New("invalid tag id")
+
+// 通知設定で受け取り可否を切り替えられる通知の種類。
+var notificationSettingTypes = []string{
+	model.NotificationTypeTagMovieAdded,
+	model.NotificationTypeTagFollowed,
+	model.NotificationTypeUserFollowed,
+	model.NotificationTypeFollowingUserCreatedTag,
+	model.NotificationTypeFollowRequested,
+	model.NotificationTypeFollowRequestApproved,
+	model.NotificationTypeFollowRequestDenied,
+	model.NotificationTypeTagLiked,
+	model.NotificationTypeTagUpdated,
+	model.NotificationTypeMentionedInNote,
+}
+
 // 通知一覧APIのレスポンスDTO。
+// 同じタグ・同じ種類のイベントが短時間に続いた場合は1件にまとめ、Count に件数を持つ。
+// CreatedAt・Actor・MovieTitle は最新のイベントのもの。
 type NotificationItem struct {
-	ID               string                     `json:"id"`
-	NotificationType string                     `json:"notification_type"`
-	IsRead           bool                       `json:"is_read"`
-	CreatedAt        time.Time                  `json:"created_at"`
-	Actor            *ActorSummary              `json:"actor"`
-	Tag              *TagSummaryForNotification `json:"tag,omitempty"`
-	MovieTitle       *string                    `json:"movie_title,omitempty"`
+	ID               string                              `json:"id"`
+	NotificationType string                              `json:"notification_type"`
+	IsRead           bool                                `json:"is_read"`
+	IsArchived       bool                                `json:"is_archived"`
+	CreatedAt        time.Time                           `json:"created_at"`
+	Actor            *ActorSummary                       `json:"actor"`
+	Tag              *TagSummaryForNotification          `json:"tag,omitempty"`
+	MovieTitle       *string                             `json:"movie_title,omitempty"`
+	Count            int                                 `json:"count"`                  // まとめたイベント数
+	Actors           []ActorSummary                      `json:"actors"`                 // 新しい順に最大2人
+	ActorCount       int                                 `json:"actor_count"`            // アクターの人数
+	MovieTitles      []string                            `json:"movie_titles,omitempty"` // 新しい順に最大3件
+	TagUpdate        *model.NotificationTagUpdateDetails `json:"tag_update,omitempty"`   // tag_updated の変更内容
 }
 
 // 通知内のアクター情報。
@@ -40,53 +86,138 @@ type TagSummaryForNotification struct {
 	Title string `json:"title"`
 }
 
+// 通知ストリームで配信する通知。
+// EventID は Last-Event-ID による再開位置として使うカーソル。
+type NotificationStreamEntry struct {
+	EventID      string
+	Notification *NotificationItem
+}
+
+// 通知設定。
+type NotificationSettings struct {
+	Types     map[string]bool            `json:"types"`      // 通知の種類ごとの受け取り可否
+	MutedTags []NotificationMutedTagItem `json:"muted_tags"` // 映画追加通知をミュートしているタグ
+}
+
+// 映画追加通知をミュートしているタグ。
+type NotificationMutedTagItem struct {
+	ID      string    `json:"id"`
+	Title   string    `json:"title"`
+	MutedAt time.Time `json:"muted_at"`
+}
+
+// 通知設定の更新内容。
+type UpdateNotificationSettingsInput struct {
+	Types        map[string]bool // 指定した種類のみ更新する
+	MuteTagIDs   []string
+	UnmuteTagIDs []string
+}
+
 // 通知に関するユースケースを表すインターフェース。
 type NotificationService interface {
 	// 通知一覧を取得する。unreadOnly が true の場合、未読通知のみに絞る。
-	ListNotifications(ctx context.Context, userID string, page, pageSize int, unreadOnly bool) ([]*NotificationItem, int64, error)
+	// archived が true の場合はアーカイブした通知のみ、false の場合はアーカイブしていない通知のみを返す。
+	ListNotifications(ctx context.Context, userID string, page, pageSize int, unreadOnly, archived bool) ([]*NotificationItem, int64, error)
 	// 未読通知数を取得する。
 	GetUnreadCount(ctx context.Context, userID string) (int64, error)
 	// 指定通知を既読にする。
 	MarkAsRead(ctx context.Context, notificationID, userID string) error
 	// 全通知を既読にする。
 	MarkAllAsRead(ctx context.Context, userID string) error
+	// 指定通知をアーカイブする（通知一覧・未読数から除外する）。
+	ArchiveNotification(ctx context.Context, notificationID, userID string) error
+	// 指定通知のアーカイブを解除する。
+	UnarchiveNotification(ctx context.Context, notificationID, userID string) error
+	// 指定通知を削除する。
+	DeleteNotification(ctx context.Context, notificationID, userID string) error
+	// 既読通知をすべて削除し、削除件数を返す。
+	DeleteReadNotifications(ctx context.Context, userID string) (int64, error)
+	// 保持ポリシーに従い、保持期間を過ぎた既読通知・上限を超えた未読通知をそれぞれ最大 batchSize 件削除する。
+	PurgeNotifications(ctx context.Context, policy NotificationRetentionPolicy, now time.Time, batchSize int) (*NotificationPurgeResult, error)
+	// 通知設定を取得する。
+	GetSettings(ctx context.Context, userID string) (*NotificationSettings, error)
+	// 通知設定を更新し、更新後の設定を返す。
+	// - 不明な通知の種類が指定された場合は ErrInvalidNotificationType を返す。
+	// - ミュート対象のタグが存在しない（または閲覧できない）場合は ErrTagNotFound を返す。
+	UpdateSettings(ctx context.Context, userID string, in UpdateNotificationSettingsInput) (*NotificationSettings, error)
+	// 指定ユーザーの通知・未読数が変化したことを受け取るチャネルを返す。
+	// 返した関数で購読を解除する。変化が続いた場合、通知は1回にまとめられることがある。
+	SubscribeStream(userID string) (<-chan struct{}, func())
+	// lastEventID 以降に作成・更新された通知を古い順に返す。
+	// 戻り値の文字列は次に渡す lastEventID（変化がなければ lastEventID のまま）。
+	// lastEventID が空または不正な場合は通知を返さず、その時点の最新の通知を指すカーソルを返す。
+	ListNotificationsSince(ctx context.Context, userID, lastEventID string) ([]NotificationStreamEntry, string, error)
+	// 以下の Notify* の sourceEventID は通知の元になったアウトボックスイベントの ID。
+	// 同じ sourceEventID で再び呼ばれた場合（再配信）は、通知・フィードイベントを重複して作成しない。
+
 	// タグに映画が追加された通知を生成する。
-	NotifyTagMovieAdded(ctx context.Context, tagID, tagMovieID, actorUserID string) error
+	NotifyTagMovieAdded(ctx context.Context, sourceEventID, tagID, tagMovieID, actorUserID string) error
 	// タグがフォローされた通知を生成する。
-	NotifyTagFollowed(ctx context.Context, tagID, actorUserID string) error
+	NotifyTagFollowed(ctx context.Context, sourceEventID, tagID, actorUserID string) error
+	// タグにいいねされた通知を生成する。
+	NotifyTagLiked(ctx context.Context, sourceEventID, tagID, actorUserID string) error
+	// タグの名前が変わった・非公開になった通知を生成する。
+	NotifyTagUpdated(ctx context.Context, sourceEventID, tagID, actorUserID string, details model.NotificationTagUpdateDetails) error
+	// 映画のメモでメンションされた通知を生成する。
+	NotifyMentionedInNote(ctx context.Context, sourceEventID, tagID, tagMovieID, actorUserID string, displayIDs []string) error
 	// ユーザーがフォローされた通知を生成する。
-	NotifyUserFollowed(ctx context.Context, followeeUserID, actorUserID string) error
+	NotifyUserFollowed(ctx context.Context, sourceEventID, followeeUserID, actorUserID string) error
 	// フォロー中ユーザーが新しいタグを作成した通知を生成する。
-	NotifyFollowingUserCreatedTag(ctx context.Context, tagID, actorUserID string) error
+	NotifyFollowingUserCreatedTag(ctx context.Context, sourceEventID, tagID, actorUserID string) error
+	// 非公開アカウントにフォローリクエストが届いた通知を生成する。
+	NotifyFollowRequested(ctx context.Context, sourceEventID, targetUserID, requesterUserID string) error
+	// フォローリクエストが承認された通知を生成する。
+	NotifyFollowRequestApproved(ctx context.Context, sourceEventID, requesterUserID, targetUserID string) error
+	// フォローリクエストが拒否された通知を生成する。
+	NotifyFollowRequestDenied(ctx context.Context, sourceEventID, requesterUserID, targetUserID string) error
 }
 
 type notificationService struct {
 	logger           *slog.Logger
 	notifRepo        repository.NotificationRepository
+	prefRepo         repository.NotificationPreferenceRepository
 	tagRepo          repository.TagRepository
 	tagFollowerRepo  repository.TagFollowerRepository
 	userFollowerRepo repository.UserFollowerRepository
+	userRepo         repository.UserRepository
+	userBlockRepo    repository.UserBlockRepository
+	feedEventRepo    repository.FeedEventRepository
+	hub              pubsub.Hub
 }
 
 // NotificationService を生成する。
+// prefRepo が指定された場合、通知設定に従って通知先を絞り込む。
+// userRepo・userBlockRepo はメモのメンションの解決に使う（メンション通知を使わない場合は nil でよい）。
+// feedEventRepo が指定された場合、通知の元になったイベントをホームフィード用にも記録する。
+// hub が指定された場合、通知・未読数の変化をストリームの購読者に配信する。
 func NewNotificationService(
 	logger *slog.Logger,
 	notifRepo repository.NotificationRepository,
+	prefRepo repository.NotificationPreferenceRepository,
 	tagRepo repository.TagRepository,
 	tagFollowerRepo repository.TagFollowerRepository,
 	userFollowerRepo repository.UserFollowerRepository,
+	userRepo repository.UserRepository,
+	userBlockRepo repository.UserBlockRepository,
+	feedEventRepo repository.FeedEventRepository,
+	hub pubsub.Hub,
 ) NotificationService {
 	return &notificationService{
 		logger:           logger,
 		notifRepo:        notifRepo,
+		prefRepo:         prefRepo,
 		tagRepo:          tagRepo,
 		tagFollowerRepo:  tagFollowerRepo,
 		userFollowerRepo: userFollowerRepo,
+		userRepo:         userRepo,
+		userBlockRepo:    userBlockRepo,
+		feedEventRepo:    feedEventRepo,
+		hub:              hub,
 	}
 }
 
 // 通知一覧を取得する。unreadOnly が true の場合、未読通知のみに絞る。
-func (s *notificationService) ListNotifications(ctx context.Context, userID string, page, pageSize int, unreadOnly bool) ([]*NotificationItem, int64, error) {
+func (s *notificationService) ListNotifications(ctx context.Context, userID string, page, pageSize int, unreadOnly, archived bool) ([]*NotificationItem, int64, error) {
 	if page < 1 {
 		page = 1
 	}
@@ -97,7 +228,7 @@ func (s *notificationService) ListNotifications(ctx context.Context, userID stri
 		pageSize = 50
 	}
 
-	rows, total, err := s.notifRepo.ListByRecipient(ctx, userID, page, pageSize, unreadOnly)
+	rows, total, err := s.notifRepo.ListByRecipient(ctx, userID, page, pageSize, unreadOnly, archived)
 	if err != nil {
 		return nil, 0, err
 	}
@@ -105,14 +236,41 @@ func (s *notificationService) ListNotifications(ctx context.Context, userID stri
 		return []*NotificationItem{}, 0, nil
 	}
 
+	items, err := s.toNotificationItems(ctx, rows)
+	if err != nil {
+		return nil, 0, err
+	}
+	return items, total, nil
+}
+
+// 通知の行をレスポンスDTOに変換する。まとめた通知にはイベントのサンプルを設定する。
+func (s *notificationService) toNotificationItems(ctx context.Context, rows []*repository.NotificationRow) ([]*NotificationItem, error) {
+	groupedIDs := make([]string, 0, len(rows))
+	for _, r := range rows {
+		if r.GroupKey != nil {
+			groupedIDs = append(groupedIDs, r.ID)
+		}
+	}
+	events, err := s.notifRepo.ListEvents(ctx, groupedIDs)
+	if err != nil {
+		return nil, err
+	}
+	eventsByNotification := make(map[string][]repository.NotificationEventRow, len(groupedIDs))
+	for _, e := range events {
+		eventsByNotification[e.NotificationID] = append(eventsByNotification[e.NotificationID], e)
+	}
+
 	items := make([]*NotificationItem, 0, len(rows))
 	for _, r := range rows {
 		item := &NotificationItem{
 			ID:               r.ID,
 			NotificationType: r.NotificationType,
 			IsRead:           r.IsRead,
-			CreatedAt:        r.CreatedAt,
+			IsArchived:       r.IsArchived,
+			CreatedAt:        r.UpdatedAt,
 			MovieTitle:       r.MovieTitle,
+			Count:            r.EventCount,
+			Actors:           []ActorSummary{},
 		}
 
 		// actor
@@ -133,13 +291,133 @@ func (s *notificationService) ListNotifications(ctx context.Context, userID stri
 			}
 		}
 
+		if r.NotificationType == model.NotificationTypeTagUpdated {
+			applyTagUpdateDetails(item, r.Details)
+		}
+
+		if r.GroupKey != nil {
+			// 最後のアクターがブロック・ミュートされている場合もあるため、表示できるイベントから設定し直す
+			item.Actor = nil
+			applyNotificationEvents(item, eventsByNotification[r.ID])
+		} else {
+			if item.Actor != nil {
+				item.Actors = append(item.Actors, *item.Actor)
+				item.ActorCount = 1
+			}
+			if item.MovieTitle != nil {
+				item.MovieTitles = []string{*item.MovieTitle}
+			}
+		}
+
 		items = append(items, item)
 	}
 
-	return items, total, nil
+	return items, nil
+}
+
+// 指定ユーザーの通知・未読数が変化したことを受け取るチャネルを返す。
+// hub が未設定の場合は何も届かないチャネルを返す。
+func (s *notificationService) SubscribeStream(userID string) (<-chan struct{}, func()) {
+	if s.hub == nil {
+		return make(chan struct{}), func() {}
+	}
+
+	messages, unsubscribe := s.hub.Subscribe(notificationStreamTopic(userID))
+	signals := make(chan struct{}, 1)
+	go func() {
+		defer close(signals)
+		for range messages {
+			// 受け取り側が処理中の場合は、未処理のシグナルに合流させる
+			select {
+			case signals <- struct{}{}:
+			default:
+			}
+		}
+	}()
+	return signals, unsubscribe
+}
+
+// lastEventID 以降に作成・更新された通知を古い順に返す。
+func (s *notificationService) ListNotificationsSince(ctx context.Context, userID, lastEventID string) ([]NotificationStreamEntry, string, error) {
+	afterSeq, err := decodeStreamCursor(lastEventID)
+	if err != nil {
+		// アプリの時刻ではなく DB の採番を基準にし、以降に書き込まれた通知を取りこぼさないようにする
+		latest, err := s.notifRepo.LatestStreamSeq(ctx, userID)
+		if err != nil {
+			return nil, "", err
+		}
+		return []NotificationStreamEntry{}, encodeStreamCursor(latest), nil
+	}
+
+	rows, err := s.notifRepo.ListUpdatedSince(ctx, userID, afterSeq, notificationStreamPageSize)
+	if err != nil {
+		return nil, "", err
+	}
+	items, err := s.toNotificationItems(ctx, rows)
+	if err != nil {
+		return nil, "", err
+	}
+
+	entries := make([]NotificationStreamEntry, 0, len(items))
+	next := lastEventID
+	for i, item := range items {
+		next = encodeStreamCursor(rows[i].StreamSeq)
+		entries = append(entries, NotificationStreamEntry{EventID: next, Notification: item})
+	}
+	return entries, next, nil
+}
+
+// まとめた通知に、含まれるイベント（新しい順）からアクター・映画タイトルのサンプルを設定する。
+func applyNotificationEvents(item *NotificationItem, events []repository.NotificationEventRow) {
+	actorIDs := make(map[string]struct{})
+	titles := make(map[string]struct{})
+	for _, e := range events {
+		if _, ok := actorIDs[e.ActorUserID]; !ok {
+			actorIDs[e.ActorUserID] = struct{}{}
+			if len(item.Actors) < notificationGroupActorSampleSize {
+				item.Actors = append(item.Actors, ActorSummary{
+					ID:          e.ActorUserID,
+					DisplayID:   e.ActorDisplayID,
+					DisplayName: e.ActorDisplayName,
+					AvatarURL:   e.ActorAvatarURL,
+				})
+			}
+		}
+		if e.MovieTitle != nil {
+			if _, ok := titles[*e.MovieTitle]; !ok && len(item.MovieTitles) < notificationGroupMovieSampleSize {
+				titles[*e.MovieTitle] = struct{}{}
+				item.MovieTitles = append(item.MovieTitles, *e.MovieTitle)
+			}
+		}
+	}
+	item.ActorCount = len(actorIDs)
+	if len(item.Actors) > 0 {
+		actor := item.Actors[0]
+		item.Actor = &actor
+	}
+	if len(item.MovieTitles) > 0 {
+		item.MovieTitle = &item.MovieTitles[0]
+	}
+}
+
+// tag_updated の通知に変更内容を設定する。
+// 非公開になったタグは、受信者が知っている変更前のタイトルで表示する（非公開化と同時に変わった名前は見せない）。
+func applyTagUpdateDetails(item *NotificationItem, raw []byte) {
+	if len(raw) == 0 {
+		return
+	}
+	var details model.NotificationTagUpdateDetails
+	if err := json.Unmarshal(raw, &details); err != nil {
+		return
+	}
+	item.TagUpdate = &details
+	if details.MadePrivate && details.PreviousTitle != nil && item.Tag != nil {
+		item.Tag.Title = *details.PreviousTitle
+	}
 }
 
 // 未読通知数を取得する。
+// まとめた通知は1件として数える。
 func (s *notificationService) GetUnreadCount(ctx context.Context, userID string) (int64, error) {
 	return s.notifRepo.CountUnread(ctx, userID)
 }
@@ -153,22 +431,197 @@ func (s *notificationService) MarkAsRead(ctx context.Context, notificationID, us
 		}
 		return err
 	}
+	s.publishChanges(ctx, userID)
 	return nil
 }
 
 // 全通知を既読にする。
 func (s *notificationService) MarkAllAsRead(ctx context.Context, userID string) error {
-	return s.notifRepo.MarkAllAsRead(ctx, userID)
+	if err := s.notifRepo.MarkAllAsRead(ctx, userID); err != nil {
+		return err
+	}
+	s.publishChanges(ctx, userID)
+	return nil
+}
+
+// 指定通知をアーカイブする。
+func (s *notificationService) ArchiveNotification(ctx context.Context, notificationID, userID string) error {
+	return s.setArchived(ctx, notificationID, userID, true)
+}
+
+// 指定通知のアーカイブを解除する。
+func (s *notificationService) UnarchiveNotification(ctx context.Context, notificationID, userID string) error {
+	return s.setArchived(ctx, notificationID, userID, false)
+}
+
+// 指定通知のアーカイブ状態を切り替える。
+func (s *notificationService) setArchived(ctx context.Context, notificationID, userID string, archived bool) error {
+	err := s.notifRepo.SetArchived(ctx, notificationID, userID, archived)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrNotificationNotFound
+		}
+		return err
+	}
+	s.publishChanges(ctx, userID)
+	return nil
+}
+
+// 指定通知を削除する。
+func (s *notificationService) DeleteNotification(ctx context.Context, notificationID, userID string) error {
+	err := s.notifRepo.Delete(ctx, notificationID, userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrNotificationNotFound
+		}
+		return err
+	}
+	s.publishChanges(ctx, userID)
+	return nil
+}
+
+// 既読通知をすべて削除し、削除件数を返す。
+func (s *notificationService) DeleteReadNotifications(ctx context.Context, userID string) (int64, error) {
+	deleted, err := s.notifRepo.DeleteRead(ctx, userID)
+	if err != nil {
+		return 0, err
+	}
+	if deleted > 0 {
+		s.publishChanges(ctx, userID)
+	}
+	return deleted, nil
+}
+
+// 通知設定を取得する。
+// 設定していない種類は受け取る（true）として返す。
+func (s *notificationService) GetSettings(ctx context.Context, userID string) (*NotificationSettings, error) {
+	settings := &NotificationSettings{
+		Types:     make(map[string]bool, len(notificationSettingTypes)),
+		MutedTags: []NotificationMutedTagItem{},
+	}
+	for _, t := range notificationSettingTypes {
+		settings.Types[t] = true
+	}
+	if s.prefRepo == nil {
+		return settings, nil
+	}
+
+	prefs, err := s.prefRepo.ListByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range prefs {
+		if _, ok := settings.Types[p.NotificationType]; ok {
+			settings.Types[p.NotificationType] = p.Enabled
+		}
+	}
+
+	mutedTags, err := s.prefRepo.ListMutedTags(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	for _, m := range mutedTags {
+		settings.MutedTags = append(settings.MutedTags, NotificationMutedTagItem{
+			ID:      m.TagID,
+			Title:   m.TagTitle,
+			MutedAt: m.CreatedAt,
+		})
+	}
+	return settings, nil
+}
+
+// 通知設定を更新し、更新後の設定を返す。
+func (s *notificationService) UpdateSettings(ctx context.Context, userID string, in UpdateNotificationSettingsInput) (*NotificationSettings, error) {
+	if s.prefRepo == nil {
+		return nil, errors.New("notification preferences are not configured")
+	}
+
+	now := time.Now()
+	prefs := make([]model.NotificationPreference, 0, len(in.Types))
+	for t, enabled := range in.Types {
+		if !isNotificationSettingType(t) {
+			return nil, ErrInvalidNotificationType
+		}
+		prefs = append(prefs, model.NotificationPreference{
+			UserID:           userID,
+			NotificationType: t,
+			Enabled:          enabled,
+			UpdatedAt:        now,
+		})
+	}
+
+	for _, tagID := range slices.Concat(in.MuteTagIDs, in.UnmuteTagIDs) {
+		if _, err := uuid.Parse(tagID); err != nil {
+			return nil, ErrInvalidTagID
+		}
+	}
+
+	// ミュート対象のタグは、閲覧できるもののみ受け付ける
+	for _, tagID := range in.MuteTagIDs {
+		tag, err := s.tagRepo.FindByID(ctx, tagID)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil, ErrTagNotFound
+			}
+			return nil, err
+		}
+		if !tag.IsPublic && tag.UserID != userID {
+			return nil, ErrTagNotFound
+		}
+	}
+
+	// 種類ごとの設定とタグのミュートは、一部だけが反映されないようまとめて更新する
+	if err := s.prefRepo.UpdateSettings(ctx, userID, prefs, in.MuteTagIDs, in.UnmuteTagIDs); err != nil {
+		return nil, err
+	}
+
+	return s.GetSettings(ctx, userID)
+}
+
+// 通知設定で切り替えられる通知の種類かチェックする。
+func isNotificationSettingType(notificationType string) bool {
+	for _, t := range notificationSettingTypes {
+		if t == notificationType {
+			return true
+		}
+	}
+	return false
+}
+
+// 通知設定に従い、通知先を指定の種類の通知を受け取るユーザーに絞り込む。
+// tagID が指定された場合、そのタグをミュートしているユーザーも除外する。
+func (s *notificationService) filterRecipients(ctx context.Context, userIDs []string, notificationType string, tagID *string) ([]string, error) {
+	if s.prefRepo == nil || len(userIDs) == 0 {
+		return userIDs, nil
+	}
+	return s.prefRepo.FilterRecipients(ctx, userIDs, notificationType, tagID)
+}
+
+// 指定ユーザーが指定の種類の通知を受け取るかチェックする。
+func (s *notificationService) acceptsNotification(ctx context.Context, userID, notificationType string) (bool, error) {
+	recipients, err := s.filterRecipients(ctx, []string{userID}, notificationType, nil)
+	if err != nil {
+		return false, err
+	}
+	return len(recipients) > 0, nil
 }
 
 // タグに映画が追加された通知を生成する。
 // 通知先: タグオーナー + タグフォロワー - アクター自身
-func (s *notificationService) NotifyTagMovieAdded(ctx context.Context, tagID, tagMovieID, actorUserID string) error {
+func (s *notificationService) NotifyTagMovieAdded(ctx context.Context, sourceEventID, tagID, tagMovieID, actorUserID string) error {
 	tag, err := s.tagRepo.FindByID(ctx, tagID)
 	if err != nil {
 		return err
 	}
 
+	s.recordFeedEvent(ctx, &model.FeedEvent{
+		ActorUserID:   actorUserID,
+		EventType:     model.FeedEventTypeTagMovieAdded,
+		TagID:         &tagID,
+		TagMovieID:    &tagMovieID,
+		SourceEventID: optionalString(sourceEventID),
+	})
+
 	followerIDs, err := s.tagFollowerRepo.ListFollowerIDs(ctx, tagID)
 	if err != nil {
 		return err
@@ -182,73 +635,264 @@ func (s *notificationService) NotifyTagMovieAdded(ctx context.Context, tagID, ta
 	}
 	delete(recipientSet, actorUserID)
 
-	if len(recipientSet) == 0 {
+	recipientIDs := make([]string, 0, len(recipientSet))
+	for id := range recipientSet {
+		recipientIDs = append(recipientIDs, id)
+	}
+	recipientIDs, err = s.filterRecipients(ctx, recipientIDs, model.NotificationTypeTagMovieAdded, &tagID)
+	if err != nil {
+		return err
+	}
+	if len(recipientIDs) == 0 {
+		return nil
+	}
+
+	return s.createGroupedNotifications(ctx, sourceEventID, recipientIDs, model.NotificationTypeTagMovieAdded, actorUserID, &tagID, &tagMovieID)
+}
+
+// タグがフォローされた通知を生成する。
+// 通知先: タグオーナー - アクター自身
+func (s *notificationService) NotifyTagFollowed(ctx context.Context, sourceEventID, tagID, actorUserID string) error {
+	tag, err := s.tagRepo.FindByID(ctx, tagID)
+	if err != nil {
+		return err
+	}
+
+	s.recordFeedEvent(ctx, &model.FeedEvent{
+		ActorUserID:   actorUserID,
+		EventType:     model.FeedEventTypeTagFollowed,
+		TagID:         &tagID,
+		SourceEventID: optionalString(sourceEventID),
+	})
+
+	// タグオーナーが自分自身の場合は通知しない
+	if tag.UserID == actorUserID {
+		return nil
+	}
+	if ok, err := s.acceptsNotification(ctx, tag.UserID, model.NotificationTypeTagFollowed); err != nil || !ok {
+		return err
+	}
+
+	return s.createGroupedNotifications(ctx, sourceEventID, []string{tag.UserID}, model.NotificationTypeTagFollowed, actorUserID, &tagID, nil)
+}
+
+// タグにいいねされた通知を生成する。
+// 通知先: タグオーナー - アクター自身
+func (s *notificationService) NotifyTagLiked(ctx context.Context, sourceEventID, tagID, actorUserID string) error {
+	tag, err := s.tagRepo.FindByID(ctx, tagID)
+	if err != nil {
+		return err
+	}
+
+	s.recordFeedEvent(ctx, &model.FeedEvent{
+		ActorUserID:   actorUserID,
+		EventType:     model.FeedEventTypeTagLiked,
+		TagID:         &tagID,
+		SourceEventID: optionalString(sourceEventID),
+	})
+
+	// 自分のタグへのいいねは通知しない
+	if tag.UserID == actorUserID {
 		return nil
 	}
+	if ok, err := s.acceptsNotification(ctx, tag.UserID, model.NotificationTypeTagLiked); err != nil || !ok {
+		return err
+	}
+
+	return s.createGroupedNotifications(ctx, sourceEventID, []string{tag.UserID}, model.NotificationTypeTagLiked, actorUserID, &tagID, nil)
+}
+
+// タグの名前が変わった・非公開になった通知を生成する。
+// 通知先: タグフォロワー - アクター自身
+func (s *notificationService) NotifyTagUpdated(ctx context.Context, sourceEventID, tagID, actorUserID string, details model.NotificationTagUpdateDetails) error {
+	if details.PreviousTitle == nil && !details.MadePrivate {
+		return nil
+	}
+	detailsJSON, err := json.Marshal(details)
+	if err != nil {
+		return err
+	}
 
-	notifications := make([]*model.Notification, 0, len(recipientSet))
-	for recipientID := range recipientSet {
+	followerIDs, err := s.tagFollowerRepo.ListFollowerIDs(ctx, tagID)
+	if err != nil {
+		return err
+	}
+	recipientIDs := make([]string, 0, len(followerIDs))
+	for _, id := range followerIDs {
+		if id != actorUserID {
+			recipientIDs = append(recipientIDs, id)
+		}
+	}
+	recipientIDs, err = s.filterRecipients(ctx, recipientIDs, model.NotificationTypeTagUpdated, nil)
+	if err != nil {
+		return err
+	}
+	if len(recipientIDs) == 0 {
+		return nil
+	}
+
+	notifications := make([]*model.Notification, 0, len(recipientIDs))
+	for _, recipientID := range recipientIDs {
 		actor := actorUserID
 		tid := tagID
-		tmid := tagMovieID
 		notifications = append(notifications, &model.Notification{
 			RecipientUserID:  recipientID,
 			ActorUserID:      &actor,
-			NotificationType: model.NotificationTypeTagMovieAdded,
+			NotificationType: model.NotificationTypeTagUpdated,
 			TagID:            &tid,
-			TagMovieID:       &tmid,
+			Details:          detailsJSON,
+			SourceEventID:    optionalString(sourceEventID),
 		})
 	}
 
-	return s.notifRepo.CreateBatch(ctx, notifications)
+	if err := s.notifRepo.CreateBatch(ctx, notifications); err != nil {
+		return err
+	}
+	s.publishChanges(ctx, recipientIDs...)
+	return nil
 }
 
-// タグがフォローされた通知を生成する。
-// 通知先: タグオーナー - アクター自身
-func (s *notificationService) NotifyTagFollowed(ctx context.Context, tagID, actorUserID string) error {
+// 映画のメモでメンションされた通知を生成する。
+// 通知先: メンションされた display_id のユーザー - アクター自身 - 退会済みユーザー - アクターとブロック関係にあるユーザー
+// 非公開タグのメモの場合、タグを閲覧できるタグオーナーのみに通知する。
+func (s *notificationService) NotifyMentionedInNote(ctx context.Context, sourceEventID, tagID, tagMovieID, actorUserID string, displayIDs []string) error {
+	if len(displayIDs) == 0 || s.userRepo == nil {
+		return nil
+	}
 	tag, err := s.tagRepo.FindByID(ctx, tagID)
 	if err != nil {
 		return err
 	}
 
-	// タグオーナーが自分自身の場合は通知しない
-	if tag.UserID == actorUserID {
+	users, err := s.userRepo.ListActiveByDisplayIDs(ctx, displayIDs)
+	if err != nil {
+		return err
+	}
+	recipientIDs := make([]string, 0, len(users))
+	for _, u := range users {
+		if u.ID == actorUserID {
+			continue
+		}
+		if !tag.IsPublic && u.ID != tag.UserID {
+			continue
+		}
+		if s.userBlockRepo != nil {
+			blocked, err := s.userBlockRepo.IsBlockedEither(ctx, actorUserID, u.ID)
+			if err != nil {
+				return err
+			}
+			if blocked {
+				continue
+			}
+		}
+		recipientIDs = append(recipientIDs, u.ID)
+	}
+	recipientIDs, err = s.filterRecipients(ctx, recipientIDs, model.NotificationTypeMentionedInNote, nil)
+	if err != nil {
+		return err
+	}
+	if len(recipientIDs) == 0 {
 		return nil
 	}
 
-	actor := actorUserID
-	tid := tagID
-	notification := &model.Notification{
-		RecipientUserID:  tag.UserID,
-		ActorUserID:      &actor,
-		NotificationType: model.NotificationTypeTagFollowed,
-		TagID:            &tid,
+	notifications := make([]*model.Notification, 0, len(recipientIDs))
+	for _, recipientID := range recipientIDs {
+		actor := actorUserID
+		tid := tagID
+		tmid := tagMovieID
+		notifications = append(notifications, &model.Notification{
+			RecipientUserID:  recipientID,
+			ActorUserID:      &actor,
+			NotificationType: model.NotificationTypeMentionedInNote,
+			TagID:            &tid,
+			TagMovieID:       &tmid,
+			SourceEventID:    optionalString(sourceEventID),
+		})
 	}
 
-	return s.notifRepo.Create(ctx, notification)
+	if err := s.notifRepo.CreateBatch(ctx, notifications); err != nil {
+		return err
+	}
+	s.publishChanges(ctx, recipientIDs...)
+	return nil
 }
 
 // ユーザーがフォローされた通知を生成する。
 // 通知先: フォローされたユーザー - アクター自身
-func (s *notificationService) NotifyUserFollowed(ctx context.Context, followeeUserID, actorUserID string) error {
+func (s *notificationService) NotifyUserFollowed(ctx context.Context, sourceEventID, followeeUserID, actorUserID string) error {
 	// フォロー先が自分自身の場合は通知しない（通常ありえないが安全のため）
 	if followeeUserID == actorUserID {
 		return nil
 	}
 
+	s.recordFeedEvent(ctx, &model.FeedEvent{
+		ActorUserID:   actorUserID,
+		EventType:     model.FeedEventTypeUserFollowed,
+		TargetUserID:  &followeeUserID,
+		SourceEventID: optionalString(sourceEventID),
+	})
+
+	if ok, err := s.acceptsNotification(ctx, followeeUserID, model.NotificationTypeUserFollowed); err != nil || !ok {
+		return err
+	}
+
+	return s.createGroupedNotifications(ctx, sourceEventID, []string{followeeUserID}, model.NotificationTypeUserFollowed, actorUserID, nil, nil)
+}
+
+// 非公開アカウントにフォローリクエストが届いた通知を生成する。
+// 通知先: リクエスト先ユーザー、アクター: リクエスト送信者
+func (s *notificationService) NotifyFollowRequested(ctx context.Context, sourceEventID, targetUserID, requesterUserID string) error {
+	return s.createUserNotification(ctx, sourceEventID, targetUserID, requesterUserID, model.NotificationTypeFollowRequested)
+}
+
+// フォローリクエストが承認された通知を生成する。
+// 通知先: リクエスト送信者、アクター: 承認したユーザー
+func (s *notificationService) NotifyFollowRequestApproved(ctx context.Context, sourceEventID, requesterUserID, targetUserID string) error {
+	// 承認によりフォロー関係が成立するため、リクエスト送信者のフォローとしてフィードに記録する
+	if requesterUserID != targetUserID {
+		s.recordFeedEvent(ctx, &model.FeedEvent{
+			ActorUserID:   requesterUserID,
+			EventType:     model.FeedEventTypeUserFollowed,
+			TargetUserID:  &targetUserID,
+			SourceEventID: optionalString(sourceEventID),
+		})
+	}
+	return s.createUserNotification(ctx, sourceEventID, requesterUserID, targetUserID, model.NotificationTypeFollowRequestApproved)
+}
+
+// フォローリクエストが拒否された通知を生成する。
+// 通知先: リクエスト送信者、アクター: 拒否したユーザー
+func (s *notificationService) NotifyFollowRequestDenied(ctx context.Context, sourceEventID, requesterUserID, targetUserID string) error {
+	return s.createUserNotification(ctx, sourceEventID, requesterUserID, targetUserID, model.NotificationTypeFollowRequestDenied)
+}
+
+// ユーザー間の通知（タグに紐づかない通知）を1件生成する。
+func (s *notificationService) createUserNotification(ctx context.Context, sourceEventID, recipientUserID, actorUserID, notificationType string) error {
+	if recipientUserID == actorUserID {
+		return nil
+	}
+	if ok, err := s.acceptsNotification(ctx, recipientUserID, notificationType); err != nil || !ok {
+		return err
+	}
+
 	actor := actorUserID
 	notification := &model.Notification{
-		RecipientUserID:  followeeUserID,
+		RecipientUserID:  recipientUserID,
 		ActorUserID:      &actor,
-		NotificationType: model.NotificationTypeUserFollowed,
+		NotificationType: notificationType,
+		SourceEventID:    optionalString(sourceEventID),
 	}
 
-	return s.notifRepo.Create(ctx, notification)
+	if err := s.notifRepo.Create(ctx, notification); err != nil {
+		return err
+	}
+	s.publishChanges(ctx, recipientUserID)
+	return nil
 }
 
 // フォロー中ユーザーが新しいタグを作成した通知を生成する。
 // 通知先: アクターのフォロワー全員（公開タグのみ）
-func (s *notificationService) NotifyFollowingUserCreatedTag(ctx context.Context, tagID, actorUserID string) error {
+func (s *notificationService) NotifyFollowingUserCreatedTag(ctx context.Context, sourceEventID, tagID, actorUserID string) error {
 	// タグが公開かチェック
 	tag, err := s.tagRepo.FindByID(ctx, tagID)
 	if err != nil {
@@ -258,10 +902,21 @@ func (s *notificationService) NotifyFollowingUserCreatedTag(ctx context.Context,
 		return nil
 	}
 
+	s.recordFeedEvent(ctx, &model.FeedEvent{
+		ActorUserID:   actorUserID,
+		EventType:     model.FeedEventTypeTagCreated,
+		TagID:         &tagID,
+		SourceEventID: optionalString(sourceEventID),
+	})
+
 	followerIDs, err := s.userFollowerRepo.ListFollowerIDs(ctx, actorUserID)
 	if err != nil {
 		return err
 	}
+	followerIDs, err = s.filterRecipients(ctx, followerIDs, model.NotificationTypeFollowingUserCreatedTag, nil)
+	if err != nil {
+		return err
+	}
 
 	if len(followerIDs) == 0 {
 		return nil
@@ -276,8 +931,103 @@ func (s *notificationService) NotifyFollowingUserCreatedTag(ctx context.Context,
 			ActorUserID:      &actor,
 			NotificationType: model.NotificationTypeFollowingUserCreatedTag,
 			TagID:            &tid,
+			SourceEventID:    optionalString(sourceEventID),
+		})
+	}
+
+	if err := s.notifRepo.CreateBatch(ctx, notifications); err != nil {
+		return err
+	}
+	s.publishChanges(ctx, followerIDs...)
+	return nil
+}
+
+// 通知を生成し、同じ受信者・種類・タグの通知が時間幅内にあればそちらにまとめる。
+// tagMovieID はまとめた通知のイベントとして記録する（通知自体には持たせない）。
+func (s *notificationService) createGroupedNotifications(ctx context.Context, sourceEventID string, recipientIDs []string, notificationType, actorUserID string, tagID, tagMovieID *string) error {
+	if len(recipientIDs) == 0 {
+		return nil
+	}
+
+	now := time.Now()
+	groupKey := notificationGroupKey(notificationType, tagID)
+
+	// 並行してまとめる場合に行ロックの順序を揃えるため、受信者をソートする
+	sorted := append([]string(nil), recipientIDs...)
+	sort.Strings(sorted)
+
+	notifications := make([]*model.Notification, 0, len(sorted))
+	for _, recipientID := range sorted {
+		actor := actorUserID
+		key := groupKey
+		notifications = append(notifications, &model.Notification{
+			RecipientUserID:  recipientID,
+			ActorUserID:      &actor,
+			NotificationType: notificationType,
+			TagID:            tagID,
+			GroupKey:         &key,
+			EventCount:       1,
+			SourceEventID:    optionalString(sourceEventID),
+			CreatedAt:        now,
+			UpdatedAt:        now,
 		})
 	}
 
-	return s.notifRepo.CreateBatch(ctx, notifications)
+	err := s.notifRepo.CreateGroupedBatch(ctx, notifications, model.NotificationEvent{
+		ActorUserID:   actorUserID,
+		TagMovieID:    tagMovieID,
+		SourceEventID: optionalString(sourceEventID),
+		CreatedAt:     now,
+	}, now.Add(-notificationGroupWindow))
+	if err != nil {
+		return err
+	}
+	s.publishChanges(ctx, sorted...)
+	return nil
+}
+
+// 通知をまとめる単位のキーを返す。
+// 同じキーの通知のうち、最後のイベントが時間幅内にあるものにまとめる。
+func notificationGroupKey(notificationType string, tagID *string) string {
+	if tagID == nil {
+		return notificationType
+	}
+	return notificationType + ":" + *tagID
+}
+
+// 通知ストリームで使う、ユーザーごとのトピック名を返す。
+func notificationStreamTopic(userID string) string {
+	return "notifications:" + userID
+}
+
+// 通知・未読数が変化したことを各ユーザーのストリームに配信する。
+// 配信内容は変化の合図のみで、購読側が差分を取得し直す。
+// 配信に失敗しても通知の生成は継続する（ログに記録するのみ）。
+func (s *notificationService) publishChanges(ctx context.Context, userIDs ...string) {
+	if s.hub == nil {
+		return
+	}
+	for _, userID := range userIDs {
+		if err := s.hub.Publish(ctx, notificationStreamTopic(userID), nil); err != nil {
+			s.logger.Warn("service.publishChanges failed",
+				slog.String("user_id", userID),
+				slog.Any("error", err),
+			)
+		}
+	}
+}
+
+// 通知の元になったイベントをホームフィード用に記録する。
+// 記録に失敗しても通知の生成は継続する（ログに記録するのみ）。
+func (s *notificationService) recordFeedEvent(ctx context.Context, event *model.FeedEvent) {
+	if s.feedEventRepo == nil {
+		return
+	}
+	if err := s.feedEventRepo.Create(ctx, event); err != nil {
+		s.logger.Warn("service.recordFeedEvent failed",
+			slog.String("event_type", event.EventType),
+			slog.String("actor_user_id", event.ActorUserID),
+			slog.Any("error", err),
+		)
+	}
 }
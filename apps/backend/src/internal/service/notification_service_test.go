@@ -0,0 +1,653 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"cinetag-backend/src/internal/model"
+	"cinetag-backend/src/internal/pubsub"
+	"cinetag-backend/src/internal/repository"
+	"cinetag-backend/src/internal/testutil"
+
+	"gorm.io/gorm"
+)
+
+func TestNotificationService_NotifyTagMovieAdded_FiltersByPreferences(t *testing.T) {
+	t.Parallel()
+
+	tagRepo := &testutil.FakeTagRepository{
+		FindByIDFn: func(ctx context.Context, id string) (*model.Tag, error) {
+			return &model.Tag{ID: id, UserID: "owner", IsPublic: true}, nil
+		},
+	}
+	tagFollowerRepo := &testutil.FakeTagFollowerRepository{
+		ListFollowerIDsFn: func(ctx context.Context, tagID string) ([]string, error) {
+			return []string{"f1", "f2", "actor"}, nil
+		},
+	}
+
+	var gotType string
+	var gotTagID *string
+	var gotCandidates []string
+	prefRepo := &testutil.FakeNotificationPreferenceRepository{
+		FilterRecipientsFn: func(ctx context.Context, userIDs []string, notificationType string, tagID *string) ([]string, error) {
+			gotType = notificationType
+			gotTagID = tagID
+			gotCandidates = append([]string{}, userIDs...)
+			// f2 は受け取らない設定
+			out := []string{}
+			for _, id := range userIDs {
+				if id != "f2" {
+					out = append(out, id)
+				}
+			}
+			return out, nil
+		},
+	}
+
+	var created []*model.Notification
+	var event model.NotificationEvent
+	notifRepo := &testutil.FakeNotificationRepository{
+		CreateGroupedBatchFn: func(ctx context.Context, notifications []*model.Notification, e model.NotificationEvent, mergeSince time.Time) error {
+			created = notifications
+			event = e
+			return nil
+		},
+	}
+
+	svc := NewNotificationService(testutil.NewTestLogger(), notifRepo, prefRepo, tagRepo, tagFollowerRepo, nil, nil, nil, nil, nil)
+	if err := svc.NotifyTagMovieAdded(context.Background(), "event1", "tag1", "tm1", "actor"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if gotType != model.NotificationTypeTagMovieAdded {
+		t.Fatalf("expected type %q, got %q", model.NotificationTypeTagMovieAdded, gotType)
+	}
+	if gotTagID == nil || *gotTagID != "tag1" {
+		t.Fatalf("expected tag_id to be passed, got %v", gotTagID)
+	}
+	sort.Strings(gotCandidates)
+	if len(gotCandidates) != 3 || gotCandidates[0] != "f1" || gotCandidates[1] != "f2" || gotCandidates[2] != "owner" {
+		t.Fatalf("unexpected candidates: %v", gotCandidates)
+	}
+
+	recipients := make([]string, 0, len(created))
+	for _, n := range created {
+		recipients = append(recipients, n.RecipientUserID)
+	}
+	if len(recipients) != 2 || recipients[0] != "f1" || recipients[1] != "owner" {
+		t.Fatalf("unexpected recipients (expected sorted): %v", recipients)
+	}
+	if event.ActorUserID != "actor" || event.TagMovieID == nil || *event.TagMovieID != "tm1" {
+		t.Fatalf("unexpected event: %+v", event)
+	}
+	// 再配信で重複させないよう、元のイベントの ID を記録する
+	if event.SourceEventID == nil || *event.SourceEventID != "event1" || created[0].SourceEventID == nil || *created[0].SourceEventID != "event1" {
+		t.Fatalf("expected source event id to be recorded, got event=%v notification=%v", event.SourceEventID, created[0].SourceEventID)
+	}
+}
+
+func TestNotificationService_NotifyTagMovieAdded_GroupKey(t *testing.T) {
+	t.Parallel()
+
+	tagRepo := &testutil.FakeTagRepository{
+		FindByIDFn: func(ctx context.Context, id string) (*model.Tag, error) {
+			return &model.Tag{ID: id, UserID: "owner", IsPublic: true}, nil
+		},
+	}
+	var keys []string
+	notifRepo := &testutil.FakeNotificationRepository{
+		CreateGroupedBatchFn: func(ctx context.Context, notifications []*model.Notification, e model.NotificationEvent, mergeSince time.Time) error {
+			// 最後のイベントが時間幅内にある通知にまとめる
+			if got := e.CreatedAt.Sub(mergeSince); got != notificationGroupWindow {
+				t.Fatalf("expected merge window %v, got %v", notificationGroupWindow, got)
+			}
+			for _, n := range notifications {
+				if n.GroupKey == nil {
+					t.Fatalf("expected group_key to be set")
+				}
+				if n.TagMovieID != nil {
+					t.Fatalf("expected tag_movie_id to be recorded only on the event")
+				}
+				keys = append(keys, *n.GroupKey)
+			}
+			return nil
+		},
+	}
+
+	svc := NewNotificationService(testutil.NewTestLogger(), notifRepo, nil, tagRepo, &testutil.FakeTagFollowerRepository{}, nil, nil, nil, nil, nil)
+	for _, tm := range []string{"tm1", "tm2"} {
+		if err := svc.NotifyTagMovieAdded(context.Background(), "event1", "tag1", tm, "actor"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if len(keys) != 2 {
+		t.Fatalf("expected 2 notifications, got %d", len(keys))
+	}
+	// 時刻はキーに含めない（時間の区切りをまたいでもまとめられる）
+	for _, k := range keys {
+		if k != model.NotificationTypeTagMovieAdded+":tag1" {
+			t.Fatalf("unexpected group key: %s", k)
+		}
+	}
+
+	tagA, tagB := "a", "b"
+	if notificationGroupKey(model.NotificationTypeTagMovieAdded, &tagA) == notificationGroupKey(model.NotificationTypeTagMovieAdded, &tagB) {
+		t.Fatalf("expected different keys for different tags")
+	}
+	if notificationGroupKey(model.NotificationTypeTagMovieAdded, &tagA) == notificationGroupKey(model.NotificationTypeTagFollowed, &tagA) {
+		t.Fatalf("expected different keys for different types")
+	}
+}
+
+func TestNotificationService_ListNotifications_Grouped(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2025, 1, 10, 12, 0, 0, 0, time.UTC)
+	strp := func(s string) *string { return &s }
+	notifRepo := &testutil.FakeNotificationRepository{
+		ListByRecipientFn: func(ctx context.Context, userID string, page, pageSize int, unreadOnly, archived bool) ([]*repository.NotificationRow, int64, error) {
+			return []*repository.NotificationRow{
+				{
+					ID: "n1", NotificationType: model.NotificationTypeTagMovieAdded,
+					CreatedAt: now.Add(-30 * time.Minute), UpdatedAt: now,
+					GroupKey: strp("k1"), EventCount: 6,
+					// 最後のアクター u0 は受信者がブロックしているため、イベントには含まれない
+					ActorUserID: strp("u0"), ActorDisplayID: strp("u0"), ActorDisplayName: strp("U0"),
+					TagID: strp("tag1"), TagTitle: strp("Tag 1"),
+				},
+				{
+					ID: "n2", NotificationType: model.NotificationTypeFollowingUserCreatedTag,
+					CreatedAt: now.Add(-time.Hour), UpdatedAt: now.Add(-time.Hour), EventCount: 1,
+					ActorUserID: strp("u9"), ActorDisplayID: strp("u9"), ActorDisplayName: strp("U9"),
+				},
+			}, 2, nil
+		},
+		ListEventsFn: func(ctx context.Context, notificationIDs []string) ([]repository.NotificationEventRow, error) {
+			if len(notificationIDs) != 1 || notificationIDs[0] != "n1" {
+				t.Fatalf("expected events to be loaded for grouped notifications only, got %v", notificationIDs)
+			}
+			ev := func(actor, title string) repository.NotificationEventRow {
+				return repository.NotificationEventRow{NotificationID: "n1", ActorUserID: actor, ActorDisplayID: actor, ActorDisplayName: actor, MovieTitle: strp(title)}
+			}
+			return []repository.NotificationEventRow{
+				ev("u1", "M6"), ev("u2", "M5"), ev("u1", "M4"), ev("u3", "M3"), ev("u4", "M2"), ev("u5", "M1"),
+			}, nil
+		},
+	}
+
+	svc := NewNotificationService(testutil.NewTestLogger(), notifRepo, nil, nil, nil, nil, nil, nil, nil, nil)
+	items, total, err := svc.ListNotifications(context.Background(), "me", 1, 20, false, false)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if total != 2 || len(items) != 2 {
+		t.Fatalf("unexpected result: total=%d items=%d", total, len(items))
+	}
+
+	grouped := items[0]
+	if grouped.Count != 6 || grouped.ActorCount != 5 || !grouped.CreatedAt.Equal(now) {
+		t.Fatalf("unexpected grouped item: %+v", grouped)
+	}
+	if len(grouped.Actors) != 2 || grouped.Actors[0].ID != "u1" || grouped.Actors[1].ID != "u2" {
+		t.Fatalf("unexpected actors: %+v", grouped.Actors)
+	}
+	if grouped.Actor == nil || grouped.Actor.ID != "u1" {
+		t.Fatalf("expected the latest visible actor, got %+v", grouped.Actor)
+	}
+	if len(grouped.MovieTitles) != 3 || grouped.MovieTitles[0] != "M6" || grouped.MovieTitle == nil || *grouped.MovieTitle != "M6" {
+		t.Fatalf("unexpected movie titles: %+v", grouped.MovieTitles)
+	}
+
+	single := items[1]
+	if single.Count != 1 || single.ActorCount != 1 || len(single.Actors) != 1 || single.Actors[0].ID != "u9" {
+		t.Fatalf("unexpected single item: %+v", single)
+	}
+}
+
+func TestNotificationService_NotifyTagFollowed_SkipsDisabledOwner(t *testing.T) {
+	t.Parallel()
+
+	tagRepo := &testutil.FakeTagRepository{
+		FindByIDFn: func(ctx context.Context, id string) (*model.Tag, error) {
+			return &model.Tag{ID: id, UserID: "owner", IsPublic: true}, nil
+		},
+	}
+	prefRepo := &testutil.FakeNotificationPreferenceRepository{
+		FilterRecipientsFn: func(ctx context.Context, userIDs []string, notificationType string, tagID *string) ([]string, error) {
+			return []string{}, nil
+		},
+	}
+	createCalled := false
+	notifRepo := &testutil.FakeNotificationRepository{
+		CreateGroupedBatchFn: func(ctx context.Context, notifications []*model.Notification, e model.NotificationEvent, mergeSince time.Time) error {
+			createCalled = true
+			return nil
+		},
+	}
+
+	svc := NewNotificationService(testutil.NewTestLogger(), notifRepo, prefRepo, tagRepo, nil, nil, nil, nil, nil, nil)
+	if err := svc.NotifyTagFollowed(context.Background(), "event1", "tag1", "follower"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if createCalled {
+		t.Fatalf("expected notification not to be created")
+	}
+}
+
+func TestNotificationService_NotifyTagLiked(t *testing.T) {
+	t.Parallel()
+
+	tagRepo := &testutil.FakeTagRepository{
+		FindByIDFn: func(ctx context.Context, id string) (*model.Tag, error) {
+			return &model.Tag{ID: id, UserID: "owner", IsPublic: true}, nil
+		},
+	}
+	var created []*model.Notification
+	notifRepo := &testutil.FakeNotificationRepository{
+		CreateGroupedBatchFn: func(ctx context.Context, notifications []*model.Notification, e model.NotificationEvent, mergeSince time.Time) error {
+			created = append(created, notifications...)
+			return nil
+		},
+	}
+	var feedEvents []string
+	feedEventRepo := &testutil.FakeFeedEventRepository{
+		CreateFn: func(ctx context.Context, event *model.FeedEvent) error {
+			feedEvents = append(feedEvents, event.EventType)
+			return nil
+		},
+	}
+
+	svc := NewNotificationService(testutil.NewTestLogger(), notifRepo, nil, tagRepo, nil, nil, nil, nil, feedEventRepo, nil)
+	if err := svc.NotifyTagLiked(context.Background(), "event1", "tag1", "liker"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// 自分のタグへのいいねはフィードに記録するが通知しない
+	if err := svc.NotifyTagLiked(context.Background(), "event1", "tag1", "owner"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(created) != 1 || created[0].RecipientUserID != "owner" || created[0].NotificationType != model.NotificationTypeTagLiked {
+		t.Fatalf("unexpected notifications: %+v", created)
+	}
+	if created[0].GroupKey == nil || *created[0].GroupKey != "tag_liked:tag1" {
+		t.Fatalf("expected likes to be grouped by tag, got %v", created[0].GroupKey)
+	}
+	if len(feedEvents) != 2 {
+		t.Fatalf("expected both likes to be recorded in the feed, got %v", feedEvents)
+	}
+}
+
+func TestNotificationService_NotifyTagUpdated(t *testing.T) {
+	t.Parallel()
+
+	tagFollowerRepo := &testutil.FakeTagFollowerRepository{
+		ListFollowerIDsFn: func(ctx context.Context, tagID string) ([]string, error) {
+			return []string{"f1", "owner", "f2"}, nil
+		},
+	}
+	var gotType string
+	prefRepo := &testutil.FakeNotificationPreferenceRepository{
+		FilterRecipientsFn: func(ctx context.Context, userIDs []string, notificationType string, tagID *string) ([]string, error) {
+			gotType = notificationType
+			return userIDs, nil
+		},
+	}
+	var created []*model.Notification
+	notifRepo := &testutil.FakeNotificationRepository{
+		CreateBatchFn: func(ctx context.Context, notifications []*model.Notification) error {
+			created = notifications
+			return nil
+		},
+	}
+
+	svc := NewNotificationService(testutil.NewTestLogger(), notifRepo, prefRepo, nil, tagFollowerRepo, nil, nil, nil, nil, nil)
+	previous := "Old"
+	err := svc.NotifyTagUpdated(context.Background(), "event1", "tag1", "owner", model.NotificationTagUpdateDetails{PreviousTitle: &previous, MadePrivate: true})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if gotType != model.NotificationTypeTagUpdated {
+		t.Fatalf("unexpected notification type: %s", gotType)
+	}
+	if len(created) != 2 || created[0].RecipientUserID != "f1" || created[1].RecipientUserID != "f2" {
+		t.Fatalf("expected followers except the owner, got %+v", created)
+	}
+	if string(created[0].Details) != `{"previous_title":"Old","made_private":true}` {
+		t.Fatalf("unexpected details: %s", created[0].Details)
+	}
+}
+
+func TestNotificationService_ListNotifications_TagUpdated(t *testing.T) {
+	t.Parallel()
+
+	strp := func(s string) *string { return &s }
+	notifRepo := &testutil.FakeNotificationRepository{
+		ListByRecipientFn: func(ctx context.Context, userID string, page, pageSize int, unreadOnly, archived bool) ([]*repository.NotificationRow, int64, error) {
+			return []*repository.NotificationRow{{
+				ID: "n1", NotificationType: model.NotificationTypeTagUpdated, EventCount: 1,
+				TagID: strp("tag1"), TagTitle: strp("Secret"),
+				Details: []byte(`{"previous_title":"Old","made_private":true}`),
+			}}, 1, nil
+		},
+	}
+
+	svc := NewNotificationService(testutil.NewTestLogger(), notifRepo, nil, nil, nil, nil, nil, nil, nil, nil)
+	items, _, err := svc.ListNotifications(context.Background(), "me", 1, 20, false, false)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	item := items[0]
+	if item.TagUpdate == nil || !item.TagUpdate.MadePrivate || item.TagUpdate.PreviousTitle == nil || *item.TagUpdate.PreviousTitle != "Old" {
+		t.Fatalf("unexpected tag_update: %+v", item.TagUpdate)
+	}
+	// 非公開になったタグは、非公開化と同時に変わった名前を見せない
+	if item.Tag == nil || item.Tag.Title != "Old" {
+		t.Fatalf("expected previous title to be shown, got %+v", item.Tag)
+	}
+}
+
+func TestNotificationService_NotifyMentionedInNote(t *testing.T) {
+	t.Parallel()
+
+	users := map[string]*model.User{
+		"actor":   {ID: "actor", DisplayID: "actor"},
+		"owner":   {ID: "owner", DisplayID: "owner"},
+		"alice":   {ID: "alice", DisplayID: "alice"},
+		"blocked": {ID: "blocked", DisplayID: "blocked"},
+	}
+	userRepo := &fakeUserRepo{
+		ListActiveByDisplayIDsFn: func(ctx context.Context, displayIDs []string) ([]*model.User, error) {
+			out := []*model.User{}
+			for _, id := range displayIDs {
+				if u, ok := users[id]; ok {
+					out = append(out, u)
+				}
+			}
+			return out, nil
+		},
+	}
+	userBlockRepo := &testutil.FakeUserBlockRepository{
+		IsBlockedEitherFn: func(ctx context.Context, userA, userB string) (bool, error) {
+			return userA == "actor" && userB == "blocked", nil
+		},
+	}
+	mentioned := []string{"actor", "owner", "alice", "blocked", "unknown"}
+
+	cases := []struct {
+		name     string
+		isPublic bool
+		want     []string
+	}{
+		{name: "公開タグはアクター自身・ブロック関係のユーザー以外に通知する", isPublic: true, want: []string{"owner", "alice"}},
+		{name: "非公開タグはタグオーナーのみに通知する", isPublic: false, want: []string{"owner"}},
+	}
+	for _, tc := range cases {
+		tagRepo := &testutil.FakeTagRepository{
+			FindByIDFn: func(ctx context.Context, id string) (*model.Tag, error) {
+				return &model.Tag{ID: id, UserID: "owner", IsPublic: tc.isPublic}, nil
+			},
+		}
+		var created []*model.Notification
+		notifRepo := &testutil.FakeNotificationRepository{
+			CreateBatchFn: func(ctx context.Context, notifications []*model.Notification) error {
+				created = notifications
+				return nil
+			},
+		}
+
+		svc := NewNotificationService(testutil.NewTestLogger(), notifRepo, nil, tagRepo, nil, nil, userRepo, userBlockRepo, nil, nil)
+		if err := svc.NotifyMentionedInNote(context.Background(), "event1", "tag1", "tm1", "actor", mentioned); err != nil {
+			t.Fatalf("%s: unexpected error: %v", tc.name, err)
+		}
+
+		got := make([]string, 0, len(created))
+		for _, n := range created {
+			if n.NotificationType != model.NotificationTypeMentionedInNote || n.TagMovieID == nil || *n.TagMovieID != "tm1" {
+				t.Fatalf("%s: unexpected notification: %+v", tc.name, n)
+			}
+			got = append(got, n.RecipientUserID)
+		}
+		if strings.Join(got, ",") != strings.Join(tc.want, ",") {
+			t.Fatalf("%s: expected recipients %v, got %v", tc.name, tc.want, got)
+		}
+	}
+}
+
+func TestNotificationService_GetSettings(t *testing.T) {
+	t.Parallel()
+
+	mutedAt := time.Date(2025, 1, 10, 12, 0, 0, 0, time.UTC)
+	prefRepo := &testutil.FakeNotificationPreferenceRepository{
+		ListByUserFn: func(ctx context.Context, userID string) ([]model.NotificationPreference, error) {
+			return []model.NotificationPreference{
+				{UserID: userID, NotificationType: model.NotificationTypeTagFollowed, Enabled: false},
+			}, nil
+		},
+		ListMutedTagsFn: func(ctx context.Context, userID string) ([]repository.NotificationMutedTagRow, error) {
+			return []repository.NotificationMutedTagRow{{TagID: "tag1", TagTitle: "Tag 1", CreatedAt: mutedAt}}, nil
+		},
+	}
+
+	svc := NewNotificationService(testutil.NewTestLogger(), nil, prefRepo, nil, nil, nil, nil, nil, nil, nil)
+	settings, err := svc.GetSettings(context.Background(), "u1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(settings.Types) != len(notificationSettingTypes) {
+		t.Fatalf("expected %d types, got %d", len(notificationSettingTypes), len(settings.Types))
+	}
+	if settings.Types[model.NotificationTypeTagFollowed] {
+		t.Fatalf("expected tag_followed to be disabled")
+	}
+	if !settings.Types[model.NotificationTypeTagMovieAdded] {
+		t.Fatalf("expected unset type to default to enabled")
+	}
+	if len(settings.MutedTags) != 1 || settings.MutedTags[0].ID != "tag1" || !settings.MutedTags[0].MutedAt.Equal(mutedAt) {
+		t.Fatalf("unexpected muted tags: %+v", settings.MutedTags)
+	}
+}
+
+func TestNotificationService_UpdateSettings(t *testing.T) {
+	t.Parallel()
+
+	const (
+		publicTagID  = "11111111-1111-1111-1111-111111111111"
+		privateTagID = "22222222-2222-2222-2222-222222222222"
+		missingTagID = "33333333-3333-3333-3333-333333333333"
+		oldTagID     = "44444444-4444-4444-4444-444444444444"
+	)
+	tagRepo := &testutil.FakeTagRepository{
+		FindByIDFn: func(ctx context.Context, id string) (*model.Tag, error) {
+			switch id {
+			case publicTagID:
+				return &model.Tag{ID: id, UserID: "other", IsPublic: true}, nil
+			case privateTagID:
+				return &model.Tag{ID: id, UserID: "other", IsPublic: false}, nil
+			}
+			return nil, gorm.ErrRecordNotFound
+		},
+	}
+
+	t.Run("種類ごとの設定とタグのミュートをまとめて更新する", func(t *testing.T) {
+		t.Parallel()
+
+		var calls int
+		var upserted []model.NotificationPreference
+		var muted, unmuted []string
+		prefRepo := &testutil.FakeNotificationPreferenceRepository{
+			UpdateSettingsFn: func(ctx context.Context, userID string, prefs []model.NotificationPreference, muteTagIDs, unmuteTagIDs []string) error {
+				calls++
+				upserted, muted, unmuted = prefs, muteTagIDs, unmuteTagIDs
+				return nil
+			},
+		}
+
+		svc := NewNotificationService(testutil.NewTestLogger(), nil, prefRepo, tagRepo, nil, nil, nil, nil, nil, nil)
+		_, err := svc.UpdateSettings(context.Background(), "u1", UpdateNotificationSettingsInput{
+			Types:        map[string]bool{model.NotificationTypeUserFollowed: false},
+			MuteTagIDs:   []string{publicTagID},
+			UnmuteTagIDs: []string{oldTagID},
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if calls != 1 {
+			t.Fatalf("expected UpdateSettings to be called once, got %d", calls)
+		}
+		if len(upserted) != 1 || upserted[0].UserID != "u1" || upserted[0].NotificationType != model.NotificationTypeUserFollowed || upserted[0].Enabled {
+			t.Fatalf("unexpected upserted: %+v", upserted)
+		}
+		if len(muted) != 1 || muted[0] != publicTagID {
+			t.Fatalf("unexpected muted: %v", muted)
+		}
+		if len(unmuted) != 1 || unmuted[0] != oldTagID {
+			t.Fatalf("unexpected unmuted: %v", unmuted)
+		}
+	})
+
+	t.Run("不明な種類はエラー", func(t *testing.T) {
+		t.Parallel()
+
+		svc := NewNotificationService(testutil.NewTestLogger(), nil, &testutil.FakeNotificationPreferenceRepository{}, tagRepo, nil, nil, nil, nil, nil, nil)
+		_, err := svc.UpdateSettings(context.Background(), "u1", UpdateNotificationSettingsInput{
+			Types: map[string]bool{"unknown": false},
+		})
+		if !errors.Is(err, ErrInvalidNotificationType) {
+			t.Fatalf("expected ErrInvalidNotificationType, got %v", err)
+		}
+	})
+
+	t.Run("UUID 形式でないタグIDはエラー", func(t *testing.T) {
+		t.Parallel()
+
+		prefRepo := &testutil.FakeNotificationPreferenceRepository{
+			UpdateSettingsFn: func(ctx context.Context, userID string, prefs []model.NotificationPreference, muteTagIDs, unmuteTagIDs []string) error {
+				t.Fatalf("UpdateSettings should not be called")
+				return nil
+			},
+		}
+		svc := NewNotificationService(testutil.NewTestLogger(), nil, prefRepo, tagRepo, nil, nil, nil, nil, nil, nil)
+		for _, in := range []UpdateNotificationSettingsInput{
+			{MuteTagIDs: []string{"not-a-uuid"}},
+			{UnmuteTagIDs: []string{"not-a-uuid"}},
+		} {
+			if _, err := svc.UpdateSettings(context.Background(), "u1", in); !errors.Is(err, ErrInvalidTagID) {
+				t.Fatalf("expected ErrInvalidTagID, got %v", err)
+			}
+		}
+	})
+
+	t.Run("存在しないタグ・他人の非公開タグはミュートできない", func(t *testing.T) {
+		t.Parallel()
+
+		svc := NewNotificationService(testutil.NewTestLogger(), nil, &testutil.FakeNotificationPreferenceRepository{}, tagRepo, nil, nil, nil, nil, nil, nil)
+		for _, tagID := range []string{missingTagID, privateTagID} {
+			_, err := svc.UpdateSettings(context.Background(), "u1", UpdateNotificationSettingsInput{
+				MuteTagIDs: []string{tagID},
+			})
+			if !errors.Is(err, ErrTagNotFound) {
+				t.Fatalf("tag %s: expected ErrTagNotFound, got %v", tagID, err)
+			}
+		}
+	})
+}
+
+func TestNotificationService_SubscribeStream_ReceivesChanges(t *testing.T) {
+	t.Parallel()
+
+	svc := NewNotificationService(testutil.NewTestLogger(), &testutil.FakeNotificationRepository{}, nil, nil, nil, nil, nil, nil, nil, pubsub.NewMemoryHub())
+
+	signals, unsubscribe := svc.SubscribeStream("me")
+	other, unsubscribeOther := svc.SubscribeStream("other")
+	defer unsubscribeOther()
+
+	if err := svc.MarkAllAsRead(context.Background(), "me"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	select {
+	case <-signals:
+	case <-time.After(time.Second):
+		t.Fatal("expected a change signal")
+	}
+	select {
+	case <-other:
+		t.Fatal("unexpected signal for another user")
+	default:
+	}
+
+	unsubscribe()
+	select {
+	case _, ok := <-signals:
+		if ok {
+			t.Fatal("expected channel to be closed after unsubscribe")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expected channel to be closed after unsubscribe")
+	}
+}
+
+func TestNotificationService_ListNotificationsSince(t *testing.T) {
+	t.Parallel()
+
+	t.Run("不正なカーソルは DB の最新の通知から再開する", func(t *testing.T) {
+		t.Parallel()
+
+		notifRepo := &testutil.FakeNotificationRepository{
+			ListUpdatedSinceFn: func(ctx context.Context, userID string, afterSeq int64, limit int) ([]*repository.NotificationRow, error) {
+				t.Fatal("unexpected ListUpdatedSince call")
+				return nil, nil
+			},
+			LatestStreamSeqFn: func(ctx context.Context, userID string) (int64, error) {
+				return 42, nil
+			},
+		}
+		svc := NewNotificationService(testutil.NewTestLogger(), notifRepo, nil, nil, nil, nil, nil, nil, nil, nil)
+
+		for _, cursor := range []string{"", "broken", "-1"} {
+			entries, next, err := svc.ListNotificationsSince(context.Background(), "me", cursor)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(entries) != 0 {
+				t.Fatalf("expected no entries, got %d", len(entries))
+			}
+			if next != "42" {
+				t.Fatalf("expected the latest stream_seq as the cursor, got %q", next)
+			}
+		}
+	})
+
+	t.Run("カーソル以降の通知を返す", func(t *testing.T) {
+		t.Parallel()
+
+		rowID := "33333333-3333-3333-3333-333333333333"
+		notifRepo := &testutil.FakeNotificationRepository{
+			ListUpdatedSinceFn: func(ctx context.Context, userID string, afterSeq int64, limit int) ([]*repository.NotificationRow, error) {
+				if afterSeq != 10 {
+					t.Fatalf("unexpected cursor: %d", afterSeq)
+				}
+				return []*repository.NotificationRow{
+					{ID: rowID, NotificationType: model.NotificationTypeUserFollowed, StreamSeq: 12, EventCount: 1},
+				}, nil
+			},
+		}
+		svc := NewNotificationService(testutil.NewTestLogger(), notifRepo, nil, nil, nil, nil, nil, nil, nil, nil)
+
+		entries, next, err := svc.ListNotificationsSince(context.Background(), "me", "10")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(entries) != 1 || entries[0].Notification.ID != rowID {
+			t.Fatalf("unexpected entries: %+v", entries)
+		}
+		if next != "12" || entries[0].EventID != "12" {
+			t.Fatalf("unexpected cursor: next=%q entry=%q", next, entries[0].EventID)
+		}
+	})
+}
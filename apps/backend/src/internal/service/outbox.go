@@ -0,0 +1,72 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+
+	"cinetag-backend/src/internal/model"
+	"cinetag-backend/src/internal/repository"
+)
+
+// 通知イベントをアウトボックスに書き込む。
+// 状態変更と同じトランザクションのリポジトリを渡すことで、状態変更が確定した場合のみ通知が配信される。
+// outboxRepo が nil の場合（通知を使わない構成）は何もしない。
+func enqueueNotificationEvent(ctx context.Context, outboxRepo repository.OutboxRepository, eventType string, payload model.NotificationEventPayload) error {
+	if outboxRepo == nil {
+		return nil
+	}
+	event, err := newNotificationOutboxEvent(eventType, payload)
+	if err != nil {
+		return err
+	}
+	return outboxRepo.Enqueue(ctx, event)
+}
+
+// 通知に関するアウトボックスイベントを生成する。
+// idempotency_key はイベントの種類と関係するIDから組み立てるため、配信前に同じイベントが積まれても1件にまとまる。
+func newNotificationOutboxEvent(eventType string, payload model.NotificationEventPayload) (*model.OutboxEvent, error) {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	keyParts := []string{eventType}
+	for _, id := range []string{payload.TagID, payload.TagMovieID, payload.RecipientUserID, payload.ActorUserID} {
+		if id != "" {
+			keyParts = append(keyParts, id)
+		}
+	}
+	// タグの変更は内容ごとに分け、名前の変更の後に非公開にした場合なども両方配信する
+	if payload.PreviousTitle != nil {
+		keyParts = append(keyParts, "renamed")
+	}
+	if payload.MadePrivate {
+		keyParts = append(keyParts, "private")
+	}
+
+	return &model.OutboxEvent{
+		EventType:      eventType,
+		Payload:        body,
+		IdempotencyKey: strings.Join(keyParts, ":"),
+		Status:         model.OutboxStatusPending,
+	}, nil
+}
+
+// 個人データのエクスポートを生成するイベントをアウトボックスに書き込む。
+// outboxRepo が nil の場合（ユニットテスト）は何もしない。
+func enqueueUserDataExportEvent(ctx context.Context, outboxRepo repository.OutboxRepository, exportID string) error {
+	if outboxRepo == nil {
+		return nil
+	}
+	body, err := json.Marshal(model.UserDataExportEventPayload{ExportID: exportID})
+	if err != nil {
+		return err
+	}
+	return outboxRepo.Enqueue(ctx, &model.OutboxEvent{
+		EventType:      model.OutboxEventTypeUserDataExportRequested,
+		Payload:        body,
+		IdempotencyKey: model.OutboxEventTypeUserDataExportRequested + ":" + exportID,
+		Status:         model.OutboxStatusPending,
+	})
+}
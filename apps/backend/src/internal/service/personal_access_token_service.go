@@ -0,0 +1,224 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"log/slog"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"cinetag-backend/src/internal/model"
+	"cinetag-backend/src/internal/repository"
+
+	"gorm.io/gorm"
+)
+
+const (
+	// パーソナルアクセストークンの接頭辞（Authorization: Bearer cnt_... で Clerk の JWT と見分ける）。
+	AccessTokenPrefix = "cnt_"
+	// ユーザーごとに発行できる（期限切れでない）トークンの上限。
+	maxAccessTokensPerUser = 20
+	// トークン名の最大文字数。
+	maxAccessTokenNameLength = 100
+	// 有効期限の既定値と上限（日数）。
+	defaultAccessTokenExpiresInDays = 30
+	maxAccessTokenExpiresInDays     = 365
+	// 一覧で見分けるために保存するトークンの先頭部分の長さ（接頭辞を含む）。
+	accessTokenDisplayPrefixLength = len(AccessTokenPrefix) + 8
+	// 最終利用日時を更新する最小間隔（リクエストごとに書き込まないため）。
+	accessTokenLastUsedInterval = time.Minute
+)
+
+var (
+	ErrAccessTokenNotFound      = errors.New("access token not found")                       // トークンが存在しない（他のユーザーのトークンを含む）
+	ErrInvalidAccessTokenName   = errors.New("name must be between 1 and 100 characters")    // トークン名が不正
+	ErrInvalidAccessTokenScopes = errors.New("scopes must contain at least one valid scope") // 不明なスコープ・スコープの指定なし
+	ErrInvalidAccessTokenExpiry = errors.New("expires_in_days must be between 1 and 365")    // 有効期限が不正
+	ErrAccessTokenLimitExceeded = errors.New("access token limit exceeded")                  // 発行数の上限に達した
+	ErrInvalidAccessToken       = errors.New("invalid access token")                         // 認証できないトークン（不明・期限切れ）
+)
+
+// トークン作成の入力。
+type CreateAccessTokenInput struct {
+	Name          string
+	Scopes        []string
+	ExpiresInDays *int // nil の場合は30日
+}
+
+// 作成したトークン。トークン本体は作成時のみ返す。
+type CreatedAccessToken struct {
+	*model.PersonalAccessToken
+	Token string `json:"token"`
+}
+
+// パーソナルアクセストークンに関するユースケースを表すインターフェース。
+type PersonalAccessTokenService interface {
+	// トークンを発行する。
+	CreateAccessToken(ctx context.Context, userID string, in CreateAccessTokenInput) (*CreatedAccessToken, error)
+	// 自分のトークンを新しい順で返す（期限切れのものを含む）。
+	ListAccessTokens(ctx context.Context, userID string) ([]*model.PersonalAccessToken, error)
+	// 自分のトークンを失効（削除）する。
+	RevokeAccessToken(ctx context.Context, tokenID, userID string) error
+	// Authorization ヘッダーのトークンを照合し、トークンと持ち主のユーザーを返す。
+	// 不明・期限切れのトークンの場合は ErrInvalidAccessToken を返す（持ち主が退会状態かどうかは呼び出し元で判定する）。
+	Authenticate(ctx context.Context, rawToken string) (*model.PersonalAccessToken, *model.User, error)
+}
+
+type personalAccessTokenService struct {
+	logger    *slog.Logger
+	tokenRepo repository.PersonalAccessTokenRepository
+	userRepo  repository.UserRepository
+	now       func() time.Time
+}
+
+// PersonalAccessTokenService を生成する。
+func NewPersonalAccessTokenService(logger *slog.Logger, tokenRepo repository.PersonalAccessTokenRepository, userRepo repository.UserRepository) PersonalAccessTokenService {
+	return &personalAccessTokenService{
+		logger:    logger,
+		tokenRepo: tokenRepo,
+		userRepo:  userRepo,
+		now:       time.Now,
+	}
+}
+
+// トークンを発行する。
+func (s *personalAccessTokenService) CreateAccessToken(ctx context.Context, userID string, in CreateAccessTokenInput) (*CreatedAccessToken, error) {
+	name := strings.TrimSpace(in.Name)
+	if name == "" || utf8.RuneCountInString(name) > maxAccessTokenNameLength {
+		return nil, ErrInvalidAccessTokenName
+	}
+	scopes, err := normalizeAccessTokenScopes(in.Scopes)
+	if err != nil {
+		return nil, err
+	}
+	days := defaultAccessTokenExpiresInDays
+	if in.ExpiresInDays != nil {
+		days = *in.ExpiresInDays
+	}
+	if days < 1 || days > maxAccessTokenExpiresInDays {
+		return nil, ErrInvalidAccessTokenExpiry
+	}
+
+	raw, err := newAccessToken()
+	if err != nil {
+		return nil, err
+	}
+	now := s.now()
+	token := &model.PersonalAccessToken{
+		UserID:      userID,
+		Name:        name,
+		TokenHash:   hashAccessToken(raw),
+		TokenPrefix: raw[:accessTokenDisplayPrefixLength],
+		Scopes:      scopes,
+		ExpiresAt:   now.Add(time.Duration(days) * 24 * time.Hour),
+	}
+	if err := s.tokenRepo.Create(ctx, token, maxAccessTokensPerUser, now); err != nil {
+		if errors.Is(err, repository.ErrAccessTokenLimitReached) {
+			return nil, ErrAccessTokenLimitExceeded
+		}
+		return nil, err
+	}
+	return &CreatedAccessToken{PersonalAccessToken: token, Token: raw}, nil
+}
+
+// 自分のトークンを返す。
+func (s *personalAccessTokenService) ListAccessTokens(ctx context.Context, userID string) ([]*model.PersonalAccessToken, error) {
+	return s.tokenRepo.ListByUser(ctx, userID)
+}
+
+// 自分のトークンを失効する。
+func (s *personalAccessTokenService) RevokeAccessToken(ctx context.Context, tokenID, userID string) error {
+	if err := s.tokenRepo.Delete(ctx, tokenID, userID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrAccessTokenNotFound
+		}
+		return err
+	}
+	return nil
+}
+
+// トークンを照合する。
+func (s *personalAccessTokenService) Authenticate(ctx context.Context, rawToken string) (*model.PersonalAccessToken, *model.User, error) {
+	rawToken = strings.TrimSpace(rawToken)
+	if !strings.HasPrefix(rawToken, AccessTokenPrefix) || len(rawToken) <= accessTokenDisplayPrefixLength {
+		return nil, nil, ErrInvalidAccessToken
+	}
+
+	token, err := s.tokenRepo.FindByHash(ctx, hashAccessToken(rawToken))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil, ErrInvalidAccessToken
+		}
+		return nil, nil, err
+	}
+	now := s.now()
+	if !now.Before(token.ExpiresAt) {
+		return nil, nil, ErrInvalidAccessToken
+	}
+
+	user, err := s.userRepo.FindByID(ctx, token.UserID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil, ErrInvalidAccessToken
+		}
+		return nil, nil, err
+	}
+	if user.PurgedAt != nil {
+		return nil, nil, ErrInvalidAccessToken
+	}
+
+	// 最終利用日時の更新に失敗しても認証は通す
+	if token.LastUsedAt == nil || now.Sub(*token.LastUsedAt) >= accessTokenLastUsedInterval {
+		if err := s.tokenRepo.TouchLastUsed(ctx, token.ID, now); err != nil {
+			s.logger.Warn("service.Authenticate: failed to update access token last_used_at",
+				slog.String("token_id", token.ID),
+				slog.Any("error", err),
+			)
+		} else {
+			token.LastUsedAt = &now
+		}
+	}
+	return token, user, nil
+}
+
+// スコープを検証し、重複を除いて定義順に並べる。
+func normalizeAccessTokenScopes(scopes []string) ([]string, error) {
+	if len(scopes) == 0 {
+		return nil, ErrInvalidAccessTokenScopes
+	}
+	requested := make(map[string]bool, len(scopes))
+	for _, s := range scopes {
+		requested[strings.TrimSpace(s)] = true
+	}
+	normalized := make([]string, 0, len(requested))
+	for _, s := range model.AccessTokenScopes {
+		if requested[s] {
+			normalized = append(normalized, s)
+			delete(requested, s)
+		}
+	}
+	if len(requested) > 0 {
+		return nil, ErrInvalidAccessTokenScopes
+	}
+	return normalized, nil
+}
+
+// ランダムなトークンを生成する。
+func newAccessToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return AccessTokenPrefix + hex.EncodeToString(b), nil
+}
+
+// トークンのハッシュ（SHA-256 の16進表現）を返す。
+// トークンは十分な長さの乱数のため、パスワードのような低速なハッシュは使わない。
+func hashAccessToken(raw string) string {
+	sum := sha256.Sum256([]byte(raw))
+	return hex.EncodeToString(sum[:])
+}
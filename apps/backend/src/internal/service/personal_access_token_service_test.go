@@ -0,0 +1,239 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"cinetag-backend/src/internal/model"
+	"cinetag-backend/src/internal/repository"
+	"cinetag-backend/src/internal/testutil"
+
+	"gorm.io/gorm"
+)
+
+func newTestPersonalAccessTokenService(tokenRepo *testutil.FakePersonalAccessTokenRepository, userRepo *fakeUserRepo, now time.Time) PersonalAccessTokenService {
+	if userRepo == nil {
+		userRepo = &fakeUserRepo{}
+	}
+	svc := NewPersonalAccessTokenService(testutil.NewTestLogger(), tokenRepo, userRepo).(*personalAccessTokenService)
+	svc.now = func() time.Time { return now }
+	return svc
+}
+
+func TestPersonalAccessTokenService_CreateAccessToken(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	days := func(d int) *int { return &d }
+
+	t.Run("入力が不正な場合はエラー", func(t *testing.T) {
+		t.Parallel()
+
+		cases := []struct {
+			name string
+			in   CreateAccessTokenInput
+			want error
+		}{
+			{name: "名前が空", in: CreateAccessTokenInput{Name: "  ", Scopes: []string{"tags:write"}}, want: ErrInvalidAccessTokenName},
+			{name: "名前が長すぎる", in: CreateAccessTokenInput{Name: strings.Repeat("あ", 101), Scopes: []string{"tags:write"}}, want: ErrInvalidAccessTokenName},
+			{name: "スコープの指定なし", in: CreateAccessTokenInput{Name: "bot"}, want: ErrInvalidAccessTokenScopes},
+			{name: "不明なスコープ", in: CreateAccessTokenInput{Name: "bot", Scopes: []string{"tags:write", "admin"}}, want: ErrInvalidAccessTokenScopes},
+			{name: "有効期限が0日", in: CreateAccessTokenInput{Name: "bot", Scopes: []string{"tags:write"}, ExpiresInDays: days(0)}, want: ErrInvalidAccessTokenExpiry},
+			{name: "有効期限が長すぎる", in: CreateAccessTokenInput{Name: "bot", Scopes: []string{"tags:write"}, ExpiresInDays: days(366)}, want: ErrInvalidAccessTokenExpiry},
+		}
+		for _, tc := range cases {
+			tokenRepo := &testutil.FakePersonalAccessTokenRepository{
+				CreateFn: func(ctx context.Context, token *model.PersonalAccessToken, maxActivePerUser int64, at time.Time) error {
+					t.Fatalf("%s: unexpected Create call", tc.name)
+					return nil
+				},
+			}
+			svc := newTestPersonalAccessTokenService(tokenRepo, nil, now)
+			if _, err := svc.CreateAccessToken(context.Background(), "u1", tc.in); !errors.Is(err, tc.want) {
+				t.Fatalf("%s: expected %v, got %v", tc.name, tc.want, err)
+			}
+		}
+	})
+
+	t.Run("発行数の上限に達した場合は ErrAccessTokenLimitExceeded", func(t *testing.T) {
+		t.Parallel()
+
+		tokenRepo := &testutil.FakePersonalAccessTokenRepository{
+			CreateFn: func(ctx context.Context, token *model.PersonalAccessToken, maxActivePerUser int64, at time.Time) error {
+				if maxActivePerUser != maxAccessTokensPerUser || !at.Equal(now) {
+					t.Fatalf("unexpected limit: max=%d now=%v", maxActivePerUser, at)
+				}
+				return repository.ErrAccessTokenLimitReached
+			},
+		}
+		svc := newTestPersonalAccessTokenService(tokenRepo, nil, now)
+		_, err := svc.CreateAccessToken(context.Background(), "u1", CreateAccessTokenInput{Name: "bot", Scopes: []string{"tags:write"}})
+		if !errors.Is(err, ErrAccessTokenLimitExceeded) {
+			t.Fatalf("expected ErrAccessTokenLimitExceeded, got %v", err)
+		}
+	})
+
+	t.Run("トークンのハッシュのみを保存し、トークンを返す", func(t *testing.T) {
+		t.Parallel()
+
+		var saved *model.PersonalAccessToken
+		tokenRepo := &testutil.FakePersonalAccessTokenRepository{
+			CreateFn: func(ctx context.Context, token *model.PersonalAccessToken, maxActivePerUser int64, at time.Time) error {
+				saved = token
+				return nil
+			},
+		}
+		svc := newTestPersonalAccessTokenService(tokenRepo, nil, now)
+		created, err := svc.CreateAccessToken(context.Background(), "u1", CreateAccessTokenInput{
+			Name:   " bot ",
+			Scopes: []string{"notifications:read", "tags:write", "tags:write"},
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !strings.HasPrefix(created.Token, AccessTokenPrefix) || len(created.Token) != len(AccessTokenPrefix)+64 {
+			t.Fatalf("unexpected token: %q", created.Token)
+		}
+		if saved.UserID != "u1" || saved.Name != "bot" {
+			t.Fatalf("unexpected token: %+v", saved)
+		}
+		if saved.TokenHash != hashAccessToken(created.Token) || strings.Contains(saved.TokenHash, created.Token) {
+			t.Fatalf("token must be stored as a hash: %q", saved.TokenHash)
+		}
+		if saved.TokenPrefix != created.Token[:12] {
+			t.Fatalf("unexpected token prefix: %q", saved.TokenPrefix)
+		}
+		// スコープは重複を除いて定義順に並べる
+		if len(saved.Scopes) != 2 || saved.Scopes[0] != "tags:write" || saved.Scopes[1] != "notifications:read" {
+			t.Fatalf("unexpected scopes: %v", saved.Scopes)
+		}
+		// 有効期限の既定値は30日
+		if !saved.ExpiresAt.Equal(now.Add(30 * 24 * time.Hour)) {
+			t.Fatalf("unexpected expires_at: %v", saved.ExpiresAt)
+		}
+	})
+}
+
+func TestPersonalAccessTokenService_RevokeAccessToken(t *testing.T) {
+	t.Parallel()
+
+	tokenRepo := &testutil.FakePersonalAccessTokenRepository{
+		DeleteFn: func(ctx context.Context, tokenID, userID string) error {
+			if tokenID == "pat1" && userID == "u1" {
+				return nil
+			}
+			return gorm.ErrRecordNotFound
+		},
+	}
+	svc := newTestPersonalAccessTokenService(tokenRepo, nil, time.Now())
+
+	if err := svc.RevokeAccessToken(context.Background(), "pat1", "u1"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// 他のユーザーのトークンは失効できない
+	if err := svc.RevokeAccessToken(context.Background(), "pat1", "u2"); !errors.Is(err, ErrAccessTokenNotFound) {
+		t.Fatalf("expected ErrAccessTokenNotFound, got %v", err)
+	}
+}
+
+func TestPersonalAccessTokenService_Authenticate(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	const rawToken = "cnt_0123456789abcdef0123456789abcdef"
+
+	newRepos := func(token *model.PersonalAccessToken, user *model.User) (*testutil.FakePersonalAccessTokenRepository, *fakeUserRepo, *[]time.Time) {
+		var touched []time.Time
+		tokenRepo := &testutil.FakePersonalAccessTokenRepository{
+			FindByHashFn: func(ctx context.Context, tokenHash string) (*model.PersonalAccessToken, error) {
+				if token == nil || tokenHash != hashAccessToken(rawToken) {
+					return nil, gorm.ErrRecordNotFound
+				}
+				return token, nil
+			},
+			TouchLastUsedFn: func(ctx context.Context, tokenID string, at time.Time) error {
+				touched = append(touched, at)
+				return nil
+			},
+		}
+		userRepo := &fakeUserRepo{
+			FindByIDFn: func(ctx context.Context, userID string) (*model.User, error) {
+				if user == nil || userID != user.ID {
+					return nil, gorm.ErrRecordNotFound
+				}
+				return user, nil
+			},
+		}
+		return tokenRepo, userRepo, &touched
+	}
+	validToken := func() *model.PersonalAccessToken {
+		return &model.PersonalAccessToken{ID: "pat1", UserID: "u1", Scopes: []string{"tags:write"}, ExpiresAt: now.Add(time.Hour)}
+	}
+
+	t.Run("照合できたらトークンとユーザーを返し、最終利用日時を更新する", func(t *testing.T) {
+		t.Parallel()
+
+		tokenRepo, userRepo, touched := newRepos(validToken(), &model.User{ID: "u1"})
+		svc := newTestPersonalAccessTokenService(tokenRepo, userRepo, now)
+		token, user, err := svc.Authenticate(context.Background(), rawToken)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if token.ID != "pat1" || user.ID != "u1" {
+			t.Fatalf("unexpected result: token=%+v user=%+v", token, user)
+		}
+		if len(*touched) != 1 || !(*touched)[0].Equal(now) {
+			t.Fatalf("expected last_used_at to be updated, got %v", *touched)
+		}
+	})
+
+	t.Run("直近に利用した場合は最終利用日時を更新しない", func(t *testing.T) {
+		t.Parallel()
+
+		token := validToken()
+		recent := now.Add(-10 * time.Second)
+		token.LastUsedAt = &recent
+		tokenRepo, userRepo, touched := newRepos(token, &model.User{ID: "u1"})
+		svc := newTestPersonalAccessTokenService(tokenRepo, userRepo, now)
+		if _, _, err := svc.Authenticate(context.Background(), rawToken); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(*touched) != 0 {
+			t.Fatalf("expected no update, got %v", *touched)
+		}
+	})
+
+	t.Run("照合できない場合は ErrInvalidAccessToken", func(t *testing.T) {
+		t.Parallel()
+
+		expired := validToken()
+		expired.ExpiresAt = now
+		purgedAt := now.Add(-time.Hour)
+
+		cases := []struct {
+			name  string
+			raw   string
+			token *model.PersonalAccessToken
+			user  *model.User
+		}{
+			{name: "接頭辞がない", raw: "0123456789abcdef0123456789abcdef", token: validToken(), user: &model.User{ID: "u1"}},
+			{name: "不明なトークン", raw: "cnt_unknown0123456789", token: validToken(), user: &model.User{ID: "u1"}},
+			{name: "期限切れ", raw: rawToken, token: expired, user: &model.User{ID: "u1"}},
+			{name: "持ち主が存在しない", raw: rawToken, token: validToken(), user: nil},
+			{name: "持ち主が完全削除済み", raw: rawToken, token: validToken(), user: &model.User{ID: "u1", PurgedAt: &purgedAt}},
+		}
+		for _, tc := range cases {
+			tokenRepo, userRepo, touched := newRepos(tc.token, tc.user)
+			svc := newTestPersonalAccessTokenService(tokenRepo, userRepo, now)
+			if _, _, err := svc.Authenticate(context.Background(), tc.raw); !errors.Is(err, ErrInvalidAccessToken) {
+				t.Fatalf("%s: expected ErrInvalidAccessToken, got %v", tc.name, err)
+			}
+			if len(*touched) != 0 {
+				t.Fatalf("%s: last_used_at must not be updated", tc.name)
+			}
+		}
+	})
+}
@@ -106,39 +106,90 @@ type TagService interface {
 
 	// ユーザーがタグをいいねしているかチェックする。
 	IsLikingTag(ctx context.Context, tagID, userID string) (bool, error)
+
+	// ユーザーのタグに関する統計（タグ数・映画数・受けたいいね/フォロー数・よく登録するジャンル/年代）を返す。
+	// publicOnly が true の場合、公開タグのみを集計する（他ユーザーのページ閲覧時）。
+	GetUserTagStats(ctx context.Context, userID string, publicOnly bool) (*UserTagStats, error)
+}
+
+// ユーザー統計で返すジャンル・年代の最大件数。
+const userTagStatsTopLimit = 5
+
+// ジャンルごとの映画数を表す。
+type GenreStat struct {
+	Name       string `json:"name"`
+	MovieCount int64  `json:"movie_count"`
+}
+
+// 公開年代ごとの映画数を表す。
+type DecadeStat struct {
+	Decade     int   `json:"decade"`
+	MovieCount int64 `json:"movie_count"`
+}
+
+// ユーザーのタグに関する統計を表す。
+type UserTagStats struct {
+	TagCount              int64        `json:"tag_count"`
+	MovieCount            int64        `json:"movie_count"`
+	ContributedMovieCount int64        `json:"contributed_movie_count"`
+	LikesReceived         int64        `json:"likes_received"`
+	FollowsReceived       int64        `json:"follows_received"`
+	TopGenres             []GenreStat  `json:"top_genres"`
+	TopDecades            []DecadeStat `json:"top_decades"`
 }
 
 type tagService struct {
-	logger              *slog.Logger
-	tagRepo             repository.TagRepository
-	tagMovieRepo        repository.TagMovieRepository
-	tagFollowerRepo     repository.TagFollowerRepository
-	tagLikeRepo         repository.TagLikeRepository
-	movieService        MovieService
-	notificationService NotificationService
-	imageBaseURL        string
+	logger          *slog.Logger
+	db              *gorm.DB
+	tagRepo         repository.TagRepository
+	tagMovieRepo    repository.TagMovieRepository
+	tagFollowerRepo repository.TagFollowerRepository
+	tagLikeRepo     repository.TagLikeRepository
+	userBlockRepo   repository.UserBlockRepository
+	movieStatusRepo repository.UserMovieStatusRepository
+	movieRatingRepo repository.MovieRatingRepository
+	movieService    MovieService
+	outboxRepo      repository.OutboxRepository
+	imageBaseURL    string
+}
+
+// 状態変更と同じトランザクションで使うリポジトリの組。
+type tagTxRepos struct {
+	tag         repository.TagRepository
+	tagMovie    repository.TagMovieRepository
+	tagFollower repository.TagFollowerRepository
+	tagLike     repository.TagLikeRepository
+	outbox      repository.OutboxRepository
 }
 
 // TagService を生成する。
 func NewTagService(
 	logger *slog.Logger,
+	db *gorm.DB,
 	tagRepo repository.TagRepository,
 	tagMovieRepo repository.TagMovieRepository,
 	tagFollowerRepo repository.TagFollowerRepository,
 	tagLikeRepo repository.TagLikeRepository,
+	userBlockRepo repository.UserBlockRepository,
+	movieStatusRepo repository.UserMovieStatusRepository,
+	movieRatingRepo repository.MovieRatingRepository,
 	movieService MovieService,
-	notificationService NotificationService,
+	outboxRepo repository.OutboxRepository,
 	imageBaseURL string,
 ) TagService {
 	return &tagService{
-		logger:              logger,
-		tagRepo:             tagRepo,
-		tagMovieRepo:        tagMovieRepo,
-		tagFollowerRepo:     tagFollowerRepo,
-		tagLikeRepo:         tagLikeRepo,
-		movieService:        movieService,
-		notificationService: notificationService,
-		imageBaseURL:        strings.TrimRight(imageBaseURL, "/"),
+		logger:          logger,
+		db:              db,
+		tagRepo:         tagRepo,
+		tagMovieRepo:    tagMovieRepo,
+		tagFollowerRepo: tagFollowerRepo,
+		tagLikeRepo:     tagLikeRepo,
+		userBlockRepo:   userBlockRepo,
+		movieStatusRepo: movieStatusRepo,
+		movieRatingRepo: movieRatingRepo,
+		movieService:    movieService,
+		outboxRepo:      outboxRepo,
+		imageBaseURL:    strings.TrimRight(imageBaseURL, "/"),
 	}
 }
 
@@ -210,6 +261,15 @@ type TagDetail struct {
 	// 一覧は未実装のため空配列を返す。
 	ParticipantCount int              `json:"participant_count"`
 	Participants     []TagParticipant `json:"participants"`
+
+	// ログイン中のビューアーの視聴進捗（未ログイン時は省略）。
+	ViewerProgress *TagWatchProgress `json:"viewer_progress,omitempty"`
+}
+
+// タグに含まれる映画のうち、ビューアーが観た映画の数を表す構造体。
+type TagWatchProgress struct {
+	WatchedCount int `json:"watched_count"`
+	MovieCount   int `json:"movie_count"`
 }
 
 // タグの所有者を表す構造体。
@@ -238,6 +298,11 @@ type TagMovieItem struct {
 	CanDelete     bool      `json:"can_delete"`
 	CreatedAt     time.Time `json:"created_at"`
 	Movie         *MovieRef `json:"movie,omitempty"`
+
+	// ログイン中のビューアーの視聴ステータス（未設定・未ログイン時は省略）。
+	ViewerStatus *MovieWatchStatusRef `json:"viewer_status,omitempty"`
+	// ログイン中のビューアーの評価（未評価・未ログイン時は省略）。
+	ViewerRating *float64 `json:"viewer_rating,omitempty"`
 }
 
 // 映画を表す構造体。
@@ -269,6 +334,7 @@ var (
 	ErrNotFollowingTag       = errors.New("not following tag")        // タグをフォローしていない
 	ErrAlreadyLikedTag       = errors.New("already liked tag")        // 既にタグをいいね済み
 	ErrNotLikedTag           = errors.New("not liked tag")            // タグをいいねしていない
+	ErrTagHidden             = errors.New("tag is hidden")            // 管理者が非表示にしたタグを公開しようとした
 )
 
 // タグを更新する。
@@ -283,18 +349,18 @@ func (s *tagService) UpdateTag(ctx context.Context, tagID string, userID string,
 	}
 
 	// タグの存在確認
-	tag, err := s.tagRepo.FindByID(ctx, tagID)
+	tag, err := s.findTag(ctx, tagID)
 	if err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, ErrTagNotFound
-		}
-		// タグの取得に失敗した場合、エラーを返す
 		return nil, err
 	}
 	// タグの作成者がユーザーIDと一致しない場合、エラーを返す
 	if tag.UserID != userID {
 		return nil, ErrTagPermissionDenied
 	}
+	// 管理者が非表示にしたタグは公開に戻せない
+	if tag.HiddenAt != nil && patch.IsPublic != nil && *patch.IsPublic {
+		return nil, ErrTagHidden
+	}
 
 	// バリデーション（title/description）
 	if patch.Title != nil {
@@ -315,13 +381,32 @@ func (s *tagService) UpdateTag(ctx context.Context, tagID string, userID string,
 		}
 	}
 
-	// タグを更新する。
-	err = s.tagRepo.UpdateByID(ctx, tagID, repository.TagUpdatePatch{
-		Title:          patch.Title,
-		Description:    patch.Description,
-		CoverImageURL:  patch.CoverImageURL,
-		IsPublic:       patch.IsPublic,
-		AddMoviePolicy: patch.AddMoviePolicy,
+	// フォロワーに通知する変更（名前の変更・非公開化）を判定する。
+	// 非公開のままのタグはフォロワーが閲覧できないため、名前が変わっても通知しない。
+	var change model.NotificationEventPayload
+	if patch.Title != nil && *patch.Title != tag.Title {
+		previous := tag.Title
+		change.PreviousTitle = &previous
+	}
+	change.MadePrivate = tag.IsPublic && patch.IsPublic != nil && !*patch.IsPublic
+	stillPublic := tag.IsPublic && !change.MadePrivate
+	notify := change.MadePrivate || (change.PreviousTitle != nil && stillPublic)
+
+	// タグの更新と、フォロワーへの通知イベントの書き込みを同じトランザクションで行う
+	err = s.withTx(ctx, func(repos tagTxRepos) error {
+		err := repos.tag.UpdateByID(ctx, tagID, repository.TagUpdatePatch{
+			Title:          patch.Title,
+			Description:    patch.Description,
+			CoverImageURL:  patch.CoverImageURL,
+			IsPublic:       patch.IsPublic,
+			AddMoviePolicy: patch.AddMoviePolicy,
+		})
+		if err != nil || !notify {
+			return err
+		}
+		change.ActorUserID = userID
+		change.TagID = tagID
+		return enqueueNotificationEvent(ctx, repos.outbox, model.OutboxEventTypeTagUpdated, change)
 	})
 	if err != nil {
 		return nil, err
@@ -365,13 +450,26 @@ func (s *tagService) GetTagDetail(ctx context.Context, tagID string, viewerUserI
 	if viewerUserID != nil && strings.TrimSpace(*viewerUserID) != "" {
 		if row.AddMoviePolicy == "everyone" {
 			canAddMovie = true
+			if *viewerUserID != row.OwnerID {
+				// タグ作成者にブロックされている場合は追加不可
+				blocked, err := s.isBlockedByOwner(ctx, row.OwnerID, *viewerUserID)
+				if err != nil {
+					return nil, err
+				}
+				if blocked {
+					canAddMovie = false
+				}
+			}
 		} else if row.AddMoviePolicy == "owner_only" {
 			canAddMovie = *viewerUserID == row.OwnerID
 		}
 	}
 
+	// ビューアーがブロックしているユーザーの投稿は非表示にする
+	blockedIDs := s.listBlockedIDs(ctx, viewerUserID)
+
 	// 参加者（タグに映画を追加したユーザー、作成者除く）を取得
-	contributors, contributorCount, err := s.tagMovieRepo.ListContributorsByTag(ctx, tagID, row.OwnerID, 10)
+	contributors, contributorCount, err := s.tagMovieRepo.ListContributorsByTag(ctx, tagID, row.OwnerID, blockedIDs, 10)
 	if err != nil {
 		// 参加者取得に失敗しても詳細自体は返す
 		contributors = []repository.TagContributor{}
@@ -396,6 +494,19 @@ func (s *tagService) GetTagDetail(ctx context.Context, tagID string, viewerUserI
 		}
 	}
 
+	// ビューアーの視聴進捗を取得
+	var viewerProgress *TagWatchProgress
+	if viewerUserID != nil && strings.TrimSpace(*viewerUserID) != "" && s.movieStatusRepo != nil {
+		watched, err := s.movieStatusRepo.CountWatchedInTag(ctx, *viewerUserID, tagID)
+		if err == nil {
+			// 視聴進捗の取得に失敗しても詳細自体は返す
+			viewerProgress = &TagWatchProgress{
+				WatchedCount: int(watched),
+				MovieCount:   row.MovieCount,
+			}
+		}
+	}
+
 	// タグの詳細を返す。
 	return &TagDetail{
 		ID:             row.ID,
@@ -420,6 +531,7 @@ func (s *tagService) GetTagDetail(ctx context.Context, tagID string, viewerUserI
 		UpdatedAt:        row.UpdatedAt,
 		ParticipantCount: int(contributorCount),
 		Participants:     participants,
+		ViewerProgress:   viewerProgress,
 	}, nil
 }
 
@@ -438,11 +550,8 @@ func (s *tagService) ListTagMovies(ctx context.Context, tagID string, viewerUser
 		pageSize = 100
 	}
 
-	tag, err := s.tagRepo.FindByID(ctx, tagID)
+	tag, err := s.findTag(ctx, tagID)
 	if err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, 0, ErrTagNotFound
-		}
 		return nil, 0, err
 	}
 
@@ -459,8 +568,11 @@ func (s *tagService) ListTagMovies(ctx context.Context, tagID string, viewerUser
 	}
 	viewerIsOwner := viewerID != "" && viewerID == tag.UserID
 
+	// ビューアーがブロックしているユーザーが追加した映画は非表示にする
+	blockedIDs := s.listBlockedIDs(ctx, viewerUserID)
+
 	offset := (page - 1) * pageSize
-	rows, total, err := s.tagMovieRepo.ListByTag(ctx, tagID, offset, pageSize)
+	rows, total, err := s.tagMovieRepo.ListByTag(ctx, tagID, blockedIDs, offset, pageSize)
 	if err != nil {
 		return nil, 0, err
 	}
@@ -468,6 +580,32 @@ func (s *tagService) ListTagMovies(ctx context.Context, tagID string, viewerUser
 		return []TagMovieItem{}, 0, nil
 	}
 
+	// ビューアーの視聴ステータス・評価をまとめて取得する（失敗しても一覧自体は返す）
+	viewerStatuses := map[int]*model.UserMovieStatus{}
+	viewerRatings := map[int]*float64{}
+	if viewerID != "" {
+		movieIDs := make([]int, 0, len(rows))
+		for _, r := range rows {
+			movieIDs = append(movieIDs, r.TmdbMovieID)
+		}
+		if s.movieStatusRepo != nil {
+			statuses, err := s.movieStatusRepo.ListByUserAndMovieIDs(ctx, viewerID, movieIDs)
+			if err == nil {
+				for i := range statuses {
+					viewerStatuses[statuses[i].TmdbMovieID] = &statuses[i]
+				}
+			}
+		}
+		if s.movieRatingRepo != nil {
+			ratings, err := s.movieRatingRepo.ListByUserAndMovieIDs(ctx, viewerID, movieIDs)
+			if err == nil {
+				for i := range ratings {
+					viewerRatings[ratings[i].TmdbMovieID] = &ratings[i].Rating
+				}
+			}
+		}
+	}
+
 	items := make([]TagMovieItem, 0, len(rows))
 	for _, r := range rows {
 		var movie *MovieRef
@@ -532,6 +670,8 @@ func (s *tagService) ListTagMovies(ctx context.Context, tagID string, viewerUser
 			CanDelete:     canDelete,
 			CreatedAt:     r.CreatedAt,
 			Movie:         movie,
+			ViewerStatus:  toMovieWatchStatusRef(viewerStatuses[r.TmdbMovieID]),
+			ViewerRating:  viewerRatings[r.TmdbMovieID],
 		})
 	}
 
@@ -560,27 +700,23 @@ func (s *tagService) CreateTag(ctx context.Context, in CreateTagInput) (*model.T
 		AddMoviePolicy: addMoviePolicy,
 	}
 
-	if err := s.tagRepo.Create(ctx, &tag); err != nil {
+	// 公開タグ作成時は、フォロワーへの通知イベントをタグと同じトランザクションで書き込む
+	err := s.withTx(ctx, func(repos tagTxRepos) error {
+		if err := repos.tag.Create(ctx, &tag); err != nil {
+			return err
+		}
+		if !tag.IsPublic {
+			return nil
+		}
+		return enqueueNotificationEvent(ctx, repos.outbox, model.OutboxEventTypeTagCreated, model.NotificationEventPayload{
+			ActorUserID: tag.UserID,
+			TagID:       tag.ID,
+		})
+	})
+	if err != nil {
 		return nil, err
 	}
 
-	// 公開タグ作成時、フォロワーに通知（非同期）
-	if tag.IsPublic && s.notificationService != nil {
-		logger := s.logger
-		tagID := tag.ID
-		userID := tag.UserID
-		go func() {
-			ctx2, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-			defer cancel()
-			if err := s.notificationService.NotifyFollowingUserCreatedTag(ctx2, tagID, userID); err != nil {
-				logger.Error("service.CreateTag failed to send notification",
-					slog.String("tag_id", tagID),
-					slog.Any("error", err),
-				)
-			}
-		}()
-	}
-
 	return &tag, nil
 }
 
@@ -598,11 +734,8 @@ func (s *tagService) AddMoviesToTag(ctx context.Context, in AddMoviesToTagInput)
 	}
 
 	// タグの存在確認（1回だけ）
-	tag, err := s.tagRepo.FindByID(ctx, in.TagID)
+	tag, err := s.findTag(ctx, in.TagID)
 	if err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, ErrTagNotFound
-		}
 		return nil, err
 	}
 
@@ -610,6 +743,16 @@ func (s *tagService) AddMoviesToTag(ctx context.Context, in AddMoviesToTagInput)
 	if tag.AddMoviePolicy == "owner_only" && tag.UserID != in.UserID {
 		return nil, ErrTagPermissionDenied
 	}
+	if tag.UserID != in.UserID {
+		// タグ作成者にブロックされているユーザーは映画を追加できない
+		blocked, err := s.isBlockedByOwner(ctx, tag.UserID, in.UserID)
+		if err != nil {
+			return nil, err
+		}
+		if blocked {
+			return nil, ErrTagPermissionDenied
+		}
+	}
 
 	// 各映画を個別に処理
 	results := make([]MovieResult, len(in.Movies))
@@ -644,7 +787,33 @@ func (s *tagService) AddMoviesToTag(ctx context.Context, in AddMoviesToTagInput)
 			Position:    movie.Position,
 		}
 
-		if err := s.tagMovieRepo.Create(ctx, &tm); err != nil {
+		// 映画の追加と通知イベントの書き込みは1件ごとに同じトランザクションで行う
+		err := s.withTx(ctx, func(repos tagTxRepos) error {
+			if err := repos.tagMovie.Create(ctx, &tm); err != nil {
+				return err
+			}
+			err := enqueueNotificationEvent(ctx, repos.outbox, model.OutboxEventTypeTagMovieAdded, model.NotificationEventPayload{
+				ActorUserID: in.UserID,
+				TagID:       in.TagID,
+				TagMovieID:  tm.ID,
+				TmdbMovieID: tm.TmdbMovieID,
+			})
+			if err != nil {
+				return err
+			}
+			// メモでメンションされたユーザーへの通知は、配信時に display_id を解決する
+			mentions := mentionsInNote(movie.Note)
+			if len(mentions) == 0 {
+				return nil
+			}
+			return enqueueNotificationEvent(ctx, repos.outbox, model.OutboxEventTypeNoteMentioned, model.NotificationEventPayload{
+				ActorUserID:         in.UserID,
+				TagID:               in.TagID,
+				TagMovieID:          tm.ID,
+				MentionedDisplayIDs: mentions,
+			})
+		})
+		if err != nil {
 			if errors.Is(err, repository.ErrTagMovieAlreadyExists) {
 				results[i] = MovieResult{
 					TmdbMovieID: movie.TmdbMovieID,
@@ -686,36 +855,76 @@ func (s *tagService) AddMoviesToTag(ctx context.Context, in AddMoviesToTagInput)
 		}
 	}
 
-	// 新規追加された映画ごとに通知を送信（非同期）
-	if summary.Created > 0 && s.notificationService != nil {
-		for _, r := range results {
-			if r.Status == "created" && r.TagMovie != nil {
-				logger := s.logger
-				notifSvc := s.notificationService
-				tagID := in.TagID
-				tagMovieID := r.TagMovie.ID
-				actorUserID := in.UserID
-				go func() {
-					ctx2, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-					defer cancel()
-					if err := notifSvc.NotifyTagMovieAdded(ctx2, tagID, tagMovieID, actorUserID); err != nil {
-						logger.Error("service.AddMoviesToTag failed to send notification",
-							slog.String("tag_id", tagID),
-							slog.String("tag_movie_id", tagMovieID),
-							slog.Any("error", err),
-						)
-					}
-				}()
-			}
-		}
-	}
-
 	return &AddMoviesResult{
 		Results: results,
 		Summary: summary,
 	}, nil
 }
 
+// fn をトランザクション内で実行する。
+// db が未設定の場合（ユニットテストなど）はトランザクションを張らず、保持しているリポジトリで実行する。
+func (s *tagService) withTx(ctx context.Context, fn func(repos tagTxRepos) error) error {
+	if s.db == nil {
+		return fn(tagTxRepos{
+			tag:         s.tagRepo,
+			tagMovie:    s.tagMovieRepo,
+			tagFollower: s.tagFollowerRepo,
+			tagLike:     s.tagLikeRepo,
+			outbox:      s.outboxRepo,
+		})
+	}
+	return s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		repos := tagTxRepos{
+			tag:         repository.NewTagRepository(tx),
+			tagMovie:    repository.NewTagMovieRepository(tx),
+			tagFollower: repository.NewTagFollowerRepository(tx),
+			tagLike:     repository.NewTagLikeRepository(tx),
+		}
+		if s.outboxRepo != nil {
+			repos.outbox = repository.NewOutboxRepository(tx)
+		}
+		return fn(repos)
+	})
+}
+
+// 利用者向けの操作の対象となるタグを取得する。
+// 作成者が退会の猶予期間中・利用停止中のタグは、詳細や一覧と同じく存在しないもの（ErrTagNotFound）として扱う。
+func (s *tagService) findTag(ctx context.Context, tagID string) (*model.Tag, error) {
+	tag, err := s.tagRepo.FindByIDWithActiveOwner(ctx, tagID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrTagNotFound
+		}
+		return nil, err
+	}
+	return tag, nil
+}
+
+// タグ作成者が指定ユーザーをブロックしているかチェックする。
+func (s *tagService) isBlockedByOwner(ctx context.Context, ownerID, userID string) (bool, error) {
+	if s.userBlockRepo == nil {
+		return false, nil
+	}
+	return s.userBlockRepo.IsBlocking(ctx, ownerID, userID)
+}
+
+// ビューアーがブロックしているユーザーIDの一覧を返す。
+// 未認証の場合や取得に失敗した場合は空を返す（表示自体は継続する）。
+func (s *tagService) listBlockedIDs(ctx context.Context, viewerUserID *string) []string {
+	if s.userBlockRepo == nil || viewerUserID == nil || strings.TrimSpace(*viewerUserID) == "" {
+		return nil
+	}
+	ids, err := s.userBlockRepo.ListBlockedIDs(ctx, *viewerUserID)
+	if err != nil {
+		s.logger.Warn("service.listBlockedIDs failed",
+			slog.String("viewer_user_id", *viewerUserID),
+			slog.Any("error", err),
+		)
+		return nil
+	}
+	return ids
+}
+
 // 公開タグ一覧を返す。
 func (s *tagService) ListPublicTags(ctx context.Context, q, sort string, page, pageSize int) ([]TagListItem, int64, error) {
 	if page < 1 {
@@ -914,11 +1123,8 @@ func (s *tagService) RemoveMovieFromTag(ctx context.Context, tagMovieID string,
 	}
 
 	// タグを取得して権限チェック
-	tag, err := s.tagRepo.FindByID(ctx, tagMovie.TagID)
+	tag, err := s.findTag(ctx, tagMovie.TagID)
 	if err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return ErrTagNotFound
-		}
 		return err
 	}
 
@@ -934,8 +1140,18 @@ func (s *tagService) RemoveMovieFromTag(ctx context.Context, tagMovieID string,
 		return ErrTagPermissionDenied
 	}
 
-	// 映画を削除
-	return s.tagMovieRepo.Delete(ctx, tagMovieID)
+	// 映画の削除と Webhook 向けのイベントの書き込みを同じトランザクションで行う
+	return s.withTx(ctx, func(repos tagTxRepos) error {
+		if err := repos.tagMovie.Delete(ctx, tagMovieID); err != nil {
+			return err
+		}
+		return enqueueNotificationEvent(ctx, repos.outbox, model.OutboxEventTypeTagMovieRemoved, model.NotificationEventPayload{
+			ActorUserID: userID,
+			TagID:       tagMovie.TagID,
+			TagMovieID:  tagMovieID,
+			TmdbMovieID: tagMovie.TmdbMovieID,
+		})
+	})
 }
 
 // タグをフォローする。
@@ -948,11 +1164,8 @@ func (s *tagService) FollowTag(ctx context.Context, tagID, userID string) error
 	}
 
 	// タグの存在確認
-	tag, err := s.tagRepo.FindByID(ctx, tagID)
+	tag, err := s.findTag(ctx, tagID)
 	if err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return ErrTagNotFound
-		}
 		return err
 	}
 
@@ -970,28 +1183,16 @@ func (s *tagService) FollowTag(ctx context.Context, tagID, userID string) error
 		return ErrAlreadyFollowingTag
 	}
 
-	// フォロー関係を作成
-	if err := s.tagFollowerRepo.Create(ctx, tagID, userID); err != nil {
-		return err
-	}
-
-	// タグオーナーに通知（非同期）
-	if s.notificationService != nil {
-		logger := s.logger
-		notifSvc := s.notificationService
-		go func() {
-			ctx2, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-			defer cancel()
-			if err := notifSvc.NotifyTagFollowed(ctx2, tagID, userID); err != nil {
-				logger.Error("service.FollowTag failed to send notification",
-					slog.String("tag_id", tagID),
-					slog.Any("error", err),
-				)
-			}
-		}()
-	}
-
-	return nil
+	// フォロー関係の作成と、タグオーナーへの通知イベントの書き込みを同じトランザクションで行う
+	return s.withTx(ctx, func(repos tagTxRepos) error {
+		if err := repos.tagFollower.Create(ctx, tagID, userID); err != nil {
+			return err
+		}
+		return enqueueNotificationEvent(ctx, repos.outbox, model.OutboxEventTypeTagFollowed, model.NotificationEventPayload{
+			ActorUserID: userID,
+			TagID:       tagID,
+		})
+	})
 }
 
 // UnfollowTag はタグのフォローを解除します。
@@ -1004,11 +1205,7 @@ func (s *tagService) UnfollowTag(ctx context.Context, tagID, userID string) erro
 	}
 
 	// タグの存在確認
-	_, err := s.tagRepo.FindByID(ctx, tagID)
-	if err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return ErrTagNotFound
-		}
+	if _, err := s.findTag(ctx, tagID); err != nil {
 		return err
 	}
 
@@ -1053,11 +1250,7 @@ func (s *tagService) ListTagFollowers(ctx context.Context, tagID string, page, p
 	}
 
 	// タグの存在確認
-	_, err := s.tagRepo.FindByID(ctx, tagID)
-	if err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, 0, ErrTagNotFound
-		}
+	if _, err := s.findTag(ctx, tagID); err != nil {
 		return nil, 0, err
 	}
 
@@ -1172,11 +1365,8 @@ func (s *tagService) LikeTag(ctx context.Context, tagID, userID string) error {
 	}
 
 	// タグの存在確認
-	tag, err := s.tagRepo.FindByID(ctx, tagID)
+	tag, err := s.findTag(ctx, tagID)
 	if err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return ErrTagNotFound
-		}
 		return err
 	}
 
@@ -1194,7 +1384,16 @@ func (s *tagService) LikeTag(ctx context.Context, tagID, userID string) error {
 		return ErrAlreadyLikedTag
 	}
 
-	return s.tagLikeRepo.Create(ctx, tagID, userID)
+	// いいねの作成と、フィードへ記録するためのイベントの書き込みを同じトランザクションで行う
+	return s.withTx(ctx, func(repos tagTxRepos) error {
+		if err := repos.tagLike.Create(ctx, tagID, userID); err != nil {
+			return err
+		}
+		return enqueueNotificationEvent(ctx, repos.outbox, model.OutboxEventTypeTagLiked, model.NotificationEventPayload{
+			ActorUserID: userID,
+			TagID:       tagID,
+		})
+	})
 }
 
 // UnlikeTag はタグのいいねを解除します。
@@ -1207,11 +1406,7 @@ func (s *tagService) UnlikeTag(ctx context.Context, tagID, userID string) error
 	}
 
 	// タグの存在確認
-	_, err := s.tagRepo.FindByID(ctx, tagID)
-	if err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return ErrTagNotFound
-		}
+	if _, err := s.findTag(ctx, tagID); err != nil {
 		return err
 	}
 
@@ -1238,3 +1433,40 @@ func (s *tagService) IsLikingTag(ctx context.Context, tagID, userID string) (boo
 
 	return s.tagLikeRepo.IsLiking(ctx, tagID, userID)
 }
+
+// ユーザーのタグに関する統計を返す。
+func (s *tagService) GetUserTagStats(ctx context.Context, userID string, publicOnly bool) (*UserTagStats, error) {
+	if strings.TrimSpace(userID) == "" {
+		return nil, fmt.Errorf("user_id is required")
+	}
+
+	row, err := s.tagRepo.GetUserTagStats(ctx, userID, publicOnly)
+	if err != nil {
+		return nil, err
+	}
+	genres, err := s.tagRepo.ListUserTopGenres(ctx, userID, publicOnly, userTagStatsTopLimit)
+	if err != nil {
+		return nil, err
+	}
+	decades, err := s.tagRepo.ListUserTopDecades(ctx, userID, publicOnly, userTagStatsTopLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	stats := &UserTagStats{
+		TagCount:              row.TagCount,
+		MovieCount:            row.MovieCount,
+		ContributedMovieCount: row.ContributedMovieCount,
+		LikesReceived:         row.LikeCount,
+		FollowsReceived:       row.FollowerCount,
+		TopGenres:             make([]GenreStat, len(genres)),
+		TopDecades:            make([]DecadeStat, len(decades)),
+	}
+	for i, g := range genres {
+		stats.TopGenres[i] = GenreStat{Name: g.Name, MovieCount: g.Count}
+	}
+	for i, d := range decades {
+		stats.TopDecades[i] = DecadeStat{Decade: d.Decade, MovieCount: d.Count}
+	}
+	return stats, nil
+}
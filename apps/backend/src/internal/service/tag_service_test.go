@@ -2,7 +2,10 @@ package service
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
+	"fmt"
+	"reflect"
 	"strings"
 	"testing"
 	"time"
@@ -46,12 +49,20 @@ func (f *fakeMovieService) GetMovieRelatedTags(_ context.Context, _ int, _ int)
 	return nil, nil
 }
 
+func (f *fakeMovieService) RefreshExpiredMovieCaches(_ context.Context, _ time.Time, _ int) (int, error) {
+	return 0, nil
+}
+
 type deps struct {
 	tagRepo         *testutil.FakeTagRepository
 	tagMovieRepo    *testutil.FakeTagMovieRepository
 	tagFollowerRepo *testutil.FakeTagFollowerRepository
 	tagLikeRepo     *testutil.FakeTagLikeRepository
+	userBlockRepo   *testutil.FakeUserBlockRepository
+	movieStatusRepo *testutil.FakeUserMovieStatusRepository
+	movieRatingRepo *testutil.FakeMovieRatingRepository
 	movieService    MovieService
+	outboxRepo      *testutil.FakeOutboxRepository
 	imageBaseURL    string
 }
 
@@ -64,13 +75,17 @@ func newTagService(t *testing.T, opt func(*deps)) TagService {
 		tagMovieRepo:    &testutil.FakeTagMovieRepository{},
 		tagFollowerRepo: &testutil.FakeTagFollowerRepository{},
 		tagLikeRepo:     &testutil.FakeTagLikeRepository{},
+		userBlockRepo:   &testutil.FakeUserBlockRepository{},
+		movieStatusRepo: &testutil.FakeUserMovieStatusRepository{},
+		movieRatingRepo: &testutil.FakeMovieRatingRepository{},
 		movieService:    nil,
+		outboxRepo:      &testutil.FakeOutboxRepository{},
 		imageBaseURL:    "",
 	}
 	if opt != nil {
 		opt(d)
 	}
-	return NewTagService(logger, d.tagRepo, d.tagMovieRepo, d.tagFollowerRepo, d.tagLikeRepo, d.movieService, nil, d.imageBaseURL)
+	return NewTagService(logger, nil, d.tagRepo, d.tagMovieRepo, d.tagFollowerRepo, d.tagLikeRepo, d.userBlockRepo, d.movieStatusRepo, d.movieRatingRepo, d.movieService, d.outboxRepo, d.imageBaseURL)
 }
 
 func TestTagService_AddMoviesToTag(t *testing.T) {
@@ -222,6 +237,35 @@ func TestTagService_AddMoviesToTag(t *testing.T) {
 		}
 	})
 
+	t.Run("権限チェック: everyone でもタグ作成者にブロックされている場合 → ErrTagPermissionDenied", func(t *testing.T) {
+		t.Parallel()
+		createCalled := false
+		svc := newTagService(t, func(d *deps) {
+			d.tagRepo.FindByIDFn = func(ctx context.Context, id string) (*model.Tag, error) {
+				return &model.Tag{ID: id, UserID: "owner1", AddMoviePolicy: "everyone"}, nil
+			}
+			d.userBlockRepo.IsBlockingFn = func(ctx context.Context, blockerID, blockedID string) (bool, error) {
+				return blockerID == "owner1" && blockedID == "blocked_user", nil
+			}
+			d.tagMovieRepo.CreateFn = func(ctx context.Context, tagMovie *model.TagMovie) error {
+				createCalled = true
+				return nil
+			}
+		})
+
+		_, err := svc.AddMoviesToTag(context.Background(), AddMoviesToTagInput{
+			TagID:  "t1",
+			UserID: "blocked_user",
+			Movies: []MovieItem{{TmdbMovieID: 1}},
+		})
+		if !errors.Is(err, ErrTagPermissionDenied) {
+			t.Fatalf("expected ErrTagPermissionDenied, got: %v", err)
+		}
+		if createCalled {
+			t.Fatalf("expected Create not to be called")
+		}
+	})
+
 	t.Run("全件成功: 3件追加", func(t *testing.T) {
 		t.Parallel()
 		var createdIDs []int
@@ -289,6 +333,83 @@ func TestTagService_AddMoviesToTag(t *testing.T) {
 		}
 	})
 
+	t.Run("通知イベント: 追加に成功した映画ごとにアウトボックスに書き込む", func(t *testing.T) {
+		t.Parallel()
+		var enqueued []string
+		svc := newTagService(t, func(d *deps) {
+			d.tagRepo.FindByIDFn = func(ctx context.Context, id string) (*model.Tag, error) {
+				return &model.Tag{ID: id, UserID: "u1"}, nil
+			}
+			d.tagMovieRepo.CreateFn = func(ctx context.Context, tagMovie *model.TagMovie) error {
+				if tagMovie.TmdbMovieID == 20 {
+					return repository.ErrTagMovieAlreadyExists
+				}
+				tagMovie.ID = fmt.Sprintf("tm%d", tagMovie.TmdbMovieID)
+				return nil
+			}
+			d.outboxRepo.EnqueueFn = func(ctx context.Context, events ...*model.OutboxEvent) error {
+				for _, e := range events {
+					if e.EventType != model.OutboxEventTypeTagMovieAdded {
+						t.Fatalf("unexpected event type: %s", e.EventType)
+					}
+					enqueued = append(enqueued, e.IdempotencyKey)
+				}
+				return nil
+			}
+		})
+
+		_, err := svc.AddMoviesToTag(context.Background(), AddMoviesToTagInput{
+			TagID:  "t1",
+			UserID: "u1",
+			Movies: []MovieItem{{TmdbMovieID: 10}, {TmdbMovieID: 20}, {TmdbMovieID: 30}},
+		})
+		if err != nil {
+			t.Fatalf("expected no error, got: %v", err)
+		}
+		want := []string{"tag.movie_added:t1:tm10:u1", "tag.movie_added:t1:tm30:u1"}
+		if !reflect.DeepEqual(enqueued, want) {
+			t.Fatalf("unexpected outbox events: %v", enqueued)
+		}
+	})
+
+	t.Run("通知イベント: メモにメンションがある場合はメンションのイベントも書き込む", func(t *testing.T) {
+		t.Parallel()
+		var events []*model.OutboxEvent
+		svc := newTagService(t, func(d *deps) {
+			d.tagRepo.FindByIDFn = func(ctx context.Context, id string) (*model.Tag, error) {
+				return &model.Tag{ID: id, UserID: "u1"}, nil
+			}
+			d.tagMovieRepo.CreateFn = func(ctx context.Context, tagMovie *model.TagMovie) error {
+				tagMovie.ID = fmt.Sprintf("tm%d", tagMovie.TmdbMovieID)
+				return nil
+			}
+			d.outboxRepo.EnqueueFn = func(ctx context.Context, e ...*model.OutboxEvent) error {
+				events = append(events, e...)
+				return nil
+			}
+		})
+
+		note := "@alice と @Bob に見てほしい"
+		_, err := svc.AddMoviesToTag(context.Background(), AddMoviesToTagInput{
+			TagID:  "t1",
+			UserID: "u1",
+			Movies: []MovieItem{{TmdbMovieID: 10, Note: &note}, {TmdbMovieID: 20}},
+		})
+		if err != nil {
+			t.Fatalf("expected no error, got: %v", err)
+		}
+		if len(events) != 3 || events[1].EventType != model.OutboxEventTypeNoteMentioned {
+			t.Fatalf("unexpected outbox events: %+v", events)
+		}
+		var p model.NotificationEventPayload
+		if err := json.Unmarshal(events[1].Payload, &p); err != nil {
+			t.Fatalf("invalid payload: %v", err)
+		}
+		if p.TagMovieID != "tm10" || !reflect.DeepEqual(p.MentionedDisplayIDs, []string{"alice", "bob"}) {
+			t.Fatalf("unexpected payload: %+v", p)
+		}
+	})
+
 	t.Run("個別バリデーション: tmdb_movie_id が不正な項目はerror", func(t *testing.T) {
 		t.Parallel()
 		svc := newTagService(t, func(d *deps) {
@@ -496,6 +617,67 @@ func TestTagService_CreateTag(t *testing.T) {
 			t.Fatalf("expected AddMoviePolicy=owner_only, got %v", created.AddMoviePolicy)
 		}
 	})
+
+	t.Run("公開タグ: フォロワーへの通知イベントをアウトボックスに書き込む", func(t *testing.T) {
+		t.Parallel()
+
+		var enqueued []*model.OutboxEvent
+		svc := newTagService(t, func(d *deps) {
+			d.tagRepo.CreateFn = func(ctx context.Context, tag *model.Tag) error {
+				tag.ID = "tag1"
+				return nil
+			}
+			d.outboxRepo.EnqueueFn = func(ctx context.Context, events ...*model.OutboxEvent) error {
+				enqueued = append(enqueued, events...)
+				return nil
+			}
+		})
+
+		if _, err := svc.CreateTag(context.Background(), CreateTagInput{UserID: "u1", Title: "title"}); err != nil {
+			t.Fatalf("expected no error, got: %v", err)
+		}
+		if len(enqueued) != 1 {
+			t.Fatalf("expected 1 outbox event, got %d", len(enqueued))
+		}
+		if enqueued[0].EventType != model.OutboxEventTypeTagCreated || enqueued[0].IdempotencyKey != "tag.created:tag1:u1" {
+			t.Fatalf("unexpected outbox event: %+v", enqueued[0])
+		}
+		var payload model.NotificationEventPayload
+		testutil.MustUnmarshalJSON(t, enqueued[0].Payload, &payload)
+		if payload.TagID != "tag1" || payload.ActorUserID != "u1" {
+			t.Fatalf("unexpected payload: %+v", payload)
+		}
+	})
+
+	t.Run("非公開タグ: 通知イベントを書き込まない", func(t *testing.T) {
+		t.Parallel()
+
+		svc := newTagService(t, func(d *deps) {
+			d.outboxRepo.EnqueueFn = func(ctx context.Context, events ...*model.OutboxEvent) error {
+				t.Fatalf("unexpected outbox event: %+v", events)
+				return nil
+			}
+		})
+
+		isPublic := false
+		if _, err := svc.CreateTag(context.Background(), CreateTagInput{UserID: "u1", Title: "title", IsPublic: &isPublic}); err != nil {
+			t.Fatalf("expected no error, got: %v", err)
+		}
+	})
+
+	t.Run("通知イベントの書き込みに失敗した場合はエラー", func(t *testing.T) {
+		t.Parallel()
+
+		svc := newTagService(t, func(d *deps) {
+			d.outboxRepo.EnqueueFn = func(ctx context.Context, events ...*model.OutboxEvent) error {
+				return errors.New("db down")
+			}
+		})
+
+		if _, err := svc.CreateTag(context.Background(), CreateTagInput{UserID: "u1", Title: "title"}); err == nil {
+			t.Fatalf("expected error")
+		}
+	})
 }
 
 func TestTagService_ListPublicTags(t *testing.T) {
@@ -688,6 +870,33 @@ func TestTagService_GetTagDetail(t *testing.T) {
 			t.Fatalf("expected CanAddMovie=false for unauthenticated user, got %v", out.CanAddMovie)
 		}
 	})
+
+	t.Run("ブロック判定に失敗した場合はエラーを返す", func(t *testing.T) {
+		t.Parallel()
+
+		viewerID := "viewer1"
+		blockErr := errors.New("db error")
+		svc := newTagService(t, func(d *deps) {
+			d.tagRepo.FindDetailByIDFn = func(ctx context.Context, id string) (*repository.TagDetailRow, error) {
+				return &repository.TagDetailRow{
+					ID:               id,
+					Title:            "Test Tag",
+					IsPublic:         true,
+					AddMoviePolicy:   "everyone",
+					OwnerID:          "owner1",
+					OwnerDisplayName: "Owner",
+				}, nil
+			}
+			d.userBlockRepo.IsBlockingFn = func(ctx context.Context, blockerID, blockedID string) (bool, error) {
+				return false, blockErr
+			}
+		})
+
+		_, err := svc.GetTagDetail(context.Background(), "t1", &viewerID)
+		if !errors.Is(err, blockErr) {
+			t.Fatalf("expected block check error, got: %v", err)
+		}
+	})
 }
 
 func TestTagService_UpdateTag(t *testing.T) {
@@ -755,6 +964,99 @@ func TestTagService_UpdateTag(t *testing.T) {
 			t.Fatalf("expected error message about add_movie_policy, got: %v", err)
 		}
 	})
+
+	t.Run("管理者が非表示にしたタグは公開に戻せない", func(t *testing.T) {
+		t.Parallel()
+
+		hiddenAt := time.Now()
+		svc := newTagService(t, func(d *deps) {
+			d.tagRepo.FindByIDFn = func(ctx context.Context, id string) (*model.Tag, error) {
+				return &model.Tag{ID: id, UserID: "u1", HiddenAt: &hiddenAt}, nil
+			}
+			d.tagRepo.UpdateByIDFn = func(ctx context.Context, id string, patch repository.TagUpdatePatch) error {
+				t.Fatalf("unexpected UpdateByID call")
+				return nil
+			}
+		})
+
+		public := true
+		_, err := svc.UpdateTag(context.Background(), "t1", "u1", UpdateTagPatch{IsPublic: &public})
+		if !errors.Is(err, ErrTagHidden) {
+			t.Fatalf("expected ErrTagHidden, got %v", err)
+		}
+	})
+}
+
+func TestTagService_UpdateTag_NotifiesFollowers(t *testing.T) {
+	t.Parallel()
+
+	newTitle := "New"
+	private := false
+	description := strPtr("desc")
+	cases := []struct {
+		name     string
+		isPublic bool
+		patch    UpdateTagPatch
+		want     *model.NotificationEventPayload
+	}{
+		{
+			name:     "公開タグの名前の変更",
+			isPublic: true,
+			patch:    UpdateTagPatch{Title: &newTitle},
+			want:     &model.NotificationEventPayload{ActorUserID: "u1", TagID: "t1", PreviousTitle: strPtr("Old")},
+		},
+		{
+			name:     "公開タグの非公開化",
+			isPublic: true,
+			patch:    UpdateTagPatch{IsPublic: &private},
+			want:     &model.NotificationEventPayload{ActorUserID: "u1", TagID: "t1", MadePrivate: true},
+		},
+		{
+			name:     "非公開タグの名前の変更は通知しない",
+			isPublic: false,
+			patch:    UpdateTagPatch{Title: &newTitle},
+		},
+		{
+			name:     "名前・公開設定以外の変更は通知しない",
+			isPublic: true,
+			patch:    UpdateTagPatch{Description: &description},
+		},
+	}
+	for _, tc := range cases {
+		var events []*model.OutboxEvent
+		svc := newTagService(t, func(d *deps) {
+			d.tagRepo.FindByIDFn = func(ctx context.Context, id string) (*model.Tag, error) {
+				return &model.Tag{ID: id, UserID: "u1", Title: "Old", IsPublic: tc.isPublic}, nil
+			}
+			d.tagRepo.FindDetailByIDFn = func(ctx context.Context, id string) (*repository.TagDetailRow, error) {
+				return &repository.TagDetailRow{ID: id, OwnerID: "u1"}, nil
+			}
+			d.outboxRepo.EnqueueFn = func(ctx context.Context, e ...*model.OutboxEvent) error {
+				events = append(events, e...)
+				return nil
+			}
+		})
+
+		if _, err := svc.UpdateTag(context.Background(), "t1", "u1", tc.patch); err != nil {
+			t.Fatalf("%s: unexpected error: %v", tc.name, err)
+		}
+		if tc.want == nil {
+			if len(events) != 0 {
+				t.Fatalf("%s: expected no events, got %+v", tc.name, events)
+			}
+			continue
+		}
+		if len(events) != 1 || events[0].EventType != model.OutboxEventTypeTagUpdated {
+			t.Fatalf("%s: unexpected events: %+v", tc.name, events)
+		}
+		var got model.NotificationEventPayload
+		if err := json.Unmarshal(events[0].Payload, &got); err != nil {
+			t.Fatalf("%s: invalid payload: %v", tc.name, err)
+		}
+		if !reflect.DeepEqual(&got, tc.want) {
+			t.Fatalf("%s: unexpected payload: %+v", tc.name, got)
+		}
+	}
 }
 
 func TestTagService_RemoveMovieFromTag(t *testing.T) {
@@ -993,6 +1295,38 @@ func TestTagService_RemoveMovieFromTag(t *testing.T) {
 			t.Fatalf("expected delete to be called with tm1, got %s", deletedID)
 		}
 	})
+
+	t.Run("Webhook 向けに削除した映画のイベントを書き込む", func(t *testing.T) {
+		t.Parallel()
+
+		var enqueued []*model.OutboxEvent
+		svc := newTagService(t, func(d *deps) {
+			d.tagMovieRepo.FindByIDFn = func(ctx context.Context, tagMovieID string) (*model.TagMovie, error) {
+				return &model.TagMovie{ID: tagMovieID, TagID: "t1", TmdbMovieID: 550, AddedByUser: "u1"}, nil
+			}
+			d.tagRepo.FindByIDFn = func(ctx context.Context, id string) (*model.Tag, error) {
+				return &model.Tag{ID: id, UserID: "owner1", AddMoviePolicy: "everyone"}, nil
+			}
+			d.outboxRepo.EnqueueFn = func(ctx context.Context, events ...*model.OutboxEvent) error {
+				enqueued = append(enqueued, events...)
+				return nil
+			}
+		})
+
+		if err := svc.RemoveMovieFromTag(context.Background(), "tm1", "u1"); err != nil {
+			t.Fatalf("expected no error, got: %v", err)
+		}
+		if len(enqueued) != 1 || enqueued[0].EventType != model.OutboxEventTypeTagMovieRemoved {
+			t.Fatalf("expected a tag.movie_removed event, got %+v", enqueued)
+		}
+		var payload model.NotificationEventPayload
+		if err := json.Unmarshal(enqueued[0].Payload, &payload); err != nil {
+			t.Fatalf("invalid payload: %v", err)
+		}
+		if payload.TagID != "t1" || payload.ActorUserID != "u1" || payload.TmdbMovieID != 550 {
+			t.Fatalf("unexpected payload: %+v", payload)
+		}
+	})
 }
 
 func TestTagService_ListTagMovies_CanDelete(t *testing.T) {
@@ -1003,7 +1337,7 @@ func TestTagService_ListTagMovies_CanDelete(t *testing.T) {
 			d.tagRepo.FindByIDFn = func(ctx context.Context, id string) (*model.Tag, error) {
 				return tag, nil
 			}
-			d.tagMovieRepo.ListByTagFn = func(ctx context.Context, tagID string, offset, limit int) ([]repository.TagMovieWithCache, int64, error) {
+			d.tagMovieRepo.ListByTagFn = func(ctx context.Context, tagID string, excludeUserIDs []string, offset, limit int) ([]repository.TagMovieWithCache, int64, error) {
 				return rows, int64(len(rows)), nil
 			}
 		})
@@ -1085,6 +1419,61 @@ func TestTagService_ListTagMovies_CanDelete(t *testing.T) {
 	})
 }
 
+func TestTagService_ListTagMovies_HidesBlockedUsers(t *testing.T) {
+	t.Parallel()
+
+	t.Run("ビューアーがブロックしているユーザーのIDが除外条件として渡される", func(t *testing.T) {
+		t.Parallel()
+
+		var gotExclude []string
+		svc := newTagService(t, func(d *deps) {
+			d.tagRepo.FindByIDFn = func(ctx context.Context, id string) (*model.Tag, error) {
+				return &model.Tag{ID: id, UserID: "owner1", IsPublic: true, AddMoviePolicy: "everyone"}, nil
+			}
+			d.userBlockRepo.ListBlockedIDsFn = func(ctx context.Context, blockerID string) ([]string, error) {
+				if blockerID != "viewer1" {
+					t.Fatalf("unexpected blockerID: %s", blockerID)
+				}
+				return []string{"blocked1"}, nil
+			}
+			d.tagMovieRepo.ListByTagFn = func(ctx context.Context, tagID string, excludeUserIDs []string, offset, limit int) ([]repository.TagMovieWithCache, int64, error) {
+				gotExclude = excludeUserIDs
+				return []repository.TagMovieWithCache{}, 0, nil
+			}
+		})
+
+		viewer := "viewer1"
+		if _, _, err := svc.ListTagMovies(context.Background(), "t1", &viewer, 1, 50); err != nil {
+			t.Fatalf("expected no error, got: %v", err)
+		}
+		if len(gotExclude) != 1 || gotExclude[0] != "blocked1" {
+			t.Fatalf("expected exclude=[blocked1], got %v", gotExclude)
+		}
+	})
+
+	t.Run("未認証の場合は除外条件なし", func(t *testing.T) {
+		t.Parallel()
+
+		gotExclude := []string{"sentinel"}
+		svc := newTagService(t, func(d *deps) {
+			d.tagRepo.FindByIDFn = func(ctx context.Context, id string) (*model.Tag, error) {
+				return &model.Tag{ID: id, UserID: "owner1", IsPublic: true, AddMoviePolicy: "everyone"}, nil
+			}
+			d.tagMovieRepo.ListByTagFn = func(ctx context.Context, tagID string, excludeUserIDs []string, offset, limit int) ([]repository.TagMovieWithCache, int64, error) {
+				gotExclude = excludeUserIDs
+				return []repository.TagMovieWithCache{}, 0, nil
+			}
+		})
+
+		if _, _, err := svc.ListTagMovies(context.Background(), "t1", nil, 1, 50); err != nil {
+			t.Fatalf("expected no error, got: %v", err)
+		}
+		if len(gotExclude) != 0 {
+			t.Fatalf("expected no exclude ids, got %v", gotExclude)
+		}
+	})
+}
+
 func TestTagService_FollowTag(t *testing.T) {
 	t.Parallel()
 
@@ -1120,6 +1509,23 @@ func TestTagService_FollowTag(t *testing.T) {
 		}
 	})
 
+	t.Run("作成者が退会・利用停止中: ErrTagNotFound", func(t *testing.T) {
+		t.Parallel()
+		svc := newTagService(t, func(d *deps) {
+			d.tagRepo.FindByIDFn = func(ctx context.Context, id string) (*model.Tag, error) {
+				return &model.Tag{ID: id, UserID: "owner1", IsPublic: true}, nil
+			}
+			d.tagRepo.FindByIDWithActiveOwnerFn = func(ctx context.Context, id string) (*model.Tag, error) {
+				return nil, gorm.ErrRecordNotFound
+			}
+		})
+
+		err := svc.FollowTag(context.Background(), "t1", "u1")
+		if !errors.Is(err, ErrTagNotFound) {
+			t.Fatalf("expected ErrTagNotFound, got: %v", err)
+		}
+	})
+
 	t.Run("非公開タグを作成者以外がフォロー: ErrTagPermissionDenied", func(t *testing.T) {
 		t.Parallel()
 		svc := newTagService(t, func(d *deps) {
@@ -1201,6 +1607,27 @@ func TestTagService_FollowTag(t *testing.T) {
 			t.Fatalf("unexpected args: tagID=%s userID=%s", gotTagID, gotUserID)
 		}
 	})
+
+	t.Run("成功: タグオーナーへの通知イベントをアウトボックスに書き込む", func(t *testing.T) {
+		t.Parallel()
+		var enqueued []*model.OutboxEvent
+		svc := newTagService(t, func(d *deps) {
+			d.tagRepo.FindByIDFn = func(ctx context.Context, id string) (*model.Tag, error) {
+				return &model.Tag{ID: id, UserID: "owner1", IsPublic: true}, nil
+			}
+			d.outboxRepo.EnqueueFn = func(ctx context.Context, events ...*model.OutboxEvent) error {
+				enqueued = append(enqueued, events...)
+				return nil
+			}
+		})
+
+		if err := svc.FollowTag(context.Background(), "t1", "u1"); err != nil {
+			t.Fatalf("expected no error, got: %v", err)
+		}
+		if len(enqueued) != 1 || enqueued[0].EventType != model.OutboxEventTypeTagFollowed {
+			t.Fatalf("unexpected outbox events: %+v", enqueued)
+		}
+	})
 }
 
 func TestTagService_UnfollowTag(t *testing.T) {
@@ -1707,3 +2134,182 @@ func TestTagService_ListTagsByUserID(t *testing.T) {
 		}
 	})
 }
+
+func TestTagService_GetUserTagStats(t *testing.T) {
+	t.Parallel()
+
+	t.Run("入力バリデーション: user_id が必須", func(t *testing.T) {
+		t.Parallel()
+		svc := newTagService(t, nil)
+		_, err := svc.GetUserTagStats(context.Background(), "", true)
+		if err == nil {
+			t.Fatalf("expected error")
+		}
+	})
+
+	t.Run("集計結果とジャンル・年代をまとめて返す", func(t *testing.T) {
+		t.Parallel()
+		var gotPublicOnly bool
+		var gotLimit int
+		svc := newTagService(t, func(d *deps) {
+			d.tagRepo.GetUserTagStatsFn = func(ctx context.Context, userID string, publicOnly bool) (*repository.UserTagStatsRow, error) {
+				gotPublicOnly = publicOnly
+				return &repository.UserTagStatsRow{TagCount: 2, MovieCount: 5, ContributedMovieCount: 1, LikeCount: 3, FollowerCount: 4}, nil
+			}
+			d.tagRepo.ListUserTopGenresFn = func(ctx context.Context, userID string, publicOnly bool, limit int) ([]repository.GenreCount, error) {
+				gotLimit = limit
+				return []repository.GenreCount{{Name: "ドラマ", Count: 3}}, nil
+			}
+			d.tagRepo.ListUserTopDecadesFn = func(ctx context.Context, userID string, publicOnly bool, limit int) ([]repository.DecadeCount, error) {
+				return []repository.DecadeCount{{Decade: 1990, Count: 2}}, nil
+			}
+		})
+
+		got, err := svc.GetUserTagStats(context.Background(), "u1", true)
+		if err != nil {
+			t.Fatalf("expected no error, got: %v", err)
+		}
+		if !gotPublicOnly || gotLimit != userTagStatsTopLimit {
+			t.Fatalf("unexpected args: publicOnly=%v limit=%d", gotPublicOnly, gotLimit)
+		}
+		if got.TagCount != 2 || got.MovieCount != 5 || got.ContributedMovieCount != 1 || got.LikesReceived != 3 || got.FollowsReceived != 4 {
+			t.Fatalf("unexpected stats: %+v", got)
+		}
+		if len(got.TopGenres) != 1 || got.TopGenres[0] != (GenreStat{Name: "ドラマ", MovieCount: 3}) {
+			t.Fatalf("unexpected genres: %+v", got.TopGenres)
+		}
+		if len(got.TopDecades) != 1 || got.TopDecades[0] != (DecadeStat{Decade: 1990, MovieCount: 2}) {
+			t.Fatalf("unexpected decades: %+v", got.TopDecades)
+		}
+	})
+
+	t.Run("リポジトリのエラーを返す", func(t *testing.T) {
+		t.Parallel()
+		svc := newTagService(t, func(d *deps) {
+			d.tagRepo.ListUserTopDecadesFn = func(ctx context.Context, userID string, publicOnly bool, limit int) ([]repository.DecadeCount, error) {
+				return nil, errors.New("db error")
+			}
+		})
+		if _, err := svc.GetUserTagStats(context.Background(), "u1", false); err == nil {
+			t.Fatalf("expected error")
+		}
+	})
+}
+
+func TestTagService_ListTagMovies_ViewerStatus(t *testing.T) {
+	t.Parallel()
+
+	rows := []repository.TagMovieWithCache{
+		{ID: "tm1", TagID: "t1", TmdbMovieID: 101, AddedByUser: "owner1", CreatedAt: time.Now()},
+		{ID: "tm2", TagID: "t1", TmdbMovieID: 102, AddedByUser: "owner1", CreatedAt: time.Now()},
+	}
+	watchedAt := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+
+	makeSvc := func(called *bool) TagService {
+		return newTagService(t, func(d *deps) {
+			d.tagRepo.FindByIDFn = func(ctx context.Context, id string) (*model.Tag, error) {
+				return &model.Tag{ID: id, UserID: "owner1", IsPublic: true, AddMoviePolicy: "everyone"}, nil
+			}
+			d.tagMovieRepo.ListByTagFn = func(ctx context.Context, tagID string, excludeUserIDs []string, offset, limit int) ([]repository.TagMovieWithCache, int64, error) {
+				return rows, int64(len(rows)), nil
+			}
+			d.movieStatusRepo.ListByUserAndMovieIDsFn = func(ctx context.Context, userID string, tmdbMovieIDs []int) ([]model.UserMovieStatus, error) {
+				*called = true
+				if userID != "viewer1" || len(tmdbMovieIDs) != 2 {
+					t.Fatalf("unexpected args: %s %v", userID, tmdbMovieIDs)
+				}
+				return []model.UserMovieStatus{
+					{UserID: userID, TmdbMovieID: 102, Status: model.MovieWatchStatusWatched, WatchedAt: &watchedAt},
+				}, nil
+			}
+			d.movieRatingRepo.ListByUserAndMovieIDsFn = func(ctx context.Context, userID string, tmdbMovieIDs []int) ([]model.MovieRating, error) {
+				*called = true
+				return []model.MovieRating{{UserID: userID, TmdbMovieID: 101, Rating: 4.5}}, nil
+			}
+		})
+	}
+
+	t.Run("ログイン中: 視聴ステータスと評価を埋め込む", func(t *testing.T) {
+		t.Parallel()
+
+		called := false
+		viewer := "viewer1"
+		out, _, err := makeSvc(&called).ListTagMovies(context.Background(), "t1", &viewer, 1, 50)
+		if err != nil {
+			t.Fatalf("expected no error, got: %v", err)
+		}
+		if out[0].ViewerStatus != nil {
+			t.Fatalf("expected no status for 101, got: %+v", out[0].ViewerStatus)
+		}
+		if out[0].ViewerRating == nil || *out[0].ViewerRating != 4.5 || out[1].ViewerRating != nil {
+			t.Fatalf("unexpected viewer ratings: %+v", out)
+		}
+		got := out[1].ViewerStatus
+		if got == nil || got.Status != model.MovieWatchStatusWatched || got.WatchedAt == nil || *got.WatchedAt != "2024-05-01" {
+			t.Fatalf("unexpected status for 102: %+v", got)
+		}
+	})
+
+	t.Run("未認証: 視聴ステータスと評価を取得しない", func(t *testing.T) {
+		t.Parallel()
+
+		called := false
+		out, _, err := makeSvc(&called).ListTagMovies(context.Background(), "t1", nil, 1, 50)
+		if err != nil {
+			t.Fatalf("expected no error, got: %v", err)
+		}
+		if called {
+			t.Fatalf("ListByUserAndMovieIDs should not be called")
+		}
+		if out[0].ViewerStatus != nil || out[1].ViewerStatus != nil {
+			t.Fatalf("expected no viewer status, got: %+v", out)
+		}
+	})
+}
+
+func TestTagService_GetTagDetail_ViewerProgress(t *testing.T) {
+	t.Parallel()
+
+	makeSvc := func() TagService {
+		return newTagService(t, func(d *deps) {
+			d.tagRepo.FindDetailByIDFn = func(ctx context.Context, id string) (*repository.TagDetailRow, error) {
+				return &repository.TagDetailRow{
+					ID:             id,
+					Title:          "Test Tag",
+					IsPublic:       true,
+					AddMoviePolicy: "everyone",
+					OwnerID:        "owner1",
+					MovieCount:     40,
+				}, nil
+			}
+			d.movieStatusRepo.CountWatchedInTagFn = func(ctx context.Context, userID, tagID string) (int64, error) {
+				return 12, nil
+			}
+		})
+	}
+
+	t.Run("ログイン中: 観た本数とタグ内の映画数を返す", func(t *testing.T) {
+		t.Parallel()
+
+		viewer := "viewer1"
+		out, err := makeSvc().GetTagDetail(context.Background(), "t1", &viewer)
+		if err != nil {
+			t.Fatalf("expected no error, got: %v", err)
+		}
+		if out.ViewerProgress == nil || out.ViewerProgress.WatchedCount != 12 || out.ViewerProgress.MovieCount != 40 {
+			t.Fatalf("unexpected progress: %+v", out.ViewerProgress)
+		}
+	})
+
+	t.Run("未認証: 進捗を返さない", func(t *testing.T) {
+		t.Parallel()
+
+		out, err := makeSvc().GetTagDetail(context.Background(), "t1", nil)
+		if err != nil {
+			t.Fatalf("expected no error, got: %v", err)
+		}
+		if out.ViewerProgress != nil {
+			t.Fatalf("expected no progress, got: %+v", out.ViewerProgress)
+		}
+	})
+}
@@ -0,0 +1,478 @@
+package service
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"cinetag-backend/src/internal/model"
+	"cinetag-backend/src/internal/repository"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// エクスポートが見つからない（または他ユーザーのエクスポート）場合のエラー。
+var ErrExportNotFound = errors.New("export not found")
+
+// エクスポートの保存期限が切れている場合のエラー。
+var ErrExportExpired = errors.New("export has expired")
+
+// エクスポート形式が不正な場合のエラー。
+var ErrInvalidExportFormat = errors.New("format must be 'json' or 'zip'")
+
+// エクスポート生成処理（アウトボックスの配信1件）のタイムアウト。
+const UserDataExportTimeout = 5 * time.Minute
+
+const (
+	// 生成したエクスポートファイルの保存期間。
+	userDataExportRetention = 7 * 24 * time.Hour
+	// 未完了のまま残ったエクスポートを失敗とみなすまでの時間。
+	// 生成処理が失敗し続けた場合などに、ユーザーが新しいエクスポートを要求できなくなるのを防ぐ。
+	userDataExportStaleAfter = 3 * UserDataExportTimeout
+	// 中断したエクスポートを失敗にする際のエラーメッセージ。
+	userDataExportTimedOutMessage = "export generation timed out"
+)
+
+// エクスポートファイルに含めるユーザープロフィール。
+type ExportProfile struct {
+	ID          string    `json:"id"`
+	DisplayID   string    `json:"display_id"`
+	DisplayName string    `json:"display_name"`
+	Email       string    `json:"email"`
+	AvatarURL   *string   `json:"avatar_url"`
+	Bio         *string   `json:"bio"`
+	IsPrivate   bool      `json:"is_private"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// エクスポートファイルに含めるタグ内の映画。
+type ExportTagMovie struct {
+	TagID         string    `json:"tag_id"`
+	TagTitle      string    `json:"tag_title"`
+	TmdbMovieID   int       `json:"tmdb_movie_id"`
+	MovieTitle    *string   `json:"movie_title"`
+	Note          *string   `json:"note"`
+	Position      int       `json:"position"`
+	AddedByUserID string    `json:"added_by_user_id"`
+	AddedAt       time.Time `json:"added_at"`
+}
+
+// エクスポートファイルに含める自分のタグ（タグ内の映画を含む）。
+type ExportTag struct {
+	ID             string           `json:"id"`
+	Title          string           `json:"title"`
+	Description    *string          `json:"description"`
+	CoverImageURL  *string          `json:"cover_image_url"`
+	IsPublic       bool             `json:"is_public"`
+	AddMoviePolicy string           `json:"add_movie_policy"`
+	CreatedAt      time.Time        `json:"created_at"`
+	UpdatedAt      time.Time        `json:"updated_at"`
+	Movies         []ExportTagMovie `json:"movies"`
+}
+
+// エクスポートファイルに含めるユーザーとの関係（フォロー・フォロワー）。
+type ExportUserRelation struct {
+	UserID      string    `json:"user_id"`
+	DisplayID   string    `json:"display_id"`
+	DisplayName string    `json:"display_name"`
+	Since       time.Time `json:"since"`
+}
+
+// エクスポートファイルに含めるタグとの関係（フォロー・いいね）。
+type ExportTagRelation struct {
+	TagID    string    `json:"tag_id"`
+	TagTitle string    `json:"tag_title"`
+	Since    time.Time `json:"since"`
+}
+
+// エクスポートファイルに含めるフォロー関係一式。
+type ExportFollows struct {
+	Following     []ExportUserRelation `json:"following"`
+	Followers     []ExportUserRelation `json:"followers"`
+	FollowingTags []ExportTagRelation  `json:"following_tags"`
+}
+
+// エクスポートファイルに含める視聴ステータス。
+type ExportMovieStatus struct {
+	TmdbMovieID int       `json:"tmdb_movie_id"`
+	MovieTitle  *string   `json:"movie_title"`
+	Status      string    `json:"status"`
+	WatchedAt   *string   `json:"watched_at"` // YYYY-MM-DD
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// エクスポートファイルに含める映画の評価・レビュー。
+type ExportMovieRating struct {
+	TmdbMovieID int       `json:"tmdb_movie_id"`
+	MovieTitle  *string   `json:"movie_title"`
+	Rating      float64   `json:"rating"`
+	Review      *string   `json:"review"`
+	IsSpoiler   bool      `json:"is_spoiler"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// エクスポートファイルの内容（JSON 形式ではこの構造体がそのまま出力される）。
+type UserDataExportBundle struct {
+	ExportedAt    time.Time            `json:"exported_at"`
+	Profile       ExportProfile        `json:"profile"`
+	Tags          []ExportTag          `json:"tags"`
+	Contributions []ExportTagMovie     `json:"contributions"`
+	Follows       ExportFollows        `json:"follows"`
+	LikedTags     []ExportTagRelation  `json:"liked_tags"`
+	Notifications []model.Notification `json:"notifications"`
+	MovieStatuses []ExportMovieStatus  `json:"movie_statuses"`
+	MovieRatings  []ExportMovieRating  `json:"movie_ratings"`
+}
+
+// 個人データエクスポートに関するユースケースを表すインターフェース。
+type UserDataExportService interface {
+	// エクスポートを要求し、ファイルの生成をアウトボックスに積む。
+	// - 未完了のエクスポートが既にある場合（同時に要求された場合を含む）は、新規作成せずにそれを返す。
+	// - format は "json" または "zip"（空文字の場合は "zip"）。
+	RequestExport(ctx context.Context, userID, format string) (*model.UserDataExport, error)
+
+	// エクスポートファイルを生成する（アウトボックスのディスパッチャーから呼ばれる）。
+	// - 削除されたエクスポートや、既に完了・失敗したエクスポートは何もしない（再配信されても二重に生成しない）。
+	// - 生成に失敗した場合はエラーを返し、ディスパッチャーがリトライする（失敗し続けた場合は一定時間後に失敗として扱う）。
+	GenerateExport(ctx context.Context, exportID string) error
+
+	// エクスポートを取得する（生成済みファイルを含む）。
+	// - 他ユーザーのエクスポートは ErrExportNotFound を返す。
+	// - 保存期限切れの場合は ErrExportExpired を返す。
+	GetExport(ctx context.Context, userID, exportID string) (*model.UserDataExport, error)
+}
+
+type userDataExportService struct {
+	logger     *slog.Logger
+	db         *gorm.DB
+	exportRepo repository.UserDataExportRepository
+	outboxRepo repository.OutboxRepository
+}
+
+// UserDataExportService の実装を生成する。
+// db が nil の場合（ユニットテスト）はトランザクションを張らずに exportRepo / outboxRepo を直接使う。
+func NewUserDataExportService(logger *slog.Logger, db *gorm.DB, exportRepo repository.UserDataExportRepository, outboxRepo repository.OutboxRepository) UserDataExportService {
+	return &userDataExportService{
+		logger:     logger,
+		db:         db,
+		exportRepo: exportRepo,
+		outboxRepo: outboxRepo,
+	}
+}
+
+// エクスポートを要求し、ファイルの生成をアウトボックスに積む。
+func (s *userDataExportService) RequestExport(ctx context.Context, userID, format string) (*model.UserDataExport, error) {
+	s.logger.Debug("service.RequestExport started",
+		slog.String("user_id", userID),
+		slog.String("format", format),
+	)
+	if userID == "" {
+		return nil, errors.New("user_id is required")
+	}
+
+	format = strings.ToLower(strings.TrimSpace(format))
+	if format == "" {
+		format = model.UserDataExportFormatZIP
+	}
+	if format != model.UserDataExportFormatJSON && format != model.UserDataExportFormatZIP {
+		return nil, ErrInvalidExportFormat
+	}
+
+	// 未完了のエクスポートがあればそれを返す
+	inProgress, err := s.exportRepo.FindInProgressByUserID(ctx, userID)
+	switch {
+	case err == nil:
+		if time.Since(inProgress.CreatedAt) < userDataExportStaleAfter {
+			return inProgress, nil
+		}
+		// 生成処理が中断したまま残ったエクスポートは失敗にして、新しく作り直す
+		if err := s.exportRepo.MarkFailed(ctx, inProgress.ID, userDataExportTimedOutMessage); err != nil {
+			return nil, err
+		}
+	case !errors.Is(err, gorm.ErrRecordNotFound):
+		return nil, err
+	}
+
+	export := &model.UserDataExport{
+		UserID: userID,
+		Format: format,
+		Status: model.UserDataExportStatusPending,
+	}
+	// エクスポートの作成とファイル生成イベントを同じトランザクションで書き込み、
+	// デプロイなどでプロセスが止まっても生成処理が失われないようにする
+	err = s.withTx(ctx, func(exportRepo repository.UserDataExportRepository, outboxRepo repository.OutboxRepository) error {
+		if err := exportRepo.Create(ctx, export); err != nil {
+			return err
+		}
+		return enqueueUserDataExportEvent(ctx, outboxRepo, export.ID)
+	})
+	if err != nil {
+		// 同時に要求されて未完了のエクスポートが先に作成された場合は、そのエクスポートを返す
+		if repository.IsUniqueViolation(err) {
+			return s.exportRepo.FindInProgressByUserID(ctx, userID)
+		}
+		return nil, err
+	}
+
+	return export, nil
+}
+
+// エクスポートファイルを生成する。
+func (s *userDataExportService) GenerateExport(ctx context.Context, exportID string) error {
+	export, err := s.exportRepo.FindByID(ctx, exportID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		return err
+	}
+	if export.Status != model.UserDataExportStatusPending && export.Status != model.UserDataExportStatusProcessing {
+		return nil
+	}
+
+	err = s.generateExport(ctx, export.ID, export.UserID, export.Format)
+	// 生成中に中断したものとして失敗にされた場合は、生成したファイルを保存しない
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		s.logger.Info("service.GenerateExport skipped export no longer in progress",
+			slog.String("export_id", export.ID),
+		)
+		return nil
+	}
+	return err
+}
+
+// エクスポートを取得する。
+func (s *userDataExportService) GetExport(ctx context.Context, userID, exportID string) (*model.UserDataExport, error) {
+	if userID == "" || exportID == "" {
+		return nil, ErrExportNotFound
+	}
+	if _, err := uuid.Parse(exportID); err != nil {
+		return nil, ErrExportNotFound
+	}
+
+	export, err := s.exportRepo.FindByID(ctx, exportID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrExportNotFound
+		}
+		return nil, err
+	}
+	if export.UserID != userID {
+		return nil, ErrExportNotFound
+	}
+	if export.ExpiresAt != nil && time.Now().After(*export.ExpiresAt) {
+		return nil, ErrExportExpired
+	}
+
+	return export, nil
+}
+
+// fn をトランザクション内で実行する。db が nil の場合はそのまま実行する。
+func (s *userDataExportService) withTx(ctx context.Context, fn func(exportRepo repository.UserDataExportRepository, outboxRepo repository.OutboxRepository) error) error {
+	if s.db == nil {
+		return fn(s.exportRepo, s.outboxRepo)
+	}
+	return s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var outboxRepo repository.OutboxRepository
+		if s.outboxRepo != nil {
+			outboxRepo = repository.NewOutboxRepository(tx)
+		}
+		return fn(repository.NewUserDataExportRepository(tx), outboxRepo)
+	})
+}
+
+// エクスポート対象データを収集してファイルを生成し、保存する。
+func (s *userDataExportService) generateExport(ctx context.Context, exportID, userID, format string) error {
+	if err := s.exportRepo.MarkProcessing(ctx, exportID); err != nil {
+		return err
+	}
+
+	snapshot, err := s.exportRepo.LoadSnapshot(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	bundle := buildUserDataExportBundle(snapshot, now)
+
+	var content []byte
+	if format == model.UserDataExportFormatJSON {
+		content, err = json.MarshalIndent(bundle, "", "  ")
+	} else {
+		content, err = encodeUserDataExportZIP(bundle)
+	}
+	if err != nil {
+		return err
+	}
+
+	fileName := fmt.Sprintf("cinetag-export-%s-%s.%s", snapshot.User.DisplayID, now.Format("20060102"), format)
+	return s.exportRepo.MarkCompleted(ctx, exportID, fileName, content, now, now.Add(userDataExportRetention))
+}
+
+// 取得したデータからエクスポートファイルの内容を組み立てる。
+// - 自分のタグ内の映画は各タグの movies に、他ユーザーのタグに追加した映画は contributions に振り分ける。
+func buildUserDataExportBundle(snapshot *repository.UserDataSnapshot, exportedAt time.Time) *UserDataExportBundle {
+	u := snapshot.User
+	bundle := &UserDataExportBundle{
+		ExportedAt: exportedAt,
+		Profile: ExportProfile{
+			ID:          u.ID,
+			DisplayID:   u.DisplayID,
+			DisplayName: u.DisplayName,
+			Email:       u.Email,
+			AvatarURL:   u.AvatarURL,
+			Bio:         u.Bio,
+			IsPrivate:   u.IsPrivate,
+			CreatedAt:   u.CreatedAt,
+		},
+		Tags:          make([]ExportTag, 0, len(snapshot.Tags)),
+		Contributions: []ExportTagMovie{},
+		Follows: ExportFollows{
+			Following:     toExportUserRelations(snapshot.Following),
+			Followers:     toExportUserRelations(snapshot.Followers),
+			FollowingTags: toExportTagRelations(snapshot.FollowingTags),
+		},
+		LikedTags:     toExportTagRelations(snapshot.LikedTags),
+		Notifications: snapshot.Notifications,
+		MovieStatuses: make([]ExportMovieStatus, 0, len(snapshot.MovieStatuses)),
+		MovieRatings:  make([]ExportMovieRating, 0, len(snapshot.MovieRatings)),
+	}
+	if bundle.Notifications == nil {
+		bundle.Notifications = []model.Notification{}
+	}
+
+	for _, row := range snapshot.MovieStatuses {
+		bundle.MovieStatuses = append(bundle.MovieStatuses, ExportMovieStatus{
+			TmdbMovieID: row.TmdbMovieID,
+			MovieTitle:  row.MovieTitle,
+			Status:      row.Status,
+			WatchedAt:   formatDate(row.WatchedAt),
+			UpdatedAt:   row.UpdatedAt,
+		})
+	}
+
+	for _, row := range snapshot.MovieRatings {
+		bundle.MovieRatings = append(bundle.MovieRatings, ExportMovieRating{
+			TmdbMovieID: row.TmdbMovieID,
+			MovieTitle:  row.MovieTitle,
+			Rating:      row.Rating,
+			Review:      row.Review,
+			IsSpoiler:   row.IsSpoiler,
+			CreatedAt:   row.CreatedAt,
+			UpdatedAt:   row.UpdatedAt,
+		})
+	}
+
+	moviesByTag := make(map[string][]ExportTagMovie)
+	for _, row := range snapshot.TagMovies {
+		m := ExportTagMovie{
+			TagID:         row.TagID,
+			TagTitle:      row.TagTitle,
+			TmdbMovieID:   row.TmdbMovieID,
+			MovieTitle:    row.MovieTitle,
+			Note:          row.Note,
+			Position:      row.Position,
+			AddedByUserID: row.AddedByUser,
+			AddedAt:       row.CreatedAt,
+		}
+		if row.TagOwnerID == u.ID {
+			moviesByTag[row.TagID] = append(moviesByTag[row.TagID], m)
+		} else {
+			bundle.Contributions = append(bundle.Contributions, m)
+		}
+	}
+
+	for _, t := range snapshot.Tags {
+		movies := moviesByTag[t.ID]
+		if movies == nil {
+			movies = []ExportTagMovie{}
+		}
+		bundle.Tags = append(bundle.Tags, ExportTag{
+			ID:             t.ID,
+			Title:          t.Title,
+			Description:    t.Description,
+			CoverImageURL:  t.CoverImageURL,
+			IsPublic:       t.IsPublic,
+			AddMoviePolicy: t.AddMoviePolicy,
+			CreatedAt:      t.CreatedAt,
+			UpdatedAt:      t.UpdatedAt,
+			Movies:         movies,
+		})
+	}
+
+	return bundle
+}
+
+func toExportUserRelations(rows []repository.ExportUserRelationRow) []ExportUserRelation {
+	out := make([]ExportUserRelation, 0, len(rows))
+	for _, r := range rows {
+		out = append(out, ExportUserRelation{
+			UserID:      r.UserID,
+			DisplayID:   r.DisplayID,
+			DisplayName: r.DisplayName,
+			Since:       r.CreatedAt,
+		})
+	}
+	return out
+}
+
+func toExportTagRelations(rows []repository.ExportTagRelationRow) []ExportTagRelation {
+	out := make([]ExportTagRelation, 0, len(rows))
+	for _, r := range rows {
+		out = append(out, ExportTagRelation{
+			TagID:    r.TagID,
+			TagTitle: r.TagTitle,
+			Since:    r.CreatedAt,
+		})
+	}
+	return out
+}
+
+// エクスポートファイルの内容を、項目ごとの JSON ファイルを含む ZIP に変換する。
+func encodeUserDataExportZIP(bundle *UserDataExportBundle) ([]byte, error) {
+	files := []struct {
+		name string
+		body any
+	}{
+		{name: "profile.json", body: bundle.Profile},
+		{name: "tags.json", body: bundle.Tags},
+		{name: "contributions.json", body: bundle.Contributions},
+		{name: "follows.json", body: bundle.Follows},
+		{name: "liked_tags.json", body: bundle.LikedTags},
+		{name: "notifications.json", body: bundle.Notifications},
+		{name: "movie_statuses.json", body: bundle.MovieStatuses},
+		{name: "movie_ratings.json", body: bundle.MovieRatings},
+	}
+
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for _, f := range files {
+		w, err := zw.CreateHeader(&zip.FileHeader{
+			Name:     f.name,
+			Method:   zip.Deflate,
+			Modified: bundle.ExportedAt,
+		})
+		if err != nil {
+			return nil, err
+		}
+		b, err := json.MarshalIndent(f.body, "", "  ")
+		if err != nil {
+			return nil, err
+		}
+		if _, err := w.Write(b); err != nil {
+			return nil, err
+		}
+	}
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
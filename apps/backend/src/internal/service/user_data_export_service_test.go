@@ -0,0 +1,420 @@
+package service
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"cinetag-backend/src/internal/model"
+	"cinetag-backend/src/internal/repository"
+	"cinetag-backend/src/internal/testutil"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"gorm.io/gorm"
+)
+
+// fakeUserDataExportRepo は UserDataExportRepository の fake 実装です。
+type fakeUserDataExportRepo struct {
+	CreateFn                 func(ctx context.Context, export *model.UserDataExport) error
+	FindByIDFn               func(ctx context.Context, id string) (*model.UserDataExport, error)
+	FindInProgressByUserIDFn func(ctx context.Context, userID string) (*model.UserDataExport, error)
+	MarkCompletedFn          func(ctx context.Context, id, fileName string, content []byte, completedAt, expiresAt time.Time) error
+	LoadSnapshotFn           func(ctx context.Context, userID string) (*repository.UserDataSnapshot, error)
+	MarkFailedFn             func(ctx context.Context, id, message string) error
+	FailStaleFn              func(ctx context.Context, createdBefore time.Time, message string) (int64, error)
+	DeleteExpiredBeforeFn    func(ctx context.Context, now time.Time, limit int) (int64, error)
+}
+
+func (f *fakeUserDataExportRepo) Create(ctx context.Context, export *model.UserDataExport) error {
+	if f.CreateFn == nil {
+		export.ID = "export-1"
+		return nil
+	}
+	return f.CreateFn(ctx, export)
+}
+
+func (f *fakeUserDataExportRepo) FindByID(ctx context.Context, id string) (*model.UserDataExport, error) {
+	if f.FindByIDFn == nil {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return f.FindByIDFn(ctx, id)
+}
+
+func (f *fakeUserDataExportRepo) FindInProgressByUserID(ctx context.Context, userID string) (*model.UserDataExport, error) {
+	if f.FindInProgressByUserIDFn == nil {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return f.FindInProgressByUserIDFn(ctx, userID)
+}
+
+func (f *fakeUserDataExportRepo) MarkProcessing(ctx context.Context, id string) error {
+	return nil
+}
+
+func (f *fakeUserDataExportRepo) MarkCompleted(ctx context.Context, id, fileName string, content []byte, completedAt, expiresAt time.Time) error {
+	if f.MarkCompletedFn == nil {
+		return nil
+	}
+	return f.MarkCompletedFn(ctx, id, fileName, content, completedAt, expiresAt)
+}
+
+func (f *fakeUserDataExportRepo) MarkFailed(ctx context.Context, id, message string) error {
+	if f.MarkFailedFn == nil {
+		return nil
+	}
+	return f.MarkFailedFn(ctx, id, message)
+}
+
+func (f *fakeUserDataExportRepo) FailStale(ctx context.Context, createdBefore time.Time, message string) (int64, error) {
+	if f.FailStaleFn == nil {
+		return 0, nil
+	}
+	return f.FailStaleFn(ctx, createdBefore, message)
+}
+
+func (f *fakeUserDataExportRepo) DeleteExpiredBefore(ctx context.Context, now time.Time, limit int) (int64, error) {
+	if f.DeleteExpiredBeforeFn == nil {
+		return 0, nil
+	}
+	return f.DeleteExpiredBeforeFn(ctx, now, limit)
+}
+
+func (f *fakeUserDataExportRepo) LoadSnapshot(ctx context.Context, userID string) (*repository.UserDataSnapshot, error) {
+	if f.LoadSnapshotFn == nil {
+		return nil, errors.New("not implemented")
+	}
+	return f.LoadSnapshotFn(ctx, userID)
+}
+
+func newTestUserDataSnapshot() *repository.UserDataSnapshot {
+	now := time.Now()
+	note := "名作"
+	return &repository.UserDataSnapshot{
+		User: &model.User{ID: "u1", DisplayID: "user1", DisplayName: "User1", Email: "u1@example.com"},
+		Tags: []model.Tag{
+			{ID: "t1", UserID: "u1", Title: "My Tag", IsPublic: true, AddMoviePolicy: "everyone"},
+		},
+		TagMovies: []repository.ExportTagMovieRow{
+			{ID: "tm1", TagID: "t1", TagTitle: "My Tag", TagOwnerID: "u1", TmdbMovieID: 100, AddedByUser: "u1", Note: &note, CreatedAt: now},
+			{ID: "tm2", TagID: "t1", TagTitle: "My Tag", TagOwnerID: "u1", TmdbMovieID: 101, AddedByUser: "u2", CreatedAt: now},
+			{ID: "tm3", TagID: "t9", TagTitle: "Other Tag", TagOwnerID: "u2", TmdbMovieID: 200, AddedByUser: "u1", CreatedAt: now},
+		},
+		Following: []repository.ExportUserRelationRow{
+			{UserID: "u2", DisplayID: "user2", DisplayName: "User2", CreatedAt: now},
+		},
+		LikedTags: []repository.ExportTagRelationRow{
+			{TagID: "t9", TagTitle: "Other Tag", CreatedAt: now},
+		},
+	}
+}
+
+func TestUserDataExportService_RequestExport(t *testing.T) {
+	t.Parallel()
+
+	t.Run("不正な形式: ErrInvalidExportFormat", func(t *testing.T) {
+		t.Parallel()
+		svc := NewUserDataExportService(testutil.NewTestLogger(), nil, &fakeUserDataExportRepo{}, nil)
+
+		_, err := svc.RequestExport(context.Background(), "u1", "csv")
+		if !errors.Is(err, ErrInvalidExportFormat) {
+			t.Fatalf("expected ErrInvalidExportFormat, got: %v", err)
+		}
+	})
+
+	t.Run("未完了のエクスポートがある: 既存のエクスポートを返す", func(t *testing.T) {
+		t.Parallel()
+		createCalled := false
+		repo := &fakeUserDataExportRepo{
+			FindInProgressByUserIDFn: func(ctx context.Context, userID string) (*model.UserDataExport, error) {
+				return &model.UserDataExport{ID: "existing", UserID: userID, Status: model.UserDataExportStatusProcessing, CreatedAt: time.Now()}, nil
+			},
+			CreateFn: func(ctx context.Context, export *model.UserDataExport) error {
+				createCalled = true
+				return nil
+			},
+		}
+		svc := NewUserDataExportService(testutil.NewTestLogger(), nil, repo, nil)
+
+		export, err := svc.RequestExport(context.Background(), "u1", "json")
+		if err != nil {
+			t.Fatalf("expected no error, got: %v", err)
+		}
+		if export.ID != "existing" {
+			t.Fatalf("expected existing export, got: %s", export.ID)
+		}
+		if createCalled {
+			t.Fatalf("expected Create not to be called")
+		}
+	})
+
+	t.Run("中断したまま残ったエクスポートがある: 失敗にして新しく作成する", func(t *testing.T) {
+		t.Parallel()
+		var failedID string
+		repo := &fakeUserDataExportRepo{
+			FindInProgressByUserIDFn: func(ctx context.Context, userID string) (*model.UserDataExport, error) {
+				return &model.UserDataExport{ID: "stale", UserID: userID, Status: model.UserDataExportStatusProcessing, CreatedAt: time.Now().Add(-time.Hour)}, nil
+			},
+			MarkFailedFn: func(ctx context.Context, id, message string) error {
+				failedID = id
+				return nil
+			},
+		}
+		svc := NewUserDataExportService(testutil.NewTestLogger(), nil, repo, nil)
+
+		export, err := svc.RequestExport(context.Background(), "u1", "json")
+		if err != nil {
+			t.Fatalf("expected no error, got: %v", err)
+		}
+		if failedID != "stale" {
+			t.Fatalf("expected stale export to be marked as failed, got: %q", failedID)
+		}
+		if export.ID != "export-1" {
+			t.Fatalf("expected new export, got: %s", export.ID)
+		}
+	})
+
+	t.Run("成功: 形式省略時は zip で作成される", func(t *testing.T) {
+		t.Parallel()
+		var created *model.UserDataExport
+		repo := &fakeUserDataExportRepo{
+			CreateFn: func(ctx context.Context, export *model.UserDataExport) error {
+				export.ID = "export-1"
+				created = export
+				return nil
+			},
+		}
+		svc := NewUserDataExportService(testutil.NewTestLogger(), nil, repo, nil)
+
+		export, err := svc.RequestExport(context.Background(), "u1", "")
+		if err != nil {
+			t.Fatalf("expected no error, got: %v", err)
+		}
+		if created == nil || export.ID != "export-1" {
+			t.Fatalf("expected export to be created")
+		}
+		if created.Format != model.UserDataExportFormatZIP || created.Status != model.UserDataExportStatusPending {
+			t.Fatalf("unexpected export: format=%s status=%s", created.Format, created.Status)
+		}
+	})
+
+	t.Run("同時に要求されて未完了のエクスポートが作成済み: 既存のエクスポートを返す", func(t *testing.T) {
+		t.Parallel()
+		findCalls := 0
+		repo := &fakeUserDataExportRepo{
+			FindInProgressByUserIDFn: func(ctx context.Context, userID string) (*model.UserDataExport, error) {
+				findCalls++
+				if findCalls == 1 {
+					return nil, gorm.ErrRecordNotFound
+				}
+				return &model.UserDataExport{ID: "existing", UserID: userID, Status: model.UserDataExportStatusPending, CreatedAt: time.Now()}, nil
+			},
+			CreateFn: func(ctx context.Context, export *model.UserDataExport) error {
+				return &pgconn.PgError{Code: "23505"}
+			},
+		}
+		svc := NewUserDataExportService(testutil.NewTestLogger(), nil, repo, nil)
+
+		export, err := svc.RequestExport(context.Background(), "u1", "json")
+		if err != nil {
+			t.Fatalf("expected no error, got: %v", err)
+		}
+		if export.ID != "existing" {
+			t.Fatalf("expected existing export, got: %s", export.ID)
+		}
+	})
+
+	t.Run("成功: 作成と同時にファイル生成イベントを積む", func(t *testing.T) {
+		t.Parallel()
+		var enqueued []*model.OutboxEvent
+		outboxRepo := &testutil.FakeOutboxRepository{
+			EnqueueFn: func(ctx context.Context, events ...*model.OutboxEvent) error {
+				enqueued = append(enqueued, events...)
+				return nil
+			},
+		}
+		svc := NewUserDataExportService(testutil.NewTestLogger(), nil, &fakeUserDataExportRepo{}, outboxRepo)
+
+		if _, err := svc.RequestExport(context.Background(), "u1", "json"); err != nil {
+			t.Fatalf("expected no error, got: %v", err)
+		}
+		if len(enqueued) != 1 || enqueued[0].EventType != model.OutboxEventTypeUserDataExportRequested {
+			t.Fatalf("expected export event to be enqueued, got: %+v", enqueued)
+		}
+		var p model.UserDataExportEventPayload
+		if err := json.Unmarshal(enqueued[0].Payload, &p); err != nil || p.ExportID != "export-1" {
+			t.Fatalf("unexpected payload: %s", enqueued[0].Payload)
+		}
+	})
+}
+
+func TestUserDataExportService_GetExport(t *testing.T) {
+	t.Parallel()
+
+	t.Run("存在しない: ErrExportNotFound", func(t *testing.T) {
+		t.Parallel()
+		svc := NewUserDataExportService(testutil.NewTestLogger(), nil, &fakeUserDataExportRepo{}, nil)
+
+		_, err := svc.GetExport(context.Background(), "u1", "11111111-1111-1111-1111-111111111111")
+		if !errors.Is(err, ErrExportNotFound) {
+			t.Fatalf("expected ErrExportNotFound, got: %v", err)
+		}
+	})
+
+	t.Run("UUID 形式でない ID: ErrExportNotFound", func(t *testing.T) {
+		t.Parallel()
+		findCalled := false
+		repo := &fakeUserDataExportRepo{
+			FindByIDFn: func(ctx context.Context, id string) (*model.UserDataExport, error) {
+				findCalled = true
+				return nil, errors.New("invalid input syntax for type uuid")
+			},
+		}
+		svc := NewUserDataExportService(testutil.NewTestLogger(), nil, repo, nil)
+
+		_, err := svc.GetExport(context.Background(), "u1", "not-a-uuid")
+		if !errors.Is(err, ErrExportNotFound) {
+			t.Fatalf("expected ErrExportNotFound, got: %v", err)
+		}
+		if findCalled {
+			t.Fatalf("expected FindByID not to be called")
+		}
+	})
+
+	t.Run("他ユーザーのエクスポート: ErrExportNotFound", func(t *testing.T) {
+		t.Parallel()
+		repo := &fakeUserDataExportRepo{
+			FindByIDFn: func(ctx context.Context, id string) (*model.UserDataExport, error) {
+				return &model.UserDataExport{ID: id, UserID: "u2"}, nil
+			},
+		}
+		svc := NewUserDataExportService(testutil.NewTestLogger(), nil, repo, nil)
+
+		_, err := svc.GetExport(context.Background(), "u1", "11111111-1111-1111-1111-111111111111")
+		if !errors.Is(err, ErrExportNotFound) {
+			t.Fatalf("expected ErrExportNotFound, got: %v", err)
+		}
+	})
+
+	t.Run("保存期限切れ: ErrExportExpired", func(t *testing.T) {
+		t.Parallel()
+		expired := time.Now().Add(-time.Hour)
+		repo := &fakeUserDataExportRepo{
+			FindByIDFn: func(ctx context.Context, id string) (*model.UserDataExport, error) {
+				return &model.UserDataExport{ID: id, UserID: "u1", Status: model.UserDataExportStatusCompleted, ExpiresAt: &expired}, nil
+			},
+		}
+		svc := NewUserDataExportService(testutil.NewTestLogger(), nil, repo, nil)
+
+		_, err := svc.GetExport(context.Background(), "u1", "11111111-1111-1111-1111-111111111111")
+		if !errors.Is(err, ErrExportExpired) {
+			t.Fatalf("expected ErrExportExpired, got: %v", err)
+		}
+	})
+}
+
+func TestUserDataExportService_GenerateExport(t *testing.T) {
+	t.Parallel()
+
+	t.Run("JSON: 自分のタグと他ユーザーのタグへの追加が振り分けられる", func(t *testing.T) {
+		t.Parallel()
+		var gotFileName string
+		var gotContent []byte
+		repo := &fakeUserDataExportRepo{
+			LoadSnapshotFn: func(ctx context.Context, userID string) (*repository.UserDataSnapshot, error) {
+				return newTestUserDataSnapshot(), nil
+			},
+			MarkCompletedFn: func(ctx context.Context, id, fileName string, content []byte, completedAt, expiresAt time.Time) error {
+				gotFileName = fileName
+				gotContent = content
+				return nil
+			},
+		}
+		svc := NewUserDataExportService(testutil.NewTestLogger(), nil, repo, nil).(*userDataExportService)
+
+		if err := svc.generateExport(context.Background(), "e1", "u1", model.UserDataExportFormatJSON); err != nil {
+			t.Fatalf("expected no error, got: %v", err)
+		}
+		if !strings.HasSuffix(gotFileName, ".json") {
+			t.Fatalf("unexpected file name: %s", gotFileName)
+		}
+
+		var bundle UserDataExportBundle
+		if err := json.Unmarshal(gotContent, &bundle); err != nil {
+			t.Fatalf("failed to unmarshal bundle: %v", err)
+		}
+		if bundle.Profile.DisplayID != "user1" {
+			t.Fatalf("unexpected profile: %+v", bundle.Profile)
+		}
+		if len(bundle.Tags) != 1 || len(bundle.Tags[0].Movies) != 2 {
+			t.Fatalf("expected 1 tag with 2 movies, got: %+v", bundle.Tags)
+		}
+		if len(bundle.Contributions) != 1 || bundle.Contributions[0].TagID != "t9" {
+			t.Fatalf("expected 1 contribution to t9, got: %+v", bundle.Contributions)
+		}
+		if len(bundle.Follows.Following) != 1 || len(bundle.LikedTags) != 1 {
+			t.Fatalf("unexpected follows/likes: %+v %+v", bundle.Follows, bundle.LikedTags)
+		}
+	})
+
+	t.Run("完了済みのエクスポート: 再配信されても生成しない", func(t *testing.T) {
+		t.Parallel()
+		repo := &fakeUserDataExportRepo{
+			FindByIDFn: func(ctx context.Context, id string) (*model.UserDataExport, error) {
+				return &model.UserDataExport{ID: id, UserID: "u1", Format: model.UserDataExportFormatJSON, Status: model.UserDataExportStatusCompleted}, nil
+			},
+			LoadSnapshotFn: func(ctx context.Context, userID string) (*repository.UserDataSnapshot, error) {
+				t.Fatalf("expected LoadSnapshot not to be called")
+				return nil, nil
+			},
+		}
+		svc := NewUserDataExportService(testutil.NewTestLogger(), nil, repo, nil)
+
+		if err := svc.GenerateExport(context.Background(), "e1"); err != nil {
+			t.Fatalf("expected no error, got: %v", err)
+		}
+	})
+
+	t.Run("ZIP: 項目ごとの JSON ファイルが含まれる", func(t *testing.T) {
+		t.Parallel()
+		var gotContent []byte
+		repo := &fakeUserDataExportRepo{
+			LoadSnapshotFn: func(ctx context.Context, userID string) (*repository.UserDataSnapshot, error) {
+				return newTestUserDataSnapshot(), nil
+			},
+			MarkCompletedFn: func(ctx context.Context, id, fileName string, content []byte, completedAt, expiresAt time.Time) error {
+				gotContent = content
+				return nil
+			},
+		}
+		svc := NewUserDataExportService(testutil.NewTestLogger(), nil, repo, nil).(*userDataExportService)
+
+		if err := svc.generateExport(context.Background(), "e1", "u1", model.UserDataExportFormatZIP); err != nil {
+			t.Fatalf("expected no error, got: %v", err)
+		}
+
+		zr, err := zip.NewReader(bytes.NewReader(gotContent), int64(len(gotContent)))
+		if err != nil {
+			t.Fatalf("failed to read zip: %v", err)
+		}
+		var names []string
+		for _, f := range zr.File {
+			names = append(names, f.Name)
+		}
+		sort.Strings(names)
+		want := []string{"contributions.json", "follows.json", "liked_tags.json", "movie_ratings.json", "movie_statuses.json", "notifications.json", "profile.json", "tags.json"}
+		if len(names) != len(want) {
+			t.Fatalf("expected files %v, got %v", want, names)
+		}
+		for i := range want {
+			if names[i] != want[i] {
+				t.Fatalf("expected files %v, got %v", want, names)
+			}
+		}
+	})
+}
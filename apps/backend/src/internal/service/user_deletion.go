@@ -0,0 +1,358 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"cinetag-backend/src/internal/model"
+	"cinetag-backend/src/internal/repository"
+
+	"gorm.io/gorm"
+)
+
+const (
+	// 退会から完全削除までの猶予期間。この期間内であれば退会を取り消せる。
+	userDeletionGracePeriod = 30 * 24 * time.Hour
+	// 完全削除ジョブが1回に処理するユーザー数の既定値。
+	defaultPurgeBatchSize = 100
+)
+
+// 退会ポリシーの指定が不正な場合のエラー。
+var ErrInvalidDeletionPolicy = errors.New("invalid deletion policy")
+
+// タグの譲渡先ユーザーが不正な場合のエラー。
+var ErrInvalidTagTransferTarget = errors.New("invalid tag transfer target")
+
+// 退会状態ではないユーザーを再開しようとした場合のエラー。
+var ErrUserNotDeactivated = errors.New("user is not deactivated")
+
+// 猶予期間を過ぎたユーザーを再開しようとした場合のエラー。
+var ErrReactivationPeriodExpired = errors.New("reactivation period has expired")
+
+// 管理者に利用停止されたユーザーを本人が再開しようとした場合のエラー。
+var ErrUserSuspended = errors.New("user is suspended")
+
+// 退会時に指定する、完全削除時のデータの扱いを表す。
+type AccountDeletionPolicy struct {
+	OwnedTags        string // 所有タグの扱い（model.OwnedTagsPolicyDelete / model.OwnedTagsPolicyTransfer）
+	TransferToUserID string // OwnedTags が transfer の場合の譲渡先ユーザーID
+	Contributions    string // 他者タグへの追加映画の扱い（model.ContributionsPolicyAnonymize / model.ContributionsPolicyRemove）
+}
+
+// 既定の退会ポリシーを返す。
+// 所有タグは削除し、他者タグへの追加映画は匿名化して残す。
+func DefaultAccountDeletionPolicy() AccountDeletionPolicy {
+	return AccountDeletionPolicy{
+		OwnedTags:     model.OwnedTagsPolicyDelete,
+		Contributions: model.ContributionsPolicyAnonymize,
+	}
+}
+
+// 退会ポリシーを検証する。
+func (p AccountDeletionPolicy) validate(userID string) error {
+	switch p.OwnedTags {
+	case model.OwnedTagsPolicyDelete:
+		if p.TransferToUserID != "" {
+			return ErrInvalidDeletionPolicy
+		}
+	case model.OwnedTagsPolicyTransfer:
+		if p.TransferToUserID == "" || p.TransferToUserID == userID {
+			return ErrInvalidTagTransferTarget
+		}
+	default:
+		return ErrInvalidDeletionPolicy
+	}
+
+	switch p.Contributions {
+	case model.ContributionsPolicyAnonymize, model.ContributionsPolicyRemove:
+	default:
+		return ErrInvalidDeletionPolicy
+	}
+	return nil
+}
+
+// ユーザーを退会状態にする。
+// - プロフィール・所有タグは即座に非表示になり、猶予期間後に完全削除される。
+// - 既に退会状態の場合は、保存済みのポリシーを変更せずにそのまま返す。
+func (s *userService) DeactivateUser(ctx context.Context, userID string, policy AccountDeletionPolicy) (*model.User, error) {
+	userID = strings.TrimSpace(userID)
+	if userID == "" {
+		return nil, errors.New("user id is required")
+	}
+	if err := policy.validate(userID); err != nil {
+		return nil, err
+	}
+	if s.db == nil {
+		return nil, errors.New("db is required")
+	}
+
+	now := time.Now()
+	purgeAt := now.Add(userDeletionGracePeriod)
+
+	var result *model.User
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		userRepo := repository.NewUserRepository(s.logger, tx)
+		deletionRepo := repository.NewUserDeletionRepository(tx)
+		userFollowerRepo := repository.NewUserFollowerRepository(tx)
+		tagFollowerRepo := repository.NewTagFollowerRepository(tx)
+		followRequestRepo := repository.NewUserFollowRequestRepository(tx)
+
+		u, err := userRepo.FindByID(ctx, userID)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrUserNotFound
+			}
+			return err
+		}
+		switch u.DeletionStatus {
+		case model.UserDeletionStatusPurged:
+			return ErrUserNotFound
+		case model.UserDeletionStatusDeactivated:
+			result = u
+			return nil
+		}
+
+		var transferTo *string
+		if policy.OwnedTags == model.OwnedTagsPolicyTransfer {
+			target, err := userRepo.FindByID(ctx, policy.TransferToUserID)
+			if err != nil {
+				if errors.Is(err, gorm.ErrRecordNotFound) {
+					return ErrInvalidTagTransferTarget
+				}
+				return err
+			}
+			if target.DeletedAt != nil {
+				return ErrInvalidTagTransferTarget
+			}
+			transferTo = &target.ID
+		}
+
+		if err := deletionRepo.MarkDeactivated(ctx, u.ID, now, purgeAt, repository.UserDeletionPolicy{
+			OwnedTags:        policy.OwnedTags,
+			TransferToUserID: transferTo,
+			Contributions:    policy.Contributions,
+		}); err != nil {
+			return err
+		}
+
+		// 当該ユーザーに紐づくフォロー関係をクリーンアップ（再開時にも復元しない）
+		if err := tagFollowerRepo.DeleteAllByUserID(ctx, u.ID); err != nil {
+			return err
+		}
+		if err := userFollowerRepo.DeleteAllByUserID(ctx, u.ID); err != nil {
+			return err
+		}
+		if err := followRequestRepo.DeleteAllByUserID(ctx, u.ID); err != nil {
+			return err
+		}
+
+		u.DeletedAt = &now
+		u.DeletionStatus = model.UserDeletionStatusDeactivated
+		u.PurgeScheduledAt = &purgeAt
+		u.OwnedTagsPolicy = &policy.OwnedTags
+		u.TagTransferUserID = transferTo
+		u.ContributionsPolicy = &policy.Contributions
+		result = u
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// Clerk 側で削除されたユーザーを退会状態にする。
+func (s *userService) DeactivateClerkUser(ctx context.Context, clerkUserID string, event *ClerkWebhookClaim) error {
+	return s.withClerkEventTx(ctx, event, func(svc *userService) error {
+		u, err := svc.FindUserByClerkUserID(ctx, clerkUserID)
+		if err != nil {
+			if errors.Is(err, ErrUserNotFound) {
+				return nil
+			}
+			return err
+		}
+
+		// Clerk 側で削除済みのため、既定ポリシーで猶予期間後に完全削除される
+		if _, err := svc.DeactivateUser(ctx, u.ID, DefaultAccountDeletionPolicy()); err != nil {
+			if errors.Is(err, ErrUserNotFound) {
+				return nil
+			}
+			return err
+		}
+		return nil
+	})
+}
+
+// 猶予期間中の退会を取り消し、ユーザーを通常状態に戻す。
+func (s *userService) ReactivateUser(ctx context.Context, userID string) (*model.User, error) {
+	userID = strings.TrimSpace(userID)
+	if userID == "" {
+		return nil, errors.New("user id is required")
+	}
+	u, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrUserNotFound
+		}
+		return nil, err
+	}
+	now := time.Now()
+	if err := checkReactivatable(u, now); err != nil {
+		return nil, err
+	}
+
+	if s.db == nil {
+		return nil, errors.New("db is required")
+	}
+	// 完全削除のジョブと同時に実行されないよう、行をロックして状態を確認し直してから戻す
+	err = s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		deletionRepo := repository.NewUserDeletionRepository(tx)
+		locked, err := deletionRepo.FindForUpdate(ctx, u.ID)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrUserNotFound
+			}
+			return err
+		}
+		if err := checkReactivatable(locked, now); err != nil {
+			return err
+		}
+		if err := deletionRepo.MarkReactivated(ctx, u.ID, now); err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrUserNotDeactivated
+			}
+			return err
+		}
+		u = locked
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	u.DeletedAt = nil
+	u.DeletionStatus = model.UserDeletionStatusActive
+	u.PurgeScheduledAt = nil
+	u.OwnedTagsPolicy = nil
+	u.TagTransferUserID = nil
+	u.ContributionsPolicy = nil
+	u.UpdatedAt = now
+	return u, nil
+}
+
+// 退会を取り消せる状態か（猶予期間中の退会状態で、利用停止中でないか）を確認する。
+func checkReactivatable(u *model.User, now time.Time) error {
+	if u.DeletionStatus != model.UserDeletionStatusDeactivated {
+		return ErrUserNotDeactivated
+	}
+	if u.SuspendedAt != nil {
+		return ErrUserSuspended
+	}
+	if u.PurgeScheduledAt != nil && !now.Before(*u.PurgeScheduledAt) {
+		return ErrReactivationPeriodExpired
+	}
+	return nil
+}
+
+// 猶予期間を過ぎた退会済みユーザーを完全削除する。
+// 1ユーザーの失敗で全体を止めず、処理できた件数と発生したエラーをまとめて返す。
+func (s *userService) PurgeDeactivatedUsers(ctx context.Context, now time.Time, limit int) (int, error) {
+	if s.db == nil {
+		return 0, errors.New("db is required")
+	}
+	if limit <= 0 {
+		limit = defaultPurgeBatchSize
+	}
+
+	users, err := repository.NewUserDeletionRepository(s.db).ListDueForPurge(ctx, now, limit)
+	if err != nil {
+		return 0, err
+	}
+
+	purged := 0
+	var errs []error
+	for _, u := range users {
+		if err := s.purgeUser(ctx, u, now); err != nil {
+			if errors.Is(err, errUserNotDueForPurge) {
+				s.logger.Info("service.PurgeDeactivatedUsers skipped user reactivated during purge",
+					slog.String("user_id", u.ID),
+				)
+				continue
+			}
+			s.logger.Error("service.PurgeDeactivatedUsers failed to purge user",
+				slog.String("user_id", u.ID),
+				slog.Any("error", err),
+			)
+			errs = append(errs, fmt.Errorf("purge user %s: %w", u.ID, err))
+			continue
+		}
+		purged++
+	}
+
+	return purged, errors.Join(errs...)
+}
+
+// 完全削除の対象を取得してから処理するまでの間に、退会が取り消された場合のエラー。
+var errUserNotDueForPurge = errors.New("user is no longer due for purge")
+
+// 退会時のポリシーに従って1ユーザー分のデータを削除・匿名化する。
+func (s *userService) purgeUser(ctx context.Context, u *model.User, now time.Time) error {
+	return s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		userRepo := repository.NewUserRepository(s.logger, tx)
+		deletionRepo := repository.NewUserDeletionRepository(tx)
+
+		// 退会の取り消しと同時に実行されないよう、行をロックして完全削除の対象のままか確認し直す
+		locked, err := deletionRepo.FindForUpdate(ctx, u.ID)
+		if err != nil {
+			return err
+		}
+		u = locked
+		if u.DeletionStatus != model.UserDeletionStatusDeactivated || u.PurgeScheduledAt == nil || u.PurgeScheduledAt.After(now) {
+			return errUserNotDueForPurge
+		}
+
+		// 他者タグへの追加映画（所有タグの譲渡より先に判定する）
+		if u.ContributionsPolicy != nil && *u.ContributionsPolicy == model.ContributionsPolicyRemove {
+			if err := deletionRepo.DeleteContributionsToOthersTags(ctx, u.ID); err != nil {
+				return err
+			}
+		}
+
+		// 所有タグ（譲渡先が退会済みなどで譲渡できない場合は削除する）
+		transferTo := ""
+		if u.OwnedTagsPolicy != nil && *u.OwnedTagsPolicy == model.OwnedTagsPolicyTransfer && u.TagTransferUserID != nil {
+			target, err := userRepo.FindByID(ctx, *u.TagTransferUserID)
+			switch {
+			case err == nil && target.DeletedAt == nil:
+				transferTo = target.ID
+			case err != nil && !errors.Is(err, gorm.ErrRecordNotFound):
+				return err
+			default:
+				s.logger.Warn("service.purgeUser tag transfer target is unavailable, deleting tags instead",
+					slog.String("user_id", u.ID),
+					slog.String("transfer_to_user_id", *u.TagTransferUserID),
+				)
+			}
+		}
+		if transferTo != "" {
+			if err := deletionRepo.TransferOwnedTags(ctx, u.ID, transferTo, now); err != nil {
+				return err
+			}
+		} else {
+			if err := deletionRepo.DeleteOwnedTags(ctx, u.ID); err != nil {
+				return err
+			}
+		}
+
+		if err := deletionRepo.DeleteUserActivity(ctx, u.ID); err != nil {
+			return err
+		}
+
+		anonymizedEmail := fmt.Sprintf("deleted+%s@example.invalid", u.ID)
+		return deletionRepo.MarkPurged(ctx, u.ID, now, anonymizedEmail)
+	})
+}
@@ -0,0 +1,176 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"cinetag-backend/src/internal/model"
+)
+
+func TestAccountDeletionPolicy_validate(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		policy  AccountDeletionPolicy
+		wantErr error
+	}{
+		{
+			name:   "既定ポリシー",
+			policy: DefaultAccountDeletionPolicy(),
+		},
+		{
+			name: "譲渡・削除",
+			policy: AccountDeletionPolicy{
+				OwnedTags:        model.OwnedTagsPolicyTransfer,
+				TransferToUserID: "u2",
+				Contributions:    model.ContributionsPolicyRemove,
+			},
+		},
+		{
+			name:    "所有タグの扱いが不正",
+			policy:  AccountDeletionPolicy{OwnedTags: "archive", Contributions: model.ContributionsPolicyAnonymize},
+			wantErr: ErrInvalidDeletionPolicy,
+		},
+		{
+			name:    "追加映画の扱いが不正",
+			policy:  AccountDeletionPolicy{OwnedTags: model.OwnedTagsPolicyDelete, Contributions: "keep"},
+			wantErr: ErrInvalidDeletionPolicy,
+		},
+		{
+			name: "削除なのに譲渡先を指定",
+			policy: AccountDeletionPolicy{
+				OwnedTags:        model.OwnedTagsPolicyDelete,
+				TransferToUserID: "u2",
+				Contributions:    model.ContributionsPolicyAnonymize,
+			},
+			wantErr: ErrInvalidDeletionPolicy,
+		},
+		{
+			name:    "譲渡先が未指定",
+			policy:  AccountDeletionPolicy{OwnedTags: model.OwnedTagsPolicyTransfer, Contributions: model.ContributionsPolicyAnonymize},
+			wantErr: ErrInvalidTagTransferTarget,
+		},
+		{
+			name: "譲渡先が自分自身",
+			policy: AccountDeletionPolicy{
+				OwnedTags:        model.OwnedTagsPolicyTransfer,
+				TransferToUserID: "u1",
+				Contributions:    model.ContributionsPolicyAnonymize,
+			},
+			wantErr: ErrInvalidTagTransferTarget,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			err := tt.policy.validate("u1")
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected %v, got: %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestUserService_DeactivateUser(t *testing.T) {
+	t.Parallel()
+
+	t.Run("不正なポリシー: ErrInvalidDeletionPolicy", func(t *testing.T) {
+		t.Parallel()
+		svc := newUserService(t, nil)
+		_, err := svc.DeactivateUser(context.Background(), "u1", AccountDeletionPolicy{})
+		if !errors.Is(err, ErrInvalidDeletionPolicy) {
+			t.Fatalf("expected ErrInvalidDeletionPolicy, got: %v", err)
+		}
+	})
+}
+
+func TestUserService_ReactivateUser(t *testing.T) {
+	t.Parallel()
+
+	t.Run("ユーザーが存在しない: ErrUserNotFound", func(t *testing.T) {
+		t.Parallel()
+		svc := newUserService(t, nil)
+		_, err := svc.ReactivateUser(context.Background(), "u1")
+		if !errors.Is(err, ErrUserNotFound) {
+			t.Fatalf("expected ErrUserNotFound, got: %v", err)
+		}
+	})
+
+	t.Run("退会状態でない: ErrUserNotDeactivated", func(t *testing.T) {
+		t.Parallel()
+		repo := &fakeUserRepo{
+			FindByIDFn: func(ctx context.Context, userID string) (*model.User, error) {
+				return &model.User{ID: userID, DeletionStatus: model.UserDeletionStatusActive}, nil
+			},
+		}
+		svc := newUserService(t, func(d *userServiceDeps) {
+			d.userRepo = repo
+		})
+		_, err := svc.ReactivateUser(context.Background(), "u1")
+		if !errors.Is(err, ErrUserNotDeactivated) {
+			t.Fatalf("expected ErrUserNotDeactivated, got: %v", err)
+		}
+	})
+
+	t.Run("完全削除済み: ErrUserNotDeactivated", func(t *testing.T) {
+		t.Parallel()
+		repo := &fakeUserRepo{
+			FindByIDFn: func(ctx context.Context, userID string) (*model.User, error) {
+				return &model.User{ID: userID, DeletionStatus: model.UserDeletionStatusPurged}, nil
+			},
+		}
+		svc := newUserService(t, func(d *userServiceDeps) {
+			d.userRepo = repo
+		})
+		_, err := svc.ReactivateUser(context.Background(), "u1")
+		if !errors.Is(err, ErrUserNotDeactivated) {
+			t.Fatalf("expected ErrUserNotDeactivated, got: %v", err)
+		}
+	})
+
+	t.Run("猶予期間切れ: ErrReactivationPeriodExpired", func(t *testing.T) {
+		t.Parallel()
+		purgeAt := time.Now().Add(-time.Hour)
+		repo := &fakeUserRepo{
+			FindByIDFn: func(ctx context.Context, userID string) (*model.User, error) {
+				return &model.User{
+					ID:               userID,
+					DeletionStatus:   model.UserDeletionStatusDeactivated,
+					PurgeScheduledAt: &purgeAt,
+				}, nil
+			},
+		}
+		svc := newUserService(t, func(d *userServiceDeps) {
+			d.userRepo = repo
+		})
+		_, err := svc.ReactivateUser(context.Background(), "u1")
+		if !errors.Is(err, ErrReactivationPeriodExpired) {
+			t.Fatalf("expected ErrReactivationPeriodExpired, got: %v", err)
+		}
+	})
+
+	t.Run("管理者による利用停止中: ErrUserSuspended", func(t *testing.T) {
+		t.Parallel()
+		suspendedAt := time.Now().Add(-time.Hour)
+		repo := &fakeUserRepo{
+			FindByIDFn: func(ctx context.Context, userID string) (*model.User, error) {
+				return &model.User{
+					ID:             userID,
+					DeletionStatus: model.UserDeletionStatusDeactivated,
+					SuspendedAt:    &suspendedAt,
+				}, nil
+			},
+		}
+		svc := newUserService(t, func(d *userServiceDeps) {
+			d.userRepo = repo
+		})
+		_, err := svc.ReactivateUser(context.Background(), "u1")
+		if !errors.Is(err, ErrUserSuspended) {
+			t.Fatalf("expected ErrUserSuspended, got: %v", err)
+		}
+	})
+}
@@ -0,0 +1,83 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"unicode/utf8"
+
+	"cinetag-backend/src/internal/model"
+	"cinetag-backend/src/internal/repository"
+)
+
+// ユーザー検索キーワードの最大文字数。
+const userSearchQueryMaxLength = 50
+
+// 検索キーワードが指定されていない場合のエラー。
+var ErrEmptySearchQuery = errors.New("search query is required")
+
+// 検索キーワードが長すぎる場合のエラー。
+var ErrSearchQueryTooLong = errors.New("search query is too long")
+
+// ユーザー検索結果の1件分を表す。
+type UserSearchResult struct {
+	User          *model.User
+	FollowerCount int64
+	IsFollowing   bool // 閲覧者がこのユーザーをフォローしているか（未ログイン時・本人は常に false）
+}
+
+// display_name / display_id でユーザーを検索する。
+// - 退会済みユーザーと、閲覧者とブロック関係にあるユーザーは結果に含めない。
+// - 完全一致・前方一致を優先し、同順位はフォロワー数・直近の活動量の多い順に並べる。
+func (s *userService) SearchUsers(ctx context.Context, viewerID, query string, page, pageSize int) ([]UserSearchResult, int64, error) {
+	query = strings.TrimSpace(query)
+	if query == "" {
+		return nil, 0, ErrEmptySearchQuery
+	}
+	if utf8.RuneCountInString(query) > userSearchQueryMaxLength {
+		return nil, 0, ErrSearchQueryTooLong
+	}
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 || pageSize > 100 {
+		pageSize = 20
+	}
+
+	rows, total, err := s.userRepo.Search(ctx, repository.UserSearchFilter{
+		Query:    query,
+		ViewerID: viewerID,
+		Offset:   (page - 1) * pageSize,
+		Limit:    pageSize,
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+
+	// 閲覧者のフォロー状態はページ内のユーザーについてまとめて確認する（結果ごとに問い合わせない）
+	following := make(map[string]bool)
+	if viewerID != "" && len(rows) > 0 {
+		ids := make([]string, len(rows))
+		for i := range rows {
+			ids[i] = rows[i].ID
+		}
+		followingIDs, err := s.userFollowerRepo.ListFollowingIDs(ctx, viewerID, ids)
+		if err != nil {
+			return nil, 0, err
+		}
+		for _, id := range followingIDs {
+			following[id] = true
+		}
+	}
+
+	results := make([]UserSearchResult, len(rows))
+	for i := range rows {
+		u := rows[i].User
+		results[i] = UserSearchResult{
+			User:          &u,
+			FollowerCount: rows[i].FollowerCount,
+			IsFollowing:   following[u.ID] && viewerID != u.ID,
+		}
+	}
+	return results, total, nil
+}
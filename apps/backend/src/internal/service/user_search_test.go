@@ -0,0 +1,120 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"cinetag-backend/src/internal/model"
+	"cinetag-backend/src/internal/repository"
+)
+
+func TestUserService_SearchUsers(t *testing.T) {
+	t.Parallel()
+
+	t.Run("キーワードが空: ErrEmptySearchQuery", func(t *testing.T) {
+		t.Parallel()
+		svc := newUserService(t, nil)
+		_, _, err := svc.SearchUsers(context.Background(), "", "   ", 1, 20)
+		if !errors.Is(err, ErrEmptySearchQuery) {
+			t.Fatalf("expected ErrEmptySearchQuery, got: %v", err)
+		}
+	})
+
+	t.Run("キーワードが長すぎる: ErrSearchQueryTooLong", func(t *testing.T) {
+		t.Parallel()
+		svc := newUserService(t, nil)
+		_, _, err := svc.SearchUsers(context.Background(), "", strings.Repeat("あ", userSearchQueryMaxLength+1), 1, 20)
+		if !errors.Is(err, ErrSearchQueryTooLong) {
+			t.Fatalf("expected ErrSearchQueryTooLong, got: %v", err)
+		}
+	})
+
+	t.Run("成功: ページングを正規化し、閲覧者のフォロー状態を設定する", func(t *testing.T) {
+		t.Parallel()
+
+		var gotFilter repository.UserSearchFilter
+		userRepo := &fakeUserRepo{
+			SearchFn: func(ctx context.Context, filter repository.UserSearchFilter) ([]repository.UserSearchRow, int64, error) {
+				gotFilter = filter
+				return []repository.UserSearchRow{
+					{User: model.User{ID: "u1", DisplayID: "viewer"}, FollowerCount: 3},
+					{User: model.User{ID: "u2", DisplayID: "taro"}, FollowerCount: 10},
+					{User: model.User{ID: "u3", DisplayID: "taro2"}, FollowerCount: 1},
+				}, 3, nil
+			},
+		}
+		// フォロー状態はページ内のユーザーについてまとめて確認し、結果ごとには確認しない
+		var gotIDs []string
+		followerRepo := &fakeUserFollowerRepo{
+			IsFollowingFn: func(ctx context.Context, followerID, followeeID string) (bool, error) {
+				t.Fatalf("IsFollowing should not be called")
+				return false, nil
+			},
+			ListFollowingIDsFn: func(ctx context.Context, followerID string, followeeIDs []string) ([]string, error) {
+				if followerID != "u1" {
+					t.Fatalf("unexpected follower: %s", followerID)
+				}
+				gotIDs = followeeIDs
+				return []string{"u2"}, nil
+			},
+		}
+		svc := newUserService(t, func(d *userServiceDeps) {
+			d.userRepo = userRepo
+			d.userFollowerRepo = followerRepo
+		})
+
+		results, total, err := svc.SearchUsers(context.Background(), "u1", "  taro ", 0, 500)
+		if err != nil {
+			t.Fatalf("expected no error, got: %v", err)
+		}
+		if gotFilter.Query != "taro" || gotFilter.ViewerID != "u1" || gotFilter.Offset != 0 || gotFilter.Limit != 20 {
+			t.Fatalf("unexpected filter: %+v", gotFilter)
+		}
+		if total != 3 || len(results) != 3 {
+			t.Fatalf("expected 3 results, got total=%d len=%d", total, len(results))
+		}
+		if strings.Join(gotIDs, ",") != "u1,u2,u3" {
+			t.Fatalf("unexpected followee ids: %v", gotIDs)
+		}
+		if results[0].IsFollowing || !results[1].IsFollowing || results[2].IsFollowing {
+			t.Fatalf("unexpected is_following: %+v", results)
+		}
+		if results[1].User.DisplayID != "taro" || results[1].FollowerCount != 10 {
+			t.Fatalf("unexpected result: %+v", results[1])
+		}
+	})
+
+	t.Run("未ログイン: フォロー状態を確認しない", func(t *testing.T) {
+		t.Parallel()
+
+		userRepo := &fakeUserRepo{
+			SearchFn: func(ctx context.Context, filter repository.UserSearchFilter) ([]repository.UserSearchRow, int64, error) {
+				return []repository.UserSearchRow{{User: model.User{ID: "u2"}}}, 1, nil
+			},
+		}
+		followerRepo := &fakeUserFollowerRepo{
+			IsFollowingFn: func(ctx context.Context, followerID, followeeID string) (bool, error) {
+				t.Fatalf("IsFollowing should not be called")
+				return false, nil
+			},
+			ListFollowingIDsFn: func(ctx context.Context, followerID string, followeeIDs []string) ([]string, error) {
+				t.Fatalf("ListFollowingIDs should not be called")
+				return nil, nil
+			},
+		}
+		svc := newUserService(t, func(d *userServiceDeps) {
+			d.userRepo = userRepo
+			d.userFollowerRepo = followerRepo
+		})
+
+		results, _, err := svc.SearchUsers(context.Background(), "", "taro", 1, 20)
+		if err != nil {
+			t.Fatalf("expected no error, got: %v", err)
+		}
+		if len(results) != 1 || results[0].IsFollowing {
+			t.Fatalf("unexpected results: %+v", results)
+		}
+	})
+}
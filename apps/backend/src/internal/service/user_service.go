@@ -5,8 +5,10 @@ import (
 	"errors"
 	"fmt"
 	"log/slog"
+	"net/url"
 	"strings"
 	"time"
+	"unicode/utf8"
 
 	"cinetag-backend/src/internal/model"
 	"cinetag-backend/src/internal/repository"
@@ -22,6 +24,7 @@ type ClerkUserInfo struct {
 	FirstName string  // 名（任意）
 	LastName  string  // 姓（任意）
 	AvatarURL *string // アイコンURL（任意）
+	Username  string  // ユーザー名（任意）
 }
 
 // ユーザーが見つからなかった場合のエラー。
@@ -36,9 +39,66 @@ var ErrAlreadyFollowing = errors.New("already following")
 // フォローしていないユーザーをアンフォローしようとした場合のエラー。
 var ErrNotFollowing = errors.New("not following")
 
+// 既にフォローリクエストを送信済みの場合のエラー。
+var ErrFollowRequestAlreadySent = errors.New("follow request already sent")
+
+// 承認・拒否対象のフォローリクエストが存在しない場合のエラー。
+var ErrFollowRequestNotFound = errors.New("follow request not found")
+
+// 自分自身をブロック・ミュートしようとした場合のエラー。
+var ErrCannotBlockSelf = errors.New("cannot block or mute yourself")
+
+// 既にブロック済みの場合のエラー。
+var ErrAlreadyBlocked = errors.New("already blocked")
+
+// ブロックしていないユーザーのブロックを解除しようとした場合のエラー。
+var ErrNotBlocked = errors.New("not blocked")
+
+// 既にミュート済みの場合のエラー。
+var ErrAlreadyMuted = errors.New("already muted")
+
+// ミュートしていないユーザーのミュートを解除しようとした場合のエラー。
+var ErrNotMuted = errors.New("not muted")
+
+// ブロック関係にあるユーザーに対して操作しようとした場合のエラー。
+var ErrUserBlocked = errors.New("user is blocked")
+
+// display_id の形式が不正な場合のエラー。
+var ErrInvalidDisplayID = errors.New("display_id must be 3-30 characters of lowercase letters, digits, '-' or '_'")
+
+// display_id が予約語の場合のエラー。
+var ErrReservedDisplayID = errors.New("display_id is reserved")
+
+// display_id が既に使用されている場合のエラー。
+var ErrDisplayIDTaken = errors.New("display_id is already taken")
+
+// フォロー操作の結果を表す。
+type FollowStatus string
+
+const (
+	// フォローが成立した。
+	FollowStatusFollowing FollowStatus = "following"
+	// 非公開アカウントのため、フォローリクエストとして承認待ちになった。
+	FollowStatusRequested FollowStatus = "requested"
+)
+
+const (
+	// 自己紹介文の最大文字数。
+	userBioMaxLength = 300
+	// アイコンURLの最大長。
+	userAvatarURLMaxLength = 2048
+	// Clerk 側に名前・ユーザー名がない場合の表示名。
+	defaultUserDisplayName = "名無し"
+)
+
 // ユーザー更新用の入力構造体。
+// 各フィールドは nil の場合は更新しない。Bio と AvatarURL は空文字でクリアする。
 type UpdateUserInput struct {
-	DisplayName *string // 表示名（nilの場合は更新しない）
+	DisplayName *string // 表示名
+	DisplayID   *string // ユーザーID（URLに使用される）
+	Bio         *string // 自己紹介文
+	AvatarURL   *string // アイコンURL
+	IsPrivate   *bool   // 非公開アカウントにするか
 }
 
 // users テーブルに関するユースケースを表すインターフェース。
@@ -46,6 +106,7 @@ type UserService interface {
 	// Clerk ユーザー情報をもとに、
 	// users テーブル上に対応するレコードが存在することを保証する。
 	// - 既に存在すればそれを返し、存在しなければ新規作成して返す。
+	// - 新規作成時、Clerk のユーザー名が使用可能であれば display_id に使う。
 	EnsureUser(ctx context.Context, clerkUser ClerkUserInfo) (*model.User, error)
 
 	// clerk_user_id からユーザー情報を取得する。
@@ -55,18 +116,45 @@ type UserService interface {
 	// display_id からユーザー情報を取得する。
 	GetUserByDisplayID(ctx context.Context, displayID string) (*model.User, error)
 
+	// 変更前の display_id から、現在の display_id を解決する。
+	// - 履歴が存在しない、またはユーザーが削除済みの場合は ErrUserNotFound を返す。
+	ResolveRenamedDisplayID(ctx context.Context, displayID string) (string, error)
+
 	// ユーザー情報を更新する。
 	UpdateUser(ctx context.Context, userID string, input UpdateUserInput) (*model.User, error)
 
-	// Clerk Webhook からユーザー情報を更新する（avatar_url）。
-	UpdateUserFromClerk(ctx context.Context, userID string, avatarURL *string) error
+	// Clerk Webhook のユーザー情報を users テーブルに同期する。
+	// - opts.Created が true の場合（user.created の受信）、対応するレコードが存在しなければ作成し、退会済みのユーザーを復帰させる。
+	// - それ以外の場合、レコードが存在しない・完全削除済みであれば同期せずに ErrUserNotFound を返す（完全削除後に届いた user.updated で作り直さない）。
+	// - email / avatar_url は常に Clerk の値で上書きする。
+	// - display_name / display_id は、アプリ上で変更されていない（前回 Clerk から同期した値のまま）場合のみ追従する。
+	// - opts.Event を指定した場合、同期と同じトランザクションで Webhook のメッセージを処理済みにする。
+	SyncUserFromClerk(ctx context.Context, clerkUser ClerkUserInfo, opts ClerkSyncOptions) (*model.User, error)
 
 	// 指定ユーザーをフォローする。
-	FollowUser(ctx context.Context, followerID, followeeID string) error
+	// - フォロー先が非公開アカウントの場合はフォローリクエストを作成し、FollowStatusRequested を返す。
+	FollowUser(ctx context.Context, followerID, followeeID string) (FollowStatus, error)
 
 	// 指定ユーザーをアンフォローする。
+	// - 未フォローで承認待ちのフォローリクエストがある場合は、リクエストを取り消す。
 	UnfollowUser(ctx context.Context, followerID, followeeID string) error
 
+	// 自分宛てのフォローリクエストを承認し、フォロー関係を作成する。
+	ApproveFollowRequest(ctx context.Context, targetID, requesterID string) error
+
+	// 自分宛てのフォローリクエストを拒否する。
+	DenyFollowRequest(ctx context.Context, targetID, requesterID string) error
+
+	// 自分宛ての承認待ちフォローリクエストを送ったユーザー一覧を取得する。
+	ListFollowRequests(ctx context.Context, userID string, page, pageSize int) ([]*model.User, int64, error)
+
+	// requesterID から targetID への承認待ちフォローリクエストが存在するかチェックする。
+	HasPendingFollowRequest(ctx context.Context, requesterID, targetID string) (bool, error)
+
+	// viewerID のユーザーが owner のタグ一覧・フォロー一覧などを閲覧できるかチェックする。
+	// - 公開アカウント、本人、承認済みフォロワーのみ閲覧可能（viewerID は未ログイン時は空文字）。
+	CanViewUserContent(ctx context.Context, viewerID string, owner *model.User) (bool, error)
+
 	// followerID が followeeID をフォローしているかチェックする。
 	IsFollowing(ctx context.Context, followerID, followeeID string) (bool, error)
 
@@ -76,32 +164,140 @@ type UserService interface {
 	// 指定ユーザーをフォローしているユーザー一覧を取得する。
 	ListFollowers(ctx context.Context, userID string, page, pageSize int) ([]*model.User, int64, error)
 
+	// display_name / display_id でユーザーを検索する。
+	// - 退会済みユーザーと、閲覧者とブロック関係にあるユーザーは除外する（viewerID は未ログイン時は空文字）。
+	// - query が空の場合は ErrEmptySearchQuery を返す。
+	SearchUsers(ctx context.Context, viewerID, query string, page, pageSize int) ([]UserSearchResult, int64, error)
+
+	// 指定ユーザーへのおすすめユーザー（フォロー候補）を取得する。
+	// - 友達の友達・共通のフォロータグ・共通の映画をもとに並べ、各候補に理由を付与する。
+	// - フォロー中・ブロック関係・退会済みのユーザーは含めない。
+	SuggestUsers(ctx context.Context, userID string, limit int) ([]UserSuggestion, error)
+
 	// フォロー数とフォロワー数を取得する。
 	GetFollowStats(ctx context.Context, userID string) (following int64, followers int64, err error)
 
-	// ユーザーを論理削除＋匿名化し、関連データをクリーンアップする。
-	DeactivateUser(ctx context.Context, userID string) error
+	// ユーザーを退会状態にし、猶予期間後の完全削除を予約する。
+	// - プロフィールと所有タグは即座に非表示になる。フォロー関係は解除され、再開しても復元されない。
+	// - 完全削除時の所有タグ・他者タグへの追加映画の扱いは policy に従う。
+	DeactivateUser(ctx context.Context, userID string, policy AccountDeletionPolicy) (*model.User, error)
+
+	// Clerk 側で削除されたユーザーを既定のポリシーで退会状態にする（user.deleted の受信時）。
+	// - ユーザーが存在しない・完全削除済みの場合は何もしない。
+	// - event を指定した場合、退会処理と同じトランザクションで Webhook のメッセージを処理済みにする。
+	DeactivateClerkUser(ctx context.Context, clerkUserID string, event *ClerkWebhookClaim) error
+
+	// 猶予期間中の退会を取り消し、ユーザーを通常状態に戻す。
+	// - 退会状態でない場合は ErrUserNotDeactivated、猶予期間を過ぎている場合は ErrReactivationPeriodExpired を返す。
+	ReactivateUser(ctx context.Context, userID string) (*model.User, error)
+
+	// 猶予期間を過ぎた退会済みユーザーを完全削除し、処理したユーザー数を返す。
+	PurgeDeactivatedUsers(ctx context.Context, now time.Time, limit int) (int, error)
+
+	// 指定ユーザーをブロックする。
+	// - 双方向のフォロー関係を削除し、以降のフォローを禁止する。
+	BlockUser(ctx context.Context, blockerID, blockedID string) error
+
+	// 指定ユーザーのブロックを解除する。
+	UnblockUser(ctx context.Context, blockerID, blockedID string) error
+
+	// 指定ユーザーをミュートする（通知・フィードの表示のみに影響する）。
+	MuteUser(ctx context.Context, muterID, mutedID string) error
+
+	// 指定ユーザーのミュートを解除する。
+	UnmuteUser(ctx context.Context, muterID, mutedID string) error
+
+	// 指定ユーザーがブロックしているユーザー一覧を取得する。
+	ListBlockedUsers(ctx context.Context, userID string, page, pageSize int) ([]*model.User, int64, error)
+
+	// 指定ユーザーがミュートしているユーザー一覧を取得する。
+	ListMutedUsers(ctx context.Context, userID string, page, pageSize int) ([]*model.User, int64, error)
 }
 
 type userService struct {
-	logger              *slog.Logger
-	db                  *gorm.DB
-	userRepo            repository.UserRepository
-	userFollowerRepo    repository.UserFollowerRepository
-	tagFollowerRepo     repository.TagFollowerRepository
-	notificationService NotificationService
+	logger               *slog.Logger
+	db                   *gorm.DB
+	userRepo             repository.UserRepository
+	userFollowerRepo     repository.UserFollowerRepository
+	tagFollowerRepo      repository.TagFollowerRepository
+	outboxRepo           repository.OutboxRepository
+	displayIDHistoryRepo repository.UserDisplayIDHistoryRepository
+	userBlockRepo        repository.UserBlockRepository
+	userMuteRepo         repository.UserMuteRepository
+	followRequestRepo    repository.UserFollowRequestRepository
+}
+
+// Clerk のユーザー情報の同期時のオプション。
+type ClerkSyncOptions struct {
+	Created bool               // user.created の受信（存在しないユーザーを作成し、退会済みのユーザーを復帰させる）
+	Event   *ClerkWebhookClaim // 同期と同じトランザクションで処理済みにする Webhook のメッセージ
+}
+
+// フォロー関係の状態変更と同じトランザクションで使うリポジトリの組。
+type userFollowTxRepos struct {
+	userFollower  repository.UserFollowerRepository
+	followRequest repository.UserFollowRequestRepository
+	outbox        repository.OutboxRepository
 }
 
 // UserService の実装を生成する。
-func NewUserService(logger *slog.Logger, db *gorm.DB, userRepo repository.UserRepository, userFollowerRepo repository.UserFollowerRepository, tagFollowerRepo repository.TagFollowerRepository, notificationService NotificationService) UserService {
+func NewUserService(logger *slog.Logger, db *gorm.DB, userRepo repository.UserRepository, userFollowerRepo repository.UserFollowerRepository, tagFollowerRepo repository.TagFollowerRepository, outboxRepo repository.OutboxRepository, displayIDHistoryRepo repository.UserDisplayIDHistoryRepository, userBlockRepo repository.UserBlockRepository, userMuteRepo repository.UserMuteRepository, followRequestRepo repository.UserFollowRequestRepository) UserService {
 	return &userService{
-		logger:              logger,
-		db:                  db,
-		userRepo:            userRepo,
-		userFollowerRepo:    userFollowerRepo,
-		tagFollowerRepo:     tagFollowerRepo,
-		notificationService: notificationService,
+		logger:               logger,
+		db:                   db,
+		userRepo:             userRepo,
+		userFollowerRepo:     userFollowerRepo,
+		tagFollowerRepo:      tagFollowerRepo,
+		outboxRepo:           outboxRepo,
+		displayIDHistoryRepo: displayIDHistoryRepo,
+		userBlockRepo:        userBlockRepo,
+		userMuteRepo:         userMuteRepo,
+		followRequestRepo:    followRequestRepo,
+	}
+}
+
+// 全てのリポジトリを db（トランザクション）に紐づけ直した userService を返す。
+// トランザクション内で呼び出したメソッドが開始するトランザクションは、セーブポイントになる。
+func (s *userService) withDB(db *gorm.DB) *userService {
+	c := *s
+	c.db = db
+	c.userRepo = repository.NewUserRepository(s.logger, db)
+	c.userFollowerRepo = repository.NewUserFollowerRepository(db)
+	c.tagFollowerRepo = repository.NewTagFollowerRepository(db)
+	if s.outboxRepo != nil {
+		c.outboxRepo = repository.NewOutboxRepository(db)
+	}
+	if s.displayIDHistoryRepo != nil {
+		c.displayIDHistoryRepo = repository.NewUserDisplayIDHistoryRepository(db)
 	}
+	if s.userBlockRepo != nil {
+		c.userBlockRepo = repository.NewUserBlockRepository(db)
+	}
+	if s.userMuteRepo != nil {
+		c.userMuteRepo = repository.NewUserMuteRepository(db)
+	}
+	if s.followRequestRepo != nil {
+		c.followRequestRepo = repository.NewUserFollowRequestRepository(db)
+	}
+	return &c
+}
+
+// Clerk Webhook のメッセージの処理（fn）と、メッセージを処理済みにする記録を同一トランザクションで行う。
+// event が nil の場合（管理者による再同期など）は、トランザクションを開始せずに fn を実行する。
+func (s *userService) withClerkEventTx(ctx context.Context, event *ClerkWebhookClaim, fn func(svc *userService) error) error {
+	if event == nil {
+		return fn(s)
+	}
+	if s.db == nil {
+		return errors.New("db is required")
+	}
+	return s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := fn(s.withDB(tx)); err != nil {
+			return err
+		}
+		// 処理中に期限が切れ、再送されたリクエストに引き継がれていた場合はロールバックする
+		return repository.NewClerkWebhookEventRepository(tx).MarkProcessed(ctx, event.SvixID, event.LockToken, time.Now())
+	})
 }
 
 // Clerk ユーザーに対応する users レコードの存在を保証する。
@@ -128,24 +324,7 @@ func (s *userService) EnsureUser(ctx context.Context, clerkInfo ClerkUserInfo) (
 		return nil, err
 	}
 
-	displayName := resolveDisplayName(clerkInfo)
-
-	// display_id はランダム生成（重複したら内部で再生成）
-	displayID := GenerateUserDisplayID(ctx, s.userRepo)
-
-	user := &model.User{
-		ClerkUserID: clerkInfo.ID,
-		DisplayID:   displayID,
-		DisplayName: displayName,
-		Email:       clerkInfo.Email,
-		AvatarURL:   clerkInfo.AvatarURL,
-	}
-
-	if err := s.userRepo.Create(ctx, user); err != nil {
-		return nil, err
-	}
-
-	return user, nil
+	return s.createUserFromClerk(ctx, clerkInfo)
 }
 
 // clerk_user_id からユーザー情報を取得する。
@@ -169,7 +348,8 @@ func (s *userService) FindUserByClerkUserID(ctx context.Context, clerkUserID str
 // - FirstName と LastName が両方存在する場合は FirstName + LastName を返す。
 // - FirstName が存在する場合は FirstName を返す。
 // - LastName が存在する場合は LastName を返す。
-// - どちらも存在しない場合は "名無し" を返す。
+// - どちらも存在しない場合は Username を返す。
+// - いずれも存在しない場合は "名無し" を返す。
 func resolveDisplayName(clerkInfo ClerkUserInfo) string {
 	first := strings.TrimSpace(clerkInfo.FirstName)
 	last := strings.TrimSpace(clerkInfo.LastName)
@@ -181,8 +361,11 @@ func resolveDisplayName(clerkInfo ClerkUserInfo) string {
 	case last != "":
 		return last
 	}
+	if username := strings.TrimSpace(clerkInfo.Username); username != "" {
+		return username
+	}
 
-	return "名無し"
+	return defaultUserDisplayName
 }
 
 // display_id からユーザー情報を取得する。
@@ -234,14 +417,96 @@ func (s *userService) UpdateUser(ctx context.Context, userID string, input Updat
 		updates["display_name"] = displayName
 	}
 
+	if input.Bio != nil {
+		bio := strings.TrimSpace(*input.Bio)
+		if utf8.RuneCountInString(bio) > userBioMaxLength {
+			return nil, fmt.Errorf("bio is too long (max %d characters)", userBioMaxLength)
+		}
+		if bio == "" {
+			updates["bio"] = nil
+		} else {
+			updates["bio"] = bio
+		}
+	}
+
+	if input.AvatarURL != nil {
+		avatarURL := strings.TrimSpace(*input.AvatarURL)
+		if avatarURL == "" {
+			updates["avatar_url"] = nil
+		} else {
+			if !isValidAvatarURL(avatarURL) {
+				return nil, errors.New("avatar_url must be a valid http(s) URL")
+			}
+			updates["avatar_url"] = avatarURL
+		}
+	}
+
+	if input.IsPrivate != nil {
+		updates["is_private"] = *input.IsPrivate
+	}
+
+	newDisplayID := ""
+	if input.DisplayID != nil {
+		newDisplayID = NormalizeCustomUserDisplayID(*input.DisplayID)
+		if err := ValidateCustomUserDisplayID(newDisplayID); err != nil {
+			return nil, err
+		}
+	}
+
 	// 更新対象がない場合はエラー
-	if len(updates) == 0 {
+	if len(updates) == 0 && newDisplayID == "" {
 		return nil, errors.New("no fields to update")
 	}
 
+	if newDisplayID != "" {
+		current, err := s.userRepo.FindByID(ctx, userID)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil, ErrUserNotFound
+			}
+			return nil, err
+		}
+		if current.DisplayID == newDisplayID {
+			// 変更がない場合は display_id の更新を行わない
+			newDisplayID = ""
+			if len(updates) == 0 {
+				return current, nil
+			}
+		} else {
+			if err := s.ensureDisplayIDAvailable(ctx, userID, newDisplayID); err != nil {
+				return nil, err
+			}
+			return s.updateUserWithDisplayID(ctx, current, newDisplayID, updates)
+		}
+	}
+
 	// updated_at を更新
 	updates["updated_at"] = time.Now()
 
+	// 公開アカウントに切り替える場合は、承認待ちのフォローリクエストの承認を同じトランザクションで行う
+	if isPublicSwitch(updates) && s.db != nil {
+		var updated *model.User
+		err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+			userRepo := repository.NewUserRepository(s.logger, tx)
+			if err := userRepo.Update(ctx, userID, updates); err != nil {
+				return err
+			}
+			if err := s.acceptAllFollowRequestsTx(ctx, tx, userID); err != nil {
+				return err
+			}
+			u, err := userRepo.FindByID(ctx, userID)
+			if err != nil {
+				return err
+			}
+			updated = u
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+		return updated, nil
+	}
+
 	// 更新実行
 	if err := s.userRepo.Update(ctx, userID, updates); err != nil {
 		return nil, err
@@ -251,83 +516,450 @@ func (s *userService) UpdateUser(ctx context.Context, userID string, input Updat
 	return s.userRepo.FindByID(ctx, userID)
 }
 
-// Clerk Webhook からユーザー情報を更新する（avatar_url）。
-func (s *userService) UpdateUserFromClerk(ctx context.Context, userID string, avatarURL *string) error {
-	s.logger.Debug("service.UpdateUserFromClerk started",
-		slog.String("user_id", userID),
+// display_id が指定ユーザーで使用可能かチェックする。
+// - 他ユーザーが現在使用している display_id は使用不可。
+// - 他ユーザーが過去に使用していた display_id も、リダイレクトを維持するため使用不可。
+func (s *userService) ensureDisplayIDAvailable(ctx context.Context, userID, displayID string) error {
+	return ensureDisplayIDAvailable(ctx, s.userRepo, s.displayIDHistoryRepo, userID, displayID)
+}
+
+// 指定したリポジトリ（トランザクション内のものを含む）で display_id の使用可否をチェックする。
+func ensureDisplayIDAvailable(ctx context.Context, userRepo repository.UserRepository, historyRepo repository.UserDisplayIDHistoryRepository, userID, displayID string) error {
+	existing, err := userRepo.FindByDisplayID(ctx, displayID)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+	if err == nil && existing.ID != userID {
+		return ErrDisplayIDTaken
+	}
+	if historyRepo == nil {
+		return nil
+	}
+	history, err := historyRepo.FindByDisplayID(ctx, displayID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		return err
+	}
+	if history.UserID != userID {
+		return ErrDisplayIDTaken
+	}
+	return nil
+}
+
+// display_id の変更を伴うユーザー更新を、履歴の登録と同一トランザクションで行う。
+// - 使用可否はトランザクション内で再チェックし、並行した変更と競合した場合の一意制約違反は ErrDisplayIDTaken として返す。
+func (s *userService) updateUserWithDisplayID(ctx context.Context, current *model.User, newDisplayID string, updates map[string]any) (*model.User, error) {
+	if s.db == nil {
+		return nil, errors.New("db is required")
+	}
+
+	updates["display_id"] = newDisplayID
+	updates["updated_at"] = time.Now()
+
+	var updated *model.User
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		userRepo := repository.NewUserRepository(s.logger, tx)
+		historyRepo := repository.NewUserDisplayIDHistoryRepository(tx)
+
+		if err := ensureDisplayIDAvailable(ctx, userRepo, historyRepo, current.ID, newDisplayID); err != nil {
+			return err
+		}
+
+		// 自分の過去の display_id に戻す場合は、その履歴を削除する
+		if err := historyRepo.DeleteByDisplayID(ctx, newDisplayID); err != nil {
+			return err
+		}
+		if err := historyRepo.Create(ctx, current.ID, current.DisplayID); err != nil {
+			return err
+		}
+		if err := userRepo.Update(ctx, current.ID, updates); err != nil {
+			return err
+		}
+		if isPublicSwitch(updates) {
+			if err := s.acceptAllFollowRequestsTx(ctx, tx, current.ID); err != nil {
+				return err
+			}
+		}
+
+		u, err := userRepo.FindByID(ctx, current.ID)
+		if err != nil {
+			return err
+		}
+		updated = u
+		return nil
+	})
+	if err != nil {
+		if repository.IsUniqueViolation(err) {
+			return nil, ErrDisplayIDTaken
+		}
+		return nil, err
+	}
+	return updated, nil
+}
+
+// 更新内容が公開アカウントへの切り替えを含むかチェックする。
+func isPublicSwitch(updates map[string]any) bool {
+	isPrivate, ok := updates["is_private"].(bool)
+	return ok && !isPrivate
+}
+
+// 承認待ちのフォローリクエストを全て承認し、リクエスト送信者への承認通知イベントを書き込む。
+// 公開アカウントではフォローリクエストが不要になるため、公開への切り替え時にトランザクション内で呼び出す。
+func (s *userService) acceptAllFollowRequestsTx(ctx context.Context, tx *gorm.DB, userID string) error {
+	requesterIDs, err := repository.NewUserFollowRequestRepository(tx).AcceptAllIncoming(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if s.outboxRepo == nil {
+		return nil
+	}
+	outboxRepo := repository.NewOutboxRepository(tx)
+	for _, requesterID := range requesterIDs {
+		if err := enqueueNotificationEvent(ctx, outboxRepo, model.OutboxEventTypeFollowRequestApproved, model.NotificationEventPayload{
+			ActorUserID:     userID,
+			RecipientUserID: requesterID,
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// アイコンURLとして有効な http(s) の絶対URLかチェックする。
+func isValidAvatarURL(raw string) bool {
+	if len(raw) > userAvatarURLMaxLength {
+		return false
+	}
+	u, err := url.Parse(raw)
+	if err != nil {
+		return false
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return false
+	}
+	return u.Host != ""
+}
+
+// 変更前の display_id から、現在の display_id を解決する。
+func (s *userService) ResolveRenamedDisplayID(ctx context.Context, displayID string) (string, error) {
+	if displayID == "" {
+		return "", errors.New("display_id is required")
+	}
+	if s.displayIDHistoryRepo == nil {
+		return "", ErrUserNotFound
+	}
+
+	history, err := s.displayIDHistoryRepo.FindByDisplayID(ctx, displayID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return "", ErrUserNotFound
+		}
+		return "", err
+	}
+
+	user, err := s.userRepo.FindByID(ctx, history.UserID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return "", ErrUserNotFound
+		}
+		return "", err
+	}
+	if user.DeletedAt != nil {
+		return "", ErrUserNotFound
+	}
+
+	return user.DisplayID, nil
+}
+
+// Clerk Webhook のユーザー情報を users テーブルに同期する。
+func (s *userService) SyncUserFromClerk(ctx context.Context, clerkInfo ClerkUserInfo, opts ClerkSyncOptions) (*model.User, error) {
+	s.logger.Debug("service.SyncUserFromClerk started",
+		slog.String("clerk_user_id", clerkInfo.ID),
+		slog.Bool("created", opts.Created),
 	)
+	if clerkInfo.ID == "" {
+		return nil, errors.New("clerk user id is required")
+	}
+	if strings.TrimSpace(clerkInfo.Email) == "" {
+		return nil, errors.New("email is required")
+	}
 
-	userID = strings.TrimSpace(userID)
-	if userID == "" {
-		return errors.New("user_id is required")
+	var synced *model.User
+	err := s.withClerkEventTx(ctx, opts.Event, func(svc *userService) error {
+		u, err := svc.syncUserFromClerk(ctx, clerkInfo, opts.Created)
+		if err != nil {
+			return err
+		}
+		synced = u
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return synced, nil
+}
+
+// Clerk のユーザー情報を users レコードに反映する。
+func (s *userService) syncUserFromClerk(ctx context.Context, clerkInfo ClerkUserInfo, created bool) (*model.User, error) {
+	current, err := s.userRepo.FindByClerkUserID(ctx, clerkInfo.ID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			// 完全削除したユーザーは clerk_user_id を書き換えているため、見つからない場合は user.created のみ作成する
+			// （user.created より先に届いた user.updated は、ユーザーの初回リクエストか user.created で作成される）
+			if !created {
+				return nil, ErrUserNotFound
+			}
+			return s.createUserFromClerk(ctx, clerkInfo)
+		}
+		return nil, err
+	}
+	if current.DeletionStatus == model.UserDeletionStatusPurged {
+		return nil, ErrUserNotFound
 	}
 
+	if created && current.DeletionStatus == model.UserDeletionStatusDeactivated {
+		reactivated, err := s.ReactivateUser(ctx, current.ID)
+		switch {
+		case err == nil:
+			current = reactivated
+		case errors.Is(err, ErrReactivationPeriodExpired):
+			// 完全削除ジョブの実行待ち。復帰はさせず、プロフィールの同期のみ行う
+			s.logger.Warn("service.SyncUserFromClerk: reactivation period expired",
+				slog.String("user_id", current.ID),
+			)
+		default:
+			return nil, err
+		}
+	}
+
+	clerkDisplayName := resolveDisplayName(clerkInfo)
 	updates := map[string]any{
-		"avatar_url": avatarURL,
-		"updated_at": time.Now(),
+		"email":              clerkInfo.Email,
+		"avatar_url":         clerkInfo.AvatarURL,
+		"clerk_display_name": clerkDisplayName,
+		"clerk_username":     optionalString(clerkInfo.Username),
+	}
+
+	// アプリ上で表示名を変更していない場合のみ、Clerk の名前に追従する
+	if current.DisplayName != clerkDisplayName {
+		lastSynced := defaultUserDisplayName
+		if current.ClerkDisplayName != nil {
+			lastSynced = *current.ClerkDisplayName
+		}
+		if current.DisplayName == lastSynced {
+			updates["display_name"] = clerkDisplayName
+		}
+	}
+
+	// アプリ上で display_id を変更していない場合のみ、Clerk のユーザー名に追従する
+	if newDisplayID := clerkUsernameDisplayID(clerkInfo); newDisplayID != "" && newDisplayID != current.DisplayID {
+		followsClerk := strings.HasPrefix(current.DisplayID, userDisplayIDPrefix)
+		if current.ClerkUsername != nil {
+			followsClerk = current.DisplayID == NormalizeCustomUserDisplayID(*current.ClerkUsername)
+		}
+		if followsClerk {
+			err := s.ensureDisplayIDAvailable(ctx, current.ID, newDisplayID)
+			switch {
+			case err == nil:
+				return s.updateUserWithDisplayID(ctx, current, newDisplayID, updates)
+			case errors.Is(err, ErrDisplayIDTaken):
+				// 使用済みの場合は display_id を変更せず、他の項目のみ同期する
+			default:
+				return nil, err
+			}
+		}
+	}
+
+	updates["updated_at"] = time.Now()
+	if err := s.userRepo.Update(ctx, current.ID, updates); err != nil {
+		return nil, err
+	}
+	return s.userRepo.FindByID(ctx, current.ID)
+}
+
+// Clerk ユーザー情報から users レコードを作成する。
+// - Clerk のユーザー名が display_id として使用可能（他のユーザーが使用・予約していない）ならそれを使い、そうでなければランダム生成する。
+// - 並行して同じ Clerk ユーザーが作成された場合は、作成済みのユーザーを返す。
+// - 並行して同じ display_id が使用された場合は、ランダム生成した display_id で作成し直す。
+func (s *userService) createUserFromClerk(ctx context.Context, clerkInfo ClerkUserInfo) (*model.User, error) {
+	displayName := resolveDisplayName(clerkInfo)
+
+	displayID := clerkUsernameDisplayID(clerkInfo)
+	if displayID != "" {
+		err := s.ensureDisplayIDAvailable(ctx, "", displayID)
+		switch {
+		case err == nil:
+		case errors.Is(err, ErrDisplayIDTaken):
+			displayID = ""
+		default:
+			return nil, err
+		}
+	}
+	if displayID == "" {
+		// display_id はランダム生成（重複したら内部で再生成）
+		displayID = GenerateUserDisplayID(ctx, s.userRepo, s.displayIDHistoryRepo)
+	}
+
+	user := &model.User{
+		ClerkUserID:      clerkInfo.ID,
+		DisplayID:        displayID,
+		DisplayName:      displayName,
+		Email:            clerkInfo.Email,
+		AvatarURL:        clerkInfo.AvatarURL,
+		ClerkDisplayName: &displayName,
+		ClerkUsername:    optionalString(clerkInfo.Username),
+	}
+
+	err := s.createUser(ctx, user)
+	if err == nil || !repository.IsUniqueViolation(err) {
+		return user, err
+	}
+
+	existing, findErr := s.userRepo.FindByClerkUserID(ctx, clerkInfo.ID)
+	switch {
+	case findErr == nil:
+		return existing, nil
+	case !errors.Is(findErr, gorm.ErrRecordNotFound):
+		return nil, findErr
+	}
+
+	user.DisplayID = GenerateUserDisplayID(ctx, s.userRepo, s.displayIDHistoryRepo)
+	if err := s.createUser(ctx, user); err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+// users レコードを作成する。
+// トランザクション内で一意制約に違反しても処理を続けられるよう、セーブポイント（トランザクション外では単独のトランザクション）で作成する。
+func (s *userService) createUser(ctx context.Context, user *model.User) error {
+	if s.db == nil {
+		return s.userRepo.Create(ctx, user)
 	}
+	return s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return repository.NewUserRepository(s.logger, tx).Create(ctx, user)
+	})
+}
 
-	return s.userRepo.Update(ctx, userID, updates)
+// 空文字を nil として扱う。
+func optionalString(v string) *string {
+	if v == "" {
+		return nil
+	}
+	return &v
 }
 
 // 指定ユーザーをフォローする。
-func (s *userService) FollowUser(ctx context.Context, followerID, followeeID string) error {
+func (s *userService) FollowUser(ctx context.Context, followerID, followeeID string) (FollowStatus, error) {
 	// 開始ログ（DEBUG）
 	s.logger.Debug("service.FollowUser started",
 		slog.String("follower_id", followerID),
 		slog.String("followee_id", followeeID),
 	)
 	if followerID == "" || followeeID == "" {
-		return errors.New("follower_id and followee_id are required")
+		return "", errors.New("follower_id and followee_id are required")
 	}
 
 	if followerID == followeeID {
-		return ErrCannotFollowSelf
+		return "", ErrCannotFollowSelf
 	}
 
 	// フォロー対象のユーザーが存在するか確認
 	followee, err := s.userRepo.FindByID(ctx, followeeID)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return ErrUserNotFound
+			return "", ErrUserNotFound
 		}
-		return err
+		return "", err
 	}
 	if followee != nil && followee.DeletedAt != nil {
-		return ErrUserNotFound
+		return "", ErrUserNotFound
+	}
+
+	// ブロック関係にある場合はフォロー不可
+	if s.userBlockRepo != nil {
+		blocked, err := s.userBlockRepo.IsBlockedEither(ctx, followerID, followeeID)
+		if err != nil {
+			return "", err
+		}
+		if blocked {
+			return "", ErrUserBlocked
+		}
 	}
 
 	// 既にフォロー済みかチェック
 	isFollowing, err := s.userFollowerRepo.IsFollowing(ctx, followerID, followeeID)
 	if err != nil {
-		return err
+		return "", err
 	}
 	if isFollowing {
-		return ErrAlreadyFollowing
+		return "", ErrAlreadyFollowing
 	}
 
-	if err := s.userFollowerRepo.Create(ctx, followerID, followeeID); err != nil {
-		return err
+	// 非公開アカウントの場合はフォローリクエストを作成する
+	if followee.IsPrivate && s.followRequestRepo != nil {
+		exists, err := s.followRequestRepo.Exists(ctx, followerID, followeeID)
+		if err != nil {
+			return "", err
+		}
+		if exists {
+			return "", ErrFollowRequestAlreadySent
+		}
+		err = s.withFollowTx(ctx, func(repos userFollowTxRepos) error {
+			if err := repos.followRequest.Create(ctx, followerID, followeeID); err != nil {
+				return err
+			}
+			return enqueueNotificationEvent(ctx, repos.outbox, model.OutboxEventTypeFollowRequested, model.NotificationEventPayload{
+				ActorUserID:     followerID,
+				RecipientUserID: followeeID,
+			})
+		})
+		if err != nil {
+			return "", err
+		}
+
+		return FollowStatusRequested, nil
 	}
 
-	// フォローされたユーザーに通知（非同期）
-	if s.notificationService != nil {
-		logger := s.logger
-		notifSvc := s.notificationService
-		go func() {
-			ctx2, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-			defer cancel()
-			if err := notifSvc.NotifyUserFollowed(ctx2, followeeID, followerID); err != nil {
-				logger.Error("service.FollowUser failed to send notification",
-					slog.String("followee_id", followeeID),
-					slog.String("follower_id", followerID),
-					slog.Any("error", err),
-				)
-			}
-		}()
+	// フォロー関係の作成と、フォローされたユーザーへの通知イベントの書き込みを同じトランザクションで行う
+	err = s.withFollowTx(ctx, func(repos userFollowTxRepos) error {
+		if err := repos.userFollower.Create(ctx, followerID, followeeID); err != nil {
+			return err
+		}
+		return enqueueNotificationEvent(ctx, repos.outbox, model.OutboxEventTypeUserFollowed, model.NotificationEventPayload{
+			ActorUserID:     followerID,
+			RecipientUserID: followeeID,
+		})
+	})
+	if err != nil {
+		return "", err
 	}
 
-	return nil
+	return FollowStatusFollowing, nil
+}
+
+// fn をトランザクション内で実行する。
+// db が未設定の場合（ユニットテストなど）はトランザクションを張らず、保持しているリポジトリで実行する。
+func (s *userService) withFollowTx(ctx context.Context, fn func(repos userFollowTxRepos) error) error {
+	if s.db == nil {
+		return fn(userFollowTxRepos{
+			userFollower:  s.userFollowerRepo,
+			followRequest: s.followRequestRepo,
+			outbox:        s.outboxRepo,
+		})
+	}
+	return s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		repos := userFollowTxRepos{
+			userFollower:  repository.NewUserFollowerRepository(tx),
+			followRequest: repository.NewUserFollowRequestRepository(tx),
+		}
+		if s.outboxRepo != nil {
+			repos.outbox = repository.NewOutboxRepository(tx)
+		}
+		return fn(repos)
+	})
 }
 
 // 指定ユーザーをアンフォローする。
@@ -342,12 +974,128 @@ func (s *userService) UnfollowUser(ctx context.Context, followerID, followeeID s
 		return err
 	}
 	if !isFollowing {
+		// 承認待ちのフォローリクエストがあれば取り消す
+		if s.followRequestRepo != nil {
+			pending, err := s.followRequestRepo.Exists(ctx, followerID, followeeID)
+			if err != nil {
+				return err
+			}
+			if pending {
+				return s.followRequestRepo.Delete(ctx, followerID, followeeID)
+			}
+		}
 		return ErrNotFollowing
 	}
 
 	return s.userFollowerRepo.Delete(ctx, followerID, followeeID)
 }
 
+// 自分宛てのフォローリクエストを承認し、フォロー関係を作成する。
+func (s *userService) ApproveFollowRequest(ctx context.Context, targetID, requesterID string) error {
+	s.logger.Debug("service.ApproveFollowRequest started",
+		slog.String("target_id", targetID),
+		slog.String("requester_id", requesterID),
+	)
+	if targetID == "" || requesterID == "" {
+		return errors.New("target_id and requester_id are required")
+	}
+
+	exists, err := s.followRequestRepo.Exists(ctx, requesterID, targetID)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return ErrFollowRequestNotFound
+	}
+
+	// リクエストの削除・フォロー関係の作成・リクエスト送信者への通知イベントの書き込みを同じトランザクションで行う
+	return s.withFollowTx(ctx, func(repos userFollowTxRepos) error {
+		if err := repos.followRequest.Delete(ctx, requesterID, targetID); err != nil {
+			return err
+		}
+
+		isFollowing, err := repos.userFollower.IsFollowing(ctx, requesterID, targetID)
+		if err != nil {
+			return err
+		}
+		if !isFollowing {
+			if err := repos.userFollower.Create(ctx, requesterID, targetID); err != nil {
+				return err
+			}
+		}
+
+		return enqueueNotificationEvent(ctx, repos.outbox, model.OutboxEventTypeFollowRequestApproved, model.NotificationEventPayload{
+			ActorUserID:     targetID,
+			RecipientUserID: requesterID,
+		})
+	})
+}
+
+// 自分宛てのフォローリクエストを拒否する。
+func (s *userService) DenyFollowRequest(ctx context.Context, targetID, requesterID string) error {
+	s.logger.Debug("service.DenyFollowRequest started",
+		slog.String("target_id", targetID),
+		slog.String("requester_id", requesterID),
+	)
+	if targetID == "" || requesterID == "" {
+		return errors.New("target_id and requester_id are required")
+	}
+
+	exists, err := s.followRequestRepo.Exists(ctx, requesterID, targetID)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return ErrFollowRequestNotFound
+	}
+
+	return s.withFollowTx(ctx, func(repos userFollowTxRepos) error {
+		if err := repos.followRequest.Delete(ctx, requesterID, targetID); err != nil {
+			return err
+		}
+		return enqueueNotificationEvent(ctx, repos.outbox, model.OutboxEventTypeFollowRequestDenied, model.NotificationEventPayload{
+			ActorUserID:     targetID,
+			RecipientUserID: requesterID,
+		})
+	})
+}
+
+// 自分宛ての承認待ちフォローリクエストを送ったユーザー一覧を取得する。
+func (s *userService) ListFollowRequests(ctx context.Context, userID string, page, pageSize int) ([]*model.User, int64, error) {
+	if userID == "" {
+		return nil, 0, errors.New("user_id is required")
+	}
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 || pageSize > 100 {
+		pageSize = 20
+	}
+	return s.followRequestRepo.ListIncoming(ctx, userID, page, pageSize)
+}
+
+// requesterID から targetID への承認待ちフォローリクエストが存在するかチェックする。
+func (s *userService) HasPendingFollowRequest(ctx context.Context, requesterID, targetID string) (bool, error) {
+	if requesterID == "" || targetID == "" || s.followRequestRepo == nil {
+		return false, nil
+	}
+	return s.followRequestRepo.Exists(ctx, requesterID, targetID)
+}
+
+// viewerID のユーザーが owner のコンテンツを閲覧できるかチェックする。
+func (s *userService) CanViewUserContent(ctx context.Context, viewerID string, owner *model.User) (bool, error) {
+	if owner == nil {
+		return false, nil
+	}
+	if !owner.IsPrivate || viewerID == owner.ID {
+		return true, nil
+	}
+	if viewerID == "" {
+		return false, nil
+	}
+	return s.userFollowerRepo.IsFollowing(ctx, viewerID, owner.ID)
+}
+
 // followerID が followeeID をフォローしているかチェックする。
 func (s *userService) IsFollowing(ctx context.Context, followerID, followeeID string) (bool, error) {
 	if followerID == "" || followeeID == "" {
@@ -403,45 +1151,174 @@ func (s *userService) GetFollowStats(ctx context.Context, userID string) (follow
 	return following, followers, nil
 }
 
-// ユーザーを、DB側で論理削除＋匿名化し、関連データをクリーンアップする。
-func (s *userService) DeactivateUser(ctx context.Context, userID string) error {
-	userID = strings.TrimSpace(userID)
-	if userID == "" {
-		return errors.New("user id is required")
+// 指定ユーザーをブロックする。
+func (s *userService) BlockUser(ctx context.Context, blockerID, blockedID string) error {
+	s.logger.Debug("service.BlockUser started",
+		slog.String("blocker_id", blockerID),
+		slog.String("blocked_id", blockedID),
+	)
+	if blockerID == "" || blockedID == "" {
+		return errors.New("blocker_id and blocked_id are required")
+	}
+	if blockerID == blockedID {
+		return ErrCannotBlockSelf
+	}
+
+	target, err := s.userRepo.FindByID(ctx, blockedID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrUserNotFound
+		}
+		return err
+	}
+	if target != nil && target.DeletedAt != nil {
+		return ErrUserNotFound
 	}
+
+	isBlocking, err := s.userBlockRepo.IsBlocking(ctx, blockerID, blockedID)
+	if err != nil {
+		return err
+	}
+	if isBlocking {
+		return ErrAlreadyBlocked
+	}
+
 	if s.db == nil {
 		return errors.New("db is required")
 	}
 
-	now := time.Now()
-
-	return s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
-		userRepo := repository.NewUserRepository(s.logger, tx)
+	err = s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		userBlockRepo := repository.NewUserBlockRepository(tx)
 		userFollowerRepo := repository.NewUserFollowerRepository(tx)
-		tagFollowerRepo := repository.NewTagFollowerRepository(tx)
+		followRequestRepo := repository.NewUserFollowRequestRepository(tx)
 
-		u, err := userRepo.FindByID(ctx, userID)
-		if err != nil {
-			if errors.Is(err, gorm.ErrRecordNotFound) {
-				return ErrUserNotFound
-			}
+		if err := userBlockRepo.Create(ctx, blockerID, blockedID); err != nil {
 			return err
 		}
 
-		// ユーザーを論理削除＋匿名化
-		anonymizedEmail := fmt.Sprintf("deleted+%s@example.invalid", u.ID)
-		if err := userRepo.UpdateForUserDeactivated(ctx, u.ID, now, anonymizedEmail); err != nil {
+		// 双方向のフォロー関係・フォローリクエストを削除
+		if err := userFollowerRepo.Delete(ctx, blockerID, blockedID); err != nil {
 			return err
 		}
-
-		// 当該ユーザーに紐づくフォロー関係をクリーンアップ
-		if err := tagFollowerRepo.DeleteAllByUserID(ctx, u.ID); err != nil {
+		if err := userFollowerRepo.Delete(ctx, blockedID, blockerID); err != nil {
 			return err
 		}
-		if err := userFollowerRepo.DeleteAllByUserID(ctx, u.ID); err != nil {
+		if err := followRequestRepo.Delete(ctx, blockerID, blockedID); err != nil {
+			return err
+		}
+		if err := followRequestRepo.Delete(ctx, blockedID, blockerID); err != nil {
 			return err
 		}
 
 		return nil
 	})
+	// 確認と作成の間に同じブロックが別リクエストで作成された場合
+	if repository.IsUniqueViolation(err) {
+		return ErrAlreadyBlocked
+	}
+	return err
+}
+
+// 指定ユーザーのブロックを解除する。
+func (s *userService) UnblockUser(ctx context.Context, blockerID, blockedID string) error {
+	if blockerID == "" || blockedID == "" {
+		return errors.New("blocker_id and blocked_id are required")
+	}
+
+	isBlocking, err := s.userBlockRepo.IsBlocking(ctx, blockerID, blockedID)
+	if err != nil {
+		return err
+	}
+	if !isBlocking {
+		return ErrNotBlocked
+	}
+
+	return s.userBlockRepo.Delete(ctx, blockerID, blockedID)
+}
+
+// 指定ユーザーをミュートする。
+func (s *userService) MuteUser(ctx context.Context, muterID, mutedID string) error {
+	s.logger.Debug("service.MuteUser started",
+		slog.String("muter_id", muterID),
+		slog.String("muted_id", mutedID),
+	)
+	if muterID == "" || mutedID == "" {
+		return errors.New("muter_id and muted_id are required")
+	}
+	if muterID == mutedID {
+		return ErrCannotBlockSelf
+	}
+
+	target, err := s.userRepo.FindByID(ctx, mutedID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrUserNotFound
+		}
+		return err
+	}
+	if target != nil && target.DeletedAt != nil {
+		return ErrUserNotFound
+	}
+
+	isMuting, err := s.userMuteRepo.IsMuting(ctx, muterID, mutedID)
+	if err != nil {
+		return err
+	}
+	if isMuting {
+		return ErrAlreadyMuted
+	}
+
+	if err := s.userMuteRepo.Create(ctx, muterID, mutedID); err != nil {
+		// 確認と作成の間に同じミュートが別リクエストで作成された場合
+		if repository.IsUniqueViolation(err) {
+			return ErrAlreadyMuted
+		}
+		return err
+	}
+	return nil
+}
+
+// 指定ユーザーのミュートを解除する。
+func (s *userService) UnmuteUser(ctx context.Context, muterID, mutedID string) error {
+	if muterID == "" || mutedID == "" {
+		return errors.New("muter_id and muted_id are required")
+	}
+
+	isMuting, err := s.userMuteRepo.IsMuting(ctx, muterID, mutedID)
+	if err != nil {
+		return err
+	}
+	if !isMuting {
+		return ErrNotMuted
+	}
+
+	return s.userMuteRepo.Delete(ctx, muterID, mutedID)
+}
+
+// 指定ユーザーがブロックしているユーザー一覧を取得する。
+func (s *userService) ListBlockedUsers(ctx context.Context, userID string, page, pageSize int) ([]*model.User, int64, error) {
+	if userID == "" {
+		return nil, 0, errors.New("user_id is required")
+	}
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 || pageSize > 100 {
+		pageSize = 20
+	}
+	return s.userBlockRepo.ListBlocked(ctx, userID, page, pageSize)
+}
+
+// 指定ユーザーがミュートしているユーザー一覧を取得する。
+func (s *userService) ListMutedUsers(ctx context.Context, userID string, page, pageSize int) ([]*model.User, int64, error) {
+	if userID == "" {
+		return nil, 0, errors.New("user_id is required")
+	}
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 || pageSize > 100 {
+		pageSize = 20
+	}
+	return s.userMuteRepo.ListMuted(ctx, userID, page, pageSize)
 }
@@ -3,6 +3,7 @@ package service
 import (
 	"context"
 	"errors"
+	"strings"
 	"testing"
 	"time"
 
@@ -10,16 +11,18 @@ import (
 	"cinetag-backend/src/internal/repository"
 	"cinetag-backend/src/internal/testutil"
 
+	"github.com/jackc/pgx/v5/pgconn"
 	"gorm.io/gorm"
 )
 
 type fakeUserRepo struct {
-	FindByIDFn                 func(ctx context.Context, userID string) (*model.User, error)
-	FindByClerkUserIDFn        func(ctx context.Context, clerkUserID string) (*model.User, error)
-	FindByDisplayIDFn          func(ctx context.Context, displayID string) (*model.User, error)
-	CreateFn                   func(ctx context.Context, user *model.User) error
-	UpdateFn                   func(ctx context.Context, userID string, updates map[string]any) error
-	UpdateForUserDeactivatedFn func(ctx context.Context, userID string, now time.Time, anonymizedEmail string) error
+	FindByIDFn               func(ctx context.Context, userID string) (*model.User, error)
+	FindByClerkUserIDFn      func(ctx context.Context, clerkUserID string) (*model.User, error)
+	FindByDisplayIDFn        func(ctx context.Context, displayID string) (*model.User, error)
+	ListActiveByDisplayIDsFn func(ctx context.Context, displayIDs []string) ([]*model.User, error)
+	CreateFn                 func(ctx context.Context, user *model.User) error
+	UpdateFn                 func(ctx context.Context, userID string, updates map[string]any) error
+	SearchFn                 func(ctx context.Context, filter repository.UserSearchFilter) ([]repository.UserSearchRow, int64, error)
 }
 
 func (f *fakeUserRepo) FindByID(ctx context.Context, userID string) (*model.User, error) {
@@ -43,6 +46,13 @@ func (f *fakeUserRepo) FindByDisplayID(ctx context.Context, displayID string) (*
 	return f.FindByDisplayIDFn(ctx, displayID)
 }
 
+func (f *fakeUserRepo) ListActiveByDisplayIDs(ctx context.Context, displayIDs []string) ([]*model.User, error) {
+	if f.ListActiveByDisplayIDsFn == nil {
+		return []*model.User{}, nil
+	}
+	return f.ListActiveByDisplayIDsFn(ctx, displayIDs)
+}
+
 func (f *fakeUserRepo) Create(ctx context.Context, user *model.User) error {
 	if f.CreateFn == nil {
 		user.ID = "u1"
@@ -58,11 +68,11 @@ func (f *fakeUserRepo) Update(ctx context.Context, userID string, updates map[st
 	return f.UpdateFn(ctx, userID, updates)
 }
 
-func (f *fakeUserRepo) UpdateForUserDeactivated(ctx context.Context, userID string, now time.Time, anonymizedEmail string) error {
-	if f.UpdateForUserDeactivatedFn == nil {
-		return nil
+func (f *fakeUserRepo) Search(ctx context.Context, filter repository.UserSearchFilter) ([]repository.UserSearchRow, int64, error) {
+	if f.SearchFn == nil {
+		return []repository.UserSearchRow{}, 0, nil
 	}
-	return f.UpdateForUserDeactivatedFn(ctx, userID, now, anonymizedEmail)
+	return f.SearchFn(ctx, filter)
 }
 
 type fakeUserFollowerRepo struct {
@@ -70,10 +80,12 @@ type fakeUserFollowerRepo struct {
 	DeleteFn            func(ctx context.Context, followerID, followeeID string) error
 	DeleteAllByUserIDFn func(ctx context.Context, userID string) error
 	IsFollowingFn       func(ctx context.Context, followerID, followeeID string) (bool, error)
+	ListFollowingIDsFn  func(ctx context.Context, followerID string, followeeIDs []string) ([]string, error)
 	ListFollowingFn     func(ctx context.Context, userID string, page, pageSize int) ([]*model.User, int64, error)
 	ListFollowersFn     func(ctx context.Context, userID string, page, pageSize int) ([]*model.User, int64, error)
 	CountFollowingFn    func(ctx context.Context, userID string) (int64, error)
 	CountFollowersFn    func(ctx context.Context, userID string) (int64, error)
+	ListSuggestionsFn   func(ctx context.Context, userID string, limit int) ([]repository.UserSuggestionRow, error)
 }
 
 func (f *fakeUserFollowerRepo) Create(ctx context.Context, followerID, followeeID string) error {
@@ -104,6 +116,13 @@ func (f *fakeUserFollowerRepo) IsFollowing(ctx context.Context, followerID, foll
 	return f.IsFollowingFn(ctx, followerID, followeeID)
 }
 
+func (f *fakeUserFollowerRepo) ListFollowingIDs(ctx context.Context, followerID string, followeeIDs []string) ([]string, error) {
+	if f.ListFollowingIDsFn == nil {
+		return []string{}, nil
+	}
+	return f.ListFollowingIDsFn(ctx, followerID, followeeIDs)
+}
+
 func (f *fakeUserFollowerRepo) ListFollowing(ctx context.Context, userID string, page, pageSize int) ([]*model.User, int64, error) {
 	if f.ListFollowingFn == nil {
 		return []*model.User{}, 0, nil
@@ -136,14 +155,125 @@ func (f *fakeUserFollowerRepo) ListFollowerIDs(ctx context.Context, userID strin
 	return []string{}, nil
 }
 
+func (f *fakeUserFollowerRepo) ListSuggestions(ctx context.Context, userID string, limit int) ([]repository.UserSuggestionRow, error) {
+	if f.ListSuggestionsFn == nil {
+		return []repository.UserSuggestionRow{}, nil
+	}
+	return f.ListSuggestionsFn(ctx, userID, limit)
+}
+
+type fakeUserDisplayIDHistoryRepo struct {
+	CreateFn            func(ctx context.Context, userID, displayID string) error
+	FindByDisplayIDFn   func(ctx context.Context, displayID string) (*model.UserDisplayIDHistory, error)
+	DeleteByDisplayIDFn func(ctx context.Context, displayID string) error
+}
+
+func (f *fakeUserDisplayIDHistoryRepo) Create(ctx context.Context, userID, displayID string) error {
+	if f.CreateFn == nil {
+		return nil
+	}
+	return f.CreateFn(ctx, userID, displayID)
+}
+
+func (f *fakeUserDisplayIDHistoryRepo) FindByDisplayID(ctx context.Context, displayID string) (*model.UserDisplayIDHistory, error) {
+	if f.FindByDisplayIDFn == nil {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return f.FindByDisplayIDFn(ctx, displayID)
+}
+
+func (f *fakeUserDisplayIDHistoryRepo) DeleteByDisplayID(ctx context.Context, displayID string) error {
+	if f.DeleteByDisplayIDFn == nil {
+		return nil
+	}
+	return f.DeleteByDisplayIDFn(ctx, displayID)
+}
+
+type fakeUserFollowRequestRepo struct {
+	CreateFn            func(ctx context.Context, requesterID, targetID string) error
+	DeleteFn            func(ctx context.Context, requesterID, targetID string) error
+	DeleteAllByUserIDFn func(ctx context.Context, userID string) error
+	ExistsFn            func(ctx context.Context, requesterID, targetID string) (bool, error)
+	ListIncomingFn      func(ctx context.Context, targetID string, page, pageSize int) ([]*model.User, int64, error)
+	AcceptAllIncomingFn func(ctx context.Context, targetID string) ([]string, error)
+}
+
+func (f *fakeUserFollowRequestRepo) Create(ctx context.Context, requesterID, targetID string) error {
+	if f.CreateFn == nil {
+		return nil
+	}
+	return f.CreateFn(ctx, requesterID, targetID)
+}
+
+func (f *fakeUserFollowRequestRepo) Delete(ctx context.Context, requesterID, targetID string) error {
+	if f.DeleteFn == nil {
+		return nil
+	}
+	return f.DeleteFn(ctx, requesterID, targetID)
+}
+
+func (f *fakeUserFollowRequestRepo) DeleteAllByUserID(ctx context.Context, userID string) error {
+	if f.DeleteAllByUserIDFn == nil {
+		return nil
+	}
+	return f.DeleteAllByUserIDFn(ctx, userID)
+}
+
+func (f *fakeUserFollowRequestRepo) Exists(ctx context.Context, requesterID, targetID string) (bool, error) {
+	if f.ExistsFn == nil {
+		return false, nil
+	}
+	return f.ExistsFn(ctx, requesterID, targetID)
+}
+
+func (f *fakeUserFollowRequestRepo) ListIncoming(ctx context.Context, targetID string, page, pageSize int) ([]*model.User, int64, error) {
+	if f.ListIncomingFn == nil {
+		return []*model.User{}, 0, nil
+	}
+	return f.ListIncomingFn(ctx, targetID, page, pageSize)
+}
+
+func (f *fakeUserFollowRequestRepo) AcceptAllIncoming(ctx context.Context, targetID string) ([]string, error) {
+	if f.AcceptAllIncomingFn == nil {
+		return []string{}, nil
+	}
+	return f.AcceptAllIncomingFn(ctx, targetID)
+}
+
+// userServiceDeps は newUserService で UserService に渡すリポジトリです。
+// userRepo / userFollowerRepo 以外は既定で nil（使わない構成）とし、必要なテストだけが設定します。
+type userServiceDeps struct {
+	userRepo             repository.UserRepository
+	userFollowerRepo     repository.UserFollowerRepository
+	tagFollowerRepo      repository.TagFollowerRepository
+	outboxRepo           repository.OutboxRepository
+	displayIDHistoryRepo repository.UserDisplayIDHistoryRepository
+	userBlockRepo        repository.UserBlockRepository
+	userMuteRepo         repository.UserMuteRepository
+	followRequestRepo    repository.UserFollowRequestRepository
+}
+
+func newUserService(t *testing.T, opt func(*userServiceDeps)) UserService {
+	t.Helper()
+
+	logger := testutil.NewTestLogger()
+	d := &userServiceDeps{
+		userRepo:         &fakeUserRepo{},
+		userFollowerRepo: &fakeUserFollowerRepo{},
+	}
+	if opt != nil {
+		opt(d)
+	}
+	return NewUserService(logger, nil, d.userRepo, d.userFollowerRepo, d.tagFollowerRepo, d.outboxRepo, d.displayIDHistoryRepo, d.userBlockRepo, d.userMuteRepo, d.followRequestRepo)
+}
+
 func TestUserService_EnsureUser(t *testing.T) {
 	t.Parallel()
 
 	t.Run("入力バリデーション: clerk user id が必須", func(t *testing.T) {
 		t.Parallel()
 
-		logger := testutil.NewTestLogger()
-		svc := NewUserService(logger, nil, &fakeUserRepo{}, &fakeUserFollowerRepo{}, nil, nil)
+		svc := newUserService(t, nil)
 		_, err := svc.EnsureUser(context.Background(), ClerkUserInfo{ID: "", Email: "a@example.com"})
 		if err == nil {
 			t.Fatalf("expected error")
@@ -153,8 +283,7 @@ func TestUserService_EnsureUser(t *testing.T) {
 	t.Run("入力バリデーション: email が必須", func(t *testing.T) {
 		t.Parallel()
 
-		logger := testutil.NewTestLogger()
-		svc := NewUserService(logger, nil, &fakeUserRepo{}, &fakeUserFollowerRepo{}, nil, nil)
+		svc := newUserService(t, nil)
 		_, err := svc.EnsureUser(context.Background(), ClerkUserInfo{ID: "clerk_1", Email: ""})
 		if err == nil {
 			t.Fatalf("expected error")
@@ -175,8 +304,9 @@ func TestUserService_EnsureUser(t *testing.T) {
 				return nil
 			},
 		}
-		logger := testutil.NewTestLogger()
-		svc := NewUserService(logger, nil, repo, &fakeUserFollowerRepo{}, nil, nil)
+		svc := newUserService(t, func(d *userServiceDeps) {
+			d.userRepo = repo
+		})
 
 		out, err := svc.EnsureUser(context.Background(), ClerkUserInfo{ID: "clerk_1", Email: "a@example.com"})
 		if err != nil {
@@ -199,8 +329,9 @@ func TestUserService_EnsureUser(t *testing.T) {
 				return nil, expected
 			},
 		}
-		logger := testutil.NewTestLogger()
-		svc := NewUserService(logger, nil, repo, &fakeUserFollowerRepo{}, nil, nil)
+		svc := newUserService(t, func(d *userServiceDeps) {
+			d.userRepo = repo
+		})
 
 		_, err := svc.EnsureUser(context.Background(), ClerkUserInfo{ID: "clerk_1", Email: "a@example.com"})
 		if !errors.Is(err, expected) {
@@ -227,8 +358,9 @@ func TestUserService_EnsureUser(t *testing.T) {
 				return nil
 			},
 		}
-		logger := testutil.NewTestLogger()
-		svc := NewUserService(logger, nil, repo, &fakeUserFollowerRepo{}, nil, nil)
+		svc := newUserService(t, func(d *userServiceDeps) {
+			d.userRepo = repo
+		})
 
 		avatar := "https://example.com/a.png"
 		out, err := svc.EnsureUser(context.Background(), ClerkUserInfo{
@@ -273,8 +405,9 @@ func TestUserService_EnsureUser(t *testing.T) {
 				return nil
 			},
 		}
-		logger := testutil.NewTestLogger()
-		svc := NewUserService(logger, nil, repo, &fakeUserFollowerRepo{}, nil, nil)
+		svc := newUserService(t, func(d *userServiceDeps) {
+			d.userRepo = repo
+		})
 
 		_, err := svc.EnsureUser(context.Background(), ClerkUserInfo{
 			ID:        "clerk_1",
@@ -305,8 +438,9 @@ func TestUserService_EnsureUser(t *testing.T) {
 				return expected
 			},
 		}
-		logger := testutil.NewTestLogger()
-		svc := NewUserService(logger, nil, repo, &fakeUserFollowerRepo{}, nil, nil)
+		svc := newUserService(t, func(d *userServiceDeps) {
+			d.userRepo = repo
+		})
 
 		_, err := svc.EnsureUser(context.Background(), ClerkUserInfo{ID: "clerk_1", Email: "a@example.com"})
 		if !errors.Is(err, expected) {
@@ -323,8 +457,7 @@ func TestUserService_FindUserByClerkUserID(t *testing.T) {
 
 	t.Run("入力バリデーション: clerk_user_id が必須", func(t *testing.T) {
 		t.Parallel()
-		logger := testutil.NewTestLogger()
-		svc := NewUserService(logger, nil, &fakeUserRepo{}, &fakeUserFollowerRepo{}, nil, nil)
+		svc := newUserService(t, nil)
 		_, err := svc.FindUserByClerkUserID(context.Background(), "")
 		if err == nil {
 			t.Fatalf("expected error")
@@ -338,8 +471,9 @@ func TestUserService_FindUserByClerkUserID(t *testing.T) {
 				return nil, gorm.ErrRecordNotFound
 			},
 		}
-		logger := testutil.NewTestLogger()
-		svc := NewUserService(logger, nil, repo, &fakeUserFollowerRepo{}, nil, nil)
+		svc := newUserService(t, func(d *userServiceDeps) {
+			d.userRepo = repo
+		})
 
 		_, err := svc.FindUserByClerkUserID(context.Background(), "clerk_1")
 		if !errors.Is(err, ErrUserNotFound) {
@@ -355,8 +489,9 @@ func TestUserService_FindUserByClerkUserID(t *testing.T) {
 				return nil, expected
 			},
 		}
-		logger := testutil.NewTestLogger()
-		svc := NewUserService(logger, nil, repo, &fakeUserFollowerRepo{}, nil, nil)
+		svc := newUserService(t, func(d *userServiceDeps) {
+			d.userRepo = repo
+		})
 
 		_, err := svc.FindUserByClerkUserID(context.Background(), "clerk_1")
 		if !errors.Is(err, expected) {
@@ -372,8 +507,9 @@ func TestUserService_FindUserByClerkUserID(t *testing.T) {
 				return expected, nil
 			},
 		}
-		logger := testutil.NewTestLogger()
-		svc := NewUserService(logger, nil, repo, &fakeUserFollowerRepo{}, nil, nil)
+		svc := newUserService(t, func(d *userServiceDeps) {
+			d.userRepo = repo
+		})
 
 		user, err := svc.FindUserByClerkUserID(context.Background(), "clerk_1")
 		if err != nil {
@@ -390,8 +526,7 @@ func TestUserService_GetUserByDisplayID(t *testing.T) {
 
 	t.Run("入力バリデーション: display_id が必須", func(t *testing.T) {
 		t.Parallel()
-		logger := testutil.NewTestLogger()
-		svc := NewUserService(logger, nil, &fakeUserRepo{}, &fakeUserFollowerRepo{}, nil, nil)
+		svc := newUserService(t, nil)
 		_, err := svc.GetUserByDisplayID(context.Background(), "")
 		if err == nil {
 			t.Fatalf("expected error")
@@ -405,8 +540,9 @@ func TestUserService_GetUserByDisplayID(t *testing.T) {
 				return nil, gorm.ErrRecordNotFound
 			},
 		}
-		logger := testutil.NewTestLogger()
-		svc := NewUserService(logger, nil, repo, &fakeUserFollowerRepo{}, nil, nil)
+		svc := newUserService(t, func(d *userServiceDeps) {
+			d.userRepo = repo
+		})
 
 		_, err := svc.GetUserByDisplayID(context.Background(), "user1")
 		if !errors.Is(err, ErrUserNotFound) {
@@ -422,8 +558,9 @@ func TestUserService_GetUserByDisplayID(t *testing.T) {
 				return &model.User{ID: "u1", DisplayID: displayID, DeletedAt: &now}, nil
 			},
 		}
-		logger := testutil.NewTestLogger()
-		svc := NewUserService(logger, nil, repo, &fakeUserFollowerRepo{}, nil, nil)
+		svc := newUserService(t, func(d *userServiceDeps) {
+			d.userRepo = repo
+		})
 
 		_, err := svc.GetUserByDisplayID(context.Background(), "user1")
 		if !errors.Is(err, ErrUserNotFound) {
@@ -439,8 +576,9 @@ func TestUserService_GetUserByDisplayID(t *testing.T) {
 				return expected, nil
 			},
 		}
-		logger := testutil.NewTestLogger()
-		svc := NewUserService(logger, nil, repo, &fakeUserFollowerRepo{}, nil, nil)
+		svc := newUserService(t, func(d *userServiceDeps) {
+			d.userRepo = repo
+		})
 
 		user, err := svc.GetUserByDisplayID(context.Background(), "user1")
 		if err != nil {
@@ -457,8 +595,7 @@ func TestUserService_UpdateUser(t *testing.T) {
 
 	t.Run("入力バリデーション: user_id が必須", func(t *testing.T) {
 		t.Parallel()
-		logger := testutil.NewTestLogger()
-		svc := NewUserService(logger, nil, &fakeUserRepo{}, &fakeUserFollowerRepo{}, nil, nil)
+		svc := newUserService(t, nil)
 		displayName := "NewName"
 		_, err := svc.UpdateUser(context.Background(), "", UpdateUserInput{DisplayName: &displayName})
 		if err == nil {
@@ -468,8 +605,7 @@ func TestUserService_UpdateUser(t *testing.T) {
 
 	t.Run("入力バリデーション: 空白のみのuser_idはエラー", func(t *testing.T) {
 		t.Parallel()
-		logger := testutil.NewTestLogger()
-		svc := NewUserService(logger, nil, &fakeUserRepo{}, &fakeUserFollowerRepo{}, nil, nil)
+		svc := newUserService(t, nil)
 		displayName := "NewName"
 		_, err := svc.UpdateUser(context.Background(), "   ", UpdateUserInput{DisplayName: &displayName})
 		if err == nil {
@@ -479,8 +615,7 @@ func TestUserService_UpdateUser(t *testing.T) {
 
 	t.Run("入力バリデーション: display_name が空文字はエラー", func(t *testing.T) {
 		t.Parallel()
-		logger := testutil.NewTestLogger()
-		svc := NewUserService(logger, nil, &fakeUserRepo{}, &fakeUserFollowerRepo{}, nil, nil)
+		svc := newUserService(t, nil)
 		displayName := ""
 		_, err := svc.UpdateUser(context.Background(), "u1", UpdateUserInput{DisplayName: &displayName})
 		if err == nil {
@@ -490,8 +625,7 @@ func TestUserService_UpdateUser(t *testing.T) {
 
 	t.Run("入力バリデーション: display_name が100文字超はエラー", func(t *testing.T) {
 		t.Parallel()
-		logger := testutil.NewTestLogger()
-		svc := NewUserService(logger, nil, &fakeUserRepo{}, &fakeUserFollowerRepo{}, nil, nil)
+		svc := newUserService(t, nil)
 		longName := ""
 		for i := 0; i < 101; i++ {
 			longName += "a"
@@ -504,8 +638,7 @@ func TestUserService_UpdateUser(t *testing.T) {
 
 	t.Run("入力バリデーション: 更新フィールドが空はエラー", func(t *testing.T) {
 		t.Parallel()
-		logger := testutil.NewTestLogger()
-		svc := NewUserService(logger, nil, &fakeUserRepo{}, &fakeUserFollowerRepo{}, nil, nil)
+		svc := newUserService(t, nil)
 		_, err := svc.UpdateUser(context.Background(), "u1", UpdateUserInput{})
 		if err == nil {
 			t.Fatalf("expected error for no fields to update")
@@ -520,8 +653,9 @@ func TestUserService_UpdateUser(t *testing.T) {
 				return expected
 			},
 		}
-		logger := testutil.NewTestLogger()
-		svc := NewUserService(logger, nil, repo, &fakeUserFollowerRepo{}, nil, nil)
+		svc := newUserService(t, func(d *userServiceDeps) {
+			d.userRepo = repo
+		})
 
 		displayName := "NewName"
 		_, err := svc.UpdateUser(context.Background(), "u1", UpdateUserInput{DisplayName: &displayName})
@@ -544,8 +678,9 @@ func TestUserService_UpdateUser(t *testing.T) {
 				return &model.User{ID: userID, DisplayID: "user1", DisplayName: "NewName"}, nil
 			},
 		}
-		logger := testutil.NewTestLogger()
-		svc := NewUserService(logger, nil, repo, &fakeUserFollowerRepo{}, nil, nil)
+		svc := newUserService(t, func(d *userServiceDeps) {
+			d.userRepo = repo
+		})
 
 		displayName := "NewName"
 		user, err := svc.UpdateUser(context.Background(), "u1", UpdateUserInput{DisplayName: &displayName})
@@ -578,8 +713,9 @@ func TestUserService_UpdateUser(t *testing.T) {
 				return &model.User{ID: userID, DisplayID: "user1", DisplayName: "TrimmedName"}, nil
 			},
 		}
-		logger := testutil.NewTestLogger()
-		svc := NewUserService(logger, nil, repo, &fakeUserFollowerRepo{}, nil, nil)
+		svc := newUserService(t, func(d *userServiceDeps) {
+			d.userRepo = repo
+		})
 
 		displayName := "  TrimmedName  "
 		_, err := svc.UpdateUser(context.Background(), "u1", UpdateUserInput{DisplayName: &displayName})
@@ -590,321 +726,968 @@ func TestUserService_UpdateUser(t *testing.T) {
 			t.Fatalf("expected display_name=TrimmedName, got %v", gotUpdates["display_name"])
 		}
 	})
-}
-
-func TestUserService_UpdateUserFromClerk(t *testing.T) {
-	t.Parallel()
-
-	t.Run("入力バリデーション: user_id が必須", func(t *testing.T) {
-		t.Parallel()
-		logger := testutil.NewTestLogger()
-		svc := NewUserService(logger, nil, &fakeUserRepo{}, &fakeUserFollowerRepo{}, nil, nil)
-		avatarURL := "https://example.com/avatar.png"
-		err := svc.UpdateUserFromClerk(context.Background(), "", &avatarURL)
-		if err == nil {
-			t.Fatalf("expected error")
-		}
-	})
 
-	t.Run("入力バリデーション: 空白のみのuser_idはエラー", func(t *testing.T) {
+	t.Run("入力バリデーション: bio が300文字超はエラー", func(t *testing.T) {
 		t.Parallel()
-		logger := testutil.NewTestLogger()
-		svc := NewUserService(logger, nil, &fakeUserRepo{}, &fakeUserFollowerRepo{}, nil, nil)
-		avatarURL := "https://example.com/avatar.png"
-		err := svc.UpdateUserFromClerk(context.Background(), "   ", &avatarURL)
+		svc := newUserService(t, nil)
+		bio := strings.Repeat("あ", 301)
+		_, err := svc.UpdateUser(context.Background(), "u1", UpdateUserInput{Bio: &bio})
 		if err == nil {
-			t.Fatalf("expected error")
-		}
-	})
-
-	t.Run("リポジトリの更新が失敗: エラーをそのまま返す", func(t *testing.T) {
-		t.Parallel()
-		expected := errors.New("db error")
-		repo := &fakeUserRepo{
-			UpdateFn: func(ctx context.Context, userID string, updates map[string]any) error {
-				return expected
-			},
-		}
-		logger := testutil.NewTestLogger()
-		svc := NewUserService(logger, nil, repo, &fakeUserFollowerRepo{}, nil, nil)
-
-		avatarURL := "https://example.com/avatar.png"
-		err := svc.UpdateUserFromClerk(context.Background(), "u1", &avatarURL)
-		if !errors.Is(err, expected) {
-			t.Fatalf("expected propagated error, got: %v", err)
+			t.Fatalf("expected error for bio too long")
 		}
 	})
 
-	t.Run("成功: avatar_url が更新される", func(t *testing.T) {
+	t.Run("成功: bio が空文字の場合は NULL でクリアされる", func(t *testing.T) {
 		t.Parallel()
-		var gotUserID string
 		var gotUpdates map[string]any
 		repo := &fakeUserRepo{
 			UpdateFn: func(ctx context.Context, userID string, updates map[string]any) error {
-				gotUserID = userID
 				gotUpdates = updates
 				return nil
 			},
-		}
-		logger := testutil.NewTestLogger()
-		svc := NewUserService(logger, nil, repo, &fakeUserFollowerRepo{}, nil, nil)
-
-		avatarURL := "https://example.com/new-avatar.png"
-		err := svc.UpdateUserFromClerk(context.Background(), "u1", &avatarURL)
-		if err != nil {
-			t.Fatalf("expected no error, got: %v", err)
-		}
-		if gotUserID != "u1" {
-			t.Fatalf("expected userID=u1, got %s", gotUserID)
-		}
-		if gotUpdates["avatar_url"] == nil {
-			t.Fatalf("expected avatar_url to be set")
-		}
-		if *(gotUpdates["avatar_url"].(*string)) != "https://example.com/new-avatar.png" {
-			t.Fatalf("expected avatar_url=https://example.com/new-avatar.png, got %v", gotUpdates["avatar_url"])
-		}
-		if _, ok := gotUpdates["updated_at"]; !ok {
-			t.Fatalf("expected updated_at to be set")
-		}
-	})
-
-	t.Run("成功: avatar_url が nil の場合も更新される", func(t *testing.T) {
-		t.Parallel()
-		var gotUpdates map[string]any
-		repo := &fakeUserRepo{
-			UpdateFn: func(ctx context.Context, userID string, updates map[string]any) error {
-				gotUpdates = updates
-				return nil
+			FindByIDFn: func(ctx context.Context, userID string) (*model.User, error) {
+				return &model.User{ID: userID, DisplayID: "user1"}, nil
 			},
 		}
-		logger := testutil.NewTestLogger()
-		svc := NewUserService(logger, nil, repo, &fakeUserFollowerRepo{}, nil, nil)
+		svc := newUserService(t, func(d *userServiceDeps) {
+			d.userRepo = repo
+		})
 
-		err := svc.UpdateUserFromClerk(context.Background(), "u1", nil)
-		if err != nil {
+		bio := "   "
+		if _, err := svc.UpdateUser(context.Background(), "u1", UpdateUserInput{Bio: &bio}); err != nil {
 			t.Fatalf("expected no error, got: %v", err)
 		}
-		// avatar_url は nil として更新されるべき
-		if _, ok := gotUpdates["avatar_url"]; !ok {
-			t.Fatalf("expected avatar_url key to be present")
-		}
-		// *string 型の nil ポインタがセットされることを確認
-		if gotUpdates["avatar_url"] != (*string)(nil) {
-			t.Fatalf("expected avatar_url=(*string)(nil), got %v", gotUpdates["avatar_url"])
+		v, ok := gotUpdates["bio"]
+		if !ok || v != nil {
+			t.Fatalf("expected bio=nil, got %v (ok=%v)", v, ok)
 		}
 	})
-}
 
-func TestUserService_FollowUser(t *testing.T) {
-	t.Parallel()
-
-	t.Run("入力バリデーション: follower_id が必須", func(t *testing.T) {
+	t.Run("入力バリデーション: avatar_url が http(s) 以外はエラー", func(t *testing.T) {
 		t.Parallel()
-		logger := testutil.NewTestLogger()
-		svc := NewUserService(logger, nil, &fakeUserRepo{}, &fakeUserFollowerRepo{}, nil, nil)
-		err := svc.FollowUser(context.Background(), "", "u2")
+		svc := newUserService(t, nil)
+		avatarURL := "javascript:alert(1)"
+		_, err := svc.UpdateUser(context.Background(), "u1", UpdateUserInput{AvatarURL: &avatarURL})
 		if err == nil {
-			t.Fatalf("expected error")
+			t.Fatalf("expected error for invalid avatar_url")
 		}
 	})
 
-	t.Run("入力バリデーション: followee_id が必須", func(t *testing.T) {
+	t.Run("入力バリデーション: display_id の形式が不正", func(t *testing.T) {
 		t.Parallel()
-		logger := testutil.NewTestLogger()
-		svc := NewUserService(logger, nil, &fakeUserRepo{}, &fakeUserFollowerRepo{}, nil, nil)
-		err := svc.FollowUser(context.Background(), "u1", "")
-		if err == nil {
-			t.Fatalf("expected error")
+		svc := newUserService(t, nil)
+		for _, id := range []string{"ab", "-alice", "alice_", "ali ce", "アリス", strings.Repeat("a", 31)} {
+			displayID := id
+			_, err := svc.UpdateUser(context.Background(), "u1", UpdateUserInput{DisplayID: &displayID})
+			if !errors.Is(err, ErrInvalidDisplayID) {
+				t.Fatalf("expected ErrInvalidDisplayID for %q, got: %v", id, err)
+			}
 		}
 	})
 
-	t.Run("自分自身をフォロー: ErrCannotFollowSelf", func(t *testing.T) {
+	t.Run("入力バリデーション: display_id が予約語", func(t *testing.T) {
 		t.Parallel()
-		logger := testutil.NewTestLogger()
-		svc := NewUserService(logger, nil, &fakeUserRepo{}, &fakeUserFollowerRepo{}, nil, nil)
-		err := svc.FollowUser(context.Background(), "u1", "u1")
-		if !errors.Is(err, ErrCannotFollowSelf) {
-			t.Fatalf("expected ErrCannotFollowSelf, got: %v", err)
+		svc := newUserService(t, nil)
+		displayID := "Settings"
+		_, err := svc.UpdateUser(context.Background(), "u1", UpdateUserInput{DisplayID: &displayID})
+		if !errors.Is(err, ErrReservedDisplayID) {
+			t.Fatalf("expected ErrReservedDisplayID, got: %v", err)
 		}
 	})
 
-	t.Run("フォロー対象が存在しない: ErrUserNotFound", func(t *testing.T) {
+	t.Run("display_id が他ユーザーに使用されている: ErrDisplayIDTaken", func(t *testing.T) {
 		t.Parallel()
 		repo := &fakeUserRepo{
 			FindByIDFn: func(ctx context.Context, userID string) (*model.User, error) {
-				return nil, gorm.ErrRecordNotFound
+				return &model.User{ID: userID, DisplayID: "user-abc123"}, nil
 			},
-		}
-		logger := testutil.NewTestLogger()
-		svc := NewUserService(logger, nil, repo, &fakeUserFollowerRepo{}, nil, nil)
-
-		err := svc.FollowUser(context.Background(), "u1", "u2")
-		if !errors.Is(err, ErrUserNotFound) {
-			t.Fatalf("expected ErrUserNotFound, got: %v", err)
-		}
-	})
-
-	t.Run("フォロー対象が削除済み: ErrUserNotFound", func(t *testing.T) {
-		t.Parallel()
-		now := time.Now()
-		repo := &fakeUserRepo{
-			FindByIDFn: func(ctx context.Context, userID string) (*model.User, error) {
-				return &model.User{ID: userID, DeletedAt: &now}, nil
+			FindByDisplayIDFn: func(ctx context.Context, displayID string) (*model.User, error) {
+				return &model.User{ID: "u2", DisplayID: displayID}, nil
 			},
 		}
-		logger := testutil.NewTestLogger()
-		svc := NewUserService(logger, nil, repo, &fakeUserFollowerRepo{}, nil, nil)
+		svc := newUserService(t, func(d *userServiceDeps) {
+			d.userRepo = repo
+			d.displayIDHistoryRepo = &fakeUserDisplayIDHistoryRepo{}
+		})
 
-		err := svc.FollowUser(context.Background(), "u1", "u2")
-		if !errors.Is(err, ErrUserNotFound) {
-			t.Fatalf("expected ErrUserNotFound, got: %v", err)
+		displayID := "alice"
+		_, err := svc.UpdateUser(context.Background(), "u1", UpdateUserInput{DisplayID: &displayID})
+		if !errors.Is(err, ErrDisplayIDTaken) {
+			t.Fatalf("expected ErrDisplayIDTaken, got: %v", err)
 		}
 	})
 
-	t.Run("既にフォロー済み: ErrAlreadyFollowing", func(t *testing.T) {
+	t.Run("display_id が他ユーザーの変更履歴に存在する: ErrDisplayIDTaken", func(t *testing.T) {
 		t.Parallel()
 		repo := &fakeUserRepo{
 			FindByIDFn: func(ctx context.Context, userID string) (*model.User, error) {
-				return &model.User{ID: userID}, nil
+				return &model.User{ID: userID, DisplayID: "user-abc123"}, nil
 			},
 		}
-		followerRepo := &fakeUserFollowerRepo{
-			IsFollowingFn: func(ctx context.Context, followerID, followeeID string) (bool, error) {
-				return true, nil
+		historyRepo := &fakeUserDisplayIDHistoryRepo{
+			FindByDisplayIDFn: func(ctx context.Context, displayID string) (*model.UserDisplayIDHistory, error) {
+				return &model.UserDisplayIDHistory{UserID: "u2", DisplayID: displayID}, nil
 			},
 		}
-		logger := testutil.NewTestLogger()
-		svc := NewUserService(logger, nil, repo, followerRepo, nil, nil)
+		svc := newUserService(t, func(d *userServiceDeps) {
+			d.userRepo = repo
+			d.displayIDHistoryRepo = historyRepo
+		})
 
-		err := svc.FollowUser(context.Background(), "u1", "u2")
-		if !errors.Is(err, ErrAlreadyFollowing) {
-			t.Fatalf("expected ErrAlreadyFollowing, got: %v", err)
+		displayID := "alice"
+		_, err := svc.UpdateUser(context.Background(), "u1", UpdateUserInput{DisplayID: &displayID})
+		if !errors.Is(err, ErrDisplayIDTaken) {
+			t.Fatalf("expected ErrDisplayIDTaken, got: %v", err)
 		}
 	})
 
-	t.Run("成功: フォローが作成される", func(t *testing.T) {
+	t.Run("成功: display_id が現在と同じ場合は更新せずに返す", func(t *testing.T) {
 		t.Parallel()
-		var gotFollowerID, gotFolloweeID string
+		updateCalled := false
 		repo := &fakeUserRepo{
 			FindByIDFn: func(ctx context.Context, userID string) (*model.User, error) {
-				return &model.User{ID: userID}, nil
-			},
-		}
-		followerRepo := &fakeUserFollowerRepo{
-			IsFollowingFn: func(ctx context.Context, followerID, followeeID string) (bool, error) {
-				return false, nil
+				return &model.User{ID: userID, DisplayID: "alice"}, nil
 			},
-			CreateFn: func(ctx context.Context, followerID, followeeID string) error {
-				gotFollowerID = followerID
-				gotFolloweeID = followeeID
+			UpdateFn: func(ctx context.Context, userID string, updates map[string]any) error {
+				updateCalled = true
 				return nil
 			},
 		}
-		logger := testutil.NewTestLogger()
-		svc := NewUserService(logger, nil, repo, followerRepo, nil, nil)
+		svc := newUserService(t, func(d *userServiceDeps) {
+			d.userRepo = repo
+			d.displayIDHistoryRepo = &fakeUserDisplayIDHistoryRepo{}
+		})
 
-		err := svc.FollowUser(context.Background(), "u1", "u2")
+		displayID := "  Alice "
+		user, err := svc.UpdateUser(context.Background(), "u1", UpdateUserInput{DisplayID: &displayID})
 		if err != nil {
 			t.Fatalf("expected no error, got: %v", err)
 		}
-		if gotFollowerID != "u1" || gotFolloweeID != "u2" {
-			t.Fatalf("unexpected args: followerID=%s followeeID=%s", gotFollowerID, gotFolloweeID)
+		if updateCalled {
+			t.Fatalf("expected Update not to be called")
+		}
+		if user == nil || user.DisplayID != "alice" {
+			t.Fatalf("unexpected user: %+v", user)
 		}
 	})
 }
 
-func TestUserService_UnfollowUser(t *testing.T) {
+func TestUserService_ResolveRenamedDisplayID(t *testing.T) {
 	t.Parallel()
 
-	t.Run("入力バリデーション: follower_id が必須", func(t *testing.T) {
+	t.Run("履歴が存在しない: ErrUserNotFound", func(t *testing.T) {
 		t.Parallel()
-		logger := testutil.NewTestLogger()
-		svc := NewUserService(logger, nil, &fakeUserRepo{}, &fakeUserFollowerRepo{}, nil, nil)
-		err := svc.UnfollowUser(context.Background(), "", "u2")
-		if err == nil {
-			t.Fatalf("expected error")
+		svc := newUserService(t, func(d *userServiceDeps) {
+			d.displayIDHistoryRepo = &fakeUserDisplayIDHistoryRepo{}
+		})
+		_, err := svc.ResolveRenamedDisplayID(context.Background(), "old-id")
+		if !errors.Is(err, ErrUserNotFound) {
+			t.Fatalf("expected ErrUserNotFound, got: %v", err)
 		}
 	})
 
-	t.Run("入力バリデーション: followee_id が必須", func(t *testing.T) {
+	t.Run("ユーザーが削除済み: ErrUserNotFound", func(t *testing.T) {
 		t.Parallel()
-		logger := testutil.NewTestLogger()
-		svc := NewUserService(logger, nil, &fakeUserRepo{}, &fakeUserFollowerRepo{}, nil, nil)
-		err := svc.UnfollowUser(context.Background(), "u1", "")
-		if err == nil {
-			t.Fatalf("expected error")
+		deletedAt := time.Now()
+		repo := &fakeUserRepo{
+			FindByIDFn: func(ctx context.Context, userID string) (*model.User, error) {
+				return &model.User{ID: userID, DisplayID: "alice", DeletedAt: &deletedAt}, nil
+			},
 		}
-	})
-
-	t.Run("フォローしていない: ErrNotFollowing", func(t *testing.T) {
-		t.Parallel()
-		followerRepo := &fakeUserFollowerRepo{
-			IsFollowingFn: func(ctx context.Context, followerID, followeeID string) (bool, error) {
-				return false, nil
+		historyRepo := &fakeUserDisplayIDHistoryRepo{
+			FindByDisplayIDFn: func(ctx context.Context, displayID string) (*model.UserDisplayIDHistory, error) {
+				return &model.UserDisplayIDHistory{UserID: "u1", DisplayID: displayID}, nil
 			},
 		}
-		logger := testutil.NewTestLogger()
-		svc := NewUserService(logger, nil, &fakeUserRepo{}, followerRepo, nil, nil)
-
-		err := svc.UnfollowUser(context.Background(), "u1", "u2")
-		if !errors.Is(err, ErrNotFollowing) {
-			t.Fatalf("expected ErrNotFollowing, got: %v", err)
+		svc := newUserService(t, func(d *userServiceDeps) {
+			d.userRepo = repo
+			d.displayIDHistoryRepo = historyRepo
+		})
+		_, err := svc.ResolveRenamedDisplayID(context.Background(), "old-id")
+		if !errors.Is(err, ErrUserNotFound) {
+			t.Fatalf("expected ErrUserNotFound, got: %v", err)
 		}
 	})
 
-	t.Run("成功: フォローが削除される", func(t *testing.T) {
+	t.Run("成功: 現在の display_id を返す", func(t *testing.T) {
 		t.Parallel()
-		var gotFollowerID, gotFolloweeID string
-		followerRepo := &fakeUserFollowerRepo{
-			IsFollowingFn: func(ctx context.Context, followerID, followeeID string) (bool, error) {
-				return true, nil
+		repo := &fakeUserRepo{
+			FindByIDFn: func(ctx context.Context, userID string) (*model.User, error) {
+				return &model.User{ID: userID, DisplayID: "alice"}, nil
 			},
-			DeleteFn: func(ctx context.Context, followerID, followeeID string) error {
-				gotFollowerID = followerID
-				gotFolloweeID = followeeID
-				return nil
+		}
+		historyRepo := &fakeUserDisplayIDHistoryRepo{
+			FindByDisplayIDFn: func(ctx context.Context, displayID string) (*model.UserDisplayIDHistory, error) {
+				return &model.UserDisplayIDHistory{UserID: "u1", DisplayID: displayID}, nil
 			},
 		}
-		logger := testutil.NewTestLogger()
-		svc := NewUserService(logger, nil, &fakeUserRepo{}, followerRepo, nil, nil)
-
-		err := svc.UnfollowUser(context.Background(), "u1", "u2")
+		svc := newUserService(t, func(d *userServiceDeps) {
+			d.userRepo = repo
+			d.displayIDHistoryRepo = historyRepo
+		})
+		got, err := svc.ResolveRenamedDisplayID(context.Background(), "user-abc123")
 		if err != nil {
 			t.Fatalf("expected no error, got: %v", err)
 		}
-		if gotFollowerID != "u1" || gotFolloweeID != "u2" {
-			t.Fatalf("unexpected args: followerID=%s followeeID=%s", gotFollowerID, gotFolloweeID)
+		if got != "alice" {
+			t.Fatalf("expected alice, got %s", got)
 		}
 	})
 }
 
-func TestUserService_IsFollowing(t *testing.T) {
+func TestUserService_SyncUserFromClerk(t *testing.T) {
 	t.Parallel()
 
-	t.Run("空のIDの場合: false を返す", func(t *testing.T) {
+	avatarURL := "https://example.com/new-avatar.png"
+	clerkInfo := ClerkUserInfo{
+		ID:        "user_123",
+		Email:     "new@example.com",
+		FirstName: "John",
+		LastName:  "Smith",
+		AvatarURL: &avatarURL,
+	}
+
+	t.Run("入力バリデーション: clerk user id が必須", func(t *testing.T) {
 		t.Parallel()
-		logger := testutil.NewTestLogger()
-		svc := NewUserService(logger, nil, &fakeUserRepo{}, &fakeUserFollowerRepo{}, nil, nil)
-		result, err := svc.IsFollowing(context.Background(), "", "u2")
-		if err != nil {
-			t.Fatalf("expected no error, got: %v", err)
-		}
-		if result {
-			t.Fatalf("expected false")
+		svc := newUserService(t, nil)
+		if _, err := svc.SyncUserFromClerk(context.Background(), ClerkUserInfo{Email: "a@example.com"}, ClerkSyncOptions{}); err == nil {
+			t.Fatalf("expected error")
 		}
 	})
 
-	t.Run("フォローしている: true を返す", func(t *testing.T) {
+	t.Run("ユーザーが存在しない: ユーザー名を display_id にして作成する", func(t *testing.T) {
 		t.Parallel()
-		followerRepo := &fakeUserFollowerRepo{
-			IsFollowingFn: func(ctx context.Context, followerID, followeeID string) (bool, error) {
-				return true, nil
+		var created *model.User
+		repo := &fakeUserRepo{
+			CreateFn: func(ctx context.Context, user *model.User) error {
+				created = user
+				return nil
 			},
 		}
-		logger := testutil.NewTestLogger()
-		svc := NewUserService(logger, nil, &fakeUserRepo{}, followerRepo, nil, nil)
+		svc := newUserService(t, func(d *userServiceDeps) {
+			d.userRepo = repo
+		})
 
-		result, err := svc.IsFollowing(context.Background(), "u1", "u2")
+		info := clerkInfo
+		info.Username = "John_Smith"
+		if _, err := svc.SyncUserFromClerk(context.Background(), info, ClerkSyncOptions{Created: true}); err != nil {
+			t.Fatalf("expected no error, got: %v", err)
+		}
+		if created == nil {
+			t.Fatalf("expected user to be created")
+		}
+		if created.DisplayID != "john_smith" || created.DisplayName != "John Smith" || created.Email != "new@example.com" {
+			t.Fatalf("unexpected user: %+v", created)
+		}
+		if created.ClerkDisplayName == nil || *created.ClerkDisplayName != "John Smith" {
+			t.Fatalf("expected clerk_display_name to be recorded, got %v", created.ClerkDisplayName)
+		}
+		if created.ClerkUsername == nil || *created.ClerkUsername != "John_Smith" {
+			t.Fatalf("expected clerk_username to be recorded, got %v", created.ClerkUsername)
+		}
+	})
+
+	t.Run("ユーザー名が変更履歴で予約済み: display_id をランダム生成して作成する", func(t *testing.T) {
+		t.Parallel()
+		var created *model.User
+		repo := &fakeUserRepo{
+			CreateFn: func(ctx context.Context, user *model.User) error {
+				created = user
+				return nil
+			},
+		}
+		historyRepo := &fakeUserDisplayIDHistoryRepo{
+			FindByDisplayIDFn: func(ctx context.Context, displayID string) (*model.UserDisplayIDHistory, error) {
+				if displayID == "john_smith" {
+					return &model.UserDisplayIDHistory{UserID: "u2", DisplayID: displayID}, nil
+				}
+				return nil, gorm.ErrRecordNotFound
+			},
+		}
+		svc := newUserService(t, func(d *userServiceDeps) {
+			d.userRepo = repo
+			d.displayIDHistoryRepo = historyRepo
+		})
+
+		info := clerkInfo
+		info.Username = "John_Smith"
+		if _, err := svc.SyncUserFromClerk(context.Background(), info, ClerkSyncOptions{Created: true}); err != nil {
+			t.Fatalf("expected no error, got: %v", err)
+		}
+		if created == nil || !strings.HasPrefix(created.DisplayID, "user-") {
+			t.Fatalf("expected generated display_id, got %+v", created)
+		}
+	})
+
+	t.Run("並行して同じ Clerk ユーザーが作成された: 作成済みのユーザーを返す", func(t *testing.T) {
+		t.Parallel()
+		calls := 0
+		repo := &fakeUserRepo{
+			FindByClerkUserIDFn: func(ctx context.Context, clerkUserID string) (*model.User, error) {
+				calls++
+				if calls == 1 {
+					return nil, gorm.ErrRecordNotFound
+				}
+				return &model.User{ID: "u1", ClerkUserID: clerkUserID}, nil
+			},
+			CreateFn: func(ctx context.Context, user *model.User) error {
+				return &pgconn.PgError{Code: "23505"}
+			},
+		}
+		svc := newUserService(t, func(d *userServiceDeps) {
+			d.userRepo = repo
+		})
+
+		got, err := svc.SyncUserFromClerk(context.Background(), clerkInfo, ClerkSyncOptions{Created: true})
+		if err != nil {
+			t.Fatalf("expected no error, got: %v", err)
+		}
+		if got.ID != "u1" {
+			t.Fatalf("expected existing user, got %+v", got)
+		}
+	})
+
+	t.Run("user.created 以外でユーザーが存在しない: 作成せずに ErrUserNotFound", func(t *testing.T) {
+		t.Parallel()
+		repo := &fakeUserRepo{
+			CreateFn: func(ctx context.Context, user *model.User) error {
+				t.Fatalf("user must not be created")
+				return nil
+			},
+		}
+		svc := newUserService(t, func(d *userServiceDeps) {
+			d.userRepo = repo
+		})
+
+		if _, err := svc.SyncUserFromClerk(context.Background(), clerkInfo, ClerkSyncOptions{}); !errors.Is(err, ErrUserNotFound) {
+			t.Fatalf("expected ErrUserNotFound, got: %v", err)
+		}
+	})
+
+	t.Run("完全削除済みのユーザー: 同期せずに ErrUserNotFound", func(t *testing.T) {
+		t.Parallel()
+		repo := &fakeUserRepo{
+			FindByClerkUserIDFn: func(ctx context.Context, clerkUserID string) (*model.User, error) {
+				return &model.User{ID: "u1", DeletionStatus: model.UserDeletionStatusPurged}, nil
+			},
+			UpdateFn: func(ctx context.Context, userID string, updates map[string]any) error {
+				t.Fatalf("purged user must not be updated")
+				return nil
+			},
+		}
+		svc := newUserService(t, func(d *userServiceDeps) {
+			d.userRepo = repo
+		})
+
+		if _, err := svc.SyncUserFromClerk(context.Background(), clerkInfo, ClerkSyncOptions{Created: true}); !errors.Is(err, ErrUserNotFound) {
+			t.Fatalf("expected ErrUserNotFound, got: %v", err)
+		}
+	})
+
+	t.Run("アプリ上で変更していない表示名は Clerk の名前に追従する", func(t *testing.T) {
+		t.Parallel()
+		lastSynced := "John Doe"
+		var gotUpdates map[string]any
+		repo := &fakeUserRepo{
+			FindByClerkUserIDFn: func(ctx context.Context, clerkUserID string) (*model.User, error) {
+				return &model.User{ID: "u1", DisplayID: "user-abc123", DisplayName: "John Doe", ClerkDisplayName: &lastSynced}, nil
+			},
+			UpdateFn: func(ctx context.Context, userID string, updates map[string]any) error {
+				gotUpdates = updates
+				return nil
+			},
+			FindByIDFn: func(ctx context.Context, userID string) (*model.User, error) {
+				return &model.User{ID: userID}, nil
+			},
+		}
+		svc := newUserService(t, func(d *userServiceDeps) {
+			d.userRepo = repo
+		})
+
+		if _, err := svc.SyncUserFromClerk(context.Background(), clerkInfo, ClerkSyncOptions{}); err != nil {
+			t.Fatalf("expected no error, got: %v", err)
+		}
+		if gotUpdates["display_name"] != "John Smith" {
+			t.Fatalf("expected display_name=John Smith, got %v", gotUpdates["display_name"])
+		}
+		if gotUpdates["email"] != "new@example.com" {
+			t.Fatalf("expected email=new@example.com, got %v", gotUpdates["email"])
+		}
+		if got, _ := gotUpdates["avatar_url"].(*string); got == nil || *got != avatarURL {
+			t.Fatalf("expected avatar_url=%s, got %v", avatarURL, gotUpdates["avatar_url"])
+		}
+		if _, ok := gotUpdates["display_id"]; ok {
+			t.Fatalf("display_id must not be updated without username")
+		}
+	})
+
+	t.Run("アプリ上で変更した表示名は上書きしない", func(t *testing.T) {
+		t.Parallel()
+		lastSynced := "John Doe"
+		var gotUpdates map[string]any
+		repo := &fakeUserRepo{
+			FindByClerkUserIDFn: func(ctx context.Context, clerkUserID string) (*model.User, error) {
+				return &model.User{ID: "u1", DisplayID: "user-abc123", DisplayName: "ジョン", ClerkDisplayName: &lastSynced}, nil
+			},
+			UpdateFn: func(ctx context.Context, userID string, updates map[string]any) error {
+				gotUpdates = updates
+				return nil
+			},
+			FindByIDFn: func(ctx context.Context, userID string) (*model.User, error) {
+				return &model.User{ID: userID}, nil
+			},
+		}
+		svc := newUserService(t, func(d *userServiceDeps) {
+			d.userRepo = repo
+		})
+
+		if _, err := svc.SyncUserFromClerk(context.Background(), clerkInfo, ClerkSyncOptions{}); err != nil {
+			t.Fatalf("expected no error, got: %v", err)
+		}
+		if _, ok := gotUpdates["display_name"]; ok {
+			t.Fatalf("display_name must not be overwritten, got %v", gotUpdates["display_name"])
+		}
+		if gotUpdates["clerk_display_name"] != "John Smith" {
+			t.Fatalf("expected clerk_display_name=John Smith, got %v", gotUpdates["clerk_display_name"])
+		}
+	})
+
+	t.Run("ユーザー名が使用済みの場合は display_id を変更しない", func(t *testing.T) {
+		t.Parallel()
+		var gotUpdates map[string]any
+		repo := &fakeUserRepo{
+			FindByClerkUserIDFn: func(ctx context.Context, clerkUserID string) (*model.User, error) {
+				return &model.User{ID: "u1", DisplayID: "user-abc123", DisplayName: "John Smith"}, nil
+			},
+			FindByDisplayIDFn: func(ctx context.Context, displayID string) (*model.User, error) {
+				return &model.User{ID: "u2", DisplayID: displayID}, nil
+			},
+			UpdateFn: func(ctx context.Context, userID string, updates map[string]any) error {
+				gotUpdates = updates
+				return nil
+			},
+			FindByIDFn: func(ctx context.Context, userID string) (*model.User, error) {
+				return &model.User{ID: userID}, nil
+			},
+		}
+		svc := newUserService(t, func(d *userServiceDeps) {
+			d.userRepo = repo
+		})
+
+		info := clerkInfo
+		info.Username = "taken"
+		if _, err := svc.SyncUserFromClerk(context.Background(), info, ClerkSyncOptions{}); err != nil {
+			t.Fatalf("expected no error, got: %v", err)
+		}
+		if _, ok := gotUpdates["display_id"]; ok {
+			t.Fatalf("display_id must not be updated, got %v", gotUpdates["display_id"])
+		}
+		if got, _ := gotUpdates["clerk_username"].(*string); got == nil || *got != "taken" {
+			t.Fatalf("expected clerk_username=taken, got %v", gotUpdates["clerk_username"])
+		}
+	})
+
+	t.Run("リポジトリの更新が失敗: エラーをそのまま返す", func(t *testing.T) {
+		t.Parallel()
+		expected := errors.New("db error")
+		repo := &fakeUserRepo{
+			FindByClerkUserIDFn: func(ctx context.Context, clerkUserID string) (*model.User, error) {
+				return &model.User{ID: "u1", DisplayID: "user-abc123", DisplayName: "John Smith"}, nil
+			},
+			UpdateFn: func(ctx context.Context, userID string, updates map[string]any) error {
+				return expected
+			},
+		}
+		svc := newUserService(t, func(d *userServiceDeps) {
+			d.userRepo = repo
+		})
+
+		if _, err := svc.SyncUserFromClerk(context.Background(), clerkInfo, ClerkSyncOptions{}); !errors.Is(err, expected) {
+			t.Fatalf("expected propagated error, got: %v", err)
+		}
+	})
+}
+
+func TestUserService_FollowUser(t *testing.T) {
+	t.Parallel()
+
+	t.Run("入力バリデーション: follower_id が必須", func(t *testing.T) {
+		t.Parallel()
+		svc := newUserService(t, nil)
+		_, err := svc.FollowUser(context.Background(), "", "u2")
+		if err == nil {
+			t.Fatalf("expected error")
+		}
+	})
+
+	t.Run("入力バリデーション: followee_id が必須", func(t *testing.T) {
+		t.Parallel()
+		svc := newUserService(t, nil)
+		_, err := svc.FollowUser(context.Background(), "u1", "")
+		if err == nil {
+			t.Fatalf("expected error")
+		}
+	})
+
+	t.Run("自分自身をフォロー: ErrCannotFollowSelf", func(t *testing.T) {
+		t.Parallel()
+		svc := newUserService(t, nil)
+		_, err := svc.FollowUser(context.Background(), "u1", "u1")
+		if !errors.Is(err, ErrCannotFollowSelf) {
+			t.Fatalf("expected ErrCannotFollowSelf, got: %v", err)
+		}
+	})
+
+	t.Run("フォロー対象が存在しない: ErrUserNotFound", func(t *testing.T) {
+		t.Parallel()
+		repo := &fakeUserRepo{
+			FindByIDFn: func(ctx context.Context, userID string) (*model.User, error) {
+				return nil, gorm.ErrRecordNotFound
+			},
+		}
+		svc := newUserService(t, func(d *userServiceDeps) {
+			d.userRepo = repo
+		})
+
+		_, err := svc.FollowUser(context.Background(), "u1", "u2")
+		if !errors.Is(err, ErrUserNotFound) {
+			t.Fatalf("expected ErrUserNotFound, got: %v", err)
+		}
+	})
+
+	t.Run("ブロック関係にある: ErrUserBlocked", func(t *testing.T) {
+		t.Parallel()
+		repo := &fakeUserRepo{
+			FindByIDFn: func(ctx context.Context, userID string) (*model.User, error) {
+				return &model.User{ID: userID}, nil
+			},
+		}
+		createCalled := false
+		followerRepo := &fakeUserFollowerRepo{
+			CreateFn: func(ctx context.Context, followerID, followeeID string) error {
+				createCalled = true
+				return nil
+			},
+		}
+		blockRepo := &testutil.FakeUserBlockRepository{
+			IsBlockedEitherFn: func(ctx context.Context, userA, userB string) (bool, error) {
+				return true, nil
+			},
+		}
+		svc := newUserService(t, func(d *userServiceDeps) {
+			d.userRepo = repo
+			d.userFollowerRepo = followerRepo
+			d.userBlockRepo = blockRepo
+		})
+
+		_, err := svc.FollowUser(context.Background(), "u1", "u2")
+		if !errors.Is(err, ErrUserBlocked) {
+			t.Fatalf("expected ErrUserBlocked, got: %v", err)
+		}
+		if createCalled {
+			t.Fatalf("expected Create not to be called")
+		}
+	})
+
+	t.Run("フォロー対象が削除済み: ErrUserNotFound", func(t *testing.T) {
+		t.Parallel()
+		now := time.Now()
+		repo := &fakeUserRepo{
+			FindByIDFn: func(ctx context.Context, userID string) (*model.User, error) {
+				return &model.User{ID: userID, DeletedAt: &now}, nil
+			},
+		}
+		svc := newUserService(t, func(d *userServiceDeps) {
+			d.userRepo = repo
+		})
+
+		_, err := svc.FollowUser(context.Background(), "u1", "u2")
+		if !errors.Is(err, ErrUserNotFound) {
+			t.Fatalf("expected ErrUserNotFound, got: %v", err)
+		}
+	})
+
+	t.Run("既にフォロー済み: ErrAlreadyFollowing", func(t *testing.T) {
+		t.Parallel()
+		repo := &fakeUserRepo{
+			FindByIDFn: func(ctx context.Context, userID string) (*model.User, error) {
+				return &model.User{ID: userID}, nil
+			},
+		}
+		followerRepo := &fakeUserFollowerRepo{
+			IsFollowingFn: func(ctx context.Context, followerID, followeeID string) (bool, error) {
+				return true, nil
+			},
+		}
+		svc := newUserService(t, func(d *userServiceDeps) {
+			d.userRepo = repo
+			d.userFollowerRepo = followerRepo
+		})
+
+		_, err := svc.FollowUser(context.Background(), "u1", "u2")
+		if !errors.Is(err, ErrAlreadyFollowing) {
+			t.Fatalf("expected ErrAlreadyFollowing, got: %v", err)
+		}
+	})
+
+	t.Run("成功: フォローが作成される", func(t *testing.T) {
+		t.Parallel()
+		var gotFollowerID, gotFolloweeID string
+		repo := &fakeUserRepo{
+			FindByIDFn: func(ctx context.Context, userID string) (*model.User, error) {
+				return &model.User{ID: userID}, nil
+			},
+		}
+		followerRepo := &fakeUserFollowerRepo{
+			IsFollowingFn: func(ctx context.Context, followerID, followeeID string) (bool, error) {
+				return false, nil
+			},
+			CreateFn: func(ctx context.Context, followerID, followeeID string) error {
+				gotFollowerID = followerID
+				gotFolloweeID = followeeID
+				return nil
+			},
+		}
+		svc := newUserService(t, func(d *userServiceDeps) {
+			d.userRepo = repo
+			d.userFollowerRepo = followerRepo
+		})
+
+		status, err := svc.FollowUser(context.Background(), "u1", "u2")
+		if err != nil {
+			t.Fatalf("expected no error, got: %v", err)
+		}
+		if status != FollowStatusFollowing {
+			t.Fatalf("expected status following, got: %s", status)
+		}
+		if gotFollowerID != "u1" || gotFolloweeID != "u2" {
+			t.Fatalf("unexpected args: followerID=%s followeeID=%s", gotFollowerID, gotFolloweeID)
+		}
+	})
+
+	t.Run("非公開アカウント: フォローリクエストが作成される", func(t *testing.T) {
+		t.Parallel()
+		repo := &fakeUserRepo{
+			FindByIDFn: func(ctx context.Context, userID string) (*model.User, error) {
+				return &model.User{ID: userID, IsPrivate: true}, nil
+			},
+		}
+		followCreated := false
+		followerRepo := &fakeUserFollowerRepo{
+			CreateFn: func(ctx context.Context, followerID, followeeID string) error {
+				followCreated = true
+				return nil
+			},
+		}
+		var gotRequesterID, gotTargetID string
+		requestRepo := &fakeUserFollowRequestRepo{
+			CreateFn: func(ctx context.Context, requesterID, targetID string) error {
+				gotRequesterID = requesterID
+				gotTargetID = targetID
+				return nil
+			},
+		}
+		svc := newUserService(t, func(d *userServiceDeps) {
+			d.userRepo = repo
+			d.userFollowerRepo = followerRepo
+			d.followRequestRepo = requestRepo
+		})
+
+		status, err := svc.FollowUser(context.Background(), "u1", "u2")
+		if err != nil {
+			t.Fatalf("expected no error, got: %v", err)
+		}
+		if status != FollowStatusRequested {
+			t.Fatalf("expected status requested, got: %s", status)
+		}
+		if followCreated {
+			t.Fatalf("expected follow not to be created")
+		}
+		if gotRequesterID != "u1" || gotTargetID != "u2" {
+			t.Fatalf("unexpected args: requesterID=%s targetID=%s", gotRequesterID, gotTargetID)
+		}
+	})
+
+	t.Run("非公開アカウントへリクエスト済み: ErrFollowRequestAlreadySent", func(t *testing.T) {
+		t.Parallel()
+		repo := &fakeUserRepo{
+			FindByIDFn: func(ctx context.Context, userID string) (*model.User, error) {
+				return &model.User{ID: userID, IsPrivate: true}, nil
+			},
+		}
+		requestRepo := &fakeUserFollowRequestRepo{
+			ExistsFn: func(ctx context.Context, requesterID, targetID string) (bool, error) {
+				return true, nil
+			},
+		}
+		svc := newUserService(t, func(d *userServiceDeps) {
+			d.userRepo = repo
+			d.followRequestRepo = requestRepo
+		})
+
+		_, err := svc.FollowUser(context.Background(), "u1", "u2")
+		if !errors.Is(err, ErrFollowRequestAlreadySent) {
+			t.Fatalf("expected ErrFollowRequestAlreadySent, got: %v", err)
+		}
+	})
+
+	t.Run("通知イベント: フォローされたユーザー宛てのイベントをアウトボックスに書き込む", func(t *testing.T) {
+		t.Parallel()
+		repo := &fakeUserRepo{
+			FindByIDFn: func(ctx context.Context, userID string) (*model.User, error) {
+				return &model.User{ID: userID}, nil
+			},
+		}
+		var enqueued []*model.OutboxEvent
+		outboxRepo := &testutil.FakeOutboxRepository{
+			EnqueueFn: func(ctx context.Context, events ...*model.OutboxEvent) error {
+				enqueued = append(enqueued, events...)
+				return nil
+			},
+		}
+		svc := newUserService(t, func(d *userServiceDeps) {
+			d.userRepo = repo
+			d.outboxRepo = outboxRepo
+		})
+
+		if _, err := svc.FollowUser(context.Background(), "u1", "u2"); err != nil {
+			t.Fatalf("expected no error, got: %v", err)
+		}
+		if len(enqueued) != 1 || enqueued[0].EventType != model.OutboxEventTypeUserFollowed {
+			t.Fatalf("unexpected outbox events: %+v", enqueued)
+		}
+		var payload model.NotificationEventPayload
+		testutil.MustUnmarshalJSON(t, enqueued[0].Payload, &payload)
+		if payload.RecipientUserID != "u2" || payload.ActorUserID != "u1" {
+			t.Fatalf("unexpected payload: %+v", payload)
+		}
+	})
+}
+
+func TestUserService_UnfollowUser(t *testing.T) {
+	t.Parallel()
+
+	t.Run("入力バリデーション: follower_id が必須", func(t *testing.T) {
+		t.Parallel()
+		svc := newUserService(t, nil)
+		err := svc.UnfollowUser(context.Background(), "", "u2")
+		if err == nil {
+			t.Fatalf("expected error")
+		}
+	})
+
+	t.Run("入力バリデーション: followee_id が必須", func(t *testing.T) {
+		t.Parallel()
+		svc := newUserService(t, nil)
+		err := svc.UnfollowUser(context.Background(), "u1", "")
+		if err == nil {
+			t.Fatalf("expected error")
+		}
+	})
+
+	t.Run("フォローしていない: ErrNotFollowing", func(t *testing.T) {
+		t.Parallel()
+		followerRepo := &fakeUserFollowerRepo{
+			IsFollowingFn: func(ctx context.Context, followerID, followeeID string) (bool, error) {
+				return false, nil
+			},
+		}
+		svc := newUserService(t, func(d *userServiceDeps) {
+			d.userFollowerRepo = followerRepo
+		})
+
+		err := svc.UnfollowUser(context.Background(), "u1", "u2")
+		if !errors.Is(err, ErrNotFollowing) {
+			t.Fatalf("expected ErrNotFollowing, got: %v", err)
+		}
+	})
+
+	t.Run("承認待ちのフォローリクエスト: リクエストが取り消される", func(t *testing.T) {
+		t.Parallel()
+		followerRepo := &fakeUserFollowerRepo{
+			IsFollowingFn: func(ctx context.Context, followerID, followeeID string) (bool, error) {
+				return false, nil
+			},
+		}
+		var gotRequesterID, gotTargetID string
+		requestRepo := &fakeUserFollowRequestRepo{
+			ExistsFn: func(ctx context.Context, requesterID, targetID string) (bool, error) {
+				return true, nil
+			},
+			DeleteFn: func(ctx context.Context, requesterID, targetID string) error {
+				gotRequesterID = requesterID
+				gotTargetID = targetID
+				return nil
+			},
+		}
+		svc := newUserService(t, func(d *userServiceDeps) {
+			d.userFollowerRepo = followerRepo
+			d.followRequestRepo = requestRepo
+		})
+
+		err := svc.UnfollowUser(context.Background(), "u1", "u2")
+		if err != nil {
+			t.Fatalf("expected no error, got: %v", err)
+		}
+		if gotRequesterID != "u1" || gotTargetID != "u2" {
+			t.Fatalf("unexpected args: requesterID=%s targetID=%s", gotRequesterID, gotTargetID)
+		}
+	})
+
+	t.Run("成功: フォローが削除される", func(t *testing.T) {
+		t.Parallel()
+		var gotFollowerID, gotFolloweeID string
+		followerRepo := &fakeUserFollowerRepo{
+			IsFollowingFn: func(ctx context.Context, followerID, followeeID string) (bool, error) {
+				return true, nil
+			},
+			DeleteFn: func(ctx context.Context, followerID, followeeID string) error {
+				gotFollowerID = followerID
+				gotFolloweeID = followeeID
+				return nil
+			},
+		}
+		svc := newUserService(t, func(d *userServiceDeps) {
+			d.userFollowerRepo = followerRepo
+		})
+
+		err := svc.UnfollowUser(context.Background(), "u1", "u2")
+		if err != nil {
+			t.Fatalf("expected no error, got: %v", err)
+		}
+		if gotFollowerID != "u1" || gotFolloweeID != "u2" {
+			t.Fatalf("unexpected args: followerID=%s followeeID=%s", gotFollowerID, gotFolloweeID)
+		}
+	})
+}
+
+func TestUserService_ApproveFollowRequest(t *testing.T) {
+	t.Parallel()
+
+	t.Run("リクエストが存在しない: ErrFollowRequestNotFound", func(t *testing.T) {
+		t.Parallel()
+		svc := newUserService(t, func(d *userServiceDeps) {
+			d.followRequestRepo = &fakeUserFollowRequestRepo{}
+		})
+
+		err := svc.ApproveFollowRequest(context.Background(), "u2", "u1")
+		if !errors.Is(err, ErrFollowRequestNotFound) {
+			t.Fatalf("expected ErrFollowRequestNotFound, got: %v", err)
+		}
+	})
+}
+
+func TestUserService_DenyFollowRequest(t *testing.T) {
+	t.Parallel()
+
+	t.Run("リクエストが存在しない: ErrFollowRequestNotFound", func(t *testing.T) {
+		t.Parallel()
+		svc := newUserService(t, func(d *userServiceDeps) {
+			d.followRequestRepo = &fakeUserFollowRequestRepo{}
+		})
+
+		err := svc.DenyFollowRequest(context.Background(), "u2", "u1")
+		if !errors.Is(err, ErrFollowRequestNotFound) {
+			t.Fatalf("expected ErrFollowRequestNotFound, got: %v", err)
+		}
+	})
+
+	t.Run("成功: リクエストが削除される", func(t *testing.T) {
+		t.Parallel()
+		var gotRequesterID, gotTargetID string
+		requestRepo := &fakeUserFollowRequestRepo{
+			ExistsFn: func(ctx context.Context, requesterID, targetID string) (bool, error) {
+				return true, nil
+			},
+			DeleteFn: func(ctx context.Context, requesterID, targetID string) error {
+				gotRequesterID = requesterID
+				gotTargetID = targetID
+				return nil
+			},
+		}
+		svc := newUserService(t, func(d *userServiceDeps) {
+			d.followRequestRepo = requestRepo
+		})
+
+		err := svc.DenyFollowRequest(context.Background(), "u2", "u1")
+		if err != nil {
+			t.Fatalf("expected no error, got: %v", err)
+		}
+		if gotRequesterID != "u1" || gotTargetID != "u2" {
+			t.Fatalf("unexpected args: requesterID=%s targetID=%s", gotRequesterID, gotTargetID)
+		}
+	})
+}
+
+func TestUserService_CanViewUserContent(t *testing.T) {
+	t.Parallel()
+
+	followerRepo := &fakeUserFollowerRepo{
+		IsFollowingFn: func(ctx context.Context, followerID, followeeID string) (bool, error) {
+			return followerID == "follower", nil
+		},
+	}
+	svc := newUserService(t, func(d *userServiceDeps) {
+		d.userFollowerRepo = followerRepo
+	})
+
+	publicOwner := &model.User{ID: "owner"}
+	privateOwner := &model.User{ID: "owner", IsPrivate: true}
+
+	tests := []struct {
+		name     string
+		viewerID string
+		owner    *model.User
+		want     bool
+	}{
+		{name: "公開アカウント: 未ログインでも閲覧可", viewerID: "", owner: publicOwner, want: true},
+		{name: "非公開アカウント: 未ログインは閲覧不可", viewerID: "", owner: privateOwner, want: false},
+		{name: "非公開アカウント: 本人は閲覧可", viewerID: "owner", owner: privateOwner, want: true},
+		{name: "非公開アカウント: 承認済みフォロワーは閲覧可", viewerID: "follower", owner: privateOwner, want: true},
+		{name: "非公開アカウント: フォロワー以外は閲覧不可", viewerID: "stranger", owner: privateOwner, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got, err := svc.CanViewUserContent(context.Background(), tt.viewerID, tt.owner)
+			if err != nil {
+				t.Fatalf("expected no error, got: %v", err)
+			}
+			if got != tt.want {
+				t.Fatalf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestUserService_IsFollowing(t *testing.T) {
+	t.Parallel()
+
+	t.Run("空のIDの場合: false を返す", func(t *testing.T) {
+		t.Parallel()
+		svc := newUserService(t, nil)
+		result, err := svc.IsFollowing(context.Background(), "", "u2")
+		if err != nil {
+			t.Fatalf("expected no error, got: %v", err)
+		}
+		if result {
+			t.Fatalf("expected false")
+		}
+	})
+
+	t.Run("フォローしている: true を返す", func(t *testing.T) {
+		t.Parallel()
+		followerRepo := &fakeUserFollowerRepo{
+			IsFollowingFn: func(ctx context.Context, followerID, followeeID string) (bool, error) {
+				return true, nil
+			},
+		}
+		svc := newUserService(t, func(d *userServiceDeps) {
+			d.userFollowerRepo = followerRepo
+		})
+
+		result, err := svc.IsFollowing(context.Background(), "u1", "u2")
 		if err != nil {
 			t.Fatalf("expected no error, got: %v", err)
 		}
@@ -920,8 +1703,9 @@ func TestUserService_IsFollowing(t *testing.T) {
 				return false, nil
 			},
 		}
-		logger := testutil.NewTestLogger()
-		svc := NewUserService(logger, nil, &fakeUserRepo{}, followerRepo, nil, nil)
+		svc := newUserService(t, func(d *userServiceDeps) {
+			d.userFollowerRepo = followerRepo
+		})
 
 		result, err := svc.IsFollowing(context.Background(), "u1", "u2")
 		if err != nil {
@@ -938,8 +1722,7 @@ func TestUserService_ListFollowing(t *testing.T) {
 
 	t.Run("入力バリデーション: user_id が必須", func(t *testing.T) {
 		t.Parallel()
-		logger := testutil.NewTestLogger()
-		svc := NewUserService(logger, nil, &fakeUserRepo{}, &fakeUserFollowerRepo{}, nil, nil)
+		svc := newUserService(t, nil)
 		_, _, err := svc.ListFollowing(context.Background(), "", 1, 20)
 		if err == nil {
 			t.Fatalf("expected error")
@@ -955,8 +1738,9 @@ func TestUserService_ListFollowing(t *testing.T) {
 				return []*model.User{}, 0, nil
 			},
 		}
-		logger := testutil.NewTestLogger()
-		svc := NewUserService(logger, nil, &fakeUserRepo{}, followerRepo, nil, nil)
+		svc := newUserService(t, func(d *userServiceDeps) {
+			d.userFollowerRepo = followerRepo
+		})
 
 		_, _, err := svc.ListFollowing(context.Background(), "u1", 0, 10)
 		if err != nil {
@@ -976,8 +1760,9 @@ func TestUserService_ListFollowing(t *testing.T) {
 				return []*model.User{}, 0, nil
 			},
 		}
-		logger := testutil.NewTestLogger()
-		svc := NewUserService(logger, nil, &fakeUserRepo{}, followerRepo, nil, nil)
+		svc := newUserService(t, func(d *userServiceDeps) {
+			d.userFollowerRepo = followerRepo
+		})
 
 		_, _, err := svc.ListFollowing(context.Background(), "u1", 2, 1000)
 		if err != nil {
@@ -998,8 +1783,9 @@ func TestUserService_ListFollowing(t *testing.T) {
 				return expected, 1, nil
 			},
 		}
-		logger := testutil.NewTestLogger()
-		svc := NewUserService(logger, nil, &fakeUserRepo{}, followerRepo, nil, nil)
+		svc := newUserService(t, func(d *userServiceDeps) {
+			d.userFollowerRepo = followerRepo
+		})
 
 		users, total, err := svc.ListFollowing(context.Background(), "u1", 1, 20)
 		if err != nil {
@@ -1019,8 +1805,7 @@ func TestUserService_ListFollowers(t *testing.T) {
 
 	t.Run("入力バリデーション: user_id が必須", func(t *testing.T) {
 		t.Parallel()
-		logger := testutil.NewTestLogger()
-		svc := NewUserService(logger, nil, &fakeUserRepo{}, &fakeUserFollowerRepo{}, nil, nil)
+		svc := newUserService(t, nil)
 		_, _, err := svc.ListFollowers(context.Background(), "", 1, 20)
 		if err == nil {
 			t.Fatalf("expected error")
@@ -1037,8 +1822,9 @@ func TestUserService_ListFollowers(t *testing.T) {
 				return []*model.User{}, 0, nil
 			},
 		}
-		logger := testutil.NewTestLogger()
-		svc := NewUserService(logger, nil, &fakeUserRepo{}, followerRepo, nil, nil)
+		svc := newUserService(t, func(d *userServiceDeps) {
+			d.userFollowerRepo = followerRepo
+		})
 
 		_, _, err := svc.ListFollowers(context.Background(), "u1", 0, 0)
 		if err != nil {
@@ -1060,8 +1846,9 @@ func TestUserService_ListFollowers(t *testing.T) {
 				return expected, 2, nil
 			},
 		}
-		logger := testutil.NewTestLogger()
-		svc := NewUserService(logger, nil, &fakeUserRepo{}, followerRepo, nil, nil)
+		svc := newUserService(t, func(d *userServiceDeps) {
+			d.userFollowerRepo = followerRepo
+		})
 
 		users, total, err := svc.ListFollowers(context.Background(), "u1", 1, 20)
 		if err != nil {
@@ -1078,8 +1865,7 @@ func TestUserService_GetFollowStats(t *testing.T) {
 
 	t.Run("入力バリデーション: user_id が必須", func(t *testing.T) {
 		t.Parallel()
-		logger := testutil.NewTestLogger()
-		svc := NewUserService(logger, nil, &fakeUserRepo{}, &fakeUserFollowerRepo{}, nil, nil)
+		svc := newUserService(t, nil)
 		_, _, err := svc.GetFollowStats(context.Background(), "")
 		if err == nil {
 			t.Fatalf("expected error")
@@ -1096,8 +1882,9 @@ func TestUserService_GetFollowStats(t *testing.T) {
 				return 20, nil
 			},
 		}
-		logger := testutil.NewTestLogger()
-		svc := NewUserService(logger, nil, &fakeUserRepo{}, followerRepo, nil, nil)
+		svc := newUserService(t, func(d *userServiceDeps) {
+			d.userFollowerRepo = followerRepo
+		})
 
 		following, followers, err := svc.GetFollowStats(context.Background(), "u1")
 		if err != nil {
@@ -1116,8 +1903,9 @@ func TestUserService_GetFollowStats(t *testing.T) {
 				return 0, expected
 			},
 		}
-		logger := testutil.NewTestLogger()
-		svc := NewUserService(logger, nil, &fakeUserRepo{}, followerRepo, nil, nil)
+		svc := newUserService(t, func(d *userServiceDeps) {
+			d.userFollowerRepo = followerRepo
+		})
 
 		_, _, err := svc.GetFollowStats(context.Background(), "u1")
 		if !errors.Is(err, expected) {
@@ -1136,8 +1924,9 @@ func TestUserService_GetFollowStats(t *testing.T) {
 				return 0, expected
 			},
 		}
-		logger := testutil.NewTestLogger()
-		svc := NewUserService(logger, nil, &fakeUserRepo{}, followerRepo, nil, nil)
+		svc := newUserService(t, func(d *userServiceDeps) {
+			d.userFollowerRepo = followerRepo
+		})
 
 		_, _, err := svc.GetFollowStats(context.Background(), "u1")
 		if !errors.Is(err, expected) {
@@ -1145,3 +1934,170 @@ func TestUserService_GetFollowStats(t *testing.T) {
 		}
 	})
 }
+
+func TestUserService_BlockUser(t *testing.T) {
+	t.Parallel()
+
+	t.Run("自分自身をブロック: ErrCannotBlockSelf", func(t *testing.T) {
+		t.Parallel()
+		svc := newUserService(t, func(d *userServiceDeps) {
+			d.userBlockRepo = &testutil.FakeUserBlockRepository{}
+		})
+		err := svc.BlockUser(context.Background(), "u1", "u1")
+		if !errors.Is(err, ErrCannotBlockSelf) {
+			t.Fatalf("expected ErrCannotBlockSelf, got: %v", err)
+		}
+	})
+
+	t.Run("ブロック対象が存在しない: ErrUserNotFound", func(t *testing.T) {
+		t.Parallel()
+		svc := newUserService(t, func(d *userServiceDeps) {
+			d.userBlockRepo = &testutil.FakeUserBlockRepository{}
+		})
+		err := svc.BlockUser(context.Background(), "u1", "u2")
+		if !errors.Is(err, ErrUserNotFound) {
+			t.Fatalf("expected ErrUserNotFound, got: %v", err)
+		}
+	})
+
+	t.Run("既にブロック済み: ErrAlreadyBlocked", func(t *testing.T) {
+		t.Parallel()
+		repo := &fakeUserRepo{
+			FindByIDFn: func(ctx context.Context, userID string) (*model.User, error) {
+				return &model.User{ID: userID}, nil
+			},
+		}
+		blockRepo := &testutil.FakeUserBlockRepository{
+			IsBlockingFn: func(ctx context.Context, blockerID, blockedID string) (bool, error) {
+				return true, nil
+			},
+		}
+		svc := newUserService(t, func(d *userServiceDeps) {
+			d.userRepo = repo
+			d.userBlockRepo = blockRepo
+		})
+		err := svc.BlockUser(context.Background(), "u1", "u2")
+		if !errors.Is(err, ErrAlreadyBlocked) {
+			t.Fatalf("expected ErrAlreadyBlocked, got: %v", err)
+		}
+	})
+}
+
+func TestUserService_UnblockUser(t *testing.T) {
+	t.Parallel()
+
+	t.Run("ブロックしていない: ErrNotBlocked", func(t *testing.T) {
+		t.Parallel()
+		svc := newUserService(t, func(d *userServiceDeps) {
+			d.userBlockRepo = &testutil.FakeUserBlockRepository{}
+		})
+		err := svc.UnblockUser(context.Background(), "u1", "u2")
+		if !errors.Is(err, ErrNotBlocked) {
+			t.Fatalf("expected ErrNotBlocked, got: %v", err)
+		}
+	})
+
+	t.Run("成功: ブロック関係が削除される", func(t *testing.T) {
+		t.Parallel()
+		var gotBlocker, gotBlocked string
+		blockRepo := &testutil.FakeUserBlockRepository{
+			IsBlockingFn: func(ctx context.Context, blockerID, blockedID string) (bool, error) {
+				return true, nil
+			},
+			DeleteFn: func(ctx context.Context, blockerID, blockedID string) error {
+				gotBlocker, gotBlocked = blockerID, blockedID
+				return nil
+			},
+		}
+		svc := newUserService(t, func(d *userServiceDeps) {
+			d.userBlockRepo = blockRepo
+		})
+		if err := svc.UnblockUser(context.Background(), "u1", "u2"); err != nil {
+			t.Fatalf("expected no error, got: %v", err)
+		}
+		if gotBlocker != "u1" || gotBlocked != "u2" {
+			t.Fatalf("unexpected delete args: %s, %s", gotBlocker, gotBlocked)
+		}
+	})
+}
+
+func TestUserService_MuteUser(t *testing.T) {
+	t.Parallel()
+
+	t.Run("既にミュート済み: ErrAlreadyMuted", func(t *testing.T) {
+		t.Parallel()
+		repo := &fakeUserRepo{
+			FindByIDFn: func(ctx context.Context, userID string) (*model.User, error) {
+				return &model.User{ID: userID}, nil
+			},
+		}
+		muteRepo := &testutil.FakeUserMuteRepository{
+			IsMutingFn: func(ctx context.Context, muterID, mutedID string) (bool, error) {
+				return true, nil
+			},
+		}
+		svc := newUserService(t, func(d *userServiceDeps) {
+			d.userRepo = repo
+			d.userMuteRepo = muteRepo
+		})
+		err := svc.MuteUser(context.Background(), "u1", "u2")
+		if !errors.Is(err, ErrAlreadyMuted) {
+			t.Fatalf("expected ErrAlreadyMuted, got: %v", err)
+		}
+	})
+
+	t.Run("確認後に別リクエストでミュートされた: ErrAlreadyMuted", func(t *testing.T) {
+		t.Parallel()
+		repo := &fakeUserRepo{
+			FindByIDFn: func(ctx context.Context, userID string) (*model.User, error) {
+				return &model.User{ID: userID}, nil
+			},
+		}
+		muteRepo := &testutil.FakeUserMuteRepository{
+			CreateFn: func(ctx context.Context, muterID, mutedID string) error {
+				return &pgconn.PgError{Code: "23505"}
+			},
+		}
+		svc := newUserService(t, func(d *userServiceDeps) {
+			d.userRepo = repo
+			d.userMuteRepo = muteRepo
+		})
+		err := svc.MuteUser(context.Background(), "u1", "u2")
+		if !errors.Is(err, ErrAlreadyMuted) {
+			t.Fatalf("expected ErrAlreadyMuted, got: %v", err)
+		}
+	})
+
+	t.Run("成功: ミュート関係が作成される（フォロー関係には影響しない）", func(t *testing.T) {
+		t.Parallel()
+		repo := &fakeUserRepo{
+			FindByIDFn: func(ctx context.Context, userID string) (*model.User, error) {
+				return &model.User{ID: userID}, nil
+			},
+		}
+		followerRepo := &fakeUserFollowerRepo{
+			DeleteFn: func(ctx context.Context, followerID, followeeID string) error {
+				t.Fatalf("expected follow relation not to be deleted")
+				return nil
+			},
+		}
+		created := false
+		muteRepo := &testutil.FakeUserMuteRepository{
+			CreateFn: func(ctx context.Context, muterID, mutedID string) error {
+				created = muterID == "u1" && mutedID == "u2"
+				return nil
+			},
+		}
+		svc := newUserService(t, func(d *userServiceDeps) {
+			d.userRepo = repo
+			d.userFollowerRepo = followerRepo
+			d.userMuteRepo = muteRepo
+		})
+		if err := svc.MuteUser(context.Background(), "u1", "u2"); err != nil {
+			t.Fatalf("expected no error, got: %v", err)
+		}
+		if !created {
+			t.Fatalf("expected mute to be created")
+		}
+	})
+}
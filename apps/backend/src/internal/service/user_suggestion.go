@@ -0,0 +1,89 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"cinetag-backend/src/internal/model"
+)
+
+const (
+	// おすすめユーザーの取得件数の既定値。
+	defaultUserSuggestionLimit = 20
+	// おすすめユーザーの取得件数の上限。
+	maxUserSuggestionLimit = 50
+)
+
+// おすすめユーザーとして表示する理由を表す。
+type SuggestionReason string
+
+const (
+	// フォロー中のユーザーにフォローされている。
+	SuggestionReasonFollowedByFollowing SuggestionReason = "followed_by_following"
+	// 同じタグをフォローしている。
+	SuggestionReasonSharedTags SuggestionReason = "shared_tags"
+	// 同じ映画をタグに追加している。
+	SuggestionReasonSharedMovies SuggestionReason = "shared_movies"
+	// フォロワーが多い（共通点がない場合）。
+	SuggestionReasonPopular SuggestionReason = "popular"
+)
+
+// おすすめユーザーの1件分を表す。
+type UserSuggestion struct {
+	User              *model.User
+	Reason            SuggestionReason
+	MutualFollowCount int64
+	SharedTagCount    int64
+	SharedMovieCount  int64
+	FollowerCount     int64
+}
+
+// 指定ユーザーへのおすすめユーザーを取得する。
+func (s *userService) SuggestUsers(ctx context.Context, userID string, limit int) ([]UserSuggestion, error) {
+	if userID == "" {
+		return nil, errors.New("user_id is required")
+	}
+	if limit < 1 || limit > maxUserSuggestionLimit {
+		limit = defaultUserSuggestionLimit
+	}
+
+	rows, err := s.userFollowerRepo.ListSuggestions(ctx, userID, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	suggestions := make([]UserSuggestion, len(rows))
+	for i := range rows {
+		u := rows[i].User
+		suggestions[i] = UserSuggestion{
+			User:              &u,
+			Reason:            suggestionReason(rows[i].MutualFollowCount, rows[i].SharedTagCount, rows[i].SharedMovieCount),
+			MutualFollowCount: rows[i].MutualFollowCount,
+			SharedTagCount:    rows[i].SharedTagCount,
+			SharedMovieCount:  rows[i].SharedMovieCount,
+			FollowerCount:     rows[i].FollowerCount,
+		}
+	}
+	return suggestions, nil
+}
+
+// スコアへの寄与が最も大きい手がかりを、おすすめの理由として返す。
+// 重みはリポジトリの並び順（友達の友達 3点・共通タグ 2点・共通映画 1点）と揃えている。
+func suggestionReason(mutualFollows, sharedTags, sharedMovies int64) SuggestionReason {
+	reason := SuggestionReasonPopular
+	best := int64(0)
+	for _, c := range []struct {
+		reason SuggestionReason
+		score  int64
+	}{
+		{SuggestionReasonFollowedByFollowing, 3 * mutualFollows},
+		{SuggestionReasonSharedTags, 2 * sharedTags},
+		{SuggestionReasonSharedMovies, sharedMovies},
+	} {
+		if c.score > best {
+			reason = c.reason
+			best = c.score
+		}
+	}
+	return reason
+}
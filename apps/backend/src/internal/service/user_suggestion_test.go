@@ -0,0 +1,72 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"cinetag-backend/src/internal/model"
+	"cinetag-backend/src/internal/repository"
+)
+
+func TestSuggestionReason(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name                                   string
+		mutualFollows, sharedTags, sharedMovie int64
+		want                                   SuggestionReason
+	}{
+		{name: "手がかりなし", want: SuggestionReasonPopular},
+		{name: "友達の友達", mutualFollows: 1, sharedMovie: 2, want: SuggestionReasonFollowedByFollowing},
+		{name: "共通タグ", mutualFollows: 1, sharedTags: 2, want: SuggestionReasonSharedTags},
+		{name: "共通映画", sharedTags: 1, sharedMovie: 5, want: SuggestionReasonSharedMovies},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if got := suggestionReason(tt.mutualFollows, tt.sharedTags, tt.sharedMovie); got != tt.want {
+				t.Fatalf("expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestUserService_SuggestUsers(t *testing.T) {
+	t.Parallel()
+
+	t.Run("件数を正規化し、理由を付与する", func(t *testing.T) {
+		t.Parallel()
+
+		var gotLimit int
+		followerRepo := &fakeUserFollowerRepo{
+			ListSuggestionsFn: func(ctx context.Context, userID string, limit int) ([]repository.UserSuggestionRow, error) {
+				gotLimit = limit
+				return []repository.UserSuggestionRow{
+					{User: model.User{ID: "u2"}, MutualFollowCount: 2},
+					{User: model.User{ID: "u3"}, FollowerCount: 100},
+				}, nil
+			},
+		}
+		svc := newUserService(t, func(d *userServiceDeps) {
+			d.userFollowerRepo = followerRepo
+		})
+
+		got, err := svc.SuggestUsers(context.Background(), "u1", 1000)
+		if err != nil {
+			t.Fatalf("expected no error, got: %v", err)
+		}
+		if gotLimit != defaultUserSuggestionLimit {
+			t.Fatalf("expected limit %d, got %d", defaultUserSuggestionLimit, gotLimit)
+		}
+		if len(got) != 2 {
+			t.Fatalf("expected 2 suggestions, got %d", len(got))
+		}
+		if got[0].User.ID != "u2" || got[0].Reason != SuggestionReasonFollowedByFollowing || got[0].MutualFollowCount != 2 {
+			t.Fatalf("unexpected suggestion: %+v", got[0])
+		}
+		if got[1].Reason != SuggestionReasonPopular || got[1].FollowerCount != 100 {
+			t.Fatalf("unexpected suggestion: %+v", got[1])
+		}
+	})
+}
@@ -0,0 +1,494 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"cinetag-backend/src/internal/model"
+	"cinetag-backend/src/internal/repository"
+	"cinetag-backend/src/internal/webhook"
+
+	"gorm.io/gorm"
+)
+
+const (
+	// ユーザーごとに登録できる Webhook の上限。
+	maxWebhooksPerUser = 10
+	// 利用者が指定する署名鍵の長さ。
+	minWebhookSecretLength = 16
+	maxWebhookSecretLength = 256
+	// 自動生成する署名鍵の接頭辞。
+	webhookSecretPrefix = "whsec_"
+)
+
+var (
+	ErrWebhookNotFound      = errors.New("webhook not found")                                                                     // Webhook が存在しない（他のユーザーの Webhook を含む）
+	ErrInvalidWebhookURL    = errors.New("url must be a public https URL")                                                        // 登録できない URL
+	ErrInvalidWebhookEvents = errors.New("events must contain at least one of movie_added, movie_removed, tag_updated, followed") // 不明なイベント・イベントの指定なし
+	ErrInvalidWebhookSecret = errors.New("secret must be between 16 and 256 characters")                                          // 署名鍵の長さが不正
+	ErrWebhookLimitExceeded = errors.New("webhook limit exceeded")                                                                // 登録数の上限に達した
+)
+
+// 送信先が配信を拒否した（リトライしても成功しない）場合のエラー。
+// 2xx 以外のうち、408・429・5xx を除く応答と、送信先が内部ネットワークの場合に返す。
+var ErrWebhookDeliveryRejected = errors.New("webhook delivery rejected")
+
+// Webhook のイベントの並び順（登録時はこの順に並べて保存する）。
+var webhookEventOrder = []string{
+	model.WebhookEventMovieAdded,
+	model.WebhookEventMovieRemoved,
+	model.WebhookEventTagUpdated,
+	model.WebhookEventFollowed,
+}
+
+// アウトボックスイベントの種類ごとの、Webhook で配信するイベントの種類。
+var webhookEventByOutboxEvent = map[string]string{
+	model.OutboxEventTypeTagMovieAdded:   model.WebhookEventMovieAdded,
+	model.OutboxEventTypeTagMovieRemoved: model.WebhookEventMovieRemoved,
+	model.OutboxEventTypeTagUpdated:      model.WebhookEventTagUpdated,
+	model.OutboxEventTypeTagFollowed:     model.WebhookEventFollowed,
+	model.OutboxEventTypeUserFollowed:    model.WebhookEventFollowed,
+}
+
+// Webhook の設定値。
+type WebhookConfig struct {
+	// AllowPrivateNetworks は http の URL と、ループバック・プライベートアドレスへの配信を許可する（ローカル開発用）。
+	AllowPrivateNetworks bool
+}
+
+// 環境変数から Webhook の設定値を読み込む。
+func WebhookConfigFromEnv() WebhookConfig {
+	return WebhookConfig{
+		AllowPrivateNetworks: strings.EqualFold(strings.TrimSpace(os.Getenv("WEBHOOK_ALLOW_PRIVATE_NETWORKS")), "true"),
+	}
+}
+
+// Webhook 作成の入力。
+type CreateWebhookInput struct {
+	TagID  *string // nil の場合はアカウント全体
+	URL    string
+	Events []string
+	Secret *string // nil の場合は自動生成する
+}
+
+// Webhook 更新の入力。nil の項目は更新しない。
+type UpdateWebhookPatch struct {
+	URL      *string
+	Events   *[]string
+	IsActive *bool
+}
+
+// 作成した Webhook。署名鍵は作成時のみ返す。
+type CreatedWebhook struct {
+	*model.Webhook
+	Secret string `json:"secret"`
+}
+
+// Webhook で配信する本文。
+type WebhookEventBody struct {
+	ID        string    `json:"id"`   // イベントID（配信先・リトライによらず共通）
+	Type      string    `json:"type"` // movie_added / movie_removed / tag_updated / followed
+	CreatedAt time.Time `json:"created_at"`
+	// Content は人が読める概要。Discord の Webhook はこの項目をメッセージとして表示する。
+	Content string                              `json:"content"`
+	Tag     *WebhookEventTag                    `json:"tag,omitempty"`   // ユーザーへのフォローの場合は nil
+	Actor   *WebhookEventActor                  `json:"actor,omitempty"` // 退会済みの場合は nil
+	Movie   *WebhookEventMovie                  `json:"movie,omitempty"`
+	Changes *model.NotificationTagUpdateDetails `json:"changes,omitempty"` // tag_updated の変更内容
+}
+
+// Webhook の本文のタグ。
+type WebhookEventTag struct {
+	ID       string `json:"id"`
+	Title    string `json:"title"`
+	IsPublic bool   `json:"is_public"`
+}
+
+// Webhook の本文の操作したユーザー。
+type WebhookEventActor struct {
+	ID          string `json:"id"`
+	DisplayID   string `json:"display_id"`
+	DisplayName string `json:"display_name"`
+}
+
+// Webhook の本文の映画。
+type WebhookEventMovie struct {
+	TmdbMovieID int     `json:"tmdb_movie_id"`
+	Title       *string `json:"title"` // 映画キャッシュがない場合は nil
+}
+
+// Webhook に関するユースケースを表すインターフェース。
+type WebhookService interface {
+	// Webhook を作成する。タグを指定する場合はタグの作成者のみ作成できる（ErrTagNotFound / ErrTagPermissionDenied）。
+	CreateWebhook(ctx context.Context, userID string, in CreateWebhookInput) (*CreatedWebhook, error)
+	// 自分が登録した Webhook を新しい順で返す。
+	ListWebhooks(ctx context.Context, userID string) ([]*model.Webhook, error)
+	// 自分の Webhook を更新し、更新後の Webhook を返す。
+	UpdateWebhook(ctx context.Context, webhookID, userID string, patch UpdateWebhookPatch) (*model.Webhook, error)
+	// 自分の Webhook を削除する。
+	DeleteWebhook(ctx context.Context, webhookID, userID string) error
+	// 自分の Webhook の配信ログを新しい順で返す。
+	ListDeliveries(ctx context.Context, webhookID, userID string, page, pageSize int) ([]*model.WebhookDelivery, int64, error)
+
+	// 通知と同じアウトボックスイベントから、購読している Webhook ごとの配信イベント（webhook.deliver）を積む。
+	FanOut(ctx context.Context, event *model.OutboxEvent) error
+	// 配信イベント（webhook.deliver）を1回送信し、配信ログに記録する。
+	// リトライしても成功しない場合は ErrWebhookDeliveryRejected を返す。
+	Deliver(ctx context.Context, event *model.OutboxEvent) error
+}
+
+type webhookService struct {
+	logger      *slog.Logger
+	webhookRepo repository.WebhookRepository
+	tagRepo     repository.TagRepository
+	userRepo    repository.UserRepository
+	outboxRepo  repository.OutboxRepository
+	sender      webhook.Sender
+	cfg         WebhookConfig
+}
+
+// WebhookService を生成する。
+func NewWebhookService(
+	logger *slog.Logger,
+	webhookRepo repository.WebhookRepository,
+	tagRepo repository.TagRepository,
+	userRepo repository.UserRepository,
+	outboxRepo repository.OutboxRepository,
+	sender webhook.Sender,
+	cfg WebhookConfig,
+) WebhookService {
+	return &webhookService{
+		logger:      logger,
+		webhookRepo: webhookRepo,
+		tagRepo:     tagRepo,
+		userRepo:    userRepo,
+		outboxRepo:  outboxRepo,
+		sender:      sender,
+		cfg:         cfg,
+	}
+}
+
+// Webhook を作成する。
+func (s *webhookService) CreateWebhook(ctx context.Context, userID string, in CreateWebhookInput) (*CreatedWebhook, error) {
+	url := strings.TrimSpace(in.URL)
+	if err := webhook.ValidateURL(url, s.cfg.AllowPrivateNetworks); err != nil {
+		return nil, ErrInvalidWebhookURL
+	}
+	events, err := normalizeWebhookEvents(in.Events)
+	if err != nil {
+		return nil, err
+	}
+	secret, err := webhookSecret(in.Secret)
+	if err != nil {
+		return nil, err
+	}
+
+	if in.TagID != nil {
+		tag, err := s.tagRepo.FindByID(ctx, *in.TagID)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil, ErrTagNotFound
+			}
+			return nil, err
+		}
+		if tag.UserID != userID {
+			return nil, ErrTagPermissionDenied
+		}
+	}
+
+	w := &model.Webhook{
+		UserID:   userID,
+		TagID:    in.TagID,
+		URL:      url,
+		Secret:   secret,
+		Events:   events,
+		IsActive: true,
+	}
+	if err := s.webhookRepo.Create(ctx, w, maxWebhooksPerUser); err != nil {
+		if errors.Is(err, repository.ErrWebhookLimitReached) {
+			return nil, ErrWebhookLimitExceeded
+		}
+		return nil, err
+	}
+	return &CreatedWebhook{Webhook: w, Secret: secret}, nil
+}
+
+// 自分が登録した Webhook を返す。
+func (s *webhookService) ListWebhooks(ctx context.Context, userID string) ([]*model.Webhook, error) {
+	return s.webhookRepo.ListByUser(ctx, userID)
+}
+
+// 自分の Webhook を更新する。
+func (s *webhookService) UpdateWebhook(ctx context.Context, webhookID, userID string, patch UpdateWebhookPatch) (*model.Webhook, error) {
+	update := repository.WebhookUpdatePatch{IsActive: patch.IsActive}
+	if patch.URL != nil {
+		url := strings.TrimSpace(*patch.URL)
+		if err := webhook.ValidateURL(url, s.cfg.AllowPrivateNetworks); err != nil {
+			return nil, ErrInvalidWebhookURL
+		}
+		update.URL = &url
+	}
+	if patch.Events != nil {
+		events, err := normalizeWebhookEvents(*patch.Events)
+		if err != nil {
+			return nil, err
+		}
+		update.Events = &events
+	}
+
+	if err := s.webhookRepo.Update(ctx, webhookID, userID, update); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrWebhookNotFound
+		}
+		return nil, err
+	}
+	return s.webhookRepo.FindByID(ctx, webhookID)
+}
+
+// 自分の Webhook を削除する。
+func (s *webhookService) DeleteWebhook(ctx context.Context, webhookID, userID string) error {
+	if err := s.webhookRepo.Delete(ctx, webhookID, userID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrWebhookNotFound
+		}
+		return err
+	}
+	return nil
+}
+
+// 自分の Webhook の配信ログを返す。
+func (s *webhookService) ListDeliveries(ctx context.Context, webhookID, userID string, page, pageSize int) ([]*model.WebhookDelivery, int64, error) {
+	w, err := s.webhookRepo.FindByID(ctx, webhookID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, 0, ErrWebhookNotFound
+		}
+		return nil, 0, err
+	}
+	if w.UserID != userID {
+		return nil, 0, ErrWebhookNotFound
+	}
+	if page < 1 {
+		page = 1
+	}
+	return s.webhookRepo.ListDeliveries(ctx, webhookID, (page-1)*pageSize, pageSize)
+}
+
+// アウトボックスイベントを購読している Webhook ごとの配信イベントに振り分ける。
+// 配信イベントの idempotency_key は配信先と元のイベントから組み立てるため、リトライで振り分けをやり直しても配信待ちのものは重複しない。
+func (s *webhookService) FanOut(ctx context.Context, event *model.OutboxEvent) error {
+	eventType, ok := webhookEventByOutboxEvent[event.EventType]
+	if !ok {
+		return nil
+	}
+	var p model.NotificationEventPayload
+	if err := json.Unmarshal(event.Payload, &p); err != nil {
+		return fmt.Errorf("%w: invalid payload: %v", ErrWebhookDeliveryRejected, err)
+	}
+
+	body := &WebhookEventBody{ID: event.ID, Type: eventType, CreatedAt: event.CreatedAt}
+	ownerUserID := p.RecipientUserID
+	if p.TagID != "" {
+		tag, err := s.tagRepo.FindByID(ctx, p.TagID)
+		if err != nil {
+			// タグが削除された場合は Webhook も削除されているため、配信しない
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil
+			}
+			return err
+		}
+		ownerUserID = tag.UserID
+		body.Tag = &WebhookEventTag{ID: tag.ID, Title: tag.Title, IsPublic: tag.IsPublic}
+	}
+
+	webhooks, err := s.webhookRepo.ListSubscribed(ctx, eventType, p.TagID, ownerUserID)
+	if err != nil || len(webhooks) == 0 {
+		return err
+	}
+
+	if err := s.fillWebhookEventBody(ctx, body, p); err != nil {
+		return err
+	}
+	raw, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+
+	deliveries := make([]*model.OutboxEvent, 0, len(webhooks))
+	for _, w := range webhooks {
+		payload, err := json.Marshal(model.WebhookDeliveryPayload{WebhookID: w.ID, EventType: eventType, Body: raw})
+		if err != nil {
+			return err
+		}
+		deliveries = append(deliveries, &model.OutboxEvent{
+			EventType:      model.OutboxEventTypeWebhookDelivery,
+			Payload:        payload,
+			IdempotencyKey: strings.Join([]string{model.OutboxEventTypeWebhookDelivery, w.ID, event.ID}, ":"),
+			SourceEventID:  &event.ID,
+			Status:         model.OutboxStatusPending,
+		})
+	}
+	return s.outboxRepo.Enqueue(ctx, deliveries...)
+}
+
+// 本文の操作したユーザー・映画・変更内容と概要を設定する。
+func (s *webhookService) fillWebhookEventBody(ctx context.Context, body *WebhookEventBody, p model.NotificationEventPayload) error {
+	actorName := "退会したユーザー"
+	if actor, err := s.userRepo.FindByID(ctx, p.ActorUserID); err == nil {
+		body.Actor = &WebhookEventActor{ID: actor.ID, DisplayID: actor.DisplayID, DisplayName: actor.DisplayName}
+		actorName = actor.DisplayName
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+
+	movieName := ""
+	if p.TmdbMovieID > 0 {
+		title, err := s.webhookRepo.FindMovieTitle(ctx, p.TmdbMovieID)
+		if err != nil {
+			return err
+		}
+		body.Movie = &WebhookEventMovie{TmdbMovieID: p.TmdbMovieID, Title: title}
+		movieName = fmt.Sprintf("映画（TMDb ID: %d）", p.TmdbMovieID)
+		if title != nil {
+			movieName = "「" + *title + "」"
+		}
+	}
+
+	switch body.Type {
+	case model.WebhookEventMovieAdded:
+		body.Content = fmt.Sprintf("%s さんが「%s」に%sを追加しました", actorName, body.Tag.Title, movieName)
+	case model.WebhookEventMovieRemoved:
+		body.Content = fmt.Sprintf("%s さんが「%s」から%sを削除しました", actorName, body.Tag.Title, movieName)
+	case model.WebhookEventTagUpdated:
+		body.Changes = &model.NotificationTagUpdateDetails{PreviousTitle: p.PreviousTitle, MadePrivate: p.MadePrivate}
+		var changes []string
+		if p.PreviousTitle != nil {
+			changes = append(changes, fmt.Sprintf("「%s」から「%s」に名前が変わりました", *p.PreviousTitle, body.Tag.Title))
+		}
+		if p.MadePrivate {
+			changes = append(changes, fmt.Sprintf("「%s」が非公開になりました", body.Tag.Title))
+		}
+		body.Content = strings.Join(changes, "。")
+	case model.WebhookEventFollowed:
+		if body.Tag != nil {
+			body.Content = fmt.Sprintf("%s さんが「%s」をフォローしました", actorName, body.Tag.Title)
+		} else {
+			body.Content = fmt.Sprintf("%s さんにフォローされました", actorName)
+		}
+	}
+	// 受信側（Discord・Slack など）でタグ名・ユーザー名がメンションとして扱われないようにする
+	body.Content = strings.ReplaceAll(body.Content, "@", "@\u200b")
+	return nil
+}
+
+// 配信イベントを1回送信し、配信ログに記録する。
+func (s *webhookService) Deliver(ctx context.Context, event *model.OutboxEvent) error {
+	var p model.WebhookDeliveryPayload
+	if err := json.Unmarshal(event.Payload, &p); err != nil {
+		return fmt.Errorf("%w: invalid payload: %v", ErrWebhookDeliveryRejected, err)
+	}
+
+	w, err := s.webhookRepo.FindByID(ctx, p.WebhookID)
+	if err != nil {
+		// 配信までの間に削除された Webhook には配信しない
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		return err
+	}
+	// 停止中の Webhook への配信は破棄する（再開しても停止中のイベントは配信しない）
+	if !w.IsActive {
+		return nil
+	}
+
+	start := time.Now()
+	resp, sendErr := s.sender.Send(ctx, &webhook.Request{
+		URL:        w.URL,
+		Secret:     w.Secret,
+		EventType:  p.EventType,
+		DeliveryID: event.ID,
+		Body:       p.Body,
+	})
+	delivery := &model.WebhookDelivery{
+		WebhookID:  w.ID,
+		DeliveryID: event.ID,
+		EventType:  p.EventType,
+		Attempt:    event.Attempts,
+		DurationMs: int(time.Since(start).Milliseconds()),
+	}
+
+	var result error
+	switch {
+	case sendErr != nil:
+		result = sendErr
+		if errors.Is(sendErr, webhook.ErrDisallowedAddress) {
+			result = fmt.Errorf("%w: %v", ErrWebhookDeliveryRejected, sendErr)
+		}
+	case resp.StatusCode >= 200 && resp.StatusCode < 300:
+		delivery.Succeeded = true
+	case resp.StatusCode == http.StatusRequestTimeout || resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500:
+		result = fmt.Errorf("webhook responded with status %d", resp.StatusCode)
+	default:
+		result = fmt.Errorf("%w: status %d", ErrWebhookDeliveryRejected, resp.StatusCode)
+	}
+	if resp != nil {
+		delivery.StatusCode = &resp.StatusCode
+	}
+	if result != nil {
+		msg := result.Error()
+		delivery.Error = &msg
+	}
+
+	// 配信ログの記録に失敗しても、配信の結果（リトライするかどうか）は変えない
+	if err := s.webhookRepo.CreateDelivery(ctx, delivery); err != nil {
+		s.logger.Warn("service.Deliver failed to record webhook delivery",
+			slog.String("webhook_id", w.ID),
+			slog.String("delivery_id", event.ID),
+			slog.Any("error", err),
+		)
+	}
+	return result
+}
+
+// イベントの指定を検査し、重複を除いて決まった順に並べて返す。
+func normalizeWebhookEvents(events []string) ([]string, error) {
+	requested := make(map[string]struct{}, len(events))
+	for _, e := range events {
+		requested[strings.TrimSpace(e)] = struct{}{}
+	}
+	normalized := make([]string, 0, len(requested))
+	for _, e := range webhookEventOrder {
+		if _, ok := requested[e]; ok {
+			normalized = append(normalized, e)
+			delete(requested, e)
+		}
+	}
+	if len(normalized) == 0 || len(requested) > 0 {
+		return nil, ErrInvalidWebhookEvents
+	}
+	return normalized, nil
+}
+
+// 署名鍵を検査して返す。指定がない場合はランダムに生成する。
+func webhookSecret(secret *string) (string, error) {
+	if secret != nil {
+		if l := len(*secret); l < minWebhookSecretLength || l > maxWebhookSecretLength {
+			return "", ErrInvalidWebhookSecret
+		}
+		return *secret, nil
+	}
+	b := make([]byte, 24)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return webhookSecretPrefix + hex.EncodeToString(b), nil
+}
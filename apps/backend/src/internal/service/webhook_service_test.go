@@ -0,0 +1,371 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"cinetag-backend/src/internal/model"
+	"cinetag-backend/src/internal/repository"
+	"cinetag-backend/src/internal/testutil"
+	"cinetag-backend/src/internal/webhook"
+
+	"gorm.io/gorm"
+)
+
+func newTestWebhookService(webhookRepo *testutil.FakeWebhookRepository, tagRepo *testutil.FakeTagRepository, userRepo *fakeUserRepo, outboxRepo *testutil.FakeOutboxRepository, sender webhook.Sender) WebhookService {
+	if tagRepo == nil {
+		tagRepo = &testutil.FakeTagRepository{}
+	}
+	if userRepo == nil {
+		userRepo = &fakeUserRepo{}
+	}
+	if outboxRepo == nil {
+		outboxRepo = &testutil.FakeOutboxRepository{}
+	}
+	if sender == nil {
+		sender = &testutil.FakeWebhookSender{}
+	}
+	return NewWebhookService(testutil.NewTestLogger(), webhookRepo, tagRepo, userRepo, outboxRepo, sender, WebhookConfig{})
+}
+
+func TestWebhookService_CreateWebhook(t *testing.T) {
+	t.Parallel()
+
+	tagRepo := &testutil.FakeTagRepository{
+		FindByIDFn: func(ctx context.Context, id string) (*model.Tag, error) {
+			if id == "missing" {
+				return nil, gorm.ErrRecordNotFound
+			}
+			return &model.Tag{ID: id, UserID: "owner"}, nil
+		},
+	}
+	tagID := func(id string) *string { return &id }
+	shortSecret := "short"
+
+	t.Run("入力が不正な場合はエラー", func(t *testing.T) {
+		t.Parallel()
+
+		cases := []struct {
+			name   string
+			userID string
+			in     CreateWebhookInput
+			want   error
+		}{
+			{name: "http の URL", userID: "owner", in: CreateWebhookInput{URL: "http://example.com/hook", Events: []string{"movie_added"}}, want: ErrInvalidWebhookURL},
+			{name: "内部ネットワークの URL", userID: "owner", in: CreateWebhookInput{URL: "https://127.0.0.1/hook", Events: []string{"movie_added"}}, want: ErrInvalidWebhookURL},
+			{name: "イベントの指定なし", userID: "owner", in: CreateWebhookInput{URL: "https://example.com/hook"}, want: ErrInvalidWebhookEvents},
+			{name: "不明なイベント", userID: "owner", in: CreateWebhookInput{URL: "https://example.com/hook", Events: []string{"movie_added", "liked"}}, want: ErrInvalidWebhookEvents},
+			{name: "短すぎる署名鍵", userID: "owner", in: CreateWebhookInput{URL: "https://example.com/hook", Events: []string{"followed"}, Secret: &shortSecret}, want: ErrInvalidWebhookSecret},
+			{name: "存在しないタグ", userID: "owner", in: CreateWebhookInput{TagID: tagID("missing"), URL: "https://example.com/hook", Events: []string{"followed"}}, want: ErrTagNotFound},
+			{name: "他のユーザーのタグ", userID: "other", in: CreateWebhookInput{TagID: tagID("t1"), URL: "https://example.com/hook", Events: []string{"followed"}}, want: ErrTagPermissionDenied},
+		}
+		for _, tc := range cases {
+			webhookRepo := &testutil.FakeWebhookRepository{
+				CreateFn: func(ctx context.Context, w *model.Webhook, maxPerUser int64) error {
+					t.Fatalf("%s: unexpected Create call", tc.name)
+					return nil
+				},
+			}
+			svc := newTestWebhookService(webhookRepo, tagRepo, nil, nil, nil)
+			if _, err := svc.CreateWebhook(context.Background(), tc.userID, tc.in); !errors.Is(err, tc.want) {
+				t.Fatalf("%s: expected %v, got %v", tc.name, tc.want, err)
+			}
+		}
+	})
+
+	t.Run("登録数の上限に達した場合は ErrWebhookLimitExceeded", func(t *testing.T) {
+		t.Parallel()
+
+		webhookRepo := &testutil.FakeWebhookRepository{
+			CreateFn: func(ctx context.Context, w *model.Webhook, maxPerUser int64) error {
+				if maxPerUser != maxWebhooksPerUser {
+					t.Fatalf("expected limit %d, got %d", maxWebhooksPerUser, maxPerUser)
+				}
+				return repository.ErrWebhookLimitReached
+			},
+		}
+		svc := newTestWebhookService(webhookRepo, tagRepo, nil, nil, nil)
+		_, err := svc.CreateWebhook(context.Background(), "owner", CreateWebhookInput{URL: "https://example.com/hook", Events: []string{"followed"}})
+		if !errors.Is(err, ErrWebhookLimitExceeded) {
+			t.Fatalf("expected ErrWebhookLimitExceeded, got %v", err)
+		}
+	})
+
+	t.Run("イベントを並べ替えて保存し、署名鍵を生成して返す", func(t *testing.T) {
+		t.Parallel()
+
+		var created *model.Webhook
+		webhookRepo := &testutil.FakeWebhookRepository{
+			CreateFn: func(ctx context.Context, w *model.Webhook, maxPerUser int64) error {
+				created = w
+				return nil
+			},
+		}
+		svc := newTestWebhookService(webhookRepo, tagRepo, nil, nil, nil)
+		got, err := svc.CreateWebhook(context.Background(), "owner", CreateWebhookInput{
+			TagID:  tagID("t1"),
+			URL:    " https://discord.com/api/webhooks/1/abc ",
+			Events: []string{"followed", "movie_added", "movie_added"},
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if created == nil || created.UserID != "owner" || *created.TagID != "t1" || created.URL != "https://discord.com/api/webhooks/1/abc" {
+			t.Fatalf("unexpected webhook: %+v", created)
+		}
+		if strings.Join(created.Events, ",") != "movie_added,followed" {
+			t.Fatalf("unexpected events: %v", created.Events)
+		}
+		if !strings.HasPrefix(got.Secret, webhookSecretPrefix) || got.Secret != created.Secret {
+			t.Fatalf("unexpected secret: %q", got.Secret)
+		}
+	})
+}
+
+func TestWebhookService_ListDeliveries_OtherUser(t *testing.T) {
+	t.Parallel()
+
+	webhookRepo := &testutil.FakeWebhookRepository{
+		FindByIDFn: func(ctx context.Context, webhookID string) (*model.Webhook, error) {
+			return &model.Webhook{ID: webhookID, UserID: "owner"}, nil
+		},
+		ListDeliveriesFn: func(ctx context.Context, webhookID string, offset, limit int) ([]*model.WebhookDelivery, int64, error) {
+			t.Fatal("unexpected ListDeliveries call")
+			return nil, 0, nil
+		},
+	}
+	svc := newTestWebhookService(webhookRepo, nil, nil, nil, nil)
+	if _, _, err := svc.ListDeliveries(context.Background(), "w1", "other", 1, 20); !errors.Is(err, ErrWebhookNotFound) {
+		t.Fatalf("expected ErrWebhookNotFound, got %v", err)
+	}
+}
+
+func TestWebhookService_FanOut(t *testing.T) {
+	t.Parallel()
+
+	outboxEvent := func(eventType string, p model.NotificationEventPayload) *model.OutboxEvent {
+		payload, _ := json.Marshal(p)
+		return &model.OutboxEvent{ID: "evt1", EventType: eventType, Payload: payload}
+	}
+	tagRepo := &testutil.FakeTagRepository{
+		FindByIDFn: func(ctx context.Context, id string) (*model.Tag, error) {
+			if id == "deleted" {
+				return nil, gorm.ErrRecordNotFound
+			}
+			return &model.Tag{ID: id, UserID: "owner", Title: "@everyone のジブリ", IsPublic: true}, nil
+		},
+	}
+	userRepo := &fakeUserRepo{
+		FindByIDFn: func(ctx context.Context, userID string) (*model.User, error) {
+			return &model.User{ID: userID, DisplayID: "jane", DisplayName: "Jane"}, nil
+		},
+	}
+
+	t.Run("購読している Webhook ごとに配信イベントを積む", func(t *testing.T) {
+		t.Parallel()
+
+		var gotArgs []string
+		webhookRepo := &testutil.FakeWebhookRepository{
+			ListSubscribedFn: func(ctx context.Context, event, tagID, ownerUserID string) ([]*model.Webhook, error) {
+				gotArgs = []string{event, tagID, ownerUserID}
+				return []*model.Webhook{{ID: "w1"}, {ID: "w2"}}, nil
+			},
+			FindMovieTitleFn: func(ctx context.Context, tmdbMovieID int) (*string, error) {
+				title := "千と千尋の神隠し"
+				return &title, nil
+			},
+		}
+		var enqueued []*model.OutboxEvent
+		outboxRepo := &testutil.FakeOutboxRepository{
+			EnqueueFn: func(ctx context.Context, events ...*model.OutboxEvent) error {
+				enqueued = append(enqueued, events...)
+				return nil
+			},
+		}
+		svc := newTestWebhookService(webhookRepo, tagRepo, userRepo, outboxRepo, nil)
+
+		err := svc.FanOut(context.Background(), outboxEvent(model.OutboxEventTypeTagMovieAdded, model.NotificationEventPayload{
+			ActorUserID: "u1", TagID: "t1", TagMovieID: "tm1", TmdbMovieID: 129,
+		}))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if strings.Join(gotArgs, ",") != "movie_added,t1,owner" {
+			t.Fatalf("unexpected ListSubscribed args: %v", gotArgs)
+		}
+		if len(enqueued) != 2 || enqueued[0].IdempotencyKey != "webhook.deliver:w1:evt1" || enqueued[1].IdempotencyKey != "webhook.deliver:w2:evt1" {
+			t.Fatalf("unexpected deliveries: %+v", enqueued)
+		}
+
+		var delivery model.WebhookDeliveryPayload
+		if err := json.Unmarshal(enqueued[0].Payload, &delivery); err != nil {
+			t.Fatalf("invalid payload: %v", err)
+		}
+		var body WebhookEventBody
+		if err := json.Unmarshal(delivery.Body, &body); err != nil {
+			t.Fatalf("invalid body: %v", err)
+		}
+		if delivery.WebhookID != "w1" || delivery.EventType != "movie_added" || body.ID != "evt1" || body.Type != "movie_added" {
+			t.Fatalf("unexpected delivery: %+v %+v", delivery, body)
+		}
+		if body.Actor == nil || body.Actor.DisplayID != "jane" || body.Movie == nil || body.Movie.TmdbMovieID != 129 {
+			t.Fatalf("unexpected body: %+v", body)
+		}
+		// タグ名のメンションは受信側で通知されないよう無効にする
+		if body.Content != "Jane さんが「@\u200beveryone のジブリ」に「千と千尋の神隠し」を追加しました" {
+			t.Fatalf("unexpected content: %q", body.Content)
+		}
+	})
+
+	t.Run("ユーザーへのフォローはアカウント全体の Webhook のみが対象", func(t *testing.T) {
+		t.Parallel()
+
+		var gotArgs []string
+		webhookRepo := &testutil.FakeWebhookRepository{
+			ListSubscribedFn: func(ctx context.Context, event, tagID, ownerUserID string) ([]*model.Webhook, error) {
+				gotArgs = []string{event, tagID, ownerUserID}
+				return []*model.Webhook{}, nil
+			},
+		}
+		svc := newTestWebhookService(webhookRepo, tagRepo, userRepo, nil, nil)
+
+		err := svc.FanOut(context.Background(), outboxEvent(model.OutboxEventTypeUserFollowed, model.NotificationEventPayload{
+			ActorUserID: "u1", RecipientUserID: "me",
+		}))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if strings.Join(gotArgs, ",") != "followed,,me" {
+			t.Fatalf("unexpected ListSubscribed args: %v", gotArgs)
+		}
+	})
+
+	t.Run("購読がない・タグが削除済み・対象外のイベントは何もしない", func(t *testing.T) {
+		t.Parallel()
+
+		webhookRepo := &testutil.FakeWebhookRepository{}
+		outboxRepo := &testutil.FakeOutboxRepository{
+			EnqueueFn: func(ctx context.Context, events ...*model.OutboxEvent) error {
+				t.Fatal("unexpected Enqueue call")
+				return nil
+			},
+		}
+		noLookup := &fakeUserRepo{
+			FindByIDFn: func(ctx context.Context, userID string) (*model.User, error) {
+				t.Fatal("unexpected user lookup")
+				return nil, nil
+			},
+		}
+		svc := newTestWebhookService(webhookRepo, tagRepo, noLookup, outboxRepo, nil)
+
+		events := []*model.OutboxEvent{
+			outboxEvent(model.OutboxEventTypeTagFollowed, model.NotificationEventPayload{ActorUserID: "u1", TagID: "t1"}),
+			outboxEvent(model.OutboxEventTypeTagMovieRemoved, model.NotificationEventPayload{ActorUserID: "u1", TagID: "deleted"}),
+			outboxEvent(model.OutboxEventTypeTagLiked, model.NotificationEventPayload{ActorUserID: "u1", TagID: "t1"}),
+		}
+		for _, e := range events {
+			if err := svc.FanOut(context.Background(), e); err != nil {
+				t.Fatalf("%s: unexpected error: %v", e.EventType, err)
+			}
+		}
+	})
+}
+
+func TestWebhookService_Deliver(t *testing.T) {
+	t.Parallel()
+
+	deliveryEvent := func(webhookID string) *model.OutboxEvent {
+		payload, _ := json.Marshal(model.WebhookDeliveryPayload{WebhookID: webhookID, EventType: "movie_added", Body: json.RawMessage(`{"type":"movie_added"}`)})
+		return &model.OutboxEvent{ID: "d1", EventType: model.OutboxEventTypeWebhookDelivery, Payload: payload, Attempts: 2}
+	}
+	webhookRepo := func(logs *[]*model.WebhookDelivery) *testutil.FakeWebhookRepository {
+		return &testutil.FakeWebhookRepository{
+			FindByIDFn: func(ctx context.Context, webhookID string) (*model.Webhook, error) {
+				switch webhookID {
+				case "inactive":
+					return &model.Webhook{ID: webhookID, URL: "https://example.com/hook", Secret: "s", IsActive: false}, nil
+				case "deleted":
+					return nil, gorm.ErrRecordNotFound
+				}
+				return &model.Webhook{ID: webhookID, URL: "https://example.com/hook", Secret: "s", IsActive: true}, nil
+			},
+			CreateDeliveryFn: func(ctx context.Context, delivery *model.WebhookDelivery) error {
+				*logs = append(*logs, delivery)
+				return nil
+			},
+		}
+	}
+
+	t.Run("応答に応じて成功・リトライ・破棄を判定し、配信ログに記録する", func(t *testing.T) {
+		t.Parallel()
+
+		cases := []struct {
+			name     string
+			status   int
+			sendErr  error
+			wantErr  bool
+			rejected bool
+		}{
+			{name: "2xx は成功", status: http.StatusNoContent},
+			{name: "5xx はリトライ", status: http.StatusBadGateway, wantErr: true},
+			{name: "429 はリトライ", status: http.StatusTooManyRequests, wantErr: true},
+			{name: "接続失敗はリトライ", sendErr: errors.New("connection refused"), wantErr: true},
+			{name: "4xx は破棄", status: http.StatusGone, wantErr: true, rejected: true},
+			{name: "リダイレクトは破棄", status: http.StatusFound, wantErr: true, rejected: true},
+			{name: "内部ネットワークへの送信は破棄", sendErr: fmt.Errorf("dial: %w", webhook.ErrDisallowedAddress), wantErr: true, rejected: true},
+		}
+		for _, tc := range cases {
+			var logs []*model.WebhookDelivery
+			var sent *webhook.Request
+			sender := &testutil.FakeWebhookSender{
+				SendFn: func(ctx context.Context, req *webhook.Request) (*webhook.Response, error) {
+					sent = req
+					if tc.sendErr != nil {
+						return nil, tc.sendErr
+					}
+					return &webhook.Response{StatusCode: tc.status}, nil
+				},
+			}
+			svc := newTestWebhookService(webhookRepo(&logs), nil, nil, nil, sender)
+
+			err := svc.Deliver(context.Background(), deliveryEvent("w1"))
+			if (err != nil) != tc.wantErr || errors.Is(err, ErrWebhookDeliveryRejected) != tc.rejected {
+				t.Fatalf("%s: unexpected error: %v", tc.name, err)
+			}
+			if sent == nil || sent.DeliveryID != "d1" || sent.EventType != "movie_added" || string(sent.Body) != `{"type":"movie_added"}` {
+				t.Fatalf("%s: unexpected request: %+v", tc.name, sent)
+			}
+			if len(logs) != 1 || logs[0].WebhookID != "w1" || logs[0].DeliveryID != "d1" || logs[0].Attempt != 2 || logs[0].Succeeded == tc.wantErr {
+				t.Fatalf("%s: unexpected delivery log: %+v", tc.name, logs)
+			}
+			if tc.sendErr == nil && (logs[0].StatusCode == nil || *logs[0].StatusCode != tc.status) {
+				t.Fatalf("%s: expected status code to be recorded", tc.name)
+			}
+			if tc.wantErr && logs[0].Error == nil {
+				t.Fatalf("%s: expected error to be recorded", tc.name)
+			}
+		}
+	})
+
+	t.Run("停止中・削除済みの Webhook には送信しない", func(t *testing.T) {
+		t.Parallel()
+
+		var logs []*model.WebhookDelivery
+		sender := &testutil.FakeWebhookSender{}
+		svc := newTestWebhookService(webhookRepo(&logs), nil, nil, nil, sender)
+
+		for _, id := range []string{"inactive", "deleted"} {
+			if err := svc.Deliver(context.Background(), deliveryEvent(id)); err != nil {
+				t.Fatalf("%s: unexpected error: %v", id, err)
+			}
+		}
+		if len(sender.Sent) != 0 || len(logs) != 0 {
+			t.Fatalf("expected nothing to be sent, got %d requests and %d logs", len(sender.Sent), len(logs))
+		}
+	})
+}
@@ -0,0 +1,27 @@
+package testutil
+
+import (
+	"context"
+	"sync"
+
+	"cinetag-backend/src/internal/mailer"
+)
+
+// FakeMailer は mailer.Mailer の手書き fake です。
+// SendFn が未設定の場合は送信したメッセージを Sent に記録します。
+type FakeMailer struct {
+	SendFn func(ctx context.Context, msg *mailer.Message) error
+
+	mu   sync.Mutex
+	Sent []*mailer.Message
+}
+
+func (f *FakeMailer) Send(ctx context.Context, msg *mailer.Message) error {
+	if f.SendFn != nil {
+		return f.SendFn(ctx, msg)
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.Sent = append(f.Sent, msg)
+	return nil
+}
@@ -2,20 +2,28 @@ package testutil
 
 import (
 	"context"
+	"time"
 
 	"cinetag-backend/src/internal/model"
 	"cinetag-backend/src/internal/repository"
+
+	"gorm.io/gorm"
 )
 
 // FakeTagRepository は repository.TagRepository の手書き fake です。
 // 必要なテストで Fn を差し替えて使います。
 type FakeTagRepository struct {
-	CreateFn           func(ctx context.Context, tag *model.Tag) error
-	FindByIDFn         func(ctx context.Context, id string) (*model.Tag, error)
-	FindDetailByIDFn   func(ctx context.Context, id string) (*repository.TagDetailRow, error)
-	UpdateByIDFn       func(ctx context.Context, id string, patch repository.TagUpdatePatch) error
-	ListPublicTagsFn   func(ctx context.Context, filter repository.TagListFilter) ([]repository.TagSummary, int64, error)
-	ListTagsByUserIDFn func(ctx context.Context, filter repository.UserTagListFilter) ([]repository.TagSummary, int64, error)
+	CreateFn   func(ctx context.Context, tag *model.Tag) error
+	FindByIDFn func(ctx context.Context, id string) (*model.Tag, error)
+	// FindByIDWithActiveOwnerFn が未設定の場合は FindByIDFn を使う（作成者の状態を区別しないテスト向け）
+	FindByIDWithActiveOwnerFn func(ctx context.Context, id string) (*model.Tag, error)
+	FindDetailByIDFn          func(ctx context.Context, id string) (*repository.TagDetailRow, error)
+	UpdateByIDFn              func(ctx context.Context, id string, patch repository.TagUpdatePatch) error
+	ListPublicTagsFn          func(ctx context.Context, filter repository.TagListFilter) ([]repository.TagSummary, int64, error)
+	ListTagsByUserIDFn        func(ctx context.Context, filter repository.UserTagListFilter) ([]repository.TagSummary, int64, error)
+	GetUserTagStatsFn         func(ctx context.Context, userID string, publicOnly bool) (*repository.UserTagStatsRow, error)
+	ListUserTopGenresFn       func(ctx context.Context, userID string, publicOnly bool, limit int) ([]repository.GenreCount, error)
+	ListUserTopDecadesFn      func(ctx context.Context, userID string, publicOnly bool, limit int) ([]repository.DecadeCount, error)
 }
 
 func (f *FakeTagRepository) Create(ctx context.Context, tag *model.Tag) error {
@@ -32,6 +40,13 @@ func (f *FakeTagRepository) FindByID(ctx context.Context, id string) (*model.Tag
 	return f.FindByIDFn(ctx, id)
 }
 
+func (f *FakeTagRepository) FindByIDWithActiveOwner(ctx context.Context, id string) (*model.Tag, error) {
+	if f.FindByIDWithActiveOwnerFn == nil {
+		return f.FindByID(ctx, id)
+	}
+	return f.FindByIDWithActiveOwnerFn(ctx, id)
+}
+
 func (f *FakeTagRepository) FindDetailByID(ctx context.Context, id string) (*repository.TagDetailRow, error) {
 	if f.FindDetailByIDFn == nil {
 		return nil, nil
@@ -60,21 +75,42 @@ func (f *FakeTagRepository) ListTagsByUserID(ctx context.Context, filter reposit
 	return f.ListTagsByUserIDFn(ctx, filter)
 }
 
+func (f *FakeTagRepository) GetUserTagStats(ctx context.Context, userID string, publicOnly bool) (*repository.UserTagStatsRow, error) {
+	if f.GetUserTagStatsFn == nil {
+		return &repository.UserTagStatsRow{}, nil
+	}
+	return f.GetUserTagStatsFn(ctx, userID, publicOnly)
+}
+
+func (f *FakeTagRepository) ListUserTopGenres(ctx context.Context, userID string, publicOnly bool, limit int) ([]repository.GenreCount, error) {
+	if f.ListUserTopGenresFn == nil {
+		return []repository.GenreCount{}, nil
+	}
+	return f.ListUserTopGenresFn(ctx, userID, publicOnly, limit)
+}
+
+func (f *FakeTagRepository) ListUserTopDecades(ctx context.Context, userID string, publicOnly bool, limit int) ([]repository.DecadeCount, error) {
+	if f.ListUserTopDecadesFn == nil {
+		return []repository.DecadeCount{}, nil
+	}
+	return f.ListUserTopDecadesFn(ctx, userID, publicOnly, limit)
+}
+
 // FakeTagMovieRepository は repository.TagMovieRepository の手書き fake です。
 type FakeTagMovieRepository struct {
 	ListRecentByTagFn       func(ctx context.Context, tagID string, limit int) ([]model.TagMovie, error)
-	ListByTagFn             func(ctx context.Context, tagID string, offset, limit int) ([]repository.TagMovieWithCache, int64, error)
+	ListByTagFn             func(ctx context.Context, tagID string, excludeUserIDs []string, offset, limit int) ([]repository.TagMovieWithCache, int64, error)
 	CreateFn                func(ctx context.Context, tagMovie *model.TagMovie) error
 	FindByIDFn              func(ctx context.Context, tagMovieID string) (*model.TagMovie, error)
 	DeleteFn                func(ctx context.Context, tagMovieID string) error
-	ListContributorsByTagFn func(ctx context.Context, tagID string, ownerID string, limit int) ([]repository.TagContributor, int64, error)
+	ListContributorsByTagFn func(ctx context.Context, tagID string, ownerID string, excludeUserIDs []string, limit int) ([]repository.TagContributor, int64, error)
 }
 
-func (f *FakeTagMovieRepository) ListContributorsByTag(ctx context.Context, tagID string, ownerID string, limit int) ([]repository.TagContributor, int64, error) {
+func (f *FakeTagMovieRepository) ListContributorsByTag(ctx context.Context, tagID string, ownerID string, excludeUserIDs []string, limit int) ([]repository.TagContributor, int64, error) {
 	if f.ListContributorsByTagFn == nil {
 		return []repository.TagContributor{}, 0, nil
 	}
-	return f.ListContributorsByTagFn(ctx, tagID, ownerID, limit)
+	return f.ListContributorsByTagFn(ctx, tagID, ownerID, excludeUserIDs, limit)
 }
 
 func (f *FakeTagMovieRepository) ListRecentByTag(ctx context.Context, tagID string, limit int) ([]model.TagMovie, error) {
@@ -84,11 +120,11 @@ func (f *FakeTagMovieRepository) ListRecentByTag(ctx context.Context, tagID stri
 	return f.ListRecentByTagFn(ctx, tagID, limit)
 }
 
-func (f *FakeTagMovieRepository) ListByTag(ctx context.Context, tagID string, offset, limit int) ([]repository.TagMovieWithCache, int64, error) {
+func (f *FakeTagMovieRepository) ListByTag(ctx context.Context, tagID string, excludeUserIDs []string, offset, limit int) ([]repository.TagMovieWithCache, int64, error) {
 	if f.ListByTagFn == nil {
 		return []repository.TagMovieWithCache{}, 0, nil
 	}
-	return f.ListByTagFn(ctx, tagID, offset, limit)
+	return f.ListByTagFn(ctx, tagID, excludeUserIDs, offset, limit)
 }
 
 func (f *FakeTagMovieRepository) Create(ctx context.Context, tagMovie *model.TagMovie) error {
@@ -120,6 +156,7 @@ type FakeTagFollowerRepository struct {
 	IsFollowingFn       func(ctx context.Context, tagID, userID string) (bool, error)
 	ListFollowersFn     func(ctx context.Context, tagID string, page, pageSize int) ([]*model.User, int64, error)
 	CountFollowersFn    func(ctx context.Context, tagID string) (int64, error)
+	ListFollowerIDsFn   func(ctx context.Context, tagID string) ([]string, error)
 	ListFollowingTagsFn func(ctx context.Context, userID string, page, pageSize int) ([]repository.TagSummary, int64, error)
 }
 
@@ -166,7 +203,10 @@ func (f *FakeTagFollowerRepository) CountFollowers(ctx context.Context, tagID st
 }
 
 func (f *FakeTagFollowerRepository) ListFollowerIDs(ctx context.Context, tagID string) ([]string, error) {
-	return []string{}, nil
+	if f.ListFollowerIDsFn == nil {
+		return []string{}, nil
+	}
+	return f.ListFollowerIDsFn(ctx, tagID)
 }
 
 func (f *FakeTagFollowerRepository) ListFollowingTags(ctx context.Context, userID string, page, pageSize int) ([]repository.TagSummary, int64, error) {
@@ -227,3 +267,746 @@ func (f *FakeTagLikeRepository) ListLikedTags(ctx context.Context, userID string
 	}
 	return f.ListLikedTagsFn(ctx, userID, page, pageSize)
 }
+
+// FakeUserBlockRepository は repository.UserBlockRepository の手書き fake です。
+type FakeUserBlockRepository struct {
+	CreateFn          func(ctx context.Context, blockerID, blockedID string) error
+	DeleteFn          func(ctx context.Context, blockerID, blockedID string) error
+	IsBlockingFn      func(ctx context.Context, blockerID, blockedID string) (bool, error)
+	IsBlockedEitherFn func(ctx context.Context, userA, userB string) (bool, error)
+	ListBlockedFn     func(ctx context.Context, blockerID string, page, pageSize int) ([]*model.User, int64, error)
+	ListBlockedIDsFn  func(ctx context.Context, blockerID string) ([]string, error)
+}
+
+func (f *FakeUserBlockRepository) Create(ctx context.Context, blockerID, blockedID string) error {
+	if f.CreateFn == nil {
+		return nil
+	}
+	return f.CreateFn(ctx, blockerID, blockedID)
+}
+
+func (f *FakeUserBlockRepository) Delete(ctx context.Context, blockerID, blockedID string) error {
+	if f.DeleteFn == nil {
+		return nil
+	}
+	return f.DeleteFn(ctx, blockerID, blockedID)
+}
+
+func (f *FakeUserBlockRepository) IsBlocking(ctx context.Context, blockerID, blockedID string) (bool, error) {
+	if f.IsBlockingFn == nil {
+		return false, nil
+	}
+	return f.IsBlockingFn(ctx, blockerID, blockedID)
+}
+
+func (f *FakeUserBlockRepository) IsBlockedEither(ctx context.Context, userA, userB string) (bool, error) {
+	if f.IsBlockedEitherFn == nil {
+		return false, nil
+	}
+	return f.IsBlockedEitherFn(ctx, userA, userB)
+}
+
+func (f *FakeUserBlockRepository) ListBlocked(ctx context.Context, blockerID string, page, pageSize int) ([]*model.User, int64, error) {
+	if f.ListBlockedFn == nil {
+		return []*model.User{}, 0, nil
+	}
+	return f.ListBlockedFn(ctx, blockerID, page, pageSize)
+}
+
+func (f *FakeUserBlockRepository) ListBlockedIDs(ctx context.Context, blockerID string) ([]string, error) {
+	if f.ListBlockedIDsFn == nil {
+		return []string{}, nil
+	}
+	return f.ListBlockedIDsFn(ctx, blockerID)
+}
+
+// FakeUserMuteRepository は repository.UserMuteRepository の手書き fake です。
+type FakeUserMuteRepository struct {
+	CreateFn       func(ctx context.Context, muterID, mutedID string) error
+	DeleteFn       func(ctx context.Context, muterID, mutedID string) error
+	IsMutingFn     func(ctx context.Context, muterID, mutedID string) (bool, error)
+	ListMutedFn    func(ctx context.Context, muterID string, page, pageSize int) ([]*model.User, int64, error)
+	ListMutedIDsFn func(ctx context.Context, muterID string) ([]string, error)
+}
+
+func (f *FakeUserMuteRepository) Create(ctx context.Context, muterID, mutedID string) error {
+	if f.CreateFn == nil {
+		return nil
+	}
+	return f.CreateFn(ctx, muterID, mutedID)
+}
+
+func (f *FakeUserMuteRepository) Delete(ctx context.Context, muterID, mutedID string) error {
+	if f.DeleteFn == nil {
+		return nil
+	}
+	return f.DeleteFn(ctx, muterID, mutedID)
+}
+
+func (f *FakeUserMuteRepository) IsMuting(ctx context.Context, muterID, mutedID string) (bool, error) {
+	if f.IsMutingFn == nil {
+		return false, nil
+	}
+	return f.IsMutingFn(ctx, muterID, mutedID)
+}
+
+func (f *FakeUserMuteRepository) ListMuted(ctx context.Context, muterID string, page, pageSize int) ([]*model.User, int64, error) {
+	if f.ListMutedFn == nil {
+		return []*model.User{}, 0, nil
+	}
+	return f.ListMutedFn(ctx, muterID, page, pageSize)
+}
+
+func (f *FakeUserMuteRepository) ListMutedIDs(ctx context.Context, muterID string) ([]string, error) {
+	if f.ListMutedIDsFn == nil {
+		return []string{}, nil
+	}
+	return f.ListMutedIDsFn(ctx, muterID)
+}
+
+// FakeUserMovieStatusRepository は repository.UserMovieStatusRepository の手書き fake です。
+type FakeUserMovieStatusRepository struct {
+	UpsertFn                func(ctx context.Context, status *model.UserMovieStatus) error
+	DeleteFn                func(ctx context.Context, userID string, tmdbMovieID int) error
+	FindByUserAndMovieFn    func(ctx context.Context, userID string, tmdbMovieID int) (*model.UserMovieStatus, error)
+	ListByUserAndMovieIDsFn func(ctx context.Context, userID string, tmdbMovieIDs []int) ([]model.UserMovieStatus, error)
+	ListByUserFn            func(ctx context.Context, filter repository.UserMovieStatusListFilter) ([]repository.UserMovieStatusWithCache, int64, error)
+	CountWatchedInTagFn     func(ctx context.Context, userID, tagID string) (int64, error)
+}
+
+func (f *FakeUserMovieStatusRepository) Upsert(ctx context.Context, status *model.UserMovieStatus) error {
+	if f.UpsertFn == nil {
+		return nil
+	}
+	return f.UpsertFn(ctx, status)
+}
+
+func (f *FakeUserMovieStatusRepository) Delete(ctx context.Context, userID string, tmdbMovieID int) error {
+	if f.DeleteFn == nil {
+		return nil
+	}
+	return f.DeleteFn(ctx, userID, tmdbMovieID)
+}
+
+func (f *FakeUserMovieStatusRepository) FindByUserAndMovie(ctx context.Context, userID string, tmdbMovieID int) (*model.UserMovieStatus, error) {
+	if f.FindByUserAndMovieFn == nil {
+		return nil, nil
+	}
+	return f.FindByUserAndMovieFn(ctx, userID, tmdbMovieID)
+}
+
+func (f *FakeUserMovieStatusRepository) ListByUserAndMovieIDs(ctx context.Context, userID string, tmdbMovieIDs []int) ([]model.UserMovieStatus, error) {
+	if f.ListByUserAndMovieIDsFn == nil {
+		return []model.UserMovieStatus{}, nil
+	}
+	return f.ListByUserAndMovieIDsFn(ctx, userID, tmdbMovieIDs)
+}
+
+func (f *FakeUserMovieStatusRepository) ListByUser(ctx context.Context, filter repository.UserMovieStatusListFilter) ([]repository.UserMovieStatusWithCache, int64, error) {
+	if f.ListByUserFn == nil {
+		return []repository.UserMovieStatusWithCache{}, 0, nil
+	}
+	return f.ListByUserFn(ctx, filter)
+}
+
+func (f *FakeUserMovieStatusRepository) CountWatchedInTag(ctx context.Context, userID, tagID string) (int64, error) {
+	if f.CountWatchedInTagFn == nil {
+		return 0, nil
+	}
+	return f.CountWatchedInTagFn(ctx, userID, tagID)
+}
+
+// FakeMovieRatingRepository は repository.MovieRatingRepository の手書き fake です。
+type FakeMovieRatingRepository struct {
+	UpsertFn                func(ctx context.Context, rating *model.MovieRating) error
+	DeleteFn                func(ctx context.Context, userID string, tmdbMovieID int) error
+	FindByUserAndMovieFn    func(ctx context.Context, userID string, tmdbMovieID int) (*model.MovieRating, error)
+	ListByUserAndMovieIDsFn func(ctx context.Context, userID string, tmdbMovieIDs []int) ([]model.MovieRating, error)
+	ListByUserFn            func(ctx context.Context, filter repository.MovieRatingListByUserFilter) ([]repository.MovieRatingWithCache, int64, error)
+	ListByMovieFn           func(ctx context.Context, filter repository.MovieRatingListByMovieFilter) ([]repository.MovieRatingWithUser, int64, error)
+	GetAggregateFn          func(ctx context.Context, tmdbMovieID int) (*repository.MovieRatingAggregate, error)
+}
+
+func (f *FakeMovieRatingRepository) Upsert(ctx context.Context, rating *model.MovieRating) error {
+	if f.UpsertFn == nil {
+		return nil
+	}
+	return f.UpsertFn(ctx, rating)
+}
+
+func (f *FakeMovieRatingRepository) Delete(ctx context.Context, userID string, tmdbMovieID int) error {
+	if f.DeleteFn == nil {
+		return nil
+	}
+	return f.DeleteFn(ctx, userID, tmdbMovieID)
+}
+
+func (f *FakeMovieRatingRepository) FindByUserAndMovie(ctx context.Context, userID string, tmdbMovieID int) (*model.MovieRating, error) {
+	if f.FindByUserAndMovieFn == nil {
+		return nil, nil
+	}
+	return f.FindByUserAndMovieFn(ctx, userID, tmdbMovieID)
+}
+
+func (f *FakeMovieRatingRepository) ListByUserAndMovieIDs(ctx context.Context, userID string, tmdbMovieIDs []int) ([]model.MovieRating, error) {
+	if f.ListByUserAndMovieIDsFn == nil {
+		return []model.MovieRating{}, nil
+	}
+	return f.ListByUserAndMovieIDsFn(ctx, userID, tmdbMovieIDs)
+}
+
+func (f *FakeMovieRatingRepository) ListByUser(ctx context.Context, filter repository.MovieRatingListByUserFilter) ([]repository.MovieRatingWithCache, int64, error) {
+	if f.ListByUserFn == nil {
+		return []repository.MovieRatingWithCache{}, 0, nil
+	}
+	return f.ListByUserFn(ctx, filter)
+}
+
+func (f *FakeMovieRatingRepository) ListByMovie(ctx context.Context, filter repository.MovieRatingListByMovieFilter) ([]repository.MovieRatingWithUser, int64, error) {
+	if f.ListByMovieFn == nil {
+		return []repository.MovieRatingWithUser{}, 0, nil
+	}
+	return f.ListByMovieFn(ctx, filter)
+}
+
+func (f *FakeMovieRatingRepository) GetAggregate(ctx context.Context, tmdbMovieID int) (*repository.MovieRatingAggregate, error) {
+	if f.GetAggregateFn == nil {
+		return &repository.MovieRatingAggregate{}, nil
+	}
+	return f.GetAggregateFn(ctx, tmdbMovieID)
+}
+
+// FakeFeedEventRepository は repository.FeedEventRepository の手書き fake です。
+type FakeFeedEventRepository struct {
+	CreateFn        func(ctx context.Context, event *model.FeedEvent) error
+	ListForViewerFn func(ctx context.Context, filter repository.FeedListFilter) ([]repository.FeedEventRow, error)
+	ListByActorFn   func(ctx context.Context, filter repository.FeedActivityFilter) ([]repository.FeedEventRow, int64, error)
+}
+
+func (f *FakeFeedEventRepository) Create(ctx context.Context, event *model.FeedEvent) error {
+	if f.CreateFn == nil {
+		return nil
+	}
+	return f.CreateFn(ctx, event)
+}
+
+func (f *FakeFeedEventRepository) ListForViewer(ctx context.Context, filter repository.FeedListFilter) ([]repository.FeedEventRow, error) {
+	if f.ListForViewerFn == nil {
+		return []repository.FeedEventRow{}, nil
+	}
+	return f.ListForViewerFn(ctx, filter)
+}
+
+func (f *FakeFeedEventRepository) ListByActor(ctx context.Context, filter repository.FeedActivityFilter) ([]repository.FeedEventRow, int64, error) {
+	if f.ListByActorFn == nil {
+		return []repository.FeedEventRow{}, 0, nil
+	}
+	return f.ListByActorFn(ctx, filter)
+}
+
+// FakeNotificationRepository は repository.NotificationRepository の手書き fake です。
+type FakeNotificationRepository struct {
+	CreateFn                func(ctx context.Context, notification *model.Notification) error
+	CreateBatchFn           func(ctx context.Context, notifications []*model.Notification) error
+	CreateGroupedBatchFn    func(ctx context.Context, notifications []*model.Notification, event model.NotificationEvent, mergeSince time.Time) error
+	ListEventsFn            func(ctx context.Context, notificationIDs []string) ([]repository.NotificationEventRow, error)
+	ListByRecipientFn       func(ctx context.Context, userID string, page, pageSize int, unreadOnly, archived bool) ([]*repository.NotificationRow, int64, error)
+	ListUpdatedSinceFn      func(ctx context.Context, userID string, afterSeq int64, limit int) ([]*repository.NotificationRow, error)
+	LatestStreamSeqFn       func(ctx context.Context, userID string) (int64, error)
+	ListUnreadSinceFn       func(ctx context.Context, userID string, since time.Time, limit int) ([]*repository.NotificationRow, int64, error)
+	CountUnreadFn           func(ctx context.Context, userID string) (int64, error)
+	MarkAsReadFn            func(ctx context.Context, notificationID, userID string) error
+	MarkAllAsReadFn         func(ctx context.Context, userID string) error
+	SetArchivedFn           func(ctx context.Context, notificationID, userID string, archived bool) error
+	DeleteFn                func(ctx context.Context, notificationID, userID string) error
+	DeleteReadFn            func(ctx context.Context, userID string) (int64, error)
+	DeleteReadBeforeFn      func(ctx context.Context, before time.Time, limit int) (int64, error)
+	DeleteUnreadOverLimitFn func(ctx context.Context, maxPerUser, limit int) (int64, error)
+}
+
+func (f *FakeNotificationRepository) Create(ctx context.Context, notification *model.Notification) error {
+	if f.CreateFn == nil {
+		return nil
+	}
+	return f.CreateFn(ctx, notification)
+}
+
+func (f *FakeNotificationRepository) CreateBatch(ctx context.Context, notifications []*model.Notification) error {
+	if f.CreateBatchFn == nil {
+		return nil
+	}
+	return f.CreateBatchFn(ctx, notifications)
+}
+
+func (f *FakeNotificationRepository) CreateGroupedBatch(ctx context.Context, notifications []*model.Notification, event model.NotificationEvent, mergeSince time.Time) error {
+	if f.CreateGroupedBatchFn == nil {
+		return nil
+	}
+	return f.CreateGroupedBatchFn(ctx, notifications, event, mergeSince)
+}
+
+func (f *FakeNotificationRepository) ListEvents(ctx context.Context, notificationIDs []string) ([]repository.NotificationEventRow, error) {
+	if f.ListEventsFn == nil {
+		return []repository.NotificationEventRow{}, nil
+	}
+	return f.ListEventsFn(ctx, notificationIDs)
+}
+
+func (f *FakeNotificationRepository) ListByRecipient(ctx context.Context, userID string, page, pageSize int, unreadOnly, archived bool) ([]*repository.NotificationRow, int64, error) {
+	if f.ListByRecipientFn == nil {
+		return []*repository.NotificationRow{}, 0, nil
+	}
+	return f.ListByRecipientFn(ctx, userID, page, pageSize, unreadOnly, archived)
+}
+
+func (f *FakeNotificationRepository) ListUpdatedSince(ctx context.Context, userID string, afterSeq int64, limit int) ([]*repository.NotificationRow, error) {
+	if f.ListUpdatedSinceFn == nil {
+		return []*repository.NotificationRow{}, nil
+	}
+	return f.ListUpdatedSinceFn(ctx, userID, afterSeq, limit)
+}
+
+func (f *FakeNotificationRepository) LatestStreamSeq(ctx context.Context, userID string) (int64, error) {
+	if f.LatestStreamSeqFn == nil {
+		return 0, nil
+	}
+	return f.LatestStreamSeqFn(ctx, userID)
+}
+
+func (f *FakeNotificationRepository) ListUnreadSince(ctx context.Context, userID string, since time.Time, limit int) ([]*repository.NotificationRow, int64, error) {
+	if f.ListUnreadSinceFn == nil {
+		return []*repository.NotificationRow{}, 0, nil
+	}
+	return f.ListUnreadSinceFn(ctx, userID, since, limit)
+}
+
+func (f *FakeNotificationRepository) CountUnread(ctx context.Context, userID string) (int64, error) {
+	if f.CountUnreadFn == nil {
+		return 0, nil
+	}
+	return f.CountUnreadFn(ctx, userID)
+}
+
+func (f *FakeNotificationRepository) MarkAsRead(ctx context.Context, notificationID, userID string) error {
+	if f.MarkAsReadFn == nil {
+		return nil
+	}
+	return f.MarkAsReadFn(ctx, notificationID, userID)
+}
+
+func (f *FakeNotificationRepository) MarkAllAsRead(ctx context.Context, userID string) error {
+	if f.MarkAllAsReadFn == nil {
+		return nil
+	}
+	return f.MarkAllAsReadFn(ctx, userID)
+}
+
+func (f *FakeNotificationRepository) SetArchived(ctx context.Context, notificationID, userID string, archived bool) error {
+	if f.SetArchivedFn == nil {
+		return nil
+	}
+	return f.SetArchivedFn(ctx, notificationID, userID, archived)
+}
+
+func (f *FakeNotificationRepository) Delete(ctx context.Context, notificationID, userID string) error {
+	if f.DeleteFn == nil {
+		return nil
+	}
+	return f.DeleteFn(ctx, notificationID, userID)
+}
+
+func (f *FakeNotificationRepository) DeleteRead(ctx context.Context, userID string) (int64, error) {
+	if f.DeleteReadFn == nil {
+		return 0, nil
+	}
+	return f.DeleteReadFn(ctx, userID)
+}
+
+func (f *FakeNotificationRepository) DeleteReadBefore(ctx context.Context, before time.Time, limit int) (int64, error) {
+	if f.DeleteReadBeforeFn == nil {
+		return 0, nil
+	}
+	return f.DeleteReadBeforeFn(ctx, before, limit)
+}
+
+func (f *FakeNotificationRepository) DeleteUnreadOverLimit(ctx context.Context, maxPerUser, limit int) (int64, error) {
+	if f.DeleteUnreadOverLimitFn == nil {
+		return 0, nil
+	}
+	return f.DeleteUnreadOverLimitFn(ctx, maxPerUser, limit)
+}
+
+// FakeNotificationPreferenceRepository は repository.NotificationPreferenceRepository の手書き fake です。
+// FilterRecipientsFn が nil の場合、通知先候補をそのまま返します。
+type FakeNotificationPreferenceRepository struct {
+	ListByUserFn       func(ctx context.Context, userID string) ([]model.NotificationPreference, error)
+	ListMutedTagsFn    func(ctx context.Context, userID string) ([]repository.NotificationMutedTagRow, error)
+	UpdateSettingsFn   func(ctx context.Context, userID string, prefs []model.NotificationPreference, muteTagIDs, unmuteTagIDs []string) error
+	FilterRecipientsFn func(ctx context.Context, userIDs []string, notificationType string, tagID *string) ([]string, error)
+}
+
+func (f *FakeNotificationPreferenceRepository) ListByUser(ctx context.Context, userID string) ([]model.NotificationPreference, error) {
+	if f.ListByUserFn == nil {
+		return []model.NotificationPreference{}, nil
+	}
+	return f.ListByUserFn(ctx, userID)
+}
+
+func (f *FakeNotificationPreferenceRepository) ListMutedTags(ctx context.Context, userID string) ([]repository.NotificationMutedTagRow, error) {
+	if f.ListMutedTagsFn == nil {
+		return []repository.NotificationMutedTagRow{}, nil
+	}
+	return f.ListMutedTagsFn(ctx, userID)
+}
+
+func (f *FakeNotificationPreferenceRepository) UpdateSettings(ctx context.Context, userID string, prefs []model.NotificationPreference, muteTagIDs, unmuteTagIDs []string) error {
+	if f.UpdateSettingsFn == nil {
+		return nil
+	}
+	return f.UpdateSettingsFn(ctx, userID, prefs, muteTagIDs, unmuteTagIDs)
+}
+
+func (f *FakeNotificationPreferenceRepository) FilterRecipients(ctx context.Context, userIDs []string, notificationType string, tagID *string) ([]string, error) {
+	if f.FilterRecipientsFn == nil {
+		return userIDs, nil
+	}
+	return f.FilterRecipientsFn(ctx, userIDs, notificationType, tagID)
+}
+
+// FakeEmailDigestRepository は repository.EmailDigestRepository の手書き fake です。
+type FakeEmailDigestRepository struct {
+	FindSettingFn           func(ctx context.Context, userID string) (*model.EmailDigestSetting, error)
+	UpsertFrequencyFn       func(ctx context.Context, userID, frequency string) error
+	ListDueFn               func(ctx context.Context, dailyBefore, weeklyBefore, now time.Time, limit int) ([]repository.EmailDigestRecipientRow, error)
+	MarkSentFn              func(ctx context.Context, userID string, at time.Time) error
+	MarkFailedFn            func(ctx context.Context, userID string, nextAttemptAt time.Time) error
+	ListFollowedTagMoviesFn func(ctx context.Context, userID string, since time.Time, limit int) ([]repository.EmailDigestMovieRow, int64, error)
+}
+
+func (f *FakeEmailDigestRepository) FindSetting(ctx context.Context, userID string) (*model.EmailDigestSetting, error) {
+	if f.FindSettingFn == nil {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return f.FindSettingFn(ctx, userID)
+}
+
+func (f *FakeEmailDigestRepository) UpsertFrequency(ctx context.Context, userID, frequency string) error {
+	if f.UpsertFrequencyFn == nil {
+		return nil
+	}
+	return f.UpsertFrequencyFn(ctx, userID, frequency)
+}
+
+func (f *FakeEmailDigestRepository) ListDue(ctx context.Context, dailyBefore, weeklyBefore, now time.Time, limit int) ([]repository.EmailDigestRecipientRow, error) {
+	if f.ListDueFn == nil {
+		return []repository.EmailDigestRecipientRow{}, nil
+	}
+	return f.ListDueFn(ctx, dailyBefore, weeklyBefore, now, limit)
+}
+
+func (f *FakeEmailDigestRepository) MarkSent(ctx context.Context, userID string, at time.Time) error {
+	if f.MarkSentFn == nil {
+		return nil
+	}
+	return f.MarkSentFn(ctx, userID, at)
+}
+
+func (f *FakeEmailDigestRepository) MarkFailed(ctx context.Context, userID string, nextAttemptAt time.Time) error {
+	if f.MarkFailedFn == nil {
+		return nil
+	}
+	return f.MarkFailedFn(ctx, userID, nextAttemptAt)
+}
+
+func (f *FakeEmailDigestRepository) ListFollowedTagMovies(ctx context.Context, userID string, since time.Time, limit int) ([]repository.EmailDigestMovieRow, int64, error) {
+	if f.ListFollowedTagMoviesFn == nil {
+		return []repository.EmailDigestMovieRow{}, 0, nil
+	}
+	return f.ListFollowedTagMoviesFn(ctx, userID, since, limit)
+}
+
+// FakeOutboxRepository は repository.OutboxRepository の手書き fake です。
+type FakeOutboxRepository struct {
+	EnqueueFn               func(ctx context.Context, events ...*model.OutboxEvent) error
+	ClaimDueFn              func(ctx context.Context, now, lockedUntil time.Time, lockToken string, limit int) ([]*model.OutboxEvent, error)
+	MarkDoneFn              func(ctx context.Context, id, lockToken string, at time.Time) error
+	MarkFailedFn            func(ctx context.Context, id, lockToken, lastError string, completedHandlers []string, nextAttemptAt *time.Time) error
+	DeleteProcessedBeforeFn func(ctx context.Context, before time.Time, limit int) (int64, error)
+}
+
+func (f *FakeOutboxRepository) Enqueue(ctx context.Context, events ...*model.OutboxEvent) error {
+	if f.EnqueueFn == nil {
+		return nil
+	}
+	return f.EnqueueFn(ctx, events...)
+}
+
+func (f *FakeOutboxRepository) ClaimDue(ctx context.Context, now, lockedUntil time.Time, lockToken string, limit int) ([]*model.OutboxEvent, error) {
+	if f.ClaimDueFn == nil {
+		return []*model.OutboxEvent{}, nil
+	}
+	return f.ClaimDueFn(ctx, now, lockedUntil, lockToken, limit)
+}
+
+func (f *FakeOutboxRepository) MarkDone(ctx context.Context, id, lockToken string, at time.Time) error {
+	if f.MarkDoneFn == nil {
+		return nil
+	}
+	return f.MarkDoneFn(ctx, id, lockToken, at)
+}
+
+func (f *FakeOutboxRepository) MarkFailed(ctx context.Context, id, lockToken, lastError string, completedHandlers []string, nextAttemptAt *time.Time) error {
+	if f.MarkFailedFn == nil {
+		return nil
+	}
+	return f.MarkFailedFn(ctx, id, lockToken, lastError, completedHandlers, nextAttemptAt)
+}
+
+func (f *FakeOutboxRepository) DeleteProcessedBefore(ctx context.Context, before time.Time, limit int) (int64, error) {
+	if f.DeleteProcessedBeforeFn == nil {
+		return 0, nil
+	}
+	return f.DeleteProcessedBeforeFn(ctx, before, limit)
+}
+
+// FakeWebhookRepository は repository.WebhookRepository の手書き fake です。
+type FakeWebhookRepository struct {
+	CreateFn                 func(ctx context.Context, webhook *model.Webhook, maxPerUser int64) error
+	FindByIDFn               func(ctx context.Context, webhookID string) (*model.Webhook, error)
+	ListByUserFn             func(ctx context.Context, userID string) ([]*model.Webhook, error)
+	UpdateFn                 func(ctx context.Context, webhookID, userID string, patch repository.WebhookUpdatePatch) error
+	DeleteFn                 func(ctx context.Context, webhookID, userID string) error
+	ListSubscribedFn         func(ctx context.Context, event, tagID, ownerUserID string) ([]*model.Webhook, error)
+	FindMovieTitleFn         func(ctx context.Context, tmdbMovieID int) (*string, error)
+	CreateDeliveryFn         func(ctx context.Context, delivery *model.WebhookDelivery) error
+	ListDeliveriesFn         func(ctx context.Context, webhookID string, offset, limit int) ([]*model.WebhookDelivery, int64, error)
+	DeleteDeliveriesBeforeFn func(ctx context.Context, before time.Time, limit int) (int64, error)
+}
+
+func (f *FakeWebhookRepository) Create(ctx context.Context, webhook *model.Webhook, maxPerUser int64) error {
+	if f.CreateFn == nil {
+		return nil
+	}
+	return f.CreateFn(ctx, webhook, maxPerUser)
+}
+
+func (f *FakeWebhookRepository) FindByID(ctx context.Context, webhookID string) (*model.Webhook, error) {
+	if f.FindByIDFn == nil {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return f.FindByIDFn(ctx, webhookID)
+}
+
+func (f *FakeWebhookRepository) ListByUser(ctx context.Context, userID string) ([]*model.Webhook, error) {
+	if f.ListByUserFn == nil {
+		return []*model.Webhook{}, nil
+	}
+	return f.ListByUserFn(ctx, userID)
+}
+
+func (f *FakeWebhookRepository) Update(ctx context.Context, webhookID, userID string, patch repository.WebhookUpdatePatch) error {
+	if f.UpdateFn == nil {
+		return nil
+	}
+	return f.UpdateFn(ctx, webhookID, userID, patch)
+}
+
+func (f *FakeWebhookRepository) Delete(ctx context.Context, webhookID, userID string) error {
+	if f.DeleteFn == nil {
+		return nil
+	}
+	return f.DeleteFn(ctx, webhookID, userID)
+}
+
+func (f *FakeWebhookRepository) ListSubscribed(ctx context.Context, event, tagID, ownerUserID string) ([]*model.Webhook, error) {
+	if f.ListSubscribedFn == nil {
+		return []*model.Webhook{}, nil
+	}
+	return f.ListSubscribedFn(ctx, event, tagID, ownerUserID)
+}
+
+func (f *FakeWebhookRepository) FindMovieTitle(ctx context.Context, tmdbMovieID int) (*string, error) {
+	if f.FindMovieTitleFn == nil {
+		return nil, nil
+	}
+	return f.FindMovieTitleFn(ctx, tmdbMovieID)
+}
+
+func (f *FakeWebhookRepository) CreateDelivery(ctx context.Context, delivery *model.WebhookDelivery) error {
+	if f.CreateDeliveryFn == nil {
+		return nil
+	}
+	return f.CreateDeliveryFn(ctx, delivery)
+}
+
+func (f *FakeWebhookRepository) ListDeliveries(ctx context.Context, webhookID string, offset, limit int) ([]*model.WebhookDelivery, int64, error) {
+	if f.ListDeliveriesFn == nil {
+		return []*model.WebhookDelivery{}, 0, nil
+	}
+	return f.ListDeliveriesFn(ctx, webhookID, offset, limit)
+}
+
+func (f *FakeWebhookRepository) DeleteDeliveriesBefore(ctx context.Context, before time.Time, limit int) (int64, error) {
+	if f.DeleteDeliveriesBeforeFn == nil {
+		return 0, nil
+	}
+	return f.DeleteDeliveriesBeforeFn(ctx, before, limit)
+}
+
+// FakeClerkWebhookEventRepository は repository.ClerkWebhookEventRepository の手書き fake です。
+// ClaimFn・SaveUserPayloadFn が nil の場合は常に初めて受け取った（最新の）メッセージとして扱います。
+type FakeClerkWebhookEventRepository struct {
+	ClaimFn         func(ctx context.Context, svixID, eventType, lockToken string, now, lockedUntil time.Time) (bool, error)
+	MarkProcessedFn func(ctx context.Context, svixID, lockToken string, at time.Time) error
+	ReleaseFn       func(ctx context.Context, svixID, lockToken string) error
+	DeleteBeforeFn  func(ctx context.Context, before time.Time, limit int) (int64, error)
+
+	SaveUserPayloadFn func(ctx context.Context, payload *model.ClerkUserPayload) (bool, error)
+	FindUserPayloadFn func(ctx context.Context, clerkUserID string) (*model.ClerkUserPayload, error)
+}
+
+func (f *FakeClerkWebhookEventRepository) Claim(ctx context.Context, svixID, eventType, lockToken string, now, lockedUntil time.Time) (bool, error) {
+	if f.ClaimFn == nil {
+		return true, nil
+	}
+	return f.ClaimFn(ctx, svixID, eventType, lockToken, now, lockedUntil)
+}
+
+func (f *FakeClerkWebhookEventRepository) MarkProcessed(ctx context.Context, svixID, lockToken string, at time.Time) error {
+	if f.MarkProcessedFn == nil {
+		return nil
+	}
+	return f.MarkProcessedFn(ctx, svixID, lockToken, at)
+}
+
+func (f *FakeClerkWebhookEventRepository) Release(ctx context.Context, svixID, lockToken string) error {
+	if f.ReleaseFn == nil {
+		return nil
+	}
+	return f.ReleaseFn(ctx, svixID, lockToken)
+}
+
+func (f *FakeClerkWebhookEventRepository) DeleteBefore(ctx context.Context, before time.Time, limit int) (int64, error) {
+	if f.DeleteBeforeFn == nil {
+		return 0, nil
+	}
+	return f.DeleteBeforeFn(ctx, before, limit)
+}
+
+func (f *FakeClerkWebhookEventRepository) SaveUserPayload(ctx context.Context, payload *model.ClerkUserPayload) (bool, error) {
+	if f.SaveUserPayloadFn == nil {
+		return true, nil
+	}
+	return f.SaveUserPayloadFn(ctx, payload)
+}
+
+func (f *FakeClerkWebhookEventRepository) FindUserPayload(ctx context.Context, clerkUserID string) (*model.ClerkUserPayload, error) {
+	if f.FindUserPayloadFn == nil {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return f.FindUserPayloadFn(ctx, clerkUserID)
+}
+
+// FakePersonalAccessTokenRepository は repository.PersonalAccessTokenRepository の手書き fake です。
+type FakePersonalAccessTokenRepository struct {
+	CreateFn        func(ctx context.Context, token *model.PersonalAccessToken, maxActivePerUser int64, now time.Time) error
+	FindByHashFn    func(ctx context.Context, tokenHash string) (*model.PersonalAccessToken, error)
+	ListByUserFn    func(ctx context.Context, userID string) ([]*model.PersonalAccessToken, error)
+	DeleteFn        func(ctx context.Context, tokenID, userID string) error
+	TouchLastUsedFn func(ctx context.Context, tokenID string, at time.Time) error
+}
+
+func (f *FakePersonalAccessTokenRepository) Create(ctx context.Context, token *model.PersonalAccessToken, maxActivePerUser int64, now time.Time) error {
+	if f.CreateFn == nil {
+		return nil
+	}
+	return f.CreateFn(ctx, token, maxActivePerUser, now)
+}
+
+func (f *FakePersonalAccessTokenRepository) FindByHash(ctx context.Context, tokenHash string) (*model.PersonalAccessToken, error) {
+	if f.FindByHashFn == nil {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return f.FindByHashFn(ctx, tokenHash)
+}
+
+func (f *FakePersonalAccessTokenRepository) ListByUser(ctx context.Context, userID string) ([]*model.PersonalAccessToken, error) {
+	if f.ListByUserFn == nil {
+		return []*model.PersonalAccessToken{}, nil
+	}
+	return f.ListByUserFn(ctx, userID)
+}
+
+func (f *FakePersonalAccessTokenRepository) Delete(ctx context.Context, tokenID, userID string) error {
+	if f.DeleteFn == nil {
+		return nil
+	}
+	return f.DeleteFn(ctx, tokenID, userID)
+}
+
+func (f *FakePersonalAccessTokenRepository) TouchLastUsed(ctx context.Context, tokenID string, at time.Time) error {
+	if f.TouchLastUsedFn == nil {
+		return nil
+	}
+	return f.TouchLastUsedFn(ctx, tokenID, at)
+}
+
+// FakeModerationRepository は repository.ModerationRepository の手書き fake です。
+type FakeModerationRepository struct {
+	ListUsersFn         func(ctx context.Context, filter repository.AdminUserFilter) ([]*model.User, int64, error)
+	UpdateUserRoleFn    func(ctx context.Context, userID, role string, now time.Time) error
+	MarkUserSuspendedFn func(ctx context.Context, userID string, now time.Time) error
+	MarkUserRestoredFn  func(ctx context.Context, userID string, now time.Time) error
+	HideTagFn           func(ctx context.Context, tagID string, now time.Time) error
+	UnhideTagFn         func(ctx context.Context, tagID string, now time.Time) error
+	CreateAuditLogFn    func(ctx context.Context, entry *model.AdminAuditLog) error
+}
+
+func (f *FakeModerationRepository) ListUsers(ctx context.Context, filter repository.AdminUserFilter) ([]*model.User, int64, error) {
+	if f.ListUsersFn == nil {
+		return []*model.User{}, 0, nil
+	}
+	return f.ListUsersFn(ctx, filter)
+}
+
+func (f *FakeModerationRepository) UpdateUserRole(ctx context.Context, userID, role string, now time.Time) error {
+	if f.UpdateUserRoleFn == nil {
+		return nil
+	}
+	return f.UpdateUserRoleFn(ctx, userID, role, now)
+}
+
+func (f *FakeModerationRepository) MarkUserSuspended(ctx context.Context, userID string, now time.Time) error {
+	if f.MarkUserSuspendedFn == nil {
+		return nil
+	}
+	return f.MarkUserSuspendedFn(ctx, userID, now)
+}
+
+func (f *FakeModerationRepository) MarkUserRestored(ctx context.Context, userID string, now time.Time) error {
+	if f.MarkUserRestoredFn == nil {
+		return nil
+	}
+	return f.MarkUserRestoredFn(ctx, userID, now)
+}
+
+func (f *FakeModerationRepository) HideTag(ctx context.Context, tagID string, now time.Time) error {
+	if f.HideTagFn == nil {
+		return nil
+	}
+	return f.HideTagFn(ctx, tagID, now)
+}
+
+func (f *FakeModerationRepository) UnhideTag(ctx context.Context, tagID string, now time.Time) error {
+	if f.UnhideTagFn == nil {
+		return nil
+	}
+	return f.UnhideTagFn(ctx, tagID, now)
+}
+
+func (f *FakeModerationRepository) CreateAuditLog(ctx context.Context, entry *model.AdminAuditLog) error {
+	if f.CreateAuditLogFn == nil {
+		return nil
+	}
+	return f.CreateAuditLogFn(ctx, entry)
+}
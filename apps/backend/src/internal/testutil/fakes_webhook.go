@@ -0,0 +1,28 @@
+package testutil
+
+import (
+	"context"
+	"net/http"
+	"sync"
+
+	"cinetag-backend/src/internal/webhook"
+)
+
+// FakeWebhookSender は webhook.Sender の手書き fake です。
+// SendFn が未設定の場合は送信したリクエストを Sent に記録し、204 を返します。
+type FakeWebhookSender struct {
+	SendFn func(ctx context.Context, req *webhook.Request) (*webhook.Response, error)
+
+	mu   sync.Mutex
+	Sent []*webhook.Request
+}
+
+func (f *FakeWebhookSender) Send(ctx context.Context, req *webhook.Request) (*webhook.Response, error) {
+	if f.SendFn != nil {
+		return f.SendFn(ctx, req)
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.Sent = append(f.Sent, req)
+	return &webhook.Response{StatusCode: http.StatusNoContent}, nil
+}
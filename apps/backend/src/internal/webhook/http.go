@@ -0,0 +1,165 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// 1回の送信（接続から応答の受信まで）のタイムアウト。
+// アウトボックスのディスパッチャーの配信タイムアウトより短くする。
+const httpSendTimeout = 5 * time.Second
+
+// 応答本文は読み捨てるが、接続を再利用できるよう読む上限。
+const maxResponseBodyBytes = 64 << 10
+
+// 送信時の User-Agent。
+const userAgent = "cinetag-webhook/1.0"
+
+// 登録できる URL の最大長。
+const maxURLLength = 2048
+
+var (
+	// 送信先が内部ネットワークのアドレスの場合のエラー。
+	ErrDisallowedAddress = errors.New("webhook: destination address is not allowed")
+	// 登録できない URL の場合のエラー。
+	ErrInvalidURL = errors.New("webhook: invalid url")
+)
+
+// HTTPSender の設定値。
+type HTTPConfig struct {
+	// AllowPrivateNetworks はループバック・プライベートアドレスへの送信を許可する（ローカル開発用）。
+	// 本番では内部のサービスへリクエストを送らせないよう false にする。
+	AllowPrivateNetworks bool
+}
+
+// HTTP の POST で Webhook を送信する Sender の実装。
+type httpSender struct {
+	client *http.Client
+	now    func() time.Time
+}
+
+// HTTP の POST で Webhook を送信する Sender を生成する。
+// リダイレクトには従わず、3xx はそのまま応答として返す。
+func NewHTTPSender(cfg HTTPConfig) Sender {
+	dialer := &net.Dialer{Timeout: httpSendTimeout}
+	if !cfg.AllowPrivateNetworks {
+		// 名前解決後の接続先アドレスを検査し、DNS で内部アドレスに向けられた場合も送信しない
+		dialer.Control = func(network, address string, _ syscall.RawConn) error {
+			return checkPublicAddress(address)
+		}
+	}
+	transport := &http.Transport{
+		Proxy:                 nil,
+		DialContext:           dialer.DialContext,
+		TLSHandshakeTimeout:   httpSendTimeout,
+		ResponseHeaderTimeout: httpSendTimeout,
+		MaxIdleConnsPerHost:   2,
+		IdleConnTimeout:       90 * time.Second,
+	}
+	return &httpSender{
+		client: &http.Client{
+			Transport: transport,
+			Timeout:   httpSendTimeout,
+			CheckRedirect: func(*http.Request, []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+		now: time.Now,
+	}
+}
+
+// Webhook を1回送信する。
+func (s *httpSender) Send(ctx context.Context, req *Request) (*Response, error) {
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, req.URL, bytes.NewReader(req.Body))
+	if err != nil {
+		return nil, fmt.Errorf("webhook: invalid request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("User-Agent", userAgent)
+	httpReq.Header.Set(HeaderEvent, req.EventType)
+	httpReq.Header.Set(HeaderDelivery, req.DeliveryID)
+	httpReq.Header.Set(HeaderSignature, Sign(req.Secret, s.now(), req.Body))
+
+	resp, err := s.client.Do(httpReq)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, maxResponseBodyBytes))
+
+	return &Response{StatusCode: resp.StatusCode}, nil
+}
+
+// 登録する URL を検査する。
+// https のみ受け付け（allowPrivateNetworks の場合は http も可）、内部ネットワークを指すホストは拒否する。
+// ホスト名の名前解決後のアドレスは送信時に検査する。
+func ValidateURL(rawURL string, allowPrivateNetworks bool) error {
+	if len(rawURL) > maxURLLength {
+		return fmt.Errorf("%w: too long", ErrInvalidURL)
+	}
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidURL, err)
+	}
+	if u.Scheme != "https" && !(allowPrivateNetworks && u.Scheme == "http") {
+		return fmt.Errorf("%w: unsupported scheme %q", ErrInvalidURL, u.Scheme)
+	}
+	if u.Hostname() == "" || u.User != nil {
+		return fmt.Errorf("%w: host is required and credentials are not allowed", ErrInvalidURL)
+	}
+	if allowPrivateNetworks {
+		return nil
+	}
+	host := strings.ToLower(u.Hostname())
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		return fmt.Errorf("%w: %s", ErrInvalidURL, ErrDisallowedAddress)
+	}
+	if addr, err := netip.ParseAddr(host); err == nil && !isPublicAddr(addr) {
+		return fmt.Errorf("%w: %s", ErrInvalidURL, ErrDisallowedAddress)
+	}
+	return nil
+}
+
+// 接続先（host:port）がインターネット上のアドレスかを検査する。
+func checkPublicAddress(address string) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	addr, err := netip.ParseAddr(host)
+	if err != nil || !isPublicAddr(addr) {
+		return fmt.Errorf("%w: %s", ErrDisallowedAddress, host)
+	}
+	return nil
+}
+
+// インターネット上のアドレス（ループバック・プライベート・リンクローカルなどを除く）かを返す。
+func isPublicAddr(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	if !addr.IsGlobalUnicast() || addr.IsPrivate() {
+		return false
+	}
+	for _, prefix := range nonPublicPrefixes {
+		if prefix.Contains(addr) {
+			return false
+		}
+	}
+	return true
+}
+
+// IsPrivate に含まれないが、内部ネットワークに届きうるため個別に除外するアドレス範囲。
+var nonPublicPrefixes = []netip.Prefix{
+	netip.MustParsePrefix("100.64.0.0/10"),  // キャリアグレード NAT（RFC 6598）
+	netip.MustParsePrefix("64:ff9b::/96"),   // NAT64（RFC 6052）。埋め込んだ IPv4 アドレス（内部のアドレスを含む）に変換される
+	netip.MustParsePrefix("64:ff9b:1::/48"), // ローカル用の NAT64（RFC 8215）
+}
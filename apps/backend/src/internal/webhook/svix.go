@@ -0,0 +1,113 @@
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Svix（Clerk の Webhook の配信基盤）が付けるヘッダー。
+// https://docs.svix.com/receiving/verifying-payloads/how-manual
+const (
+	HeaderSvixID        = "svix-id"        // メッセージID（同じメッセージの再送で共通）
+	HeaderSvixTimestamp = "svix-timestamp" // 送信時刻（UNIX時刻）
+	HeaderSvixSignature = "svix-signature" // "v1,{署名}" をスペース区切りで並べたもの
+)
+
+// Svix の署名鍵の接頭辞。
+const svixSecretPrefix = "whsec_"
+
+// 送信時刻と受信時刻のずれの既定の許容範囲（前後とも）。
+const DefaultSvixTolerance = 5 * time.Minute
+
+var (
+	// 署名鍵の形式が不正な場合のエラー。
+	ErrInvalidSvixSecret = errors.New("webhook: invalid svix secret")
+	// 署名の検証に失敗した場合のエラー（ヘッダーの不足・時刻のずれ・署名の不一致）。
+	ErrInvalidSvixSignature = errors.New("webhook: invalid svix signature")
+)
+
+// SvixVerifier は Svix の署名付きリクエストの検証を表すインターフェース。
+type SvixVerifier interface {
+	// Verify はヘッダーの値と本文から署名を検証します。検証できない場合は ErrInvalidSvixSignature を返します。
+	Verify(id, timestamp, signature string, body []byte, now time.Time) error
+}
+
+type svixVerifier struct {
+	keys      [][]byte
+	tolerance time.Duration
+}
+
+// NewSvixVerifier は署名鍵（"whsec_..."）から SvixVerifier を生成します。
+// 署名鍵のローテーション中は新旧の鍵を渡し、いずれかで署名が一致すれば受け付けます。
+// tolerance が 0 以下の場合は DefaultSvixTolerance を使います。
+func NewSvixVerifier(secrets []string, tolerance time.Duration) (SvixVerifier, error) {
+	if len(secrets) == 0 {
+		return nil, ErrInvalidSvixSecret
+	}
+	keys := make([][]byte, 0, len(secrets))
+	for _, s := range secrets {
+		key, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(strings.TrimSpace(s), svixSecretPrefix))
+		if err != nil || len(key) == 0 {
+			return nil, ErrInvalidSvixSecret
+		}
+		keys = append(keys, key)
+	}
+	if tolerance <= 0 {
+		tolerance = DefaultSvixTolerance
+	}
+	return &svixVerifier{keys: keys, tolerance: tolerance}, nil
+}
+
+// 署名を検証する。
+func (v *svixVerifier) Verify(id, timestamp, signature string, body []byte, now time.Time) error {
+	if id == "" || timestamp == "" || signature == "" {
+		return ErrInvalidSvixSignature
+	}
+	ts, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return ErrInvalidSvixSignature
+	}
+	// 古いリクエストの再送（リプレイ）を防ぐため、時刻が大きくずれたものは受け付けない
+	if d := now.Sub(time.Unix(ts, 0)); d > v.tolerance || d < -v.tolerance {
+		return ErrInvalidSvixSignature
+	}
+
+	for _, key := range v.keys {
+		expected := []byte(computeSvixSignature(key, id, timestamp, body))
+		for _, part := range strings.Fields(signature) {
+			version, sig, ok := strings.Cut(part, ",")
+			if !ok || version != "v1" {
+				continue
+			}
+			if hmac.Equal([]byte(sig), expected) {
+				return nil
+			}
+		}
+	}
+	return ErrInvalidSvixSignature
+}
+
+// SignSvix は Svix と同じ形式の svix-signature ヘッダーの値を返します（テスト用）。
+func SignSvix(secret, id string, timestamp time.Time, body []byte) (string, error) {
+	key, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(secret, svixSecretPrefix))
+	if err != nil || len(key) == 0 {
+		return "", ErrInvalidSvixSecret
+	}
+	return "v1," + computeSvixSignature(key, id, strconv.FormatInt(timestamp.Unix(), 10), body), nil
+}
+
+// "{メッセージID}.{UNIX時刻}.{本文}" の HMAC-SHA256 を base64 で返す。
+func computeSvixSignature(key []byte, id, ts string, body []byte) string {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(id))
+	mac.Write([]byte("."))
+	mac.Write([]byte(ts))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return base64.StdEncoding.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,97 @@
+package webhook
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestSvixVerifier_Verify(t *testing.T) {
+	t.Parallel()
+
+	// Svix のドキュメントに掲載されている検証用の値
+	const (
+		secret    = "whsec_MfKQ9r8GKYqrTwjUPD8ILPZIo2LaLaSw"
+		id        = "msg_p5jXN8AQM9LWM0D4loKWxJek"
+		timestamp = "1614265330"
+		signature = "v1,g0hM9SsE+OTPJTGt/tmIKtSyZlE3uFJELVlNIOLJ1OE="
+	)
+	body := []byte(`{"test": 2432232314}`)
+	sentAt := time.Unix(1614265330, 0)
+
+	v, err := NewSvixVerifier([]string{secret}, 0)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	t.Run("署名が一致すれば受け付ける", func(t *testing.T) {
+		t.Parallel()
+
+		if err := v.Verify(id, timestamp, signature, body, sentAt.Add(time.Minute)); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		// 複数の署名のうち1つが一致すればよい
+		if err := v.Verify(id, timestamp, "v1,bm90LWEtc2lnbmF0dXJl "+signature, body, sentAt); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("検証できないリクエストは拒否する", func(t *testing.T) {
+		t.Parallel()
+
+		cases := []struct {
+			name      string
+			id        string
+			timestamp string
+			signature string
+			body      []byte
+			now       time.Time
+		}{
+			{name: "本文の改ざん", id: id, timestamp: timestamp, signature: signature, body: []byte(`{"test": 1}`), now: sentAt},
+			{name: "メッセージIDの差し替え", id: "msg_other", timestamp: timestamp, signature: signature, body: body, now: sentAt},
+			{name: "古いリクエスト", id: id, timestamp: timestamp, signature: signature, body: body, now: sentAt.Add(6 * time.Minute)},
+			{name: "未来のリクエスト", id: id, timestamp: timestamp, signature: signature, body: body, now: sentAt.Add(-6 * time.Minute)},
+			{name: "不明なバージョン", id: id, timestamp: timestamp, signature: "v2,g0hM9SsE+OTPJTGt/tmIKtSyZlE3uFJELVlNIOLJ1OE=", body: body, now: sentAt},
+			{name: "ヘッダーなし", id: id, timestamp: "", signature: signature, body: body, now: sentAt},
+			{name: "不正な時刻", id: id, timestamp: "abc", signature: signature, body: body, now: sentAt},
+		}
+		for _, tc := range cases {
+			if err := v.Verify(tc.id, tc.timestamp, tc.signature, tc.body, tc.now); !errors.Is(err, ErrInvalidSvixSignature) {
+				t.Errorf("%s: expected ErrInvalidSvixSignature, got %v", tc.name, err)
+			}
+		}
+	})
+
+	t.Run("ローテーション中は新旧どちらの鍵の署名も受け付ける", func(t *testing.T) {
+		t.Parallel()
+
+		const newSecret = "whsec_bmV3LXNpZ25pbmctc2VjcmV0LWZvci10ZXN0cw=="
+		rotated, err := NewSvixVerifier([]string{newSecret, secret}, 0)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if err := rotated.Verify(id, timestamp, signature, body, sentAt); err != nil {
+			t.Fatalf("old secret: unexpected error: %v", err)
+		}
+		signed, err := SignSvix(newSecret, id, sentAt, body)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if err := rotated.Verify(id, timestamp, signed, body, sentAt); err != nil {
+			t.Fatalf("new secret: unexpected error: %v", err)
+		}
+		if err := v.Verify(id, timestamp, signed, body, sentAt); !errors.Is(err, ErrInvalidSvixSignature) {
+			t.Fatalf("expected ErrInvalidSvixSignature, got %v", err)
+		}
+	})
+
+	t.Run("不正な署名鍵は生成時にエラー", func(t *testing.T) {
+		t.Parallel()
+
+		for _, secrets := range [][]string{nil, {"whsec_"}, {"whsec_not base64!"}} {
+			if _, err := NewSvixVerifier(secrets, 0); !errors.Is(err, ErrInvalidSvixSecret) {
+				t.Errorf("%v: expected ErrInvalidSvixSecret, got %v", secrets, err)
+			}
+		}
+	})
+}
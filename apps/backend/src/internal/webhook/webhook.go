@@ -0,0 +1,60 @@
+// Package webhook は外部サービス（Discord・Slack など）への Webhook の送信を提供します。
+//
+// 送信処理は Sender インターフェースで抽象化しており、本番では HTTPSender を使います。
+// 本文は HMAC-SHA256 で署名し、受信側は X-Cinetag-Signature ヘッダーで送信元と改ざんの有無を検証できます。
+//
+// また、受信する Webhook（Clerk が Svix 経由で送るもの）の署名の検証も提供します。
+package webhook
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+	"time"
+)
+
+// 送信時に付けるヘッダー。
+const (
+	HeaderEvent     = "X-Cinetag-Event"     // イベントの種類（movie_added など）
+	HeaderDelivery  = "X-Cinetag-Delivery"  // 配信ID（同じ配信のリトライで共通）
+	HeaderSignature = "X-Cinetag-Signature" // t={UNIX時刻},v1={署名}
+)
+
+// Request は送信する Webhook を表します。
+type Request struct {
+	URL        string
+	Secret     string
+	EventType  string
+	DeliveryID string
+	Body       []byte
+}
+
+// Response は送信先の応答を表します。
+type Response struct {
+	StatusCode int
+}
+
+// Sender は Webhook の送信を表すインターフェース。
+type Sender interface {
+	// Send は Webhook を1回送信し、応答を返します。
+	// 応答を受け取れなかった場合（接続失敗・タイムアウトなど）はエラーを返します。ステータスコードの判定は呼び出し側で行います。
+	Send(ctx context.Context, req *Request) (*Response, error)
+}
+
+// Sign は本文の署名を X-Cinetag-Signature ヘッダーの形式（t={UNIX時刻},v1={署名}）で返す。
+// 署名は "{UNIX時刻}.{本文}" の HMAC-SHA256（16進数）で、時刻を含めることで古いリクエストの再送を検知できる。
+func Sign(secret string, timestamp time.Time, body []byte) string {
+	ts := strconv.FormatInt(timestamp.Unix(), 10)
+	return "t=" + ts + ",v1=" + computeSignature(secret, ts, body)
+}
+
+// "{UNIX時刻}.{本文}" の HMAC-SHA256 を16進数で返す。
+func computeSignature(secret, ts string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(ts))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,64 @@
+package router
+
+import (
+	"cinetag-backend/src/internal/middleware"
+	"cinetag-backend/src/internal/model"
+)
+
+// accessTokenRouteScopes はパーソナルアクセストークンで呼び出せる API と、必要なスコープです。
+// ここに含まれない API（トークン・Webhook の管理、プロフィールの更新、退会など）はトークンでは呼び出せず、403 を返します。
+// ルートを追加した場合は、トークンで呼び出せるようにするかを検討してここに追加してください。
+var accessTokenRouteScopes = middleware.AccessTokenRouteScopes{
+	// プロフィール
+	"GET /api/v1/users/me": model.AccessTokenScopeProfileRead,
+
+	// ユーザー
+	"GET /api/v1/users/search":                  model.AccessTokenScopeUsersRead,
+	"GET /api/v1/users/:displayId/tags":         model.AccessTokenScopeUsersRead,
+	"GET /api/v1/users/:displayId/following":    model.AccessTokenScopeUsersRead,
+	"GET /api/v1/users/:displayId/followers":    model.AccessTokenScopeUsersRead,
+	"GET /api/v1/users/:displayId/follow-stats": model.AccessTokenScopeUsersRead,
+	"GET /api/v1/users/:displayId/stats":        model.AccessTokenScopeUsersRead,
+	"GET /api/v1/users/:displayId/ratings":      model.AccessTokenScopeUsersRead,
+	"GET /api/v1/users/:displayId/activity":     model.AccessTokenScopeUsersRead,
+	"GET /api/v1/me/suggestions/users":          model.AccessTokenScopeUsersRead,
+
+	// タグ
+	"GET /api/v1/tags/:tagId":                       model.AccessTokenScopeTagsRead,
+	"GET /api/v1/tags/:tagId/movies":                model.AccessTokenScopeTagsRead,
+	"GET /api/v1/tags/:tagId/follow-status":         model.AccessTokenScopeTagsRead,
+	"GET /api/v1/tags/:tagId/like-status":           model.AccessTokenScopeTagsRead,
+	"GET /api/v1/me/following-tags":                 model.AccessTokenScopeTagsRead,
+	"GET /api/v1/me/liked-tags":                     model.AccessTokenScopeTagsRead,
+	"POST /api/v1/tags":                             model.AccessTokenScopeTagsWrite,
+	"PATCH /api/v1/tags/:tagId":                     model.AccessTokenScopeTagsWrite,
+	"POST /api/v1/tags/:tagId/movies":               model.AccessTokenScopeTagsWrite,
+	"DELETE /api/v1/tags/:tagId/movies/:tagMovieId": model.AccessTokenScopeTagsWrite,
+	"POST /api/v1/tags/:tagId/follow":               model.AccessTokenScopeTagsWrite,
+	"DELETE /api/v1/tags/:tagId/follow":             model.AccessTokenScopeTagsWrite,
+	"POST /api/v1/tags/:tagId/like":                 model.AccessTokenScopeTagsWrite,
+	"DELETE /api/v1/tags/:tagId/like":               model.AccessTokenScopeTagsWrite,
+
+	// 映画
+	"GET /api/v1/movies/:tmdbMovieId":              model.AccessTokenScopeMoviesRead,
+	"GET /api/v1/movies/:tmdbMovieId/ratings":      model.AccessTokenScopeMoviesRead,
+	"GET /api/v1/me/movie-statuses":                model.AccessTokenScopeMoviesRead,
+	"PUT /api/v1/me/movies/:tmdbMovieId/status":    model.AccessTokenScopeMoviesWrite,
+	"DELETE /api/v1/me/movies/:tmdbMovieId/status": model.AccessTokenScopeMoviesWrite,
+	"PUT /api/v1/me/movies/:tmdbMovieId/rating":    model.AccessTokenScopeMoviesWrite,
+	"DELETE /api/v1/me/movies/:tmdbMovieId/rating": model.AccessTokenScopeMoviesWrite,
+
+	// 通知
+	"GET /api/v1/notifications":                             model.AccessTokenScopeNotificationsRead,
+	"GET /api/v1/notifications/unread-count":                model.AccessTokenScopeNotificationsRead,
+	"GET /api/v1/notifications/stream":                      model.AccessTokenScopeNotificationsRead,
+	"PATCH /api/v1/notifications/:notificationId/read":      model.AccessTokenScopeNotificationsWrite,
+	"PATCH /api/v1/notifications/read-all":                  model.AccessTokenScopeNotificationsWrite,
+	"PATCH /api/v1/notifications/:notificationId/archive":   model.AccessTokenScopeNotificationsWrite,
+	"PATCH /api/v1/notifications/:notificationId/unarchive": model.AccessTokenScopeNotificationsWrite,
+	"DELETE /api/v1/notifications/:notificationId":          model.AccessTokenScopeNotificationsWrite,
+	"DELETE /api/v1/notifications":                          model.AccessTokenScopeNotificationsWrite,
+
+	// ホームフィード
+	"GET /api/v1/feed/me": model.AccessTokenScopeFeedRead,
+}
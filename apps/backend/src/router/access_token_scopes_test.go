@@ -0,0 +1,41 @@
+package router
+
+import (
+	"testing"
+
+	"cinetag-backend/src/internal/model"
+)
+
+// スコープの表のキーが登録されたルートと一致していることを確認する（ルートの変更で照合できなくなるのを防ぐ）。
+func TestAccessTokenRouteScopes(t *testing.T) {
+	r := NewRouter(&Dependencies{})
+
+	routes := map[string]bool{}
+	for _, route := range r.Routes() {
+		routes[route.Method+" "+route.Path] = true
+	}
+
+	validScopes := map[string]bool{}
+	for _, s := range model.AccessTokenScopes {
+		validScopes[s] = true
+	}
+
+	for key, scope := range accessTokenRouteScopes {
+		if !routes[key] {
+			t.Errorf("%s is not a registered route", key)
+		}
+		if !validScopes[scope] {
+			t.Errorf("%s: unknown scope %q", key, scope)
+		}
+	}
+
+	// トークンの管理はトークン自身では行えない
+	for _, key := range []string{"GET /api/v1/me/access-tokens", "POST /api/v1/me/access-tokens", "DELETE /api/v1/me/access-tokens/:tokenId"} {
+		if !routes[key] {
+			t.Errorf("%s is not a registered route", key)
+		}
+		if _, ok := accessTokenRouteScopes[key]; ok {
+			t.Errorf("%s must not be callable with an access token", key)
+		}
+	}
+}
@@ -1,17 +1,25 @@
 package router
 
 import (
+	"context"
 	"log/slog"
 	"os"
+	"strings"
+	"time"
 
 	"cinetag-backend/src/internal/db"
 	"cinetag-backend/src/internal/handler"
 	"cinetag-backend/src/internal/logger"
 	"cinetag-backend/src/internal/middleware"
+	"cinetag-backend/src/internal/model"
+	"cinetag-backend/src/internal/outbox"
+	"cinetag-backend/src/internal/pubsub"
 	"cinetag-backend/src/internal/repository"
 	"cinetag-backend/src/internal/service"
+	"cinetag-backend/src/internal/webhook"
 
 	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
 )
 
 // Dependencies はアプリケーションの依存関係をまとめた構造体です。
@@ -24,7 +32,13 @@ type Dependencies struct {
 	MovieHandler        *handler.MovieHandler
 	UserHandler         *handler.UserHandler
 	NotificationHandler *handler.NotificationHandler
+	FeedHandler         *handler.FeedHandler
+	EmailDigestHandler  *handler.EmailDigestHandler
+	ExportHandler       *handler.UserDataExportHandler
 	ClerkWebhookHandler *handler.ClerkWebhookHandler
+	WebhookHandler      *handler.WebhookHandler
+	AccessTokenHandler  *handler.PersonalAccessTokenHandler
+	AdminHandler        *handler.AdminHandler
 
 	// Middlewares
 	MaintenanceMiddleware   gin.HandlerFunc
@@ -32,6 +46,27 @@ type Dependencies struct {
 	RecoveryMiddleware      gin.HandlerFunc
 	AuthMiddleware          gin.HandlerFunc
 	OptionalAuthMiddleware  gin.HandlerFunc
+
+	// ReactivationAuthMiddleware は退会状態のユーザーも通す認証ミドルウェアです（退会の取り消し専用）。
+	ReactivationAuthMiddleware gin.HandlerFunc
+
+	// AdminMiddleware は管理者用 API を保護するミドルウェアです（admin ロールのユーザーのみ通す）。
+	AdminMiddleware gin.HandlerFunc
+
+	// AdminJobMiddleware はメンテナンスジョブの API を保護するミドルウェアです（admin ロールのユーザーまたは X-Admin-Token のみ通す）。
+	AdminJobMiddleware gin.HandlerFunc
+
+	// OutboxDispatcher はアウトボックスのイベント（通知・Webhook など）を配信するワーカーです。
+	// サーバー起動時に Start し、終了時に Shutdown で配信中のイベントを処理し終えるまで待ちます。
+	OutboxDispatcher *outbox.Dispatcher
+
+	// StopNotificationHub は通知ストリームの Hub のバックグラウンド処理（LISTEN 接続）を停止します。
+	// サーバー終了時に呼び出します。
+	StopNotificationHub context.CancelFunc
+
+	// JWTValidator は認証ミドルウェアで共有する Clerk JWT 検証器です（設定不備の場合は nil）。
+	// サーバー起動時に Start し、JWKS をバックグラウンドで更新します。
+	JWTValidator *middleware.ClerkJWTValidator
 }
 
 // NewDependencies はアプリケーションの依存関係を組み立てて返します。
@@ -49,28 +84,75 @@ func NewDependencies() *Dependencies {
 	tagLikeRepo := repository.NewTagLikeRepository(database)
 	userRepo := repository.NewUserRepository(log, database)
 	userFollowerRepo := repository.NewUserFollowerRepository(database)
+	displayIDHistoryRepo := repository.NewUserDisplayIDHistoryRepository(database)
+	userBlockRepo := repository.NewUserBlockRepository(database)
+	userMuteRepo := repository.NewUserMuteRepository(database)
+	followRequestRepo := repository.NewUserFollowRequestRepository(database)
+	exportRepo := repository.NewUserDataExportRepository(database)
+	movieStatusRepo := repository.NewUserMovieStatusRepository(database)
+	movieRatingRepo := repository.NewMovieRatingRepository(database)
+	feedEventRepo := repository.NewFeedEventRepository(database)
+	emailDigestRepo := repository.NewEmailDigestRepository(database)
+	outboxRepo := repository.NewOutboxRepository(database)
+	webhookRepo := repository.NewWebhookRepository(database)
+	clerkWebhookEventRepo := repository.NewClerkWebhookEventRepository(database)
+	accessTokenRepo := repository.NewPersonalAccessTokenRepository(database)
+	moderationRepo := repository.NewModerationRepository(database)
 
 	// Services
 	movieService := service.NewMovieService(log, database)
 	notifRepo := repository.NewNotificationRepository(database)
-	notificationService := service.NewNotificationService(log, notifRepo, tagRepo, tagFollowerRepo, userFollowerRepo)
+	notifPrefRepo := repository.NewNotificationPreferenceRepository(database)
+	hubCtx, stopHub := context.WithCancel(context.Background())
+	notificationService := service.NewNotificationService(log, notifRepo, notifPrefRepo, tagRepo, tagFollowerRepo, userFollowerRepo, userRepo, userBlockRepo, feedEventRepo, newNotificationHub(hubCtx, log, database))
 	imageBaseURL := os.Getenv("TMDB_IMAGE_BASE_URL")
-	tagService := service.NewTagService(log, tagRepo, tagMovieRepo, tagFollowerRepo, tagLikeRepo, movieService, notificationService, imageBaseURL)
-	userService := service.NewUserService(log, database, userRepo, userFollowerRepo, tagFollowerRepo, notificationService)
+	tagService := service.NewTagService(log, database, tagRepo, tagMovieRepo, tagFollowerRepo, tagLikeRepo, userBlockRepo, movieStatusRepo, movieRatingRepo, movieService, outboxRepo, imageBaseURL)
+	userService := service.NewUserService(log, database, userRepo, userFollowerRepo, tagFollowerRepo, outboxRepo, displayIDHistoryRepo, userBlockRepo, userMuteRepo, followRequestRepo)
+	exportService := service.NewUserDataExportService(log, database, exportRepo, outboxRepo)
+	movieStatusService := service.NewMovieStatusService(log, movieStatusRepo, movieService)
+	movieRatingService := service.NewMovieRatingService(log, movieRatingRepo, userBlockRepo, movieService)
+	feedService := service.NewFeedService(log, feedEventRepo)
+	// メール送信は定期ジョブ（src/cmd/jobs）で行うため、API では Mailer を使わない
+	emailDigestService := service.NewEmailDigestService(log, emailDigestRepo, notifRepo, nil, service.EmailDigestConfigFromEnv())
+	webhookConfig := service.WebhookConfigFromEnv()
+	webhookSender := webhook.NewHTTPSender(webhook.HTTPConfig{AllowPrivateNetworks: webhookConfig.AllowPrivateNetworks})
+	webhookService := service.NewWebhookService(log, webhookRepo, tagRepo, userRepo, outboxRepo, webhookSender, webhookConfig)
+	clerkWebhookService := service.NewClerkWebhookService(log, clerkWebhookEventRepo, service.ClerkWebhookConfigFromEnv())
+	accessTokenService := service.NewPersonalAccessTokenService(log, accessTokenRepo, userRepo)
+	adminService := service.NewAdminService(log, database, moderationRepo, userRepo, tagRepo)
+	maintenanceService := service.NewMaintenanceService(log, userService, notificationService, movieService, outboxRepo, webhookRepo, clerkWebhookEventRepo, exportRepo, notificationRetentionPolicy(log))
+
+	// Workers
+	// Webhook への振り分けは再実行しても重複しないため、通知より先に実行する
+	outboxHandlers := outbox.MergeHandlers(outbox.WebhookHandlers(webhookService), outbox.NotificationHandlers(notificationService), outbox.UserDataExportHandlers(exportService))
+	outboxDispatcher := outbox.NewDispatcher(log, outboxRepo, outboxHandlers, outbox.Config{
+		HandlerTimeouts: map[string]time.Duration{model.OutboxEventTypeUserDataExportRequested: service.UserDataExportTimeout},
+	})
 
 	// Handlers
 	tagHandler := handler.NewTagHandler(log, tagService)
-	movieHandler := handler.NewMovieHandler(log, movieService)
-	userHandler := handler.NewUserHandler(log, userService, tagService)
+	movieHandler := handler.NewMovieHandler(log, movieService, movieStatusService, movieRatingService)
+	userHandler := handler.NewUserHandler(log, userService, tagService, movieRatingService, feedService)
 	notificationHandler := handler.NewNotificationHandler(log, notificationService)
-	clerkWebhookHandler := handler.NewClerkWebhookHandler(log, userService)
+	feedHandler := handler.NewFeedHandler(log, feedService)
+	emailDigestHandler := handler.NewEmailDigestHandler(log, emailDigestService)
+	exportHandler := handler.NewUserDataExportHandler(log, exportService)
+	clerkWebhookHandler := handler.NewClerkWebhookHandler(log, userService, clerkWebhookService)
+	webhookHandler := handler.NewWebhookHandler(log, webhookService)
+	accessTokenHandler := handler.NewPersonalAccessTokenHandler(log, accessTokenService)
+	adminHandler := handler.NewAdminHandler(log, adminService, notificationService, maintenanceService)
 
 	// Middlewares
 	maintenanceMiddleware := middleware.NewMaintenanceMiddleware(log)
 	requestLoggerMiddleware := middleware.NewRequestLoggerMiddleware(log)
 	recoveryMiddleware := middleware.NewRecoveryMiddleware(log)
-	authMiddleware := middleware.NewAuthMiddleware(log, userService)
-	optionalAuthMiddleware := middleware.NewOptionalAuthMiddleware(log, userService)
+	jwtValidator := newClerkJWTValidator(log)
+	accessTokenAuthenticator := middleware.NewAccessTokenAuthenticator(log, accessTokenService, accessTokenRouteScopes)
+	authMiddleware := middleware.NewAuthMiddleware(log, userService, jwtValidator, accessTokenAuthenticator)
+	optionalAuthMiddleware := middleware.NewOptionalAuthMiddleware(log, userService, jwtValidator, accessTokenAuthenticator)
+	reactivationAuthMiddleware := middleware.NewReactivationAuthMiddleware(log, userService, jwtValidator)
+	adminMiddleware := middleware.NewAdminMiddleware(log, userService, jwtValidator)
+	adminJobMiddleware := middleware.NewAdminJobMiddleware(log, userService, jwtValidator)
 
 	return &Dependencies{
 		Logger:                  log,
@@ -78,11 +160,71 @@ func NewDependencies() *Dependencies {
 		MovieHandler:            movieHandler,
 		UserHandler:             userHandler,
 		NotificationHandler:     notificationHandler,
+		FeedHandler:             feedHandler,
+		EmailDigestHandler:      emailDigestHandler,
+		ExportHandler:           exportHandler,
 		ClerkWebhookHandler:     clerkWebhookHandler,
+		WebhookHandler:          webhookHandler,
+		AccessTokenHandler:      accessTokenHandler,
+		AdminHandler:            adminHandler,
 		MaintenanceMiddleware:   maintenanceMiddleware,
 		RequestLoggerMiddleware: requestLoggerMiddleware,
 		RecoveryMiddleware:      recoveryMiddleware,
 		AuthMiddleware:          authMiddleware,
 		OptionalAuthMiddleware:  optionalAuthMiddleware,
+
+		ReactivationAuthMiddleware: reactivationAuthMiddleware,
+		AdminMiddleware:            adminMiddleware,
+		AdminJobMiddleware:         adminJobMiddleware,
+		OutboxDispatcher:           outboxDispatcher,
+		StopNotificationHub:        stopHub,
+		JWTValidator:               jwtValidator,
+	}
+}
+
+// newClerkJWTValidator は認証ミドルウェアで共有する Clerk JWT 検証器を返します。
+// CLERK_AUTHORIZED_PARTIES は本番環境（GIN_MODE=release）では必須です。
+// それ以外の環境で未設定の場合は、CORS で許可しているフロントエンドのオリジンを azp の許可リストにします。
+// 設定不備の場合は nil を返します（認証が必要なリクエストは 500 になります）。
+func newClerkJWTValidator(log *slog.Logger) *middleware.ClerkJWTValidator {
+	cfg, err := middleware.ClerkJWTConfigFromEnv()
+	if err != nil {
+		log.Error("invalid clerk jwt config", slog.Any("error", err))
+		return nil
+	}
+	if len(cfg.AuthorizedParties) == 0 {
+		// 本番環境でローカル開発用のオリジンを azp として受け付けないよう、明示的な許可リストを必須にする
+		if gin.Mode() == gin.ReleaseMode {
+			log.Error("CLERK_AUTHORIZED_PARTIES is required in release mode")
+			return nil
+		}
+		cfg.AuthorizedParties = frontendOrigins
+	}
+	validator, err := middleware.NewClerkJWTValidator(log, cfg)
+	if err != nil {
+		log.Error("failed to create clerk jwt validator", slog.Any("error", err))
+		return nil
+	}
+	return validator
+}
+
+// notificationRetentionPolicy は管理者用 API から実行する purge-notifications の保持ポリシーを返します。
+// 設定不備の場合はログに出し、通知を削除しないポリシーにします（定期ジョブでは起動時にエラーになります）。
+func notificationRetentionPolicy(log *slog.Logger) service.NotificationRetentionPolicy {
+	policy, err := service.NotificationRetentionPolicyFromEnv()
+	if err != nil {
+		log.Error("invalid notification retention policy", slog.Any("error", err))
+		return service.NotificationRetentionPolicy{}
+	}
+	return policy
+}
+
+// newNotificationHub は通知ストリームの配信に使う Hub を返します。
+// 複数インスタンスで動かす場合は NOTIFICATION_PUBSUB=postgres を指定し、LISTEN/NOTIFY で全インスタンスに配信します。
+// ctx がキャンセルされると LISTEN 接続を閉じます。
+func newNotificationHub(ctx context.Context, log *slog.Logger, database *gorm.DB) pubsub.Hub {
+	if strings.EqualFold(strings.TrimSpace(os.Getenv("NOTIFICATION_PUBSUB")), "postgres") {
+		return pubsub.NewPostgresHub(ctx, log, database, os.Getenv("DATABASE_URL"))
 	}
+	return pubsub.NewMemoryHub()
 }
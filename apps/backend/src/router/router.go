@@ -10,14 +10,13 @@ import (
 	ginSwagger "github.com/swaggo/gin-swagger"
 )
 
-func NewRouter() *gin.Engine {
+// NewRouter は deps を使ってルーターを組み立てます。
+// バックグラウンドのワーカー（deps.OutboxDispatcher）の開始・停止は呼び出し側で行います。
+func NewRouter(deps *Dependencies) *gin.Engine {
 	// gin.Default() の代わりに gin.New() を使用し、
 	// カスタムのロガーとリカバリーミドルウェアを適用
 	r := gin.New()
 
-	// 依存関係の組み立て
-	deps := NewDependencies()
-
 	// ミドルウェア設定（ログとリカバリーを含む）
 	setupMiddleware(r, deps)
 
@@ -27,21 +26,25 @@ func NewRouter() *gin.Engine {
 	return r
 }
 
+// frontendOrigins はフロントエンドのオリジン（開発環境と本番環境）です。
+// CORS の許可オリジンと、本番環境以外での Clerk JWT の azp の既定の許可リストに使います。
+var frontendOrigins = []string{
+	"http://localhost:3000", // ローカル開発環境
+	"http://localhost:8787", // ローカル開発環境（Cloudflare Pages プレビュー）
+	"https://cinetag-frontend-develop.yuta-develop-ct.workers.dev", // develop環境（Cloudflare Workers）
+	"https://cine-tag.com", // 本番環境
+}
+
 // setupMiddleware はミドルウェアを設定します。
 func setupMiddleware(r *gin.Engine, deps *Dependencies) {
 	// CORS設定
 	r.Use(cors.New(cors.Config{
 		// 許可するオリジン（開発環境と本番環境のフロントエンドURL）
-		AllowOrigins: []string{
-			"http://localhost:3000", // ローカル開発環境
-			"http://localhost:8787", // ローカル開発環境（Cloudflare Pages プレビュー）
-			"https://cinetag-frontend-develop.yuta-develop-ct.workers.dev", // develop環境（Cloudflare Workers）
-			"https://cine-tag.com", // 本番環境
-		},
+		AllowOrigins: frontendOrigins,
 		// 許可するHTTPメソッド
 		AllowMethods: []string{"GET", "POST", "PATCH", "DELETE", "OPTIONS"},
 		// 許可するリクエストヘッダー（Origin, Content-Type, Authorizationを許可）
-		AllowHeaders: []string{"Origin", "Content-Type", "Authorization"},
+		AllowHeaders: []string{"Origin", "Content-Type", "Authorization", "Last-Event-ID"},
 		// レスポンスでアクセスを許可するヘッダー（Content-Lengthをクライアントに公開）
 		ExposeHeaders: []string{"Content-Length"},
 		// Cookieなどを含む認証情報のクロスオリジン送信を許可
@@ -74,11 +77,47 @@ func setupRoutes(r *gin.Engine, deps *Dependencies) {
 		// Clerk Webhook
 		api.POST("/clerk/webhook", deps.ClerkWebhookHandler.HandleWebhook)
 
+		// メールダイジェストの配信停止（メール内のリンクから開くため認証不要）
+		api.GET("/email/unsubscribe", deps.EmailDigestHandler.ConfirmUnsubscribe)
+		api.POST("/email/unsubscribe", deps.EmailDigestHandler.Unsubscribe)
+
 		// 公開ルート（認証不要）
 		setupPublicRoutes(api, deps)
 
 		// 認証必須ルート
 		setupAuthRoutes(api, deps)
+
+		// 退会の取り消し（退会状態のユーザーも認証を通す）
+		api.POST("/users/me/reactivate", deps.ReactivationAuthMiddleware, deps.UserHandler.ReactivateMe)
+
+		// 管理者用ルート
+		setupAdminRoutes(api, deps)
+	}
+}
+
+// setupAdminRoutes は管理者用のルートを設定します。
+// admin ロールのユーザーのみが呼び出せます。メンテナンスジョブのみ、定期実行用に X-Admin-Token でも呼び出せます。
+func setupAdminRoutes(api *gin.RouterGroup, deps *Dependencies) {
+	// メンテナンスジョブ
+	api.POST("/admin/jobs/:job", deps.AdminJobMiddleware, deps.AdminHandler.RunJob)
+
+	adminGroup := api.Group("/admin")
+	adminGroup.Use(deps.AdminMiddleware)
+	{
+		// ユーザー
+		adminGroup.GET("/users", deps.AdminHandler.ListUsers)
+		adminGroup.GET("/users/:userId", deps.AdminHandler.GetUser)
+		adminGroup.PATCH("/users/:userId/role", deps.AdminHandler.UpdateUserRole)
+		adminGroup.POST("/users/:userId/suspend", deps.AdminHandler.SuspendUser)
+		adminGroup.POST("/users/:userId/restore", deps.AdminHandler.RestoreUser)
+		adminGroup.GET("/users/:userId/notifications", deps.AdminHandler.ListUserNotifications)
+
+		// タグ
+		adminGroup.POST("/tags/:tagId/hide", deps.AdminHandler.HideTag)
+		adminGroup.POST("/tags/:tagId/unhide", deps.AdminHandler.UnhideTag)
+
+		// Clerk
+		adminGroup.POST("/clerk/users/:clerkUserId/resync", deps.ClerkWebhookHandler.ResyncUser)
 	}
 }
 
@@ -91,16 +130,21 @@ func setupPublicRoutes(api *gin.RouterGroup, deps *Dependencies) {
 	api.GET("/tags/:tagId/followers", deps.TagHandler.ListTagFollowers)
 
 	// ユーザー（公開）
+	api.GET("/users/search", deps.OptionalAuthMiddleware, deps.UserHandler.SearchUsers)
 	api.GET("/users/:displayId", deps.UserHandler.GetUserByDisplayID)
 	api.GET("/users/:displayId/tags", deps.OptionalAuthMiddleware, deps.UserHandler.ListUserTags)
-	api.GET("/users/:displayId/following", deps.UserHandler.ListFollowing)
-	api.GET("/users/:displayId/followers", deps.UserHandler.ListFollowers)
+	api.GET("/users/:displayId/following", deps.OptionalAuthMiddleware, deps.UserHandler.ListFollowing)
+	api.GET("/users/:displayId/followers", deps.OptionalAuthMiddleware, deps.UserHandler.ListFollowers)
 	api.GET("/users/:displayId/follow-stats", deps.OptionalAuthMiddleware, deps.UserHandler.GetUserFollowStats)
+	api.GET("/users/:displayId/stats", deps.OptionalAuthMiddleware, deps.UserHandler.GetUserStats)
+	api.GET("/users/:displayId/ratings", deps.OptionalAuthMiddleware, deps.UserHandler.ListUserRatings)
+	api.GET("/users/:displayId/activity", deps.OptionalAuthMiddleware, deps.UserHandler.ListUserActivity)
 
 	// 映画（公開）
 	api.GET("/movies/search", deps.MovieHandler.SearchMovies)
-	api.GET("/movies/:tmdbMovieId", deps.MovieHandler.GetMovieDetail)
+	api.GET("/movies/:tmdbMovieId", deps.OptionalAuthMiddleware, deps.MovieHandler.GetMovieDetail)
 	api.GET("/movies/:tmdbMovieId/tags", deps.MovieHandler.GetMovieTags)
+	api.GET("/movies/:tmdbMovieId/ratings", deps.OptionalAuthMiddleware, deps.MovieHandler.ListMovieRatings)
 }
 
 // setupAuthRoutes は認証必須のルートを設定します。
@@ -123,6 +167,21 @@ func setupAuthRoutes(api *gin.RouterGroup, deps *Dependencies) {
 		// 通知
 		setupNotificationRoutes(authGroup, deps)
 
+		// ホームフィード
+		authGroup.GET("/feed/me", deps.FeedHandler.ListMyFeed)
+
+		// 視聴ステータス
+		setupMovieStatusRoutes(authGroup, deps)
+
+		// 評価・レビュー
+		setupMovieRatingRoutes(authGroup, deps)
+
+		// Webhook
+		setupWebhookRoutes(authGroup, deps)
+
+		// パーソナルアクセストークン
+		setupAccessTokenRoutes(authGroup, deps)
+
 		// 自分のフォロー中タグ一覧
 		authGroup.GET("/me/following-tags", deps.TagHandler.ListFollowingTags)
 		authGroup.GET("/me/liked-tags", deps.TagHandler.ListLikedTags)
@@ -133,8 +192,21 @@ func setupAuthRoutes(api *gin.RouterGroup, deps *Dependencies) {
 func setupUserRoutes(authGroup *gin.RouterGroup, deps *Dependencies) {
 	authGroup.GET("/users/me", deps.UserHandler.GetMe)
 	authGroup.PATCH("/users/me", deps.UserHandler.UpdateMe)
+	authGroup.DELETE("/users/me", deps.UserHandler.DeleteMe)
 	authGroup.POST("/users/:displayId/follow", deps.UserHandler.FollowUser)
 	authGroup.DELETE("/users/:displayId/follow", deps.UserHandler.UnfollowUser)
+	authGroup.POST("/users/:displayId/block", deps.UserHandler.BlockUser)
+	authGroup.DELETE("/users/:displayId/block", deps.UserHandler.UnblockUser)
+	authGroup.POST("/users/:displayId/mute", deps.UserHandler.MuteUser)
+	authGroup.DELETE("/users/:displayId/mute", deps.UserHandler.UnmuteUser)
+	authGroup.GET("/me/blocks", deps.UserHandler.ListBlockedUsers)
+	authGroup.GET("/me/mutes", deps.UserHandler.ListMutedUsers)
+	authGroup.GET("/me/follow-requests", deps.UserHandler.ListFollowRequests)
+	authGroup.GET("/me/suggestions/users", deps.UserHandler.ListUserSuggestions)
+	authGroup.POST("/me/follow-requests/:displayId/approve", deps.UserHandler.ApproveFollowRequest)
+	authGroup.POST("/me/follow-requests/:displayId/deny", deps.UserHandler.DenyFollowRequest)
+	authGroup.POST("/me/export", deps.ExportHandler.RequestExport)
+	authGroup.GET("/me/export/:exportId", deps.ExportHandler.DownloadExport)
 }
 
 // setupTagRoutes はタグ関連の認証必須ルートを設定します。
@@ -145,6 +217,19 @@ func setupTagRoutes(authGroup *gin.RouterGroup, deps *Dependencies) {
 	authGroup.DELETE("/tags/:tagId/movies/:tagMovieId", deps.TagHandler.RemoveMovieFromTag)
 }
 
+// setupMovieStatusRoutes は視聴ステータス関連の認証必須ルートを設定します。
+func setupMovieStatusRoutes(authGroup *gin.RouterGroup, deps *Dependencies) {
+	authGroup.PUT("/me/movies/:tmdbMovieId/status", deps.MovieHandler.SetMovieStatus)
+	authGroup.DELETE("/me/movies/:tmdbMovieId/status", deps.MovieHandler.ClearMovieStatus)
+	authGroup.GET("/me/movie-statuses", deps.MovieHandler.ListMyMovieStatuses)
+}
+
+// setupMovieRatingRoutes は評価・レビュー関連の認証必須ルートを設定します。
+func setupMovieRatingRoutes(authGroup *gin.RouterGroup, deps *Dependencies) {
+	authGroup.PUT("/me/movies/:tmdbMovieId/rating", deps.MovieHandler.SetMovieRating)
+	authGroup.DELETE("/me/movies/:tmdbMovieId/rating", deps.MovieHandler.DeleteMovieRating)
+}
+
 // setupTagFollowRoutes はタグフォロー関連の認証必須ルートを設定します。
 func setupTagFollowRoutes(authGroup *gin.RouterGroup, deps *Dependencies) {
 	authGroup.POST("/tags/:tagId/follow", deps.TagHandler.FollowTag)
@@ -163,8 +248,34 @@ func setupTagLikeRoutes(authGroup *gin.RouterGroup, deps *Dependencies) {
 func setupNotificationRoutes(authGroup *gin.RouterGroup, deps *Dependencies) {
 	authGroup.GET("/notifications", deps.NotificationHandler.ListNotifications)
 	authGroup.GET("/notifications/unread-count", deps.NotificationHandler.GetUnreadCount)
+	authGroup.GET("/notifications/stream", deps.NotificationHandler.StreamNotifications)
 	authGroup.PATCH("/notifications/:notificationId/read", deps.NotificationHandler.MarkAsRead)
 	authGroup.PATCH("/notifications/read-all", deps.NotificationHandler.MarkAllAsRead)
+	authGroup.PATCH("/notifications/:notificationId/archive", deps.NotificationHandler.ArchiveNotification)
+	authGroup.PATCH("/notifications/:notificationId/unarchive", deps.NotificationHandler.UnarchiveNotification)
+	authGroup.DELETE("/notifications/:notificationId", deps.NotificationHandler.DeleteNotification)
+	authGroup.DELETE("/notifications", deps.NotificationHandler.DeleteNotifications)
+	authGroup.GET("/me/notification-settings", deps.NotificationHandler.GetNotificationSettings)
+	authGroup.PATCH("/me/notification-settings", deps.NotificationHandler.UpdateNotificationSettings)
+	authGroup.GET("/me/email-digest", deps.EmailDigestHandler.GetEmailDigestSettings)
+	authGroup.PATCH("/me/email-digest", deps.EmailDigestHandler.UpdateEmailDigestSettings)
+}
+
+// setupWebhookRoutes は Webhook 関連の認証必須ルートを設定します。
+func setupWebhookRoutes(authGroup *gin.RouterGroup, deps *Dependencies) {
+	authGroup.GET("/me/webhooks", deps.WebhookHandler.ListWebhooks)
+	authGroup.POST("/me/webhooks", deps.WebhookHandler.CreateWebhook)
+	authGroup.PATCH("/me/webhooks/:webhookId", deps.WebhookHandler.UpdateWebhook)
+	authGroup.DELETE("/me/webhooks/:webhookId", deps.WebhookHandler.DeleteWebhook)
+	authGroup.GET("/me/webhooks/:webhookId/deliveries", deps.WebhookHandler.ListWebhookDeliveries)
+}
+
+// setupAccessTokenRoutes はパーソナルアクセストークン関連の認証必須ルートを設定します。
+// トークンの管理はトークン自身では行えません（accessTokenRouteScopes に含めない）。
+func setupAccessTokenRoutes(authGroup *gin.RouterGroup, deps *Dependencies) {
+	authGroup.GET("/me/access-tokens", deps.AccessTokenHandler.ListAccessTokens)
+	authGroup.POST("/me/access-tokens", deps.AccessTokenHandler.CreateAccessToken)
+	authGroup.DELETE("/me/access-tokens/:tokenId", deps.AccessTokenHandler.RevokeAccessToken)
 }
 
 // healthCheckHandler はヘルスチェック用のハンドラーです。